@@ -2,6 +2,7 @@ package main
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +16,7 @@ import (
 	"ton-platform/internal/service"
 	"ton-platform/pkg/response"
 	"ton-platform/pkg/rbac"
+	"ton-platform/pkg/storage"
 )
 
 func main() {
@@ -36,20 +38,165 @@ func main() {
 	}
 	defer database.CloseConnection(db, logger)
 
+	// Connect to Redis; without it live telematics fan-out stays within this instance
+	redisClient, err := database.NewRedisClient(&cfg.Redis, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Redis unavailable, live telematics streaming limited to this instance")
+		redisClient = nil
+	}
+	defer database.CloseRedisClient(redisClient, logger)
+
 	// Initialize repositories
 	userRepo := postgres.NewUserRepositoryPostgres(db)
 	roleRepo := postgres.NewRoleRepositoryPostgres(db)
+	vehicleRepo := postgres.NewVehicleRepositoryPostgres(db)
+	workOrderRepo := postgres.NewWorkOrderRepositoryPostgres(db)
+	inspectionRepo := postgres.NewInspectionRepositoryPostgres(db)
+	damageRepo := postgres.NewDamageReportRepositoryPostgres(db)
+	invoiceRepo := postgres.NewInvoiceRepositoryPostgres(db)
+	telematicsRepo := postgres.NewTelematicsRepositoryPostgres(db)
+	deviceRepo := postgres.NewDeviceRepositoryPostgres(db)
+	vehicleStatusRepo := postgres.NewVehicleStatusRepositoryPostgres(db)
+	dtcRepo := postgres.NewDTCRepositoryPostgres(db)
+	tripRepo := postgres.NewTripRepositoryPostgres(db)
+	geofenceRepo := postgres.NewGeofenceRepositoryPostgres(db)
+	notificationRepo := postgres.NewNotificationRepositoryPostgres(db)
+	driverBehaviourRepo := postgres.NewDriverBehaviourRepositoryPostgres(db)
+	fuelRepo := postgres.NewFuelRepositoryPostgres(db)
+	telematicsStorageRepo := postgres.NewTelematicsStorageRepositoryPostgres(db)
+	conditionRepo := postgres.NewConditionRepositoryPostgres(db)
+	serviceRequestRepo := postgres.NewServiceRequestRepositoryPostgres(db)
+	mechanicRepo := postgres.NewMechanicRepositoryPostgres(db)
+	laborRepo := postgres.NewLaborRepositoryPostgres(db)
+	inventoryRepo := postgres.NewInventoryRepositoryPostgres(db)
+	estimateRepo := postgres.NewEstimateRepositoryPostgres(db)
+	laborOperationRepo := postgres.NewLaborOperationRepositoryPostgres(db)
+	maintenanceRepo := postgres.NewMaintenanceRepositoryPostgres(db)
+	appointmentRepo := postgres.NewAppointmentRepositoryPostgres(db)
+	attachmentRepo := postgres.NewAttachmentRepositoryPostgres(db)
+
+	// Initialize attachment storage
+	attachmentStorage, err := storage.New(storage.Config{
+		Driver:        cfg.Storage.Driver,
+		LocalPath:     cfg.Storage.LocalPath,
+		LocalURL:      strings.TrimRight(cfg.Workshop.PublicURL, "/") + "/api/v1/files",
+		SigningSecret: cfg.Storage.SigningSecret,
+		Endpoint:      cfg.Storage.Endpoint,
+		Region:        cfg.Storage.Region,
+		Bucket:        cfg.Storage.Bucket,
+		AccessKey:     cfg.Storage.AccessKey,
+		SecretKey:     cfg.Storage.SecretKey,
+		PathStyle:     cfg.Storage.PathStyle,
+	})
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize attachment storage")
+	}
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, roleRepo, cfg.JWT.Secret, logger)
+	inspectionService := service.NewInspectionService(inspectionRepo, vehicleRepo, workOrderRepo, logger)
+	damageService := service.NewDamageService(damageRepo, vehicleRepo, inspectionRepo, invoiceRepo, logger)
+	vehicleImportService := service.NewVehicleImportService(vehicleRepo, logger)
+	deviceService := service.NewDeviceService(deviceRepo, vehicleRepo, logger)
+	telematicsIngestService := service.NewTelematicsIngestService(telematicsRepo, deviceRepo, logger)
+	defer telematicsIngestService.Close()
+	telematicsStreamService := service.NewTelematicsStreamService(redisClient, vehicleRepo, logger)
+	telematicsStreamService.Start()
+	defer telematicsStreamService.Close()
+	vehicleStatusService := service.NewVehicleStatusService(vehicleStatusRepo, dtcRepo, vehicleRepo, redisClient, logger)
+	telematicsIngestService.AddListener(vehicleStatusService)
+	telematicsIngestService.AddListener(telematicsStreamService)
+	telematicsStorageService := service.NewTelematicsStorageService(telematicsStorageRepo, telematicsRepo, vehicleRepo, service.TelematicsRetentionConfig{
+		RawRetention:        time.Duration(cfg.Telematics.RawRetentionDays) * 24 * time.Hour,
+		MinuteRetention:     time.Duration(cfg.Telematics.MinuteRetentionDays) * 24 * time.Hour,
+		HourRetention:       time.Duration(cfg.Telematics.HourRetentionDays) * 24 * time.Hour,
+		PartitionPremake:    cfg.Telematics.PartitionPremakeDays,
+		MaintenanceInterval: time.Duration(cfg.Telematics.MaintenanceInterval) * time.Minute,
+	}, logger)
+	telematicsStorageService.Start()
+	defer telematicsStorageService.Close()
+	telematicsIngestService.AddListener(telematicsStorageService)
+	tripService := service.NewTripService(tripRepo, telematicsRepo, vehicleRepo, logger)
+	driverBehaviourService := service.NewDriverBehaviourService(driverBehaviourRepo, vehicleRepo, userRepo, logger)
+	tripService.AddListener(driverBehaviourService)
+	fuelService := service.NewFuelService(fuelRepo, telematicsRepo, vehicleRepo, logger)
+	tripService.AddListener(fuelService)
+	fuelService.Start()
+	defer fuelService.Close()
+	telematicsIngestService.AddListener(fuelService)
+	tripService.Start()
+	defer tripService.Close()
+	telematicsIngestService.AddListener(tripService)
+	notificationService := service.NewNotificationService(notificationRepo, logger)
+	geofenceService := service.NewGeofenceService(geofenceRepo, vehicleRepo, userRepo, notificationService, logger)
+	telematicsIngestService.AddListener(geofenceService)
+	dtcService := service.NewDTCService(dtcRepo, deviceRepo, vehicleRepo, userRepo, roleRepo, notificationService, logger)
+	conditionService := service.NewConditionService(conditionRepo, vehicleRepo, workOrderRepo, userRepo, roleRepo, notificationService, logger)
+	telematicsIngestService.AddListener(conditionService)
+	mechanicService := service.NewMechanicService(mechanicRepo, workOrderRepo, userRepo, roleRepo, serviceRequestRepo, logger)
+	workOrderService := service.NewWorkOrderService(workOrderRepo, vehicleRepo, userRepo, mechanicService, logger)
+	serviceRequestService := service.NewServiceRequestService(serviceRequestRepo, vehicleRepo, userRepo, roleRepo, notificationService, logger)
+	workOrderService.AddListener(serviceRequestService)
+	laborOperationService := service.NewLaborOperationService(laborOperationRepo, logger)
+	estimateService := service.NewEstimateService(estimateRepo, workOrderRepo, inventoryRepo, mechanicRepo, workOrderService, laborOperationService, service.EstimateConfig{
+		DefaultLaborRate: cfg.Workshop.DefaultLaborRate,
+		TaxRate:          cfg.Workshop.TaxRate,
+		LinkValidity:     time.Duration(cfg.Workshop.EstimateLinkDays) * 24 * time.Hour,
+		ApprovalURL:      strings.TrimRight(cfg.Workshop.PublicURL, "/") + "/api/v1/estimate-approvals",
+	}, logger)
+	laborService := service.NewLaborService(laborRepo, mechanicRepo, workOrderService, estimateService, laborOperationService, cfg.Workshop.DefaultLaborRate, logger)
+	workOrderService.AddListener(laborService)
+	partsService := service.NewPartsService(inventoryRepo, mechanicRepo, workOrderService, estimateService, logger)
+	maintenanceService := service.NewMaintenanceService(maintenanceRepo, vehicleRepo, workOrderRepo, laborRepo, userRepo, roleRepo,
+		laborOperationService, notificationService, time.Duration(cfg.Workshop.MaintenanceInterval)*time.Minute, logger)
+	maintenanceService.Start()
+	defer maintenanceService.Close()
+	workOrderService.AddListener(maintenanceService)
+	appointmentService := service.NewAppointmentService(appointmentRepo, mechanicRepo, serviceRequestRepo, workOrderService, mechanicService,
+		notificationService, service.AppointmentConfig{
+			OpeningHour: cfg.Workshop.OpeningHour,
+			ClosingHour: cfg.Workshop.ClosingHour,
+		}, logger)
+	workOrderService.AddListener(appointmentService)
+	attachmentService := service.NewAttachmentService(attachmentRepo, vehicleRepo, inspectionRepo, workOrderService, attachmentStorage, service.AttachmentConfig{
+		MaxSize:       int64(cfg.Storage.MaxUploadMB) << 20,
+		URLExpiry:     time.Duration(cfg.Storage.URLExpiry) * time.Minute,
+		ThumbnailSize: cfg.Storage.ThumbnailSize,
+	}, logger)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, logger)
 	roleHandler := handler.NewRoleHandler(roleRepo, logger)
+	inspectionHandler := handler.NewInspectionHandler(inspectionService, logger)
+	damageHandler := handler.NewDamageHandler(damageService, logger)
+	vehicleImportHandler := handler.NewVehicleImportHandler(vehicleImportService, logger)
+	telematicsHandler := handler.NewTelematicsHandler(telematicsIngestService, logger)
+	deviceHandler := handler.NewDeviceHandler(deviceService, logger)
+	telematicsStreamHandler := handler.NewTelematicsStreamHandler(telematicsStreamService, logger)
+	vehicleStatusHandler := handler.NewVehicleStatusHandler(vehicleStatusService, logger)
+	tripHandler := handler.NewTripHandler(tripService, logger)
+	geofenceHandler := handler.NewGeofenceHandler(geofenceService, logger)
+	notificationHandler := handler.NewNotificationHandler(notificationService, logger)
+	dtcHandler := handler.NewDTCHandler(dtcService, logger)
+	driverBehaviourHandler := handler.NewDriverBehaviourHandler(driverBehaviourService, logger)
+	fuelHandler := handler.NewFuelHandler(fuelService, logger)
+	telematicsHistoryHandler := handler.NewTelematicsHistoryHandler(telematicsStorageService, logger)
+	conditionHandler := handler.NewConditionHandler(conditionService, logger)
+	workOrderHandler := handler.NewWorkOrderHandler(workOrderService, logger)
+	serviceRequestHandler := handler.NewServiceRequestHandler(serviceRequestService, logger)
+	mechanicHandler := handler.NewMechanicHandler(mechanicService, logger)
+	laborHandler := handler.NewLaborHandler(laborService, logger)
+	partsHandler := handler.NewPartsHandler(partsService, logger)
+	estimateHandler := handler.NewEstimateHandler(estimateService, logger)
+	laborOperationHandler := handler.NewLaborOperationHandler(laborOperationService, logger)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService, logger)
+	appointmentHandler := handler.NewAppointmentHandler(appointmentService, logger)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, logger)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(cfg.JWT.Secret, logger)
 	rbacMiddleware := middleware.NewRBACMiddleware(roleRepo, logger)
+	deviceAuthMiddleware := middleware.NewDeviceAuthMiddleware(deviceRepo, logger)
 
 	// Create Gin router
 	router := gin.New()
@@ -72,6 +219,7 @@ func main() {
 			"version":    "1.0.0",
 			"service":    "TON Platform Backend",
 			"database":   dbHealth,
+			"telematics": telematicsIngestService.ListenerStats(),
 			"timestamp":  time.Now().UTC(),
 		})
 	})
@@ -158,6 +306,642 @@ func main() {
 			permissions.GET("", roleHandler.GetAllPermissions)
 		}
 
+		// Vehicle inspection routes
+		inspections := v1.Group("/inspections")
+		inspections.Use(authMiddleware.RequireAuth())
+		{
+			inspectionsRead := inspections.Group("")
+			inspectionsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceInspection, rbac.ActionRead))
+			inspectionsRead.GET("/templates", inspectionHandler.ListTemplates)
+			inspectionsRead.GET("/templates/:id", inspectionHandler.GetTemplate)
+			inspectionsRead.GET("/templates/:id/versions", inspectionHandler.GetTemplateVersions)
+			inspectionsRead.GET("/:id", inspectionHandler.GetByID)
+
+			templatesManage := inspections.Group("/templates")
+			templatesManage.Use(rbacMiddleware.RequirePermission(rbac.ResourceInspection, rbac.ActionUpdate))
+			templatesManage.POST("", inspectionHandler.CreateTemplate)
+			templatesManage.PUT("/:id", inspectionHandler.PublishTemplateVersion)
+			templatesManage.DELETE("/:id", inspectionHandler.DeactivateTemplate)
+
+			inspectionsCreate := inspections.Group("")
+			inspectionsCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceInspection, rbac.ActionCreate))
+			inspectionsCreate.POST("", inspectionHandler.Submit)
+
+			inspectionAttachmentsList := inspections.Group("")
+			inspectionAttachmentsList.Use(rbacMiddleware.RequirePermission(rbac.ResourceAttachment, rbac.ActionList))
+			inspectionAttachmentsList.GET("/:id/attachments", attachmentHandler.ListInspection)
+
+			inspectionAttachmentsCreate := inspections.Group("")
+			inspectionAttachmentsCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceAttachment, rbac.ActionCreate))
+			inspectionAttachmentsCreate.POST("/:id/attachments", attachmentHandler.UploadInspection)
+		}
+
+		// Vehicle routes
+		vehicles := v1.Group("/vehicles")
+		vehicles.Use(authMiddleware.RequireAuth())
+		{
+			vehicleImport := vehicles.Group("/import")
+			vehicleImport.Use(rbacMiddleware.RequirePermission(rbac.ResourceVehicle, rbac.ActionImport))
+			vehicleImport.POST("", vehicleImportHandler.Import)
+			vehicleImport.POST("/preview", vehicleImportHandler.Preview)
+
+			vehicleExport := vehicles.Group("/export")
+			vehicleExport.Use(rbacMiddleware.RequirePermission(rbac.ResourceVehicle, rbac.ActionExport))
+			vehicleExport.GET("", vehicleImportHandler.Export)
+
+			vehicleStatus := vehicles.Group("")
+			vehicleStatus.Use(rbacMiddleware.RequirePermission(rbac.ResourceTelematics, rbac.ActionRead))
+			vehicleStatus.GET("/realtime-status", vehicleStatusHandler.GetFleetStatus)
+			vehicleStatus.GET("/:id/realtime-status", vehicleStatusHandler.GetVehicleStatus)
+			vehicleStatus.GET("/:id/trips", tripHandler.ListTrips)
+			vehicleStatus.GET("/:id/trips/:tripId", tripHandler.GetTrip)
+			vehicleStatus.GET("/:id/telematics", telematicsHistoryHandler.GetHistory)
+
+			vehicleRoutes := vehicles.Group("")
+			vehicleRoutes.Use(rbacMiddleware.RequirePermission(rbac.ResourceGPSData, rbac.ActionRead))
+			vehicleRoutes.GET("/:id/route", tripHandler.GetRoute)
+			vehicleRoutes.GET("/:id/trips/:tripId/route", tripHandler.GetTripRoute)
+
+			vehicleInspections := vehicles.Group("/:id/inspections")
+			vehicleInspections.Use(rbacMiddleware.RequirePermission(rbac.ResourceInspection, rbac.ActionList))
+			vehicleInspections.GET("", inspectionHandler.GetVehicleHistory)
+
+			vehicleDamageRead := vehicles.Group("/:id/damage-reports")
+			vehicleDamageRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceDamageReport, rbac.ActionRead))
+			vehicleDamageRead.GET("", damageHandler.ListByVehicle)
+			vehicleDamageRead.GET("/compare", damageHandler.Compare)
+
+			vehicleAttachmentsList := vehicles.Group("/:id/attachments")
+			vehicleAttachmentsList.Use(rbacMiddleware.RequirePermission(rbac.ResourceAttachment, rbac.ActionList))
+			vehicleAttachmentsList.GET("", attachmentHandler.ListVehicle)
+
+			vehicleAttachmentsCreate := vehicles.Group("/:id/attachments")
+			vehicleAttachmentsCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceAttachment, rbac.ActionCreate))
+			vehicleAttachmentsCreate.POST("", attachmentHandler.UploadVehicle)
+
+			vehicleDevices := vehicles.Group("/:id/devices")
+			vehicleDevices.Use(rbacMiddleware.RequirePermission(rbac.ResourceTelematicsDevice, rbac.ActionRead))
+			vehicleDevices.GET("", deviceHandler.GetVehicleDevices)
+
+			vehicleDTC := vehicles.Group("/:id/dtc-codes")
+			vehicleDTC.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionRead))
+			vehicleDTC.GET("", dtcHandler.ListByVehicle)
+
+			vehicleConditionAlerts := vehicles.Group("/:id/condition-alerts")
+			vehicleConditionAlerts.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionRead))
+			vehicleConditionAlerts.GET("", conditionHandler.ListVehicleAlerts)
+
+			vehicleFuelRead := vehicles.Group("")
+			vehicleFuelRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceFuel, rbac.ActionRead))
+			vehicleFuelRead.GET("/:id/fuel-profile", fuelHandler.GetProfile)
+			vehicleFuelRead.GET("/:id/fuel-events", fuelHandler.ListEvents)
+			vehicleFuelRead.GET("/:id/fuel-consumption", fuelHandler.GetConsumption)
+
+			vehicleFuelUpdate := vehicles.Group("")
+			vehicleFuelUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceFuel, rbac.ActionUpdate))
+			vehicleFuelUpdate.PUT("/:id/fuel-profile", fuelHandler.SaveProfile)
+
+			vehicleDamageCreate := vehicles.Group("/:id/damage-reports")
+			vehicleDamageCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceDamageReport, rbac.ActionCreate))
+			vehicleDamageCreate.POST("", damageHandler.Create)
+		}
+
+		// Damage report routes
+		damageReports := v1.Group("/damage-reports")
+		damageReports.Use(authMiddleware.RequireAuth())
+		{
+			damageRead := damageReports.Group("")
+			damageRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceDamageReport, rbac.ActionRead))
+			damageRead.GET("/zones", damageHandler.GetZones)
+			damageRead.GET("/:id", damageHandler.GetByID)
+
+			damageUpdate := damageReports.Group("/:id")
+			damageUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceDamageReport, rbac.ActionUpdate))
+			damageUpdate.PUT("/status", damageHandler.UpdateStatus)
+
+			damageRepair := damageReports.Group("/:id")
+			damageRepair.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionCreate))
+			damageRepair.POST("/repair-work-order", damageHandler.CreateRepairWorkOrder)
+
+			damageCharge := damageReports.Group("/:id")
+			damageCharge.Use(rbacMiddleware.RequirePermission(rbac.ResourceInvoice, rbac.ActionCreate))
+			damageCharge.POST("/charge", damageHandler.Charge)
+		}
+
+		// Telematics routes
+		telematics := v1.Group("/telematics")
+		{
+			// Devices authenticate with their own credentials instead of user tokens
+			telematicsIngest := telematics.Group("/ingest")
+			telematicsIngest.Use(deviceAuthMiddleware.RequireDevice())
+			telematicsIngest.POST("", telematicsHandler.Ingest)
+
+			telematicsDTC := telematics.Group("/dtc")
+			telematicsDTC.Use(deviceAuthMiddleware.RequireDevice())
+			telematicsDTC.POST("", dtcHandler.Report)
+
+			// Live updates over WebSocket or SSE
+			telematicsStream := telematics.Group("/stream")
+			telematicsStream.Use(authMiddleware.RequireStreamAuth())
+			telematicsStream.Use(rbacMiddleware.RequirePermission(rbac.ResourceGPSData, rbac.ActionRead))
+			telematicsStream.GET("", telematicsStreamHandler.Stream)
+		}
+
+		// Trip maintenance routes
+		trips := v1.Group("/trips")
+		trips.Use(authMiddleware.RequireAuth())
+		{
+			tripsManage := trips.Group("")
+			tripsManage.Use(rbacMiddleware.RequirePermission(rbac.ResourceTelematics, rbac.ActionUpdate))
+			tripsManage.POST("/rebuild", tripHandler.Rebuild)
+		}
+
+		// Driver behaviour routes
+		drivers := v1.Group("/drivers")
+		drivers.Use(authMiddleware.RequireAuth())
+		{
+			driversRead := drivers.Group("")
+			driversRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceDriverBehaviour, rbac.ActionRead))
+			driversRead.GET("/:id/behaviour", driverBehaviourHandler.GetHistory)
+			driversRead.GET("/:id/behaviour/trips", driverBehaviourHandler.ListTrips)
+
+			driversList := drivers.Group("/behaviour")
+			driversList.Use(rbacMiddleware.RequirePermission(rbac.ResourceDriverBehaviour, rbac.ActionList))
+			driversList.GET("/leaderboard", driverBehaviourHandler.GetLeaderboard)
+
+			driversSettings := drivers.Group("/behaviour/settings")
+			driversSettings.Use(rbacMiddleware.RequirePermission(rbac.ResourceDriverBehaviour, rbac.ActionUpdate))
+			driversSettings.GET("", driverBehaviourHandler.GetSettings)
+			driversSettings.PUT("", driverBehaviourHandler.UpdateSettings)
+		}
+
+		// Diagnostic trouble code routes
+		dtcRoutes := v1.Group("/dtc")
+		dtcRoutes.Use(authMiddleware.RequireAuth())
+		{
+			dtcRead := dtcRoutes.Group("")
+			dtcRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionRead))
+			dtcRead.GET("/codes/:code", dtcHandler.Describe)
+			dtcRead.GET("/severity-rules", dtcHandler.ListRules)
+
+			dtcCreate := dtcRoutes.Group("")
+			dtcCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionCreate))
+			dtcCreate.POST("/severity-rules", dtcHandler.CreateRule)
+
+			dtcUpdate := dtcRoutes.Group("")
+			dtcUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionUpdate))
+			dtcUpdate.PUT("/severity-rules/:id", dtcHandler.UpdateRule)
+
+			dtcDelete := dtcRoutes.Group("")
+			dtcDelete.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionDelete))
+			dtcDelete.DELETE("/severity-rules/:id", dtcHandler.DeleteRule)
+		}
+
+		// Condition monitoring routes
+		conditionRules := v1.Group("/condition-rules")
+		conditionRules.Use(authMiddleware.RequireAuth())
+		{
+			conditionRulesRead := conditionRules.Group("")
+			conditionRulesRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionRead))
+			conditionRulesRead.GET("", conditionHandler.ListRules)
+			conditionRulesRead.GET("/:id", conditionHandler.GetRule)
+
+			conditionRulesCreate := conditionRules.Group("")
+			conditionRulesCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionCreate))
+			conditionRulesCreate.POST("", conditionHandler.CreateRule)
+
+			conditionRulesUpdate := conditionRules.Group("")
+			conditionRulesUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionUpdate))
+			conditionRulesUpdate.PUT("/:id", conditionHandler.UpdateRule)
+
+			conditionRulesDelete := conditionRules.Group("")
+			conditionRulesDelete.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionDelete))
+			conditionRulesDelete.DELETE("/:id", conditionHandler.DeleteRule)
+		}
+
+		conditionAlerts := v1.Group("/condition-alerts")
+		conditionAlerts.Use(authMiddleware.RequireAuth())
+		{
+			conditionAlertsList := conditionAlerts.Group("")
+			conditionAlertsList.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionList))
+			conditionAlertsList.GET("", conditionHandler.ListAlerts)
+
+			conditionAlertsRead := conditionAlerts.Group("")
+			conditionAlertsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionRead))
+			conditionAlertsRead.GET("/:id", conditionHandler.GetAlert)
+
+			conditionAlertsAcknowledge := conditionAlerts.Group("")
+			conditionAlertsAcknowledge.Use(rbacMiddleware.RequirePermission(rbac.ResourceDiagnostics, rbac.ActionApprove))
+			conditionAlertsAcknowledge.PUT("/:id/acknowledge", conditionHandler.AcknowledgeAlert)
+		}
+
+		// Work order routes
+		workOrders := v1.Group("/workorders")
+		workOrders.Use(authMiddleware.RequireAuth())
+		{
+			workOrdersList := workOrders.Group("")
+			workOrdersList.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionList))
+			workOrdersList.GET("", workOrderHandler.List)
+
+			workOrdersRead := workOrders.Group("")
+			workOrdersRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionRead))
+			workOrdersRead.GET("/:id", workOrderHandler.GetByID)
+			workOrdersRead.GET("/:id/history", workOrderHandler.GetStatusHistory)
+			workOrdersRead.GET("/:id/tasks", laborHandler.ListTasks)
+			workOrdersRead.GET("/:id/labor", laborHandler.GetLabor)
+
+			workOrdersCreate := workOrders.Group("")
+			workOrdersCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionCreate))
+			workOrdersCreate.POST("", workOrderHandler.Create)
+
+			workOrdersUpdate := workOrders.Group("")
+			workOrdersUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionUpdate))
+			workOrdersUpdate.PUT("/:id", workOrderHandler.Update)
+			workOrdersUpdate.PUT("/:id/start", workOrderHandler.Start)
+			workOrdersUpdate.PUT("/:id/hold", workOrderHandler.Hold)
+			workOrdersUpdate.PUT("/:id/resume", workOrderHandler.Resume)
+			workOrdersUpdate.PUT("/:id/complete", workOrderHandler.Complete)
+			workOrdersUpdate.PUT("/:id/cancel", workOrderHandler.Cancel)
+			workOrdersUpdate.POST("/:id/tasks", laborHandler.CreateTask)
+			workOrdersUpdate.PUT("/:id/tasks/:taskId/clock-in", laborHandler.ClockIn)
+			workOrdersUpdate.PUT("/:id/tasks/:taskId/pause", laborHandler.Pause)
+			workOrdersUpdate.PUT("/:id/tasks/:taskId/clock-out", laborHandler.ClockOut)
+
+			workOrderPartsRead := workOrders.Group("")
+			workOrderPartsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrderItem, rbac.ActionRead))
+			workOrderPartsRead.GET("/:id/parts", partsHandler.List)
+
+			workOrderPartsCreate := workOrders.Group("")
+			workOrderPartsCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrderItem, rbac.ActionCreate))
+			workOrderPartsCreate.POST("/:id/parts", partsHandler.AddPart)
+
+			workOrderPartsUpdate := workOrders.Group("")
+			workOrderPartsUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrderItem, rbac.ActionUpdate))
+			workOrderPartsUpdate.PUT("/:id/parts/:partId/issue", partsHandler.IssuePart)
+
+			workOrderPartsDelete := workOrders.Group("")
+			workOrderPartsDelete.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrderItem, rbac.ActionDelete))
+			workOrderPartsDelete.DELETE("/:id/parts/:partId", partsHandler.RemovePart)
+
+			workOrderEstimatesRead := workOrders.Group("")
+			workOrderEstimatesRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceEstimate, rbac.ActionRead))
+			workOrderEstimatesRead.GET("/:id/estimates", estimateHandler.List)
+			workOrderEstimatesRead.GET("/:id/estimates/:estimateId", estimateHandler.GetByID)
+
+			workOrderEstimatesCreate := workOrders.Group("")
+			workOrderEstimatesCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceEstimate, rbac.ActionCreate))
+			workOrderEstimatesCreate.POST("/:id/estimates", estimateHandler.Create)
+
+			workOrderEstimatesUpdate := workOrders.Group("")
+			workOrderEstimatesUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceEstimate, rbac.ActionUpdate))
+			workOrderEstimatesUpdate.PUT("/:id/estimates/:estimateId", estimateHandler.Update)
+			workOrderEstimatesUpdate.PUT("/:id/estimates/:estimateId/send", estimateHandler.Send)
+
+			workOrderAppointmentsRead := workOrders.Group("")
+			workOrderAppointmentsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceAppointment, rbac.ActionRead))
+			workOrderAppointmentsRead.GET("/:id/appointments", appointmentHandler.ListByWorkOrder)
+
+			workOrderAppointmentsCreate := workOrders.Group("")
+			workOrderAppointmentsCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceAppointment, rbac.ActionCreate))
+			workOrderAppointmentsCreate.POST("/:id/appointments", appointmentHandler.Book)
+			workOrderAppointmentsCreate.POST("/:id/appointments/check", appointmentHandler.Check)
+
+			workOrderAttachmentsList := workOrders.Group("")
+			workOrderAttachmentsList.Use(rbacMiddleware.RequirePermission(rbac.ResourceAttachment, rbac.ActionList))
+			workOrderAttachmentsList.GET("/:id/attachments", attachmentHandler.ListWorkOrder)
+
+			workOrderAttachmentsCreate := workOrders.Group("")
+			workOrderAttachmentsCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceAttachment, rbac.ActionCreate))
+			workOrderAttachmentsCreate.POST("/:id/attachments", attachmentHandler.UploadWorkOrder)
+
+			workOrdersAssign := workOrders.Group("")
+			workOrdersAssign.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionAssign))
+			workOrdersAssign.GET("/:id/assignment-candidates", workOrderHandler.AssignmentCandidates)
+			workOrdersAssign.PUT("/:id/assign", workOrderHandler.Assign)
+
+			workOrdersDelete := workOrders.Group("")
+			workOrdersDelete.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionDelete))
+			workOrdersDelete.DELETE("/:id", workOrderHandler.Delete)
+		}
+
+		// Service request (PS) routes
+		serviceRequests := v1.Group("/servicerequest")
+		serviceRequests.Use(authMiddleware.RequireAuth())
+		{
+			serviceRequestsCreate := serviceRequests.Group("")
+			serviceRequestsCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceServiceRequest, rbac.ActionCreate))
+			serviceRequestsCreate.POST("", serviceRequestHandler.Create)
+
+			serviceRequestsList := serviceRequests.Group("")
+			serviceRequestsList.Use(rbacMiddleware.RequirePermission(rbac.ResourceServiceRequest, rbac.ActionList))
+			serviceRequestsList.GET("", serviceRequestHandler.List)
+
+			serviceRequestsRead := serviceRequests.Group("")
+			serviceRequestsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceServiceRequest, rbac.ActionRead))
+			serviceRequestsRead.GET("/:id", serviceRequestHandler.GetByID)
+
+			serviceRequestsApprove := serviceRequests.Group("")
+			serviceRequestsApprove.Use(rbacMiddleware.RequirePermission(rbac.ResourceServiceRequest, rbac.ActionApprove))
+			serviceRequestsApprove.PUT("/:id/approve", serviceRequestHandler.Approve)
+
+			serviceRequestsReject := serviceRequests.Group("")
+			serviceRequestsReject.Use(rbacMiddleware.RequirePermission(rbac.ResourceServiceRequest, rbac.ActionReject))
+			serviceRequestsReject.PUT("/:id/reject", serviceRequestHandler.Reject)
+
+			serviceRequestsAllocate := serviceRequests.Group("")
+			serviceRequestsAllocate.Use(rbacMiddleware.RequirePermission(rbac.ResourceServiceRequest, rbac.ActionAssign))
+			serviceRequestsAllocate.PUT("/:id/allocate", serviceRequestHandler.Allocate)
+
+			serviceRequestsConvert := serviceRequests.Group("")
+			serviceRequestsConvert.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionCreate))
+			serviceRequestsConvert.PUT("/:id/convert", serviceRequestHandler.Convert)
+
+			serviceRequestsUpdate := serviceRequests.Group("")
+			serviceRequestsUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceServiceRequest, rbac.ActionUpdate))
+			serviceRequestsUpdate.PUT("/:id/cancel", serviceRequestHandler.Cancel)
+		}
+
+		// Service area routes
+		serviceAreas := v1.Group("/service-areas")
+		serviceAreas.Use(authMiddleware.RequireAuth())
+		{
+			serviceAreasRead := serviceAreas.Group("")
+			serviceAreasRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceServiceRequest, rbac.ActionList))
+			serviceAreasRead.GET("", serviceRequestHandler.ListAreas)
+
+			serviceAreasManage := serviceAreas.Group("")
+			serviceAreasManage.Use(rbacMiddleware.RequirePermission(rbac.ResourceSystem, rbac.ActionUpdate))
+			serviceAreasManage.POST("", serviceRequestHandler.CreateArea)
+			serviceAreasManage.PUT("/:id", serviceRequestHandler.UpdateArea)
+			serviceAreasManage.PUT("/:id/members", serviceRequestHandler.AssignToArea)
+		}
+
+		// Mechanic profile, shift and workload routes
+		mechanics := v1.Group("/mechanics")
+		mechanics.Use(authMiddleware.RequireAuth())
+		{
+			mechanicsList := mechanics.Group("")
+			mechanicsList.Use(rbacMiddleware.RequirePermission(rbac.ResourceMechanic, rbac.ActionList))
+			mechanicsList.GET("", mechanicHandler.List)
+			mechanicsList.GET("/workload", mechanicHandler.Workload)
+
+			mechanicsRead := mechanics.Group("")
+			mechanicsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceMechanic, rbac.ActionRead))
+			mechanicsRead.GET("/:id", mechanicHandler.GetByID)
+			mechanicsRead.GET("/:id/shifts", mechanicHandler.ListShifts)
+			mechanicsRead.GET("/:id/clock", laborHandler.ActiveClock)
+
+			mechanicsUpdate := mechanics.Group("")
+			mechanicsUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceMechanic, rbac.ActionUpdate))
+			mechanicsUpdate.PUT("/:id", mechanicHandler.UpdateProfile)
+			mechanicsUpdate.POST("/:id/shifts", mechanicHandler.CreateShift)
+			mechanicsUpdate.DELETE("/:id/shifts/:shiftId", mechanicHandler.DeleteShift)
+		}
+
+		// Labor operation catalogue routes
+		laborOperations := v1.Group("/labor-operations")
+		laborOperations.Use(authMiddleware.RequireAuth())
+		{
+			laborOperationsList := laborOperations.Group("")
+			laborOperationsList.Use(rbacMiddleware.RequirePermission(rbac.ResourceLaborOperation, rbac.ActionList))
+			laborOperationsList.GET("", laborOperationHandler.List)
+
+			laborOperationsRead := laborOperations.Group("")
+			laborOperationsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceLaborOperation, rbac.ActionRead))
+			laborOperationsRead.GET("/:id", laborOperationHandler.GetByID)
+
+			laborOperationsCreate := laborOperations.Group("")
+			laborOperationsCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceLaborOperation, rbac.ActionCreate))
+			laborOperationsCreate.POST("", laborOperationHandler.Create)
+
+			laborOperationsUpdate := laborOperations.Group("")
+			laborOperationsUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceLaborOperation, rbac.ActionUpdate))
+			laborOperationsUpdate.PUT("/:id", laborOperationHandler.Update)
+
+			laborOperationsImport := laborOperations.Group("")
+			laborOperationsImport.Use(rbacMiddleware.RequirePermission(rbac.ResourceLaborOperation, rbac.ActionImport))
+			laborOperationsImport.POST("/import", laborOperationHandler.Import)
+		}
+
+		// Preventive maintenance plan routes
+		maintenancePlans := v1.Group("/maintenance-plans")
+		maintenancePlans.Use(authMiddleware.RequireAuth())
+		{
+			maintenancePlansList := maintenancePlans.Group("")
+			maintenancePlansList.Use(rbacMiddleware.RequirePermission(rbac.ResourceMaintenancePlan, rbac.ActionList))
+			maintenancePlansList.GET("", maintenanceHandler.ListPlans)
+
+			maintenancePlansRead := maintenancePlans.Group("")
+			maintenancePlansRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceMaintenancePlan, rbac.ActionRead))
+			maintenancePlansRead.GET("/:id", maintenanceHandler.GetPlan)
+
+			maintenancePlansCreate := maintenancePlans.Group("")
+			maintenancePlansCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceMaintenancePlan, rbac.ActionCreate))
+			maintenancePlansCreate.POST("", maintenanceHandler.CreatePlan)
+
+			maintenancePlansUpdate := maintenancePlans.Group("")
+			maintenancePlansUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceMaintenancePlan, rbac.ActionUpdate))
+			maintenancePlansUpdate.PUT("/:id", maintenanceHandler.UpdatePlan)
+			maintenancePlansUpdate.POST("/run", maintenanceHandler.RunScheduler)
+		}
+
+		// Vehicle maintenance schedule routes
+		maintenanceSchedules := v1.Group("/maintenance-schedules")
+		maintenanceSchedules.Use(authMiddleware.RequireAuth())
+		{
+			maintenanceSchedulesList := maintenanceSchedules.Group("")
+			maintenanceSchedulesList.Use(rbacMiddleware.RequirePermission(rbac.ResourceMaintenancePlan, rbac.ActionList))
+			maintenanceSchedulesList.GET("", maintenanceHandler.ListSchedules)
+
+			// Maintenance done outside a work order is recorded by whoever can update work orders
+			maintenanceSchedulesDone := maintenanceSchedules.Group("")
+			maintenanceSchedulesDone.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkOrder, rbac.ActionUpdate))
+			maintenanceSchedulesDone.POST("/:id/done", maintenanceHandler.RecordDone)
+		}
+
+		// Workshop bay routes
+		workshopBays := v1.Group("/workshop-bays")
+		workshopBays.Use(authMiddleware.RequireAuth())
+		{
+			workshopBaysList := workshopBays.Group("")
+			workshopBaysList.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkshopBay, rbac.ActionList))
+			workshopBaysList.GET("", appointmentHandler.ListBays)
+
+			workshopBaysRead := workshopBays.Group("")
+			workshopBaysRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkshopBay, rbac.ActionRead))
+			workshopBaysRead.GET("/:id", appointmentHandler.GetBay)
+
+			workshopBaysCreate := workshopBays.Group("")
+			workshopBaysCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkshopBay, rbac.ActionCreate))
+			workshopBaysCreate.POST("", appointmentHandler.CreateBay)
+
+			workshopBaysUpdate := workshopBays.Group("")
+			workshopBaysUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceWorkshopBay, rbac.ActionUpdate))
+			workshopBaysUpdate.PUT("/:id", appointmentHandler.UpdateBay)
+		}
+
+		// Workshop appointment calendar routes
+		appointments := v1.Group("/appointments")
+		appointments.Use(authMiddleware.RequireAuth())
+		{
+			appointmentsList := appointments.Group("")
+			appointmentsList.Use(rbacMiddleware.RequirePermission(rbac.ResourceAppointment, rbac.ActionList))
+			appointmentsList.GET("", appointmentHandler.List)
+			appointmentsList.GET("/capacity", appointmentHandler.Capacity)
+
+			appointmentsRead := appointments.Group("")
+			appointmentsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceAppointment, rbac.ActionRead))
+			appointmentsRead.GET("/:id", appointmentHandler.Get)
+
+			appointmentsUpdate := appointments.Group("")
+			appointmentsUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceAppointment, rbac.ActionUpdate))
+			appointmentsUpdate.PUT("/:id/move", appointmentHandler.Move)
+			appointmentsUpdate.PUT("/:id/cancel", appointmentHandler.Cancel)
+		}
+
+		// Attachment routes
+		attachments := v1.Group("/attachments")
+		attachments.Use(authMiddleware.RequireAuth())
+		{
+			attachmentsRead := attachments.Group("")
+			attachmentsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceAttachment, rbac.ActionRead))
+			attachmentsRead.GET("/:id", attachmentHandler.Get)
+			attachmentsRead.GET("/:id/content", attachmentHandler.Content)
+
+			attachmentsDelete := attachments.Group("")
+			attachmentsDelete.Use(rbacMiddleware.RequirePermission(rbac.ResourceAttachment, rbac.ActionDelete))
+			attachmentsDelete.DELETE("/:id", attachmentHandler.Delete)
+		}
+
+		// Signed file download routes (public, authorised by the link signature; local storage only)
+		v1.GET("/files/*key", attachmentHandler.ServeFile)
+
+		// Estimate approval routes (public, authorised by the link token)
+		estimateApprovals := v1.Group("/estimate-approvals")
+		{
+			estimateApprovals.GET("/:token", estimateHandler.GetForCustomer)
+			estimateApprovals.POST("/:token", estimateHandler.Respond)
+		}
+
+		// Stock transfer request routes
+		stockTransfers := v1.Group("/stock-transfers")
+		stockTransfers.Use(authMiddleware.RequireAuth())
+		{
+			stockTransfersList := stockTransfers.Group("")
+			stockTransfersList.Use(rbacMiddleware.RequirePermission(rbac.ResourceStockMovement, rbac.ActionList))
+			stockTransfersList.GET("", partsHandler.ListTransfers)
+
+			stockTransfersRead := stockTransfers.Group("")
+			stockTransfersRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceStockMovement, rbac.ActionRead))
+			stockTransfersRead.GET("/:id", partsHandler.GetTransfer)
+
+			stockTransfersUpdate := stockTransfers.Group("")
+			stockTransfersUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceStockMovement, rbac.ActionUpdate))
+			stockTransfersUpdate.PUT("/:id/fulfil", partsHandler.FulfilTransfer)
+			stockTransfersUpdate.PUT("/:id/cancel", partsHandler.CancelTransfer)
+		}
+
+		// Report routes
+		reports := v1.Group("/reports")
+		reports.Use(authMiddleware.RequireAuth())
+		{
+			reportsRead := reports.Group("")
+			reportsRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceReport, rbac.ActionRead))
+			reportsRead.GET("/labor-efficiency", laborHandler.EfficiencyReport)
+			reportsRead.GET("/labor-standards", laborOperationHandler.StandardReport)
+			reportsRead.GET("/maintenance-overdue", maintenanceHandler.OverdueReport)
+		}
+
+		// Fuel receipt and anomaly routes
+		fuel := v1.Group("/fuel")
+		fuel.Use(authMiddleware.RequireAuth())
+		{
+			fuelCreate := fuel.Group("")
+			fuelCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceFuel, rbac.ActionCreate))
+			fuelCreate.POST("/receipts", fuelHandler.CreateReceipt)
+
+			fuelRead := fuel.Group("")
+			fuelRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceFuel, rbac.ActionRead))
+			fuelRead.GET("/receipts", fuelHandler.ListReceipts)
+			fuelRead.GET("/receipts/:id", fuelHandler.GetReceipt)
+			fuelRead.GET("/anomalies/:id", fuelHandler.GetAnomaly)
+
+			fuelUpdate := fuel.Group("")
+			fuelUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceFuel, rbac.ActionUpdate))
+			fuelUpdate.PUT("/receipts/:id", fuelHandler.UpdateReceipt)
+
+			fuelDelete := fuel.Group("")
+			fuelDelete.Use(rbacMiddleware.RequirePermission(rbac.ResourceFuel, rbac.ActionDelete))
+			fuelDelete.DELETE("/receipts/:id", fuelHandler.DeleteReceipt)
+
+			fuelList := fuel.Group("")
+			fuelList.Use(rbacMiddleware.RequirePermission(rbac.ResourceFuel, rbac.ActionList))
+			fuelList.GET("/anomalies", fuelHandler.ListAnomalies)
+
+			fuelReview := fuel.Group("")
+			fuelReview.Use(rbacMiddleware.RequirePermission(rbac.ResourceFuel, rbac.ActionApprove))
+			fuelReview.PUT("/anomalies/:id/review", fuelHandler.ReviewAnomaly)
+		}
+
+		// Geofence routes
+		geofences := v1.Group("/geofences")
+		geofences.Use(authMiddleware.RequireAuth())
+		{
+			geofencesRead := geofences.Group("")
+			geofencesRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceGeofence, rbac.ActionRead))
+			geofencesRead.GET("", geofenceHandler.List)
+			geofencesRead.GET("/events", geofenceHandler.ListEvents)
+			geofencesRead.GET("/:id", geofenceHandler.GetByID)
+			geofencesRead.GET("/:id/events", geofenceHandler.ListGeofenceEvents)
+			geofencesRead.GET("/:id/alert-rules", geofenceHandler.ListAlertRules)
+
+			geofencesCreate := geofences.Group("")
+			geofencesCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceGeofence, rbac.ActionCreate))
+			geofencesCreate.POST("", geofenceHandler.Create)
+			geofencesCreate.POST("/:id/alert-rules", geofenceHandler.CreateAlertRule)
+
+			geofencesUpdate := geofences.Group("")
+			geofencesUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceGeofence, rbac.ActionUpdate))
+			geofencesUpdate.PUT("/:id", geofenceHandler.Update)
+			geofencesUpdate.PUT("/alert-rules/:ruleId", geofenceHandler.UpdateAlertRule)
+
+			geofencesDelete := geofences.Group("")
+			geofencesDelete.Use(rbacMiddleware.RequirePermission(rbac.ResourceGeofence, rbac.ActionDelete))
+			geofencesDelete.DELETE("/:id", geofenceHandler.Delete)
+			geofencesDelete.DELETE("/alert-rules/:ruleId", geofenceHandler.DeleteAlertRule)
+		}
+
+		// Notification routes for the current user
+		notifications := v1.Group("/notifications")
+		notifications.Use(authMiddleware.RequireAuth())
+		{
+			notifications.GET("", notificationHandler.List)
+			notifications.PUT("/read-all", notificationHandler.MarkAllRead)
+			notifications.PUT("/:id/read", notificationHandler.MarkRead)
+		}
+
+		// Telematics device registry routes
+		devices := v1.Group("/devices")
+		devices.Use(authMiddleware.RequireAuth())
+		{
+			devicesRead := devices.Group("")
+			devicesRead.Use(rbacMiddleware.RequirePermission(rbac.ResourceTelematicsDevice, rbac.ActionRead))
+			devicesRead.GET("", deviceHandler.List)
+			devicesRead.GET("/:id", deviceHandler.GetByID)
+			devicesRead.GET("/:id/bindings", deviceHandler.GetBindingHistory)
+
+			devicesCreate := devices.Group("")
+			devicesCreate.Use(rbacMiddleware.RequirePermission(rbac.ResourceTelematicsDevice, rbac.ActionCreate))
+			devicesCreate.POST("", deviceHandler.Register)
+			devicesCreate.POST("/:id/credentials", deviceHandler.RotateCredentials)
+
+			devicesUpdate := devices.Group("/:id")
+			devicesUpdate.Use(rbacMiddleware.RequirePermission(rbac.ResourceTelematicsDevice, rbac.ActionUpdate))
+			devicesUpdate.PUT("", deviceHandler.Update)
+			devicesUpdate.POST("/bind", deviceHandler.Bind)
+			devicesUpdate.POST("/unbind", deviceHandler.Unbind)
+		}
+
 		// RBAC demonstration routes
 		demo := v1.Group("/demo")
 		demo.Use(authMiddleware.RequireAuth())
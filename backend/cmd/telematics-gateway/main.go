@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/config"
+	"ton-platform/internal/database"
+	"ton-platform/internal/gateway"
+	"ton-platform/internal/gateway/protocol"
+	"ton-platform/internal/repository/postgres"
+	"ton-platform/internal/service"
+)
+
+func main() {
+	// Load configuration
+	cfg := config.Load()
+
+	// Setup logger
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetLevel(logrus.InfoLevel)
+
+	listeners, err := cfg.Gateway.GetTCPListeners()
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid gateway configuration")
+	}
+
+	// Connect to database
+	db, err := database.NewConnection(&cfg.Database, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to connect to database")
+	}
+	defer database.CloseConnection(db, logger)
+
+	// Connect to Redis so API instances can stream samples received here
+	redisClient, err := database.NewRedisClient(&cfg.Redis, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Redis unavailable, samples will not be streamed live")
+		redisClient = nil
+	}
+	defer database.CloseRedisClient(redisClient, logger)
+
+	// Initialize repositories and the shared ingestion pipeline
+	telematicsRepo := postgres.NewTelematicsRepositoryPostgres(db)
+	deviceRepo := postgres.NewDeviceRepositoryPostgres(db)
+	vehicleRepo := postgres.NewVehicleRepositoryPostgres(db)
+	vehicleStatusRepo := postgres.NewVehicleStatusRepositoryPostgres(db)
+	dtcRepo := postgres.NewDTCRepositoryPostgres(db)
+	geofenceRepo := postgres.NewGeofenceRepositoryPostgres(db)
+	userRepo := postgres.NewUserRepositoryPostgres(db)
+	notificationService := service.NewNotificationService(postgres.NewNotificationRepositoryPostgres(db), logger)
+	telematicsIngestService := service.NewTelematicsIngestService(telematicsRepo, deviceRepo, logger)
+	defer telematicsIngestService.Close()
+	telematicsIngestService.AddListener(service.NewVehicleStatusService(vehicleStatusRepo, dtcRepo, vehicleRepo, redisClient, logger))
+	// Trip segmentation, fuel analysis, compaction and retention run in the API server; the
+	// gateway only queues the vehicles and hours it ingests
+	telematicsIngestService.AddListener(service.NewTripService(postgres.NewTripRepositoryPostgres(db), telematicsRepo, vehicleRepo, logger))
+	telematicsIngestService.AddListener(service.NewFuelService(postgres.NewFuelRepositoryPostgres(db), telematicsRepo, vehicleRepo, logger))
+	telematicsIngestService.AddListener(service.NewTelematicsStorageService(postgres.NewTelematicsStorageRepositoryPostgres(db), telematicsRepo, vehicleRepo, service.TelematicsRetentionConfig{}, logger))
+	telematicsIngestService.AddListener(service.NewGeofenceService(geofenceRepo, vehicleRepo, userRepo, notificationService, logger))
+	telematicsIngestService.AddListener(service.NewConditionService(postgres.NewConditionRepositoryPostgres(db), vehicleRepo,
+		postgres.NewWorkOrderRepositoryPostgres(db), userRepo, postgres.NewRoleRepositoryPostgres(db), notificationService, logger))
+	if redisClient != nil {
+		telematicsIngestService.AddListener(service.NewTelematicsStreamService(redisClient, vehicleRepo, logger))
+	}
+
+	// Optional raw frame capture for later replay
+	var recorder *gateway.Recorder
+	if cfg.Gateway.RecordFile != "" {
+		file, err := os.OpenFile(cfg.Gateway.RecordFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to open frame record file")
+		}
+		defer file.Close()
+		recorder = gateway.NewRecorder(file)
+	}
+
+	authorizer := gateway.NewDeviceAuthorizer(deviceRepo)
+
+	// Start TCP listeners
+	var servers []*gateway.TCPServer
+	for name, addr := range listeners {
+		streamProtocol, err := protocol.LookupStream(name)
+		if err != nil {
+			logger.WithError(err).WithField("available", protocol.Names()).Fatal("Invalid gateway listener")
+		}
+		server := gateway.NewTCPServer(addr, streamProtocol, authorizer, telematicsIngestService, recorder, logger)
+		servers = append(servers, server)
+		go func() {
+			if err := server.ListenAndServe(); err != nil {
+				logger.WithError(err).Fatal("Telematics TCP listener failed")
+			}
+		}()
+	}
+
+	// Start MQTT subscriber
+	var subscriber *gateway.MQTTSubscriber
+	if cfg.Gateway.MQTTBroker != "" {
+		subscriber, err = gateway.NewMQTTSubscriber(gateway.MQTTConfig{
+			BrokerURL:     cfg.Gateway.MQTTBroker,
+			ClientID:      cfg.Gateway.MQTTClientID,
+			Username:      cfg.Gateway.MQTTUsername,
+			Password:      cfg.Gateway.MQTTPassword,
+			Topic:         cfg.Gateway.MQTTTopic,
+			PayloadFormat: cfg.Gateway.MQTTPayloadFormat,
+		}, telematicsIngestService, recorder, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid MQTT configuration")
+		}
+		if err := subscriber.Start(); err != nil {
+			logger.WithError(err).Fatal("Failed to start MQTT subscriber")
+		}
+	}
+
+	// Wait for shutdown signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("Shutting down telematics gateway")
+	if subscriber != nil {
+		subscriber.Close()
+	}
+	for _, server := range servers {
+		server.Close()
+	}
+}
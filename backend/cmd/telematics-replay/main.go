@@ -0,0 +1,90 @@
+// Command telematics-replay replays frames captured by the telematics gateway
+// (GATEWAY_RECORD_FILE) against a gateway, or decodes them offline.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/gateway"
+	"ton-platform/internal/gateway/protocol"
+)
+
+func main() {
+	file := flag.String("file", "", "frame capture file written by the gateway")
+	addr := flag.String("addr", "localhost:5027", "gateway TCP listener for stream protocol frames")
+	broker := flag.String("mqtt-broker", "", "MQTT broker for frames of non-TCP protocols")
+	topic := flag.String("mqtt-topic", "ton/telematics/+/data", "MQTT topic, + is replaced by the device identifier")
+	speed := flag.Float64("speed", 0, "replay speed relative to the capture, 0 sends as fast as possible")
+	decode := flag.Bool("decode", false, "print the decoded samples instead of sending frames")
+	flag.Parse()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.InfoLevel)
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to open capture file")
+	}
+	frames, err := gateway.ReadRecordedFrames(f)
+	f.Close()
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to read capture file")
+	}
+
+	if *decode {
+		if err := decodeFrames(frames); err != nil {
+			logger.WithError(err).Fatal("Failed to decode frames")
+		}
+		return
+	}
+
+	replayer := &gateway.Replayer{
+		TCPAddr: *addr,
+		MQTT: gateway.MQTTConfig{
+			BrokerURL: *broker,
+			ClientID:  "ton-telematics-replay",
+			Topic:     *topic,
+		},
+		Speed:  *speed,
+		Logger: logger,
+	}
+	if err := replayer.Replay(frames); err != nil {
+		logger.WithError(err).Fatal("Replay failed")
+	}
+	logger.WithField("frames", len(frames)).Info("Replay finished")
+}
+
+// decodeFrames prints the normalized samples of every frame as JSON lines
+func decodeFrames(frames []*gateway.RecordedFrame) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for i, frame := range frames {
+		decoder, err := protocol.Lookup(frame.Protocol)
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i+1, err)
+		}
+		samples, err := decoder.DecodeFrame(frame.Frame)
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i+1, err)
+		}
+		for _, sample := range samples {
+			if sample == nil {
+				continue
+			}
+			sample.DeviceID = frame.Identifier
+			if err := encoder.Encode(sample); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
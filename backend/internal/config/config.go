@@ -3,14 +3,20 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Redis      RedisConfig      `mapstructure:"redis"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	Gateway    GatewayConfig    `mapstructure:"gateway"`
+	Telematics TelematicsConfig `mapstructure:"telematics"`
+	Workshop   WorkshopConfig   `mapstructure:"workshop"`
+	Storage    StorageConfig    `mapstructure:"storage"`
 }
 
 // ServerConfig represents server configuration
@@ -47,6 +53,55 @@ type JWTConfig struct {
 	RefreshExpireTime int   `mapstructure:"refresh_expire_time"`
 }
 
+// GatewayConfig represents telematics gateway configuration
+type GatewayConfig struct {
+	// TCPListeners maps protocols to listen addresses, e.g. "teltonika_codec8=:5027"
+	TCPListeners      string `mapstructure:"tcp_listeners"`
+	MQTTBroker        string `mapstructure:"mqtt_broker"` // empty disables the MQTT subscriber
+	MQTTClientID      string `mapstructure:"mqtt_client_id"`
+	MQTTUsername      string `mapstructure:"mqtt_username"`
+	MQTTPassword      string `mapstructure:"mqtt_password"`
+	MQTTTopic         string `mapstructure:"mqtt_topic"`
+	MQTTPayloadFormat string `mapstructure:"mqtt_payload_format"`
+	RecordFile        string `mapstructure:"record_file"` // empty disables frame recording
+}
+
+// TelematicsConfig represents telematics storage and retention configuration
+type TelematicsConfig struct {
+	RawRetentionDays     int `mapstructure:"raw_retention_days"`    // raw samples, whole daily partitions are dropped
+	MinuteRetentionDays  int `mapstructure:"minute_retention_days"` // 1-minute aggregates
+	HourRetentionDays    int `mapstructure:"hour_retention_days"`   // 1-hour aggregates, 0 keeps them forever
+	PartitionPremakeDays int `mapstructure:"partition_premake_days"`
+	MaintenanceInterval  int `mapstructure:"maintenance_interval"` // minutes
+}
+
+// WorkshopConfig represents workshop configuration
+type WorkshopConfig struct {
+	DefaultLaborRate    float64 `mapstructure:"default_labor_rate"`   // hourly rate of mechanics without their own rate
+	TaxRate             float64 `mapstructure:"tax_rate"`             // percent added to estimates
+	EstimateLinkDays    int     `mapstructure:"estimate_link_days"`   // validity of customer approval links
+	PublicURL           string  `mapstructure:"public_url"`           // base URL customer approval links point to
+	MaintenanceInterval int     `mapstructure:"maintenance_interval"` // minutes between preventive maintenance scheduler runs
+	OpeningHour         int     `mapstructure:"opening_hour"`         // hour of day (UTC) bays can be booked from
+	ClosingHour         int     `mapstructure:"closing_hour"`         // hour of day (UTC) bookings must end by
+}
+
+// StorageConfig represents object storage configuration for uploaded attachments
+type StorageConfig struct {
+	Driver        string `mapstructure:"driver"`         // local, s3
+	LocalPath     string `mapstructure:"local_path"`     // directory of the local driver
+	SigningSecret string `mapstructure:"signing_secret"` // signs the download links of the local driver
+	Endpoint      string `mapstructure:"endpoint"`       // S3 compatible endpoint, e.g. http://localhost:9000 for MinIO
+	Region        string `mapstructure:"region"`
+	Bucket        string `mapstructure:"bucket"`
+	AccessKey     string `mapstructure:"access_key"`
+	SecretKey     string `mapstructure:"secret_key"`
+	PathStyle     bool   `mapstructure:"path_style"` // bucket in the path rather than the host name
+	MaxUploadMB   int    `mapstructure:"max_upload_mb"`
+	URLExpiry     int    `mapstructure:"url_expiry"`     // minutes signed download links stay valid
+	ThumbnailSize int    `mapstructure:"thumbnail_size"` // longest side of image thumbnails in pixels
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	return &Config{
@@ -76,6 +131,46 @@ func Load() *Config {
 			AccessExpireTime: getEnvAsInt("JWT_ACCESS_EXPIRE_TIME", 15), // 15 minutes
 			RefreshExpireTime: getEnvAsInt("JWT_REFRESH_EXPIRE_TIME", 168), // 7 days
 		},
+		Gateway: GatewayConfig{
+			TCPListeners:      getEnv("GATEWAY_TCP_LISTENERS", "teltonika_codec8=:5027"),
+			MQTTBroker:        getEnv("GATEWAY_MQTT_BROKER", ""),
+			MQTTClientID:      getEnv("GATEWAY_MQTT_CLIENT_ID", "ton-telematics-gateway"),
+			MQTTUsername:      getEnv("GATEWAY_MQTT_USERNAME", ""),
+			MQTTPassword:      getEnv("GATEWAY_MQTT_PASSWORD", ""),
+			MQTTTopic:         getEnv("GATEWAY_MQTT_TOPIC", "ton/telematics/+/data"),
+			MQTTPayloadFormat: getEnv("GATEWAY_MQTT_PAYLOAD_FORMAT", "json"),
+			RecordFile:        getEnv("GATEWAY_RECORD_FILE", ""),
+		},
+		Telematics: TelematicsConfig{
+			RawRetentionDays:     getEnvAsInt("TELEMATICS_RAW_RETENTION_DAYS", 90),
+			MinuteRetentionDays:  getEnvAsInt("TELEMATICS_MINUTE_RETENTION_DAYS", 365),
+			HourRetentionDays:    getEnvAsInt("TELEMATICS_HOUR_RETENTION_DAYS", 0),
+			PartitionPremakeDays: getEnvAsInt("TELEMATICS_PARTITION_PREMAKE_DAYS", 7),
+			MaintenanceInterval:  getEnvAsInt("TELEMATICS_MAINTENANCE_INTERVAL", 5),
+		},
+		Workshop: WorkshopConfig{
+			DefaultLaborRate:    getEnvAsFloat("WORKSHOP_DEFAULT_LABOR_RATE", 50),
+			TaxRate:             getEnvAsFloat("WORKSHOP_TAX_RATE", 0),
+			EstimateLinkDays:    getEnvAsInt("WORKSHOP_ESTIMATE_LINK_DAYS", 14),
+			PublicURL:           getEnv("WORKSHOP_PUBLIC_URL", "http://localhost:8080"),
+			MaintenanceInterval: getEnvAsInt("WORKSHOP_MAINTENANCE_INTERVAL", 60),
+			OpeningHour:         getEnvAsInt("WORKSHOP_OPENING_HOUR", 8),
+			ClosingHour:         getEnvAsInt("WORKSHOP_CLOSING_HOUR", 18),
+		},
+		Storage: StorageConfig{
+			Driver:        getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:     getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+			SigningSecret: getEnv("STORAGE_SIGNING_SECRET", "ton-platform-storage-secret"),
+			Endpoint:      getEnv("STORAGE_ENDPOINT", ""),
+			Region:        getEnv("STORAGE_REGION", "us-east-1"),
+			Bucket:        getEnv("STORAGE_BUCKET", ""),
+			AccessKey:     getEnv("STORAGE_ACCESS_KEY", ""),
+			SecretKey:     getEnv("STORAGE_SECRET_KEY", ""),
+			PathStyle:     getEnvAsBool("STORAGE_PATH_STYLE", true),
+			MaxUploadMB:   getEnvAsInt("STORAGE_MAX_UPLOAD_MB", 10),
+			URLExpiry:     getEnvAsInt("STORAGE_URL_EXPIRY", 15),
+			ThumbnailSize: getEnvAsInt("STORAGE_THUMBNAIL_SIZE", 320),
+		},
 	}
 }
 
@@ -90,6 +185,23 @@ func (r *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%s", r.Host, r.Port)
 }
 
+// GetTCPListeners parses TCPListeners into protocol → address pairs
+func (g *GatewayConfig) GetTCPListeners() (map[string]string, error) {
+	listeners := make(map[string]string)
+	for _, entry := range strings.Split(g.TCPListeners, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid gateway listener %q, expected protocol=address", entry)
+		}
+		listeners[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return listeners, nil
+}
+
 // Helper functions
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -107,4 +219,22 @@ func getEnvAsInt(key string, defaultValue int) int {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if valueStr, exists := os.LookupEnv(key); exists {
+		if value, err := strconv.ParseFloat(strings.TrimSpace(valueStr), 64); err == nil {
+			return value
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if valueStr, exists := os.LookupEnv(key); exists {
+		if value, err := strconv.ParseBool(strings.TrimSpace(valueStr)); err == nil {
+			return value
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/config"
+)
+
+// NewRedisClient creates a new Redis client and verifies the connection
+func NewRedisClient(cfg *config.RedisConfig, logger *logrus.Logger) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.GetRedisAddr(),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"addr": cfg.GetRedisAddr(),
+		"db":   cfg.DB,
+	}).Info("Redis connection established successfully")
+
+	return client, nil
+}
+
+// CloseRedisClient closes the Redis client
+func CloseRedisClient(client *redis.Client, logger *logrus.Logger) error {
+	if client == nil {
+		return nil
+	}
+
+	if err := client.Close(); err != nil {
+		logger.WithError(err).Error("Failed to close redis connection")
+		return err
+	}
+
+	logger.Info("Redis connection closed successfully")
+	return nil
+}
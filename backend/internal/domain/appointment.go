@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// WorkshopBay is a bay or lift of a workshop branch that work orders are booked on. Empty
+// vehicle types accept every vehicle type.
+type WorkshopBay struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	BranchID     uint       `json:"branch_id" gorm:"not null"`
+	Branch       *Warehouse `json:"branch,omitempty" gorm:"foreignKey:BranchID"`
+	Name         string     `json:"name" gorm:"not null"`
+	BayType      string     `json:"bay_type" gorm:"not null"` // bay, lift
+	VehicleTypes StringList `json:"vehicle_types" gorm:"type:jsonb"`
+	IsActive     bool       `json:"is_active"`
+	Notes        string     `json:"notes"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// WorkshopBay type constants
+const (
+	BayTypeBay  = "bay"
+	BayTypeLift = "lift"
+)
+
+// Accepts reports whether a vehicle of the given type can be worked on in the bay
+func (b *WorkshopBay) Accepts(vehicleType string) bool {
+	return len(b.VehicleTypes) == 0 || b.VehicleTypes.Contains(vehicleType)
+}
+
+// WorkOrderAppointment books a work order on a bay for a period. The mechanic is the one
+// assigned to the work order and follows it when the work order is reassigned.
+type WorkOrderAppointment struct {
+	ID           uint         `json:"id" gorm:"primaryKey"`
+	WorkOrderID  uint         `json:"work_order_id" gorm:"not null"`
+	WorkOrder    *WorkOrder   `json:"work_order,omitempty" gorm:"foreignKey:WorkOrderID"`
+	BranchID     uint         `json:"branch_id" gorm:"not null"`
+	BayID        uint         `json:"bay_id" gorm:"not null"`
+	Bay          *WorkshopBay `json:"bay,omitempty" gorm:"foreignKey:BayID"`
+	MechanicID   *uint        `json:"mechanic_id"`
+	Mechanic     *User        `json:"mechanic,omitempty" gorm:"foreignKey:MechanicID"`
+	StartsAt     time.Time    `json:"starts_at" gorm:"not null"`
+	EndsAt       time.Time    `json:"ends_at" gorm:"not null"`
+	Status       string       `json:"status" gorm:"not null"`
+	Notes        string       `json:"notes"`
+	CancelReason string       `json:"cancel_reason"`
+	CreatedBy    uint         `json:"created_by" gorm:"not null"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// WorkOrderAppointment status constants
+const (
+	AppointmentStatusScheduled = "scheduled"
+	AppointmentStatusCompleted = "completed"
+	AppointmentStatusCancelled = "cancelled"
+)
+
+// Hours returns the part of the appointment within [from, to) in hours
+func (a *WorkOrderAppointment) Hours(from, to time.Time) float64 {
+	start, end := a.StartsAt, a.EndsAt
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start).Hours()
+}
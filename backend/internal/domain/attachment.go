@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Attachment is a file uploaded to a work order, vehicle or inspection and kept in object
+// storage. URL and ThumbnailURL are signed download links filled in when the attachment is
+// served; they expire at URLExpiresAt.
+type Attachment struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	OwnerType    string    `json:"owner_type" gorm:"not null"` // work_order, vehicle, inspection
+	OwnerID      uint      `json:"owner_id" gorm:"not null"`
+	Category     string    `json:"category" gorm:"not null"`
+	FileName     string    `json:"file_name" gorm:"not null"`
+	ContentType  string    `json:"content_type" gorm:"not null"`
+	SizeBytes    int64     `json:"size_bytes" gorm:"not null"`
+	Checksum     string    `json:"checksum" gorm:"not null"`
+	StorageKey   string    `json:"-" gorm:"not null"`
+	ThumbnailKey *string   `json:"-"`
+	Width        *int      `json:"width"`
+	Height       *int      `json:"height"`
+	Description  string    `json:"description"`
+	UploadedBy   uint      `json:"uploaded_by" gorm:"not null"`
+	Uploader     *User     `json:"uploader,omitempty" gorm:"foreignKey:UploadedBy"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	URL          string     `json:"url,omitempty" gorm:"-"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty" gorm:"-"`
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty" gorm:"-"`
+}
+
+// Attachment owner type constants
+const (
+	AttachmentOwnerWorkOrder  = "work_order"
+	AttachmentOwnerVehicle    = "vehicle"
+	AttachmentOwnerInspection = "inspection"
+)
+
+// Attachment category constants. The photo categories match the work order photo types.
+const (
+	AttachmentCategoryBefore   = "before"
+	AttachmentCategoryAfter    = "after"
+	AttachmentCategoryDamage   = "damage"
+	AttachmentCategoryProgress = "progress"
+	AttachmentCategoryDocument = "document"
+	AttachmentCategoryOther    = "other"
+)
+
+// IsImage reports whether the attachment is an image
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// WorkOrderPhoto lists an image attachment among the photos of a work order
+type WorkOrderPhoto struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	WorkOrderID  uint      `json:"work_order_id" gorm:"not null"`
+	AttachmentID *uint     `json:"attachment_id"`
+	PhotoURL     string    `json:"photo_url" gorm:"not null"`
+	Description  string    `json:"description"`
+	PhotoType    string    `json:"photo_type" gorm:"not null"` // before, after, damage, progress, etc.
+	UploadedBy   uint      `json:"uploaded_by" gorm:"not null"`
+	UploadedAt   time.Time `json:"uploaded_at" gorm:"autoCreateTime"`
+}
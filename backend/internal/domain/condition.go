@@ -0,0 +1,148 @@
+package domain
+
+import "time"
+
+// ConditionRule raises an alert when a sensor reading stays beyond a threshold. Once raised,
+// the alert only clears when the reading is back past the clear threshold, so readings
+// hovering around the threshold do not flap.
+type ConditionRule struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	Name             string    `json:"name" gorm:"not null"`
+	Description      string    `json:"description"`
+	VehicleType      *string   `json:"vehicle_type"`             // nil applies to every vehicle type
+	Metric           string    `json:"metric" gorm:"not null"`   // engine_temperature, oil_pressure, battery_voltage, engine_rpm
+	Operator         string    `json:"operator" gorm:"not null"` // above, below
+	Threshold        float64   `json:"threshold"`
+	ClearThreshold   float64   `json:"clear_threshold"`
+	DurationSeconds  int       `json:"duration_seconds"`             // how long the condition must hold, 0 alerts on the first reading
+	EngineState      string    `json:"engine_state" gorm:"not null"` // any, running, off
+	Severity         string    `json:"severity" gorm:"not null"`     // warning, critical
+	CreateWorkOrder  bool      `json:"create_work_order"`            // open an inspection work order when raised
+	RecipientUserIDs UintList  `json:"recipient_user_ids" gorm:"type:jsonb"`
+	IsActive         bool      `json:"is_active"`
+	CreatedBy        *uint     `json:"created_by"` // empty for the default rules
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Condition metric constants
+const (
+	ConditionMetricEngineTemp   = "engine_temperature"
+	ConditionMetricOilPressure  = "oil_pressure"
+	ConditionMetricBatteryLevel = "battery_voltage"
+	ConditionMetricEngineRPM    = "engine_rpm"
+)
+
+// Condition operator constants
+const (
+	ConditionOperatorAbove = "above"
+	ConditionOperatorBelow = "below"
+)
+
+// Condition engine state constants
+const (
+	ConditionEngineAny     = "any"
+	ConditionEngineRunning = "running"
+	ConditionEngineOff     = "off"
+)
+
+// Condition severity constants
+const (
+	ConditionSeverityWarning  = "warning"
+	ConditionSeverityCritical = "critical"
+)
+
+// Reading returns the rule's metric from a sample. Devices send zero for sensors they do
+// not report, so zero readings are treated as missing.
+func (r *ConditionRule) Reading(sample *TelematicsData) (float64, bool) {
+	var value float64
+	switch r.Metric {
+	case ConditionMetricEngineTemp:
+		value = sample.EngineTemp
+	case ConditionMetricOilPressure:
+		value = sample.OilPressure
+	case ConditionMetricBatteryLevel:
+		value = sample.BatteryLevel
+	case ConditionMetricEngineRPM:
+		value = float64(sample.EngineRPM)
+	}
+	return value, value != 0
+}
+
+// MatchesEngine reports whether the rule is evaluated for a sample with the given engine status
+func (r *ConditionRule) MatchesEngine(engineStatus string) bool {
+	switch r.EngineState {
+	case ConditionEngineRunning:
+		return engineStatus != "" && engineStatus != EngineStatusOff
+	case ConditionEngineOff:
+		return engineStatus == EngineStatusOff
+	}
+	return true
+}
+
+// Breached reports whether a reading is beyond the threshold
+func (r *ConditionRule) Breached(value float64) bool {
+	if r.Operator == ConditionOperatorBelow {
+		return value < r.Threshold
+	}
+	return value > r.Threshold
+}
+
+// Cleared reports whether a reading is back past the clear threshold
+func (r *ConditionRule) Cleared(value float64) bool {
+	if r.Operator == ConditionOperatorBelow {
+		return value >= r.ClearThreshold
+	}
+	return value <= r.ClearThreshold
+}
+
+// Worse returns the reading further beyond the threshold
+func (r *ConditionRule) Worse(a, b float64) float64 {
+	if (r.Operator == ConditionOperatorBelow) == (b < a) {
+		return b
+	}
+	return a
+}
+
+// ConditionVehicleState tracks a pending breach of a rule by a vehicle
+type ConditionVehicleState struct {
+	RuleID          uint       `json:"rule_id" gorm:"primaryKey;autoIncrement:false"`
+	VehicleID       uint       `json:"vehicle_id" gorm:"primaryKey;autoIncrement:false"`
+	BreachStartedAt *time.Time `json:"breach_started_at"` // nil when the condition does not hold
+	PeakValue       float64    `json:"peak_value"`
+	SampleAt        time.Time  `json:"sample_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ConditionAlert records a rule breached by a vehicle for the rule's duration. The threshold,
+// metric and severity are copied from the rule as it was when the alert was raised.
+type ConditionAlert struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	RuleID         *uint          `json:"rule_id"` // nil once the rule is deleted
+	Rule           *ConditionRule `json:"rule,omitempty" gorm:"foreignKey:RuleID"`
+	VehicleID      uint           `json:"vehicle_id" gorm:"not null"`
+	Name           string         `json:"name" gorm:"not null"`
+	Metric         string         `json:"metric" gorm:"not null"`
+	Operator       string         `json:"operator" gorm:"not null"`
+	Threshold      float64        `json:"threshold"`
+	Severity       string         `json:"severity" gorm:"not null"`
+	Status         string         `json:"status" gorm:"not null"` // active, cleared
+	StartedAt      time.Time      `json:"started_at"`             // first reading beyond the threshold
+	TriggeredAt    time.Time      `json:"triggered_at"`           // when the condition had held for the duration
+	TriggerValue   float64        `json:"trigger_value"`
+	PeakValue      float64        `json:"peak_value"`
+	ClearedAt      *time.Time     `json:"cleared_at"`
+	ClearValue     *float64       `json:"clear_value"`
+	AcknowledgedBy *uint          `json:"acknowledged_by"`
+	AcknowledgedAt *time.Time     `json:"acknowledged_at"`
+	Note           string         `json:"note"`
+	WorkOrderID    *uint          `json:"work_order_id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// ConditionAlertStatus constants
+const (
+	ConditionAlertActive  = "active"
+	ConditionAlertCleared = "cleared"
+)
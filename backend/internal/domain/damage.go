@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+// DamageReport represents a damage record on a vehicle, annotated on the vehicle diagram
+type DamageReport struct {
+	ID                uint       `json:"id" gorm:"primaryKey"`
+	VehicleID         uint       `json:"vehicle_id" gorm:"not null"`
+	Vehicle           *Vehicle   `json:"vehicle,omitempty" gorm:"foreignKey:VehicleID"`
+	InspectionID      *uint      `json:"inspection_id"` // inspection during which the damage was recorded
+	ZoneCode          string     `json:"zone_code" gorm:"not null"`
+	PositionX         *float64   `json:"position_x"` // relative position on the zone diagram (0-1)
+	PositionY         *float64   `json:"position_y"`
+	DamageType        string     `json:"damage_type" gorm:"not null"`
+	Severity          string     `json:"severity" gorm:"not null"`
+	Description       string     `json:"description"`
+	PhotoURLs         StringList `json:"photo_urls" gorm:"type:jsonb"`
+	Status            string     `json:"status" gorm:"not null"` // open, repaired, charged
+	EstimatedCost     float64    `json:"estimated_cost"`
+	ChargedAmount     float64    `json:"charged_amount"`
+	RepairWorkOrderID *uint      `json:"repair_work_order_id"`
+	InvoiceItemID     *uint      `json:"invoice_item_id"`
+	ReportedBy        uint       `json:"reported_by" gorm:"not null"`
+	Reporter          *User      `json:"reporter,omitempty" gorm:"foreignKey:ReportedBy"`
+	ReportedAt        time.Time  `json:"reported_at" gorm:"not null"`
+	ResolvedAt        *time.Time `json:"resolved_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// DamageStatus constants
+const (
+	DamageStatusOpen     = "open"
+	DamageStatusRepaired = "repaired"
+	DamageStatusCharged  = "charged"
+)
+
+// DamageType constants
+const (
+	DamageTypeScratch = "scratch"
+	DamageTypeDent    = "dent"
+	DamageTypeCrack   = "crack"
+	DamageTypeChip    = "chip"
+	DamageTypeBroken  = "broken"
+	DamageTypeMissing = "missing"
+	DamageTypeStain   = "stain"
+	DamageTypeOther   = "other"
+)
+
+// DamageSeverity constants
+const (
+	DamageSeverityMinor    = "minor"
+	DamageSeverityModerate = "moderate"
+	DamageSeveritySevere   = "severe"
+)
+
+// VehicleDiagramZones lists the zone codes of the vehicle diagram with their labels
+var VehicleDiagramZones = map[string]string{
+	"front_bumper":       "Front bumper",
+	"hood":               "Hood",
+	"windshield":         "Windshield",
+	"roof":               "Roof",
+	"rear_window":        "Rear window",
+	"trunk":              "Trunk / tailgate",
+	"rear_bumper":        "Rear bumper",
+	"left_front_fender":  "Left front fender",
+	"left_front_door":    "Left front door",
+	"left_rear_door":     "Left rear door",
+	"left_rear_quarter":  "Left rear quarter panel",
+	"left_mirror":        "Left mirror",
+	"right_front_fender": "Right front fender",
+	"right_front_door":   "Right front door",
+	"right_rear_door":    "Right rear door",
+	"right_rear_quarter": "Right rear quarter panel",
+	"right_mirror":       "Right mirror",
+	"left_headlight":     "Left headlight",
+	"right_headlight":    "Right headlight",
+	"left_taillight":     "Left taillight",
+	"right_taillight":    "Right taillight",
+	"left_front_wheel":   "Left front wheel",
+	"right_front_wheel":  "Right front wheel",
+	"left_rear_wheel":    "Left rear wheel",
+	"right_rear_wheel":   "Right rear wheel",
+	"interior":           "Interior",
+	"underbody":          "Underbody",
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// TelematicsDevice represents a physical telematics unit registered with the platform
+type TelematicsDevice struct {
+	ID                  uint           `json:"id" gorm:"primaryKey"`
+	Identifier          string         `json:"identifier" gorm:"uniqueIndex;not null"` // IMEI or serial reported by the unit
+	IMEI                string         `json:"imei" gorm:"column:imei"`
+	SerialNumber        string         `json:"serial_number"`
+	Manufacturer        string         `json:"manufacturer"`
+	Model               string         `json:"model"`
+	FirmwareVersion     string         `json:"firmware_version"`
+	Protocol            string         `json:"protocol" gorm:"not null"` // http, teltonika_codec8, gt06, mqtt
+	SIMICCID            string         `json:"sim_iccid" gorm:"column:sim_iccid"`
+	SIMPhoneNumber      string         `json:"sim_phone_number" gorm:"column:sim_phone_number"`
+	SIMCarrier          string         `json:"sim_carrier" gorm:"column:sim_carrier"`
+	Status              string         `json:"status" gorm:"not null"`    // active, inactive, maintenance, retired
+	AuthType            string         `json:"auth_type" gorm:"not null"` // token, hmac
+	CredentialHash      string         `json:"-"`
+	HMACSecret          string         `json:"-" gorm:"column:hmac_secret"`
+	CredentialRotatedAt *time.Time     `json:"credential_rotated_at"`
+	LastSeenAt          *time.Time     `json:"last_seen_at"`
+	Notes               string         `json:"notes"`
+	CurrentBinding      *DeviceBinding `json:"current_binding,omitempty" gorm:"-"`
+	CreatedBy           uint           `json:"created_by"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// DeviceBinding records the installation of a device in a vehicle over a period of time
+type DeviceBinding struct {
+	ID         uint              `json:"id" gorm:"primaryKey"`
+	DeviceID   uint              `json:"device_id" gorm:"not null"`
+	Device     *TelematicsDevice `json:"device,omitempty" gorm:"foreignKey:DeviceID"`
+	VehicleID  uint              `json:"vehicle_id" gorm:"not null"`
+	Vehicle    *Vehicle          `json:"vehicle,omitempty" gorm:"foreignKey:VehicleID"`
+	BoundFrom  time.Time         `json:"bound_from" gorm:"not null"`
+	BoundUntil *time.Time        `json:"bound_until"` // nil while the device is still installed
+	BoundBy    uint              `json:"bound_by"`
+	UnboundBy  *uint             `json:"unbound_by"`
+	Note       string            `json:"note"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Covers reports whether the binding was active at the given time
+func (b *DeviceBinding) Covers(t time.Time) bool {
+	if t.Before(b.BoundFrom) {
+		return false
+	}
+	return b.BoundUntil == nil || t.Before(*b.BoundUntil)
+}
+
+// DeviceStatus constants
+const (
+	DeviceStatusActive      = "active"
+	DeviceStatusInactive    = "inactive"
+	DeviceStatusMaintenance = "maintenance"
+	DeviceStatusRetired     = "retired"
+)
+
+// DeviceAuthType constants
+const (
+	DeviceAuthToken = "token" // static bearer token, stored hashed
+	DeviceAuthHMAC  = "hmac"  // request body signed with a shared key
+)
+
+// DeviceProtocol constants
+const (
+	DeviceProtocolHTTP            = "http"
+	DeviceProtocolTeltonikaCodec8 = "teltonika_codec8"
+	DeviceProtocolGT06            = "gt06"
+	DeviceProtocolMQTT            = "mqtt"
+)
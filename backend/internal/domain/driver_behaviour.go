@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// DriverBehaviourSettings holds the thresholds and score weights of driver behaviour scoring.
+// There is a single settings row.
+type DriverBehaviourSettings struct {
+	ID                uint          `json:"-" gorm:"primaryKey"`
+	DefaultSpeedLimit float64       `json:"default_speed_limit"`            // km/h
+	SpeedLimits       SpeedLimitMap `json:"speed_limits" gorm:"type:jsonb"` // vehicle type -> km/h
+	SpeedingTolerance float64       `json:"speeding_tolerance"`             // km/h above the limit before speeding counts
+	// Harsh events are detected from the speed change between consecutive samples
+	HarshAccelThreshold     float64 `json:"harsh_accel_threshold"`      // km/h gained per second
+	HarshBrakeThreshold     float64 `json:"harsh_brake_threshold"`      // km/h lost per second
+	HarshMaxIntervalSeconds int     `json:"harsh_max_interval_seconds"` // longer sample intervals are not judged
+	IdleThresholdSeconds    int     `json:"idle_threshold_seconds"`     // idling beyond this is excessive
+	NightStartHour          int     `json:"night_start_hour"`
+	NightEndHour            int     `json:"night_end_hour"`
+	Timezone                string  `json:"timezone"` // for night hours and week boundaries
+	// Score weights, see DriverBehaviourMetrics.Score
+	HarshAccelWeight         float64   `json:"harsh_accel_weight"`
+	HarshBrakeWeight         float64   `json:"harsh_brake_weight"`
+	SpeedingWeight           float64   `json:"speeding_weight"`
+	IdleWeight               float64   `json:"idle_weight"`
+	NightWeight              float64   `json:"night_weight"`
+	LeaderboardMinDistanceKm float64   `json:"leaderboard_min_distance_km"`
+	UpdatedBy                *uint     `json:"updated_by"`
+	UpdatedAt                time.Time `json:"updated_at"`
+}
+
+// TableName returns the driver behaviour settings table name
+func (DriverBehaviourSettings) TableName() string {
+	return "driver_behaviour_settings"
+}
+
+// SpeedLimitFor returns the speed limit of a vehicle type
+func (s *DriverBehaviourSettings) SpeedLimitFor(vehicleType string) float64 {
+	if limit, ok := s.SpeedLimits[vehicleType]; ok {
+		return limit
+	}
+	return s.DefaultSpeedLimit
+}
+
+// Location returns the settings timezone, UTC when it is invalid
+func (s *DriverBehaviourSettings) Location() *time.Location {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// IsNight reports whether a local time falls in the night hours, which may wrap past midnight
+func (s *DriverBehaviourSettings) IsNight(local time.Time) bool {
+	hour := local.Hour()
+	if s.NightStartHour <= s.NightEndHour {
+		return hour >= s.NightStartHour && hour < s.NightEndHour
+	}
+	return hour >= s.NightStartHour || hour < s.NightEndHour
+}
+
+// WeekStart returns the Monday of the week containing t in loc, as a date at midnight UTC
+func WeekStart(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	offset := (int(local.Weekday()) + 6) % 7
+	return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// SpeedLimitMap maps vehicle types to speed limits, persisted as a JSONB object
+type SpeedLimitMap map[string]float64
+
+// Value implements driver.Valuer
+func (m SpeedLimitMap) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]float64(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (m *SpeedLimitMap) Scan(value interface{}) error {
+	if value == nil {
+		*m = SpeedLimitMap{}
+		return nil
+	}
+	data, err := jsonBytes(value, "SpeedLimitMap")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, (*map[string]float64)(m))
+}
+
+// DriverBehaviourMetrics are the raw behaviour figures of a stretch of driving. They add up,
+// so weekly figures are sums of trip figures and scores are computed from the sums.
+type DriverBehaviourMetrics struct {
+	DistanceKm           float64 `json:"distance_km"`
+	DrivingSeconds       int     `json:"driving_seconds"`
+	HarshAccelerations   int     `json:"harsh_accelerations"`
+	HarshBrakings        int     `json:"harsh_brakings"`
+	SpeedingEvents       int     `json:"speeding_events"`
+	SpeedingSeconds      int     `json:"speeding_seconds"`
+	ExcessiveIdleSeconds int     `json:"excessive_idle_seconds"`
+	NightSeconds         int     `json:"night_seconds"`
+}
+
+// Add accumulates other into m
+func (m *DriverBehaviourMetrics) Add(other DriverBehaviourMetrics) {
+	m.DistanceKm += other.DistanceKm
+	m.DrivingSeconds += other.DrivingSeconds
+	m.HarshAccelerations += other.HarshAccelerations
+	m.HarshBrakings += other.HarshBrakings
+	m.SpeedingEvents += other.SpeedingEvents
+	m.SpeedingSeconds += other.SpeedingSeconds
+	m.ExcessiveIdleSeconds += other.ExcessiveIdleSeconds
+	m.NightSeconds += other.NightSeconds
+}
+
+// Score rates the metrics from 0 to 100. Each weight is the penalty for one harsh event per
+// 100 km, or for one percent of driving time spent speeding, idling excessively or at night.
+func (m DriverBehaviourMetrics) Score(settings *DriverBehaviourSettings) float64 {
+	// Short stretches would turn a single event into a huge rate
+	per100Km := 100 / math.Max(m.DistanceKm, 1)
+	penalty := settings.HarshAccelWeight*float64(m.HarshAccelerations)*per100Km +
+		settings.HarshBrakeWeight*float64(m.HarshBrakings)*per100Km
+	if m.DrivingSeconds > 0 {
+		driving := float64(m.DrivingSeconds)
+		penalty += settings.SpeedingWeight*float64(m.SpeedingSeconds)/driving*100 +
+			settings.NightWeight*float64(m.NightSeconds)/driving*100
+	}
+	if total := m.DrivingSeconds + m.ExcessiveIdleSeconds; total > 0 {
+		penalty += settings.IdleWeight * float64(m.ExcessiveIdleSeconds) / float64(total) * 100
+	}
+	score := math.Max(0, 100-penalty)
+	return math.Round(score*10) / 10
+}
+
+// DriverTripBehaviour is the behaviour of one driver during a trip. A trip spanning a change
+// of the vehicle's assignee has one row per driver; DriverID is empty when the assignee is
+// not a user.
+type DriverTripBehaviour struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TripID     uint      `json:"trip_id" gorm:"not null"`
+	VehicleID  uint      `json:"vehicle_id" gorm:"not null"`
+	DriverID   *uint     `json:"driver_id"`
+	AssignedTo string    `json:"assigned_to"` // Vehicle.AssignedTo at the time
+	StartTime  time.Time `json:"start_time" gorm:"not null"`
+	EndTime    time.Time `json:"end_time" gorm:"not null"`
+	WeekStart  time.Time `json:"week_start" gorm:"type:date;not null"`
+	DriverBehaviourMetrics
+	Score     float64   `json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// VehicleDriverAssignment is a period during which Vehicle.AssignedTo held a value.
+// The rows are written by a database trigger whenever the assignee changes.
+type VehicleDriverAssignment struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	VehicleID  uint       `json:"vehicle_id" gorm:"not null"`
+	AssignedTo string     `json:"assigned_to"`
+	DriverID   *uint      `json:"driver_id"` // user named by AssignedTo, by username or ID
+	StartedAt  time.Time  `json:"started_at" gorm:"not null"`
+	EndedAt    *time.Time `json:"ended_at"`
+}
+
+// Covers reports whether the assignment was current at the given time
+func (a *VehicleDriverAssignment) Covers(t time.Time) bool {
+	if t.Before(a.StartedAt) {
+		return false
+	}
+	return a.EndedAt == nil || t.Before(*a.EndedAt)
+}
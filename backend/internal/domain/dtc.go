@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// DTC severity constants
+const (
+	DTCSeverityInfo     = "info"
+	DTCSeverityWarning  = "warning"
+	DTCSeverityError    = "error"
+	DTCSeverityCritical = "critical"
+)
+
+// DTCSeverityRule classifies diagnostic trouble codes matching a pattern.
+// Patterns match a code exactly, or by prefix when they end in '*'.
+type DTCSeverityRule struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	Pattern          string    `json:"pattern" gorm:"not null"`
+	Severity         string    `json:"severity" gorm:"not null"`
+	WorkOrderType    string    `json:"work_order_type"` // emergency or repair, used for critical codes
+	RecipientUserIDs UintList  `json:"recipient_user_ids" gorm:"type:jsonb"`
+	Description      string    `json:"description"`
+	IsActive         bool      `json:"is_active"`
+	CreatedBy        *uint     `json:"created_by"` // empty for the default rules
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Matches reports whether the rule pattern matches a normalized code
+func (r *DTCSeverityRule) Matches(code string) bool {
+	if prefix, ok := strings.CutSuffix(r.Pattern, "*"); ok {
+		return strings.HasPrefix(code, prefix)
+	}
+	return r.Pattern == code
+}
+
+// Specificity ranks matching rules; exact patterns beat any prefix and longer prefixes beat shorter ones
+func (r *DTCSeverityRule) Specificity() int {
+	if prefix, ok := strings.CutSuffix(r.Pattern, "*"); ok {
+		return len(prefix)
+	}
+	return len(r.Pattern) + 1
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// WorkOrderEstimate is a version of the cost estimate of a work order. The customer approves or
+// declines its lines through the approval link and signs the decision.
+type WorkOrderEstimate struct {
+	ID                uint                    `json:"id" gorm:"primaryKey"`
+	WorkOrderID       uint                    `json:"work_order_id" gorm:"not null"`
+	Version           int                     `json:"version" gorm:"not null"`
+	Status            string                  `json:"status" gorm:"not null"`
+	Lines             []WorkOrderEstimateLine `json:"lines" gorm:"foreignKey:EstimateID"`
+	LaborSubtotal     float64                 `json:"labor_subtotal"`
+	PartsSubtotal     float64                 `json:"parts_subtotal"`
+	DiscountAmount    float64                 `json:"discount_amount"`
+	TaxRate           float64                 `json:"tax_rate"` // percent
+	TaxAmount         float64                 `json:"tax_amount"`
+	Total             float64                 `json:"total"`
+	ApprovedSubtotal  float64                 `json:"approved_subtotal"` // approved lines after discount, before tax
+	ApprovedTotal     float64                 `json:"approved_total"`
+	Notes             string                  `json:"notes"`
+	ApprovalTokenHash *string                 `json:"-"`
+	TokenExpiresAt    *time.Time              `json:"token_expires_at"`
+	SentAt            *time.Time              `json:"sent_at"`
+	SentBy            *uint                   `json:"sent_by"`
+	RespondedAt       *time.Time              `json:"responded_at"`
+	SignerName        string                  `json:"signer_name"`
+	Signature         string                  `json:"signature,omitempty"` // image data URL
+	SignerIP          string                  `json:"signer_ip,omitempty"`
+	SignerUserAgent   string                  `json:"signer_user_agent,omitempty"`
+	CustomerComment   string                  `json:"customer_comment"`
+	CreatedBy         uint                    `json:"created_by" gorm:"not null"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+// WorkOrderEstimateLine is a labor or part line of an estimate. Labor quantities are hours.
+type WorkOrderEstimateLine struct {
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	EstimateID      uint           `json:"estimate_id" gorm:"not null"`
+	LineType        string         `json:"line_type" gorm:"not null"`
+	OperationID     *uint          `json:"operation_id"` // catalogued labor operation of labor lines
+	Description     string         `json:"description" gorm:"not null"`
+	InventoryItemID *uint          `json:"inventory_item_id"`
+	InventoryItem   *InventoryItem `json:"inventory_item,omitempty" gorm:"foreignKey:InventoryItemID"`
+	Quantity        float64        `json:"quantity" gorm:"not null"`
+	UnitPrice       float64        `json:"unit_price" gorm:"not null"`
+	DiscountPercent float64        `json:"discount_percent"`
+	Amount          float64        `json:"amount"`   // after the line discount
+	Approved        *bool          `json:"approved"` // nil until the customer decides
+	SortOrder       int            `json:"sort_order"`
+}
+
+// WorkOrderEstimate status constants
+const (
+	EstimateStatusDraft             = "draft"
+	EstimateStatusSent              = "sent"
+	EstimateStatusApproved          = "approved"
+	EstimateStatusPartiallyApproved = "partially_approved"
+	EstimateStatusDeclined          = "declined"
+	EstimateStatusSuperseded        = "superseded" // a newer version replaced it before the customer answered
+)
+
+// WorkOrderEstimateLine type constants
+const (
+	EstimateLineLabor = "labor"
+	EstimateLinePart  = "part"
+)
+
+// TableName returns the work order estimate lines table name
+func (WorkOrderEstimateLine) TableName() string {
+	return "work_order_estimate_lines"
+}
+
+// IsApproved reports whether the customer approved at least part of the estimate
+func (e *WorkOrderEstimate) IsApproved() bool {
+	return e.Status == EstimateStatusApproved || e.Status == EstimateStatusPartiallyApproved
+}
+
+// Recalculate prices the lines and sets the subtotals, tax and total. The estimate discount is
+// taken off before tax, and the approved amounts carry their share of it.
+func (e *WorkOrderEstimate) Recalculate() {
+	e.LaborSubtotal, e.PartsSubtotal = 0, 0
+	approved := 0.0
+	for i := range e.Lines {
+		line := &e.Lines[i]
+		gross := line.Quantity * line.UnitPrice
+		line.Amount = roundCents(gross - gross*line.DiscountPercent/100)
+		if line.LineType == EstimateLineLabor {
+			e.LaborSubtotal += line.Amount
+		} else {
+			e.PartsSubtotal += line.Amount
+		}
+		if line.Approved != nil && *line.Approved {
+			approved += line.Amount
+		}
+	}
+	e.LaborSubtotal = roundCents(e.LaborSubtotal)
+	e.PartsSubtotal = roundCents(e.PartsSubtotal)
+
+	subtotal := e.LaborSubtotal + e.PartsSubtotal
+	e.DiscountAmount = math.Min(e.DiscountAmount, subtotal)
+	taxable := subtotal - e.DiscountAmount
+	e.TaxAmount = roundCents(taxable * e.TaxRate / 100)
+	e.Total = roundCents(taxable + e.TaxAmount)
+
+	e.ApprovedSubtotal, e.ApprovedTotal = 0, 0
+	if approved > 0 {
+		e.ApprovedSubtotal = roundCents(approved - e.DiscountAmount*approved/subtotal)
+		e.ApprovedTotal = roundCents(e.ApprovedSubtotal + e.ApprovedSubtotal*e.TaxRate/100)
+	}
+}
+
+// roundCents rounds an amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,127 @@
+package domain
+
+import "time"
+
+// VehicleFuelProfile holds the fuel tank data needed to turn fuel levels into litres
+type VehicleFuelProfile struct {
+	VehicleID          uint      `json:"vehicle_id" gorm:"primaryKey;autoIncrement:false"`
+	TankCapacityLiters float64   `json:"tank_capacity_liters" gorm:"not null"`
+	UpdatedBy          *uint     `json:"updated_by"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Liters converts a fuel level change in percent to litres
+func (p *VehicleFuelProfile) Liters(percent float64) *float64 {
+	if p == nil || p.TankCapacityLiters <= 0 {
+		return nil
+	}
+	liters := percent * p.TankCapacityLiters / 100
+	return &liters
+}
+
+// FuelEvent is a refuel or a sudden drop detected in a vehicle's fuel level series.
+// Events are derived data: they are re-detected when late samples arrive, keeping their IDs.
+type FuelEvent struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	VehicleID     uint      `json:"vehicle_id" gorm:"not null"`
+	Type          string    `json:"type" gorm:"not null"` // refuel, drop
+	StartedAt     time.Time `json:"started_at" gorm:"not null"`
+	EndedAt       time.Time `json:"ended_at" gorm:"not null"`
+	LevelBefore   float64   `json:"level_before"` // percent, filtered
+	LevelAfter    float64   `json:"level_after"`
+	ChangePercent float64   `json:"change_percent"` // always positive
+	Liters        *float64  `json:"liters"`         // empty without a fuel profile
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Overlaps reports whether two events of the same type describe the same change
+func (e *FuelEvent) Overlaps(other *FuelEvent) bool {
+	return e.Type == other.Type && !e.StartedAt.After(other.EndedAt) && !other.StartedAt.After(e.EndedAt)
+}
+
+// FuelEventType constants
+const (
+	FuelEventRefuel = "refuel"
+	FuelEventDrop   = "drop"
+)
+
+// FuelReceipt is a manually entered fuel purchase, reconciled against detected refuels
+type FuelReceipt struct {
+	ID                   uint       `json:"id" gorm:"primaryKey"`
+	VehicleID            uint       `json:"vehicle_id" gorm:"not null"`
+	Vehicle              *Vehicle   `json:"vehicle,omitempty" gorm:"foreignKey:VehicleID"`
+	FilledAt             time.Time  `json:"filled_at" gorm:"not null"`
+	Liters               float64    `json:"liters" gorm:"not null"`
+	TotalCost            float64    `json:"total_cost"`
+	Odometer             *int       `json:"odometer"`
+	Station              string     `json:"station"`
+	ReceiptNumber        string     `json:"receipt_number"`
+	Notes                string     `json:"notes"`
+	EnteredBy            uint       `json:"entered_by" gorm:"not null"`
+	FuelEventID          *uint      `json:"fuel_event_id"`   // matched refuel
+	DetectedLiters       *float64   `json:"detected_liters"` // litres of the matched refuel
+	ReconciliationStatus string     `json:"reconciliation_status" gorm:"not null"`
+	ReconciledAt         *time.Time `json:"reconciled_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// FuelReconciliationStatus constants
+const (
+	FuelReconciliationPending   = "pending"   // telematics does not cover the fill yet
+	FuelReconciliationMatched   = "matched"   // a refuel of about the same volume was detected
+	FuelReconciliationMismatch  = "mismatch"  // the detected refuel differs from the receipt
+	FuelReconciliationUnmatched = "unmatched" // no refuel was detected
+	FuelReconciliationNoData    = "no_data"   // the vehicle reported no fuel levels around the fill
+)
+
+// FuelAnomaly is a finding of the fuel analysis awaiting review
+type FuelAnomaly struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	VehicleID     uint       `json:"vehicle_id" gorm:"not null"`
+	Type          string     `json:"type" gorm:"not null"`
+	FuelEventID   *uint      `json:"fuel_event_id"`
+	FuelEvent     *FuelEvent `json:"fuel_event,omitempty" gorm:"foreignKey:FuelEventID"`
+	FuelReceiptID *uint      `json:"fuel_receipt_id"`
+	OccurredAt    time.Time  `json:"occurred_at" gorm:"not null"`
+	Liters        *float64   `json:"liters"` // volume lost or unaccounted for
+	Description   string     `json:"description"`
+	Status        string     `json:"status" gorm:"not null"`
+	ReviewedBy    *uint      `json:"reviewed_by"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+	ReviewNote    string     `json:"review_note"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// FuelAnomalyType constants
+const (
+	FuelAnomalySuddenDrop       = "sudden_drop"       // possible theft or leak
+	FuelAnomalyReceiptMismatch  = "receipt_mismatch"  // receipt volume differs from the detected refuel
+	FuelAnomalyUnmatchedReceipt = "unmatched_receipt" // no refuel detected for a receipt
+)
+
+// FuelAnomalyStatus constants
+const (
+	FuelAnomalyOpen      = "open"
+	FuelAnomalyConfirmed = "confirmed"
+	FuelAnomalyDismissed = "dismissed"
+)
+
+// TripFuelConsumption is the fuel used during a trip
+type TripFuelConsumption struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	TripID          uint      `json:"trip_id" gorm:"not null"`
+	VehicleID       uint      `json:"vehicle_id" gorm:"not null"`
+	StartTime       time.Time `json:"start_time" gorm:"not null"`
+	EndTime         time.Time `json:"end_time" gorm:"not null"`
+	DistanceKm      float64   `json:"distance_km"`
+	FuelUsedPercent float64   `json:"fuel_used_percent"`
+	FuelUsedLiters  *float64  `json:"fuel_used_liters"`                                // empty without a fuel profile
+	LitersPer100Km  *float64  `json:"liters_per_100km" gorm:"column:liters_per_100km"` // empty without a profile or distance
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,300 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"ton-platform/pkg/geo"
+)
+
+// Geofence is a circular or polygonal area that vehicle positions are evaluated against
+type Geofence struct {
+	ID                    uint                  `json:"id" gorm:"primaryKey"`
+	Name                  string                `json:"name" gorm:"not null"`
+	Description           string                `json:"description"`
+	Shape                 string                `json:"shape" gorm:"not null"` // circle, polygon
+	CenterLatitude        *float64              `json:"center_latitude,omitempty"`
+	CenterLongitude       *float64              `json:"center_longitude,omitempty"`
+	RadiusMeters          *float64              `json:"radius_meters,omitempty"`
+	Polygon               GeofencePolygon       `json:"polygon,omitempty" gorm:"type:jsonb"`
+	MinLatitude           float64               `json:"-"`
+	MinLongitude          float64               `json:"-"`
+	MaxLatitude           float64               `json:"-"`
+	MaxLongitude          float64               `json:"-"`
+	DwellThresholdMinutes *int                  `json:"dwell_threshold_minutes"`    // nil disables dwell events
+	Schedule              GeofenceSchedule      `json:"schedule" gorm:"type:jsonb"` // nil means always active
+	Timezone              string                `json:"timezone" gorm:"not null"`
+	IsActive              bool                  `json:"is_active"`
+	Assignments           []*GeofenceAssignment `json:"assignments" gorm:"foreignKey:GeofenceID"`
+	CreatedBy             uint                  `json:"created_by"`
+	CreatedAt             time.Time             `json:"created_at"`
+	UpdatedAt             time.Time             `json:"updated_at"`
+}
+
+// GeofenceShape constants
+const (
+	GeofenceShapeCircle  = "circle"
+	GeofenceShapePolygon = "polygon"
+)
+
+// UpdateBounds recalculates the bounding box from the geometry
+func (g *Geofence) UpdateBounds() {
+	switch g.Shape {
+	case GeofenceShapeCircle:
+		if g.CenterLatitude == nil || g.CenterLongitude == nil || g.RadiusMeters == nil {
+			return
+		}
+		// One degree of latitude is about 111.32 km; longitude degrees shrink with the cosine of the latitude
+		latDelta := *g.RadiusMeters / 111320
+		lonDelta := 180.0
+		if cos := cosDegrees(*g.CenterLatitude); cos > 1e-6 {
+			lonDelta = latDelta / cos
+		}
+		g.MinLatitude, g.MaxLatitude = *g.CenterLatitude-latDelta, *g.CenterLatitude+latDelta
+		g.MinLongitude, g.MaxLongitude = *g.CenterLongitude-lonDelta, *g.CenterLongitude+lonDelta
+	case GeofenceShapePolygon:
+		for i, p := range g.Polygon {
+			if i == 0 || p.Latitude < g.MinLatitude {
+				g.MinLatitude = p.Latitude
+			}
+			if i == 0 || p.Latitude > g.MaxLatitude {
+				g.MaxLatitude = p.Latitude
+			}
+			if i == 0 || p.Longitude < g.MinLongitude {
+				g.MinLongitude = p.Longitude
+			}
+			if i == 0 || p.Longitude > g.MaxLongitude {
+				g.MaxLongitude = p.Longitude
+			}
+		}
+	}
+}
+
+// Contains reports whether a position lies inside the geofence
+func (g *Geofence) Contains(latitude, longitude float64) bool {
+	if latitude < g.MinLatitude || latitude > g.MaxLatitude ||
+		longitude < g.MinLongitude || longitude > g.MaxLongitude {
+		return false
+	}
+
+	point := geo.Point{Latitude: latitude, Longitude: longitude}
+	switch g.Shape {
+	case GeofenceShapeCircle:
+		center := geo.Point{Latitude: *g.CenterLatitude, Longitude: *g.CenterLongitude}
+		return geo.DistanceMeters(center, point) <= *g.RadiusMeters
+	case GeofenceShapePolygon:
+		return geo.PolygonContains(g.Polygon, point)
+	}
+	return false
+}
+
+// AppliesTo reports whether the geofence is evaluated for a vehicle. Without assignments it applies to every vehicle.
+func (g *Geofence) AppliesTo(vehicleID uint, category string) bool {
+	if len(g.Assignments) == 0 {
+		return true
+	}
+	for _, assignment := range g.Assignments {
+		if assignment.VehicleID != nil && *assignment.VehicleID == vehicleID {
+			return true
+		}
+		if assignment.VehicleCategory != nil && *assignment.VehicleCategory == category {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActiveAt reports whether events are raised at t according to the schedule
+func (g *Geofence) IsActiveAt(t time.Time) bool {
+	if len(g.Schedule) == 0 {
+		return true
+	}
+	location, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := t.In(location)
+	for _, window := range g.Schedule {
+		if window.Contains(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// GeofenceAssignment attaches a geofence to a vehicle or to every vehicle of a category
+type GeofenceAssignment struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	GeofenceID      uint      `json:"geofence_id" gorm:"not null"`
+	VehicleID       *uint     `json:"vehicle_id,omitempty"`
+	VehicleCategory *string   `json:"vehicle_category,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// GeofenceVehicleState tracks whether a vehicle is inside a geofence
+type GeofenceVehicleState struct {
+	GeofenceID    uint       `json:"geofence_id" gorm:"primaryKey;autoIncrement:false"`
+	VehicleID     uint       `json:"vehicle_id" gorm:"primaryKey;autoIncrement:false"`
+	Inside        bool       `json:"inside"`
+	EnteredAt     *time.Time `json:"entered_at"`
+	DwellReported bool       `json:"dwell_reported"`
+	SampleAt      time.Time  `json:"sample_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// GeofenceEvent records a vehicle entering, leaving or dwelling in a geofence
+type GeofenceEvent struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	GeofenceID   uint      `json:"geofence_id" gorm:"not null"`
+	Geofence     *Geofence `json:"geofence,omitempty" gorm:"foreignKey:GeofenceID"`
+	VehicleID    uint      `json:"vehicle_id" gorm:"not null"`
+	EventType    string    `json:"event_type" gorm:"not null"` // enter, exit, dwell
+	OccurredAt   time.Time `json:"occurred_at" gorm:"not null"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	DwellSeconds *int      `json:"dwell_seconds,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GeofenceEventType constants
+const (
+	GeofenceEventEnter = "enter"
+	GeofenceEventExit  = "exit"
+	GeofenceEventDwell = "dwell"
+)
+
+// GeofenceAlertRule notifies users about events of a geofence
+type GeofenceAlertRule struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	GeofenceID       uint      `json:"geofence_id" gorm:"not null"`
+	EventType        string    `json:"event_type" gorm:"not null"`
+	RecipientUserIDs UintList  `json:"recipient_user_ids" gorm:"type:jsonb"`
+	IsActive         bool      `json:"is_active"`
+	CreatedBy        uint      `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// GeofencePolygon is the vertex list of a polygon geofence persisted as JSONB
+type GeofencePolygon []geo.Point
+
+// Value implements driver.Valuer
+func (p GeofencePolygon) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal([]geo.Point(p))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (p *GeofencePolygon) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+	data, err := jsonBytes(value, "GeofencePolygon")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, (*[]geo.Point)(p))
+}
+
+// GeofenceScheduleWindow is a weekly recurring period in the geofence's timezone.
+// A window ending before it starts runs past midnight; equal times cover the whole day.
+type GeofenceScheduleWindow struct {
+	Days  []int  `json:"days"`  // 0 = Sunday ... 6 = Saturday
+	Start string `json:"start"` // HH:MM
+	End   string `json:"end"`   // HH:MM
+}
+
+// Contains reports whether a local time falls inside the window
+func (w GeofenceScheduleWindow) Contains(local time.Time) bool {
+	start, err := ParseClock(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(w.End)
+	if err != nil {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	today := int(local.Weekday())
+	yesterday := (today + 6) % 7
+
+	switch {
+	case start == end:
+		return w.hasDay(today)
+	case start < end:
+		return w.hasDay(today) && minute >= start && minute < end
+	default:
+		return (w.hasDay(today) && minute >= start) || (w.hasDay(yesterday) && minute < end)
+	}
+}
+
+func (w GeofenceScheduleWindow) hasDay(day int) bool {
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseClock parses an HH:MM time of day into minutes after midnight
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// GeofenceSchedule is the list of active windows of a geofence persisted as JSONB
+type GeofenceSchedule []GeofenceScheduleWindow
+
+// Value implements driver.Valuer
+func (s GeofenceSchedule) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]GeofenceScheduleWindow(s))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (s *GeofenceSchedule) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	data, err := jsonBytes(value, "GeofenceSchedule")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, (*[]GeofenceScheduleWindow)(s))
+}
+
+func jsonBytes(value interface{}, target string) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("cannot scan %T into %s", value, target)
+	}
+}
+
+func cosDegrees(degrees float64) float64 {
+	return math.Cos(degrees * math.Pi / 180)
+}
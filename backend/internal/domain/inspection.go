@@ -0,0 +1,101 @@
+package domain
+
+import "time"
+
+// InspectionTemplate represents a versioned vehicle inspection checklist
+type InspectionTemplate struct {
+	ID          uint                `json:"id" gorm:"primaryKey"`
+	Code        string              `json:"code" gorm:"not null"` // stable identifier shared by all versions
+	Name        string              `json:"name" gorm:"not null"`
+	Description string              `json:"description"`
+	VehicleType string              `json:"vehicle_type"` // empty applies to all vehicle types
+	Version     int                 `json:"version" gorm:"not null"`
+	IsActive    bool                `json:"is_active" gorm:"default:true"`
+	Sections    []InspectionSection `json:"sections" gorm:"foreignKey:TemplateID"`
+	CreatedBy   uint                `json:"created_by" gorm:"not null"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// InspectionSection groups checklist items within a template
+type InspectionSection struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	TemplateID uint             `json:"template_id" gorm:"not null"`
+	Title      string           `json:"title" gorm:"not null"`
+	SortOrder  int              `json:"sort_order"`
+	Items      []InspectionItem `json:"items" gorm:"foreignKey:SectionID"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// InspectionItem represents a single checklist entry
+type InspectionItem struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	SectionID      uint      `json:"section_id" gorm:"not null"`
+	Code           string    `json:"code" gorm:"not null"`
+	Label          string    `json:"label" gorm:"not null"`
+	ItemType       string    `json:"item_type" gorm:"not null"` // pass_fail, value, text
+	Unit           string    `json:"unit"`
+	MinValue       *float64  `json:"min_value"`
+	MaxValue       *float64  `json:"max_value"`
+	IsRequired     bool      `json:"is_required"` // written as given; the service defaults omitted values to true
+	IsCritical     bool      `json:"is_critical" gorm:"default:false"`
+	PhotosRequired int       `json:"photos_required" gorm:"default:0"`
+	SortOrder      int       `json:"sort_order"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Inspection represents a submitted inspection of a vehicle
+type Inspection struct {
+	ID               uint                `json:"id" gorm:"primaryKey"`
+	InspectionNumber string              `json:"inspection_number" gorm:"uniqueIndex;not null"`
+	VehicleID        uint                `json:"vehicle_id" gorm:"not null"`
+	Vehicle          *Vehicle            `json:"vehicle,omitempty" gorm:"foreignKey:VehicleID"`
+	WorkOrderID      *uint               `json:"work_order_id"`
+	TemplateID       uint                `json:"template_id" gorm:"not null"`
+	Template         *InspectionTemplate `json:"template,omitempty" gorm:"foreignKey:TemplateID"`
+	InspectorID      uint                `json:"inspector_id" gorm:"not null"`
+	Inspector        *User               `json:"inspector,omitempty" gorm:"foreignKey:InspectorID"`
+	Odometer         int                 `json:"odometer"`
+	Outcome          string              `json:"outcome" gorm:"not null"` // passed, advisory, failed
+	Notes            string              `json:"notes"`
+	Results          []InspectionResult  `json:"results" gorm:"foreignKey:InspectionID"`
+	SubmittedAt      time.Time           `json:"submitted_at" gorm:"not null"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+}
+
+// InspectionResult represents the answer recorded for one checklist item
+type InspectionResult struct {
+	ID                uint            `json:"id" gorm:"primaryKey"`
+	InspectionID      uint            `json:"inspection_id" gorm:"not null"`
+	ItemID            uint            `json:"item_id" gorm:"not null"`
+	Item              *InspectionItem `json:"item,omitempty" gorm:"foreignKey:ItemID"`
+	Result            string          `json:"result" gorm:"not null"` // pass, fail, na
+	Value             *float64        `json:"value"`
+	Text              string          `json:"text"`
+	PhotoURLs         StringList      `json:"photo_urls" gorm:"type:jsonb"`
+	Notes             string          `json:"notes"`
+	DefectWorkOrderID *uint           `json:"defect_work_order_id"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+// InspectionItemType constants
+const (
+	InspectionItemTypePassFail = "pass_fail"
+	InspectionItemTypeValue    = "value"
+	InspectionItemTypeText     = "text"
+)
+
+// InspectionResult constants
+const (
+	InspectionResultPass = "pass"
+	InspectionResultFail = "fail"
+	InspectionResultNA   = "na"
+)
+
+// InspectionOutcome constants
+const (
+	InspectionOutcomePassed   = "passed"
+	InspectionOutcomeAdvisory = "advisory" // non-critical items failed
+	InspectionOutcomeFailed   = "failed"   // at least one critical item failed
+)
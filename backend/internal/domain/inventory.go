@@ -12,6 +12,7 @@ type Warehouse struct {
 	ManagerID   *uint            `json:"manager_id"`
 	Manager     *User            `json:"manager" gorm:"foreignKey:ManagerID"`
 	Capacity    int              `json:"capacity"`
+	AreaID      *uint            `json:"area_id"` // service area of a branch
 	IsActive    bool             `json:"is_active" gorm:"default:true"`
 	CreatedAt   time.Time        `json:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at"`
@@ -47,7 +48,7 @@ type InventoryStock struct {
 	Warehouse      Warehouse       `json:"warehouse" gorm:"foreignKey:WarehouseID"`
 	Quantity       int             `json:"quantity" gorm:"not null"`
 	ReservedQuantity int           `json:"reserved_quantity" gorm:"default:0"`
-	AvailableQuantity int          `json:"available_quantity" gorm:"not null"`
+	AvailableQuantity int          `json:"available_quantity" gorm:"->"`
 	LastCountDate  *time.Time      `json:"last_count_date"`
 	LastUpdated    time.Time       `json:"last_updated"`
 	CreatedAt      time.Time       `json:"created_at"`
@@ -62,8 +63,8 @@ InventoryItemID uint               `json:"inventory_item_id" gorm:"not null"`
 InventoryItem  InventoryItem        `json:"inventory_item" gorm:"foreignKey:InventoryItemID"`
 FromWarehouseID *uint               `json:"from_warehouse_id"`
 FromWarehouse   *Warehouse          `json:"from_warehouse" gorm:"foreignKey:FromWarehouseID"`
-ToWarehouseID   uint                `json:"to_warehouse_id" gorm:"not null"`
-ToWarehouse     Warehouse           `json:"to_warehouse" gorm:"foreignKey:ToWarehouseID"`
+ToWarehouseID   *uint               `json:"to_warehouse_id"` // nil when stock leaves, e.g. usage
+ToWarehouse     *Warehouse          `json:"to_warehouse" gorm:"foreignKey:ToWarehouseID"`
 TransactionType string              `json:"transaction_type" gorm:"not null"`
 Quantity        int                 `json:"quantity" gorm:"not null"`
 UnitPrice       float64             `json:"unit_price"`
@@ -79,6 +80,34 @@ CreatedAt       time.Time           `json:"created_at"`
 UpdatedAt       time.Time           `json:"updated_at"`
 }
 
+// StockTransferRequest asks the warehouse to move stock to a warehouse that is short, such as
+// a mechanic's small warehouse that cannot cover the parts of a work order
+type StockTransferRequest struct {
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	RequestNumber   string         `json:"request_number" gorm:"uniqueIndex;not null"`
+	InventoryItemID uint           `json:"inventory_item_id" gorm:"not null"`
+	InventoryItem   *InventoryItem `json:"inventory_item,omitempty" gorm:"foreignKey:InventoryItemID"`
+	FromWarehouseID *uint          `json:"from_warehouse_id"` // suggested source until fulfilled
+	ToWarehouseID   uint           `json:"to_warehouse_id" gorm:"not null"`
+	Quantity        int            `json:"quantity" gorm:"not null"`
+	Status          string         `json:"status" gorm:"not null"`
+	WorkOrderID     *uint          `json:"work_order_id"`
+	WorkOrderPartID *uint          `json:"work_order_part_id"`
+	RequestedBy     uint           `json:"requested_by" gorm:"not null"`
+	FulfilledBy     *uint          `json:"fulfilled_by"`
+	FulfilledAt     *time.Time     `json:"fulfilled_at"`
+	Note            string         `json:"note"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// StockTransferRequest status constants
+const (
+	TransferStatusRequested = "requested"
+	TransferStatusFulfilled = "fulfilled"
+	TransferStatusCancelled = "cancelled"
+)
+
 // WarehouseType constants
 const (
 	WarehouseTypeCentral  = "central"
@@ -12,7 +12,7 @@ type Invoice struct {
 	CustomerEmail   string          `json:"customer_email"`
 	CustomerPhone   string          `json:"customer_phone"`
 	CustomerAddress string          `json:"customer_address"`
-	Type            string          `json:"type" gorm:"not null"` // service, rental, parts, etc.
+	Type            string          `json:"type" gorm:"column:invoice_type;not null"` // service, rental, parts, etc.
 	Status          string          `json:"status" gorm:"not null"`
 	Subtotal        float64         `json:"subtotal"`
 	TaxAmount       float64         `json:"tax_amount"`
@@ -37,10 +37,11 @@ type InvoiceItem struct {
 	Description string   `json:"description" gorm:"not null"`
 	Quantity    int      `json:"quantity" gorm:"not null"`
 	UnitPrice   float64  `json:"unit_price" gorm:"not null"`
-	TotalPrice  float64  `json:"total_price" gorm:"not null"`
+	TotalPrice  float64  `json:"total_price" gorm:"column:total_with_tax;->"` // generated by the database
 	TaxRate     float64  `json:"tax_rate" gorm:"default:0"`
-	TaxAmount   float64  `json:"tax_amount"`
+	TaxAmount   float64  `json:"tax_amount" gorm:"->"` // generated by the database
 	ItemType    string   `json:"item_type" gorm:"not null"` // labor, parts, fees, etc.
+	ReferenceType string `json:"reference_type"` // work_order_part, damage_report, etc.
 	ReferenceID *uint    `json:"reference_id"` // Reference to work_order_part, inventory_item, etc.
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// WorkOrderTask is a piece of work on a work order that mechanics clock time against
+type WorkOrderTask struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	WorkOrderID    uint       `json:"work_order_id" gorm:"not null"`
+	OperationID    *uint      `json:"operation_id"`
+	Description    string     `json:"description" gorm:"not null"`
+	EstimatedHours float64    `json:"estimated_hours"`
+	StandardHours  *float64   `json:"standard_hours"` // flat-rate hours of the operation when the task was added
+	Status         string     `json:"status" gorm:"not null"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	CreatedBy      uint       `json:"created_by" gorm:"not null"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// LaborOperation is a catalogued labor operation with its flat-rate hours. Empty vehicle type and
+// make lists apply the operation to every vehicle.
+type LaborOperation struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	Code          string     `json:"code" gorm:"not null;uniqueIndex"`
+	Description   string     `json:"description" gorm:"not null"`
+	VehicleTypes  StringList `json:"vehicle_types" gorm:"type:jsonb"`
+	Makes         StringList `json:"makes" gorm:"type:jsonb"`
+	StandardHours float64    `json:"standard_hours" gorm:"not null"`
+	DefaultPrice  *float64   `json:"default_price"` // flat-rate price; nil charges the standard hours at the labor rate
+	IsActive      bool       `json:"is_active"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// AppliesTo reports whether the operation can be performed on a vehicle of the given type and make
+func (o *LaborOperation) AppliesTo(vehicleType, vehicleMake string) bool {
+	if len(o.VehicleTypes) > 0 && !o.VehicleTypes.Contains(vehicleType) {
+		return false
+	}
+	if len(o.Makes) == 0 {
+		return true
+	}
+	for _, m := range o.Makes {
+		if strings.EqualFold(m, vehicleMake) {
+			return true
+		}
+	}
+	return false
+}
+
+// HourlyPrice returns the hourly equivalent of the flat-rate price, or rate without one
+func (o *LaborOperation) HourlyPrice(rate float64) float64 {
+	if o.DefaultPrice == nil || o.StandardHours <= 0 {
+		return rate
+	}
+	return roundCents(*o.DefaultPrice / o.StandardHours)
+}
+
+// WorkOrderTask status constants
+const (
+	TaskStatusOpen      = "open"
+	TaskStatusCompleted = "completed"
+)
+
+// LaborTimeEntry is an uninterrupted period a mechanic worked on a task. The entry is
+// running while EndedAt is nil.
+type LaborTimeEntry struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	WorkOrderID uint       `json:"work_order_id" gorm:"not null"`
+	TaskID      uint       `json:"task_id" gorm:"not null"`
+	MechanicID  uint       `json:"mechanic_id" gorm:"not null"`
+	Mechanic    *User      `json:"mechanic,omitempty" gorm:"foreignKey:MechanicID"`
+	StartedAt   time.Time  `json:"started_at" gorm:"not null"`
+	EndedAt     *time.Time `json:"ended_at"`
+	EndReason   string     `json:"end_reason"`
+	Note        string     `json:"note"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// LaborTimeEntry end reason constants
+const (
+	ClockEndPaused     = "paused"
+	ClockEndClockedOut = "clocked_out"
+	ClockEndStopped    = "stopped" // the work order left in_progress while the clock was running
+)
+
+// Hours returns the duration of the entry in hours, counting a running entry up to now
+func (e *LaborTimeEntry) Hours(now time.Time) float64 {
+	end := now
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	return end.Sub(e.StartedAt).Hours()
+}
+
+// WorkOrderLabor is a labor charge on a work order. Lines with a task are rolled up from
+// the time clock per task, mechanic and day; lines without one are entered by hand.
+type WorkOrderLabor struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	WorkOrderID uint      `json:"work_order_id" gorm:"not null"`
+	TaskID      *uint     `json:"task_id"`
+	MechanicID  uint      `json:"mechanic_id" gorm:"not null"`
+	Mechanic    *User     `json:"mechanic,omitempty" gorm:"foreignKey:MechanicID"`
+	Description string    `json:"description" gorm:"not null"`
+	HoursWorked float64   `json:"hours_worked" gorm:"not null"`
+	HourlyRate  float64   `json:"hourly_rate" gorm:"not null"`
+	TotalCost   float64   `json:"total_cost" gorm:"->"` // generated by the database
+	WorkDate    time.Time `json:"work_date" gorm:"type:date;not null"`
+	Notes       string    `json:"notes"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName returns the work order labor table name
+func (WorkOrderLabor) TableName() string {
+	return "work_order_labor"
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// MaintenancePlan is preventive maintenance due after a distance, a time or engine hours,
+// whichever comes first. Empty vehicle type, make and model apply the plan to every vehicle.
+type MaintenancePlan struct {
+	ID                  uint      `json:"id" gorm:"primaryKey"`
+	Name                string    `json:"name" gorm:"not null"`
+	Description         string    `json:"description"`
+	VehicleType         *string   `json:"vehicle_type"`
+	Make                *string   `json:"make"`
+	Model               *string   `json:"model"`
+	IntervalKm          *int      `json:"interval_km"`
+	IntervalDays        *int      `json:"interval_days"`
+	IntervalEngineHours *float64  `json:"interval_engine_hours"`
+	LeadKm              int       `json:"lead_km"` // how far ahead of due the work order or reminder is raised
+	LeadDays            int       `json:"lead_days"`
+	LeadEngineHours     float64   `json:"lead_engine_hours"`
+	Action              string    `json:"action" gorm:"not null"` // work_order, reminder
+	OperationID         *uint     `json:"operation_id"`           // labor operation added as a task to opened work orders
+	Priority            string    `json:"priority" gorm:"not null"`
+	RecipientUserIDs    UintList  `json:"recipient_user_ids" gorm:"type:jsonb"`
+	IsActive            bool      `json:"is_active"`
+	CreatedBy           uint      `json:"created_by" gorm:"not null"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Maintenance plan action constants
+const (
+	MaintenanceActionWorkOrder = "work_order"
+	MaintenanceActionReminder  = "reminder"
+)
+
+// AppliesTo reports whether the plan covers a vehicle; make and model match case-insensitively
+func (p *MaintenancePlan) AppliesTo(vehicle *Vehicle) bool {
+	if p.VehicleType != nil && *p.VehicleType != vehicle.Type {
+		return false
+	}
+	if p.Make != nil && !strings.EqualFold(*p.Make, vehicle.Make) {
+		return false
+	}
+	return p.Model == nil || strings.EqualFold(*p.Model, vehicle.Model)
+}
+
+// VehicleMaintenanceSchedule tracks a maintenance plan on one vehicle from when it was last done
+type VehicleMaintenanceSchedule struct {
+	ID                  uint             `json:"id" gorm:"primaryKey"`
+	PlanID              uint             `json:"plan_id" gorm:"not null"`
+	Plan                *MaintenancePlan `json:"plan,omitempty" gorm:"foreignKey:PlanID"`
+	VehicleID           uint             `json:"vehicle_id" gorm:"not null"`
+	Vehicle             *Vehicle         `json:"vehicle,omitempty" gorm:"foreignKey:VehicleID"`
+	LastDoneAt          time.Time        `json:"last_done_at" gorm:"not null"`
+	LastDoneOdometer    int              `json:"last_done_odometer"`
+	LastDoneEngineHours float64          `json:"last_done_engine_hours"`
+	NextDueAt           *time.Time       `json:"next_due_at"`
+	NextDueOdometer     *int             `json:"next_due_odometer"`
+	NextDueEngineHours  *float64         `json:"next_due_engine_hours"`
+	CurrentOdometer     int              `json:"current_odometer"`
+	CurrentEngineHours  float64          `json:"current_engine_hours"`
+	Status              string           `json:"status" gorm:"not null"` // ok, due_soon, overdue
+	EvaluatedAt         *time.Time       `json:"evaluated_at"`
+	WorkOrderID         *uint            `json:"work_order_id"`   // open maintenance work order
+	NotifiedStatus      string           `json:"notified_status"` // last status the recipients were told about
+	IsActive            bool             `json:"is_active"`       // false once the vehicle no longer matches the plan
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
+
+// Maintenance schedule status constants, in increasing urgency
+const (
+	MaintenanceStatusOK      = "ok"
+	MaintenanceStatusDueSoon = "due_soon"
+	MaintenanceStatusOverdue = "overdue"
+)
+
+var maintenanceStatusRank = map[string]int{
+	MaintenanceStatusOK:      0,
+	MaintenanceStatusDueSoon: 1,
+	MaintenanceStatusOverdue: 2,
+}
+
+// MaintenanceStatusAbove reports whether status a is more urgent than status b
+func MaintenanceStatusAbove(a, b string) bool {
+	return maintenanceStatusRank[a] > maintenanceStatusRank[b]
+}
+
+// Baseline starts a schedule for a vehicle whose maintenance history is unknown. The time
+// baseline is the vehicle's last service, or now; the meter baselines are the readings
+// rounded down to the last whole interval, as if the plan had been followed since new.
+func (s *VehicleMaintenanceSchedule) Baseline(plan *MaintenancePlan, lastService time.Time, odometer int, engineHours float64, now time.Time) {
+	s.LastDoneAt = now
+	if !lastService.IsZero() && lastService.Before(now) {
+		s.LastDoneAt = lastService
+	}
+	s.LastDoneOdometer = odometer
+	if plan.IntervalKm != nil {
+		s.LastDoneOdometer = odometer - odometer%*plan.IntervalKm
+	}
+	s.LastDoneEngineHours = engineHours
+	if plan.IntervalEngineHours != nil {
+		s.LastDoneEngineHours = math.Floor(engineHours / *plan.IntervalEngineHours) * *plan.IntervalEngineHours
+	}
+}
+
+// Evaluate recalculates when the plan is next due from the current readings. The schedule is
+// overdue once any interval has passed and due soon once any is within the plan's lead.
+func (s *VehicleMaintenanceSchedule) Evaluate(plan *MaintenancePlan, odometer int, engineHours float64, now time.Time) {
+	s.CurrentOdometer = odometer
+	s.CurrentEngineHours = engineHours
+	s.NextDueAt, s.NextDueOdometer, s.NextDueEngineHours = nil, nil, nil
+	s.EvaluatedAt = &now
+
+	status := MaintenanceStatusOK
+	raise := func(overdue, dueSoon bool) {
+		switch {
+		case overdue:
+			status = MaintenanceStatusOverdue
+		case dueSoon && status == MaintenanceStatusOK:
+			status = MaintenanceStatusDueSoon
+		}
+	}
+	if plan.IntervalDays != nil {
+		due := s.LastDoneAt.AddDate(0, 0, *plan.IntervalDays)
+		s.NextDueAt = &due
+		raise(!now.Before(due), !now.Before(due.AddDate(0, 0, -plan.LeadDays)))
+	}
+	if plan.IntervalKm != nil {
+		due := s.LastDoneOdometer + *plan.IntervalKm
+		s.NextDueOdometer = &due
+		raise(odometer >= due, odometer >= due-plan.LeadKm)
+	}
+	if plan.IntervalEngineHours != nil {
+		due := s.LastDoneEngineHours + *plan.IntervalEngineHours
+		s.NextDueEngineHours = &due
+		raise(engineHours >= due, engineHours >= due-plan.LeadEngineHours)
+	}
+	s.Status = status
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// MechanicProfile holds what the workshop needs to know to assign work to a mechanic
+type MechanicProfile struct {
+	UserID             uint            `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	User               *User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	BranchID           *uint           `json:"branch_id"` // branch or workshop the mechanic works at
+	Branch             *Warehouse      `json:"branch,omitempty" gorm:"foreignKey:BranchID"`
+	WarehouseID        *uint           `json:"warehouse_id"` // small warehouse the mechanic draws parts from
+	DailyCapacityHours float64         `json:"daily_capacity_hours"`
+	HourlyRate         *float64        `json:"hourly_rate"`  // nil uses the workshop default labor rate
+	IsAvailable        bool            `json:"is_available"` // false while the mechanic cannot take new work
+	Notes              string          `json:"notes"`
+	Skills             []MechanicSkill `json:"skills" gorm:"foreignKey:UserID;references:UserID"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// MechanicSkill is a skill or certification of a mechanic. Empty service and vehicle types
+// cover every type.
+type MechanicSkill struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"not null"`
+	ServiceType    string     `json:"service_type"`
+	VehicleType    string     `json:"vehicle_type"`
+	Level          int        `json:"level"` // 1 basic, 2 experienced, 3 expert
+	Certification  string     `json:"certification"`
+	CertifiedUntil *time.Time `json:"certified_until"` // nil when the skill does not expire
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// MechanicSkill level constants
+const (
+	SkillLevelBasic       = 1
+	SkillLevelExperienced = 2
+	SkillLevelExpert      = 3
+)
+
+// Covers reports whether the skill applies to a service type on a vehicle type at a time
+func (s *MechanicSkill) Covers(serviceType, vehicleType string, at time.Time) bool {
+	if s.CertifiedUntil != nil && s.CertifiedUntil.Before(at) {
+		return false
+	}
+	return (s.ServiceType == "" || s.ServiceType == serviceType) &&
+		(s.VehicleType == "" || s.VehicleType == vehicleType)
+}
+
+// MechanicShift is a scheduled working period of a mechanic
+type MechanicShift struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null"`
+	StartsAt  time.Time `json:"starts_at" gorm:"not null"`
+	EndsAt    time.Time `json:"ends_at" gorm:"not null"`
+	Note      string    `json:"note"`
+	CreatedBy uint      `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Hours returns the part of the shift within [from, to) in hours
+func (s *MechanicShift) Hours(from, to time.Time) float64 {
+	start, end := s.StartsAt, s.EndsAt
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start).Hours()
+}
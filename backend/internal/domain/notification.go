@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Notification is an in-app message for a user
+type Notification struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	UserID        uint       `json:"user_id" gorm:"not null"`
+	Type          string     `json:"type" gorm:"not null"`
+	Title         string     `json:"title" gorm:"not null"`
+	Message       string     `json:"message"`
+	ReferenceType string     `json:"reference_type,omitempty"` // entity the notification is about
+	ReferenceID   *uint      `json:"reference_id,omitempty"`
+	ReadAt        *time.Time `json:"read_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
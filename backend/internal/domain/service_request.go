@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// ServiceArea groups vehicles and workshop branches under an Area Manager
+type ServiceArea struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null"`
+	Description string    `json:"description"`
+	ManagerID   *uint     `json:"manager_id"`
+	Manager     *User     `json:"manager,omitempty" gorm:"foreignKey:ManagerID"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ServiceRequest (PS) is raised by a driver or coordinator for a vehicle that needs attention.
+// An Area Manager approves it and allocates it to a workshop branch before it is converted
+// into a work order.
+type ServiceRequest struct {
+	ID                  uint         `json:"id" gorm:"primaryKey"`
+	RequestNumber       string       `json:"request_number" gorm:"uniqueIndex;not null"`
+	VehicleID           uint         `json:"vehicle_id" gorm:"not null"`
+	Vehicle             *Vehicle     `json:"vehicle,omitempty" gorm:"foreignKey:VehicleID"`
+	AreaID              *uint        `json:"area_id"` // area of the vehicle when raised, nil when it has none
+	Area                *ServiceArea `json:"area,omitempty" gorm:"foreignKey:AreaID"`
+	RequestedBy         uint         `json:"requested_by" gorm:"not null"`
+	Requester           *User        `json:"requester,omitempty" gorm:"foreignKey:RequestedBy"`
+	Symptoms            string       `json:"symptoms" gorm:"not null"`
+	PhotoURLs           StringList   `json:"photo_urls" gorm:"type:jsonb"`
+	Latitude            *float64     `json:"latitude"`
+	Longitude           *float64     `json:"longitude"`
+	LocationDescription string       `json:"location_description"`
+	Odometer            int          `json:"odometer"`
+	Urgency             string       `json:"urgency" gorm:"not null"` // low, normal, high, critical
+	Status              string       `json:"status" gorm:"not null"`
+	ReviewedBy          *uint        `json:"reviewed_by"`
+	ReviewedAt          *time.Time   `json:"reviewed_at"`
+	ReviewNote          string       `json:"review_note"`
+	BranchID            *uint        `json:"branch_id"` // workshop branch the request is allocated to
+	Branch              *Warehouse   `json:"branch,omitempty" gorm:"foreignKey:BranchID"`
+	AllocatedBy         *uint        `json:"allocated_by"`
+	AllocatedAt         *time.Time   `json:"allocated_at"`
+	WorkOrderID         *uint        `json:"work_order_id"`
+	WorkOrder           *WorkOrder   `json:"work_order,omitempty" gorm:"foreignKey:WorkOrderID"`
+	CreatedAt           time.Time    `json:"created_at"`
+	UpdatedAt           time.Time    `json:"updated_at"`
+}
+
+// ServiceRequestStatus constants
+const (
+	ServiceRequestSubmitted = "submitted"
+	ServiceRequestApproved  = "approved"
+	ServiceRequestRejected  = "rejected"
+	ServiceRequestAllocated = "allocated"
+	ServiceRequestConverted = "converted"
+	ServiceRequestCancelled = "cancelled"
+)
+
+// ServiceRequestUrgency constants
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyHigh     = "high"
+	UrgencyCritical = "critical"
+)
@@ -0,0 +1,93 @@
+package domain
+
+import "time"
+
+// Telematics resolutions
+const (
+	TelematicsResolutionRaw    = "raw"
+	TelematicsResolutionMinute = "1m"
+	TelematicsResolutionHour   = "1h"
+)
+
+// TelematicsAggregate summarizes the samples of a vehicle in a 1-minute or 1-hour bucket.
+// Raw samples are served in the same shape with a sample count of one.
+type TelematicsAggregate struct {
+	VehicleID       uint      `json:"vehicle_id" gorm:"primaryKey;autoIncrement:false"`
+	Bucket          time.Time `json:"bucket" gorm:"primaryKey"` // bucket start, or the sample timestamp
+	SampleCount     int       `json:"sample_count"`
+	Latitude        float64   `json:"latitude"` // last fix in the bucket
+	Longitude       float64   `json:"longitude"`
+	AvgSpeed        float64   `json:"avg_speed"` // km/h
+	MaxSpeed        float64   `json:"max_speed"`
+	EngineOnSamples int       `json:"engine_on_samples"`
+	FuelLevel       *float64  `json:"fuel_level"` // average of the samples reporting one
+	EngineTemp      *float64  `json:"engine_temp" gorm:"column:engine_temperature"`
+	MaxEngineTemp   *float64  `json:"max_engine_temp" gorm:"column:max_engine_temperature"`
+	OilPressure     *float64  `json:"oil_pressure"`
+	BatteryLevel    *float64  `json:"battery_level" gorm:"column:battery_voltage"`
+	MinBatteryLevel *float64  `json:"min_battery_level" gorm:"column:min_battery_voltage"`
+	EngineRPM       *int      `json:"engine_rpm" gorm:"column:engine_rpm"`
+	MaxEngineRPM    *int      `json:"max_engine_rpm" gorm:"column:max_engine_rpm"`
+	TotalDistance   *float64  `json:"total_distance"` // odometer at the end of the bucket
+	UpdatedAt       time.Time `json:"-"`
+}
+
+// NewRawTelematicsAggregate presents a raw sample as a single-sample bucket
+func NewRawTelematicsAggregate(sample *TelematicsData) *TelematicsAggregate {
+	engineOn := 0
+	if sample.EngineStatus != "" && sample.EngineStatus != EngineStatusOff {
+		engineOn = 1
+	}
+	point := &TelematicsAggregate{
+		VehicleID:       sample.VehicleID,
+		Bucket:          sample.Timestamp,
+		SampleCount:     1,
+		Latitude:        sample.Latitude,
+		Longitude:       sample.Longitude,
+		AvgSpeed:        sample.Speed,
+		MaxSpeed:        sample.Speed,
+		EngineOnSamples: engineOn,
+		EngineTemp:      &sample.EngineTemp,
+		MaxEngineTemp:   &sample.EngineTemp,
+		OilPressure:     &sample.OilPressure,
+		BatteryLevel:    &sample.BatteryLevel,
+		MinBatteryLevel: &sample.BatteryLevel,
+		EngineRPM:       &sample.EngineRPM,
+		MaxEngineRPM:    &sample.EngineRPM,
+		TotalDistance:   &sample.TotalDistance,
+	}
+	if sample.FuelLevel > 0 {
+		point.FuelLevel = &sample.FuelLevel
+	}
+	return point
+}
+
+// TelematicsCompactionTask is a vehicle hour whose raw samples changed since it was last aggregated
+type TelematicsCompactionTask struct {
+	VehicleID uint      `json:"vehicle_id" gorm:"primaryKey;autoIncrement:false"`
+	Hour      time.Time `json:"hour" gorm:"primaryKey"`
+	QueuedAt  time.Time `json:"queued_at"`
+}
+
+// TableName returns the compaction queue table name
+func (TelematicsCompactionTask) TableName() string {
+	return "telematics_compaction_queue"
+}
+
+// Reprocess queue consumers
+const (
+	ReprocessConsumerTrips = "trips"
+	ReprocessConsumerFuel  = "fuel"
+)
+
+// TelematicsReprocessMark queues a vehicle whose samples from Earliest on must be analysed again by a consumer
+type TelematicsReprocessMark struct {
+	Consumer  string    `json:"consumer" gorm:"primaryKey"`
+	VehicleID uint      `json:"vehicle_id" gorm:"primaryKey;autoIncrement:false"`
+	Earliest  time.Time `json:"earliest"`
+}
+
+// TableName returns the reprocess queue table name
+func (TelematicsReprocessMark) TableName() string {
+	return "telematics_reprocess_queue"
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Trip is a journey segmented from a vehicle's telematics samples
+type Trip struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	VehicleID       uint      `json:"vehicle_id" gorm:"not null"`
+	Status          string    `json:"status" gorm:"not null"` // in_progress, completed
+	StartTime       time.Time `json:"start_time" gorm:"not null"`
+	EndTime         time.Time `json:"end_time" gorm:"not null"`
+	StartLatitude   float64   `json:"start_latitude"`
+	StartLongitude  float64   `json:"start_longitude"`
+	StartPlace      string    `json:"start_place,omitempty"`
+	EndLatitude     float64   `json:"end_latitude"`
+	EndLongitude    float64   `json:"end_longitude"`
+	EndPlace        string    `json:"end_place,omitempty"`
+	DistanceKm      float64   `json:"distance_km"`
+	DurationSeconds int       `json:"duration_seconds"`
+	MovingSeconds   int       `json:"moving_seconds"`
+	IdleSeconds     int       `json:"idle_seconds"`
+	MaxSpeed        float64   `json:"max_speed"`
+	AvgSpeed        float64   `json:"avg_speed"`
+	FuelUsedPercent *float64  `json:"fuel_used_percent"`
+	SampleCount     int       `json:"sample_count"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TripStatus constants
+const (
+	TripStatusInProgress = "in_progress"
+	TripStatusCompleted  = "completed"
+)
+
+// RoutePoint is a point of a rendered route
+type RoutePoint struct {
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Timestamp time.Time `json:"timestamp"`
+	Speed     float64   `json:"speed"`
+}
@@ -40,6 +40,7 @@ type Permission struct {
 
 // UserRoles constants
 const (
+	RoleAdministrator  = "Administrator"
 	RoleMechanic       = "Mechanic"
 	RoleServiceAdvisor = "Service Advisor"
 	RoleWarehouseStaff = "Warehouse Staff"
@@ -15,10 +15,11 @@ type Vehicle struct {
 	Category     string    `json:"category" gorm:"not null"` // rental, workshop, customer
 	Status       string    `json:"status" gorm:"not null"` // available, rented, in_maintenance, out_of_service
 	Odometer     int       `json:"odometer"`
-	LastService  time.Time `json:"last_service"`
-	NextService  time.Time `json:"next_service"`
+	LastService  time.Time `json:"last_service" gorm:"column:last_service_date"`
+	NextService  time.Time `json:"next_service" gorm:"column:next_service_date"`
 	Location     string    `json:"location"`
 	AssignedTo   string    `json:"assigned_to"` // driver, mechanic, etc.
+	AreaID       *uint     `json:"area_id"`     // service area the vehicle belongs to
 	Notes        string    `json:"notes"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
@@ -53,23 +54,39 @@ const (
 
 // TelematicsData represents real-time vehicle telematics information
 type TelematicsData struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	VehicleID    uint      `json:"vehicle_id" gorm:"not null"`
-	Vehicle      Vehicle   `json:"vehicle" gorm:"foreignKey:VehicleID"`
-	Latitude     float64   `json:"latitude"`
-	Longitude    float64   `json:"longitude"`
-	Speed        float64   `json:"speed"`
-	Heading      float64   `json:"heading"`
-	Altitude     float64   `json:"altitude"`
-	EngineStatus string    `json:"engine_status"`
-	FuelLevel    float64   `json:"fuel_level"`
-	EngineTemp   float64   `json:"engine_temp"`
-	OilPressure  float64   `json:"oil_pressure"`
-	BatteryLevel float64   `json:"battery_level"`
-	Timestamp    time.Time `json:"timestamp"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	VehicleID       uint      `json:"vehicle_id" gorm:"not null"`
+	Vehicle         *Vehicle  `json:"vehicle,omitempty" gorm:"foreignKey:VehicleID"`
+	DeviceID        string    `json:"device_id"` // reporting unit, unique together with Timestamp
+	Latitude        float64   `json:"latitude"`
+	Longitude       float64   `json:"longitude"`
+	Speed           float64   `json:"speed"`   // km/h
+	Heading         float64   `json:"heading"` // degrees
+	Altitude        float64   `json:"altitude"`
+	EngineStatus    string    `json:"engine_status"` // on, off, idle
+	FuelLevel       float64   `json:"fuel_level"`    // percentage
+	EngineTemp      float64   `json:"engine_temp" gorm:"column:engine_temperature"`
+	OilPressure     float64   `json:"oil_pressure"`
+	BatteryLevel    float64   `json:"battery_level" gorm:"column:battery_voltage"` // volts
+	EngineRPM       int       `json:"engine_rpm" gorm:"column:engine_rpm"`
+	TotalDistance   float64   `json:"total_distance"`   // km
+	FuelConsumption float64   `json:"fuel_consumption"` // L/100km
+	Timestamp       time.Time `json:"timestamp"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TableName returns the telematics table name
+func (TelematicsData) TableName() string {
+	return "telematics_data"
 }
 
+// EngineStatus constants
+const (
+	EngineStatusOn   = "on"
+	EngineStatusOff  = "off"
+	EngineStatusIdle = "idle"
+)
+
 // DTCCode represents a Diagnostic Trouble Code
 type DTCCode struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
@@ -82,6 +99,8 @@ type DTCCode struct {
 	FirstSeen   time.Time `json:"first_seen"`
 	LastSeen    time.Time `json:"last_seen"`
 	ResolvedAt  *time.Time `json:"resolved_at"`
+	ResolutionNote string  `json:"resolution_note"`
+	WorkOrderID *uint     `json:"work_order_id"` // work order opened for a critical code
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
\ No newline at end of file
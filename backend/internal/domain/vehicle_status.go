@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// VehicleLatestState is the most recent telematics state of a vehicle, projected on ingest
+type VehicleLatestState struct {
+	VehicleID     uint      `json:"vehicle_id" gorm:"primaryKey;autoIncrement:false"`
+	DeviceID      string    `json:"device_id"`
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	Speed         float64   `json:"speed"`
+	Heading       float64   `json:"heading"`
+	EngineStatus  string    `json:"engine_status"`
+	FuelLevel     float64   `json:"fuel_level"`
+	BatteryLevel  float64   `json:"battery_level" gorm:"column:battery_voltage"`
+	EngineRPM     int       `json:"engine_rpm" gorm:"column:engine_rpm"`
+	TotalDistance float64   `json:"total_distance"`
+	SampleAt      time.Time `json:"sample_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for VehicleLatestState
+func (VehicleLatestState) TableName() string {
+	return "vehicle_latest_states"
+}
+
+// NewVehicleLatestState takes the projected fields from a telematics sample
+func NewVehicleLatestState(sample *TelematicsData) *VehicleLatestState {
+	return &VehicleLatestState{
+		VehicleID:     sample.VehicleID,
+		DeviceID:      sample.DeviceID,
+		Latitude:      sample.Latitude,
+		Longitude:     sample.Longitude,
+		Speed:         sample.Speed,
+		Heading:       sample.Heading,
+		EngineStatus:  sample.EngineStatus,
+		FuelLevel:     sample.FuelLevel,
+		BatteryLevel:  sample.BatteryLevel,
+		EngineRPM:     sample.EngineRPM,
+		TotalDistance: sample.TotalDistance,
+		SampleAt:      sample.Timestamp,
+	}
+}
+
+// VehicleRealtimeStatus is the live status of a vehicle as served to clients
+type VehicleRealtimeStatus struct {
+	VehicleID        uint       `json:"vehicle_id"`
+	PlateNumber      string     `json:"plate_number,omitempty"`
+	HasTelematics    bool       `json:"has_telematics"`
+	Latitude         float64    `json:"latitude"`
+	Longitude        float64    `json:"longitude"`
+	Speed            float64    `json:"speed"`
+	Heading          float64    `json:"heading"`
+	Ignition         bool       `json:"ignition"`
+	EngineStatus     string     `json:"engine_status,omitempty"`
+	FuelLevel        float64    `json:"fuel_level"`
+	BatteryLevel     float64    `json:"battery_level"`
+	ActiveDTCCount   int        `json:"active_dtc_count"`
+	LastSeenAt       *time.Time `json:"last_seen_at"`
+	StalenessSeconds int64      `json:"staleness_seconds"`
+	Online           bool       `json:"online"`
+}
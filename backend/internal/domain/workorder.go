@@ -15,6 +15,8 @@ type WorkOrder struct {
 	Priority        string          `json:"priority" gorm:"not null"`
 	Status          string          `json:"status" gorm:"not null"`
 	Description     string          `json:"description" gorm:"not null"`
+	Symptoms        string          `json:"symptoms"`
+	Diagnosis       string          `json:"diagnosis"`
 	AssignedMechanicID *uint        `json:"assigned_mechanic_id"`
 	AssignedMechanic *User          `json:"assigned_mechanic" gorm:"foreignKey:AssignedMechanicID"`
 	ServiceAdvisorID uint           `json:"service_advisor_id" gorm:"not null"`
@@ -27,25 +29,60 @@ type WorkOrder struct {
 	CompletionDate  *time.Time      `json:"completion_date"`
 	PartsUsed       []WorkOrderPart `json:"parts_used" gorm:"foreignKey:WorkOrderID"`
 	Notes           string          `json:"notes"`
+	ServiceRequestID *uint          `json:"service_request_id"` // service request the work order was converted from
+	BranchID        *uint           `json:"branch_id"`          // workshop branch carrying out the work
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
 }
 
-// WorkOrderPart represents parts used in a work order
+// WorkOrderStatusHistory records a status change of a work order with the user who made it
+type WorkOrderStatusHistory struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	WorkOrderID   uint      `json:"work_order_id" gorm:"not null"`
+	OldStatus     *string   `json:"old_status"` // nil for the entry written on creation
+	NewStatus     string    `json:"new_status" gorm:"not null"`
+	ChangedBy     uint      `json:"changed_by" gorm:"not null"`
+	ChangedByUser *User     `json:"changed_by_user,omitempty" gorm:"foreignKey:ChangedBy"`
+	Notes         string    `json:"notes"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+// TableName returns the work order status history table name
+func (WorkOrderStatusHistory) TableName() string {
+	return "work_order_status_history"
+}
+
+// WorkOrderPart is a part needed on a work order. The part is reserved at its warehouse
+// when added and taken out of stock when issued.
 type WorkOrderPart struct {
-	ID           uint        `json:"id" gorm:"primaryKey"`
-	WorkOrderID  uint        `json:"work_order_id" gorm:"not null"`
-	WorkOrder    WorkOrder   `json:"work_order" gorm:"foreignKey:WorkOrderID"`
-	PartID       uint        `json:"part_id" gorm:"not null"`
-	Part         InventoryItem `json:"part" gorm:"foreignKey:PartID"`
-	Quantity     int         `json:"quantity" gorm:"not null"`
-	UnitPrice    float64     `json:"unit_price" gorm:"not null"`
-	TotalPrice   float64     `json:"total_price" gorm:"not null"`
-	UsedAt       time.Time   `json:"used_at"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	WorkOrderID      uint           `json:"work_order_id" gorm:"not null"`
+	InventoryItemID  uint           `json:"inventory_item_id" gorm:"not null"`
+	InventoryItem    *InventoryItem `json:"inventory_item,omitempty" gorm:"foreignKey:InventoryItemID"`
+	WarehouseID      uint           `json:"warehouse_id" gorm:"not null"`
+	Warehouse        *Warehouse     `json:"warehouse,omitempty" gorm:"foreignKey:WarehouseID"`
+	Quantity         int            `json:"quantity" gorm:"not null"`
+	ReservedQuantity int            `json:"reserved_quantity"` // held at the warehouse; below quantity while short
+	UnitPrice        float64        `json:"unit_price" gorm:"not null"`
+	TotalPrice       float64        `json:"total_price" gorm:"->"`
+	Status           string         `json:"status" gorm:"not null"`
+	UsedAt           *time.Time     `json:"used_at"` // when the part was issued
+	UsedBy           uint           `json:"used_by" gorm:"not null"`
+	RemovedAt        *time.Time     `json:"removed_at"`
+	Notes            string         `json:"notes"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
 }
 
+// WorkOrderPart status constants
+const (
+	PartStatusReserved = "reserved" // the full quantity is held at the warehouse
+	PartStatusShort    = "short"    // waiting for a stock transfer to cover the rest
+	PartStatusIssued   = "issued"
+	PartStatusReleased = "released" // removed before it was issued
+	PartStatusReturned = "returned" // removed after it was issued and put back into stock
+)
+
 // WorkOrderStatus constants
 const (
 	StatusPending     = "pending"
@@ -0,0 +1,58 @@
+// Package gateway receives telematics data from trackers over raw TCP and MQTT,
+// decodes it with the protocol package and feeds it into the ingestion pipeline.
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+)
+
+// Gateway errors
+var (
+	ErrDeviceNotAllowed = errors.New("device is not allowed to connect")
+)
+
+// Sink receives normalized samples. It is implemented by service.TelematicsIngestService.
+type Sink interface {
+	IngestSamples(samples []*domain.TelematicsData) (*service.IngestResult, error)
+}
+
+// DeviceAuthorizer decides whether a device identified by a transport may send data
+// using the given protocol. Trackers on raw TCP cannot present secrets, so the device
+// registry is the only admission check.
+type DeviceAuthorizer struct {
+	deviceRepo interfaces.DeviceRepository
+}
+
+// NewDeviceAuthorizer creates a new device authorizer
+func NewDeviceAuthorizer(deviceRepo interfaces.DeviceRepository) *DeviceAuthorizer {
+	return &DeviceAuthorizer{deviceRepo: deviceRepo}
+}
+
+// Authorize checks that identifier is a registered, active device configured for protocol
+func (a *DeviceAuthorizer) Authorize(identifier, protocol string) (*domain.TelematicsDevice, error) {
+	device, err := a.deviceRepo.GetByIdentifier(identifier)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("%w: unknown device %s", ErrDeviceNotAllowed, identifier)
+		}
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+	if device.Status != domain.DeviceStatusActive {
+		return nil, fmt.Errorf("%w: device %s is %s", ErrDeviceNotAllowed, identifier, device.Status)
+	}
+	if device.Protocol != protocol {
+		return nil, fmt.Errorf("%w: device %s is configured for %s", ErrDeviceNotAllowed, identifier, device.Protocol)
+	}
+	return device, nil
+}
+
+// isNotFound reports whether a repository error means the record does not exist
+func isNotFound(err error) bool {
+	return err != nil && strings.HasSuffix(err.Error(), "not found")
+}
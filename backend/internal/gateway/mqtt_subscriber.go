@@ -0,0 +1,139 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/gateway/protocol"
+)
+
+const mqttQoS = 1
+
+// MQTTConfig configures the MQTT subscriber
+type MQTTConfig struct {
+	BrokerURL string
+	ClientID  string
+	Username  string
+	Password  string
+	// Topic must contain exactly one "+" wildcard at the level holding the device identifier,
+	// e.g. "ton/telematics/+/data"
+	Topic string
+	// PayloadFormat names the protocol decoder used for message payloads
+	PayloadFormat string
+}
+
+// MQTTSubscriber ingests telematics messages published to an MQTT broker.
+// Device authentication is enforced by the broker; the pipeline still rejects
+// identifiers that are not registered and active.
+type MQTTSubscriber struct {
+	config        MQTTConfig
+	decoder       protocol.FrameDecoder
+	identifierPos int
+	sink          Sink
+	recorder      *Recorder
+	logger        *logrus.Logger
+	client        mqtt.Client
+}
+
+// NewMQTTSubscriber creates a new MQTT subscriber. recorder may be nil.
+func NewMQTTSubscriber(config MQTTConfig, sink Sink, recorder *Recorder, logger *logrus.Logger) (*MQTTSubscriber, error) {
+	decoder, err := protocol.Lookup(config.PayloadFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	identifierPos := -1
+	for i, level := range strings.Split(config.Topic, "/") {
+		if level == "+" {
+			if identifierPos >= 0 {
+				return nil, fmt.Errorf("MQTT topic %q must contain a single + wildcard", config.Topic)
+			}
+			identifierPos = i
+		}
+		if level == "#" {
+			return nil, fmt.Errorf("MQTT topic %q must not contain the # wildcard", config.Topic)
+		}
+	}
+	if identifierPos < 0 {
+		return nil, fmt.Errorf("MQTT topic %q must contain a + wildcard for the device identifier", config.Topic)
+	}
+
+	return &MQTTSubscriber{
+		config:        config,
+		decoder:       decoder,
+		identifierPos: identifierPos,
+		sink:          sink,
+		recorder:      recorder,
+		logger:        logger,
+	}, nil
+}
+
+// Start connects to the broker and subscribes; subscriptions are restored after reconnects
+func (s *MQTTSubscriber) Start() error {
+	opts := mqtt.NewClientOptions().
+		AddBroker(s.config.BrokerURL).
+		SetClientID(s.config.ClientID).
+		SetUsername(s.config.Username).
+		SetPassword(s.config.Password).
+		// Persistent session so QoS 1 messages queued while disconnected are delivered
+		SetCleanSession(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetOrderMatters(false).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			s.logger.WithError(err).Warn("MQTT connection lost")
+		}).
+		SetOnConnectHandler(func(client mqtt.Client) {
+			token := client.Subscribe(s.config.Topic, mqttQoS, s.handleMessage)
+			if token.Wait() && token.Error() != nil {
+				s.logger.WithError(token.Error()).Error("Failed to subscribe to MQTT topic")
+				return
+			}
+			s.logger.WithField("topic", s.config.Topic).Info("MQTT subscriber connected")
+		})
+
+	s.client = mqtt.NewClient(opts)
+	token := s.client.Connect()
+	if token.WaitTimeout(30*time.Second) && token.Error() != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", token.Error())
+	}
+	return nil
+}
+
+// Close disconnects from the broker, letting in-flight handlers finish
+func (s *MQTTSubscriber) Close() {
+	if s.client != nil {
+		s.client.Disconnect(uint(5 * time.Second / time.Millisecond))
+	}
+}
+
+// handleMessage decodes and ingests one MQTT message. The message is acknowledged to the
+// broker when the handler returns.
+func (s *MQTTSubscriber) handleMessage(_ mqtt.Client, msg mqtt.Message) {
+	levels := strings.Split(msg.Topic(), "/")
+	if s.identifierPos >= len(levels) || levels[s.identifierPos] == "" {
+		s.logger.WithField("topic", msg.Topic()).Warn("MQTT message without device identifier")
+		return
+	}
+	identifier := levels[s.identifierPos]
+	log := s.logger.WithFields(logrus.Fields{
+		"protocol": s.decoder.Name(),
+		"device":   identifier,
+	})
+
+	if s.recorder != nil {
+		s.recorder.Record(s.decoder.Name(), identifier, msg.Payload())
+	}
+
+	samples, err := s.decoder.DecodeFrame(msg.Payload())
+	if err != nil {
+		log.WithError(err).Warn("Failed to decode MQTT telematics message")
+		return
+	}
+	ingestDecoded(log, s.sink, identifier, samples)
+}
@@ -0,0 +1,51 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"ton-platform/internal/domain"
+)
+
+// JSON decodes payloads in the format of the HTTP ingestion API, either
+// {"samples": [...]} or a single sample object. It is used for MQTT messages.
+type JSON struct{}
+
+// Name returns the protocol name
+func (JSON) Name() string {
+	return "json"
+}
+
+// DecodeFrame decodes a JSON payload
+func (JSON) DecodeFrame(frame []byte) ([]*domain.TelematicsData, error) {
+	frame = bytes.TrimSpace(frame)
+
+	var batch struct {
+		Samples []*domain.TelematicsData `json:"samples"`
+	}
+	if err := json.Unmarshal(frame, &batch); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFrame, err)
+	}
+
+	samples := batch.Samples
+	if samples == nil {
+		var single domain.TelematicsData
+		if err := json.Unmarshal(frame, &single); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFrame, err)
+		}
+		samples = []*domain.TelematicsData{&single}
+	}
+
+	// Routing is derived from the transport, never from the payload
+	for _, sample := range samples {
+		if sample == nil {
+			continue
+		}
+		sample.ID = 0
+		sample.VehicleID = 0
+		sample.Vehicle = nil
+		sample.DeviceID = ""
+	}
+	return samples, nil
+}
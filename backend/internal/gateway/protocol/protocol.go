@@ -0,0 +1,93 @@
+// Package protocol defines the decoders used by the telematics gateway to turn
+// tracker frames into normalized telematics samples.
+package protocol
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"ton-platform/internal/domain"
+)
+
+// Protocol errors
+var (
+	ErrUnknownProtocol = errors.New("unknown protocol")
+	ErrInvalidFrame    = errors.New("invalid frame")
+)
+
+// FrameDecoder converts one complete frame into normalized samples.
+// Decoders do not know the device; callers set DeviceID on the returned samples.
+type FrameDecoder interface {
+	Name() string
+	DecodeFrame(frame []byte) ([]*domain.TelematicsData, error)
+}
+
+// StreamProtocol is a FrameDecoder for trackers that keep a long-lived TCP connection open
+type StreamProtocol interface {
+	FrameDecoder
+
+	// Handshake reads the login message of a new connection and returns the device identifier
+	Handshake(r *bufio.Reader) (string, error)
+	// Accept answers the login message, accepting or refusing the device
+	Accept(w io.Writer, accepted bool) error
+	// ReadFrame reads the next complete frame from the connection
+	ReadFrame(r *bufio.Reader) ([]byte, error)
+	// Acknowledge confirms how many records of the last frame were processed
+	Acknowledge(w io.Writer, frame []byte, records int) error
+}
+
+// ReplayClient is implemented by stream protocols that can act as a simulated device
+// when replaying recorded frames
+type ReplayClient interface {
+	// Login sends the login message for identifier and waits for the server answer
+	Login(rw *bufio.ReadWriter, identifier string) error
+	// ReadAck reads the server acknowledgement of a sent frame
+	ReadAck(r *bufio.Reader) (int, error)
+}
+
+var decoders = map[string]FrameDecoder{}
+
+// Register makes a decoder available by name
+func Register(decoder FrameDecoder) {
+	decoders[decoder.Name()] = decoder
+}
+
+// Lookup returns the decoder registered under name
+func Lookup(name string) (FrameDecoder, error) {
+	decoder, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProtocol, name)
+	}
+	return decoder, nil
+}
+
+// LookupStream returns the stream protocol registered under name
+func LookupStream(name string) (StreamProtocol, error) {
+	decoder, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	stream, ok := decoder.(StreamProtocol)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support TCP streams", ErrUnknownProtocol, name)
+	}
+	return stream, nil
+}
+
+// Names lists the registered decoders
+func Names() []string {
+	names := make([]string, 0, len(decoders))
+	for name := range decoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(Codec8{})
+	Register(JSON{})
+}
@@ -0,0 +1,267 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+const (
+	codec8ID = 0x08
+	// maxCodec8DataLength bounds the data field of an AVL packet
+	maxCodec8DataLength = 64 * 1024
+	maxIMEILength       = 32
+)
+
+// Teltonika IO element IDs mapped into samples
+const (
+	teltonikaIOIgnition         = 239 // 0 off, 1 on
+	teltonikaIOExternalVoltage  = 66  // mV
+	teltonikaIOTotalOdometer    = 16  // m
+	teltonikaIOCoolantTemp      = 32  // °C, signed (OBD)
+	teltonikaIOEngineRPM        = 36  // rpm (OBD)
+	teltonikaIOFuelLevelPercent = 48  // % (OBD)
+)
+
+// Codec8 implements the Teltonika Codec 8 TCP protocol used by FMB/FMC series trackers.
+//
+// A connection starts with the IMEI (2-byte length + ASCII) answered by 0x01 or 0x00.
+// Each AVL packet is: 4 zero bytes, 4-byte data length, data (codec ID, record count,
+// records, record count) and a CRC-16/IBM over the data. The server answers with the
+// number of accepted records as a 4-byte integer.
+type Codec8 struct{}
+
+// Name returns the protocol name
+func (Codec8) Name() string {
+	return domain.DeviceProtocolTeltonikaCodec8
+}
+
+// Handshake reads the IMEI sent when the tracker connects
+func (Codec8) Handshake(r *bufio.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	if length == 0 || length > maxIMEILength {
+		return "", fmt.Errorf("%w: IMEI length %d", ErrInvalidFrame, length)
+	}
+	imei := make([]byte, length)
+	if _, err := io.ReadFull(r, imei); err != nil {
+		return "", err
+	}
+	return string(imei), nil
+}
+
+// Accept answers the IMEI message
+func (Codec8) Accept(w io.Writer, accepted bool) error {
+	answer := byte(0x00)
+	if accepted {
+		answer = 0x01
+	}
+	_, err := w.Write([]byte{answer})
+	return err
+}
+
+// ReadFrame reads one AVL packet including preamble, length and CRC
+func (Codec8) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if binary.BigEndian.Uint32(header[:4]) != 0 {
+		return nil, fmt.Errorf("%w: missing preamble", ErrInvalidFrame)
+	}
+	length := binary.BigEndian.Uint32(header[4:8])
+	if length < 3 || length > maxCodec8DataLength {
+		return nil, fmt.Errorf("%w: data length %d", ErrInvalidFrame, length)
+	}
+
+	frame := make([]byte, 8+int(length)+4)
+	copy(frame, header)
+	if _, err := io.ReadFull(r, frame[8:]); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// Acknowledge reports the number of accepted records; fewer than sent makes the tracker resend
+func (Codec8) Acknowledge(w io.Writer, frame []byte, records int) error {
+	ack := make([]byte, 4)
+	binary.BigEndian.PutUint32(ack, uint32(records))
+	_, err := w.Write(ack)
+	return err
+}
+
+// DecodeFrame decodes the AVL records of a packet
+func (Codec8) DecodeFrame(frame []byte) ([]*domain.TelematicsData, error) {
+	if len(frame) < 12 {
+		return nil, fmt.Errorf("%w: packet too short", ErrInvalidFrame)
+	}
+	length := int(binary.BigEndian.Uint32(frame[4:8]))
+	if len(frame) != 8+length+4 {
+		return nil, fmt.Errorf("%w: length mismatch", ErrInvalidFrame)
+	}
+
+	data := frame[8 : 8+length]
+	crc := binary.BigEndian.Uint32(frame[8+length:])
+	if uint32(crc16IBM(data)) != crc {
+		return nil, fmt.Errorf("%w: CRC mismatch", ErrInvalidFrame)
+	}
+	if data[0] != codec8ID {
+		return nil, fmt.Errorf("%w: unsupported codec 0x%02x", ErrInvalidFrame, data[0])
+	}
+
+	count := int(data[1])
+	if int(data[len(data)-1]) != count {
+		return nil, fmt.Errorf("%w: record count mismatch", ErrInvalidFrame)
+	}
+
+	br := &byteReader{buf: data[2 : len(data)-1]}
+	samples := make([]*domain.TelematicsData, 0, count)
+	for i := 0; i < count; i++ {
+		sample := decodeCodec8Record(br)
+		if br.err != nil {
+			return nil, fmt.Errorf("%w: record %d: %v", ErrInvalidFrame, i, br.err)
+		}
+		samples = append(samples, sample)
+	}
+	if br.remaining() != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidFrame, br.remaining())
+	}
+	return samples, nil
+}
+
+// decodeCodec8Record decodes one AVL record: timestamp, priority, GPS element and IO element
+func decodeCodec8Record(br *byteReader) *domain.TelematicsData {
+	sample := &domain.TelematicsData{}
+	sample.Timestamp = time.UnixMilli(int64(br.uint64())).UTC()
+	br.uint8() // priority
+
+	sample.Longitude = float64(int32(br.uint32())) / 1e7
+	sample.Latitude = float64(int32(br.uint32())) / 1e7
+	sample.Altitude = float64(int16(br.uint16()))
+	sample.Heading = float64(br.uint16())
+	br.uint8() // satellites
+	sample.Speed = float64(br.uint16())
+
+	br.uint8() // event IO ID
+	br.uint8() // total IO count
+	for _, size := range []int{1, 2, 4, 8} {
+		n := int(br.uint8())
+		for j := 0; j < n; j++ {
+			id := br.uint8()
+			var value uint64
+			switch size {
+			case 1:
+				value = uint64(br.uint8())
+			case 2:
+				value = uint64(br.uint16())
+			case 4:
+				value = uint64(br.uint32())
+			case 8:
+				value = br.uint64()
+			}
+			applyTeltonikaIO(sample, id, value)
+		}
+	}
+	return sample
+}
+
+// applyTeltonikaIO maps known IO elements onto sample fields
+func applyTeltonikaIO(sample *domain.TelematicsData, id uint8, value uint64) {
+	switch id {
+	case teltonikaIOIgnition:
+		if value == 1 {
+			sample.EngineStatus = domain.EngineStatusOn
+		} else {
+			sample.EngineStatus = domain.EngineStatusOff
+		}
+	case teltonikaIOExternalVoltage:
+		sample.BatteryLevel = float64(value) / 1000
+	case teltonikaIOTotalOdometer:
+		sample.TotalDistance = float64(value) / 1000
+	case teltonikaIOCoolantTemp:
+		sample.EngineTemp = float64(int8(value))
+	case teltonikaIOEngineRPM:
+		sample.EngineRPM = int(value)
+	case teltonikaIOFuelLevelPercent:
+		sample.FuelLevel = float64(value)
+	}
+}
+
+// Login sends the IMEI and waits for the acceptance byte
+func (Codec8) Login(rw *bufio.ReadWriter, identifier string) error {
+	if err := binary.Write(rw, binary.BigEndian, uint16(len(identifier))); err != nil {
+		return err
+	}
+	if _, err := rw.WriteString(identifier); err != nil {
+		return err
+	}
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+
+	answer, err := rw.ReadByte()
+	if err != nil {
+		return err
+	}
+	if answer != 0x01 {
+		return fmt.Errorf("device %s was refused by the server", identifier)
+	}
+	return nil
+}
+
+// ReadAck reads the number of records accepted by the server
+func (Codec8) ReadAck(r *bufio.Reader) (int, error) {
+	var accepted uint32
+	if err := binary.Read(r, binary.BigEndian, &accepted); err != nil {
+		return 0, err
+	}
+	return int(accepted), nil
+}
+
+// crc16IBM computes the CRC-16/IBM (ARC) checksum used by Teltonika packets
+func crc16IBM(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = (crc >> 1) ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// byteReader reads big-endian values from a buffer, remembering the first out-of-range read
+type byteReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (b *byteReader) next(n int) []byte {
+	if b.err != nil {
+		return make([]byte, n)
+	}
+	if b.pos+n > len(b.buf) {
+		b.err = io.ErrUnexpectedEOF
+		return make([]byte, n)
+	}
+	out := b.buf[b.pos : b.pos+n]
+	b.pos += n
+	return out
+}
+
+func (b *byteReader) uint8() uint8   { return b.next(1)[0] }
+func (b *byteReader) uint16() uint16 { return binary.BigEndian.Uint16(b.next(2)) }
+func (b *byteReader) uint32() uint32 { return binary.BigEndian.Uint32(b.next(4)) }
+func (b *byteReader) uint64() uint64 { return binary.BigEndian.Uint64(b.next(8)) }
+func (b *byteReader) remaining() int { return len(b.buf) - b.pos }
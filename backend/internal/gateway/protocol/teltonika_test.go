@@ -0,0 +1,130 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// codec8Frame is an AVL packet captured from an FMB tracker: one record with the external
+// voltage (IO 66) among its IO elements
+const codec8Frame = "000000000000003608010000016B40D8EA30010000000000000000000000000000000105021503010101425E0F01F10000601A014E0000000000000000010000C7CF"
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex: %v", err)
+	}
+	return b
+}
+
+// withCRC frames AVL data with the preamble, data length and CRC
+func withCRC(data []byte) []byte {
+	frame := make([]byte, 8, 8+len(data)+4)
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(data)))
+	frame = append(frame, data...)
+	return binary.BigEndian.AppendUint32(frame, uint32(crc16IBM(data)))
+}
+
+func TestCodec8DecodeFrame(t *testing.T) {
+	captured := decodeHex(t, codec8Frame)
+	data := captured[8 : len(captured)-4]
+
+	badCRC := bytes.Clone(captured)
+	badCRC[len(badCRC)-1] ^= 0xFF
+
+	// The record count still matches but the IO elements are cut short
+	truncatedRecord := append(bytes.Clone(data[:30]), data[len(data)-1])
+
+	otherCodec := bytes.Clone(data)
+	otherCodec[0] = 0x8E
+
+	tests := []struct {
+		name    string
+		frame   []byte
+		samples int
+		wantErr bool
+	}{
+		{name: "captured frame", frame: captured, samples: 1},
+		{name: "truncated frame", frame: captured[:len(captured)-6], wantErr: true},
+		{name: "header only", frame: captured[:8], wantErr: true},
+		{name: "CRC mismatch", frame: badCRC, wantErr: true},
+		{name: "truncated record", frame: withCRC(truncatedRecord), wantErr: true},
+		{name: "unsupported codec", frame: withCRC(otherCodec), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples, err := Codec8{}.DecodeFrame(tt.frame)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFrame) {
+					t.Fatalf("DecodeFrame() error = %v, want ErrInvalidFrame", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeFrame() error = %v", err)
+			}
+			if len(samples) != tt.samples {
+				t.Fatalf("DecodeFrame() returned %d samples, want %d", len(samples), tt.samples)
+			}
+		})
+	}
+}
+
+func TestCodec8DecodeFrameRecord(t *testing.T) {
+	samples, err := Codec8{}.DecodeFrame(decodeHex(t, codec8Frame))
+	if err != nil {
+		t.Fatalf("DecodeFrame() error = %v", err)
+	}
+	sample := samples[0]
+
+	if want := time.Date(2019, 6, 10, 10, 4, 46, 0, time.UTC); !sample.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", sample.Timestamp, want)
+	}
+	if sample.Latitude != 0 || sample.Longitude != 0 || sample.Speed != 0 {
+		t.Errorf("position = %v,%v speed %v, want no GPS fix", sample.Latitude, sample.Longitude, sample.Speed)
+	}
+	if sample.BatteryLevel != 24.079 {
+		t.Errorf("BatteryLevel = %v, want 24.079", sample.BatteryLevel)
+	}
+	if sample.EngineStatus != "" {
+		t.Errorf("EngineStatus = %q, want none without the ignition IO", sample.EngineStatus)
+	}
+}
+
+func TestCodec8ReadFrame(t *testing.T) {
+	captured := decodeHex(t, codec8Frame)
+
+	tests := []struct {
+		name    string
+		stream  []byte
+		wantErr error
+	}{
+		{name: "captured frame", stream: captured},
+		{name: "truncated frame", stream: captured[:len(captured)-6], wantErr: io.ErrUnexpectedEOF},
+		{name: "missing preamble", stream: append([]byte{0, 0, 0, 1}, captured[4:]...), wantErr: ErrInvalidFrame},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame, err := Codec8{}.ReadFrame(bufio.NewReader(bytes.NewReader(tt.stream)))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReadFrame() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFrame() error = %v", err)
+			}
+			if !bytes.Equal(frame, tt.stream) {
+				t.Fatalf("ReadFrame() = %x, want %x", frame, tt.stream)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordedFrame is a raw frame captured by the gateway
+type RecordedFrame struct {
+	ReceivedAt time.Time
+	Protocol   string
+	Identifier string
+	Frame      []byte
+}
+
+// Recorder appends received frames to a capture file, one frame per line:
+// "<RFC3339Nano>\t<protocol>\t<device identifier>\t<hex frame>".
+// Captures are replayed with the telematics-replay tool.
+type Recorder struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+// NewRecorder creates a new recorder writing to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: bufio.NewWriter(w)}
+}
+
+// Record appends a frame to the capture
+func (r *Recorder) Record(protocolName, identifier string, frame []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fmt.Fprintf(r.w, "%s\t%s\t%s\t%s\n",
+		time.Now().UTC().Format(time.RFC3339Nano), protocolName, identifier, hex.EncodeToString(frame))
+	r.w.Flush()
+}
+
+// ReadRecordedFrames parses a capture written by Recorder
+func ReadRecordedFrames(r io.Reader) ([]*RecordedFrame, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var frames []*RecordedFrame
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 tab separated fields", line)
+		}
+		receivedAt, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid timestamp: %w", line, err)
+		}
+		frame, err := hex.DecodeString(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid frame: %w", line, err)
+		}
+		frames = append(frames, &RecordedFrame{
+			ReceivedAt: receivedAt,
+			Protocol:   fields[1],
+			Identifier: fields[2],
+			Frame:      frame,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
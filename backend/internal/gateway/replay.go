@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/gateway/protocol"
+)
+
+// Replayer sends recorded frames to a running gateway, acting as the original devices
+type Replayer struct {
+	// TCPAddr is the gateway listener used for stream protocol frames
+	TCPAddr string
+	// MQTT is used for frames of other protocols; Topic replaces "+" with the device identifier
+	MQTT MQTTConfig
+	// Speed scales the original gaps between frames; 0 sends as fast as possible
+	Speed  float64
+	Logger *logrus.Logger
+}
+
+// replayConn is an open simulated device connection
+type replayConn struct {
+	conn   net.Conn
+	rw     *bufio.ReadWriter
+	client protocol.ReplayClient
+}
+
+// Replay sends frames in order, keeping one TCP connection per device
+func (r *Replayer) Replay(frames []*RecordedFrame) error {
+	conns := make(map[string]*replayConn)
+	defer func() {
+		for _, c := range conns {
+			c.conn.Close()
+		}
+	}()
+
+	var mqttClient mqtt.Client
+	defer func() {
+		if mqttClient != nil {
+			mqttClient.Disconnect(250)
+		}
+	}()
+
+	for i, frame := range frames {
+		if i > 0 && r.Speed > 0 {
+			if gap := frame.ReceivedAt.Sub(frames[i-1].ReceivedAt); gap > 0 {
+				time.Sleep(time.Duration(float64(gap) / r.Speed))
+			}
+		}
+
+		stream, err := protocol.LookupStream(frame.Protocol)
+		if err != nil {
+			if mqttClient == nil {
+				if mqttClient, err = r.connectMQTT(); err != nil {
+					return err
+				}
+			}
+			topic := strings.Replace(r.MQTT.Topic, "+", frame.Identifier, 1)
+			token := mqttClient.Publish(topic, mqttQoS, false, frame.Frame)
+			if token.Wait() && token.Error() != nil {
+				return fmt.Errorf("frame %d: failed to publish: %w", i+1, token.Error())
+			}
+			r.Logger.WithFields(logrus.Fields{"frame": i + 1, "device": frame.Identifier}).Info("Frame published")
+			continue
+		}
+
+		key := frame.Protocol + "/" + frame.Identifier
+		c, ok := conns[key]
+		if !ok {
+			if c, err = r.dial(stream, frame.Identifier); err != nil {
+				return fmt.Errorf("frame %d: %w", i+1, err)
+			}
+			conns[key] = c
+		}
+
+		if _, err := c.rw.Write(frame.Frame); err != nil {
+			return fmt.Errorf("frame %d: failed to send: %w", i+1, err)
+		}
+		if err := c.rw.Flush(); err != nil {
+			return fmt.Errorf("frame %d: failed to send: %w", i+1, err)
+		}
+		accepted, err := c.client.ReadAck(c.rw.Reader)
+		if err != nil {
+			return fmt.Errorf("frame %d: failed to read acknowledgement: %w", i+1, err)
+		}
+		r.Logger.WithFields(logrus.Fields{
+			"frame":    i + 1,
+			"device":   frame.Identifier,
+			"accepted": accepted,
+		}).Info("Frame replayed")
+	}
+	return nil
+}
+
+// dial opens a connection and logs in as identifier
+func (r *Replayer) dial(stream protocol.StreamProtocol, identifier string) (*replayConn, error) {
+	client, ok := stream.(protocol.ReplayClient)
+	if !ok {
+		return nil, fmt.Errorf("protocol %s cannot be replayed over TCP", stream.Name())
+	}
+
+	conn, err := net.DialTimeout("tcp", r.TCPAddr, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	if err := client.Login(rw, identifier); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &replayConn{conn: conn, rw: rw, client: client}, nil
+}
+
+func (r *Replayer) connectMQTT() (mqtt.Client, error) {
+	if r.MQTT.BrokerURL == "" {
+		return nil, fmt.Errorf("an MQTT broker is required to replay non-TCP frames")
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(r.MQTT.BrokerURL).
+		SetClientID(r.MQTT.ClientID).
+		SetUsername(r.MQTT.Username).
+		SetPassword(r.MQTT.Password)
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if token.Wait() && token.Error() != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", token.Error())
+	}
+	return client, nil
+}
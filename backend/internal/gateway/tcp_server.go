@@ -0,0 +1,241 @@
+package gateway
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/gateway/protocol"
+)
+
+const (
+	// handshakeTimeout bounds how long a new connection may take to identify itself
+	handshakeTimeout = 30 * time.Second
+	// idleTimeout closes connections of trackers that stopped sending data
+	idleTimeout  = 10 * time.Minute
+	writeTimeout = 10 * time.Second
+)
+
+// TCPServer accepts long-lived tracker connections speaking one stream protocol
+type TCPServer struct {
+	addr       string
+	protocol   protocol.StreamProtocol
+	authorizer *DeviceAuthorizer
+	sink       Sink
+	recorder   *Recorder
+	logger     *logrus.Logger
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewTCPServer creates a new TCP server. recorder may be nil.
+func NewTCPServer(
+	addr string,
+	streamProtocol protocol.StreamProtocol,
+	authorizer *DeviceAuthorizer,
+	sink Sink,
+	recorder *Recorder,
+	logger *logrus.Logger,
+) *TCPServer {
+	return &TCPServer{
+		addr:       addr,
+		protocol:   streamProtocol,
+		authorizer: authorizer,
+		sink:       sink,
+		recorder:   recorder,
+		logger:     logger,
+		conns:      make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe accepts connections until Close is called
+func (s *TCPServer) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		listener.Close()
+		return nil
+	}
+	s.listener = listener
+	s.mu.Unlock()
+
+	s.logger.WithFields(logrus.Fields{
+		"addr":     listener.Addr().String(),
+		"protocol": s.protocol.Name(),
+	}).Info("Telematics TCP listener started")
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		if !s.track(conn) {
+			conn.Close()
+			return nil
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.untrack(conn)
+			s.serve(conn)
+		}()
+	}
+}
+
+// Close stops accepting connections, closes open ones and waits for their handlers
+func (s *TCPServer) Close() {
+	s.mu.Lock()
+	s.closed = true
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *TCPServer) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *TCPServer) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *TCPServer) untrack(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	conn.Close()
+}
+
+// serve runs the handshake and then processes frames until the connection ends
+func (s *TCPServer) serve(conn net.Conn) {
+	log := s.logger.WithFields(logrus.Fields{
+		"remote":   conn.RemoteAddr().String(),
+		"protocol": s.protocol.Name(),
+	})
+	reader := bufio.NewReader(conn)
+
+	conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	identifier, err := s.protocol.Handshake(reader)
+	if err != nil {
+		log.WithError(err).Debug("Telematics handshake failed")
+		return
+	}
+	log = log.WithField("device", identifier)
+
+	_, authErr := s.authorizer.Authorize(identifier, s.protocol.Name())
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := s.protocol.Accept(conn, authErr == nil); err != nil {
+		log.WithError(err).Debug("Failed to answer telematics handshake")
+		return
+	}
+	if authErr != nil {
+		if errors.Is(authErr, ErrDeviceNotAllowed) {
+			log.WithError(authErr).Warn("Telematics device refused")
+		} else {
+			log.WithError(authErr).Error("Failed to authorize telematics device")
+		}
+		return
+	}
+	log.Info("Telematics device connected")
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		frame, err := s.protocol.ReadFrame(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) || s.isClosed() {
+				log.Info("Telematics device disconnected")
+			} else {
+				log.WithError(err).Warn("Telematics connection closed")
+			}
+			return
+		}
+
+		if s.recorder != nil {
+			s.recorder.Record(s.protocol.Name(), identifier, frame)
+		}
+
+		records := s.handleFrame(log, identifier, frame)
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := s.protocol.Acknowledge(conn, frame, records); err != nil {
+			log.WithError(err).Warn("Failed to acknowledge telematics frame")
+			return
+		}
+	}
+}
+
+// handleFrame decodes and ingests one frame and returns the number of records to acknowledge.
+// Samples rejected by the pipeline are acknowledged too, otherwise the tracker would resend
+// them forever; only decoding or storage failures leave the frame unacknowledged.
+func (s *TCPServer) handleFrame(log *logrus.Entry, identifier string, frame []byte) int {
+	samples, err := s.protocol.DecodeFrame(frame)
+	if err != nil {
+		log.WithError(err).Warn("Failed to decode telematics frame")
+		return 0
+	}
+	return ingestDecoded(log, s.sink, identifier, samples)
+}
+
+// ingestDecoded tags samples with their device and passes them to the sink
+func ingestDecoded(log *logrus.Entry, sink Sink, identifier string, samples []*domain.TelematicsData) int {
+	if len(samples) == 0 {
+		return 0
+	}
+	for _, sample := range samples {
+		if sample != nil {
+			sample.DeviceID = identifier
+		}
+	}
+
+	result, err := sink.IngestSamples(samples)
+	if err != nil {
+		log.WithError(err).Error("Failed to ingest telematics samples")
+		return 0
+	}
+	if result.Rejected > 0 {
+		for _, item := range result.Items {
+			if item.Error != "" {
+				log.WithField("reason", item.Error).Warnf("Rejected %d telematics samples", result.Rejected)
+				break
+			}
+		}
+	}
+	return len(samples)
+}
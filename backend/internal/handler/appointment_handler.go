@@ -0,0 +1,468 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// AppointmentHandler handles workshop bay and work order appointment HTTP requests
+type AppointmentHandler struct {
+	appointmentService *service.AppointmentService
+	logger             *logrus.Logger
+}
+
+// NewAppointmentHandler creates a new appointment handler
+func NewAppointmentHandler(appointmentService *service.AppointmentService, logger *logrus.Logger) *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+		logger:             logger,
+	}
+}
+
+// CreateBay adds a bay or lift to a branch
+// @Summary Create workshop bay
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param request body service.WorkshopBayRequest true "Workshop bay"
+// @Success 201 {object} response.Response "Workshop bay created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 409 {object} response.Response "Bay name already used at the branch"
+// @Router /workshop-bays [post]
+func (h *AppointmentHandler) CreateBay(c *gin.Context) {
+	var req service.WorkshopBayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	bay, err := h.appointmentService.CreateBay(&req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create workshop bay")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Workshop bay created successfully", bay)
+}
+
+// ListBays lists the workshop bays
+// @Summary List workshop bays
+// @Tags appointments
+// @Produce json
+// @Param branch_id query int false "Filter by branch"
+// @Param active query bool false "Only active bays"
+// @Success 200 {object} response.Response "Workshop bays retrieved successfully"
+// @Router /workshop-bays [get]
+func (h *AppointmentHandler) ListBays(c *gin.Context) {
+	branchID, ok := parseOptionalID(c, "branch_id")
+	if !ok {
+		return
+	}
+	activeOnly, _ := strconv.ParseBool(c.Query("active"))
+
+	bays, err := h.appointmentService.ListBays(branchID, activeOnly)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve workshop bays")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Workshop bays retrieved successfully", bays)
+}
+
+// GetBay returns a workshop bay
+// @Summary Get workshop bay
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Workshop bay ID"
+// @Success 200 {object} response.Response "Workshop bay retrieved successfully"
+// @Failure 404 {object} response.Response "Workshop bay not found"
+// @Router /workshop-bays/{id} [get]
+func (h *AppointmentHandler) GetBay(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "workshop bay")
+	if !ok {
+		return
+	}
+
+	bay, err := h.appointmentService.GetBay(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve workshop bay")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Workshop bay retrieved successfully", bay)
+}
+
+// UpdateBay replaces a workshop bay
+// @Summary Update workshop bay
+// @Description A bay with scheduled appointments ahead cannot move branch or be deactivated.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Workshop bay ID"
+// @Param request body service.WorkshopBayRequest true "Workshop bay"
+// @Success 200 {object} response.Response "Workshop bay updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Workshop bay not found"
+// @Router /workshop-bays/{id} [put]
+func (h *AppointmentHandler) UpdateBay(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "workshop bay")
+	if !ok {
+		return
+	}
+
+	var req service.WorkshopBayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	bay, err := h.appointmentService.UpdateBay(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update workshop bay")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Workshop bay updated successfully", bay)
+}
+
+// Book books a work order on a bay
+// @Summary Book work order appointment
+// @Description Books the work order and its assigned mechanic on a bay. The duration defaults to the remaining
+// @Description estimated hours. Bookings outside opening hours, on a booked bay or mechanic, or outside the
+// @Description mechanic's shifts are rejected.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.AppointmentRequest true "Appointment"
+// @Success 201 {object} response.Response "Appointment booked successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Appointment conflicts with the calendar"
+// @Router /workorders/{id}/appointments [post]
+func (h *AppointmentHandler) Book(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	workOrderID, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.AppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	appointment, err := h.appointmentService.Book(viewer, workOrderID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to book appointment")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Appointment booked successfully", appointment)
+}
+
+// Check reports whether a work order can be booked as requested
+// @Summary Check work order appointment
+// @Description Lists the conflicts of a booking without saving it. The work order's own appointment is ignored,
+// @Description so a move can be checked before it is made.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.AppointmentRequest true "Appointment"
+// @Success 200 {object} response.Response "Appointment checked successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id}/appointments/check [post]
+func (h *AppointmentHandler) Check(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	workOrderID, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.AppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	check, err := h.appointmentService.Check(viewer, workOrderID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to check appointment")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Appointment checked successfully", check)
+}
+
+// ListByWorkOrder lists the appointments of a work order
+// @Summary List work order appointments
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Appointments retrieved successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id}/appointments [get]
+func (h *AppointmentHandler) ListByWorkOrder(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	workOrderID, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	appointments, err := h.appointmentService.ListByWorkOrder(viewer, workOrderID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve appointments")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Appointments retrieved successfully", appointments)
+}
+
+// List lists the appointments of the workshop calendar
+// @Summary List appointments
+// @Description Appointments overlapping the range, earliest first. Mechanics only see their own.
+// @Tags appointments
+// @Produce json
+// @Param branch_id query int false "Filter by branch"
+// @Param bay_id query int false "Filter by bay"
+// @Param mechanic_id query int false "Filter by mechanic"
+// @Param status query string false "Filter by status (scheduled, completed, cancelled)"
+// @Param from query string false "Start time (RFC3339), defaults to today"
+// @Param to query string false "End time (RFC3339), defaults to 7 days after today"
+// @Success 200 {object} response.Response "Appointments retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /appointments [get]
+func (h *AppointmentHandler) List(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to, ok := parseTimeRange(c, today, today.AddDate(0, 0, 7))
+	if !ok {
+		return
+	}
+	filter := interfaces.AppointmentFilter{From: from, To: to, Status: c.Query("status")}
+	switch filter.Status {
+	case "", domain.AppointmentStatusScheduled, domain.AppointmentStatusCompleted, domain.AppointmentStatusCancelled:
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid status", "status must be scheduled, completed or cancelled")
+		return
+	}
+	if filter.BranchID, ok = parseOptionalID(c, "branch_id"); !ok {
+		return
+	}
+	if filter.BayID, ok = parseOptionalID(c, "bay_id"); !ok {
+		return
+	}
+	if filter.MechanicID, ok = parseOptionalID(c, "mechanic_id"); !ok {
+		return
+	}
+
+	appointments, err := h.appointmentService.List(viewer, filter)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve appointments")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Appointments retrieved successfully", appointments)
+}
+
+// Capacity returns the day or week view of a branch
+// @Summary Get workshop capacity
+// @Description Booked against available bay and mechanic hours per day, with the calendar of each bay and
+// @Description mechanic. Mechanic hours are shift hours within opening hours, capped at the daily capacity.
+// @Tags appointments
+// @Produce json
+// @Param branch_id query int true "Branch ID"
+// @Param date query string false "First day (YYYY-MM-DD), defaults to today"
+// @Param view query string false "day or week" default(day)
+// @Success 200 {object} response.Response "Workshop capacity retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /appointments/capacity [get]
+func (h *AppointmentHandler) Capacity(c *gin.Context) {
+	branchID, ok := parseOptionalID(c, "branch_id")
+	if !ok {
+		return
+	}
+	if branchID == 0 {
+		response.ValidationError(c, "Invalid branch", "branch_id is required")
+		return
+	}
+	date := time.Now().UTC()
+	if raw := c.Query("date"); raw != "" {
+		value, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid date", "date must be a date in YYYY-MM-DD format")
+			return
+		}
+		date = value
+	}
+	days := 1
+	switch c.DefaultQuery("view", "day") {
+	case "day":
+	case "week":
+		days = 7
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid view", "view must be day or week")
+		return
+	}
+
+	capacity, err := h.appointmentService.Capacity(branchID, date, days)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve workshop capacity")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Workshop capacity retrieved successfully", capacity)
+}
+
+// Get returns an appointment
+// @Summary Get appointment
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} response.Response "Appointment retrieved successfully"
+// @Failure 404 {object} response.Response "Appointment not found"
+// @Router /appointments/{id} [get]
+func (h *AppointmentHandler) Get(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "appointment")
+	if !ok {
+		return
+	}
+
+	appointment, err := h.appointmentService.Get(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve appointment")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Appointment retrieved successfully", appointment)
+}
+
+// Move moves a scheduled appointment
+// @Summary Move appointment
+// @Description Moves the appointment to another period or bay and notifies the mechanic. The same conflict
+// @Description checks as booking apply.
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param request body service.MoveAppointmentRequest true "New period"
+// @Success 200 {object} response.Response "Appointment moved successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Appointment not found"
+// @Failure 409 {object} response.Response "Appointment conflicts with the calendar"
+// @Router /appointments/{id}/move [put]
+func (h *AppointmentHandler) Move(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "appointment")
+	if !ok {
+		return
+	}
+
+	var req service.MoveAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	appointment, err := h.appointmentService.Move(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to move appointment")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Appointment moved successfully", appointment)
+}
+
+// Cancel cancels a scheduled appointment
+// @Summary Cancel appointment
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param request body service.CancelAppointmentRequest true "Cancellation reason"
+// @Success 200 {object} response.Response "Appointment cancelled successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Appointment not found"
+// @Router /appointments/{id}/cancel [put]
+func (h *AppointmentHandler) Cancel(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "appointment")
+	if !ok {
+		return
+	}
+
+	var req service.CancelAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	appointment, err := h.appointmentService.Cancel(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel appointment")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Appointment cancelled successfully", appointment)
+}
+
+// handleError maps appointment service errors to HTTP responses
+func (h *AppointmentHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrWorkshopBayNotFound),
+		errors.Is(err, service.ErrAppointmentNotFound),
+		errors.Is(err, service.ErrWorkOrderNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrWorkOrderAccessDenied),
+		errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrWorkshopBayExists),
+		errors.Is(err, service.ErrAppointmentExists),
+		errors.Is(err, service.ErrAppointmentClosed),
+		errors.Is(err, service.ErrAppointmentConflict),
+		errors.Is(err, service.ErrWorkOrderClosed):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		response.Error(c, http.StatusBadRequest, "Invalid time range", err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+	"ton-platform/pkg/storage"
+)
+
+// AttachmentHandler handles file attachment HTTP requests for work orders, vehicles and inspections
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+	logger            *logrus.Logger
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(attachmentService *service.AttachmentService, logger *logrus.Logger) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		logger:            logger,
+	}
+}
+
+// UploadWorkOrder attaches a file to a work order
+// @Summary Upload work order attachment
+// @Description Images are listed among the work order photos, with the category as photo type.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param file formData file true "JPEG, PNG, GIF or WebP image or PDF"
+// @Param category formData string false "before, after, damage, progress, document or other"
+// @Param description formData string false "Description"
+// @Success 201 {object} response.Response "Attachment uploaded successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 413 {object} response.Response "File too large"
+// @Failure 415 {object} response.Response "Unsupported file type"
+// @Router /workorders/{id}/attachments [post]
+func (h *AttachmentHandler) UploadWorkOrder(c *gin.Context) {
+	h.upload(c, domain.AttachmentOwnerWorkOrder, "work order")
+}
+
+// ListWorkOrder lists the attachments of a work order
+// @Summary List work order attachments
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Attachments retrieved successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id}/attachments [get]
+func (h *AttachmentHandler) ListWorkOrder(c *gin.Context) {
+	h.list(c, domain.AttachmentOwnerWorkOrder, "work order")
+}
+
+// UploadVehicle attaches a file to a vehicle
+// @Summary Upload vehicle attachment
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param file formData file true "JPEG, PNG, GIF or WebP image or PDF"
+// @Param category formData string false "before, after, damage, progress, document or other"
+// @Param description formData string false "Description"
+// @Success 201 {object} response.Response "Attachment uploaded successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Failure 413 {object} response.Response "File too large"
+// @Failure 415 {object} response.Response "Unsupported file type"
+// @Router /vehicles/{id}/attachments [post]
+func (h *AttachmentHandler) UploadVehicle(c *gin.Context) {
+	h.upload(c, domain.AttachmentOwnerVehicle, "vehicle")
+}
+
+// ListVehicle lists the attachments of a vehicle
+// @Summary List vehicle attachments
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} response.Response "Attachments retrieved successfully"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/attachments [get]
+func (h *AttachmentHandler) ListVehicle(c *gin.Context) {
+	h.list(c, domain.AttachmentOwnerVehicle, "vehicle")
+}
+
+// UploadInspection attaches a file to an inspection
+// @Summary Upload inspection attachment
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Inspection ID"
+// @Param file formData file true "JPEG, PNG, GIF or WebP image or PDF"
+// @Param category formData string false "before, after, damage, progress, document or other"
+// @Param description formData string false "Description"
+// @Success 201 {object} response.Response "Attachment uploaded successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Inspection not found"
+// @Failure 413 {object} response.Response "File too large"
+// @Failure 415 {object} response.Response "Unsupported file type"
+// @Router /inspections/{id}/attachments [post]
+func (h *AttachmentHandler) UploadInspection(c *gin.Context) {
+	h.upload(c, domain.AttachmentOwnerInspection, "inspection")
+}
+
+// ListInspection lists the attachments of an inspection
+// @Summary List inspection attachments
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Inspection ID"
+// @Success 200 {object} response.Response "Attachments retrieved successfully"
+// @Failure 404 {object} response.Response "Inspection not found"
+// @Router /inspections/{id}/attachments [get]
+func (h *AttachmentHandler) ListInspection(c *gin.Context) {
+	h.list(c, domain.AttachmentOwnerInspection, "inspection")
+}
+
+// Get returns an attachment with signed download links
+// @Summary Get attachment
+// @Description url and thumbnail_url download the file and its thumbnail until url_expires_at.
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} response.Response "Attachment retrieved successfully"
+// @Failure 404 {object} response.Response "Attachment not found"
+// @Router /attachments/{id} [get]
+func (h *AttachmentHandler) Get(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "attachment")
+	if !ok {
+		return
+	}
+
+	attachment, err := h.attachmentService.Get(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve attachment")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attachment retrieved successfully", attachment)
+}
+
+// Content redirects to a fresh signed download link of an attachment
+// @Summary Download attachment
+// @Description A stable address for an attachment, such as the photo_url of work order photos. Redirects to
+// @Description a signed link of the file, or of its thumbnail when thumbnail=true and there is one.
+// @Tags attachments
+// @Param id path int true "Attachment ID"
+// @Param thumbnail query bool false "Download the thumbnail"
+// @Success 302 "Redirect to the signed download link"
+// @Failure 404 {object} response.Response "Attachment not found"
+// @Router /attachments/{id}/content [get]
+func (h *AttachmentHandler) Content(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "attachment")
+	if !ok {
+		return
+	}
+	thumbnail, _ := strconv.ParseBool(c.Query("thumbnail"))
+
+	url, err := h.attachmentService.ContentURL(viewer, id, thumbnail)
+	if err != nil {
+		h.handleError(c, err, "Failed to download attachment")
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+// Delete deletes an attachment and its files
+// @Summary Delete attachment
+// @Description Drivers and mechanics can only delete their own uploads.
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} response.Response "Attachment deleted successfully"
+// @Failure 403 {object} response.Response "Uploaded by another user"
+// @Failure 404 {object} response.Response "Attachment not found"
+// @Router /attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "attachment")
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.Delete(viewer, id); err != nil {
+		h.handleError(c, err, "Failed to delete attachment")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attachment deleted successfully", nil)
+}
+
+// ServeFile serves a file of the local storage for a signed download link
+// @Summary Download file
+// @Description Public; authorised by the signature of the link. Only used with local file storage.
+// @Tags attachments
+// @Param key path string true "Object key"
+// @Param expires query int true "Expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 "File content"
+// @Failure 403 {object} response.Response "Invalid or expired link"
+// @Failure 404 {object} response.Response "File not found"
+// @Router /files/{key} [get]
+func (h *AttachmentHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	body, object, err := h.attachmentService.OpenSigned(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		h.handleError(c, err, "Failed to download file")
+		return
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, object.Size, object.ContentType, body, map[string]string{
+		"Cache-Control":          "private, max-age=300",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// upload reads a multipart file upload and attaches it to the owner in the id path parameter
+func (h *AttachmentHandler) upload(c *gin.Context, ownerType, label string) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	ownerID, ok := parseIDParam(c, "id", label)
+	if !ok {
+		return
+	}
+
+	maxSize := h.attachmentService.MaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(c, http.StatusRequestEntityTooLarge, "File too large", fmt.Sprintf("Maximum file size is %d MB", maxSize>>20))
+			return
+		}
+		response.Error(c, http.StatusBadRequest, "File is required", err.Error())
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to read file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(viewer, ownerType, ownerID, &service.AttachmentUpload{
+		FileName:    header.Filename,
+		Category:    c.PostForm("category"),
+		Description: c.PostForm("description"),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if err != nil {
+		h.handleError(c, err, "Failed to upload attachment")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Attachment uploaded successfully", attachment)
+}
+
+// list lists the attachments of the owner in the id path parameter
+func (h *AttachmentHandler) list(c *gin.Context, ownerType, label string) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	ownerID, ok := parseIDParam(c, "id", label)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.attachmentService.List(viewer, ownerType, ownerID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve attachments")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attachments retrieved successfully", attachments)
+}
+
+// handleError maps attachment service errors to HTTP responses
+func (h *AttachmentHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrAttachmentNotFound),
+		errors.Is(err, service.ErrWorkOrderNotFound),
+		errors.Is(err, service.ErrVehicleNotFound),
+		errors.Is(err, service.ErrInspectionNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrAttachmentAccessDenied),
+		errors.Is(err, service.ErrWorkOrderAccessDenied),
+		errors.Is(err, service.ErrVehicleAccessDenied),
+		errors.Is(err, storage.ErrInvalidSignature):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		response.Error(c, http.StatusRequestEntityTooLarge, message, err.Error())
+	case errors.Is(err, service.ErrAttachmentType):
+		response.Error(c, http.StatusUnsupportedMediaType, message, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
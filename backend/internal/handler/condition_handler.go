@@ -0,0 +1,350 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// ConditionHandler handles condition monitoring HTTP requests
+type ConditionHandler struct {
+	conditionService *service.ConditionService
+	logger           *logrus.Logger
+}
+
+// NewConditionHandler creates a new condition handler
+func NewConditionHandler(conditionService *service.ConditionService, logger *logrus.Logger) *ConditionHandler {
+	return &ConditionHandler{
+		conditionService: conditionService,
+		logger:           logger,
+	}
+}
+
+// CreateRule creates a condition rule
+// @Summary Create condition rule
+// @Description A reading beyond the threshold for duration_seconds raises an alert that clears once a
+// @Description reading is back past clear_threshold. Rules for a vehicle type replace the generic rules
+// @Description on the same metric, operator and engine state.
+// @Tags diagnostics
+// @Accept json
+// @Produce json
+// @Param request body service.ConditionRuleRequest true "Condition rule"
+// @Success 201 {object} response.Response "Condition rule created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Router /condition-rules [post]
+func (h *ConditionHandler) CreateRule(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.ConditionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.conditionService.CreateRule(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create condition rule")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Condition rule created successfully", rule)
+}
+
+// ListRules lists the condition rules
+// @Summary List condition rules
+// @Tags diagnostics
+// @Produce json
+// @Success 200 {object} response.Response "Condition rules retrieved successfully"
+// @Router /condition-rules [get]
+func (h *ConditionHandler) ListRules(c *gin.Context) {
+	rules, err := h.conditionService.ListRules()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve condition rules")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Condition rules retrieved successfully", rules)
+}
+
+// GetRule returns a condition rule
+// @Summary Get condition rule
+// @Tags diagnostics
+// @Produce json
+// @Param id path int true "Condition rule ID"
+// @Success 200 {object} response.Response "Condition rule retrieved successfully"
+// @Failure 404 {object} response.Response "Condition rule not found"
+// @Router /condition-rules/{id} [get]
+func (h *ConditionHandler) GetRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "condition rule")
+	if !ok {
+		return
+	}
+
+	rule, err := h.conditionService.GetRule(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve condition rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Condition rule retrieved successfully", rule)
+}
+
+// UpdateRule replaces a condition rule
+// @Summary Update condition rule
+// @Description Deactivating a rule or changing its metric or operator clears its active alerts
+// @Tags diagnostics
+// @Accept json
+// @Produce json
+// @Param id path int true "Condition rule ID"
+// @Param request body service.ConditionRuleRequest true "Condition rule"
+// @Success 200 {object} response.Response "Condition rule updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Condition rule not found"
+// @Router /condition-rules/{id} [put]
+func (h *ConditionHandler) UpdateRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "condition rule")
+	if !ok {
+		return
+	}
+
+	var req service.ConditionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.conditionService.UpdateRule(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update condition rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Condition rule updated successfully", rule)
+}
+
+// DeleteRule deletes a condition rule
+// @Summary Delete condition rule
+// @Description Active alerts of the rule are cleared; past alerts are kept
+// @Tags diagnostics
+// @Produce json
+// @Param id path int true "Condition rule ID"
+// @Success 200 {object} response.Response "Condition rule deleted successfully"
+// @Failure 404 {object} response.Response "Condition rule not found"
+// @Router /condition-rules/{id} [delete]
+func (h *ConditionHandler) DeleteRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "condition rule")
+	if !ok {
+		return
+	}
+
+	if err := h.conditionService.DeleteRule(id); err != nil {
+		h.handleError(c, err, "Failed to delete condition rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Condition rule deleted successfully", nil)
+}
+
+// ListAlerts lists condition alerts across the fleet
+// @Summary List condition alerts
+// @Description Active alerts first, most recently triggered first
+// @Tags diagnostics
+// @Produce json
+// @Param vehicle_id query int false "Filter by vehicle"
+// @Param rule_id query int false "Filter by rule"
+// @Param status query string false "active or cleared"
+// @Param severity query string false "warning or critical"
+// @Param acknowledged query bool false "Filter by acknowledgement"
+// @Param from query string false "Triggered at or after (RFC3339)"
+// @Param to query string false "Triggered at or before (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Condition alerts retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /condition-alerts [get]
+func (h *ConditionHandler) ListAlerts(c *gin.Context) {
+	filter, ok := parseConditionAlertFilter(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseOptionalID(c, "vehicle_id")
+	if !ok {
+		return
+	}
+	filter.VehicleID = vehicleID
+	page, limit, offset := parsePagination(c)
+
+	alerts, err := h.conditionService.ListAlerts(filter, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve condition alerts")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Condition alerts retrieved successfully", alerts)
+}
+
+// ListVehicleAlerts lists the condition alerts of a vehicle
+// @Summary List vehicle condition alerts
+// @Description Active alerts first, most recently triggered first
+// @Tags diagnostics
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param rule_id query int false "Filter by rule"
+// @Param status query string false "active or cleared"
+// @Param severity query string false "warning or critical"
+// @Param acknowledged query bool false "Filter by acknowledgement"
+// @Param from query string false "Triggered at or after (RFC3339)"
+// @Param to query string false "Triggered at or before (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Condition alerts retrieved successfully"
+// @Failure 403 {object} response.Response "Vehicle not assigned to driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/condition-alerts [get]
+func (h *ConditionHandler) ListVehicleAlerts(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	filter, ok := parseConditionAlertFilter(c)
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	alerts, err := h.conditionService.ListVehicleAlerts(viewer, vehicleID, filter, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve condition alerts")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Condition alerts retrieved successfully", alerts)
+}
+
+// GetAlert returns a condition alert
+// @Summary Get condition alert
+// @Tags diagnostics
+// @Produce json
+// @Param id path int true "Condition alert ID"
+// @Success 200 {object} response.Response "Condition alert retrieved successfully"
+// @Failure 403 {object} response.Response "Vehicle not assigned to driver"
+// @Failure 404 {object} response.Response "Condition alert not found"
+// @Router /condition-alerts/{id} [get]
+func (h *ConditionHandler) GetAlert(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "condition alert")
+	if !ok {
+		return
+	}
+
+	alert, err := h.conditionService.GetAlert(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve condition alert")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Condition alert retrieved successfully", alert)
+}
+
+// AcknowledgeAlert records that the current user has seen a condition alert
+// @Summary Acknowledge condition alert
+// @Description Acknowledging does not clear an active alert; it clears once readings recover
+// @Tags diagnostics
+// @Accept json
+// @Produce json
+// @Param id path int true "Condition alert ID"
+// @Param request body service.ConditionAlertAcknowledgeRequest false "Acknowledgement"
+// @Success 200 {object} response.Response "Condition alert acknowledged successfully"
+// @Failure 404 {object} response.Response "Condition alert not found"
+// @Failure 409 {object} response.Response "Condition alert already acknowledged"
+// @Router /condition-alerts/{id}/acknowledge [put]
+func (h *ConditionHandler) AcknowledgeAlert(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "condition alert")
+	if !ok {
+		return
+	}
+
+	var req service.ConditionAlertAcknowledgeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	alert, err := h.conditionService.AcknowledgeAlert(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to acknowledge condition alert")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Condition alert acknowledged successfully", alert)
+}
+
+// parseConditionAlertFilter reads the alert filters shared by the fleet and vehicle listings
+func parseConditionAlertFilter(c *gin.Context) (interfaces.ConditionAlertFilter, bool) {
+	filter := interfaces.ConditionAlertFilter{
+		Status:   c.Query("status"),
+		Severity: c.Query("severity"),
+	}
+	ruleID, ok := parseOptionalID(c, "rule_id")
+	if !ok {
+		return filter, false
+	}
+	filter.RuleID = ruleID
+	if raw := c.Query("acknowledged"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.ValidationError(c, "Invalid acknowledged filter", "acknowledged must be true or false")
+			return filter, false
+		}
+		filter.Acknowledged = &value
+	}
+	from, to, ok := parseTimeRange(c, time.Time{}, time.Time{})
+	if !ok {
+		return filter, false
+	}
+	filter.From, filter.To = optionalTime(from), optionalTime(to)
+	return filter, true
+}
+
+// handleError maps condition service errors to HTTP responses
+func (h *ConditionHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrConditionRuleNotFound),
+		errors.Is(err, service.ErrConditionAlertNotFound),
+		errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrConditionAlertAcknowledged):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// DamageHandler handles vehicle damage report HTTP requests
+type DamageHandler struct {
+	damageService *service.DamageService
+	logger        *logrus.Logger
+}
+
+// NewDamageHandler creates a new damage handler
+func NewDamageHandler(damageService *service.DamageService, logger *logrus.Logger) *DamageHandler {
+	return &DamageHandler{
+		damageService: damageService,
+		logger:        logger,
+	}
+}
+
+// GetZones lists the zone codes of the vehicle diagram
+// @Summary Get vehicle diagram zones
+// @Tags damage
+// @Produce json
+// @Success 200 {object} response.Response "Zones retrieved successfully"
+// @Router /damage-reports/zones [get]
+func (h *DamageHandler) GetZones(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Vehicle diagram zones retrieved successfully", domain.VehicleDiagramZones)
+}
+
+// Create records damage on a vehicle
+// @Summary Report vehicle damage
+// @Tags damage
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param request body service.CreateDamageReportRequest true "Damage details"
+// @Success 201 {object} response.Response "Damage report created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/damage-reports [post]
+func (h *DamageHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+
+	var req service.CreateDamageReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind damage report request")
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	report, err := h.damageService.Create(vehicleID, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create damage report")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Damage report created successfully", report)
+}
+
+// ListByVehicle lists damage reports of a vehicle
+// @Summary List vehicle damage
+// @Tags damage
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param status query string false "Filter by status (open, repaired, charged)"
+// @Success 200 {object} response.Response "Damage reports retrieved successfully"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/damage-reports [get]
+func (h *DamageHandler) ListByVehicle(c *gin.Context) {
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+
+	reports, err := h.damageService.ListByVehicle(vehicleID, c.Query("status"))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve damage reports")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Damage reports retrieved successfully", reports)
+}
+
+// Compare detects new damage between two inspections of a vehicle
+// @Summary Compare vehicle damage between inspections
+// @Tags damage
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param from query int true "Earlier inspection ID"
+// @Param to query int true "Later inspection ID"
+// @Success 200 {object} response.Response "Damage comparison completed"
+// @Failure 400 {object} response.Response "Invalid inspections"
+// @Router /vehicles/{id}/damage-reports/compare [get]
+func (h *DamageHandler) Compare(c *gin.Context) {
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+
+	from, errFrom := strconv.ParseUint(c.Query("from"), 10, 32)
+	to, errTo := strconv.ParseUint(c.Query("to"), 10, 32)
+	if errFrom != nil || errTo != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid inspection IDs", "from and to query parameters are required")
+		return
+	}
+
+	comparison, err := h.damageService.Compare(vehicleID, uint(from), uint(to))
+	if err != nil {
+		h.handleError(c, err, "Failed to compare damage")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Damage comparison completed", comparison)
+}
+
+// GetByID retrieves a damage report
+// @Summary Get damage report
+// @Tags damage
+// @Produce json
+// @Param id path int true "Damage report ID"
+// @Success 200 {object} response.Response "Damage report retrieved successfully"
+// @Failure 404 {object} response.Response "Damage report not found"
+// @Router /damage-reports/{id} [get]
+func (h *DamageHandler) GetByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "damage report")
+	if !ok {
+		return
+	}
+
+	report, err := h.damageService.GetByID(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve damage report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Damage report retrieved successfully", report)
+}
+
+// UpdateStatus marks damage as repaired or re-opens it
+// @Summary Update damage status
+// @Tags damage
+// @Accept json
+// @Produce json
+// @Param id path int true "Damage report ID"
+// @Param request body service.UpdateDamageStatusRequest true "New status"
+// @Success 200 {object} response.Response "Damage status updated"
+// @Failure 400 {object} response.Response "Validation error"
+// @Router /damage-reports/{id}/status [put]
+func (h *DamageHandler) UpdateStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "damage report")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateDamageStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	report, err := h.damageService.UpdateStatus(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update damage status")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Damage status updated successfully", report)
+}
+
+// Charge converts damage into an invoice item charged to the customer
+// @Summary Charge damage to customer
+// @Tags damage
+// @Accept json
+// @Produce json
+// @Param id path int true "Damage report ID"
+// @Param request body service.ChargeDamageRequest true "Charge details"
+// @Success 200 {object} response.Response "Damage charged to customer"
+// @Failure 400 {object} response.Response "Validation error"
+// @Router /damage-reports/{id}/charge [post]
+func (h *DamageHandler) Charge(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "damage report")
+	if !ok {
+		return
+	}
+
+	var req service.ChargeDamageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	report, err := h.damageService.ChargeToCustomer(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to charge damage")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Damage charged to customer successfully", report)
+}
+
+// CreateRepairWorkOrder converts damage into a repair work order
+// @Summary Create repair work order from damage
+// @Tags damage
+// @Accept json
+// @Produce json
+// @Param id path int true "Damage report ID"
+// @Param request body service.RepairDamageRequest false "Work order options"
+// @Success 201 {object} response.Response "Repair work order created"
+// @Failure 400 {object} response.Response "Validation error"
+// @Router /damage-reports/{id}/repair-work-order [post]
+func (h *DamageHandler) CreateRepairWorkOrder(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "damage report")
+	if !ok {
+		return
+	}
+
+	var req service.RepairDamageRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+			return
+		}
+	}
+
+	workOrder, err := h.damageService.CreateRepairWorkOrder(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create repair work order")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Repair work order created successfully", workOrder)
+}
+
+// handleError maps damage service errors to HTTP responses
+func (h *DamageHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrDamageReportNotFound),
+		errors.Is(err, service.ErrVehicleNotFound),
+		errors.Is(err, service.ErrInspectionNotFound),
+		errors.Is(err, service.ErrInvoiceNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrInvalidDamageOperation), isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// DeviceHandler handles telematics device registry HTTP requests
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+	logger        *logrus.Logger
+}
+
+// NewDeviceHandler creates a new device handler
+func NewDeviceHandler(deviceService *service.DeviceService, logger *logrus.Logger) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+		logger:        logger,
+	}
+}
+
+// Register adds a device to the registry
+// @Summary Register telematics device
+// @Description The generated secret is returned only once
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Param request body service.CreateDeviceRequest true "Device details"
+// @Success 201 {object} response.Response "Device registered successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 409 {object} response.Response "Device already exists"
+// @Router /devices [post]
+func (h *DeviceHandler) Register(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.CreateDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	credentials, err := h.deviceService.Register(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to register device")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Device registered successfully", credentials)
+}
+
+// List lists registered devices
+// @Summary List telematics devices
+// @Tags devices
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param search query string false "Search identifier, IMEI or serial number"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Devices retrieved successfully"
+// @Router /devices [get]
+func (h *DeviceHandler) List(c *gin.Context) {
+	page, limit, offset := parsePagination(c)
+
+	devices, err := h.deviceService.List(c.Query("status"), c.Query("search"), page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve devices")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Devices retrieved successfully", devices)
+}
+
+// GetByID retrieves a device with its current binding
+// @Summary Get telematics device
+// @Tags devices
+// @Produce json
+// @Param id path int true "Device ID"
+// @Success 200 {object} response.Response "Device retrieved successfully"
+// @Failure 404 {object} response.Response "Device not found"
+// @Router /devices/{id} [get]
+func (h *DeviceHandler) GetByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "device")
+	if !ok {
+		return
+	}
+
+	device, err := h.deviceService.GetByID(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve device")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Device retrieved successfully", device)
+}
+
+// Update changes device details and status
+// @Summary Update telematics device
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Param id path int true "Device ID"
+// @Param request body service.UpdateDeviceRequest true "Device details"
+// @Success 200 {object} response.Response "Device updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Device not found"
+// @Router /devices/{id} [put]
+func (h *DeviceHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "device")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	device, err := h.deviceService.Update(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update device")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Device updated successfully", device)
+}
+
+// RotateCredentials issues new device credentials
+// @Summary Rotate device credentials
+// @Description The previous credentials stop working immediately; the new secret is returned only once
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Param id path int true "Device ID"
+// @Param request body service.RotateCredentialsRequest false "Credential type"
+// @Success 200 {object} response.Response "Device credentials rotated"
+// @Failure 404 {object} response.Response "Device not found"
+// @Router /devices/{id}/credentials [post]
+func (h *DeviceHandler) RotateCredentials(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "device")
+	if !ok {
+		return
+	}
+
+	var req service.RotateCredentialsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+			return
+		}
+	}
+
+	credentials, err := h.deviceService.RotateCredentials(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to rotate device credentials")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Device credentials rotated successfully", credentials)
+}
+
+// Bind installs a device in a vehicle
+// @Summary Bind device to vehicle
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Param id path int true "Device ID"
+// @Param request body service.BindDeviceRequest true "Binding details"
+// @Success 201 {object} response.Response "Device bound to vehicle"
+// @Failure 400 {object} response.Response "Invalid binding"
+// @Failure 404 {object} response.Response "Device or vehicle not found"
+// @Router /devices/{id}/bind [post]
+func (h *DeviceHandler) Bind(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "device")
+	if !ok {
+		return
+	}
+
+	var req service.BindDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	binding, err := h.deviceService.Bind(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to bind device")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Device bound to vehicle successfully", binding)
+}
+
+// Unbind removes a device from its vehicle
+// @Summary Unbind device from vehicle
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Param id path int true "Device ID"
+// @Param request body service.UnbindDeviceRequest false "Unbind details"
+// @Success 200 {object} response.Response "Device unbound from vehicle"
+// @Failure 404 {object} response.Response "Device is not bound"
+// @Router /devices/{id}/unbind [post]
+func (h *DeviceHandler) Unbind(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "device")
+	if !ok {
+		return
+	}
+
+	var req service.UnbindDeviceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+			return
+		}
+	}
+
+	if err := h.deviceService.Unbind(id, &req, userID); err != nil {
+		h.handleError(c, err, "Failed to unbind device")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Device unbound from vehicle successfully", nil)
+}
+
+// GetBindingHistory lists the vehicles a device has been installed in
+// @Summary Get device binding history
+// @Tags devices
+// @Produce json
+// @Param id path int true "Device ID"
+// @Success 200 {object} response.Response "Binding history retrieved successfully"
+// @Failure 404 {object} response.Response "Device not found"
+// @Router /devices/{id}/bindings [get]
+func (h *DeviceHandler) GetBindingHistory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "device")
+	if !ok {
+		return
+	}
+
+	bindings, err := h.deviceService.GetBindingHistory(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve binding history")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Binding history retrieved successfully", bindings)
+}
+
+// GetVehicleDevices lists the devices installed in a vehicle over time
+// @Summary Get vehicle devices
+// @Tags devices
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} response.Response "Vehicle devices retrieved successfully"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/devices [get]
+func (h *DeviceHandler) GetVehicleDevices(c *gin.Context) {
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+
+	bindings, err := h.deviceService.GetVehicleDevices(vehicleID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve vehicle devices")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Vehicle devices retrieved successfully", bindings)
+}
+
+// handleError maps device service errors to HTTP responses
+func (h *DeviceHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrDeviceNotFound),
+		errors.Is(err, service.ErrVehicleNotFound),
+		errors.Is(err, service.ErrDeviceBindingNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrDeviceExists):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case errors.Is(err, service.ErrInvalidDeviceBinding), isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
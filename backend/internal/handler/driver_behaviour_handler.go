@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// DriverBehaviourHandler handles driver behaviour scoring HTTP requests
+type DriverBehaviourHandler struct {
+	behaviourService *service.DriverBehaviourService
+	logger           *logrus.Logger
+}
+
+// NewDriverBehaviourHandler creates a new driver behaviour handler
+func NewDriverBehaviourHandler(behaviourService *service.DriverBehaviourService, logger *logrus.Logger) *DriverBehaviourHandler {
+	return &DriverBehaviourHandler{
+		behaviourService: behaviourService,
+		logger:           logger,
+	}
+}
+
+// GetHistory returns the weekly behaviour of a driver
+// @Summary Get driver behaviour history
+// @Description Weekly metrics and scores for the weeks touching the range, 12 weeks by default.
+// @Description Drivers may only view their own history.
+// @Tags drivers
+// @Produce json
+// @Param id path int true "Driver user ID"
+// @Param from query string false "Range start (RFC3339)"
+// @Param to query string false "Range end (RFC3339)"
+// @Success 200 {object} response.Response "Driver behaviour retrieved successfully"
+// @Failure 403 {object} response.Response "Not the driver's own history"
+// @Failure 404 {object} response.Response "Driver not found"
+// @Router /drivers/{id}/behaviour [get]
+func (h *DriverBehaviourHandler) GetHistory(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	driverID, ok := parseIDParam(c, "id", "driver")
+	if !ok {
+		return
+	}
+	from, to, ok := parseTimeRange(c, time.Time{}, time.Time{})
+	if !ok {
+		return
+	}
+
+	history, err := h.behaviourService.GetHistory(viewer, driverID, optionalTime(from), optionalTime(to))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve driver behaviour")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Driver behaviour retrieved successfully", history)
+}
+
+// ListTrips lists the scored trips of a driver
+// @Summary List driver trip behaviour
+// @Description Per-trip metrics and scores, newest first. Defaults to the last 30 days.
+// @Tags drivers
+// @Produce json
+// @Param id path int true "Driver user ID"
+// @Param from query string false "Range start (RFC3339)"
+// @Param to query string false "Range end (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Driver trips retrieved successfully"
+// @Failure 403 {object} response.Response "Not the driver's own trips"
+// @Failure 404 {object} response.Response "Driver not found"
+// @Router /drivers/{id}/behaviour/trips [get]
+func (h *DriverBehaviourHandler) ListTrips(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	driverID, ok := parseIDParam(c, "id", "driver")
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	from, to, ok := parseTimeRange(c, now.Add(-30*24*time.Hour), now)
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	trips, err := h.behaviourService.ListTrips(viewer, driverID, from, to, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve driver trips")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Driver trips retrieved successfully", trips)
+}
+
+// GetLeaderboard ranks drivers by weekly score
+// @Summary Get weekly driver leaderboard
+// @Description Drivers below the minimum weekly distance are not ranked
+// @Tags drivers
+// @Produce json
+// @Param week query string false "Any day of the week (YYYY-MM-DD), defaults to the current week"
+// @Success 200 {object} response.Response "Leaderboard retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid week"
+// @Router /drivers/behaviour/leaderboard [get]
+func (h *DriverBehaviourHandler) GetLeaderboard(c *gin.Context) {
+	var day *time.Time
+	if raw := c.Query("week"); raw != "" {
+		value, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid week", "week must be a date in YYYY-MM-DD format")
+			return
+		}
+		day = &value
+	}
+
+	leaderboard, err := h.behaviourService.GetLeaderboard(day)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve leaderboard")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Leaderboard retrieved successfully", leaderboard)
+}
+
+// GetSettings returns the scoring settings
+// @Summary Get driver behaviour settings
+// @Tags drivers
+// @Produce json
+// @Success 200 {object} response.Response "Settings retrieved successfully"
+// @Router /drivers/behaviour/settings [get]
+func (h *DriverBehaviourHandler) GetSettings(c *gin.Context) {
+	settings, err := h.behaviourService.GetSettings()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve settings")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Settings retrieved successfully", settings)
+}
+
+// UpdateSettings replaces the scoring settings
+// @Summary Update driver behaviour settings
+// @Description Trips are scored with the settings current when they complete; rebuild trips to rescore history
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param request body service.DriverBehaviourSettingsRequest true "Scoring settings"
+// @Success 200 {object} response.Response "Settings updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Router /drivers/behaviour/settings [put]
+func (h *DriverBehaviourHandler) UpdateSettings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.DriverBehaviourSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	settings, err := h.behaviourService.UpdateSettings(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update settings")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Settings updated successfully", settings)
+}
+
+// optionalTime returns nil for the zero time
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// handleError maps driver behaviour service errors to HTTP responses
+func (h *DriverBehaviourHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrDriverNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrDriverAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		response.Error(c, http.StatusBadRequest, "Invalid time range", err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// DTCHandler handles diagnostic trouble code HTTP requests
+type DTCHandler struct {
+	dtcService *service.DTCService
+	logger     *logrus.Logger
+}
+
+// NewDTCHandler creates a new DTC handler
+func NewDTCHandler(dtcService *service.DTCService, logger *logrus.Logger) *DTCHandler {
+	return &DTCHandler{
+		dtcService: dtcService,
+		logger:     logger,
+	}
+}
+
+// Report accepts the active diagnostic trouble codes of an authenticated device
+// @Summary Report diagnostic trouble codes
+// @Description The report is the full set of active codes; codes missing from it are resolved.
+// @Description Critical codes open an emergency or repair work order, once per vehicle and code.
+// @Tags diagnostics
+// @Accept json
+// @Produce json
+// @Param X-Device-ID header string true "Device identifier"
+// @Param request body service.DTCReportRequest true "Active codes"
+// @Success 200 {object} response.Response "DTC report processed"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 401 {object} response.Response "Invalid device credentials"
+// @Failure 409 {object} response.Response "Device not bound to a vehicle"
+// @Router /telematics/dtc [post]
+func (h *DTCHandler) Report(c *gin.Context) {
+	device, ok := currentDevice(c)
+	if !ok {
+		return
+	}
+
+	var req service.DTCReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	result, err := h.dtcService.Report(device, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to process DTC report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "DTC report processed", result)
+}
+
+// ListByVehicle lists the diagnostic trouble codes of a vehicle
+// @Summary List vehicle diagnostic trouble codes
+// @Description Active codes first, then resolved codes, most recently seen first
+// @Tags diagnostics
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param active query bool false "Filter by active status"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "DTC codes retrieved successfully"
+// @Failure 403 {object} response.Response "Vehicle not assigned to driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/dtc-codes [get]
+func (h *DTCHandler) ListByVehicle(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	var active *bool
+	if raw := c.Query("active"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.ValidationError(c, "Invalid active filter", "active must be true or false")
+			return
+		}
+		active = &value
+	}
+	page, limit, offset := parsePagination(c)
+
+	codes, err := h.dtcService.ListByVehicle(viewer, vehicleID, active, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve DTC codes")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "DTC codes retrieved successfully", codes)
+}
+
+// Describe looks up a code in the dictionary and classifies it
+// @Summary Describe diagnostic trouble code
+// @Description Dictionary description, vehicle system and the severity the active rules assign
+// @Tags diagnostics
+// @Produce json
+// @Param code path string true "Code, e.g. P0301"
+// @Success 200 {object} response.Response "DTC definition retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid code"
+// @Router /dtc/codes/{code} [get]
+func (h *DTCHandler) Describe(c *gin.Context) {
+	definition, err := h.dtcService.Describe(c.Param("code"))
+	if err != nil {
+		h.handleError(c, err, "Failed to describe DTC code")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "DTC definition retrieved successfully", definition)
+}
+
+// CreateRule creates a severity rule
+// @Summary Create DTC severity rule
+// @Description Patterns match a code exactly or, ending in '*', by prefix; the most specific active rule wins
+// @Tags diagnostics
+// @Accept json
+// @Produce json
+// @Param request body service.DTCSeverityRuleRequest true "Severity rule"
+// @Success 201 {object} response.Response "Severity rule created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 409 {object} response.Response "Pattern already exists"
+// @Router /dtc/severity-rules [post]
+func (h *DTCHandler) CreateRule(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.DTCSeverityRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.dtcService.CreateRule(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create severity rule")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Severity rule created successfully", rule)
+}
+
+// ListRules lists the severity rules
+// @Summary List DTC severity rules
+// @Tags diagnostics
+// @Produce json
+// @Success 200 {object} response.Response "Severity rules retrieved successfully"
+// @Router /dtc/severity-rules [get]
+func (h *DTCHandler) ListRules(c *gin.Context) {
+	rules, err := h.dtcService.ListRules()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve severity rules")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Severity rules retrieved successfully", rules)
+}
+
+// UpdateRule replaces a severity rule
+// @Summary Update DTC severity rule
+// @Description Active codes pick up the new severity on their next report
+// @Tags diagnostics
+// @Accept json
+// @Produce json
+// @Param id path int true "Severity rule ID"
+// @Param request body service.DTCSeverityRuleRequest true "Severity rule"
+// @Success 200 {object} response.Response "Severity rule updated successfully"
+// @Failure 404 {object} response.Response "Severity rule not found"
+// @Failure 409 {object} response.Response "Pattern already exists"
+// @Router /dtc/severity-rules/{id} [put]
+func (h *DTCHandler) UpdateRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "severity rule")
+	if !ok {
+		return
+	}
+
+	var req service.DTCSeverityRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.dtcService.UpdateRule(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update severity rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Severity rule updated successfully", rule)
+}
+
+// DeleteRule deletes a severity rule
+// @Summary Delete DTC severity rule
+// @Tags diagnostics
+// @Produce json
+// @Param id path int true "Severity rule ID"
+// @Success 200 {object} response.Response "Severity rule deleted successfully"
+// @Failure 404 {object} response.Response "Severity rule not found"
+// @Router /dtc/severity-rules/{id} [delete]
+func (h *DTCHandler) DeleteRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "severity rule")
+	if !ok {
+		return
+	}
+
+	if err := h.dtcService.DeleteRule(id); err != nil {
+		h.handleError(c, err, "Failed to delete severity rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Severity rule deleted successfully", nil)
+}
+
+// handleError maps DTC service errors to HTTP responses
+func (h *DTCHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrDTCRuleNotFound), errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrDTCRuleExists), errors.Is(err, service.ErrDeviceBindingNotFound):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// EstimateHandler handles work order estimate and customer approval HTTP requests
+type EstimateHandler struct {
+	estimateService *service.EstimateService
+	logger          *logrus.Logger
+}
+
+// NewEstimateHandler creates a new estimate handler
+func NewEstimateHandler(estimateService *service.EstimateService, logger *logrus.Logger) *EstimateHandler {
+	return &EstimateHandler{
+		estimateService: estimateService,
+		logger:          logger,
+	}
+}
+
+// List lists the estimates of a work order
+// @Summary List work order estimates
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Estimates retrieved successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id}/estimates [get]
+func (h *EstimateHandler) List(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	estimates, err := h.estimateService.List(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve estimates")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Estimates retrieved successfully", estimates)
+}
+
+// GetByID returns an estimate of a work order
+// @Summary Get work order estimate
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param estimateId path int true "Estimate ID"
+// @Success 200 {object} response.Response "Estimate retrieved successfully"
+// @Failure 404 {object} response.Response "Estimate not found"
+// @Router /workorders/{id}/estimates/{estimateId} [get]
+func (h *EstimateHandler) GetByID(c *gin.Context) {
+	viewer, id, estimateID, ok := parseEstimateParams(c)
+	if !ok {
+		return
+	}
+
+	estimate, err := h.estimateService.Get(viewer, id, estimateID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve estimate")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Estimate retrieved successfully", estimate)
+}
+
+// Create drafts a new estimate version
+// @Summary Create work order estimate
+// @Description Drafts the next version of the work order's estimate. Part lines are priced from the
+// @Description inventory item and labor lines from the mechanic's hourly rate unless a unit_price is
+// @Description given. Earlier versions in draft or waiting for the customer are superseded.
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.EstimateRequest true "Estimate"
+// @Success 201 {object} response.Response "Estimate created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Work order is closed"
+// @Router /workorders/{id}/estimates [post]
+func (h *EstimateHandler) Create(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.EstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	estimate, err := h.estimateService.Create(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create estimate")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Estimate created successfully", estimate)
+}
+
+// Update replaces the lines of a draft estimate
+// @Summary Update work order estimate
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param estimateId path int true "Estimate ID"
+// @Param request body service.EstimateRequest true "Estimate"
+// @Success 200 {object} response.Response "Estimate updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Estimate not found"
+// @Failure 409 {object} response.Response "Estimate is not a draft"
+// @Router /workorders/{id}/estimates/{estimateId} [put]
+func (h *EstimateHandler) Update(c *gin.Context) {
+	viewer, id, estimateID, ok := parseEstimateParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.EstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	estimate, err := h.estimateService.Update(viewer, id, estimateID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update estimate")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Estimate updated successfully", estimate)
+}
+
+// Send issues the customer approval link of an estimate
+// @Summary Send work order estimate
+// @Description Returns the approval link to share with the customer. Sending again replaces the link.
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param estimateId path int true "Estimate ID"
+// @Success 200 {object} response.Response "Estimate sent successfully"
+// @Failure 404 {object} response.Response "Estimate not found"
+// @Failure 409 {object} response.Response "Estimate already answered or superseded"
+// @Router /workorders/{id}/estimates/{estimateId}/send [put]
+func (h *EstimateHandler) Send(c *gin.Context) {
+	viewer, id, estimateID, ok := parseEstimateParams(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.estimateService.Send(viewer, id, estimateID)
+	if err != nil {
+		h.handleError(c, err, "Failed to send estimate")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Estimate sent successfully", link)
+}
+
+// GetForCustomer returns the estimate behind an approval link
+// @Summary Get estimate for approval
+// @Description Public endpoint for the customer; the token in the link authorises the request
+// @Tags estimate-approvals
+// @Produce json
+// @Param token path string true "Approval token"
+// @Success 200 {object} response.Response "Estimate retrieved successfully"
+// @Failure 404 {object} response.Response "Estimate not found"
+// @Failure 410 {object} response.Response "Approval link expired"
+// @Router /estimate-approvals/{token} [get]
+func (h *EstimateHandler) GetForCustomer(c *gin.Context) {
+	estimate, err := h.estimateService.GetForCustomer(c.Param("token"))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve estimate")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Estimate retrieved successfully", estimate)
+}
+
+// Respond records the customer's signed decision on an estimate
+// @Summary Approve or decline estimate
+// @Description Public endpoint for the customer. Every line must be approved or declined; the
+// @Description signature, signer name, time, IP address and user agent are recorded.
+// @Tags estimate-approvals
+// @Accept json
+// @Produce json
+// @Param token path string true "Approval token"
+// @Param request body service.EstimateResponseRequest true "Decisions and signature"
+// @Success 200 {object} response.Response "Estimate answered successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Estimate not found"
+// @Failure 409 {object} response.Response "Estimate already answered"
+// @Failure 410 {object} response.Response "Approval link expired"
+// @Router /estimate-approvals/{token} [post]
+func (h *EstimateHandler) Respond(c *gin.Context) {
+	var req service.EstimateResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	estimate, err := h.estimateService.Respond(c.Param("token"), &req, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		h.handleError(c, err, "Failed to answer estimate")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Estimate answered successfully", estimate)
+}
+
+// parseEstimateParams reads the current viewer and the work order and estimate path parameters
+func parseEstimateParams(c *gin.Context) (service.Viewer, uint, uint, bool) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	estimateID, ok := parseIDParam(c, "estimateId", "estimate")
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	return viewer, id, estimateID, true
+}
+
+// handleError maps estimate service errors to HTTP responses
+func (h *EstimateHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrWorkOrderNotFound),
+		errors.Is(err, service.ErrEstimateNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrWorkOrderAccessDenied),
+		errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrEstimateLinkExpired):
+		response.Error(c, http.StatusGone, message, err.Error())
+	case errors.Is(err, service.ErrWorkOrderClosed),
+		errors.Is(err, service.ErrEstimateNotDraft),
+		errors.Is(err, service.ErrEstimateNotSendable),
+		errors.Is(err, service.ErrEstimateAnswered):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,471 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// FuelHandler handles fuel analytics HTTP requests
+type FuelHandler struct {
+	fuelService *service.FuelService
+	logger      *logrus.Logger
+}
+
+// NewFuelHandler creates a new fuel handler
+func NewFuelHandler(fuelService *service.FuelService, logger *logrus.Logger) *FuelHandler {
+	return &FuelHandler{
+		fuelService: fuelService,
+		logger:      logger,
+	}
+}
+
+// GetProfile returns the fuel profile of a vehicle
+// @Summary Get vehicle fuel profile
+// @Tags fuel
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} response.Response "Fuel profile retrieved successfully"
+// @Failure 404 {object} response.Response "Vehicle or fuel profile not found"
+// @Router /vehicles/{id}/fuel-profile [get]
+func (h *FuelHandler) GetProfile(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+
+	profile, err := h.fuelService.GetProfile(viewer, vehicleID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve fuel profile")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel profile retrieved successfully", profile)
+}
+
+// SaveProfile sets the tank capacity of a vehicle
+// @Summary Set vehicle fuel profile
+// @Description Litres of past fuel events and trips are recalculated and recent receipts reconciled again
+// @Tags fuel
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param request body service.FuelProfileRequest true "Fuel profile"
+// @Success 200 {object} response.Response "Fuel profile saved successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/fuel-profile [put]
+func (h *FuelHandler) SaveProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+
+	var req service.FuelProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	profile, err := h.fuelService.SaveProfile(vehicleID, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to save fuel profile")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel profile saved successfully", profile)
+}
+
+// ListEvents lists the detected refuels and drops of a vehicle
+// @Summary List vehicle fuel events
+// @Description Refuels and sudden drops starting in the range, newest first. Defaults to the last 30 days.
+// @Tags fuel
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param type query string false "Event type (refuel, drop)"
+// @Param from query string false "Range start (RFC3339)"
+// @Param to query string false "Range end (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Fuel events retrieved successfully"
+// @Failure 403 {object} response.Response "Vehicle not assigned to the driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/fuel-events [get]
+func (h *FuelHandler) ListEvents(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	from, to, ok := parseTimeRange(c, now.Add(-30*24*time.Hour), now)
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	events, err := h.fuelService.ListEvents(viewer, vehicleID, c.Query("type"), from, to, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve fuel events")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel events retrieved successfully", events)
+}
+
+// GetConsumption returns the per-trip fuel consumption of a vehicle
+// @Summary Get vehicle fuel consumption
+// @Description Fuel used per trip with L/100km, and totals for the range. Defaults to the last 30 days.
+// @Tags fuel
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param from query string false "Range start (RFC3339)"
+// @Param to query string false "Range end (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Fuel consumption retrieved successfully"
+// @Failure 403 {object} response.Response "Vehicle not assigned to the driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/fuel-consumption [get]
+func (h *FuelHandler) GetConsumption(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	from, to, ok := parseTimeRange(c, now.Add(-30*24*time.Hour), now)
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	report, err := h.fuelService.GetConsumption(viewer, vehicleID, from, to, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve fuel consumption")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel consumption retrieved successfully", report)
+}
+
+// CreateReceipt records a fuel purchase
+// @Summary Create fuel receipt
+// @Description The receipt is reconciled against detected refuels; drivers may only enter receipts for assigned vehicles
+// @Tags fuel
+// @Accept json
+// @Produce json
+// @Param request body service.FuelReceiptRequest true "Fuel receipt"
+// @Success 201 {object} response.Response "Fuel receipt created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 403 {object} response.Response "Vehicle not assigned to the driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /fuel/receipts [post]
+func (h *FuelHandler) CreateReceipt(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	var req service.FuelReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	receipt, err := h.fuelService.CreateReceipt(viewer, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create fuel receipt")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Fuel receipt created successfully", receipt)
+}
+
+// ListReceipts lists fuel receipts
+// @Summary List fuel receipts
+// @Description Drivers only see the receipts they entered
+// @Tags fuel
+// @Produce json
+// @Param vehicle_id query int false "Vehicle ID"
+// @Param status query string false "Reconciliation status (pending, matched, mismatch, unmatched, no_data)"
+// @Param from query string false "Filled after (RFC3339)"
+// @Param to query string false "Filled before (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Fuel receipts retrieved successfully"
+// @Router /fuel/receipts [get]
+func (h *FuelHandler) ListReceipts(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseOptionalID(c, "vehicle_id")
+	if !ok {
+		return
+	}
+	from, to, ok := parseTimeRange(c, time.Time{}, time.Time{})
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	filter := interfaces.FuelReceiptFilter{
+		VehicleID: vehicleID,
+		Status:    c.Query("status"),
+		From:      optionalTime(from),
+		To:        optionalTime(to),
+	}
+	receipts, err := h.fuelService.ListReceipts(viewer, filter, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve fuel receipts")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel receipts retrieved successfully", receipts)
+}
+
+// GetReceipt returns a fuel receipt
+// @Summary Get fuel receipt
+// @Tags fuel
+// @Produce json
+// @Param id path int true "Receipt ID"
+// @Success 200 {object} response.Response "Fuel receipt retrieved successfully"
+// @Failure 404 {object} response.Response "Fuel receipt not found"
+// @Router /fuel/receipts/{id} [get]
+func (h *FuelHandler) GetReceipt(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "receipt")
+	if !ok {
+		return
+	}
+
+	receipt, err := h.fuelService.GetReceipt(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve fuel receipt")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel receipt retrieved successfully", receipt)
+}
+
+// UpdateReceipt replaces a fuel receipt
+// @Summary Update fuel receipt
+// @Description The receipt is reconciled again
+// @Tags fuel
+// @Accept json
+// @Produce json
+// @Param id path int true "Receipt ID"
+// @Param request body service.FuelReceiptRequest true "Fuel receipt"
+// @Success 200 {object} response.Response "Fuel receipt updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Fuel receipt or vehicle not found"
+// @Router /fuel/receipts/{id} [put]
+func (h *FuelHandler) UpdateReceipt(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "receipt")
+	if !ok {
+		return
+	}
+
+	var req service.FuelReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	receipt, err := h.fuelService.UpdateReceipt(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update fuel receipt")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel receipt updated successfully", receipt)
+}
+
+// DeleteReceipt deletes a fuel receipt
+// @Summary Delete fuel receipt
+// @Tags fuel
+// @Produce json
+// @Param id path int true "Receipt ID"
+// @Success 200 {object} response.Response "Fuel receipt deleted successfully"
+// @Failure 404 {object} response.Response "Fuel receipt not found"
+// @Router /fuel/receipts/{id} [delete]
+func (h *FuelHandler) DeleteReceipt(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "receipt")
+	if !ok {
+		return
+	}
+
+	if err := h.fuelService.DeleteReceipt(viewer, id); err != nil {
+		h.handleError(c, err, "Failed to delete fuel receipt")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel receipt deleted successfully", nil)
+}
+
+// ListAnomalies lists fuel anomalies
+// @Summary List fuel anomalies
+// @Tags fuel
+// @Produce json
+// @Param vehicle_id query int false "Vehicle ID"
+// @Param type query string false "Anomaly type (sudden_drop, receipt_mismatch, unmatched_receipt)"
+// @Param status query string false "Review status (open, confirmed, dismissed)"
+// @Param from query string false "Occurred after (RFC3339)"
+// @Param to query string false "Occurred before (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Fuel anomalies retrieved successfully"
+// @Router /fuel/anomalies [get]
+func (h *FuelHandler) ListAnomalies(c *gin.Context) {
+	vehicleID, ok := parseOptionalID(c, "vehicle_id")
+	if !ok {
+		return
+	}
+	from, to, ok := parseTimeRange(c, time.Time{}, time.Time{})
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	filter := interfaces.FuelAnomalyFilter{
+		VehicleID: vehicleID,
+		Type:      c.Query("type"),
+		Status:    c.Query("status"),
+		From:      optionalTime(from),
+		To:        optionalTime(to),
+	}
+	anomalies, err := h.fuelService.ListAnomalies(filter, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve fuel anomalies")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel anomalies retrieved successfully", anomalies)
+}
+
+// GetAnomaly returns a fuel anomaly
+// @Summary Get fuel anomaly
+// @Tags fuel
+// @Produce json
+// @Param id path int true "Anomaly ID"
+// @Success 200 {object} response.Response "Fuel anomaly retrieved successfully"
+// @Failure 404 {object} response.Response "Fuel anomaly not found"
+// @Router /fuel/anomalies/{id} [get]
+func (h *FuelHandler) GetAnomaly(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "anomaly")
+	if !ok {
+		return
+	}
+
+	anomaly, err := h.fuelService.GetAnomaly(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve fuel anomaly")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel anomaly retrieved successfully", anomaly)
+}
+
+// ReviewAnomaly confirms or dismisses a fuel anomaly
+// @Summary Review fuel anomaly
+// @Tags fuel
+// @Accept json
+// @Produce json
+// @Param id path int true "Anomaly ID"
+// @Param request body service.FuelAnomalyReviewRequest true "Review outcome"
+// @Success 200 {object} response.Response "Fuel anomaly reviewed successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Fuel anomaly not found"
+// @Router /fuel/anomalies/{id}/review [put]
+func (h *FuelHandler) ReviewAnomaly(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "anomaly")
+	if !ok {
+		return
+	}
+
+	var req service.FuelAnomalyReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	anomaly, err := h.fuelService.ReviewAnomaly(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to review fuel anomaly")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fuel anomaly reviewed successfully", anomaly)
+}
+
+// parseOptionalID reads an optional numeric query parameter, 0 when absent
+func parseOptionalID(c *gin.Context, name string) (uint, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid "+name, err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleError maps fuel service errors to HTTP responses
+func (h *FuelHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrFuelProfileNotFound),
+		errors.Is(err, service.ErrFuelReceiptNotFound),
+		errors.Is(err, service.ErrFuelAnomalyNotFound),
+		errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		response.Error(c, http.StatusBadRequest, "Invalid time range", err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// GeofenceHandler handles geofence HTTP requests
+type GeofenceHandler struct {
+	geofenceService *service.GeofenceService
+	logger          *logrus.Logger
+}
+
+// NewGeofenceHandler creates a new geofence handler
+func NewGeofenceHandler(geofenceService *service.GeofenceService, logger *logrus.Logger) *GeofenceHandler {
+	return &GeofenceHandler{
+		geofenceService: geofenceService,
+		logger:          logger,
+	}
+}
+
+// Create creates a geofence
+// @Summary Create geofence
+// @Description Circles need a center and radius, polygons at least 3 points. Without vehicle_ids or
+// @Description vehicle_categories the geofence applies to every vehicle. Schedule windows use days
+// @Description 0 (Sunday) to 6 and HH:MM times in the geofence timezone; without a schedule it is always active.
+// @Tags geofences
+// @Accept json
+// @Produce json
+// @Param request body service.GeofenceRequest true "Geofence definition"
+// @Success 201 {object} response.Response "Geofence created successfully"
+// @Failure 400 {object} response.Response "Validation failed"
+// @Router /geofences [post]
+func (h *GeofenceHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.GeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	geofence, err := h.geofenceService.Create(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create geofence")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Geofence created successfully", geofence)
+}
+
+// List lists geofences
+// @Summary List geofences
+// @Tags geofences
+// @Produce json
+// @Param search query string false "Search by name"
+// @Param active query bool false "Only active (true) or inactive (false) geofences"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Geofences retrieved successfully"
+// @Router /geofences [get]
+func (h *GeofenceHandler) List(c *gin.Context) {
+	page, limit, offset := parsePagination(c)
+
+	var active *bool
+	if raw := c.Query("active"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid active filter", err.Error())
+			return
+		}
+		active = &value
+	}
+
+	geofences, err := h.geofenceService.List(c.Query("search"), active, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve geofences")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Geofences retrieved successfully", geofences)
+}
+
+// GetByID retrieves a geofence
+// @Summary Get geofence
+// @Tags geofences
+// @Produce json
+// @Param id path int true "Geofence ID"
+// @Success 200 {object} response.Response "Geofence retrieved successfully"
+// @Failure 404 {object} response.Response "Geofence not found"
+// @Router /geofences/{id} [get]
+func (h *GeofenceHandler) GetByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "geofence")
+	if !ok {
+		return
+	}
+
+	geofence, err := h.geofenceService.GetByID(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve geofence")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Geofence retrieved successfully", geofence)
+}
+
+// Update replaces a geofence definition
+// @Summary Update geofence
+// @Tags geofences
+// @Accept json
+// @Produce json
+// @Param id path int true "Geofence ID"
+// @Param request body service.GeofenceRequest true "Geofence definition"
+// @Success 200 {object} response.Response "Geofence updated successfully"
+// @Failure 404 {object} response.Response "Geofence not found"
+// @Router /geofences/{id} [put]
+func (h *GeofenceHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "geofence")
+	if !ok {
+		return
+	}
+
+	var req service.GeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	geofence, err := h.geofenceService.Update(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update geofence")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Geofence updated successfully", geofence)
+}
+
+// Delete deletes a geofence
+// @Summary Delete geofence
+// @Description Deletes the geofence together with its events and alert rules
+// @Tags geofences
+// @Produce json
+// @Param id path int true "Geofence ID"
+// @Success 200 {object} response.Response "Geofence deleted successfully"
+// @Failure 404 {object} response.Response "Geofence not found"
+// @Router /geofences/{id} [delete]
+func (h *GeofenceHandler) Delete(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "geofence")
+	if !ok {
+		return
+	}
+
+	if err := h.geofenceService.Delete(id); err != nil {
+		h.handleError(c, err, "Failed to delete geofence")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Geofence deleted successfully", nil)
+}
+
+// ListEvents lists geofence events
+// @Summary List geofence events
+// @Tags geofences
+// @Produce json
+// @Param geofence_id query int false "Filter by geofence"
+// @Param vehicle_id query int false "Filter by vehicle"
+// @Param event_type query string false "enter, exit or dwell"
+// @Param from query string false "Range start (RFC3339)"
+// @Param to query string false "Range end (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Geofence events retrieved successfully"
+// @Router /geofences/events [get]
+func (h *GeofenceHandler) ListEvents(c *gin.Context) {
+	filter, ok := parseGeofenceEventFilter(c)
+	if !ok {
+		return
+	}
+	h.listEvents(c, filter)
+}
+
+// ListGeofenceEvents lists the events of one geofence
+// @Summary List events of a geofence
+// @Tags geofences
+// @Produce json
+// @Param id path int true "Geofence ID"
+// @Param vehicle_id query int false "Filter by vehicle"
+// @Param event_type query string false "enter, exit or dwell"
+// @Param from query string false "Range start (RFC3339)"
+// @Param to query string false "Range end (RFC3339)"
+// @Success 200 {object} response.Response "Geofence events retrieved successfully"
+// @Router /geofences/{id}/events [get]
+func (h *GeofenceHandler) ListGeofenceEvents(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "geofence")
+	if !ok {
+		return
+	}
+	filter, ok := parseGeofenceEventFilter(c)
+	if !ok {
+		return
+	}
+	filter.GeofenceID = id
+	h.listEvents(c, filter)
+}
+
+func (h *GeofenceHandler) listEvents(c *gin.Context, filter interfaces.GeofenceEventFilter) {
+	page, limit, offset := parsePagination(c)
+
+	events, err := h.geofenceService.ListEvents(filter, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve geofence events")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Geofence events retrieved successfully", events)
+}
+
+// CreateAlertRule adds an alert rule to a geofence
+// @Summary Create geofence alert rule
+// @Description Notifies the recipients whenever the geofence raises an event of the given type
+// @Tags geofences
+// @Accept json
+// @Produce json
+// @Param id path int true "Geofence ID"
+// @Param request body service.GeofenceAlertRuleRequest true "Alert rule"
+// @Success 201 {object} response.Response "Alert rule created successfully"
+// @Failure 404 {object} response.Response "Geofence not found"
+// @Router /geofences/{id}/alert-rules [post]
+func (h *GeofenceHandler) CreateAlertRule(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "geofence")
+	if !ok {
+		return
+	}
+
+	var req service.GeofenceAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.geofenceService.CreateAlertRule(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create alert rule")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Alert rule created successfully", rule)
+}
+
+// ListAlertRules lists the alert rules of a geofence
+// @Summary List geofence alert rules
+// @Tags geofences
+// @Produce json
+// @Param id path int true "Geofence ID"
+// @Success 200 {object} response.Response "Alert rules retrieved successfully"
+// @Failure 404 {object} response.Response "Geofence not found"
+// @Router /geofences/{id}/alert-rules [get]
+func (h *GeofenceHandler) ListAlertRules(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "geofence")
+	if !ok {
+		return
+	}
+
+	rules, err := h.geofenceService.ListAlertRules(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve alert rules")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Alert rules retrieved successfully", rules)
+}
+
+// UpdateAlertRule replaces an alert rule
+// @Summary Update geofence alert rule
+// @Tags geofences
+// @Accept json
+// @Produce json
+// @Param ruleId path int true "Alert rule ID"
+// @Param request body service.GeofenceAlertRuleRequest true "Alert rule"
+// @Success 200 {object} response.Response "Alert rule updated successfully"
+// @Failure 404 {object} response.Response "Alert rule not found"
+// @Router /geofences/alert-rules/{ruleId} [put]
+func (h *GeofenceHandler) UpdateAlertRule(c *gin.Context) {
+	ruleID, ok := parseIDParam(c, "ruleId", "alert rule")
+	if !ok {
+		return
+	}
+
+	var req service.GeofenceAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.geofenceService.UpdateAlertRule(ruleID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update alert rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Alert rule updated successfully", rule)
+}
+
+// DeleteAlertRule deletes an alert rule
+// @Summary Delete geofence alert rule
+// @Tags geofences
+// @Produce json
+// @Param ruleId path int true "Alert rule ID"
+// @Success 200 {object} response.Response "Alert rule deleted successfully"
+// @Failure 404 {object} response.Response "Alert rule not found"
+// @Router /geofences/alert-rules/{ruleId} [delete]
+func (h *GeofenceHandler) DeleteAlertRule(c *gin.Context) {
+	ruleID, ok := parseIDParam(c, "ruleId", "alert rule")
+	if !ok {
+		return
+	}
+
+	if err := h.geofenceService.DeleteAlertRule(ruleID); err != nil {
+		h.handleError(c, err, "Failed to delete alert rule")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Alert rule deleted successfully", nil)
+}
+
+// parseGeofenceEventFilter reads the event filter query parameters
+func parseGeofenceEventFilter(c *gin.Context) (interfaces.GeofenceEventFilter, bool) {
+	filter := interfaces.GeofenceEventFilter{EventType: c.Query("event_type")}
+
+	for name, target := range map[string]*uint{"geofence_id": &filter.GeofenceID, "vehicle_id": &filter.VehicleID} {
+		if raw := c.Query(name); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				response.Error(c, http.StatusBadRequest, "Invalid "+name, err.Error())
+				return filter, false
+			}
+			*target = uint(id)
+		}
+	}
+
+	from, to, ok := parseTimeRange(c, time.Time{}, time.Time{})
+	if !ok {
+		return filter, false
+	}
+	if !from.IsZero() {
+		filter.From = &from
+	}
+	if !to.IsZero() {
+		filter.To = &to
+	}
+	return filter, true
+}
+
+// handleError maps geofence service errors to HTTP responses
+func (h *GeofenceHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrGeofenceNotFound),
+		errors.Is(err, service.ErrGeofenceAlertRuleNotFound),
+		errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// currentUserID returns the authenticated user ID, writing an error response when it is missing
+func currentUserID(c *gin.Context) (uint, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Authentication required", "User not authenticated")
+		return 0, false
+	}
+
+	id, ok := userID.(uint)
+	if !ok {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", "Invalid user ID format")
+		return 0, false
+	}
+	return id, true
+}
+
+// currentDevice returns the device authenticated by the device middleware, writing an error response when it is missing
+func currentDevice(c *gin.Context) (*domain.TelematicsDevice, bool) {
+	value, exists := c.Get("device")
+	device, ok := value.(*domain.TelematicsDevice)
+	if !exists || !ok {
+		response.Unauthorized(c, "Device not authenticated")
+		return nil, false
+	}
+	return device, true
+}
+
+// currentUserRole returns the role name of the authenticated user
+func currentUserRole(c *gin.Context) string {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return roleStr
+}
+
+// currentViewer returns the authenticated user as a service viewer, writing an error response when it is missing
+func currentViewer(c *gin.Context) (service.Viewer, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return service.Viewer{}, false
+	}
+	username, _ := c.Get("username")
+	usernameStr, _ := username.(string)
+	return service.Viewer{UserID: userID, Username: usernameStr, Role: currentUserRole(c)}, true
+}
+
+// parseIDParam parses a numeric path parameter, writing an error response when it is invalid
+func parseIDParam(c *gin.Context, name, label string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid "+label+" ID", err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parsePagination reads page and limit query parameters and returns page, limit and offset
+func parsePagination(c *gin.Context) (int, int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return page, limit, (page - 1) * limit
+}
+
+// isValidationError reports whether a service error was caused by invalid input
+func isValidationError(err error) bool {
+	return strings.HasPrefix(err.Error(), "validation failed")
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// InspectionHandler handles vehicle inspection HTTP requests
+type InspectionHandler struct {
+	inspectionService *service.InspectionService
+	logger            *logrus.Logger
+}
+
+// NewInspectionHandler creates a new inspection handler
+func NewInspectionHandler(inspectionService *service.InspectionService, logger *logrus.Logger) *InspectionHandler {
+	return &InspectionHandler{
+		inspectionService: inspectionService,
+		logger:            logger,
+	}
+}
+
+// CreateTemplate creates a new inspection checklist template
+// @Summary Create inspection template
+// @Description Creates version 1 of a vehicle inspection checklist template
+// @Tags inspections
+// @Accept json
+// @Produce json
+// @Param request body service.InspectionTemplateRequest true "Template definition"
+// @Success 201 {object} response.Response "Template created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 409 {object} response.Response "Template code already exists"
+// @Router /inspections/templates [post]
+func (h *InspectionHandler) CreateTemplate(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.InspectionTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind inspection template request")
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	template, err := h.inspectionService.CreateTemplate(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create inspection template")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Inspection template created successfully", template)
+}
+
+// PublishTemplateVersion publishes a new version of an existing template
+// @Summary Publish new template version
+// @Description Creates a new version of a template and retires the previous one
+// @Tags inspections
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param request body service.InspectionTemplateRequest true "Template definition"
+// @Success 201 {object} response.Response "Template version published"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Template not found"
+// @Router /inspections/templates/{id} [put]
+func (h *InspectionHandler) PublishTemplateVersion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "template")
+	if !ok {
+		return
+	}
+
+	var req service.InspectionTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind inspection template request")
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	template, err := h.inspectionService.PublishNewVersion(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to publish template version")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Inspection template version published successfully", template)
+}
+
+// ListTemplates lists inspection templates
+// @Summary List inspection templates
+// @Description Lists active template versions, or all versions with all=true
+// @Tags inspections
+// @Produce json
+// @Param all query bool false "Include retired versions"
+// @Success 200 {object} response.Response "Templates retrieved successfully"
+// @Router /inspections/templates [get]
+func (h *InspectionHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.inspectionService.ListTemplates(c.Query("all") != "true")
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to retrieve inspection templates")
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve inspection templates", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Inspection templates retrieved successfully", templates)
+}
+
+// GetTemplate retrieves a template version with its checklist
+// @Summary Get inspection template
+// @Tags inspections
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} response.Response "Template retrieved successfully"
+// @Failure 404 {object} response.Response "Template not found"
+// @Router /inspections/templates/{id} [get]
+func (h *InspectionHandler) GetTemplate(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "template")
+	if !ok {
+		return
+	}
+
+	template, err := h.inspectionService.GetTemplate(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve inspection template")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Inspection template retrieved successfully", template)
+}
+
+// GetTemplateVersions lists all versions of a template
+// @Summary List template versions
+// @Tags inspections
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} response.Response "Template versions retrieved successfully"
+// @Failure 404 {object} response.Response "Template not found"
+// @Router /inspections/templates/{id}/versions [get]
+func (h *InspectionHandler) GetTemplateVersions(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "template")
+	if !ok {
+		return
+	}
+
+	versions, err := h.inspectionService.GetTemplateVersions(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve template versions")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Inspection template versions retrieved successfully", versions)
+}
+
+// DeactivateTemplate retires a template version
+// @Summary Deactivate inspection template
+// @Tags inspections
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} response.Response "Template deactivated"
+// @Failure 404 {object} response.Response "Template not found"
+// @Router /inspections/templates/{id} [delete]
+func (h *InspectionHandler) DeactivateTemplate(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "template")
+	if !ok {
+		return
+	}
+
+	if err := h.inspectionService.DeactivateTemplate(id); err != nil {
+		h.handleError(c, err, "Failed to deactivate inspection template")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Inspection template deactivated successfully", nil)
+}
+
+// Submit records a completed inspection
+// @Summary Submit inspection
+// @Description Submits inspection results; failed critical items open a repair work order
+// @Tags inspections
+// @Accept json
+// @Produce json
+// @Param request body service.SubmitInspectionRequest true "Inspection results"
+// @Success 201 {object} response.Response "Inspection submitted successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Vehicle or template not found"
+// @Router /inspections [post]
+func (h *InspectionHandler) Submit(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SubmitInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to bind inspection submission")
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	inspection, err := h.inspectionService.Submit(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to submit inspection")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Inspection submitted successfully", inspection)
+}
+
+// GetByID retrieves a submitted inspection
+// @Summary Get inspection
+// @Tags inspections
+// @Produce json
+// @Param id path int true "Inspection ID"
+// @Success 200 {object} response.Response "Inspection retrieved successfully"
+// @Failure 404 {object} response.Response "Inspection not found"
+// @Router /inspections/{id} [get]
+func (h *InspectionHandler) GetByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "inspection")
+	if !ok {
+		return
+	}
+
+	inspection, err := h.inspectionService.GetInspection(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve inspection")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Inspection retrieved successfully", inspection)
+}
+
+// GetVehicleHistory retrieves the inspection history of a vehicle
+// @Summary Get vehicle inspection history
+// @Tags inspections
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Inspection history retrieved successfully"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/inspections [get]
+func (h *InspectionHandler) GetVehicleHistory(c *gin.Context) {
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+
+	page, limit, offset := parsePagination(c)
+
+	inspections, total, err := h.inspectionService.GetVehicleHistory(vehicleID, offset, limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve inspection history")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Inspection history retrieved successfully", gin.H{
+		"inspections": inspections,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"count": len(inspections),
+			"total": total,
+		},
+	})
+}
+
+// handleError maps inspection service errors to HTTP responses
+func (h *InspectionHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInspectionTemplateNotFound),
+		errors.Is(err, service.ErrInspectionNotFound),
+		errors.Is(err, service.ErrVehicleNotFound),
+		errors.Is(err, service.ErrWorkOrderNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrInspectionTemplateExists):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case errors.Is(err, service.ErrInvalidInspection), isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
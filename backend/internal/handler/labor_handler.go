@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// LaborHandler handles work order task, time clock and labor HTTP requests
+type LaborHandler struct {
+	laborService *service.LaborService
+	logger       *logrus.Logger
+}
+
+// NewLaborHandler creates a new labor handler
+func NewLaborHandler(laborService *service.LaborService, logger *logrus.Logger) *LaborHandler {
+	return &LaborHandler{
+		laborService: laborService,
+		logger:       logger,
+	}
+}
+
+// CreateTask adds a task to a work order
+// @Summary Create work order task
+// @Description A task that performs a catalogued labor operation takes its description and standard hours
+// @Description from the operation, which must apply to the work order's vehicle.
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.WorkOrderTaskRequest true "Task"
+// @Success 201 {object} response.Response "Work order task created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Work order is closed"
+// @Router /workorders/{id}/tasks [post]
+func (h *LaborHandler) CreateTask(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.WorkOrderTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	task, err := h.laborService.CreateTask(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create work order task")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Work order task created successfully", task)
+}
+
+// ListTasks lists the tasks of a work order
+// @Summary List work order tasks
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Work order tasks retrieved successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id}/tasks [get]
+func (h *LaborHandler) ListTasks(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	tasks, err := h.laborService.ListTasks(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve work order tasks")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order tasks retrieved successfully", tasks)
+}
+
+// ClockIn starts the current mechanic's clock on a task
+// @Summary Clock in on task
+// @Description Starts or resumes the current mechanic's clock on a task of a work order in progress.
+// @Description A mechanic can only be clocked in on one task at a time.
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param taskId path int true "Task ID"
+// @Param request body service.ClockRequest false "Note"
+// @Success 200 {object} response.Response "Clocked in successfully"
+// @Failure 403 {object} response.Response "Not a mechanic assigned to the work order"
+// @Failure 404 {object} response.Response "Work order task not found"
+// @Failure 409 {object} response.Response "Already clocked in, work order not in progress or beyond the approved estimate"
+// @Router /workorders/{id}/tasks/{taskId}/clock-in [put]
+func (h *LaborHandler) ClockIn(c *gin.Context) {
+	viewer, id, taskID, ok := parseTaskParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.ClockRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	entry, err := h.laborService.ClockIn(viewer, id, taskID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to clock in")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Clocked in successfully", entry)
+}
+
+// Pause pauses the current mechanic's clock on a task
+// @Summary Pause task clock
+// @Description The time so far is added to the work order labor; clocking in again resumes
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param taskId path int true "Task ID"
+// @Param request body service.ClockRequest false "Note"
+// @Success 200 {object} response.Response "Clock paused successfully"
+// @Failure 404 {object} response.Response "Work order task not found"
+// @Failure 409 {object} response.Response "Not clocked in on the task"
+// @Router /workorders/{id}/tasks/{taskId}/pause [put]
+func (h *LaborHandler) Pause(c *gin.Context) {
+	viewer, id, taskID, ok := parseTaskParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.ClockRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	entry, err := h.laborService.Pause(viewer, id, taskID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to pause clock")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Clock paused successfully", entry)
+}
+
+// ClockOut stops the current mechanic's clock on a task
+// @Summary Clock out of task
+// @Description The time is added to the work order labor at the mechanic's hourly rate and the work
+// @Description order's actual hours and cost are updated. complete_task marks the task completed.
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param taskId path int true "Task ID"
+// @Param request body service.ClockOutRequest false "Clock out"
+// @Success 200 {object} response.Response "Clocked out successfully"
+// @Failure 404 {object} response.Response "Work order task not found"
+// @Failure 409 {object} response.Response "Not clocked in on the task"
+// @Router /workorders/{id}/tasks/{taskId}/clock-out [put]
+func (h *LaborHandler) ClockOut(c *gin.Context) {
+	viewer, id, taskID, ok := parseTaskParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.ClockOutRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	entry, err := h.laborService.ClockOut(viewer, id, taskID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to clock out")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Clocked out successfully", entry)
+}
+
+// GetLabor returns the tasks, clock entries and labor of a work order
+// @Summary Get work order labor
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Work order labor retrieved successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id}/labor [get]
+func (h *LaborHandler) GetLabor(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	summary, err := h.laborService.GetLabor(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve work order labor")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order labor retrieved successfully", summary)
+}
+
+// ActiveClock returns the running clock of a mechanic
+// @Summary Get mechanic clock
+// @Description The running clock entry, or null when the mechanic is not clocked in. Mechanics can
+// @Description only view their own clock.
+// @Tags mechanics
+// @Produce json
+// @Param id path int true "Mechanic user ID"
+// @Success 200 {object} response.Response "Clock retrieved successfully"
+// @Failure 403 {object} response.Response "Not the current mechanic"
+// @Router /mechanics/{id}/clock [get]
+func (h *LaborHandler) ActiveClock(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "mechanic")
+	if !ok {
+		return
+	}
+
+	entry, err := h.laborService.ActiveClock(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve clock")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Clock retrieved successfully", entry)
+}
+
+// EfficiencyReport compares estimated and recorded hours of completed work orders
+// @Summary Labor efficiency report
+// @Description Work orders completed in the range with their estimated and recorded labor hours, and per
+// @Description mechanic the hours clocked against their share of the estimates. Efficiency is estimated
+// @Description per actual hour, so above 1 beats the estimate.
+// @Tags reports
+// @Produce json
+// @Param from query string false "Completed at or after (RFC3339), defaults to 30 days ago"
+// @Param to query string false "Completed before (RFC3339), defaults to now"
+// @Param mechanic_id query int false "Work orders the mechanic worked on"
+// @Param branch_id query int false "Filter by branch"
+// @Success 200 {object} response.Response "Labor efficiency report retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /reports/labor-efficiency [get]
+func (h *LaborHandler) EfficiencyReport(c *gin.Context) {
+	now := time.Now().UTC()
+	from, to, ok := parseTimeRange(c, now.AddDate(0, 0, -30), now)
+	if !ok {
+		return
+	}
+	mechanicID, ok := parseOptionalID(c, "mechanic_id")
+	if !ok {
+		return
+	}
+	branchID, ok := parseOptionalID(c, "branch_id")
+	if !ok {
+		return
+	}
+
+	report, err := h.laborService.EfficiencyReport(interfaces.LaborEfficiencyFilter{
+		From:       from,
+		To:         to,
+		MechanicID: mechanicID,
+		BranchID:   branchID,
+	})
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve labor efficiency report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Labor efficiency report retrieved successfully", report)
+}
+
+// parseTaskParams reads the current viewer and the work order and task path parameters
+func parseTaskParams(c *gin.Context) (service.Viewer, uint, uint, bool) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	taskID, ok := parseIDParam(c, "taskId", "task")
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	return viewer, id, taskID, true
+}
+
+// bindOptionalJSON binds the request body when there is one
+func bindOptionalJSON(c *gin.Context, req interface{}) bool {
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return false
+		}
+	}
+	return true
+}
+
+// handleError maps labor service errors to HTTP responses
+func (h *LaborHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrWorkOrderNotFound),
+		errors.Is(err, service.ErrWorkOrderTaskNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrWorkOrderAccessDenied),
+		errors.Is(err, service.ErrVehicleAccessDenied),
+		errors.Is(err, service.ErrMechanicAccessDenied),
+		errors.Is(err, service.ErrClockNotMechanic):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrClockActive),
+		errors.Is(err, service.ErrNoActiveClock),
+		errors.Is(err, service.ErrWorkOrderNotInProgress),
+		errors.Is(err, service.ErrWorkOrderTaskCompleted),
+		errors.Is(err, service.ErrEstimateExceeded),
+		errors.Is(err, service.ErrWorkOrderClosed):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		response.Error(c, http.StatusBadRequest, "Invalid time range", err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// LaborOperationHandler handles labor operation catalogue HTTP requests
+type LaborOperationHandler struct {
+	operationService *service.LaborOperationService
+	logger           *logrus.Logger
+}
+
+// NewLaborOperationHandler creates a new labor operation handler
+func NewLaborOperationHandler(operationService *service.LaborOperationService, logger *logrus.Logger) *LaborOperationHandler {
+	return &LaborOperationHandler{
+		operationService: operationService,
+		logger:           logger,
+	}
+}
+
+// Create adds an operation to the catalogue
+// @Summary Create labor operation
+// @Tags labor-operations
+// @Accept json
+// @Produce json
+// @Param request body service.LaborOperationRequest true "Labor operation"
+// @Success 201 {object} response.Response "Labor operation created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 409 {object} response.Response "Code already exists"
+// @Router /labor-operations [post]
+func (h *LaborOperationHandler) Create(c *gin.Context) {
+	var req service.LaborOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	operation, err := h.operationService.Create(&req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create labor operation")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Labor operation created successfully", operation)
+}
+
+// List lists catalogued operations
+// @Summary List labor operations
+// @Tags labor-operations
+// @Produce json
+// @Param search query string false "Search code or description"
+// @Param vehicle_type query string false "Operations that apply to the vehicle type"
+// @Param make query string false "Operations that apply to the make"
+// @Param active query bool false "Only active operations"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Labor operations retrieved successfully"
+// @Router /labor-operations [get]
+func (h *LaborOperationHandler) List(c *gin.Context) {
+	activeOnly, _ := strconv.ParseBool(c.Query("active"))
+	page, limit, offset := parsePagination(c)
+
+	operations, err := h.operationService.List(interfaces.LaborOperationFilter{
+		Search:      c.Query("search"),
+		VehicleType: c.Query("vehicle_type"),
+		Make:        c.Query("make"),
+		ActiveOnly:  activeOnly,
+	}, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve labor operations")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Labor operations retrieved successfully", operations)
+}
+
+// GetByID returns a catalogued operation
+// @Summary Get labor operation
+// @Tags labor-operations
+// @Produce json
+// @Param id path int true "Labor operation ID"
+// @Success 200 {object} response.Response "Labor operation retrieved successfully"
+// @Failure 404 {object} response.Response "Labor operation not found"
+// @Router /labor-operations/{id} [get]
+func (h *LaborOperationHandler) GetByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "labor operation")
+	if !ok {
+		return
+	}
+
+	operation, err := h.operationService.Get(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve labor operation")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Labor operation retrieved successfully", operation)
+}
+
+// Update replaces a catalogued operation
+// @Summary Update labor operation
+// @Description Tasks already created keep the standard hours they were created with
+// @Tags labor-operations
+// @Accept json
+// @Produce json
+// @Param id path int true "Labor operation ID"
+// @Param request body service.LaborOperationRequest true "Labor operation"
+// @Success 200 {object} response.Response "Labor operation updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Labor operation not found"
+// @Failure 409 {object} response.Response "Code already exists"
+// @Router /labor-operations/{id} [put]
+func (h *LaborOperationHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "labor operation")
+	if !ok {
+		return
+	}
+
+	var req service.LaborOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	operation, err := h.operationService.Update(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update labor operation")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Labor operation updated successfully", operation)
+}
+
+// Import validates the uploaded catalogue and, when dry_run is false, creates and updates the
+// operations by code in one transaction
+// @Summary Import labor operations
+// @Description Columns: code, description, vehicle_types, makes, standard_hours, default_price and is_active.
+// @Description Vehicle types and makes are lists separated by semicolons; leave them empty for every vehicle.
+// @Tags labor-operations
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param format formData string false "File format (csv, xlsx)"
+// @Param dry_run formData bool false "Only validate the file (default true)"
+// @Success 200 {object} response.Response "Validation report"
+// @Success 201 {object} response.Response "Labor operations imported"
+// @Failure 400 {object} response.Response "Invalid file or rows"
+// @Router /labor-operations/import [post]
+func (h *LaborOperationHandler) Import(c *gin.Context) {
+	file, header, format, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	dryRun, err := strconv.ParseBool(c.DefaultPostForm("dry_run", "true"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid dry_run value", err.Error())
+		return
+	}
+
+	report, err := h.operationService.Import(format, file, header.Size, dryRun)
+	if errors.Is(err, service.ErrImportValidation) {
+		response.ValidationError(c, "Import rejected, no labor operations were imported", report)
+		return
+	}
+	if err != nil {
+		h.handleError(c, err, "Failed to import labor operations")
+		return
+	}
+
+	if dryRun {
+		response.Success(c, http.StatusOK, "Import validation completed", report)
+		return
+	}
+	response.Success(c, http.StatusCreated, "Labor operations imported successfully", report)
+}
+
+// StandardReport compares the standard hours of catalogued operations with the labor recorded on them
+// @Summary Labor standard times report
+// @Description Per operation, the standard hours of the tasks on work orders completed in the range against
+// @Description the hours recorded in work order labor. Efficiency is standard per actual hour, so above 1
+// @Description beats the standard.
+// @Tags reports
+// @Produce json
+// @Param from query string false "Completed at or after (RFC3339), defaults to 90 days ago"
+// @Param to query string false "Completed before (RFC3339), defaults to now"
+// @Param operation_id query int false "Filter by operation"
+// @Param branch_id query int false "Filter by branch"
+// @Success 200 {object} response.Response "Labor standards report retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /reports/labor-standards [get]
+func (h *LaborOperationHandler) StandardReport(c *gin.Context) {
+	now := time.Now().UTC()
+	from, to, ok := parseTimeRange(c, now.AddDate(0, 0, -90), now)
+	if !ok {
+		return
+	}
+	operationID, ok := parseOptionalID(c, "operation_id")
+	if !ok {
+		return
+	}
+	branchID, ok := parseOptionalID(c, "branch_id")
+	if !ok {
+		return
+	}
+
+	report, err := h.operationService.StandardReport(interfaces.LaborStandardFilter{
+		From:        from,
+		To:          to,
+		OperationID: operationID,
+		BranchID:    branchID,
+	})
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve labor standards report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Labor standards report retrieved successfully", report)
+}
+
+// handleError maps labor operation service errors to HTTP responses
+func (h *LaborOperationHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrLaborOperationNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrLaborOperationExists):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case errors.Is(err, service.ErrUnsupportedFileFormat),
+		errors.Is(err, service.ErrEmptyImportFile),
+		errors.Is(err, service.ErrInvalidColumnMapping),
+		errors.Is(err, service.ErrInvalidTimeRange),
+		isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
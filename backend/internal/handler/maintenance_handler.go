@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// MaintenanceHandler handles preventive maintenance HTTP requests
+type MaintenanceHandler struct {
+	maintenanceService *service.MaintenanceService
+	logger             *logrus.Logger
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenanceService *service.MaintenanceService, logger *logrus.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+		logger:             logger,
+	}
+}
+
+// CreatePlan creates a maintenance plan
+// @Summary Create maintenance plan
+// @Description The plan is due after interval_km, interval_days or interval_engine_hours, whichever comes first.
+// @Description Matching vehicles are scheduled on the next scheduler run.
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Param request body service.MaintenancePlanRequest true "Maintenance plan"
+// @Success 201 {object} response.Response "Maintenance plan created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Router /maintenance-plans [post]
+func (h *MaintenanceHandler) CreatePlan(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.MaintenancePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	plan, err := h.maintenanceService.CreatePlan(&req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create maintenance plan")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Maintenance plan created successfully", plan)
+}
+
+// ListPlans lists the maintenance plans
+// @Summary List maintenance plans
+// @Tags maintenance
+// @Produce json
+// @Param active query bool false "Only active plans"
+// @Success 200 {object} response.Response "Maintenance plans retrieved successfully"
+// @Router /maintenance-plans [get]
+func (h *MaintenanceHandler) ListPlans(c *gin.Context) {
+	activeOnly, _ := strconv.ParseBool(c.Query("active"))
+
+	plans, err := h.maintenanceService.ListPlans(activeOnly)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve maintenance plans")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Maintenance plans retrieved successfully", plans)
+}
+
+// GetPlan returns a maintenance plan
+// @Summary Get maintenance plan
+// @Tags maintenance
+// @Produce json
+// @Param id path int true "Maintenance plan ID"
+// @Success 200 {object} response.Response "Maintenance plan retrieved successfully"
+// @Failure 404 {object} response.Response "Maintenance plan not found"
+// @Router /maintenance-plans/{id} [get]
+func (h *MaintenanceHandler) GetPlan(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "maintenance plan")
+	if !ok {
+		return
+	}
+
+	plan, err := h.maintenanceService.GetPlan(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve maintenance plan")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Maintenance plan retrieved successfully", plan)
+}
+
+// UpdatePlan replaces a maintenance plan
+// @Summary Update maintenance plan
+// @Description Schedules keep when the plan was last done; the new plan applies from the next scheduler run.
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Param id path int true "Maintenance plan ID"
+// @Param request body service.MaintenancePlanRequest true "Maintenance plan"
+// @Success 200 {object} response.Response "Maintenance plan updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Maintenance plan not found"
+// @Router /maintenance-plans/{id} [put]
+func (h *MaintenanceHandler) UpdatePlan(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "maintenance plan")
+	if !ok {
+		return
+	}
+
+	var req service.MaintenancePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	plan, err := h.maintenanceService.UpdatePlan(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update maintenance plan")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Maintenance plan updated successfully", plan)
+}
+
+// RunScheduler runs the preventive maintenance scheduler now
+// @Summary Run maintenance scheduler
+// @Description Schedules the vehicles of the active plans, evaluates every schedule and opens the work orders
+// @Description and reminders that are due. The scheduler also runs periodically.
+// @Tags maintenance
+// @Produce json
+// @Success 200 {object} response.Response "Maintenance scheduler completed"
+// @Router /maintenance-plans/run [post]
+func (h *MaintenanceHandler) RunScheduler(c *gin.Context) {
+	result, err := h.maintenanceService.RunScheduler()
+	if err != nil {
+		h.handleError(c, err, "Failed to run maintenance scheduler")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Maintenance scheduler completed successfully", result)
+}
+
+// ListSchedules lists vehicle maintenance schedules, the most urgent first
+// @Summary List maintenance schedules
+// @Tags maintenance
+// @Produce json
+// @Param plan_id query int false "Filter by plan"
+// @Param vehicle_id query int false "Filter by vehicle"
+// @Param area_id query int false "Filter by service area of the vehicle"
+// @Param status query string false "Filter by status (ok, due_soon, overdue)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Maintenance schedules retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /maintenance-schedules [get]
+func (h *MaintenanceHandler) ListSchedules(c *gin.Context) {
+	filter, ok := parseMaintenanceFilter(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseOptionalID(c, "vehicle_id")
+	if !ok {
+		return
+	}
+	filter.VehicleID = vehicleID
+	filter.Status = c.Query("status")
+	switch filter.Status {
+	case "", domain.MaintenanceStatusOK, domain.MaintenanceStatusDueSoon, domain.MaintenanceStatusOverdue:
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid status", "status must be ok, due_soon or overdue")
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	schedules, err := h.maintenanceService.ListSchedules(filter, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve maintenance schedules")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Maintenance schedules retrieved successfully", schedules)
+}
+
+// ListVehicleSchedules lists the maintenance schedules of a vehicle
+// @Summary List vehicle maintenance schedules
+// @Tags maintenance
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Maintenance schedules retrieved successfully"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/maintenance [get]
+func (h *MaintenanceHandler) ListVehicleSchedules(c *gin.Context) {
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	schedules, err := h.maintenanceService.ListSchedules(interfaces.MaintenanceScheduleFilter{VehicleID: vehicleID}, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve maintenance schedules")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Maintenance schedules retrieved successfully", schedules)
+}
+
+// RecordDone records maintenance carried out outside a work order
+// @Summary Record maintenance done
+// @Description Restarts the schedule from the given date and readings, which default to now and the
+// @Description vehicle's current readings. Completing the schedule's work order does this automatically.
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Param id path int true "Maintenance schedule ID"
+// @Param request body service.MaintenanceDoneRequest false "When and at which readings the maintenance was done"
+// @Success 200 {object} response.Response "Maintenance recorded successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Maintenance schedule not found"
+// @Router /maintenance-schedules/{id}/done [post]
+func (h *MaintenanceHandler) RecordDone(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "maintenance schedule")
+	if !ok {
+		return
+	}
+
+	var req service.MaintenanceDoneRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	schedule, err := h.maintenanceService.RecordDone(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to record maintenance")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Maintenance recorded successfully", schedule)
+}
+
+// OverdueReport lists the overdue preventive maintenance
+// @Summary Overdue maintenance report
+// @Description Overdue schedules as of the last scheduler run with how far past due each interval is,
+// @Description the longest overdue first, and the number of overdue vehicles per plan.
+// @Tags reports
+// @Produce json
+// @Param plan_id query int false "Filter by plan"
+// @Param area_id query int false "Filter by service area of the vehicle"
+// @Success 200 {object} response.Response "Overdue maintenance report retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /reports/maintenance-overdue [get]
+func (h *MaintenanceHandler) OverdueReport(c *gin.Context) {
+	filter, ok := parseMaintenanceFilter(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.maintenanceService.OverdueReport(filter)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve overdue maintenance report")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Overdue maintenance report retrieved successfully", report)
+}
+
+// parseMaintenanceFilter parses the plan and service area filters of maintenance schedules
+func parseMaintenanceFilter(c *gin.Context) (interfaces.MaintenanceScheduleFilter, bool) {
+	planID, ok := parseOptionalID(c, "plan_id")
+	if !ok {
+		return interfaces.MaintenanceScheduleFilter{}, false
+	}
+	areaID, ok := parseOptionalID(c, "area_id")
+	if !ok {
+		return interfaces.MaintenanceScheduleFilter{}, false
+	}
+	return interfaces.MaintenanceScheduleFilter{PlanID: planID, AreaID: areaID}, true
+}
+
+// handleError maps maintenance service errors to HTTP responses
+func (h *MaintenanceHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrMaintenancePlanNotFound),
+		errors.Is(err, service.ErrMaintenanceScheduleNotFound),
+		errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
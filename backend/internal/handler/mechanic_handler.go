@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// MechanicHandler handles mechanic profile, shift and workload HTTP requests
+type MechanicHandler struct {
+	mechanicService *service.MechanicService
+	logger          *logrus.Logger
+}
+
+// NewMechanicHandler creates a new mechanic handler
+func NewMechanicHandler(mechanicService *service.MechanicService, logger *logrus.Logger) *MechanicHandler {
+	return &MechanicHandler{
+		mechanicService: mechanicService,
+		logger:          logger,
+	}
+}
+
+// List lists the mechanic profiles
+// @Summary List mechanics
+// @Description Active mechanics with their branch, capacity and skills. Mechanics without a profile
+// @Description are listed with the defaults.
+// @Tags mechanics
+// @Produce json
+// @Param branch_id query int false "Filter by branch"
+// @Success 200 {object} response.Response "Mechanics retrieved successfully"
+// @Router /mechanics [get]
+func (h *MechanicHandler) List(c *gin.Context) {
+	branchID, ok := parseOptionalID(c, "branch_id")
+	if !ok {
+		return
+	}
+
+	profiles, err := h.mechanicService.List(branchID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve mechanics")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Mechanics retrieved successfully", profiles)
+}
+
+// Workload summarises the open work and schedule of the mechanics
+// @Summary Get mechanic workload
+// @Description Open work orders and remaining estimated hours against the scheduled shift hours of the
+// @Description coming days, most utilised first
+// @Tags mechanics
+// @Produce json
+// @Param branch_id query int false "Filter by branch"
+// @Param days query int false "Days ahead to count shifts" default(7)
+// @Success 200 {object} response.Response "Mechanic workload retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /mechanics/workload [get]
+func (h *MechanicHandler) Workload(c *gin.Context) {
+	branchID, ok := parseOptionalID(c, "branch_id")
+	if !ok {
+		return
+	}
+	days := 0
+	if raw := c.Query("days"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			response.ValidationError(c, "Invalid days", "days must be a number")
+			return
+		}
+		days = value
+	}
+
+	workload, err := h.mechanicService.Workload(branchID, days)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve mechanic workload")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Mechanic workload retrieved successfully", workload)
+}
+
+// GetByID returns the profile of a mechanic
+// @Summary Get mechanic profile
+// @Description Mechanics can only view their own profile
+// @Tags mechanics
+// @Produce json
+// @Param id path int true "Mechanic user ID"
+// @Success 200 {object} response.Response "Mechanic retrieved successfully"
+// @Failure 403 {object} response.Response "Not the current mechanic"
+// @Failure 404 {object} response.Response "Mechanic not found"
+// @Router /mechanics/{id} [get]
+func (h *MechanicHandler) GetByID(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "mechanic")
+	if !ok {
+		return
+	}
+
+	profile, err := h.mechanicService.Get(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve mechanic")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Mechanic retrieved successfully", profile)
+}
+
+// UpdateProfile replaces the profile of a mechanic
+// @Summary Update mechanic profile
+// @Description The skills in the request replace the current skills
+// @Tags mechanics
+// @Accept json
+// @Produce json
+// @Param id path int true "Mechanic user ID"
+// @Param request body service.MechanicProfileRequest true "Mechanic profile"
+// @Success 200 {object} response.Response "Mechanic profile updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Mechanic not found"
+// @Router /mechanics/{id} [put]
+func (h *MechanicHandler) UpdateProfile(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "mechanic")
+	if !ok {
+		return
+	}
+
+	var req service.MechanicProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	profile, err := h.mechanicService.SaveProfile(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update mechanic profile")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Mechanic profile updated successfully", profile)
+}
+
+// ListShifts lists the shifts of a mechanic
+// @Summary List mechanic shifts
+// @Description Shifts overlapping the time range, earliest first. Mechanics can only view their own shifts.
+// @Tags mechanics
+// @Produce json
+// @Param id path int true "Mechanic user ID"
+// @Param from query string false "Start of the range (RFC3339), defaults to now"
+// @Param to query string false "End of the range (RFC3339), defaults to a week after from"
+// @Success 200 {object} response.Response "Mechanic shifts retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid time range"
+// @Failure 404 {object} response.Response "Mechanic not found"
+// @Router /mechanics/{id}/shifts [get]
+func (h *MechanicHandler) ListShifts(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "mechanic")
+	if !ok {
+		return
+	}
+	from, to, ok := parseTimeRange(c, time.Now().UTC(), time.Time{})
+	if !ok {
+		return
+	}
+	if to.IsZero() {
+		to = from.AddDate(0, 0, 7)
+	}
+
+	shifts, err := h.mechanicService.ListShifts(viewer, id, from, to)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve mechanic shifts")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Mechanic shifts retrieved successfully", shifts)
+}
+
+// CreateShift schedules a shift for a mechanic
+// @Summary Create mechanic shift
+// @Description Shifts are at most 16 hours and cannot overlap other shifts of the mechanic
+// @Tags mechanics
+// @Accept json
+// @Produce json
+// @Param id path int true "Mechanic user ID"
+// @Param request body service.MechanicShiftRequest true "Shift"
+// @Success 201 {object} response.Response "Mechanic shift created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Mechanic not found"
+// @Failure 409 {object} response.Response "Shift overlaps another shift"
+// @Router /mechanics/{id}/shifts [post]
+func (h *MechanicHandler) CreateShift(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "mechanic")
+	if !ok {
+		return
+	}
+
+	var req service.MechanicShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	shift, err := h.mechanicService.CreateShift(id, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create mechanic shift")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Mechanic shift created successfully", shift)
+}
+
+// DeleteShift removes a shift of a mechanic
+// @Summary Delete mechanic shift
+// @Tags mechanics
+// @Produce json
+// @Param id path int true "Mechanic user ID"
+// @Param shiftId path int true "Shift ID"
+// @Success 200 {object} response.Response "Mechanic shift deleted successfully"
+// @Failure 404 {object} response.Response "Mechanic shift not found"
+// @Router /mechanics/{id}/shifts/{shiftId} [delete]
+func (h *MechanicHandler) DeleteShift(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "mechanic")
+	if !ok {
+		return
+	}
+	shiftID, ok := parseIDParam(c, "shiftId", "shift")
+	if !ok {
+		return
+	}
+
+	if err := h.mechanicService.DeleteShift(id, shiftID); err != nil {
+		h.handleError(c, err, "Failed to delete mechanic shift")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Mechanic shift deleted successfully", nil)
+}
+
+// handleError maps mechanic service errors to HTTP responses
+func (h *MechanicHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrMechanicNotFound),
+		errors.Is(err, service.ErrMechanicShiftNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrMechanicAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrMechanicShiftOverlap):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		response.Error(c, http.StatusBadRequest, "Invalid time range", err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
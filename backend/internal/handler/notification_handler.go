@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// NotificationHandler handles the current user's notifications
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+	logger              *logrus.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *service.NotificationService, logger *logrus.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// List lists the current user's notifications
+// @Summary List my notifications
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Notifications retrieved successfully"
+// @Router /notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	notifications, err := h.notificationService.List(userID, unreadOnly, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve notifications")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Notifications retrieved successfully", notifications)
+}
+
+// MarkRead marks a notification as read
+// @Summary Mark notification as read
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} response.Response "Notification marked as read"
+// @Failure 404 {object} response.Response "Notification not found"
+// @Router /notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "notification")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(userID, id); err != nil {
+		h.handleError(c, err, "Failed to mark notification as read")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Notification marked as read", nil)
+}
+
+// MarkAllRead marks all of the current user's notifications as read
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} response.Response "Notifications marked as read"
+// @Router /notifications/read-all [put]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.notificationService.MarkAllRead(userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to mark notifications as read")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Notifications marked as read", gin.H{"updated": count})
+}
+
+// handleError maps notification service errors to HTTP responses
+func (h *NotificationHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrNotificationNotFound):
+		response.NotFound(c, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
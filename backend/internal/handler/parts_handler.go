@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// PartsHandler handles work order part and stock transfer HTTP requests
+type PartsHandler struct {
+	partsService *service.PartsService
+	logger       *logrus.Logger
+}
+
+// NewPartsHandler creates a new parts handler
+func NewPartsHandler(partsService *service.PartsService, logger *logrus.Logger) *PartsHandler {
+	return &PartsHandler{
+		partsService: partsService,
+		logger:       logger,
+	}
+}
+
+// List lists the parts of a work order
+// @Summary List work order parts
+// @Description Parts with their reservation status, the stock transfer requests raised for
+// @Description shortages and the cost of the issued parts
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Work order parts retrieved successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id}/parts [get]
+func (h *PartsHandler) List(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	parts, err := h.partsService.List(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve work order parts")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order parts retrieved successfully", parts)
+}
+
+// AddPart reserves a part for a work order
+// @Summary Add work order part
+// @Description Reserves the part at the warehouse, by default the assigned mechanic's small warehouse.
+// @Description When the warehouse cannot cover the quantity the rest is requested by stock transfer and
+// @Description a work order in progress moves to waiting_for_parts.
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.WorkOrderPartRequest true "Part"
+// @Success 201 {object} response.Response "Work order part added successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order or inventory item not found"
+// @Failure 409 {object} response.Response "Work order is closed or beyond the approved estimate"
+// @Router /workorders/{id}/parts [post]
+func (h *PartsHandler) AddPart(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.WorkOrderPartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	result, err := h.partsService.AddPart(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to add work order part")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Work order part added successfully", result)
+}
+
+// IssuePart takes a reserved part out of stock
+// @Summary Issue work order part
+// @Description Records the reserved quantity as a usage transaction and adds the part to the work
+// @Description order's actual cost. Short parts cannot be issued until their transfer arrives.
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param partId path int true "Work order part ID"
+// @Param request body service.PartMovementRequest false "Note"
+// @Success 200 {object} response.Response "Work order part issued successfully"
+// @Failure 404 {object} response.Response "Work order part not found"
+// @Failure 409 {object} response.Response "Part is short, already issued or removed"
+// @Router /workorders/{id}/parts/{partId}/issue [put]
+func (h *PartsHandler) IssuePart(c *gin.Context) {
+	viewer, id, partID, ok := parsePartParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.PartMovementRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	part, err := h.partsService.IssuePart(viewer, id, partID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to issue work order part")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order part issued successfully", part)
+}
+
+// RemovePart takes a part off a work order
+// @Summary Remove work order part
+// @Description Releases the reservation of a part that was not issued and cancels its open transfer
+// @Description requests. An issued part is returned to stock.
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param partId path int true "Work order part ID"
+// @Param request body service.PartMovementRequest false "Note"
+// @Success 200 {object} response.Response "Work order part removed successfully"
+// @Failure 404 {object} response.Response "Work order part not found"
+// @Failure 409 {object} response.Response "Part already removed or work order closed"
+// @Router /workorders/{id}/parts/{partId} [delete]
+func (h *PartsHandler) RemovePart(c *gin.Context) {
+	viewer, id, partID, ok := parsePartParams(c)
+	if !ok {
+		return
+	}
+
+	var req service.PartMovementRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	part, err := h.partsService.RemovePart(viewer, id, partID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to remove work order part")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order part removed successfully", part)
+}
+
+// ListTransfers lists stock transfer requests
+// @Summary List stock transfer requests
+// @Tags inventory
+// @Produce json
+// @Param status query string false "requested, fulfilled or cancelled"
+// @Param to_warehouse_id query int false "Filter by destination warehouse"
+// @Param work_order_id query int false "Filter by work order"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Stock transfer requests retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /stock-transfers [get]
+func (h *PartsHandler) ListTransfers(c *gin.Context) {
+	toWarehouseID, ok := parseOptionalID(c, "to_warehouse_id")
+	if !ok {
+		return
+	}
+	workOrderID, ok := parseOptionalID(c, "work_order_id")
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	transfers, err := h.partsService.ListTransfers(interfaces.StockTransferFilter{
+		Status:        c.Query("status"),
+		ToWarehouseID: toWarehouseID,
+		WorkOrderID:   workOrderID,
+	}, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve stock transfer requests")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Stock transfer requests retrieved successfully", transfers)
+}
+
+// GetTransfer returns a stock transfer request
+// @Summary Get stock transfer request
+// @Tags inventory
+// @Produce json
+// @Param id path int true "Stock transfer request ID"
+// @Success 200 {object} response.Response "Stock transfer request retrieved successfully"
+// @Failure 404 {object} response.Response "Stock transfer request not found"
+// @Router /stock-transfers/{id} [get]
+func (h *PartsHandler) GetTransfer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "stock transfer request")
+	if !ok {
+		return
+	}
+
+	transfer, err := h.partsService.GetTransfer(id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve stock transfer request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Stock transfer request retrieved successfully", transfer)
+}
+
+// FulfilTransfer ships a stock transfer request
+// @Summary Fulfil stock transfer request
+// @Description Moves the stock from the source warehouse, by default the suggested one, and reserves it
+// @Description for the work order part that was short
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock transfer request ID"
+// @Param request body service.FulfilTransferRequest false "Source warehouse"
+// @Success 200 {object} response.Response "Stock transfer request fulfilled successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Stock transfer request not found"
+// @Failure 409 {object} response.Response "Request closed or not enough stock at the source"
+// @Router /stock-transfers/{id}/fulfil [put]
+func (h *PartsHandler) FulfilTransfer(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "stock transfer request")
+	if !ok {
+		return
+	}
+
+	var req service.FulfilTransferRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	transfer, err := h.partsService.FulfilTransfer(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to fulfil stock transfer request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Stock transfer request fulfilled successfully", transfer)
+}
+
+// CancelTransfer cancels a stock transfer request
+// @Summary Cancel stock transfer request
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock transfer request ID"
+// @Param request body service.CancelTransferRequest true "Reason"
+// @Success 200 {object} response.Response "Stock transfer request cancelled successfully"
+// @Failure 404 {object} response.Response "Stock transfer request not found"
+// @Failure 409 {object} response.Response "Request already closed"
+// @Router /stock-transfers/{id}/cancel [put]
+func (h *PartsHandler) CancelTransfer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "stock transfer request")
+	if !ok {
+		return
+	}
+
+	var req service.CancelTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	transfer, err := h.partsService.CancelTransfer(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel stock transfer request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Stock transfer request cancelled successfully", transfer)
+}
+
+// parsePartParams reads the current viewer and the work order and part path parameters
+func parsePartParams(c *gin.Context) (service.Viewer, uint, uint, bool) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	partID, ok := parseIDParam(c, "partId", "part")
+	if !ok {
+		return viewer, 0, 0, false
+	}
+	return viewer, id, partID, true
+}
+
+// handleError maps parts service errors to HTTP responses
+func (h *PartsHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrWorkOrderNotFound),
+		errors.Is(err, service.ErrWorkOrderPartNotFound),
+		errors.Is(err, service.ErrInventoryItemNotFound),
+		errors.Is(err, service.ErrStockTransferNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrWorkOrderAccessDenied),
+		errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrWorkOrderClosed),
+		errors.Is(err, service.ErrEstimateExceeded),
+		errors.Is(err, service.ErrPartShort),
+		errors.Is(err, service.ErrPartIssued),
+		errors.Is(err, service.ErrPartRemoved),
+		errors.Is(err, service.ErrStockTransferClosed),
+		errors.Is(err, service.ErrInsufficientStock):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,465 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// ServiceRequestHandler handles service request (PS) and service area HTTP requests
+type ServiceRequestHandler struct {
+	serviceRequestService *service.ServiceRequestService
+	logger                *logrus.Logger
+}
+
+// NewServiceRequestHandler creates a new service request handler
+func NewServiceRequestHandler(serviceRequestService *service.ServiceRequestService, logger *logrus.Logger) *ServiceRequestHandler {
+	return &ServiceRequestHandler{
+		serviceRequestService: serviceRequestService,
+		logger:                logger,
+	}
+}
+
+// Create submits a service request
+// @Summary Submit service request
+// @Description Raised by a driver or coordinator; drivers can only raise requests for their assigned
+// @Description vehicles. The request takes the area of the vehicle and its Area Manager is notified.
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param request body service.ServiceRequestRequest true "Service request"
+// @Success 201 {object} response.Response "Service request submitted successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 403 {object} response.Response "Vehicle not assigned to driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /servicerequest [post]
+func (h *ServiceRequestHandler) Create(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	var req service.ServiceRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	request, err := h.serviceRequestService.Create(viewer, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to submit service request")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Service request submitted successfully", request)
+}
+
+// List lists service requests
+// @Summary List service requests
+// @Description Most urgent first, then oldest first. Drivers only see their own requests and Area
+// @Description Managers those of their areas and of vehicles without an area.
+// @Tags service-requests
+// @Produce json
+// @Param vehicle_id query int false "Filter by vehicle"
+// @Param status query string false "submitted, approved, rejected, allocated, converted or cancelled"
+// @Param urgency query string false "low, normal, high or critical"
+// @Param from query string false "Submitted at or after (RFC3339)"
+// @Param to query string false "Submitted at or before (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Service requests retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /servicerequest [get]
+func (h *ServiceRequestHandler) List(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseOptionalID(c, "vehicle_id")
+	if !ok {
+		return
+	}
+	from, to, ok := parseTimeRange(c, time.Time{}, time.Time{})
+	if !ok {
+		return
+	}
+	filter := interfaces.ServiceRequestFilter{
+		VehicleID: vehicleID,
+		Status:    c.Query("status"),
+		Urgency:   c.Query("urgency"),
+		From:      optionalTime(from),
+		To:        optionalTime(to),
+	}
+	page, limit, offset := parsePagination(c)
+
+	requests, err := h.serviceRequestService.List(viewer, filter, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve service requests")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service requests retrieved successfully", requests)
+}
+
+// GetByID returns a service request
+// @Summary Get service request
+// @Tags service-requests
+// @Produce json
+// @Param id path int true "Service request ID"
+// @Success 200 {object} response.Response "Service request retrieved successfully"
+// @Failure 403 {object} response.Response "Service request outside the user's scope"
+// @Failure 404 {object} response.Response "Service request not found"
+// @Router /servicerequest/{id} [get]
+func (h *ServiceRequestHandler) GetByID(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "service request")
+	if !ok {
+		return
+	}
+
+	request, err := h.serviceRequestService.Get(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve service request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service request retrieved successfully", request)
+}
+
+// Approve approves a submitted service request
+// @Summary Approve service request
+// @Description Giving a branch_id also allocates the request to that branch
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Service request ID"
+// @Param request body service.ServiceRequestReviewRequest false "Review"
+// @Success 200 {object} response.Response "Service request approved successfully"
+// @Failure 403 {object} response.Response "Service request outside the user's areas"
+// @Failure 404 {object} response.Response "Service request not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /servicerequest/{id}/approve [put]
+func (h *ServiceRequestHandler) Approve(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "service request")
+	if !ok {
+		return
+	}
+
+	var req service.ServiceRequestReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	request, err := h.serviceRequestService.Approve(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to approve service request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service request approved successfully", request)
+}
+
+// Reject rejects a service request
+// @Summary Reject service request
+// @Description Submitted or approved requests can be rejected; a note is required
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Service request ID"
+// @Param request body service.ServiceRequestReviewRequest true "Review"
+// @Success 200 {object} response.Response "Service request rejected successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 403 {object} response.Response "Service request outside the user's areas"
+// @Failure 404 {object} response.Response "Service request not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /servicerequest/{id}/reject [put]
+func (h *ServiceRequestHandler) Reject(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "service request")
+	if !ok {
+		return
+	}
+
+	var req service.ServiceRequestReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	request, err := h.serviceRequestService.Reject(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to reject service request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service request rejected successfully", request)
+}
+
+// Allocate allocates a service request to a workshop branch
+// @Summary Allocate service request
+// @Description Approved requests are allocated to a branch or workshop of their area; allocated ones can be moved
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Service request ID"
+// @Param request body service.ServiceRequestAllocateRequest true "Branch"
+// @Success 200 {object} response.Response "Service request allocated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 403 {object} response.Response "Service request outside the user's areas"
+// @Failure 404 {object} response.Response "Service request not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /servicerequest/{id}/allocate [put]
+func (h *ServiceRequestHandler) Allocate(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "service request")
+	if !ok {
+		return
+	}
+
+	var req service.ServiceRequestAllocateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	request, err := h.serviceRequestService.Allocate(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to allocate service request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service request allocated successfully", request)
+}
+
+// Convert converts a service request into a work order
+// @Summary Convert service request into work order
+// @Description Allocated requests become a pending work order at the allocated branch with the current
+// @Description user as service advisor. The requester is notified as the work order progresses.
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Service request ID"
+// @Param request body service.ServiceRequestConvertRequest false "Work order details"
+// @Success 200 {object} response.Response "Service request converted successfully"
+// @Failure 403 {object} response.Response "Service request outside the user's areas"
+// @Failure 404 {object} response.Response "Service request not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /servicerequest/{id}/convert [put]
+func (h *ServiceRequestHandler) Convert(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "service request")
+	if !ok {
+		return
+	}
+
+	var req service.ServiceRequestConvertRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	request, err := h.serviceRequestService.Convert(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to convert service request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service request converted successfully", request)
+}
+
+// Cancel cancels a service request
+// @Summary Cancel service request
+// @Description Requests that have not been rejected or converted can be cancelled by the requester or a reviewer
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Service request ID"
+// @Param request body service.ServiceRequestCancelRequest false "Reason"
+// @Success 200 {object} response.Response "Service request cancelled successfully"
+// @Failure 403 {object} response.Response "Service request outside the user's scope"
+// @Failure 404 {object} response.Response "Service request not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /servicerequest/{id}/cancel [put]
+func (h *ServiceRequestHandler) Cancel(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "service request")
+	if !ok {
+		return
+	}
+
+	var req service.ServiceRequestCancelRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	request, err := h.serviceRequestService.Cancel(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel service request")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service request cancelled successfully", request)
+}
+
+// ListAreas lists the service areas
+// @Summary List service areas
+// @Tags service-requests
+// @Produce json
+// @Success 200 {object} response.Response "Service areas retrieved successfully"
+// @Router /service-areas [get]
+func (h *ServiceRequestHandler) ListAreas(c *gin.Context) {
+	areas, err := h.serviceRequestService.ListAreas()
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve service areas")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service areas retrieved successfully", areas)
+}
+
+// CreateArea creates a service area
+// @Summary Create service area
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param request body service.ServiceAreaRequest true "Service area"
+// @Success 201 {object} response.Response "Service area created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 409 {object} response.Response "Service area name already exists"
+// @Router /service-areas [post]
+func (h *ServiceRequestHandler) CreateArea(c *gin.Context) {
+	var req service.ServiceAreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	area, err := h.serviceRequestService.CreateArea(&req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create service area")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Service area created successfully", area)
+}
+
+// UpdateArea replaces the details of a service area
+// @Summary Update service area
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Service area ID"
+// @Param request body service.ServiceAreaRequest true "Service area"
+// @Success 200 {object} response.Response "Service area updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Service area not found"
+// @Failure 409 {object} response.Response "Service area name already exists"
+// @Router /service-areas/{id} [put]
+func (h *ServiceRequestHandler) UpdateArea(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "service area")
+	if !ok {
+		return
+	}
+
+	var req service.ServiceAreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	area, err := h.serviceRequestService.UpdateArea(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update service area")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service area updated successfully", area)
+}
+
+// AssignToArea moves vehicles and branches into a service area
+// @Summary Assign vehicles and branches to service area
+// @Description Requests raised earlier keep the area they were raised in
+// @Tags service-requests
+// @Accept json
+// @Produce json
+// @Param id path int true "Service area ID"
+// @Param request body service.ServiceAreaMembersRequest true "Vehicles and branches"
+// @Success 200 {object} response.Response "Service area members assigned successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Service area not found"
+// @Router /service-areas/{id}/members [put]
+func (h *ServiceRequestHandler) AssignToArea(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "service area")
+	if !ok {
+		return
+	}
+
+	var req service.ServiceAreaMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	area, err := h.serviceRequestService.AssignToArea(id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to assign service area members")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Service area members assigned successfully", area)
+}
+
+// handleError maps service request service errors to HTTP responses
+func (h *ServiceRequestHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrServiceRequestNotFound),
+		errors.Is(err, service.ErrServiceAreaNotFound),
+		errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrServiceRequestAccessDenied),
+		errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidServiceRequestTransition),
+		errors.Is(err, service.ErrServiceAreaExists):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// TelematicsHandler handles telematics HTTP requests
+type TelematicsHandler struct {
+	ingestService *service.TelematicsIngestService
+	logger        *logrus.Logger
+}
+
+// NewTelematicsHandler creates a new telematics handler
+func NewTelematicsHandler(ingestService *service.TelematicsIngestService, logger *logrus.Logger) *TelematicsHandler {
+	return &TelematicsHandler{
+		ingestService: ingestService,
+		logger:        logger,
+	}
+}
+
+// Ingest accepts a batch of telematics samples from an authenticated device
+// @Summary Ingest telematics samples
+// @Description Samples are deduplicated on device and timestamp, may arrive out of order and are
+// @Description routed to the vehicle the device was bound to at each sample's timestamp
+// @Tags telematics
+// @Accept json
+// @Produce json
+// @Param X-Device-ID header string true "Device identifier"
+// @Param request body service.TelematicsIngestRequest true "Sample batch"
+// @Success 200 {object} response.Response "Batch processed with per-item results"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 401 {object} response.Response "Invalid device credentials"
+// @Failure 413 {object} response.Response "Request body too large"
+// @Failure 503 {object} response.Response "Ingestion unavailable"
+// @Router /telematics/ingest [post]
+func (h *TelematicsHandler) Ingest(c *gin.Context) {
+	device, ok := currentDevice(c)
+	if !ok {
+		return
+	}
+
+	var req service.TelematicsIngestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(c, http.StatusRequestEntityTooLarge, "Request body too large", err.Error())
+			return
+		}
+		response.Error(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	result, err := h.ingestService.Ingest(device, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to ingest telematics samples")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Telematics batch processed", result)
+}
+
+// handleError maps telematics service errors to HTTP responses
+func (h *TelematicsHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrIngestionStopped):
+		response.Error(c, http.StatusServiceUnavailable, message, err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
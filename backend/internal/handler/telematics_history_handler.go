@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// TelematicsHistoryHandler handles telematics history HTTP requests
+type TelematicsHistoryHandler struct {
+	storageService *service.TelematicsStorageService
+	logger         *logrus.Logger
+}
+
+// NewTelematicsHistoryHandler creates a new telematics history handler
+func NewTelematicsHistoryHandler(storageService *service.TelematicsStorageService, logger *logrus.Logger) *TelematicsHistoryHandler {
+	return &TelematicsHistoryHandler{
+		storageService: storageService,
+		logger:         logger,
+	}
+}
+
+// GetHistory returns the telematics history of a vehicle
+// @Summary Get vehicle telematics history
+// @Description Without a resolution, ranges up to 6 hours are served raw, up to 7 days at 1-minute
+// @Description and longer ones at 1-hour resolution; ranges past the raw or 1-minute retention fall
+// @Description back to the next coarser resolution. Raw samples are returned as single-sample buckets.
+// @Tags telematics
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param from query string false "Start of the range (RFC3339), defaults to 1 hour ago"
+// @Param to query string false "End of the range (RFC3339), defaults to now"
+// @Param resolution query string false "auto, raw, 1m or 1h" default(auto)
+// @Success 200 {object} response.Response "Telematics history retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid time range or resolution"
+// @Failure 403 {object} response.Response "Vehicle not assigned to the driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/telematics [get]
+func (h *TelematicsHistoryHandler) GetHistory(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	from, to, ok := parseTimeRange(c, now.Add(-time.Hour), now)
+	if !ok {
+		return
+	}
+
+	series, err := h.storageService.GetSeries(viewer, vehicleID, from, to, c.Query("resolution"))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve telematics history")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Telematics history retrieved successfully", series)
+}
+
+// handleError maps telematics storage service errors to HTTP responses
+func (h *TelematicsHistoryHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		response.Error(c, http.StatusBadRequest, "Invalid time range", err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+const (
+	streamWriteTimeout = 10 * time.Second
+	// streamPingInterval keeps idle connections alive through proxies
+	streamPingInterval = 25 * time.Second
+	streamPongTimeout  = 60 * time.Second
+	// streamMaxMessageSize bounds client subscription messages
+	streamMaxMessageSize = 16 * 1024
+)
+
+// Stream message types
+const (
+	streamMessageSubscribed = "subscribed"
+	streamMessageUpdate     = "update"
+	streamMessageDropped    = "dropped"
+	streamMessageError      = "error"
+	streamMessageSubscribe  = "subscribe"
+)
+
+// streamMessage is the envelope of WebSocket messages and the payload of SSE events
+type streamMessage struct {
+	Type         string                             `json:"type"`
+	Data         *service.TelematicsUpdate          `json:"data,omitempty"`
+	Subscription *service.StreamSubscriptionRequest `json:"subscription,omitempty"`
+	Count        int                                `json:"count,omitempty"`
+	Message      string                             `json:"message,omitempty"`
+}
+
+// streamClientMessage is sent by WebSocket clients to change their subscription
+type streamClientMessage struct {
+	Type string `json:"type"`
+	service.StreamSubscriptionRequest
+}
+
+// TelematicsStreamHandler serves live telematics updates over WebSocket and Server-Sent Events
+type TelematicsStreamHandler struct {
+	streamService *service.TelematicsStreamService
+	upgrader      websocket.Upgrader
+	logger        *logrus.Logger
+}
+
+// NewTelematicsStreamHandler creates a new telematics stream handler
+func NewTelematicsStreamHandler(streamService *service.TelematicsStreamService, logger *logrus.Logger) *TelematicsStreamHandler {
+	return &TelematicsStreamHandler{
+		streamService: streamService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+			// Authentication uses bearer tokens, not cookies, so cross-origin clients are safe
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		logger: logger,
+	}
+}
+
+// Stream pushes live position and engine updates
+// @Summary Stream live telematics updates
+// @Description Upgrades to a WebSocket when requested, otherwise streams Server-Sent Events.
+// @Description The token may be passed as access_token because browsers cannot set headers on these requests.
+// @Description WebSocket clients can change the selection by sending {"type":"subscribe","vehicle_ids":[...],"bbox":{...}}.
+// @Description Updates a client cannot keep up with are dropped and reported with a "dropped" message.
+// @Tags telematics
+// @Produce json
+// @Produce text/event-stream
+// @Param vehicle_ids query string false "Comma separated vehicle IDs"
+// @Param bbox query string false "Bounding box as min_lon,min_lat,max_lon,max_lat"
+// @Param access_token query string false "JWT access token"
+// @Success 200 {object} handler.streamMessage "Update stream"
+// @Failure 400 {object} response.Response "Invalid subscription"
+// @Failure 403 {object} response.Response "No vehicles available"
+// @Router /telematics/stream [get]
+func (h *TelematicsStreamHandler) Stream(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	req, err := parseStreamSubscription(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid subscription", err.Error())
+		return
+	}
+
+	sub, err := h.streamService.Subscribe(viewer, req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	defer h.streamService.Unsubscribe(sub)
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		h.serveWebSocket(c, sub, req)
+		return
+	}
+	h.serveSSE(c, sub, req)
+}
+
+// serveWebSocket pumps updates to a WebSocket and applies subscription changes sent by the client
+func (h *TelematicsStreamHandler) serveWebSocket(c *gin.Context, sub *service.StreamSubscription, req *service.StreamSubscriptionRequest) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written an error response
+		return
+	}
+	defer conn.Close()
+
+	// Only the writer loop writes to the connection; the reader hands replies over
+	replies := make(chan *streamMessage, 8)
+	readerDone := make(chan struct{})
+	writerDone := make(chan struct{})
+	defer close(writerDone)
+	go func() {
+		defer close(readerDone)
+		h.readWebSocket(conn, sub, replies, writerDone)
+	}()
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+
+	write := func(msg *streamMessage) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return conn.WriteJSON(msg)
+	}
+
+	if err := write(&streamMessage{Type: streamMessageSubscribed, Subscription: req}); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case update := <-sub.Updates():
+			if dropped := sub.TakeDropped(); dropped > 0 {
+				if err := write(&streamMessage{Type: streamMessageDropped, Count: dropped}); err != nil {
+					return
+				}
+			}
+			if err := write(&streamMessage{Type: streamMessageUpdate, Data: update}); err != nil {
+				return
+			}
+		case reply := <-replies:
+			if err := write(reply); err != nil {
+				return
+			}
+		case <-ping.C:
+			deadline := time.Now().Add(streamWriteTimeout)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				return
+			}
+		case <-sub.Done():
+			closeMsg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, sub.CloseReason())
+			conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(streamWriteTimeout))
+			return
+		case <-readerDone:
+			return
+		}
+	}
+}
+
+// readWebSocket handles client messages until the connection closes
+func (h *TelematicsStreamHandler) readWebSocket(conn *websocket.Conn, sub *service.StreamSubscription, replies chan<- *streamMessage, writerDone <-chan struct{}) {
+	reply := func(msg *streamMessage) bool {
+		select {
+		case replies <- msg:
+			return true
+		case <-writerDone:
+			return false
+		}
+	}
+
+	conn.SetReadLimit(streamMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(streamPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamPongTimeout))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(streamPongTimeout))
+
+		var msg streamClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type != streamMessageSubscribe {
+			if !reply(&streamMessage{Type: streamMessageError, Message: "expected a subscribe message"}) {
+				return
+			}
+			continue
+		}
+
+		req := msg.StreamSubscriptionRequest
+		if err := h.streamService.Resubscribe(sub, &req); err != nil {
+			if !reply(&streamMessage{Type: streamMessageError, Message: err.Error()}) {
+				return
+			}
+			continue
+		}
+		if !reply(&streamMessage{Type: streamMessageSubscribed, Subscription: &req}) {
+			return
+		}
+	}
+}
+
+// serveSSE pumps updates as Server-Sent Events until the client disconnects
+func (h *TelematicsStreamHandler) serveSSE(c *gin.Context, sub *service.StreamSubscription, req *service.StreamSubscriptionRequest) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Disable proxy buffering (nginx) so events are delivered immediately
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	writeEvent := func(msg *streamMessage) bool {
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", msg.Type, payload); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+
+	if !writeEvent(&streamMessage{Type: streamMessageSubscribed, Subscription: req}) {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamPingInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case update := <-sub.Updates():
+			if dropped := sub.TakeDropped(); dropped > 0 {
+				if !writeEvent(&streamMessage{Type: streamMessageDropped, Count: dropped}) {
+					return
+				}
+			}
+			if !writeEvent(&streamMessage{Type: streamMessageUpdate, Data: update}) {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-sub.Done():
+			writeEvent(&streamMessage{Type: streamMessageError, Message: sub.CloseReason()})
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// parseStreamSubscription reads the vehicle_ids and bbox query parameters
+func parseStreamSubscription(c *gin.Context) (*service.StreamSubscriptionRequest, error) {
+	req := &service.StreamSubscriptionRequest{}
+
+	if raw := strings.TrimSpace(c.Query("vehicle_ids")); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vehicle ID %q", part)
+			}
+			req.VehicleIDs = append(req.VehicleIDs, uint(id))
+		}
+	}
+
+	if raw := strings.TrimSpace(c.Query("bbox")); raw != "" {
+		parts := strings.Split(raw, ",")
+		if len(parts) != 4 {
+			return nil, errors.New("bbox must be min_lon,min_lat,max_lon,max_lat")
+		}
+		values := make([]float64, 4)
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bbox value %q", part)
+			}
+			values[i] = value
+		}
+		req.BBox = &service.BoundingBox{
+			MinLongitude: values[0],
+			MinLatitude:  values[1],
+			MaxLongitude: values[2],
+			MaxLatitude:  values[3],
+		}
+	}
+
+	return req, nil
+}
+
+// handleError maps stream service errors to HTTP responses
+func (h *TelematicsStreamHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNoStreamableVehicles):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidBoundingBox):
+		response.Error(c, http.StatusBadRequest, "Invalid subscription", err.Error())
+	default:
+		h.logger.WithError(err).Error("Failed to start telematics stream")
+		response.Error(c, http.StatusInternalServerError, "Failed to start telematics stream", err.Error())
+	}
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// TripHandler handles trip and route history HTTP requests
+type TripHandler struct {
+	tripService *service.TripService
+	logger      *logrus.Logger
+}
+
+// NewTripHandler creates a new trip handler
+func NewTripHandler(tripService *service.TripService, logger *logrus.Logger) *TripHandler {
+	return &TripHandler{
+		tripService: tripService,
+		logger:      logger,
+	}
+}
+
+// ListTrips lists the trips of a vehicle
+// @Summary List vehicle trips
+// @Description Trips starting in the time range, newest first. Defaults to the last 7 days.
+// @Tags trips
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param from query string false "Range start (RFC3339)"
+// @Param to query string false "Range end (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Trips retrieved successfully"
+// @Failure 403 {object} response.Response "Vehicle not assigned to driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/trips [get]
+func (h *TripHandler) ListTrips(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	from, to, ok := parseTimeRange(c, now.Add(-7*24*time.Hour), now)
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(c)
+
+	trips, err := h.tripService.ListTrips(viewer, vehicleID, from, to, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve trips")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Trips retrieved successfully", trips)
+}
+
+// GetTrip returns one trip of a vehicle
+// @Summary Get vehicle trip
+// @Tags trips
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param tripId path int true "Trip ID"
+// @Success 200 {object} response.Response "Trip retrieved successfully"
+// @Failure 404 {object} response.Response "Trip not found"
+// @Router /vehicles/{id}/trips/{tripId} [get]
+func (h *TripHandler) GetTrip(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	tripID, ok := parseIDParam(c, "tripId", "trip")
+	if !ok {
+		return
+	}
+
+	trip, err := h.tripService.GetTrip(viewer, vehicleID, tripID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve trip")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Trip retrieved successfully", trip)
+}
+
+// GetTripRoute returns the simplified route of a trip
+// @Summary Get trip route
+// @Description Route points simplified with Douglas–Peucker; tolerance 0 returns every point
+// @Tags trips
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param tripId path int true "Trip ID"
+// @Param tolerance query number false "Simplification tolerance in meters" default(10)
+// @Success 200 {object} response.Response "Trip route retrieved successfully"
+// @Failure 404 {object} response.Response "Trip not found"
+// @Router /vehicles/{id}/trips/{tripId}/route [get]
+func (h *TripHandler) GetTripRoute(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	tripID, ok := parseIDParam(c, "tripId", "trip")
+	if !ok {
+		return
+	}
+	tolerance, ok := parseTolerance(c)
+	if !ok {
+		return
+	}
+
+	route, err := h.tripService.GetTripRoute(viewer, vehicleID, tripID, tolerance)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve trip route")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Trip route retrieved successfully", route)
+}
+
+// GetRoute returns the simplified route of a vehicle in a time range
+// @Summary Get vehicle route history
+// @Description Route points in the range (at most 7 days), simplified with Douglas–Peucker
+// @Tags trips
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param from query string true "Range start (RFC3339)"
+// @Param to query string true "Range end (RFC3339)"
+// @Param tolerance query number false "Simplification tolerance in meters" default(10)
+// @Success 200 {object} response.Response "Route retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid time range"
+// @Router /vehicles/{id}/route [get]
+func (h *TripHandler) GetRoute(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+	if c.Query("from") == "" || c.Query("to") == "" {
+		response.Error(c, http.StatusBadRequest, "Invalid time range", "from and to are required")
+		return
+	}
+	from, to, ok := parseTimeRange(c, time.Time{}, time.Time{})
+	if !ok {
+		return
+	}
+	tolerance, ok := parseTolerance(c)
+	if !ok {
+		return
+	}
+
+	route, err := h.tripService.GetRoute(viewer, vehicleID, from, to, tolerance)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve route")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Route retrieved successfully", route)
+}
+
+// Rebuild re-segments historical telematics into trips
+// @Summary Rebuild trips
+// @Description Starts a background rebuild of the trips in the range (at most 92 days), for one vehicle or all vehicles with telematics
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Param request body service.RebuildTripsRequest true "Rebuild range"
+// @Success 202 {object} response.Response "Trip rebuild started"
+// @Failure 400 {object} response.Response "Invalid time range"
+// @Failure 409 {object} response.Response "Rebuild already running"
+// @Router /trips/rebuild [post]
+func (h *TripHandler) Rebuild(c *gin.Context) {
+	var req service.RebuildTripsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	started, err := h.tripService.Rebuild(&req)
+	if err != nil {
+		h.handleError(c, err, "Failed to start trip rebuild")
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Trip rebuild started", started)
+}
+
+// parseTimeRange reads the from and to query parameters, falling back to the given defaults
+func parseTimeRange(c *gin.Context, defaultFrom, defaultTo time.Time) (time.Time, time.Time, bool) {
+	parse := func(name string, fallback time.Time) (time.Time, bool) {
+		raw := c.Query(name)
+		if raw == "" {
+			return fallback, true
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid time range", fmt.Sprintf("%s must be an RFC3339 timestamp", name))
+			return time.Time{}, false
+		}
+		return value.UTC(), true
+	}
+
+	from, ok := parse("from", defaultFrom)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	to, ok := parse("to", defaultTo)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// parseTolerance reads the route simplification tolerance; -1 selects the service default
+func parseTolerance(c *gin.Context) (float64, bool) {
+	raw := c.Query("tolerance")
+	if raw == "" {
+		return -1, true
+	}
+	tolerance, err := strconv.ParseFloat(raw, 64)
+	if err != nil || tolerance < 0 {
+		response.Error(c, http.StatusBadRequest, "Invalid tolerance", "tolerance must be a non-negative number of meters")
+		return 0, false
+	}
+	return tolerance, true
+}
+
+// handleError maps trip service errors to HTTP responses
+func (h *TripHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrVehicleNotFound), errors.Is(err, service.ErrTripNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		response.Error(c, http.StatusBadRequest, "Invalid time range", err.Error())
+	case errors.Is(err, service.ErrTripRebuildInProgress):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// maxImportFileSize limits uploaded import files to 10 MB
+const maxImportFileSize = 10 << 20
+
+// VehicleImportHandler handles bulk vehicle import and export HTTP requests
+type VehicleImportHandler struct {
+	importService *service.VehicleImportService
+	logger        *logrus.Logger
+}
+
+// NewVehicleImportHandler creates a new vehicle import handler
+func NewVehicleImportHandler(importService *service.VehicleImportService, logger *logrus.Logger) *VehicleImportHandler {
+	return &VehicleImportHandler{
+		importService: importService,
+		logger:        logger,
+	}
+}
+
+// Preview reads the uploaded file headers and suggests a column mapping
+// @Summary Preview vehicle import file
+// @Tags vehicles
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param format formData string false "File format (csv, xlsx); derived from the file name when omitted"
+// @Success 200 {object} response.Response "Import preview generated"
+// @Failure 400 {object} response.Response "Invalid file"
+// @Router /vehicles/import/preview [post]
+func (h *VehicleImportHandler) Preview(c *gin.Context) {
+	file, header, format, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	preview, err := h.importService.Preview(format, file, header.Size)
+	if err != nil {
+		h.handleError(c, err, "Failed to preview import file")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Import preview generated successfully", preview)
+}
+
+// Import validates the uploaded file and, when dry_run is false, imports all vehicles in one transaction
+// @Summary Import vehicles
+// @Tags vehicles
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param format formData string false "File format (csv, xlsx)"
+// @Param mapping formData string false "JSON object mapping vehicle fields to column headers"
+// @Param dry_run formData bool false "Only validate the file (default true)"
+// @Success 200 {object} response.Response "Validation report"
+// @Success 201 {object} response.Response "Vehicles imported"
+// @Failure 400 {object} response.Response "Invalid file or rows"
+// @Router /vehicles/import [post]
+func (h *VehicleImportHandler) Import(c *gin.Context) {
+	file, header, format, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	var mapping map[string]string
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid column mapping", err.Error())
+			return
+		}
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultPostForm("dry_run", "true"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid dry_run value", err.Error())
+		return
+	}
+
+	report, err := h.importService.Import(format, file, header.Size, mapping, dryRun)
+	if errors.Is(err, service.ErrImportValidation) {
+		response.ValidationError(c, "Import rejected, no vehicles were imported", report)
+		return
+	}
+	if err != nil {
+		h.handleError(c, err, "Failed to import vehicles")
+		return
+	}
+
+	if dryRun {
+		response.Success(c, http.StatusOK, "Import validation completed", report)
+		return
+	}
+	response.Success(c, http.StatusCreated, "Vehicles imported successfully", report)
+}
+
+// Export streams the filtered vehicle list as CSV or XLSX
+// @Summary Export vehicles
+// @Tags vehicles
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format (csv, xlsx), default csv"
+// @Param status query string false "Filter by status"
+// @Param type query string false "Filter by type"
+// @Param category query string false "Filter by category"
+// @Param make query string false "Filter by make"
+// @Param search query string false "Search plate number, VIN, make or model"
+// @Success 200 {file} file "Vehicle export"
+// @Failure 400 {object} response.Response "Unsupported format"
+// @Router /vehicles/export [get]
+func (h *VehicleImportHandler) Export(c *gin.Context) {
+	format, err := service.DetectFileFormat(c.DefaultQuery("format", service.FileFormatCSV), "")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Unsupported export format", err.Error())
+		return
+	}
+
+	filter := interfaces.VehicleFilter{
+		Status:   c.Query("status"),
+		Type:     c.Query("type"),
+		Category: c.Query("category"),
+		Make:     c.Query("make"),
+		Search:   c.Query("search"),
+	}
+
+	contentType := "text/csv"
+	if format == service.FileFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	filename := fmt.Sprintf("vehicles-%s.%s", time.Now().Format("20060102-150405"), format)
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent once streaming starts, so failures can only be logged
+	if err := h.importService.Export(c.Writer, format, filter); err != nil {
+		h.logger.WithError(err).Error("Failed to export vehicles")
+		c.Abort()
+	}
+}
+
+// openImportFile opens an uploaded CSV or XLSX import file and determines its format
+func openImportFile(c *gin.Context) (multipart.File, *multipart.FileHeader, string, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Import file is required", err.Error())
+		return nil, nil, "", false
+	}
+	if header.Size > maxImportFileSize {
+		response.Error(c, http.StatusBadRequest, "Import file too large", "Maximum file size is 10 MB")
+		return nil, nil, "", false
+	}
+
+	format, err := service.DetectFileFormat(c.PostForm("format"), header.Filename)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Unsupported file format", err.Error())
+		return nil, nil, "", false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to read import file", err.Error())
+		return nil, nil, "", false
+	}
+	return file, header, format, true
+}
+
+// handleError maps vehicle import service errors to HTTP responses
+func (h *VehicleImportHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrUnsupportedFileFormat),
+		errors.Is(err, service.ErrEmptyImportFile),
+		errors.Is(err, service.ErrInvalidColumnMapping),
+		isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// VehicleStatusHandler handles realtime vehicle status HTTP requests
+type VehicleStatusHandler struct {
+	statusService *service.VehicleStatusService
+	logger        *logrus.Logger
+}
+
+// NewVehicleStatusHandler creates a new vehicle status handler
+func NewVehicleStatusHandler(statusService *service.VehicleStatusService, logger *logrus.Logger) *VehicleStatusHandler {
+	return &VehicleStatusHandler{
+		statusService: statusService,
+		logger:        logger,
+	}
+}
+
+// GetVehicleStatus returns the realtime status of a vehicle
+// @Summary Get vehicle realtime status
+// @Description Last position, speed, ignition, fuel, battery, active DTC count and online flag
+// @Tags vehicles
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} response.Response "Vehicle status retrieved successfully"
+// @Failure 403 {object} response.Response "Vehicle not assigned to driver"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /vehicles/{id}/realtime-status [get]
+func (h *VehicleStatusHandler) GetVehicleStatus(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	vehicleID, ok := parseIDParam(c, "id", "vehicle")
+	if !ok {
+		return
+	}
+
+	status, err := h.statusService.GetStatus(viewer, vehicleID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve vehicle status")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Vehicle status retrieved successfully", status)
+}
+
+// GetFleetStatus returns the realtime status of all visible vehicles with telematics
+// @Summary Get fleet realtime status
+// @Tags vehicles
+// @Produce json
+// @Param online query bool false "Only online (true) or offline (false) vehicles"
+// @Success 200 {object} response.Response "Fleet status retrieved successfully"
+// @Router /vehicles/realtime-status [get]
+func (h *VehicleStatusHandler) GetFleetStatus(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	var filter service.FleetStatusFilter
+	if raw := c.Query("online"); raw != "" {
+		online, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid online filter", err.Error())
+			return
+		}
+		filter.Online = &online
+	}
+
+	fleet, err := h.statusService.ListStatuses(viewer, filter)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve fleet status")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Fleet status retrieved successfully", fleet)
+}
+
+// handleError maps vehicle status service errors to HTTP responses
+func (h *VehicleStatusHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
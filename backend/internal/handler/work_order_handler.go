@@ -0,0 +1,527 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/internal/service"
+	"ton-platform/pkg/response"
+)
+
+// WorkOrderHandler handles work order HTTP requests
+type WorkOrderHandler struct {
+	workOrderService *service.WorkOrderService
+	logger           *logrus.Logger
+}
+
+// NewWorkOrderHandler creates a new work order handler
+func NewWorkOrderHandler(workOrderService *service.WorkOrderService, logger *logrus.Logger) *WorkOrderHandler {
+	return &WorkOrderHandler{
+		workOrderService: workOrderService,
+		logger:           logger,
+	}
+}
+
+// Create opens a work order
+// @Summary Create work order
+// @Description The work order starts as pending with the current user as service advisor
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param request body service.CreateWorkOrderRequest true "Work order"
+// @Success 201 {object} response.Response "Work order created successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Vehicle not found"
+// @Router /workorders [post]
+func (h *WorkOrderHandler) Create(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	var req service.CreateWorkOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	workOrder, err := h.workOrderService.Create(viewer, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create work order")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Work order created successfully", workOrder)
+}
+
+// List lists work orders
+// @Summary List work orders
+// @Description Most urgent first, then newest first. Mechanics only see the work orders assigned
+// @Description to them and drivers those of their vehicles.
+// @Tags work-orders
+// @Produce json
+// @Param vehicle_id query int false "Filter by vehicle"
+// @Param mechanic_id query int false "Filter by assigned mechanic"
+// @Param status query string false "Filter by status"
+// @Param priority query string false "Filter by priority"
+// @Param service_type query string false "Filter by service type"
+// @Param search query string false "Work order number or customer name"
+// @Param from query string false "Created at or after (RFC3339)"
+// @Param to query string false "Created at or before (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response "Work orders retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /workorders [get]
+func (h *WorkOrderHandler) List(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	vehicleID, ok := parseOptionalID(c, "vehicle_id")
+	if !ok {
+		return
+	}
+	mechanicID, ok := parseOptionalID(c, "mechanic_id")
+	if !ok {
+		return
+	}
+	from, to, ok := parseTimeRange(c, time.Time{}, time.Time{})
+	if !ok {
+		return
+	}
+	filter := interfaces.WorkOrderFilter{
+		VehicleID:   vehicleID,
+		MechanicID:  mechanicID,
+		Status:      c.Query("status"),
+		Priority:    c.Query("priority"),
+		ServiceType: c.Query("service_type"),
+		Search:      c.Query("search"),
+		From:        optionalTime(from),
+		To:          optionalTime(to),
+	}
+	page, limit, offset := parsePagination(c)
+
+	workOrders, err := h.workOrderService.List(viewer, filter, page, limit, offset)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve work orders")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work orders retrieved successfully", workOrders)
+}
+
+// GetByID returns a work order
+// @Summary Get work order
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Work order retrieved successfully"
+// @Failure 403 {object} response.Response "Work order not accessible"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id} [get]
+func (h *WorkOrderHandler) GetByID(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	workOrder, err := h.workOrderService.Get(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve work order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order retrieved successfully", workOrder)
+}
+
+// Update changes the details of a work order
+// @Summary Update work order
+// @Description Status and assignment only change through the transition endpoints
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.WorkOrderRequest true "Work order details"
+// @Success 200 {object} response.Response "Work order updated successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Work order is completed or cancelled"
+// @Router /workorders/{id} [put]
+func (h *WorkOrderHandler) Update(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.WorkOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	workOrder, err := h.workOrderService.Update(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update work order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order updated successfully", workOrder)
+}
+
+// Delete deletes a work order
+// @Summary Delete work order
+// @Description Only pending or cancelled work orders can be deleted
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Work order deleted successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Work order cannot be deleted"
+// @Router /workorders/{id} [delete]
+func (h *WorkOrderHandler) Delete(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	if err := h.workOrderService.Delete(viewer, id); err != nil {
+		h.handleError(c, err, "Failed to delete work order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order deleted successfully", nil)
+}
+
+// GetStatusHistory returns the status changes of a work order
+// @Summary Get work order status history
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Work order history retrieved successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Router /workorders/{id}/history [get]
+func (h *WorkOrderHandler) GetStatusHistory(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	history, err := h.workOrderService.GetStatusHistory(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve work order history")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order history retrieved successfully", history)
+}
+
+// AssignmentCandidates ranks the mechanics for a work order
+// @Summary List assignment candidates
+// @Description Mechanics of the work order's branch and those without a branch, ranked by skill match,
+// @Description open hours against their shifts over the coming week and how soon they are on shift.
+// @Description The first candidate is recommended when it is qualified, available and scheduled.
+// @Tags work-orders
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Success 200 {object} response.Response "Assignment candidates retrieved successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Work order is closed"
+// @Router /workorders/{id}/assignment-candidates [get]
+func (h *WorkOrderHandler) AssignmentCandidates(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	candidates, err := h.workOrderService.AssignmentCandidates(viewer, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve assignment candidates")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Assignment candidates retrieved successfully", candidates)
+}
+
+// Assign assigns a work order to a mechanic
+// @Summary Assign work order
+// @Description Pending work orders become assigned; assigned ones that have not started can be reassigned.
+// @Description Without mechanic_id the recommended candidate is assigned. Assigning anyone else requires
+// @Description an override_reason, which is recorded in the status history.
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.AssignWorkOrderRequest false "Mechanic"
+// @Success 200 {object} response.Response "Work order assigned successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /workorders/{id}/assign [put]
+func (h *WorkOrderHandler) Assign(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.AssignWorkOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	workOrder, err := h.workOrderService.Assign(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to assign work order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order assigned successfully", workOrder)
+}
+
+// Start begins work on a work order
+// @Summary Start work order
+// @Description Moves an assigned work order to in_progress
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.WorkOrderTransitionRequest false "Note"
+// @Success 200 {object} response.Response "Work order started successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /workorders/{id}/start [put]
+func (h *WorkOrderHandler) Start(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.WorkOrderTransitionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	workOrder, err := h.workOrderService.Start(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to start work order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order started successfully", workOrder)
+}
+
+// Hold pauses a work order
+// @Summary Hold work order
+// @Description Moves a work order in progress to on_hold or waiting_for_parts; a note is required
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.HoldWorkOrderRequest true "Hold reason"
+// @Success 200 {object} response.Response "Work order put on hold successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /workorders/{id}/hold [put]
+func (h *WorkOrderHandler) Hold(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.HoldWorkOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	workOrder, err := h.workOrderService.Hold(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to put work order on hold")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order put on hold successfully", workOrder)
+}
+
+// Resume continues a paused work order
+// @Summary Resume work order
+// @Description Moves a work order on hold or waiting for parts back to in_progress
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.WorkOrderTransitionRequest false "Note"
+// @Success 200 {object} response.Response "Work order resumed successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /workorders/{id}/resume [put]
+func (h *WorkOrderHandler) Resume(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.WorkOrderTransitionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	workOrder, err := h.workOrderService.Resume(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to resume work order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order resumed successfully", workOrder)
+}
+
+// Complete finishes a work order
+// @Summary Complete work order
+// @Description Only work orders in progress can be completed
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.CompleteWorkOrderRequest false "Completion details"
+// @Success 200 {object} response.Response "Work order completed successfully"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /workorders/{id}/complete [put]
+func (h *WorkOrderHandler) Complete(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.CompleteWorkOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ValidationError(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	workOrder, err := h.workOrderService.Complete(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to complete work order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order completed successfully", workOrder)
+}
+
+// Cancel cancels a work order
+// @Summary Cancel work order
+// @Description Any work order that is not completed can be cancelled; a note is required
+// @Tags work-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Work order ID"
+// @Param request body service.CancelWorkOrderRequest true "Cancellation reason"
+// @Success 200 {object} response.Response "Work order cancelled successfully"
+// @Failure 400 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "Work order not found"
+// @Failure 409 {object} response.Response "Invalid status transition"
+// @Router /workorders/{id}/cancel [put]
+func (h *WorkOrderHandler) Cancel(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id", "work order")
+	if !ok {
+		return
+	}
+
+	var req service.CancelWorkOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Validation failed", err.Error())
+		return
+	}
+
+	workOrder, err := h.workOrderService.Cancel(viewer, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel work order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Work order cancelled successfully", workOrder)
+}
+
+// handleError maps work order service errors to HTTP responses
+func (h *WorkOrderHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrWorkOrderNotFound),
+		errors.Is(err, service.ErrVehicleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrWorkOrderAccessDenied),
+		errors.Is(err, service.ErrVehicleAccessDenied):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidWorkOrderTransition),
+		errors.Is(err, service.ErrWorkOrderClosed),
+		errors.Is(err, service.ErrWorkOrderNotDeletable):
+		response.Error(c, http.StatusConflict, message, err.Error())
+	case errors.Is(err, service.ErrInvalidTimeRange):
+		response.Error(c, http.StatusBadRequest, "Invalid time range", err.Error())
+	case isValidationError(err):
+		response.Error(c, http.StatusBadRequest, message, err.Error())
+	default:
+		h.logger.WithError(err).Error(message)
+		response.Error(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
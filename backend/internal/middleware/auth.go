@@ -110,6 +110,21 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	}
 }
 
+// RequireStreamAuth middleware requires valid JWT token and also accepts it from the
+// access_token query parameter, since browsers cannot set headers on WebSocket and
+// EventSource requests
+func (m *AuthMiddleware) RequireStreamAuth() gin.HandlerFunc {
+	requireAuth := m.RequireAuth()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		requireAuth(c)
+	}
+}
+
 // OptionalAuth middleware validates token if present but doesn't require it
 func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/pkg/security"
+)
+
+const (
+	// maxDeviceRequestBody bounds the request bodies of all device requests
+	maxDeviceRequestBody = 8 << 20
+	// maxSignatureSkew is the accepted clock difference for signed device requests
+	maxSignatureSkew = 5 * time.Minute
+)
+
+// DeviceAuthMiddleware authenticates telematics devices using their registered credentials
+type DeviceAuthMiddleware struct {
+	deviceRepo interfaces.DeviceRepository
+	logger     *logrus.Logger
+}
+
+// NewDeviceAuthMiddleware creates a new device authentication middleware
+func NewDeviceAuthMiddleware(deviceRepo interfaces.DeviceRepository, logger *logrus.Logger) *DeviceAuthMiddleware {
+	return &DeviceAuthMiddleware{
+		deviceRepo: deviceRepo,
+		logger:     logger,
+	}
+}
+
+// RequireDevice middleware requires a registered, active device and caps its request body.
+// Token devices send "Authorization: Device <token>"; HMAC devices send X-Device-Timestamp
+// (unix seconds) and X-Device-Signature (hex HMAC-SHA256 of "<timestamp>.<body>").
+func (m *DeviceAuthMiddleware) RequireDevice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDeviceRequestBody)
+
+		identifier := c.GetHeader("X-Device-ID")
+		if identifier == "" {
+			m.reject(c, http.StatusUnauthorized, "X-Device-ID header is required", "missing_device_id")
+			return
+		}
+
+		device, err := m.deviceRepo.GetByIdentifier(identifier)
+		if err != nil {
+			m.logger.WithError(err).WithField("device", identifier).Warn("Unknown device in authentication middleware")
+			m.reject(c, http.StatusUnauthorized, "Invalid device credentials", "invalid_device_credentials")
+			return
+		}
+
+		var authenticated bool
+		switch device.AuthType {
+		case domain.DeviceAuthHMAC:
+			authenticated = m.verifySignature(c, device)
+		default:
+			authenticated = m.verifyToken(c, device)
+		}
+		if c.IsAborted() {
+			return
+		}
+		if !authenticated {
+			m.logger.WithField("device", identifier).Warn("Device authentication failed")
+			m.reject(c, http.StatusUnauthorized, "Invalid device credentials", "invalid_device_credentials")
+			return
+		}
+
+		if device.Status != domain.DeviceStatusActive {
+			m.reject(c, http.StatusForbidden, "Device is not active", "device_inactive")
+			return
+		}
+
+		c.Set("device", device)
+		c.Next()
+	}
+}
+
+// verifyToken checks the bearer token of a token device
+func (m *DeviceAuthMiddleware) verifyToken(c *gin.Context, device *domain.TelematicsDevice) bool {
+	parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+	if len(parts) != 2 || parts[0] != "Device" || parts[1] == "" || device.CredentialHash == "" {
+		return false
+	}
+	return security.VerifyToken(device.CredentialHash, parts[1])
+}
+
+// verifySignature checks the request signature of an HMAC device and restores the body for handlers
+func (m *DeviceAuthMiddleware) verifySignature(c *gin.Context, device *domain.TelematicsDevice) bool {
+	timestamp := c.GetHeader("X-Device-Timestamp")
+	signature := c.GetHeader("X-Device-Signature")
+	if timestamp == "" || signature == "" || device.HMACSecret == "" {
+		return false
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	skew := time.Since(time.Unix(seconds, 0))
+	if skew > maxSignatureSkew || skew < -maxSignatureSkew {
+		m.reject(c, http.StatusUnauthorized, "Request timestamp outside the accepted window", "stale_device_signature")
+		return false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			m.reject(c, http.StatusRequestEntityTooLarge, "Request body too large", "request_too_large")
+			return false
+		}
+		m.reject(c, http.StatusBadRequest, "Failed to read request body", "invalid_request_body")
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	return security.VerifyDeviceSignature(device.HMACSecret, timestamp, body, signature)
+}
+
+func (m *DeviceAuthMiddleware) reject(c *gin.Context, status int, message, code string) {
+	c.JSON(status, gin.H{
+		"success": false,
+		"message": message,
+		"error":   code,
+	})
+	c.Abort()
+}
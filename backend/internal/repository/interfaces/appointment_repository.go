@@ -0,0 +1,53 @@
+package interfaces
+
+import (
+	"errors"
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// Errors returned by Create and Update when the database constraints reject an appointment
+var (
+	// ErrAppointmentOverlap means a scheduled appointment of the bay or mechanic overlaps the appointment
+	ErrAppointmentOverlap = errors.New("appointment overlaps a scheduled appointment of the bay or mechanic")
+	// ErrAppointmentScheduled means the work order already has a scheduled appointment
+	ErrAppointmentScheduled = errors.New("work order already has a scheduled appointment")
+)
+
+// AppointmentFilter narrows appointment queries to those overlapping [From, To); empty fields are ignored
+type AppointmentFilter struct {
+	From       time.Time
+	To         time.Time
+	BranchID   uint
+	BayID      uint
+	MechanicID uint
+	Status     string
+}
+
+// AppointmentRepository defines the interface for workshop bay and appointment data access operations
+type AppointmentRepository interface {
+	// Bay operations
+	CreateBay(bay *domain.WorkshopBay) error
+	GetBay(id uint) (*domain.WorkshopBay, error)
+	// ListBays retrieves the bays ordered by branch and name, optionally of one branch
+	ListBays(branchID uint, activeOnly bool) ([]*domain.WorkshopBay, error)
+	UpdateBay(bay *domain.WorkshopBay) error
+
+	// Appointment operations
+	// Create creates an appointment; it fails with ErrAppointmentOverlap or ErrAppointmentScheduled
+	// when it would double book a bay, mechanic or work order
+	Create(appointment *domain.WorkOrderAppointment) error
+	GetByID(id uint) (*domain.WorkOrderAppointment, error)
+	// GetScheduled retrieves the scheduled appointment of a work order
+	GetScheduled(workOrderID uint) (*domain.WorkOrderAppointment, error)
+	ListByWorkOrder(workOrderID uint) ([]*domain.WorkOrderAppointment, error)
+	// List retrieves the appointments overlapping the filter range with their bay and work order, earliest first
+	List(filter AppointmentFilter) ([]*domain.WorkOrderAppointment, error)
+	// ListConflicting retrieves the scheduled appointments on the bay, or with the mechanic, that
+	// overlap [from, to), leaving out one appointment
+	ListConflicting(bayID uint, mechanicID *uint, from, to time.Time, excludeID uint) ([]*domain.WorkOrderAppointment, error)
+	// Update saves the booking fields of an appointment; it fails with ErrAppointmentOverlap when
+	// it would double book a bay or mechanic
+	Update(appointment *domain.WorkOrderAppointment) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import "ton-platform/internal/domain"
+
+// AttachmentRepository defines the interface for attachment data access operations
+type AttachmentRepository interface {
+	Create(attachment *domain.Attachment) error
+	GetByID(id uint) (*domain.Attachment, error)
+	// ListByOwner retrieves the attachments of a work order, vehicle or inspection, latest first
+	ListByOwner(ownerType string, ownerID uint) ([]*domain.Attachment, error)
+	// Delete deletes an attachment and the work order photo pointing at it
+	Delete(id uint) error
+
+	// CreateWorkOrderPhoto lists an image attachment among the photos of its work order
+	CreateWorkOrderPhoto(photo *domain.WorkOrderPhoto) error
+}
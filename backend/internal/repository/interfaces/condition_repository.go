@@ -0,0 +1,47 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// ConditionAlertFilter narrows condition alert queries; empty fields are ignored
+type ConditionAlertFilter struct {
+	VehicleID    uint
+	RuleID       uint
+	Status       string
+	Severity     string
+	Acknowledged *bool
+	From         *time.Time
+	To           *time.Time
+}
+
+// ConditionRepository defines the interface for condition monitoring data access operations
+type ConditionRepository interface {
+	// Rule operations
+	CreateRule(rule *domain.ConditionRule) error
+	GetRule(id uint) (*domain.ConditionRule, error)
+	UpdateRule(rule *domain.ConditionRule) error
+	DeleteRule(id uint) error
+	ListRules() ([]*domain.ConditionRule, error)
+	ListActiveRules() ([]*domain.ConditionRule, error)
+	// ResetRule clears the active alerts of a rule at t and forgets its pending breaches
+	ResetRule(ruleID uint, at time.Time) error
+
+	// Vehicle state operations
+	GetVehicleStates(vehicleIDs []uint) ([]*domain.ConditionVehicleState, error)
+	// SaveVehicleStates upserts states, keeping stored states with a newer sample
+	SaveVehicleStates(states []*domain.ConditionVehicleState) error
+
+	// Alert operations
+	GetActiveAlerts(vehicleIDs []uint) ([]*domain.ConditionAlert, error)
+	// SaveAlerts creates new alerts and updates existing ones in one transaction
+	SaveAlerts(alerts []*domain.ConditionAlert) error
+	GetAlert(id uint) (*domain.ConditionAlert, error)
+	UpdateAlert(alert *domain.ConditionAlert) error
+	ListAlerts(filter ConditionAlertFilter, offset, limit int) ([]*domain.ConditionAlert, int64, error)
+	SetAlertWorkOrder(id, workOrderID uint) error
+	// FindOpenWorkOrderID returns the unfinished work order linked to any alert of a rule for a vehicle, or 0
+	FindOpenWorkOrderID(vehicleID, ruleID uint) (uint, error)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import "ton-platform/internal/domain"
+
+// DamageReportRepository defines the interface for damage report data access operations
+type DamageReportRepository interface {
+	// CRUD operations
+	Create(report *domain.DamageReport) error
+	GetByID(id uint) (*domain.DamageReport, error)
+
+	// UpdateStatus locks the report and passes it to check, which sets the new status and
+	// resolution time. Only those two columns are written.
+	UpdateStatus(id uint, check func(report *domain.DamageReport) error) (*domain.DamageReport, error)
+	// Charge locks the report and passes it to check with the invoice, which is locked when it
+	// exists and created when its ID is zero. item is then added to the invoice and the report
+	// is marked charged with it.
+	Charge(id uint, invoice *domain.Invoice, item *domain.InvoiceItem, check func(report *domain.DamageReport, invoice *domain.Invoice) error) (*domain.DamageReport, error)
+	// CreateRepairWorkOrder locks the report and passes it to check, then creates the work order
+	// and links it to the report as its repair.
+	CreateRepairWorkOrder(id uint, workOrder *domain.WorkOrder, check func(report *domain.DamageReport) error) error
+
+	// Query operations
+	GetByVehicle(vehicleID uint, status string) ([]*domain.DamageReport, error)
+	GetByInspection(inspectionID uint) ([]*domain.DamageReport, error)
+}
@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// DeviceRepository defines the interface for telematics device data access operations
+type DeviceRepository interface {
+	// Device operations
+	Create(device *domain.TelematicsDevice) error
+	GetByID(id uint) (*domain.TelematicsDevice, error)
+	GetByIdentifier(identifier string) (*domain.TelematicsDevice, error)
+	Update(device *domain.TelematicsDevice) error
+	List(status, search string, offset, limit int) ([]*domain.TelematicsDevice, int64, error)
+	UpdateLastSeen(id uint, at time.Time) error
+
+	// Binding operations
+	Bind(binding *domain.DeviceBinding) error
+	Unbind(deviceID uint, at time.Time, userID uint) error
+	GetCurrentBinding(deviceID uint) (*domain.DeviceBinding, error)
+	GetBindings(deviceID uint) ([]*domain.DeviceBinding, error)
+	GetBindingsInRange(deviceID uint, from, to time.Time) ([]*domain.DeviceBinding, error)
+	GetBindingsByVehicle(vehicleID uint) ([]*domain.DeviceBinding, error)
+}
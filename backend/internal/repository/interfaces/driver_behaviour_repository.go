@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// DriverBehaviourTotals sums the trip behaviour of a driver in a week
+type DriverBehaviourTotals struct {
+	DriverID  uint      `json:"driver_id"`
+	WeekStart time.Time `json:"week_start"`
+	Trips     int       `json:"trips"`
+	domain.DriverBehaviourMetrics
+}
+
+// DriverBehaviourRepository defines the interface for driver behaviour data access operations
+type DriverBehaviourRepository interface {
+	GetSettings() (*domain.DriverBehaviourSettings, error)
+	UpdateSettings(settings *domain.DriverBehaviourSettings) error
+
+	// GetAssignments returns the assignments of a vehicle that overlap [from, to], oldest first
+	GetAssignments(vehicleID uint, from, to time.Time) ([]*domain.VehicleDriverAssignment, error)
+
+	// ReplaceTripBehaviours atomically replaces the behaviour rows of trips of a vehicle. It is
+	// serialized with trip replacement; rows of trips replaced in the meantime are dropped.
+	ReplaceTripBehaviours(vehicleID uint, tripIDs []uint, behaviours []*domain.DriverTripBehaviour) error
+	ListTripBehaviours(driverID uint, from, to time.Time, offset, limit int) ([]*domain.DriverTripBehaviour, int64, error)
+
+	// WeeklyTotals sums the trips of weeks starting in [from, to]; driverID 0 covers every driver
+	// with a user account
+	WeeklyTotals(driverID uint, from, to time.Time) ([]*DriverBehaviourTotals, error)
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// DTCReportResult describes how a report changed the active codes of a vehicle
+type DTCReportResult struct {
+	Opened   []*domain.DTCCode // codes that became active
+	Updated  []*domain.DTCCode // active codes reported again
+	Resolved []*domain.DTCCode // active codes missing from the report
+}
+
+// DTCRepository defines the interface for diagnostic trouble code data access operations
+type DTCRepository interface {
+	// CountActiveByVehicles counts active codes per vehicle, for all vehicles when vehicleIDs is nil
+	CountActiveByVehicles(vehicleIDs []uint) (map[uint]int, error)
+
+	// ApplyReport reconciles the active codes of a vehicle with the full set of codes it reported at
+	// reportedAt. Reports are serialized per vehicle; a report older than a code's last sighting
+	// neither resolves nor reopens that code.
+	ApplyReport(vehicleID uint, reportedAt time.Time, codes []*domain.DTCCode) (*DTCReportResult, error)
+	ListByVehicle(vehicleID uint, active *bool, offset, limit int) ([]*domain.DTCCode, int64, error)
+	// LinkWorkOrder links a code occurrence to the unfinished work order linked to any occurrence
+	// of the same code on the vehicle and returns its ID. When there is none, workOrder is created
+	// and linked instead. Calls are serialized per vehicle with ApplyReport.
+	LinkWorkOrder(code *domain.DTCCode, workOrder *domain.WorkOrder) (uint, error)
+
+	// Severity rule operations
+	CreateRule(rule *domain.DTCSeverityRule) error
+	GetRule(id uint) (*domain.DTCSeverityRule, error)
+	UpdateRule(rule *domain.DTCSeverityRule) error
+	DeleteRule(id uint) error
+	ListRules() ([]*domain.DTCSeverityRule, error)
+	ListActiveRules() ([]*domain.DTCSeverityRule, error)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"ton-platform/internal/domain"
+)
+
+// EstimateRepository defines the interface for work order estimate data access operations
+type EstimateRepository interface {
+	// Create locks the work order, numbers the estimate as its next version and stores it with its
+	// lines. Earlier versions still in draft or waiting for the customer are superseded.
+	Create(estimate *domain.WorkOrderEstimate) error
+	GetByID(id uint) (*domain.WorkOrderEstimate, error)
+	// GetByTokenHash retrieves an estimate by the SHA-256 of its approval link token
+	GetByTokenHash(hash string) (*domain.WorkOrderEstimate, error)
+	// ListByWorkOrder retrieves the estimates of a work order with their lines, newest version first
+	ListByWorkOrder(workOrderID uint) ([]*domain.WorkOrderEstimate, error)
+	// Save locks the estimate and passes it with its lines to apply, which changes them in place.
+	// Lines apply dropped are deleted and lines without an ID are added. When the estimate ends up
+	// approved, the work order's estimated cost is set to the approved total.
+	Save(id uint, apply func(estimate *domain.WorkOrderEstimate) error) (*domain.WorkOrderEstimate, error)
+}
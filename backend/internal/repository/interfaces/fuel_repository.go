@@ -0,0 +1,84 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// FuelReceiptFilter represents filters for listing fuel receipts
+type FuelReceiptFilter struct {
+	VehicleID uint
+	EnteredBy uint
+	Status    string
+	From      *time.Time
+	To        *time.Time
+}
+
+// FuelAnomalyFilter represents filters for listing fuel anomalies
+type FuelAnomalyFilter struct {
+	VehicleID uint
+	Type      string
+	Status    string
+	From      *time.Time
+	To        *time.Time
+}
+
+// FuelConsumptionTotals sums the trip fuel consumption of a vehicle
+type FuelConsumptionTotals struct {
+	Trips           int     `json:"trips"`
+	DistanceKm      float64 `json:"distance_km"`
+	FuelUsedPercent float64 `json:"fuel_used_percent"`
+	FuelUsedLiters  float64 `json:"fuel_used_liters"`
+	// MeasuredDistanceKm is the distance of the trips with a litre figure
+	MeasuredDistanceKm float64 `json:"measured_distance_km"`
+}
+
+// FuelRepository defines the interface for fuel analytics data access operations
+type FuelRepository interface {
+	// Fuel profile operations
+	GetProfile(vehicleID uint) (*domain.VehicleFuelProfile, error)
+	SaveProfile(profile *domain.VehicleFuelProfile) error
+	// RecalculateLiters recomputes the litres of a vehicle's events and trip consumption from a
+	// tank capacity; trips shorter than minDistanceKm get no L/100km figure
+	RecalculateLiters(vehicleID uint, capacityLiters, minDistanceKm float64) error
+
+	// SyncEvents reconciles the stored events of a vehicle overlapping [from, to] with freshly
+	// detected ones. A stored event overlapping a detected event of the same type is updated in
+	// place; the others are deleted unless an anomaly of theirs was reviewed. Syncs are
+	// serialized per vehicle. It returns the detected events with their IDs.
+	SyncEvents(vehicleID uint, from, to time.Time, events []*domain.FuelEvent) ([]*domain.FuelEvent, error)
+	// GetEventsInRange returns the events of a vehicle overlapping [from, to], oldest first;
+	// eventType may be empty
+	GetEventsInRange(vehicleID uint, eventType string, from, to time.Time) ([]*domain.FuelEvent, error)
+	ListEvents(vehicleID uint, eventType string, from, to time.Time, offset, limit int) ([]*domain.FuelEvent, int64, error)
+
+	// Fuel receipt operations
+	CreateReceipt(receipt *domain.FuelReceipt) error
+	GetReceipt(id uint) (*domain.FuelReceipt, error)
+	UpdateReceipt(receipt *domain.FuelReceipt) error
+	DeleteReceipt(id uint) error
+	ListReceipts(filter FuelReceiptFilter, offset, limit int) ([]*domain.FuelReceipt, int64, error)
+	// GetReceiptsInRange returns the receipts of a vehicle filled in [from, to]
+	GetReceiptsInRange(vehicleID uint, from, to time.Time) ([]*domain.FuelReceipt, error)
+	// GetPendingReceipts returns up to limit pending receipts filled before t, oldest first
+	GetPendingReceipts(filledBefore time.Time, limit int) ([]*domain.FuelReceipt, error)
+	// UpdateReconciliation stores only the reconciliation fields of a receipt
+	UpdateReconciliation(receipt *domain.FuelReceipt) error
+
+	// Fuel anomaly operations
+	// SaveAnomaly creates an anomaly or refreshes the open one of the same event or receipt and
+	// type; reviewed anomalies are left as they are
+	SaveAnomaly(anomaly *domain.FuelAnomaly) error
+	// DeleteOpenReceiptAnomalies removes the open anomalies of a receipt other than keepType
+	DeleteOpenReceiptAnomalies(receiptID uint, keepType string) error
+	GetAnomaly(id uint) (*domain.FuelAnomaly, error)
+	UpdateAnomaly(anomaly *domain.FuelAnomaly) error
+	ListAnomalies(filter FuelAnomalyFilter, offset, limit int) ([]*domain.FuelAnomaly, int64, error)
+
+	// ReplaceTripConsumptions atomically replaces the consumption rows of trips of a vehicle. It is
+	// serialized with trip replacement; rows of trips replaced in the meantime are dropped.
+	ReplaceTripConsumptions(vehicleID uint, tripIDs []uint, consumptions []*domain.TripFuelConsumption) error
+	ListTripConsumptions(vehicleID uint, from, to time.Time, offset, limit int) ([]*domain.TripFuelConsumption, int64, error)
+	SumTripConsumptions(vehicleID uint, from, to time.Time) (*FuelConsumptionTotals, error)
+}
@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// GeofenceEventFilter narrows geofence event queries; empty fields are ignored
+type GeofenceEventFilter struct {
+	GeofenceID uint
+	VehicleID  uint
+	EventType  string
+	From       *time.Time
+	To         *time.Time
+}
+
+// GeofenceRepository defines the interface for geofence data access operations
+type GeofenceRepository interface {
+	// Geofence operations; assignments are loaded and saved with their geofence
+	Create(geofence *domain.Geofence) error
+	GetByID(id uint) (*domain.Geofence, error)
+	Update(geofence *domain.Geofence) error
+	Delete(id uint) error
+	List(search string, active *bool, offset, limit int) ([]*domain.Geofence, int64, error)
+	ListActive() ([]*domain.Geofence, error)
+
+	// Vehicle state operations
+	GetVehicleStates(vehicleIDs []uint) ([]*domain.GeofenceVehicleState, error)
+	SaveVehicleStates(states []*domain.GeofenceVehicleState) error
+	DeleteVehicleStates(geofenceID uint) error
+
+	// Event operations
+	CreateEvents(events []*domain.GeofenceEvent) error
+	ListEvents(filter GeofenceEventFilter, offset, limit int) ([]*domain.GeofenceEvent, int64, error)
+
+	// Alert rule operations
+	CreateAlertRule(rule *domain.GeofenceAlertRule) error
+	GetAlertRule(id uint) (*domain.GeofenceAlertRule, error)
+	UpdateAlertRule(rule *domain.GeofenceAlertRule) error
+	DeleteAlertRule(id uint) error
+	ListAlertRules(geofenceID uint) ([]*domain.GeofenceAlertRule, error)
+	ListActiveAlertRules() ([]*domain.GeofenceAlertRule, error)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import "ton-platform/internal/domain"
+
+// InspectionRepository defines the interface for inspection data access operations
+type InspectionRepository interface {
+	// Template operations
+	CreateTemplate(template *domain.InspectionTemplate) error
+	GetTemplateByID(id uint) (*domain.InspectionTemplate, error)
+	GetActiveTemplateByCode(code string) (*domain.InspectionTemplate, error)
+	GetTemplates(activeOnly bool) ([]*domain.InspectionTemplate, error)
+	GetTemplateVersions(code string) ([]*domain.InspectionTemplate, error)
+	PublishTemplateVersion(template *domain.InspectionTemplate) error
+	DeactivateTemplate(id uint) error
+
+	// Inspection operations
+	Create(inspection *domain.Inspection) error
+	GetByID(id uint) (*domain.Inspection, error)
+	GetByVehicle(vehicleID uint, offset, limit int) ([]*domain.Inspection, error)
+	CountByVehicle(vehicleID uint) (int64, error)
+	// CreateDefectWorkOrder creates the work order and links it to the failed results in one
+	// transaction. Results already linked to a work order are left alone, and the work order is
+	// not created when all of them are.
+	CreateDefectWorkOrder(workOrder *domain.WorkOrder, resultIDs []uint) error
+}
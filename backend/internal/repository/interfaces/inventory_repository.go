@@ -0,0 +1,50 @@
+package interfaces
+
+import (
+	"ton-platform/internal/domain"
+)
+
+// StockTransferFilter narrows stock transfer request queries; empty fields are ignored
+type StockTransferFilter struct {
+	Status        string
+	ToWarehouseID uint
+	WorkOrderID   uint
+}
+
+// InventoryRepository defines the interface for stock, work order part and stock transfer data access operations.
+// Every stock movement locks the inventory_stock rows it changes for the length of its transaction.
+type InventoryRepository interface {
+	// Item and stock operations
+	GetItem(id uint) (*domain.InventoryItem, error)
+	GetWarehouse(id uint) (*domain.Warehouse, error)
+	// ListStock retrieves the stock of an item at the active warehouses, most available first
+	ListStock(itemID uint) ([]*domain.InventoryStock, error)
+
+	// Work order part operations
+	GetPart(id uint) (*domain.WorkOrderPart, error)
+	ListParts(workOrderID uint) ([]*domain.WorkOrderPart, error)
+	// ReservePart locks the item's stock at the part's warehouse and the part's work order, and
+	// passes the work committed to the work order to check. It then reserves as much of the part's
+	// quantity as is available and stores the part as reserved. When the stock falls short the
+	// part is stored as short and transfer is stored for the missing quantity.
+	ReservePart(part *domain.WorkOrderPart, transfer *domain.StockTransferRequest, check func(committed *WorkOrderCommitment) error) error
+	// IssuePart locks the part and passes it to check, then takes its quantity out of the
+	// reserved stock and records movement as the usage transaction. The work order's actual cost
+	// is recalculated.
+	IssuePart(id uint, movement *domain.InventoryTransaction, check func(part *domain.WorkOrderPart) error) (*domain.WorkOrderPart, error)
+	// RemovePart locks the part and passes it to check. The reservation of a part that was not
+	// issued is released and its open transfer requests are cancelled; an issued part is put back
+	// into stock and recorded as the return transaction movement.
+	RemovePart(id uint, movement *domain.InventoryTransaction, check func(part *domain.WorkOrderPart) error) (*domain.WorkOrderPart, error)
+
+	// Stock transfer operations
+	GetTransfer(id uint) (*domain.StockTransferRequest, error)
+	ListTransfers(filter StockTransferFilter, offset, limit int) ([]*domain.StockTransferRequest, int64, error)
+	// FulfilTransfer locks the transfer and the item's stock at the source and destination
+	// warehouses and passes the transfer and source stock to check. The stock is then moved,
+	// recorded as the transfer transaction movement, and reserved for the short part the
+	// transfer was raised for.
+	FulfilTransfer(id, fromWarehouseID, fulfilledBy uint, movement *domain.InventoryTransaction, check func(transfer *domain.StockTransferRequest, source *domain.InventoryStock) error) (*domain.StockTransferRequest, error)
+	// CancelTransfer locks the transfer and cancels it unless check fails
+	CancelTransfer(id uint, note string, check func(transfer *domain.StockTransferRequest) error) (*domain.StockTransferRequest, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import "ton-platform/internal/domain"
+
+// InvoiceRepository defines the interface for invoice data access operations
+type InvoiceRepository interface {
+	// CRUD operations
+	Create(invoice *domain.Invoice) error
+	GetByID(id uint) (*domain.Invoice, error)
+
+	// Line items
+	AddItem(item *domain.InvoiceItem) error
+}
@@ -0,0 +1,52 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// LaborOperationFilter narrows labor operation queries; empty fields are ignored
+type LaborOperationFilter struct {
+	Search      string // code or description
+	VehicleType string // operations that apply to the vehicle type
+	Make        string // operations that apply to the make
+	ActiveOnly  bool
+}
+
+// LaborStandardFilter narrows the standard vs actual hours comparison; empty fields are ignored
+type LaborStandardFilter struct {
+	From        time.Time // completion date range of the work orders
+	To          time.Time
+	OperationID uint
+	BranchID    uint
+}
+
+// LaborStandardRow is the labor recorded on one task that references a catalogued operation
+type LaborStandardRow struct {
+	OperationID    uint
+	Code           string
+	Description    string
+	TaskID         uint
+	WorkOrderID    uint
+	WONumber       string
+	CompletionDate time.Time
+	StandardHours  float64
+	ActualHours    float64
+	LaborCost      float64
+}
+
+// LaborOperationRepository defines the interface for labor operation catalogue data access operations
+type LaborOperationRepository interface {
+	Create(operation *domain.LaborOperation) error
+	GetByID(id uint) (*domain.LaborOperation, error)
+	GetByCodes(codes []string) ([]*domain.LaborOperation, error)
+	List(filter LaborOperationFilter, offset, limit int) ([]*domain.LaborOperation, int64, error)
+	Update(operation *domain.LaborOperation) error
+	// Import creates and updates catalogue operations in a single transaction
+	Import(created, updated []*domain.LaborOperation) error
+
+	// ListStandardComparison returns the labor recorded on the tasks of the work orders completed in
+	// the range that reference an operation. Tasks without recorded labor are left out.
+	ListStandardComparison(filter LaborStandardFilter) ([]*LaborStandardRow, error)
+}
@@ -0,0 +1,55 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// LaborEfficiencyFilter narrows the labor efficiency report; empty fields are ignored
+type LaborEfficiencyFilter struct {
+	From       time.Time // completion date range of the work orders
+	To         time.Time
+	MechanicID uint // work orders the mechanic clocked time on
+	BranchID   uint
+}
+
+// LaborEfficiencyRow is the time one mechanic clocked on one completed work order
+type LaborEfficiencyRow struct {
+	WorkOrderID    uint
+	WONumber       string
+	ServiceType    string
+	EstimatedHours float64
+	CompletionDate time.Time
+	MechanicID     uint
+	FirstName      string
+	LastName       string
+	Hours          float64
+	Cost           float64
+}
+
+// LaborRepository defines the interface for work order task, time clock and labor data access operations
+type LaborRepository interface {
+	// Task operations
+	CreateTask(task *domain.WorkOrderTask) error
+	GetTask(id uint) (*domain.WorkOrderTask, error)
+	ListTasks(workOrderID uint) ([]*domain.WorkOrderTask, error)
+
+	// Time clock operations
+	// StartEntry locks the mechanic and the entry's work order, and passes the mechanic's running
+	// entry, nil when there is none, and the work committed to the work order to check. The entry
+	// is stored unless check fails.
+	StartEntry(entry *domain.LaborTimeEntry, check func(active *domain.LaborTimeEntry, committed *WorkOrderCommitment) error) error
+	GetActiveEntry(mechanicID uint) (*domain.LaborTimeEntry, error)
+	ListActiveEntries(workOrderID uint) ([]*domain.LaborTimeEntry, error)
+	// StopEntry locks the entry's work order, ends a running entry with the EndedAt, EndReason and
+	// Note set on it and rolls the mechanic's hours on the task that day up into work order labor
+	// at the hourly rate. The work order's actual hours and cost are recalculated from its labor
+	// and issued parts.
+	StopEntry(entry *domain.LaborTimeEntry, hourlyRate float64, completeTask bool) error
+	ListEntries(workOrderID uint) ([]*domain.LaborTimeEntry, error)
+
+	// Labor operations
+	ListLabor(workOrderID uint) ([]*domain.WorkOrderLabor, error)
+	ListLaborEfficiency(filter LaborEfficiencyFilter) ([]*LaborEfficiencyRow, error)
+}
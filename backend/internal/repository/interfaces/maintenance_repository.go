@@ -0,0 +1,49 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// MaintenanceScheduleFilter narrows vehicle maintenance schedule queries; empty fields are ignored.
+// Only schedules of active plans that still match their vehicle are returned.
+type MaintenanceScheduleFilter struct {
+	PlanID    uint
+	VehicleID uint
+	AreaID    uint // service area of the vehicle
+	Status    string
+}
+
+// MeterReading is the current odometer and engine hours of a vehicle
+type MeterReading struct {
+	VehicleID   uint
+	Odometer    int     // km, the higher of the recorded odometer and the telematics total distance
+	EngineHours float64 // running time of the vehicle's recorded trips
+}
+
+// MaintenanceRepository defines the interface for preventive maintenance data access operations
+type MaintenanceRepository interface {
+	CreatePlan(plan *domain.MaintenancePlan) error
+	GetPlan(id uint) (*domain.MaintenancePlan, error)
+	ListPlans(activeOnly bool) ([]*domain.MaintenancePlan, error)
+	UpdatePlan(plan *domain.MaintenancePlan) error
+
+	// ListUnscheduledVehicles returns the vehicles matching a plan that have no active schedule for it
+	ListUnscheduledVehicles(plan *domain.MaintenancePlan) ([]*domain.Vehicle, error)
+	// CreateSchedules creates schedules, reactivating a retired schedule of the same plan and vehicle
+	CreateSchedules(schedules []*domain.VehicleMaintenanceSchedule) error
+	GetSchedule(id uint) (*domain.VehicleMaintenanceSchedule, error)
+	ListSchedules(filter MaintenanceScheduleFilter, offset, limit int) ([]*domain.VehicleMaintenanceSchedule, int64, error)
+	ListSchedulesByWorkOrder(workOrderID uint) ([]*domain.VehicleMaintenanceSchedule, error)
+	// FindActiveSchedulesInBatches walks the active schedules of active plans with their plan and vehicle
+	FindActiveSchedulesInBatches(batchSize int, fn func(schedules []*domain.VehicleMaintenanceSchedule) error) error
+	UpdateSchedule(schedule *domain.VehicleMaintenanceSchedule) error
+
+	// ReadMeters returns the current meter readings of the vehicles keyed by vehicle ID
+	ReadMeters(vehicleIDs []uint) (map[uint]*MeterReading, error)
+	// SyncVehicleServiceDates sets the vehicles' next service date to the earliest due date of their active schedules
+	SyncVehicleServiceDates(vehicleIDs []uint) error
+	// RecordVehicleService moves the vehicle's last service date up to doneAt
+	RecordVehicleService(vehicleID uint, doneAt time.Time) error
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// MechanicRepository defines the interface for mechanic profile and shift data access operations
+type MechanicRepository interface {
+	// Profile operations
+	GetProfile(userID uint) (*domain.MechanicProfile, error)
+	// ListProfiles retrieves the profiles of the given mechanics; mechanics without a profile are left out
+	ListProfiles(userIDs []uint) ([]*domain.MechanicProfile, error)
+	// SaveProfile creates or updates a profile and replaces its skills
+	SaveProfile(profile *domain.MechanicProfile) error
+
+	// Shift operations
+	CreateShift(shift *domain.MechanicShift) error
+	GetShift(id uint) (*domain.MechanicShift, error)
+	DeleteShift(id uint) error
+	// ListShifts retrieves the shifts of the mechanics overlapping [from, to), earliest first
+	ListShifts(userIDs []uint, from, to time.Time) ([]*domain.MechanicShift, error)
+}
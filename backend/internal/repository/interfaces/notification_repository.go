@@ -0,0 +1,13 @@
+package interfaces
+
+import "ton-platform/internal/domain"
+
+// NotificationRepository defines the interface for notification data access operations
+type NotificationRepository interface {
+	CreateBatch(notifications []*domain.Notification) error
+	ListByUser(userID uint, unreadOnly bool, offset, limit int) ([]*domain.Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	// MarkRead marks one notification of a user as read
+	MarkRead(userID, id uint) error
+	MarkAllRead(userID uint) (int64, error)
+}
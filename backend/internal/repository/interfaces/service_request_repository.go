@@ -0,0 +1,41 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// ServiceRequestFilter narrows service request queries; empty fields are ignored
+type ServiceRequestFilter struct {
+	VehicleID   uint
+	RequestedBy uint
+	AreaIDs     []uint // restricts the result to these areas when not nil
+	WithoutArea bool   // with AreaIDs, also includes requests raised without an area
+	Status      string
+	Urgency     string
+	From        *time.Time
+	To          *time.Time
+}
+
+// ServiceRequestRepository defines the interface for service request data access operations
+type ServiceRequestRepository interface {
+	// Request operations
+	Create(request *domain.ServiceRequest) error
+	GetByID(id uint) (*domain.ServiceRequest, error)
+	List(filter ServiceRequestFilter, offset, limit int) ([]*domain.ServiceRequest, int64, error)
+	// Transition locks the request and passes it to apply, which changes it in place. A work order
+	// returned by apply is created with its initial status history entry and linked to the request.
+	// Errors from apply abort the transition unchanged.
+	Transition(id uint, apply func(request *domain.ServiceRequest) (*domain.WorkOrder, error)) (*domain.ServiceRequest, error)
+
+	// Area operations
+	CreateArea(area *domain.ServiceArea) error
+	GetArea(id uint) (*domain.ServiceArea, error)
+	UpdateArea(area *domain.ServiceArea) error
+	ListAreas() ([]*domain.ServiceArea, error)
+	GetAreaIDsByManager(managerID uint) ([]uint, error)
+	// AssignToArea moves vehicles and branches into an area
+	AssignToArea(areaID uint, vehicleIDs, branchIDs []uint) error
+	GetBranch(id uint) (*domain.Warehouse, error)
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// TelematicsRepository defines the interface for telematics data access operations
+type TelematicsRepository interface {
+	// InsertBatch bulk-inserts samples, skipping any whose (device, timestamp) is already stored.
+	// The returned slice reports for each input sample whether it was inserted.
+	InsertBatch(samples []*domain.TelematicsData) ([]bool, error)
+
+	// GetByVehicle returns the samples of a vehicle in [from, to] ordered by timestamp
+	GetByVehicle(vehicleID uint, from, to time.Time) ([]*domain.TelematicsData, error)
+	// GetLastBefore returns the newest sample of a vehicle before t
+	GetLastBefore(vehicleID uint, t time.Time) (*domain.TelematicsData, error)
+	// GetVehicleIDsInRange returns the vehicles with samples in [from, to]
+	GetVehicleIDsInRange(from, to time.Time) ([]uint, error)
+
+	// QueueReprocess queues vehicles (vehicle ID -> earliest changed sample) for a consumer,
+	// keeping the earlier time of vehicles already queued
+	QueueReprocess(consumer string, earliest map[uint]time.Time) error
+	// TakeReprocess dequeues and returns every vehicle queued for a consumer
+	TakeReprocess(consumer string) (map[uint]time.Time, error)
+}
@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// TelematicsStorageRepository defines the interface for telematics partition, compaction and
+// retention operations
+type TelematicsStorageRepository interface {
+	// ListPartitionDays returns the days that have a raw sample partition, oldest first
+	ListPartitionDays() ([]time.Time, error)
+	// CreatePartition creates the partition of a day, moving its samples out of the default partition
+	CreatePartition(day time.Time) error
+	DropPartition(day time.Time) error
+	// PurgeDefaultPartition deletes samples older than t that landed in the default partition
+	PurgeDefaultPartition(before time.Time) (int64, error)
+
+	// EnqueueCompaction queues vehicle hours for aggregation, touching those already queued
+	EnqueueCompaction(tasks []*domain.TelematicsCompactionTask) error
+	// GetCompactionTasks returns up to limit queued vehicle hours, oldest hour first
+	GetCompactionTasks(limit int) ([]*domain.TelematicsCompactionTask, error)
+	// Compact recomputes the 1-minute and 1-hour aggregates of a queued vehicle hour from raw
+	// samples and dequeues it unless it was queued again meanwhile
+	Compact(task *domain.TelematicsCompactionTask) error
+	// OldestQueuedHour returns the oldest queued hour, nil when the queue is empty
+	OldestQueuedHour() (*time.Time, error)
+
+	// GetAggregates returns the buckets of a vehicle at a resolution in [from, to], oldest first
+	GetAggregates(resolution string, vehicleID uint, from, to time.Time) ([]*domain.TelematicsAggregate, error)
+	// DeleteAggregatesBefore deletes buckets at a resolution starting before t
+	DeleteAggregatesBefore(resolution string, before time.Time) (int64, error)
+}
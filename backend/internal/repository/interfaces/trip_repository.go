@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// TripSegment is the result of re-segmenting a range: the trips that replace the stored trips
+// intersecting [From, To]
+type TripSegment struct {
+	From  time.Time
+	To    time.Time
+	Trips []*domain.Trip
+}
+
+// TripRepository defines the interface for trip data access operations
+type TripRepository interface {
+	GetByID(id uint) (*domain.Trip, error)
+	ListByVehicle(vehicleID uint, from, to time.Time, offset, limit int) ([]*domain.Trip, int64, error)
+	// Resegment passes the trips of a vehicle that intersect [from, to] to segment, then atomically
+	// deletes the trips intersecting the range it returns and stores its trips instead. Calls for the
+	// same vehicle are serialized from the read to the write, so segment never works from trips
+	// another call is about to replace.
+	Resegment(vehicleID uint, from, to time.Time, segment func(existing []*domain.Trip) (*TripSegment, error)) error
+}
@@ -0,0 +1,32 @@
+package interfaces
+
+import "ton-platform/internal/domain"
+
+// VehicleFilter narrows vehicle queries; empty fields are ignored
+type VehicleFilter struct {
+	Status   string
+	Type     string
+	Category string
+	Make     string
+	Search   string // matches plate number, VIN, make or model
+}
+
+// VehicleRepository defines the interface for vehicle data access operations
+type VehicleRepository interface {
+	// CRUD operations
+	Create(vehicle *domain.Vehicle) error
+	GetByID(id uint) (*domain.Vehicle, error)
+	GetByPlateNumber(plateNumber string) (*domain.Vehicle, error)
+	GetByIDs(ids []uint) ([]*domain.Vehicle, error)
+	Update(vehicle *domain.Vehicle) error
+	Delete(id uint) error
+
+	// Bulk operations
+	CreateBatch(vehicles []*domain.Vehicle) error
+	GetExistingPlateNumbers(plateNumbers []string) ([]string, error)
+	GetExistingVINs(vins []string) ([]string, error)
+	FindInBatches(filter VehicleFilter, batchSize int, fn func(vehicles []*domain.Vehicle) error) error
+
+	// Assignment lookups
+	GetByAssignee(assignees []string) ([]*domain.Vehicle, error)
+}
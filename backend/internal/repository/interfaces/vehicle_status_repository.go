@@ -0,0 +1,12 @@
+package interfaces
+
+import "ton-platform/internal/domain"
+
+// VehicleStatusRepository defines the interface for the vehicle latest-state projection
+type VehicleStatusRepository interface {
+	// UpsertLatestStates stores states, keeping the existing row when it is from a newer sample
+	UpsertLatestStates(states []*domain.VehicleLatestState) error
+	GetLatestState(vehicleID uint) (*domain.VehicleLatestState, error)
+	// ListLatestStates returns the states of the given vehicles, or of all vehicles when vehicleIDs is nil
+	ListLatestStates(vehicleIDs []uint) ([]*domain.VehicleLatestState, error)
+}
@@ -0,0 +1,48 @@
+package interfaces
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// WorkOrderFilter narrows work order queries; empty fields are ignored
+type WorkOrderFilter struct {
+	VehicleID   uint
+	VehicleIDs  []uint // restricts the result to these vehicles when not nil
+	MechanicID  uint
+	Status      string
+	Priority    string
+	ServiceType string
+	Search      string // matches the work order number or customer name
+	From        *time.Time
+	To          *time.Time
+}
+
+// WorkOrderCommitment is the work committed to a work order: its labor lines and its reserved,
+// short and issued parts. It is read while the work order row is locked.
+type WorkOrderCommitment struct {
+	Labor []*domain.WorkOrderLabor
+	Parts []*domain.WorkOrderPart
+}
+
+// WorkOrderRepository defines the interface for work order data access operations
+type WorkOrderRepository interface {
+	// CRUD operations
+	// Create stores a work order together with its initial status history entry
+	Create(workOrder *domain.WorkOrder) error
+	GetByID(id uint) (*domain.WorkOrder, error)
+	Update(workOrder *domain.WorkOrder) error
+	Delete(id uint) error
+	List(filter WorkOrderFilter, offset, limit int) ([]*domain.WorkOrder, int64, error)
+
+	// Status operations
+	// Transition locks the work order and passes it to apply, which changes it in place and
+	// returns the history entry to record. Errors from apply abort the transition unchanged.
+	Transition(id uint, apply func(workOrder *domain.WorkOrder) (*domain.WorkOrderStatusHistory, error)) (*domain.WorkOrder, error)
+	GetStatusHistory(workOrderID uint) ([]*domain.WorkOrderStatusHistory, error)
+
+	// Workload operations
+	// ListOpenByMechanics retrieves the assigned, in progress and paused work orders of the mechanics
+	ListOpenByMechanics(mechanicIDs []uint) ([]*domain.WorkOrder, error)
+}
@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// workshopBayColumns are the bay columns written when a bay is updated
+var workshopBayColumns = []string{"branch_id", "name", "bay_type", "vehicle_types", "is_active", "notes"}
+
+// appointmentColumns are the appointment columns written when an appointment is moved or closed
+var appointmentColumns = []string{"branch_id", "bay_id", "mechanic_id", "starts_at", "ends_at", "status", "notes", "cancel_reason"}
+
+// AppointmentRepositoryPostgres implements AppointmentRepository interface using PostgreSQL
+type AppointmentRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewAppointmentRepositoryPostgres creates a new PostgreSQL appointment repository
+func NewAppointmentRepositoryPostgres(db *gorm.DB) interfaces.AppointmentRepository {
+	return &AppointmentRepositoryPostgres{db: db}
+}
+
+// CreateBay creates a workshop bay
+func (r *AppointmentRepositoryPostgres) CreateBay(bay *domain.WorkshopBay) error {
+	return r.db.Create(bay).Error
+}
+
+// GetBay retrieves a workshop bay by ID
+func (r *AppointmentRepositoryPostgres) GetBay(id uint) (*domain.WorkshopBay, error) {
+	var bay domain.WorkshopBay
+	if err := r.db.First(&bay, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("workshop bay not found")
+		}
+		return nil, err
+	}
+	return &bay, nil
+}
+
+// ListBays retrieves workshop bays ordered by branch and name
+func (r *AppointmentRepositoryPostgres) ListBays(branchID uint, activeOnly bool) ([]*domain.WorkshopBay, error) {
+	query := r.db.Model(&domain.WorkshopBay{})
+	if branchID != 0 {
+		query = query.Where("branch_id = ?", branchID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	bays := []*domain.WorkshopBay{}
+	if err := query.Order("branch_id, name").Find(&bays).Error; err != nil {
+		return nil, err
+	}
+	return bays, nil
+}
+
+// UpdateBay saves the fields of a workshop bay
+func (r *AppointmentRepositoryPostgres) UpdateBay(bay *domain.WorkshopBay) error {
+	result := r.db.Model(bay).Select(workshopBayColumns).Updates(bay)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("workshop bay not found")
+	}
+	return nil
+}
+
+// Create creates an appointment
+func (r *AppointmentRepositoryPostgres) Create(appointment *domain.WorkOrderAppointment) error {
+	return appointmentError(r.db.Create(appointment).Error)
+}
+
+// GetByID retrieves an appointment with its bay and mechanic
+func (r *AppointmentRepositoryPostgres) GetByID(id uint) (*domain.WorkOrderAppointment, error) {
+	var appointment domain.WorkOrderAppointment
+	if err := r.db.Preload("Bay").Preload("Mechanic").First(&appointment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("appointment not found")
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// GetScheduled retrieves the scheduled appointment of a work order
+func (r *AppointmentRepositoryPostgres) GetScheduled(workOrderID uint) (*domain.WorkOrderAppointment, error) {
+	var appointment domain.WorkOrderAppointment
+	err := r.db.Preload("Bay").Preload("Mechanic").
+		Where("work_order_id = ? AND status = ?", workOrderID, domain.AppointmentStatusScheduled).
+		First(&appointment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("appointment not found")
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// ListByWorkOrder retrieves the appointments of a work order, latest first
+func (r *AppointmentRepositoryPostgres) ListByWorkOrder(workOrderID uint) ([]*domain.WorkOrderAppointment, error) {
+	appointments := []*domain.WorkOrderAppointment{}
+	err := r.db.Preload("Bay").Preload("Mechanic").
+		Where("work_order_id = ?", workOrderID).
+		Order("created_at DESC, id DESC").Find(&appointments).Error
+	if err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// List retrieves the appointments overlapping the filter range, earliest first
+func (r *AppointmentRepositoryPostgres) List(filter interfaces.AppointmentFilter) ([]*domain.WorkOrderAppointment, error) {
+	query := r.db.Model(&domain.WorkOrderAppointment{}).
+		Where("starts_at < ? AND ends_at > ?", filter.To, filter.From)
+	if filter.BranchID != 0 {
+		query = query.Where("branch_id = ?", filter.BranchID)
+	}
+	if filter.BayID != 0 {
+		query = query.Where("bay_id = ?", filter.BayID)
+	}
+	if filter.MechanicID != 0 {
+		query = query.Where("mechanic_id = ?", filter.MechanicID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	appointments := []*domain.WorkOrderAppointment{}
+	err := query.Preload("Bay").Preload("Mechanic").Preload("WorkOrder.Vehicle").
+		Order("starts_at, id").Find(&appointments).Error
+	if err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// ListConflicting retrieves the scheduled appointments overlapping [from, to) on the bay or with the mechanic
+func (r *AppointmentRepositoryPostgres) ListConflicting(bayID uint, mechanicID *uint, from, to time.Time, excludeID uint) ([]*domain.WorkOrderAppointment, error) {
+	query := r.db.Model(&domain.WorkOrderAppointment{}).
+		Where("status = ? AND starts_at < ? AND ends_at > ? AND id <> ?", domain.AppointmentStatusScheduled, to, from, excludeID)
+	if mechanicID != nil {
+		query = query.Where("(bay_id = ? OR mechanic_id = ?)", bayID, *mechanicID)
+	} else {
+		query = query.Where("bay_id = ?", bayID)
+	}
+
+	appointments := []*domain.WorkOrderAppointment{}
+	if err := query.Preload("WorkOrder").Order("starts_at, id").Find(&appointments).Error; err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// Update saves the booking fields of an appointment
+func (r *AppointmentRepositoryPostgres) Update(appointment *domain.WorkOrderAppointment) error {
+	result := r.db.Model(appointment).Select(appointmentColumns).Updates(appointment)
+	if result.Error != nil {
+		return appointmentError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("appointment not found")
+	}
+	return nil
+}
+
+// appointmentError translates violations of the appointment booking constraints
+func appointmentError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch {
+	case pgErr.Code == "23P01": // exclusion_violation
+		return fmt.Errorf("%w: %s", interfaces.ErrAppointmentOverlap, pgErr.ConstraintName)
+	case pgErr.Code == "23505" && pgErr.ConstraintName == "idx_work_order_appointments_scheduled":
+		return interfaces.ErrAppointmentScheduled
+	}
+	return err
+}
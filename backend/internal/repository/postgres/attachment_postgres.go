@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// AttachmentRepositoryPostgres implements AttachmentRepository interface using PostgreSQL
+type AttachmentRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepositoryPostgres creates a new PostgreSQL attachment repository
+func NewAttachmentRepositoryPostgres(db *gorm.DB) interfaces.AttachmentRepository {
+	return &AttachmentRepositoryPostgres{db: db}
+}
+
+// Create creates an attachment
+func (r *AttachmentRepositoryPostgres) Create(attachment *domain.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+// GetByID retrieves an attachment with its uploader
+func (r *AttachmentRepositoryPostgres) GetByID(id uint) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	if err := r.db.Preload("Uploader").First(&attachment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("attachment not found")
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ListByOwner retrieves the attachments of an owner, latest first
+func (r *AttachmentRepositoryPostgres) ListByOwner(ownerType string, ownerID uint) ([]*domain.Attachment, error) {
+	attachments := []*domain.Attachment{}
+	err := r.db.Preload("Uploader").
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("created_at DESC, id DESC").Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// Delete deletes an attachment; its work order photo is removed by the foreign key cascade
+func (r *AttachmentRepositoryPostgres) Delete(id uint) error {
+	result := r.db.Delete(&domain.Attachment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("attachment not found")
+	}
+	return nil
+}
+
+// CreateWorkOrderPhoto creates a work order photo
+func (r *AttachmentRepositoryPostgres) CreateWorkOrderPhoto(photo *domain.WorkOrderPhoto) error {
+	return r.db.Create(photo).Error
+}
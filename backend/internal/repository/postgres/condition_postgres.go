@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// ConditionRepositoryPostgres implements ConditionRepository interface using PostgreSQL
+type ConditionRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewConditionRepositoryPostgres creates a new PostgreSQL condition repository
+func NewConditionRepositoryPostgres(db *gorm.DB) interfaces.ConditionRepository {
+	return &ConditionRepositoryPostgres{db: db}
+}
+
+// CreateRule creates a condition rule
+func (r *ConditionRepositoryPostgres) CreateRule(rule *domain.ConditionRule) error {
+	return r.db.Create(rule).Error
+}
+
+// GetRule retrieves a condition rule by ID
+func (r *ConditionRepositoryPostgres) GetRule(id uint) (*domain.ConditionRule, error) {
+	var rule domain.ConditionRule
+	if err := r.db.First(&rule, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("condition rule not found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule saves a condition rule
+func (r *ConditionRepositoryPostgres) UpdateRule(rule *domain.ConditionRule) error {
+	return r.db.Save(rule).Error
+}
+
+// DeleteRule deletes a condition rule; its alerts are kept without the rule
+func (r *ConditionRepositoryPostgres) DeleteRule(id uint) error {
+	result := r.db.Delete(&domain.ConditionRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("condition rule not found")
+	}
+	return nil
+}
+
+// ListRules retrieves every condition rule ordered by metric and name
+func (r *ConditionRepositoryPostgres) ListRules() ([]*domain.ConditionRule, error) {
+	var rules []*domain.ConditionRule
+	if err := r.db.Order("metric, name, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ListActiveRules retrieves every active condition rule
+func (r *ConditionRepositoryPostgres) ListActiveRules() ([]*domain.ConditionRule, error) {
+	var rules []*domain.ConditionRule
+	if err := r.db.Where("is_active = ?", true).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ResetRule clears the active alerts of a rule at t and forgets its pending breaches
+func (r *ConditionRepositoryPostgres) ResetRule(ruleID uint, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.ConditionAlert{}).
+			Where("rule_id = ? AND status = ?", ruleID, domain.ConditionAlertActive).
+			Updates(map[string]interface{}{"status": domain.ConditionAlertCleared, "cleared_at": at}).Error; err != nil {
+			return err
+		}
+		return tx.Where("rule_id = ?", ruleID).Delete(&domain.ConditionVehicleState{}).Error
+	})
+}
+
+// GetVehicleStates retrieves the rule states of vehicles
+func (r *ConditionRepositoryPostgres) GetVehicleStates(vehicleIDs []uint) ([]*domain.ConditionVehicleState, error) {
+	var states []*domain.ConditionVehicleState
+	if len(vehicleIDs) == 0 {
+		return states, nil
+	}
+	if err := r.db.Where("vehicle_id IN ?", vehicleIDs).Find(&states).Error; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// SaveVehicleStates upserts states, keeping stored states with a newer sample
+func (r *ConditionRepositoryPostgres) SaveVehicleStates(states []*domain.ConditionVehicleState) error {
+	if len(states) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "rule_id"}, {Name: "vehicle_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"breach_started_at", "peak_value", "sample_at", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "condition_vehicle_states.sample_at <= excluded.sample_at"},
+		}},
+	}).Create(&states).Error
+}
+
+// GetActiveAlerts retrieves the active alerts of vehicles
+func (r *ConditionRepositoryPostgres) GetActiveAlerts(vehicleIDs []uint) ([]*domain.ConditionAlert, error) {
+	var alerts []*domain.ConditionAlert
+	if len(vehicleIDs) == 0 {
+		return alerts, nil
+	}
+	if err := r.db.Where("vehicle_id IN ? AND status = ?", vehicleIDs, domain.ConditionAlertActive).
+		Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// SaveAlerts creates new alerts and updates existing ones in one transaction
+func (r *ConditionRepositoryPostgres) SaveAlerts(alerts []*domain.ConditionAlert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, alert := range alerts {
+			if alert.ID == 0 {
+				if err := tx.Omit(clause.Associations).Create(alert).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Model(alert).Updates(map[string]interface{}{
+				"status":      alert.Status,
+				"peak_value":  alert.PeakValue,
+				"cleared_at":  alert.ClearedAt,
+				"clear_value": alert.ClearValue,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetAlert retrieves a condition alert by ID with its rule
+func (r *ConditionRepositoryPostgres) GetAlert(id uint) (*domain.ConditionAlert, error) {
+	var alert domain.ConditionAlert
+	if err := r.db.Preload("Rule").First(&alert, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("condition alert not found")
+		}
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// UpdateAlert saves the acknowledgement of a condition alert
+func (r *ConditionRepositoryPostgres) UpdateAlert(alert *domain.ConditionAlert) error {
+	return r.db.Model(alert).Updates(map[string]interface{}{
+		"acknowledged_by": alert.AcknowledgedBy,
+		"acknowledged_at": alert.AcknowledgedAt,
+		"note":            alert.Note,
+	}).Error
+}
+
+// ListAlerts retrieves a page of condition alerts, active first, most recently triggered first
+func (r *ConditionRepositoryPostgres) ListAlerts(filter interfaces.ConditionAlertFilter, offset, limit int) ([]*domain.ConditionAlert, int64, error) {
+	query := r.db.Model(&domain.ConditionAlert{})
+	if filter.VehicleID != 0 {
+		query = query.Where("vehicle_id = ?", filter.VehicleID)
+	}
+	if filter.RuleID != 0 {
+		query = query.Where("rule_id = ?", filter.RuleID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
+	}
+	if filter.Acknowledged != nil {
+		if *filter.Acknowledged {
+			query = query.Where("acknowledged_at IS NOT NULL")
+		} else {
+			query = query.Where("acknowledged_at IS NULL")
+		}
+	}
+	if filter.From != nil {
+		query = query.Where("triggered_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("triggered_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var alerts []*domain.ConditionAlert
+	if err := query.Order("status = 'active' DESC, triggered_at DESC, id DESC").
+		Offset(offset).Limit(limit).Find(&alerts).Error; err != nil {
+		return nil, 0, err
+	}
+	return alerts, total, nil
+}
+
+// SetAlertWorkOrder links an alert to the work order raised for it
+func (r *ConditionRepositoryPostgres) SetAlertWorkOrder(id, workOrderID uint) error {
+	return r.db.Model(&domain.ConditionAlert{}).Where("id = ?", id).Update("work_order_id", workOrderID).Error
+}
+
+// FindOpenWorkOrderID returns the unfinished work order linked to any alert of a rule for a vehicle, or 0
+func (r *ConditionRepositoryPostgres) FindOpenWorkOrderID(vehicleID, ruleID uint) (uint, error) {
+	var ids []uint
+	err := r.db.Table("condition_alerts").
+		Select("condition_alerts.work_order_id").
+		Joins("JOIN work_orders ON work_orders.id = condition_alerts.work_order_id").
+		Where("condition_alerts.vehicle_id = ? AND condition_alerts.rule_id = ?", vehicleID, ruleID).
+		Where("work_orders.status NOT IN ?", []string{domain.StatusCompleted, domain.StatusCancelled}).
+		Order("condition_alerts.id DESC").
+		Limit(1).
+		Pluck("condition_alerts.work_order_id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// DamageReportRepositoryPostgres implements DamageReportRepository interface using PostgreSQL
+type DamageReportRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewDamageReportRepositoryPostgres creates a new PostgreSQL damage report repository
+func NewDamageReportRepositoryPostgres(db *gorm.DB) interfaces.DamageReportRepository {
+	return &DamageReportRepositoryPostgres{db: db}
+}
+
+// Create creates a new damage report
+func (r *DamageReportRepositoryPostgres) Create(report *domain.DamageReport) error {
+	return r.db.Omit(clause.Associations).Create(report).Error
+}
+
+// GetByID retrieves a damage report by ID
+func (r *DamageReportRepositoryPostgres) GetByID(id uint) (*domain.DamageReport, error) {
+	var report domain.DamageReport
+	if err := r.db.Preload("Vehicle").Preload("Reporter").First(&report, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("damage report not found")
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// UpdateStatus changes the status of a locked damage report, writing only the status and resolution time
+func (r *DamageReportRepositoryPostgres) UpdateStatus(id uint, check func(report *domain.DamageReport) error) (*domain.DamageReport, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		report, err := lockDamageReport(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := check(report); err != nil {
+			return err
+		}
+		return tx.Model(report).Updates(map[string]interface{}{
+			"status":      report.Status,
+			"resolved_at": report.ResolvedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Charge adds an invoice line for a locked damage report and marks the report charged
+func (r *DamageReportRepositoryPostgres) Charge(id uint, invoice *domain.Invoice, item *domain.InvoiceItem, check func(report *domain.DamageReport, invoice *domain.Invoice) error) (*domain.DamageReport, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		report, err := lockDamageReport(tx, id)
+		if err != nil {
+			return err
+		}
+		if invoice.ID != 0 {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(invoice, invoice.ID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return fmt.Errorf("invoice not found")
+				}
+				return err
+			}
+		}
+		if err := check(report, invoice); err != nil {
+			return err
+		}
+
+		if invoice.ID == 0 {
+			if err := tx.Omit(clause.Associations).Create(invoice).Error; err != nil {
+				return err
+			}
+			if err := tx.Raw("SELECT invoice_number FROM invoices WHERE id = ?", invoice.ID).
+				Scan(&invoice.InvoiceNumber).Error; err != nil {
+				return err
+			}
+		}
+		item.InvoiceID = invoice.ID
+		if err := tx.Omit(clause.Associations).Create(item).Error; err != nil {
+			return err
+		}
+		return tx.Model(report).Updates(map[string]interface{}{
+			"status":          domain.DamageStatusCharged,
+			"charged_amount":  item.UnitPrice * float64(item.Quantity),
+			"invoice_item_id": item.ID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// CreateRepairWorkOrder creates a work order for a locked damage report and links it as its repair
+func (r *DamageReportRepositoryPostgres) CreateRepairWorkOrder(id uint, workOrder *domain.WorkOrder, check func(report *domain.DamageReport) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		report, err := lockDamageReport(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := check(report); err != nil {
+			return err
+		}
+		if err := createWorkOrder(tx, workOrder); err != nil {
+			return err
+		}
+		return tx.Model(report).Update("repair_work_order_id", workOrder.ID).Error
+	})
+}
+
+// GetByVehicle retrieves damage reports of a vehicle, optionally filtered by status
+func (r *DamageReportRepositoryPostgres) GetByVehicle(vehicleID uint, status string) ([]*domain.DamageReport, error) {
+	var reports []*domain.DamageReport
+	query := r.db.Where("vehicle_id = ?", vehicleID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("reported_at DESC").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// GetByInspection retrieves damage reports recorded during an inspection
+func (r *DamageReportRepositoryPostgres) GetByInspection(inspectionID uint) ([]*domain.DamageReport, error) {
+	var reports []*domain.DamageReport
+	if err := r.db.Where("inspection_id = ?", inspectionID).Order("id").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// lockDamageReport locks a damage report for the rest of the transaction
+func lockDamageReport(tx *gorm.DB, id uint) (*domain.DamageReport, error) {
+	var report domain.DamageReport
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&report, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("damage report not found")
+		}
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// DeviceRepositoryPostgres implements DeviceRepository interface using PostgreSQL
+type DeviceRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewDeviceRepositoryPostgres creates a new PostgreSQL device repository
+func NewDeviceRepositoryPostgres(db *gorm.DB) interfaces.DeviceRepository {
+	return &DeviceRepositoryPostgres{db: db}
+}
+
+// Create creates a new device
+func (r *DeviceRepositoryPostgres) Create(device *domain.TelematicsDevice) error {
+	return r.db.Create(device).Error
+}
+
+// GetByID retrieves a device by ID
+func (r *DeviceRepositoryPostgres) GetByID(id uint) (*domain.TelematicsDevice, error) {
+	var device domain.TelematicsDevice
+	if err := r.db.First(&device, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("device not found")
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+// GetByIdentifier retrieves a device by the identifier it reports
+func (r *DeviceRepositoryPostgres) GetByIdentifier(identifier string) (*domain.TelematicsDevice, error) {
+	var device domain.TelematicsDevice
+	if err := r.db.Where("identifier = ?", identifier).First(&device).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("device not found")
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+// Update updates a device
+func (r *DeviceRepositoryPostgres) Update(device *domain.TelematicsDevice) error {
+	return r.db.Save(device).Error
+}
+
+// List retrieves devices filtered by status and a search term on identifier, IMEI or serial number
+func (r *DeviceRepositoryPostgres) List(status, search string, offset, limit int) ([]*domain.TelematicsDevice, int64, error) {
+	query := r.db.Model(&domain.TelematicsDevice{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if search != "" {
+		searchQuery := fmt.Sprintf("%%%s%%", search)
+		query = query.Where("identifier ILIKE ? OR imei ILIKE ? OR serial_number ILIKE ?", searchQuery, searchQuery, searchQuery)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var devices []*domain.TelematicsDevice
+	if err := query.Order("identifier").Offset(offset).Limit(limit).Find(&devices).Error; err != nil {
+		return nil, 0, err
+	}
+	return devices, total, nil
+}
+
+// UpdateLastSeen records when the device last delivered data
+func (r *DeviceRepositoryPostgres) UpdateLastSeen(id uint, at time.Time) error {
+	return r.db.Model(&domain.TelematicsDevice{}).Where("id = ?", id).
+		UpdateColumn("last_seen_at", at).Error
+}
+
+// Bind closes the open binding of the device at the new binding's start and opens the new binding
+func (r *DeviceRepositoryPostgres) Bind(binding *domain.DeviceBinding) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.DeviceBinding{}).
+			Where("device_id = ? AND bound_until IS NULL", binding.DeviceID).
+			Updates(map[string]interface{}{
+				"bound_until": binding.BoundFrom,
+				"unbound_by":  binding.BoundBy,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Device", "Vehicle").Create(binding).Error
+	})
+}
+
+// Unbind closes the open binding of the device
+func (r *DeviceRepositoryPostgres) Unbind(deviceID uint, at time.Time, userID uint) error {
+	result := r.db.Model(&domain.DeviceBinding{}).
+		Where("device_id = ? AND bound_until IS NULL", deviceID).
+		Updates(map[string]interface{}{
+			"bound_until": at,
+			"unbound_by":  userID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("device binding not found")
+	}
+	return nil
+}
+
+// GetCurrentBinding retrieves the open binding of a device
+func (r *DeviceRepositoryPostgres) GetCurrentBinding(deviceID uint) (*domain.DeviceBinding, error) {
+	var binding domain.DeviceBinding
+	if err := r.db.Preload("Vehicle").
+		Where("device_id = ? AND bound_until IS NULL", deviceID).
+		First(&binding).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("device binding not found")
+		}
+		return nil, err
+	}
+	return &binding, nil
+}
+
+// GetBindings retrieves the binding history of a device, newest first
+func (r *DeviceRepositoryPostgres) GetBindings(deviceID uint) ([]*domain.DeviceBinding, error) {
+	var bindings []*domain.DeviceBinding
+	if err := r.db.Preload("Vehicle").
+		Where("device_id = ?", deviceID).
+		Order("bound_from DESC").
+		Find(&bindings).Error; err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
+
+// GetBindingsInRange retrieves the bindings of a device that overlap the given period
+func (r *DeviceRepositoryPostgres) GetBindingsInRange(deviceID uint, from, to time.Time) ([]*domain.DeviceBinding, error) {
+	var bindings []*domain.DeviceBinding
+	if err := r.db.
+		Where("device_id = ? AND bound_from <= ? AND (bound_until IS NULL OR bound_until > ?)", deviceID, to, from).
+		Order("bound_from").
+		Find(&bindings).Error; err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
+
+// GetBindingsByVehicle retrieves all devices ever installed in a vehicle, newest first
+func (r *DeviceRepositoryPostgres) GetBindingsByVehicle(vehicleID uint) ([]*domain.DeviceBinding, error) {
+	var bindings []*domain.DeviceBinding
+	if err := r.db.Preload("Device").
+		Where("vehicle_id = ?", vehicleID).
+		Order("bound_from DESC").
+		Find(&bindings).Error; err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// DriverBehaviourRepositoryPostgres implements DriverBehaviourRepository interface using PostgreSQL
+type DriverBehaviourRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewDriverBehaviourRepositoryPostgres creates a new PostgreSQL driver behaviour repository
+func NewDriverBehaviourRepositoryPostgres(db *gorm.DB) interfaces.DriverBehaviourRepository {
+	return &DriverBehaviourRepositoryPostgres{db: db}
+}
+
+// GetSettings retrieves the scoring settings
+func (r *DriverBehaviourRepositoryPostgres) GetSettings() (*domain.DriverBehaviourSettings, error) {
+	var settings domain.DriverBehaviourSettings
+	if err := r.db.First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("driver behaviour settings not found")
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateSettings saves the scoring settings
+func (r *DriverBehaviourRepositoryPostgres) UpdateSettings(settings *domain.DriverBehaviourSettings) error {
+	return r.db.Save(settings).Error
+}
+
+// GetAssignments returns the assignments of a vehicle that overlap [from, to], oldest first
+func (r *DriverBehaviourRepositoryPostgres) GetAssignments(vehicleID uint, from, to time.Time) ([]*domain.VehicleDriverAssignment, error) {
+	var assignments []*domain.VehicleDriverAssignment
+	if err := r.db.Where("vehicle_id = ? AND started_at <= ? AND (ended_at IS NULL OR ended_at > ?)", vehicleID, to, from).
+		Order("started_at").Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// ReplaceTripBehaviours replaces the behaviour rows of trips under the vehicle's trip lock
+func (r *DriverBehaviourRepositoryPostgres) ReplaceTripBehaviours(vehicleID uint, tripIDs []uint, behaviours []*domain.DriverTripBehaviour) error {
+	if len(tripIDs) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", tripLockNamespace, vehicleID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", tripIDs).Delete(&domain.DriverTripBehaviour{}).Error; err != nil {
+			return err
+		}
+
+		var existing []uint
+		if err := tx.Model(&domain.Trip{}).Where("id IN ?", tripIDs).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		current := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			current[id] = true
+		}
+		rows := make([]*domain.DriverTripBehaviour, 0, len(behaviours))
+		for _, behaviour := range behaviours {
+			if current[behaviour.TripID] {
+				rows = append(rows, behaviour)
+			}
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// ListTripBehaviours lists the trip behaviour of a driver starting in [from, to], newest first
+func (r *DriverBehaviourRepositoryPostgres) ListTripBehaviours(driverID uint, from, to time.Time, offset, limit int) ([]*domain.DriverTripBehaviour, int64, error) {
+	query := r.db.Model(&domain.DriverTripBehaviour{}).
+		Where("driver_id = ? AND start_time BETWEEN ? AND ?", driverID, from, to)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var behaviours []*domain.DriverTripBehaviour
+	if err := query.Order("start_time DESC").Offset(offset).Limit(limit).Find(&behaviours).Error; err != nil {
+		return nil, 0, err
+	}
+	return behaviours, total, nil
+}
+
+// WeeklyTotals sums trip behaviour per driver and week
+func (r *DriverBehaviourRepositoryPostgres) WeeklyTotals(driverID uint, from, to time.Time) ([]*interfaces.DriverBehaviourTotals, error) {
+	query := r.db.Model(&domain.DriverTripBehaviour{}).
+		Select(`driver_id, week_start, COUNT(DISTINCT trip_id) AS trips,
+			SUM(distance_km) AS distance_km, SUM(driving_seconds) AS driving_seconds,
+			SUM(harsh_accelerations) AS harsh_accelerations, SUM(harsh_brakings) AS harsh_brakings,
+			SUM(speeding_events) AS speeding_events, SUM(speeding_seconds) AS speeding_seconds,
+			SUM(excessive_idle_seconds) AS excessive_idle_seconds, SUM(night_seconds) AS night_seconds`).
+		Where("driver_id IS NOT NULL AND week_start BETWEEN ? AND ?", from, to).
+		Group("driver_id, week_start").
+		Order("week_start, driver_id")
+	if driverID != 0 {
+		query = query.Where("driver_id = ?", driverID)
+	}
+
+	var totals []*interfaces.DriverBehaviourTotals
+	if err := query.Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+const (
+	// dtcLockNamespace scopes the advisory locks that serialize DTC reports per vehicle
+	dtcLockNamespace = 36001
+	// dtcAutoResolutionNote marks codes resolved because the vehicle stopped reporting them
+	dtcAutoResolutionNote = "No longer reported by the vehicle"
+)
+
+// DTCRepositoryPostgres implements DTCRepository interface using PostgreSQL
+type DTCRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewDTCRepositoryPostgres creates a new PostgreSQL DTC repository
+func NewDTCRepositoryPostgres(db *gorm.DB) interfaces.DTCRepository {
+	return &DTCRepositoryPostgres{db: db}
+}
+
+// CountActiveByVehicles counts active codes per vehicle, for all vehicles when vehicleIDs is nil
+func (r *DTCRepositoryPostgres) CountActiveByVehicles(vehicleIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int)
+	query := r.db.Model(&domain.DTCCode{}).
+		Select("vehicle_id, COUNT(*) AS count").
+		Where("is_active = ?", true).
+		Group("vehicle_id")
+	if vehicleIDs != nil {
+		if len(vehicleIDs) == 0 {
+			return counts, nil
+		}
+		query = query.Where("vehicle_id IN ?", vehicleIDs)
+	}
+
+	var rows []struct {
+		VehicleID uint
+		Count     int
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.VehicleID] = row.Count
+	}
+	return counts, nil
+}
+
+// ApplyReport reconciles the active codes of a vehicle with a report inside one transaction
+func (r *DTCRepositoryPostgres) ApplyReport(vehicleID uint, reportedAt time.Time, codes []*domain.DTCCode) (*interfaces.DTCReportResult, error) {
+	result := &interfaces.DTCReportResult{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", dtcLockNamespace, vehicleID).Error; err != nil {
+			return err
+		}
+
+		var active []*domain.DTCCode
+		if err := tx.Where("vehicle_id = ? AND is_active = ?", vehicleID, true).Find(&active).Error; err != nil {
+			return err
+		}
+		activeByCode := make(map[string]*domain.DTCCode, len(active))
+		for _, code := range active {
+			activeByCode[code.Code] = code
+		}
+
+		reported := make(map[string]bool, len(codes))
+		for _, code := range codes {
+			reported[code.Code] = true
+			if existing, ok := activeByCode[code.Code]; ok {
+				if reportedAt.After(existing.LastSeen) {
+					existing.LastSeen = reportedAt
+				}
+				if reportedAt.Before(existing.FirstSeen) {
+					existing.FirstSeen = reportedAt
+				}
+				existing.Severity = code.Severity
+				existing.Description = code.Description
+				if err := tx.Model(existing).Updates(map[string]interface{}{
+					"first_seen":  existing.FirstSeen,
+					"last_seen":   existing.LastSeen,
+					"severity":    existing.Severity,
+					"description": existing.Description,
+				}).Error; err != nil {
+					return err
+				}
+				result.Updated = append(result.Updated, existing)
+				continue
+			}
+
+			// A late report must not reopen a code that was resolved after it was taken
+			var resolvedLater int64
+			if err := tx.Model(&domain.DTCCode{}).
+				Where("vehicle_id = ? AND code = ? AND is_active = ? AND resolved_at >= ?", vehicleID, code.Code, false, reportedAt).
+				Count(&resolvedLater).Error; err != nil {
+				return err
+			}
+			if resolvedLater > 0 {
+				continue
+			}
+
+			code.ID = 0
+			code.VehicleID = vehicleID
+			code.IsActive = true
+			code.FirstSeen = reportedAt
+			code.LastSeen = reportedAt
+			if err := tx.Omit(clause.Associations).Create(code).Error; err != nil {
+				return err
+			}
+			result.Opened = append(result.Opened, code)
+		}
+
+		for _, existing := range active {
+			if reported[existing.Code] || !existing.LastSeen.Before(reportedAt) {
+				continue
+			}
+			resolvedAt := reportedAt
+			existing.IsActive = false
+			existing.ResolvedAt = &resolvedAt
+			existing.ResolutionNote = dtcAutoResolutionNote
+			if err := tx.Model(existing).Updates(map[string]interface{}{
+				"is_active":       false,
+				"resolved_at":     resolvedAt,
+				"resolution_note": dtcAutoResolutionNote,
+			}).Error; err != nil {
+				return err
+			}
+			result.Resolved = append(result.Resolved, existing)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListByVehicle retrieves a page of a vehicle's codes, most recently seen first
+func (r *DTCRepositoryPostgres) ListByVehicle(vehicleID uint, active *bool, offset, limit int) ([]*domain.DTCCode, int64, error) {
+	query := r.db.Model(&domain.DTCCode{}).Where("vehicle_id = ?", vehicleID)
+	if active != nil {
+		query = query.Where("is_active = ?", *active)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var codes []*domain.DTCCode
+	if err := query.Order("is_active DESC, last_seen DESC, id DESC").Offset(offset).Limit(limit).Find(&codes).Error; err != nil {
+		return nil, 0, err
+	}
+	return codes, total, nil
+}
+
+// LinkWorkOrder links a code occurrence to the vehicle's open work order for the code, creating
+// workOrder when there is none. It holds the vehicle's report lock, so concurrent reports of the
+// same code open a single work order.
+func (r *DTCRepositoryPostgres) LinkWorkOrder(code *domain.DTCCode, workOrder *domain.WorkOrder) (uint, error) {
+	var workOrderID uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", dtcLockNamespace, code.VehicleID).Error; err != nil {
+			return err
+		}
+		var ids []uint
+		err := tx.Table("dtc_codes").
+			Select("dtc_codes.work_order_id").
+			Joins("JOIN work_orders ON work_orders.id = dtc_codes.work_order_id").
+			Where("dtc_codes.vehicle_id = ? AND dtc_codes.code = ?", code.VehicleID, code.Code).
+			Where("work_orders.status NOT IN ?", []string{domain.StatusCompleted, domain.StatusCancelled}).
+			Order("dtc_codes.id DESC").
+			Limit(1).
+			Pluck("dtc_codes.work_order_id", &ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			workOrderID = ids[0]
+		} else {
+			if err := createWorkOrder(tx, workOrder); err != nil {
+				return err
+			}
+			workOrderID = workOrder.ID
+		}
+		return tx.Model(&domain.DTCCode{}).Where("id = ?", code.ID).Update("work_order_id", workOrderID).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return workOrderID, nil
+}
+
+// CreateRule creates a severity rule
+func (r *DTCRepositoryPostgres) CreateRule(rule *domain.DTCSeverityRule) error {
+	return r.db.Create(rule).Error
+}
+
+// GetRule retrieves a severity rule by ID
+func (r *DTCRepositoryPostgres) GetRule(id uint) (*domain.DTCSeverityRule, error) {
+	var rule domain.DTCSeverityRule
+	if err := r.db.First(&rule, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("dtc severity rule not found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule saves a severity rule
+func (r *DTCRepositoryPostgres) UpdateRule(rule *domain.DTCSeverityRule) error {
+	return r.db.Save(rule).Error
+}
+
+// DeleteRule deletes a severity rule
+func (r *DTCRepositoryPostgres) DeleteRule(id uint) error {
+	result := r.db.Delete(&domain.DTCSeverityRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("dtc severity rule not found")
+	}
+	return nil
+}
+
+// ListRules retrieves every severity rule ordered by pattern
+func (r *DTCRepositoryPostgres) ListRules() ([]*domain.DTCSeverityRule, error) {
+	var rules []*domain.DTCSeverityRule
+	if err := r.db.Order("pattern").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ListActiveRules retrieves every active severity rule
+func (r *DTCRepositoryPostgres) ListActiveRules() ([]*domain.DTCSeverityRule, error) {
+	var rules []*domain.DTCSeverityRule
+	if err := r.db.Where("is_active = ?", true).Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
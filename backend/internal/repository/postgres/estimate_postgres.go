@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// EstimateRepositoryPostgres implements EstimateRepository interface using PostgreSQL
+type EstimateRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewEstimateRepositoryPostgres creates a new PostgreSQL estimate repository
+func NewEstimateRepositoryPostgres(db *gorm.DB) interfaces.EstimateRepository {
+	return &EstimateRepositoryPostgres{db: db}
+}
+
+// Create stores the next version of a work order estimate
+func (r *EstimateRepositoryPostgres) Create(estimate *domain.WorkOrderEstimate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var workOrder domain.WorkOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&workOrder, estimate.WorkOrderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("work order not found")
+			}
+			return err
+		}
+
+		var version int
+		if err := tx.Model(&domain.WorkOrderEstimate{}).Where("work_order_id = ?", estimate.WorkOrderID).
+			Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.WorkOrderEstimate{}).
+			Where("work_order_id = ? AND status IN ?", estimate.WorkOrderID, []string{domain.EstimateStatusDraft, domain.EstimateStatusSent}).
+			Updates(map[string]interface{}{"status": domain.EstimateStatusSuperseded, "approval_token_hash": nil}).Error; err != nil {
+			return err
+		}
+
+		estimate.Version = version + 1
+		if err := tx.Omit(clause.Associations).Create(estimate).Error; err != nil {
+			return err
+		}
+		return saveEstimateLines(tx, estimate)
+	})
+}
+
+// GetByID retrieves an estimate with its lines by ID
+func (r *EstimateRepositoryPostgres) GetByID(id uint) (*domain.WorkOrderEstimate, error) {
+	var estimate domain.WorkOrderEstimate
+	if err := r.preloadLines(r.db).First(&estimate, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("estimate not found")
+		}
+		return nil, err
+	}
+	return &estimate, nil
+}
+
+// GetByTokenHash retrieves an estimate with its lines by the hash of its approval token
+func (r *EstimateRepositoryPostgres) GetByTokenHash(hash string) (*domain.WorkOrderEstimate, error) {
+	var estimate domain.WorkOrderEstimate
+	if err := r.preloadLines(r.db).Where("approval_token_hash = ?", hash).First(&estimate).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("estimate not found")
+		}
+		return nil, err
+	}
+	return &estimate, nil
+}
+
+// ListByWorkOrder retrieves the estimates of a work order, newest version first
+func (r *EstimateRepositoryPostgres) ListByWorkOrder(workOrderID uint) ([]*domain.WorkOrderEstimate, error) {
+	estimates := []*domain.WorkOrderEstimate{}
+	if err := r.preloadLines(r.db).Where("work_order_id = ?", workOrderID).
+		Order("version DESC").Find(&estimates).Error; err != nil {
+		return nil, err
+	}
+	return estimates, nil
+}
+
+// Save applies a change to an estimate and its lines under a row lock
+func (r *EstimateRepositoryPostgres) Save(id uint, apply func(estimate *domain.WorkOrderEstimate) error) (*domain.WorkOrderEstimate, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var estimate domain.WorkOrderEstimate
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&estimate, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("estimate not found")
+			}
+			return err
+		}
+		if err := tx.Where("estimate_id = ?", id).Order("sort_order, id").Find(&estimate.Lines).Error; err != nil {
+			return err
+		}
+
+		if err := apply(&estimate); err != nil {
+			return err
+		}
+
+		if err := tx.Omit(clause.Associations).Save(&estimate).Error; err != nil {
+			return err
+		}
+		if err := saveEstimateLines(tx, &estimate); err != nil {
+			return err
+		}
+		if !estimate.IsApproved() {
+			return nil
+		}
+		return tx.Model(&domain.WorkOrder{}).Where("id = ?", estimate.WorkOrderID).
+			Update("estimated_cost", estimate.ApprovedTotal).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// preloadLines loads estimate lines in their order with the inventory items of part lines
+func (r *EstimateRepositoryPostgres) preloadLines(db *gorm.DB) *gorm.DB {
+	return db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order, id")
+	}).Preload("Lines.InventoryItem")
+}
+
+// saveEstimateLines stores the lines of an estimate and deletes the lines it no longer has
+func saveEstimateLines(tx *gorm.DB, estimate *domain.WorkOrderEstimate) error {
+	keep := []uint{0}
+	for i := range estimate.Lines {
+		line := &estimate.Lines[i]
+		line.EstimateID = estimate.ID
+		line.SortOrder = i + 1
+		if err := tx.Omit(clause.Associations).Save(line).Error; err != nil {
+			return err
+		}
+		keep = append(keep, line.ID)
+	}
+	return tx.Where("estimate_id = ? AND id NOT IN ?", estimate.ID, keep).
+		Delete(&domain.WorkOrderEstimateLine{}).Error
+}
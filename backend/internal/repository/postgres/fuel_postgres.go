@@ -0,0 +1,382 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// fuelLockNamespace scopes the advisory locks that serialize fuel event syncs per vehicle
+const fuelLockNamespace = 38001
+
+// FuelRepositoryPostgres implements FuelRepository interface using PostgreSQL
+type FuelRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewFuelRepositoryPostgres creates a new PostgreSQL fuel repository
+func NewFuelRepositoryPostgres(db *gorm.DB) interfaces.FuelRepository {
+	return &FuelRepositoryPostgres{db: db}
+}
+
+// GetProfile retrieves the fuel profile of a vehicle
+func (r *FuelRepositoryPostgres) GetProfile(vehicleID uint) (*domain.VehicleFuelProfile, error) {
+	var profile domain.VehicleFuelProfile
+	if err := r.db.Where("vehicle_id = ?", vehicleID).First(&profile).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("fuel profile not found")
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SaveProfile creates or replaces the fuel profile of a vehicle
+func (r *FuelRepositoryPostgres) SaveProfile(profile *domain.VehicleFuelProfile) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "vehicle_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tank_capacity_liters", "updated_by", "updated_at"}),
+	}).Create(profile).Error
+}
+
+// RecalculateLiters recomputes litre figures of a vehicle in SQL
+func (r *FuelRepositoryPostgres) RecalculateLiters(vehicleID uint, capacityLiters, minDistanceKm float64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE fuel_events SET liters = ROUND(change_percent * ? / 100, 2)
+			WHERE vehicle_id = ?`, capacityLiters, vehicleID).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE trip_fuel_consumptions
+			SET fuel_used_liters = ROUND(fuel_used_percent * ? / 100, 2),
+				liters_per_100km = CASE WHEN distance_km >= ?
+					THEN ROUND(fuel_used_percent * ? / distance_km, 2) END
+			WHERE vehicle_id = ?`, capacityLiters, minDistanceKm, capacityLiters, vehicleID).Error
+	})
+}
+
+// SyncEvents replaces the events of a vehicle in a window, keeping the IDs of events detected again
+func (r *FuelRepositoryPostgres) SyncEvents(vehicleID uint, from, to time.Time, events []*domain.FuelEvent) ([]*domain.FuelEvent, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", fuelLockNamespace, vehicleID).Error; err != nil {
+			return err
+		}
+
+		var stored []*domain.FuelEvent
+		if err := tx.Where("vehicle_id = ? AND started_at <= ? AND ended_at >= ?", vehicleID, to, from).
+			Order("started_at").Find(&stored).Error; err != nil {
+			return err
+		}
+
+		claimed := make(map[uint]bool, len(stored))
+		for _, event := range events {
+			for _, existing := range stored {
+				if !claimed[existing.ID] && existing.Overlaps(event) {
+					claimed[existing.ID] = true
+					event.ID = existing.ID
+					event.CreatedAt = existing.CreatedAt
+					break
+				}
+			}
+			if event.ID != 0 {
+				if err := tx.Save(event).Error; err != nil {
+					return err
+				}
+			} else if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+		}
+
+		var stale []uint
+		for _, existing := range stored {
+			if !claimed[existing.ID] {
+				stale = append(stale, existing.ID)
+			}
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		// A reviewed finding keeps its event as evidence
+		return tx.Where("id IN ?", stale).
+			Where("NOT EXISTS (SELECT 1 FROM fuel_anomalies WHERE fuel_anomalies.fuel_event_id = fuel_events.id AND fuel_anomalies.status <> ?)", domain.FuelAnomalyOpen).
+			Delete(&domain.FuelEvent{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetEventsInRange returns the events of a vehicle overlapping [from, to], oldest first
+func (r *FuelRepositoryPostgres) GetEventsInRange(vehicleID uint, eventType string, from, to time.Time) ([]*domain.FuelEvent, error) {
+	query := r.db.Where("vehicle_id = ? AND started_at <= ? AND ended_at >= ?", vehicleID, to, from)
+	if eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+
+	var events []*domain.FuelEvent
+	if err := query.Order("started_at").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListEvents lists the events of a vehicle starting in [from, to], newest first
+func (r *FuelRepositoryPostgres) ListEvents(vehicleID uint, eventType string, from, to time.Time, offset, limit int) ([]*domain.FuelEvent, int64, error) {
+	query := r.db.Model(&domain.FuelEvent{}).
+		Where("vehicle_id = ? AND started_at BETWEEN ? AND ?", vehicleID, from, to)
+	if eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []*domain.FuelEvent
+	if err := query.Order("started_at DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// CreateReceipt creates a new fuel receipt
+func (r *FuelRepositoryPostgres) CreateReceipt(receipt *domain.FuelReceipt) error {
+	return r.db.Create(receipt).Error
+}
+
+// GetReceipt retrieves a fuel receipt by ID
+func (r *FuelRepositoryPostgres) GetReceipt(id uint) (*domain.FuelReceipt, error) {
+	var receipt domain.FuelReceipt
+	if err := r.db.Preload("Vehicle").First(&receipt, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("fuel receipt not found")
+		}
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// UpdateReceipt updates a fuel receipt
+func (r *FuelRepositoryPostgres) UpdateReceipt(receipt *domain.FuelReceipt) error {
+	return r.db.Omit("Vehicle").Save(receipt).Error
+}
+
+// DeleteReceipt deletes a fuel receipt; its anomalies are removed with it
+func (r *FuelRepositoryPostgres) DeleteReceipt(id uint) error {
+	result := r.db.Delete(&domain.FuelReceipt{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("fuel receipt not found")
+	}
+	return nil
+}
+
+// ListReceipts retrieves a page of fuel receipts, newest fill first
+func (r *FuelRepositoryPostgres) ListReceipts(filter interfaces.FuelReceiptFilter, offset, limit int) ([]*domain.FuelReceipt, int64, error) {
+	query := r.db.Model(&domain.FuelReceipt{})
+	if filter.VehicleID != 0 {
+		query = query.Where("vehicle_id = ?", filter.VehicleID)
+	}
+	if filter.EnteredBy != 0 {
+		query = query.Where("entered_by = ?", filter.EnteredBy)
+	}
+	if filter.Status != "" {
+		query = query.Where("reconciliation_status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("filled_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("filled_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var receipts []*domain.FuelReceipt
+	if err := query.Preload("Vehicle").Order("filled_at DESC").Offset(offset).Limit(limit).Find(&receipts).Error; err != nil {
+		return nil, 0, err
+	}
+	return receipts, total, nil
+}
+
+// GetReceiptsInRange returns the receipts of a vehicle filled in [from, to]
+func (r *FuelRepositoryPostgres) GetReceiptsInRange(vehicleID uint, from, to time.Time) ([]*domain.FuelReceipt, error) {
+	var receipts []*domain.FuelReceipt
+	if err := r.db.Where("vehicle_id = ? AND filled_at BETWEEN ? AND ?", vehicleID, from, to).
+		Order("filled_at").Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// GetPendingReceipts returns pending receipts filled before t, oldest first
+func (r *FuelRepositoryPostgres) GetPendingReceipts(filledBefore time.Time, limit int) ([]*domain.FuelReceipt, error) {
+	var receipts []*domain.FuelReceipt
+	if err := r.db.Where("reconciliation_status = ? AND filled_at < ?", domain.FuelReconciliationPending, filledBefore).
+		Order("filled_at").Limit(limit).Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// UpdateReconciliation stores only the reconciliation fields of a receipt
+func (r *FuelRepositoryPostgres) UpdateReconciliation(receipt *domain.FuelReceipt) error {
+	return r.db.Model(&domain.FuelReceipt{}).Where("id = ?", receipt.ID).Updates(map[string]interface{}{
+		"fuel_event_id":         receipt.FuelEventID,
+		"detected_liters":       receipt.DetectedLiters,
+		"reconciliation_status": receipt.ReconciliationStatus,
+		"reconciled_at":         receipt.ReconciledAt,
+	}).Error
+}
+
+// SaveAnomaly inserts an anomaly, refreshing the open anomaly it duplicates
+func (r *FuelRepositoryPostgres) SaveAnomaly(anomaly *domain.FuelAnomaly) error {
+	conflict := clause.OnConflict{
+		Columns: []clause.Column{{Name: "fuel_receipt_id"}, {Name: "type"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "fuel_receipt_id IS NOT NULL"},
+		}},
+		DoUpdates: clause.AssignmentColumns([]string{"occurred_at", "liters", "description", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "fuel_anomalies.status = ?", Vars: []interface{}{domain.FuelAnomalyOpen}},
+		}},
+	}
+	if anomaly.FuelReceiptID == nil {
+		conflict.Columns = []clause.Column{{Name: "fuel_event_id"}, {Name: "type"}}
+		conflict.TargetWhere = clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "fuel_event_id IS NOT NULL"},
+		}}
+	}
+	return r.db.Omit("FuelEvent").Clauses(conflict).Create(anomaly).Error
+}
+
+// DeleteOpenReceiptAnomalies removes the open anomalies of a receipt other than keepType
+func (r *FuelRepositoryPostgres) DeleteOpenReceiptAnomalies(receiptID uint, keepType string) error {
+	return r.db.Where("fuel_receipt_id = ? AND status = ? AND type <> ?", receiptID, domain.FuelAnomalyOpen, keepType).
+		Delete(&domain.FuelAnomaly{}).Error
+}
+
+// GetAnomaly retrieves a fuel anomaly by ID with its event
+func (r *FuelRepositoryPostgres) GetAnomaly(id uint) (*domain.FuelAnomaly, error) {
+	var anomaly domain.FuelAnomaly
+	if err := r.db.Preload("FuelEvent").First(&anomaly, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("fuel anomaly not found")
+		}
+		return nil, err
+	}
+	return &anomaly, nil
+}
+
+// UpdateAnomaly updates a fuel anomaly
+func (r *FuelRepositoryPostgres) UpdateAnomaly(anomaly *domain.FuelAnomaly) error {
+	return r.db.Omit("FuelEvent").Save(anomaly).Error
+}
+
+// ListAnomalies retrieves a page of fuel anomalies, newest first
+func (r *FuelRepositoryPostgres) ListAnomalies(filter interfaces.FuelAnomalyFilter, offset, limit int) ([]*domain.FuelAnomaly, int64, error) {
+	query := r.db.Model(&domain.FuelAnomaly{})
+	if filter.VehicleID != 0 {
+		query = query.Where("vehicle_id = ?", filter.VehicleID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var anomalies []*domain.FuelAnomaly
+	if err := query.Preload("FuelEvent").Order("occurred_at DESC").Offset(offset).Limit(limit).Find(&anomalies).Error; err != nil {
+		return nil, 0, err
+	}
+	return anomalies, total, nil
+}
+
+// ReplaceTripConsumptions replaces the consumption rows of trips under the vehicle's trip lock
+func (r *FuelRepositoryPostgres) ReplaceTripConsumptions(vehicleID uint, tripIDs []uint, consumptions []*domain.TripFuelConsumption) error {
+	if len(tripIDs) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", tripLockNamespace, vehicleID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("trip_id IN ?", tripIDs).Delete(&domain.TripFuelConsumption{}).Error; err != nil {
+			return err
+		}
+
+		var existing []uint
+		if err := tx.Model(&domain.Trip{}).Where("id IN ?", tripIDs).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		current := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			current[id] = true
+		}
+		rows := make([]*domain.TripFuelConsumption, 0, len(consumptions))
+		for _, consumption := range consumptions {
+			if current[consumption.TripID] {
+				rows = append(rows, consumption)
+			}
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// ListTripConsumptions lists the trip consumption of a vehicle starting in [from, to], newest first
+func (r *FuelRepositoryPostgres) ListTripConsumptions(vehicleID uint, from, to time.Time, offset, limit int) ([]*domain.TripFuelConsumption, int64, error) {
+	query := r.db.Model(&domain.TripFuelConsumption{}).
+		Where("vehicle_id = ? AND start_time BETWEEN ? AND ?", vehicleID, from, to)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var consumptions []*domain.TripFuelConsumption
+	if err := query.Order("start_time DESC").Offset(offset).Limit(limit).Find(&consumptions).Error; err != nil {
+		return nil, 0, err
+	}
+	return consumptions, total, nil
+}
+
+// SumTripConsumptions sums the trip consumption of a vehicle starting in [from, to]
+func (r *FuelRepositoryPostgres) SumTripConsumptions(vehicleID uint, from, to time.Time) (*interfaces.FuelConsumptionTotals, error) {
+	var totals interfaces.FuelConsumptionTotals
+	if err := r.db.Model(&domain.TripFuelConsumption{}).
+		Select(`COUNT(*) AS trips, COALESCE(SUM(distance_km), 0) AS distance_km,
+			COALESCE(SUM(fuel_used_percent), 0) AS fuel_used_percent,
+			COALESCE(SUM(fuel_used_liters), 0) AS fuel_used_liters,
+			COALESCE(SUM(distance_km) FILTER (WHERE fuel_used_liters IS NOT NULL), 0) AS measured_distance_km`).
+		Where("vehicle_id = ? AND start_time BETWEEN ? AND ?", vehicleID, from, to).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
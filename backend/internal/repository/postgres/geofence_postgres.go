@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// GeofenceRepositoryPostgres implements GeofenceRepository interface using PostgreSQL
+type GeofenceRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewGeofenceRepositoryPostgres creates a new PostgreSQL geofence repository
+func NewGeofenceRepositoryPostgres(db *gorm.DB) interfaces.GeofenceRepository {
+	return &GeofenceRepositoryPostgres{db: db}
+}
+
+// Create creates a geofence with its assignments
+func (r *GeofenceRepositoryPostgres) Create(geofence *domain.Geofence) error {
+	return r.db.Create(geofence).Error
+}
+
+// GetByID retrieves a geofence with its assignments
+func (r *GeofenceRepositoryPostgres) GetByID(id uint) (*domain.Geofence, error) {
+	var geofence domain.Geofence
+	if err := r.db.Preload("Assignments").First(&geofence, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("geofence not found")
+		}
+		return nil, err
+	}
+	return &geofence, nil
+}
+
+// Update saves a geofence and replaces its assignments
+func (r *GeofenceRepositoryPostgres) Update(geofence *domain.Geofence) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Assignments").Save(geofence).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("geofence_id = ?", geofence.ID).Delete(&domain.GeofenceAssignment{}).Error; err != nil {
+			return err
+		}
+		if len(geofence.Assignments) == 0 {
+			return nil
+		}
+		for _, assignment := range geofence.Assignments {
+			assignment.ID = 0
+			assignment.GeofenceID = geofence.ID
+		}
+		return tx.Create(&geofence.Assignments).Error
+	})
+}
+
+// Delete deletes a geofence; assignments, states, events and alert rules cascade
+func (r *GeofenceRepositoryPostgres) Delete(id uint) error {
+	result := r.db.Delete(&domain.Geofence{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("geofence not found")
+	}
+	return nil
+}
+
+// List retrieves a page of geofences with their assignments
+func (r *GeofenceRepositoryPostgres) List(search string, active *bool, offset, limit int) ([]*domain.Geofence, int64, error) {
+	query := r.db.Model(&domain.Geofence{})
+	if search != "" {
+		query = query.Where("name ILIKE ?", fmt.Sprintf("%%%s%%", search))
+	}
+	if active != nil {
+		query = query.Where("is_active = ?", *active)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var geofences []*domain.Geofence
+	if err := query.Preload("Assignments").Order("name").Offset(offset).Limit(limit).Find(&geofences).Error; err != nil {
+		return nil, 0, err
+	}
+	return geofences, total, nil
+}
+
+// ListActive retrieves every active geofence with its assignments
+func (r *GeofenceRepositoryPostgres) ListActive() ([]*domain.Geofence, error) {
+	var geofences []*domain.Geofence
+	if err := r.db.Preload("Assignments").Where("is_active = ?", true).Find(&geofences).Error; err != nil {
+		return nil, err
+	}
+	return geofences, nil
+}
+
+// GetVehicleStates retrieves the geofence states of the given vehicles
+func (r *GeofenceRepositoryPostgres) GetVehicleStates(vehicleIDs []uint) ([]*domain.GeofenceVehicleState, error) {
+	var states []*domain.GeofenceVehicleState
+	if len(vehicleIDs) == 0 {
+		return states, nil
+	}
+	if err := r.db.Where("vehicle_id IN ?", vehicleIDs).Find(&states).Error; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// SaveVehicleStates upserts geofence states, never replacing a state with an older sample
+func (r *GeofenceRepositoryPostgres) SaveVehicleStates(states []*domain.GeofenceVehicleState) error {
+	if len(states) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "geofence_id"}, {Name: "vehicle_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"inside", "entered_at", "dwell_reported", "sample_at", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "geofence_vehicle_states.sample_at <= excluded.sample_at"},
+		}},
+	}).Create(&states).Error
+}
+
+// DeleteVehicleStates forgets the vehicle states of a geofence
+func (r *GeofenceRepositoryPostgres) DeleteVehicleStates(geofenceID uint) error {
+	return r.db.Where("geofence_id = ?", geofenceID).Delete(&domain.GeofenceVehicleState{}).Error
+}
+
+// CreateEvents stores geofence events
+func (r *GeofenceRepositoryPostgres) CreateEvents(events []*domain.GeofenceEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.Omit("Geofence").Create(&events).Error
+}
+
+// ListEvents retrieves a page of geofence events, newest first
+func (r *GeofenceRepositoryPostgres) ListEvents(filter interfaces.GeofenceEventFilter, offset, limit int) ([]*domain.GeofenceEvent, int64, error) {
+	query := r.db.Model(&domain.GeofenceEvent{})
+	if filter.GeofenceID != 0 {
+		query = query.Where("geofence_id = ?", filter.GeofenceID)
+	}
+	if filter.VehicleID != 0 {
+		query = query.Where("vehicle_id = ?", filter.VehicleID)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []*domain.GeofenceEvent
+	if err := query.Preload("Geofence", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "shape")
+	}).Order("occurred_at DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// CreateAlertRule creates an alert rule
+func (r *GeofenceRepositoryPostgres) CreateAlertRule(rule *domain.GeofenceAlertRule) error {
+	return r.db.Create(rule).Error
+}
+
+// GetAlertRule retrieves an alert rule by ID
+func (r *GeofenceRepositoryPostgres) GetAlertRule(id uint) (*domain.GeofenceAlertRule, error) {
+	var rule domain.GeofenceAlertRule
+	if err := r.db.First(&rule, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("geofence alert rule not found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateAlertRule saves an alert rule
+func (r *GeofenceRepositoryPostgres) UpdateAlertRule(rule *domain.GeofenceAlertRule) error {
+	return r.db.Save(rule).Error
+}
+
+// DeleteAlertRule deletes an alert rule
+func (r *GeofenceRepositoryPostgres) DeleteAlertRule(id uint) error {
+	result := r.db.Delete(&domain.GeofenceAlertRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("geofence alert rule not found")
+	}
+	return nil
+}
+
+// ListAlertRules retrieves the alert rules of a geofence
+func (r *GeofenceRepositoryPostgres) ListAlertRules(geofenceID uint) ([]*domain.GeofenceAlertRule, error) {
+	var rules []*domain.GeofenceAlertRule
+	if err := r.db.Where("geofence_id = ?", geofenceID).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ListActiveAlertRules retrieves every active alert rule
+func (r *GeofenceRepositoryPostgres) ListActiveAlertRules() ([]*domain.GeofenceAlertRule, error) {
+	var rules []*domain.GeofenceAlertRule
+	if err := r.db.Where("is_active = ?", true).Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
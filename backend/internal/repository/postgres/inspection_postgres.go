@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// InspectionRepositoryPostgres implements InspectionRepository interface using PostgreSQL
+type InspectionRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewInspectionRepositoryPostgres creates a new PostgreSQL inspection repository
+func NewInspectionRepositoryPostgres(db *gorm.DB) interfaces.InspectionRepository {
+	return &InspectionRepositoryPostgres{db: db}
+}
+
+// preloadTemplate loads sections and items of a template in display order
+func preloadTemplate(db *gorm.DB) *gorm.DB {
+	return db.Preload("Sections", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("sort_order, id")
+	}).Preload("Sections.Items", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("sort_order, id")
+	})
+}
+
+// CreateTemplate creates a new template together with its sections and items
+func (r *InspectionRepositoryPostgres) CreateTemplate(template *domain.InspectionTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// GetTemplateByID retrieves a template with its sections and items
+func (r *InspectionRepositoryPostgres) GetTemplateByID(id uint) (*domain.InspectionTemplate, error) {
+	var template domain.InspectionTemplate
+	if err := preloadTemplate(r.db).First(&template, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("inspection template not found")
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetActiveTemplateByCode retrieves the active version of a template
+func (r *InspectionRepositoryPostgres) GetActiveTemplateByCode(code string) (*domain.InspectionTemplate, error) {
+	var template domain.InspectionTemplate
+	if err := preloadTemplate(r.db).Where("code = ? AND is_active = ?", code, true).
+		Order("version DESC").First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("inspection template not found")
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetTemplates retrieves templates, optionally only the active versions
+func (r *InspectionRepositoryPostgres) GetTemplates(activeOnly bool) ([]*domain.InspectionTemplate, error) {
+	var templates []*domain.InspectionTemplate
+	query := r.db.Order("code, version DESC")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// GetTemplateVersions retrieves all versions of a template, newest first
+func (r *InspectionRepositoryPostgres) GetTemplateVersions(code string) ([]*domain.InspectionTemplate, error) {
+	var templates []*domain.InspectionTemplate
+	if err := r.db.Where("code = ?", code).Order("version DESC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// PublishTemplateVersion stores a new template version and retires the previous ones
+func (r *InspectionRepositoryPostgres) PublishTemplateVersion(template *domain.InspectionTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&domain.InspectionTemplate{}).Where("code = ?", template.Code).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.InspectionTemplate{}).Where("code = ?", template.Code).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+
+		template.Version = latest + 1
+		template.IsActive = true
+		return tx.Create(template).Error
+	})
+}
+
+// DeactivateTemplate retires a template version
+func (r *InspectionRepositoryPostgres) DeactivateTemplate(id uint) error {
+	return r.db.Model(&domain.InspectionTemplate{}).Where("id = ?", id).Update("is_active", false).Error
+}
+
+// Create stores a submitted inspection with its results in one transaction
+func (r *InspectionRepositoryPostgres) Create(inspection *domain.Inspection) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(inspection).Error; err != nil {
+			return err
+		}
+		for i := range inspection.Results {
+			inspection.Results[i].InspectionID = inspection.ID
+			if err := tx.Omit(clause.Associations).Create(&inspection.Results[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves an inspection with its results
+func (r *InspectionRepositoryPostgres) GetByID(id uint) (*domain.Inspection, error) {
+	var inspection domain.Inspection
+	if err := r.db.Preload("Vehicle").Preload("Template").Preload("Inspector").
+		Preload("Results").Preload("Results.Item").
+		First(&inspection, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("inspection not found")
+		}
+		return nil, err
+	}
+	return &inspection, nil
+}
+
+// GetByVehicle retrieves the inspection history of a vehicle, newest first
+func (r *InspectionRepositoryPostgres) GetByVehicle(vehicleID uint, offset, limit int) ([]*domain.Inspection, error) {
+	var inspections []*domain.Inspection
+	if err := r.db.Preload("Template").Preload("Inspector").
+		Where("vehicle_id = ?", vehicleID).
+		Order("submitted_at DESC").
+		Offset(offset).Limit(limit).Find(&inspections).Error; err != nil {
+		return nil, err
+	}
+	return inspections, nil
+}
+
+// CountByVehicle counts inspections recorded for a vehicle
+func (r *InspectionRepositoryPostgres) CountByVehicle(vehicleID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Inspection{}).Where("vehicle_id = ?", vehicleID).Count(&count).Error
+	return count, err
+}
+
+// CreateDefectWorkOrder creates a work order for failed inspection results not yet linked to one
+func (r *InspectionRepositoryPostgres) CreateDefectWorkOrder(workOrder *domain.WorkOrder, resultIDs []uint) error {
+	if len(resultIDs) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var unlinked []uint
+		if err := tx.Model(&domain.InspectionResult{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND defect_work_order_id IS NULL", resultIDs).
+			Pluck("id", &unlinked).Error; err != nil {
+			return err
+		}
+		if len(unlinked) == 0 {
+			return nil
+		}
+		if err := createWorkOrder(tx, workOrder); err != nil {
+			return err
+		}
+		return tx.Model(&domain.InspectionResult{}).Where("id IN ?", unlinked).
+			Update("defect_work_order_id", workOrder.ID).Error
+	})
+}
@@ -0,0 +1,503 @@
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// InventoryRepositoryPostgres implements InventoryRepository interface using PostgreSQL
+type InventoryRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewInventoryRepositoryPostgres creates a new PostgreSQL inventory repository
+func NewInventoryRepositoryPostgres(db *gorm.DB) interfaces.InventoryRepository {
+	return &InventoryRepositoryPostgres{db: db}
+}
+
+// GetItem retrieves an inventory item by ID
+func (r *InventoryRepositoryPostgres) GetItem(id uint) (*domain.InventoryItem, error) {
+	var item domain.InventoryItem
+	if err := r.db.First(&item, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("inventory item not found")
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetWarehouse retrieves a warehouse by ID
+func (r *InventoryRepositoryPostgres) GetWarehouse(id uint) (*domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+	if err := r.db.First(&warehouse, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("warehouse not found")
+		}
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// ListStock retrieves the stock of an item at the active warehouses, most available first
+func (r *InventoryRepositoryPostgres) ListStock(itemID uint) ([]*domain.InventoryStock, error) {
+	stocks := []*domain.InventoryStock{}
+	if err := r.db.Preload("Warehouse").
+		Joins("JOIN warehouses w ON w.id = inventory_stock.warehouse_id AND w.is_active").
+		Where("inventory_stock.inventory_item_id = ?", itemID).
+		Order("inventory_stock.available_quantity DESC, inventory_stock.id").Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// GetPart retrieves a work order part by ID
+func (r *InventoryRepositoryPostgres) GetPart(id uint) (*domain.WorkOrderPart, error) {
+	var part domain.WorkOrderPart
+	if err := r.db.Preload("InventoryItem").Preload("Warehouse").First(&part, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("work order part not found")
+		}
+		return nil, err
+	}
+	return &part, nil
+}
+
+// ListParts retrieves the parts of a work order in the order they were added
+func (r *InventoryRepositoryPostgres) ListParts(workOrderID uint) ([]*domain.WorkOrderPart, error) {
+	parts := []*domain.WorkOrderPart{}
+	if err := r.db.Preload("InventoryItem").Preload("Warehouse").
+		Where("work_order_id = ?", workOrderID).Order("id").Find(&parts).Error; err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// ReservePart reserves what the warehouse has available of a part and raises a transfer for the rest
+func (r *InventoryRepositoryPostgres) ReservePart(part *domain.WorkOrderPart, transfer *domain.StockTransferRequest, check func(committed *interfaces.WorkOrderCommitment) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		stocks, err := lockStock(tx, part.InventoryItemID, part.WarehouseID)
+		if err != nil {
+			return err
+		}
+		stock := stocks[part.WarehouseID]
+		committed, err := lockWorkOrderCommitment(tx, part.WorkOrderID)
+		if err != nil {
+			return err
+		}
+		if err := check(committed); err != nil {
+			return err
+		}
+
+		part.ReservedQuantity = part.Quantity
+		if stock.AvailableQuantity < part.Quantity {
+			part.ReservedQuantity = max(stock.AvailableQuantity, 0)
+		}
+		part.Status = domain.PartStatusReserved
+		if part.ReservedQuantity < part.Quantity {
+			part.Status = domain.PartStatusShort
+		}
+		if err := tx.Omit(clause.Associations).Create(part).Error; err != nil {
+			return err
+		}
+		if err := reserveStock(tx, stock, part.ReservedQuantity); err != nil {
+			return err
+		}
+
+		if part.Status != domain.PartStatusShort {
+			return nil
+		}
+		transfer.InventoryItemID = part.InventoryItemID
+		transfer.ToWarehouseID = part.WarehouseID
+		transfer.Quantity = part.Quantity - part.ReservedQuantity
+		transfer.Status = domain.TransferStatusRequested
+		transfer.WorkOrderID = &part.WorkOrderID
+		transfer.WorkOrderPartID = &part.ID
+		return tx.Omit(clause.Associations).Create(transfer).Error
+	})
+}
+
+// IssuePart takes a reserved part out of stock as a usage transaction
+func (r *InventoryRepositoryPostgres) IssuePart(id uint, movement *domain.InventoryTransaction, check func(part *domain.WorkOrderPart) error) (*domain.WorkOrderPart, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		part, err := lockPart(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := check(part); err != nil {
+			return err
+		}
+		stocks, err := lockStock(tx, part.InventoryItemID, part.WarehouseID)
+		if err != nil {
+			return err
+		}
+		stock := stocks[part.WarehouseID]
+		if stock.Quantity < part.ReservedQuantity || stock.ReservedQuantity < part.ReservedQuantity {
+			return fmt.Errorf("stock of item %d at warehouse %d is below the reserved quantity", part.InventoryItemID, part.WarehouseID)
+		}
+
+		now := time.Now().UTC()
+		if err := tx.Model(stock).Updates(map[string]interface{}{
+			"quantity":          gorm.Expr("quantity - ?", part.ReservedQuantity),
+			"reserved_quantity": gorm.Expr("reserved_quantity - ?", part.ReservedQuantity),
+			"last_updated":      now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(part).Updates(map[string]interface{}{
+			"status":            domain.PartStatusIssued,
+			"reserved_quantity": 0,
+			"used_at":           now,
+			"used_by":           movement.PerformedBy,
+		}).Error; err != nil {
+			return err
+		}
+
+		movement.FromWarehouseID = &part.WarehouseID
+		movement.ToWarehouseID = nil
+		if err := recordMovement(tx, movement, part, domain.TransactionTypeUsage, now); err != nil {
+			return err
+		}
+		return refreshWorkOrderActuals(tx, part.WorkOrderID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPart(id)
+}
+
+// RemovePart releases the reservation of a part or returns an issued part to stock
+func (r *InventoryRepositoryPostgres) RemovePart(id uint, movement *domain.InventoryTransaction, check func(part *domain.WorkOrderPart) error) (*domain.WorkOrderPart, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		part, err := lockPart(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := check(part); err != nil {
+			return err
+		}
+		stocks, err := lockStock(tx, part.InventoryItemID, part.WarehouseID)
+		if err != nil {
+			return err
+		}
+		stock := stocks[part.WarehouseID]
+
+		now := time.Now().UTC()
+		if part.Status == domain.PartStatusIssued {
+			if err := tx.Model(stock).Updates(map[string]interface{}{
+				"quantity":     gorm.Expr("quantity + ?", part.Quantity),
+				"last_updated": now,
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(part).Updates(map[string]interface{}{
+				"status":     domain.PartStatusReturned,
+				"removed_at": now,
+			}).Error; err != nil {
+				return err
+			}
+			movement.FromWarehouseID = nil
+			movement.ToWarehouseID = &part.WarehouseID
+			if err := recordMovement(tx, movement, part, domain.TransactionTypeReturn, now); err != nil {
+				return err
+			}
+			return refreshWorkOrderActuals(tx, part.WorkOrderID)
+		}
+
+		if err := reserveStock(tx, stock, -part.ReservedQuantity); err != nil {
+			return err
+		}
+		if err := tx.Model(part).Updates(map[string]interface{}{
+			"status":            domain.PartStatusReleased,
+			"reserved_quantity": 0,
+			"removed_at":        now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.StockTransferRequest{}).
+			Where("work_order_part_id = ? AND status = ?", part.ID, domain.TransferStatusRequested).
+			Updates(map[string]interface{}{
+				"status": domain.TransferStatusCancelled,
+				"note":   "Part removed from the work order",
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPart(id)
+}
+
+// GetTransfer retrieves a stock transfer request by ID
+func (r *InventoryRepositoryPostgres) GetTransfer(id uint) (*domain.StockTransferRequest, error) {
+	var transfer domain.StockTransferRequest
+	if err := r.db.Preload("InventoryItem").First(&transfer, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("stock transfer request not found")
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// ListTransfers retrieves stock transfer requests with pagination, oldest first
+func (r *InventoryRepositoryPostgres) ListTransfers(filter interfaces.StockTransferFilter, offset, limit int) ([]*domain.StockTransferRequest, int64, error) {
+	query := r.db.Model(&domain.StockTransferRequest{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ToWarehouseID != 0 {
+		query = query.Where("to_warehouse_id = ?", filter.ToWarehouseID)
+	}
+	if filter.WorkOrderID != 0 {
+		query = query.Where("work_order_id = ?", filter.WorkOrderID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	transfers := []*domain.StockTransferRequest{}
+	if err := query.Preload("InventoryItem").Order("created_at, id").
+		Offset(offset).Limit(limit).Find(&transfers).Error; err != nil {
+		return nil, 0, err
+	}
+	return transfers, total, nil
+}
+
+// FulfilTransfer moves the stock of a transfer request and reserves it for the part that was short
+func (r *InventoryRepositoryPostgres) FulfilTransfer(id, fromWarehouseID, fulfilledBy uint, movement *domain.InventoryTransaction, check func(transfer *domain.StockTransferRequest, source *domain.InventoryStock) error) (*domain.StockTransferRequest, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var transfer domain.StockTransferRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transfer, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("stock transfer request not found")
+			}
+			return err
+		}
+
+		// The part is locked before the stock, in the same order as issuing and removing parts
+		var part *domain.WorkOrderPart
+		if transfer.WorkOrderPartID != nil {
+			locked, err := lockPart(tx, *transfer.WorkOrderPartID)
+			if err != nil {
+				return err
+			}
+			part = locked
+		}
+
+		stocks, err := lockStock(tx, transfer.InventoryItemID, fromWarehouseID, transfer.ToWarehouseID)
+		if err != nil {
+			return err
+		}
+		source, destination := stocks[fromWarehouseID], stocks[transfer.ToWarehouseID]
+		if err := check(&transfer, source); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if err := tx.Model(source).Updates(map[string]interface{}{
+			"quantity":     gorm.Expr("quantity - ?", transfer.Quantity),
+			"last_updated": now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(destination).Updates(map[string]interface{}{
+			"quantity":     gorm.Expr("quantity + ?", transfer.Quantity),
+			"last_updated": now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&transfer).Updates(map[string]interface{}{
+			"status":            domain.TransferStatusFulfilled,
+			"from_warehouse_id": fromWarehouseID,
+			"fulfilled_by":      fulfilledBy,
+			"fulfilled_at":      now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var item domain.InventoryItem
+		if err := tx.First(&item, transfer.InventoryItemID).Error; err != nil {
+			return err
+		}
+		movement.InventoryItemID = transfer.InventoryItemID
+		movement.FromWarehouseID = &fromWarehouseID
+		movement.ToWarehouseID = &transfer.ToWarehouseID
+		movement.TransactionType = domain.TransactionTypeTransfer
+		movement.Quantity = transfer.Quantity
+		movement.UnitPrice = item.UnitPrice
+		movement.TotalValue = roundMoney(item.UnitPrice * float64(transfer.Quantity))
+		movement.ReferenceType = "stock_transfer_request"
+		movement.ReferenceID = &transfer.ID
+		movement.TransactionDate = now
+		if err := tx.Omit(clause.Associations).Create(movement).Error; err != nil {
+			return err
+		}
+
+		if part == nil || part.Status != domain.PartStatusShort || part.WarehouseID != transfer.ToWarehouseID {
+			return nil
+		}
+		// Top up the reservation of the short part from what the destination now has available
+		destination.AvailableQuantity += transfer.Quantity
+		topUp := min(part.Quantity-part.ReservedQuantity, max(destination.AvailableQuantity, 0))
+		if topUp == 0 {
+			return nil
+		}
+		if err := reserveStock(tx, destination, topUp); err != nil {
+			return err
+		}
+		status := domain.PartStatusShort
+		if part.ReservedQuantity+topUp == part.Quantity {
+			status = domain.PartStatusReserved
+		}
+		return tx.Model(part).Updates(map[string]interface{}{
+			"status":            status,
+			"reserved_quantity": part.ReservedQuantity + topUp,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetTransfer(id)
+}
+
+// CancelTransfer cancels an open stock transfer request
+func (r *InventoryRepositoryPostgres) CancelTransfer(id uint, note string, check func(transfer *domain.StockTransferRequest) error) (*domain.StockTransferRequest, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var transfer domain.StockTransferRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transfer, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("stock transfer request not found")
+			}
+			return err
+		}
+		if err := check(&transfer); err != nil {
+			return err
+		}
+		return tx.Model(&transfer).Updates(map[string]interface{}{
+			"status": domain.TransferStatusCancelled,
+			"note":   note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetTransfer(id)
+}
+
+// lockPart locks a work order part for the rest of the transaction
+func lockPart(tx *gorm.DB, id uint) (*domain.WorkOrderPart, error) {
+	var part domain.WorkOrderPart
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&part, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("work order part not found")
+		}
+		return nil, err
+	}
+	return &part, nil
+}
+
+// lockStock locks the stock rows of an item at the warehouses for the rest of the transaction and
+// returns them by warehouse. Empty rows are created for warehouses that never held the item, and
+// rows are locked in ID order so concurrent movements between the same warehouses cannot deadlock.
+func lockStock(tx *gorm.DB, itemID uint, warehouseIDs ...uint) (map[uint]*domain.InventoryStock, error) {
+	for _, warehouseID := range warehouseIDs {
+		if err := tx.Exec(`
+			INSERT INTO inventory_stock (inventory_item_id, warehouse_id, quantity, reserved_quantity)
+			VALUES (?, ?, 0, 0)
+			ON CONFLICT (inventory_item_id, warehouse_id) DO NOTHING`, itemID, warehouseID).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	stocks := []*domain.InventoryStock{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("inventory_item_id = ? AND warehouse_id IN ?", itemID, warehouseIDs).
+		Order("id").Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	byWarehouse := make(map[uint]*domain.InventoryStock, len(stocks))
+	for _, stock := range stocks {
+		byWarehouse[stock.WarehouseID] = stock
+	}
+	return byWarehouse, nil
+}
+
+// reserveStock adds quantity, negative to release, to the reserved stock of a locked row
+func reserveStock(tx *gorm.DB, stock *domain.InventoryStock, quantity int) error {
+	if quantity == 0 {
+		return nil
+	}
+	return tx.Model(stock).Updates(map[string]interface{}{
+		"reserved_quantity": gorm.Expr("GREATEST(reserved_quantity + ?, 0)", quantity),
+		"last_updated":      time.Now().UTC(),
+	}).Error
+}
+
+// recordMovement stores the inventory transaction of a work order part, valued at the part's unit price
+func recordMovement(tx *gorm.DB, movement *domain.InventoryTransaction, part *domain.WorkOrderPart, transactionType string, at time.Time) error {
+	movement.InventoryItemID = part.InventoryItemID
+	movement.TransactionType = transactionType
+	movement.Quantity = part.Quantity
+	movement.UnitPrice = part.UnitPrice
+	movement.TotalValue = roundMoney(part.UnitPrice * float64(part.Quantity))
+	movement.ReferenceType = "work_order"
+	movement.ReferenceID = &part.WorkOrderID
+	movement.TransactionDate = at
+	return tx.Omit(clause.Associations).Create(movement).Error
+}
+
+// lockWorkOrder locks a work order for the rest of the transaction, serialising the work added to it
+func lockWorkOrder(tx *gorm.DB, workOrderID uint) error {
+	var workOrder domain.WorkOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&workOrder, workOrderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("work order not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// lockWorkOrderCommitment locks a work order and reads its labor and its reserved, short and
+// issued parts
+func lockWorkOrderCommitment(tx *gorm.DB, workOrderID uint) (*interfaces.WorkOrderCommitment, error) {
+	if err := lockWorkOrder(tx, workOrderID); err != nil {
+		return nil, err
+	}
+	committed := &interfaces.WorkOrderCommitment{}
+	if err := tx.Where("work_order_id = ?", workOrderID).Order("work_date, id").Find(&committed.Labor).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("work_order_id = ? AND status IN ?", workOrderID,
+		[]string{domain.PartStatusReserved, domain.PartStatusShort, domain.PartStatusIssued}).
+		Order("id").Find(&committed.Parts).Error; err != nil {
+		return nil, err
+	}
+	return committed, nil
+}
+
+// refreshWorkOrderActuals recalculates the actual hours and cost of a work order from its labor
+// and issued parts
+func refreshWorkOrderActuals(tx *gorm.DB, workOrderID uint) error {
+	return tx.Exec(`
+		UPDATE work_orders SET
+			actual_hours = (SELECT COALESCE(SUM(hours_worked), 0) FROM work_order_labor WHERE work_order_id = ?),
+			actual_cost = (SELECT COALESCE(SUM(total_cost), 0) FROM work_order_labor WHERE work_order_id = ?)
+				+ (SELECT COALESCE(SUM(total_price), 0) FROM work_order_parts WHERE work_order_id = ? AND status = ?)
+		WHERE id = ?`,
+		workOrderID, workOrderID, workOrderID, domain.PartStatusIssued, workOrderID).Error
+}
+
+// roundMoney rounds an amount to cents
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// InvoiceRepositoryPostgres implements InvoiceRepository interface using PostgreSQL
+type InvoiceRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepositoryPostgres creates a new PostgreSQL invoice repository
+func NewInvoiceRepositoryPostgres(db *gorm.DB) interfaces.InvoiceRepository {
+	return &InvoiceRepositoryPostgres{db: db}
+}
+
+// Create creates a new invoice
+// The invoice number is generated by a database trigger and loaded back after insert.
+func (r *InvoiceRepositoryPostgres) Create(invoice *domain.Invoice) error {
+	if err := r.db.Omit(clause.Associations).Create(invoice).Error; err != nil {
+		return err
+	}
+	return r.db.Raw("SELECT invoice_number FROM invoices WHERE id = ?", invoice.ID).
+		Scan(&invoice.InvoiceNumber).Error
+}
+
+// GetByID retrieves an invoice with its line items
+func (r *InvoiceRepositoryPostgres) GetByID(id uint) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	if err := r.db.Preload("InvoiceItems").First(&invoice, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// AddItem adds a line item to an invoice; invoice totals are maintained by a database trigger
+func (r *InvoiceRepositoryPostgres) AddItem(item *domain.InvoiceItem) error {
+	return r.db.Omit(clause.Associations).Create(item).Error
+}
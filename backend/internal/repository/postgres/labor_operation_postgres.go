@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// laborOperationColumns are the catalogue columns written when an operation is updated
+var laborOperationColumns = []string{"code", "description", "vehicle_types", "makes", "standard_hours", "default_price", "is_active"}
+
+// LaborOperationRepositoryPostgres implements LaborOperationRepository interface using PostgreSQL
+type LaborOperationRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewLaborOperationRepositoryPostgres creates a new PostgreSQL labor operation repository
+func NewLaborOperationRepositoryPostgres(db *gorm.DB) interfaces.LaborOperationRepository {
+	return &LaborOperationRepositoryPostgres{db: db}
+}
+
+// Create creates a labor operation
+func (r *LaborOperationRepositoryPostgres) Create(operation *domain.LaborOperation) error {
+	return r.db.Create(operation).Error
+}
+
+// GetByID retrieves a labor operation by ID
+func (r *LaborOperationRepositoryPostgres) GetByID(id uint) (*domain.LaborOperation, error) {
+	var operation domain.LaborOperation
+	if err := r.db.First(&operation, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("labor operation not found")
+		}
+		return nil, err
+	}
+	return &operation, nil
+}
+
+// GetByCodes retrieves the labor operations with the given codes
+func (r *LaborOperationRepositoryPostgres) GetByCodes(codes []string) ([]*domain.LaborOperation, error) {
+	operations := []*domain.LaborOperation{}
+	if len(codes) == 0 {
+		return operations, nil
+	}
+	if err := r.db.Where("code IN ?", codes).Find(&operations).Error; err != nil {
+		return nil, err
+	}
+	return operations, nil
+}
+
+// List retrieves a page of labor operations ordered by code
+func (r *LaborOperationRepositoryPostgres) List(filter interfaces.LaborOperationFilter, offset, limit int) ([]*domain.LaborOperation, int64, error) {
+	query := r.db.Model(&domain.LaborOperation{})
+	if filter.Search != "" {
+		search := "%" + filter.Search + "%"
+		query = query.Where("(code ILIKE ? OR description ILIKE ?)", search, search)
+	}
+	if filter.VehicleType != "" {
+		query = query.Where("(vehicle_types = '[]'::jsonb OR vehicle_types @> jsonb_build_array(?::text))", filter.VehicleType)
+	}
+	if filter.Make != "" {
+		query = query.Where(`(makes = '[]'::jsonb OR EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(makes) m WHERE LOWER(m) = LOWER(?)))`, filter.Make)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	operations := []*domain.LaborOperation{}
+	if err := query.Order("code").Offset(offset).Limit(limit).Find(&operations).Error; err != nil {
+		return nil, 0, err
+	}
+	return operations, total, nil
+}
+
+// Update saves the catalogue fields of a labor operation
+func (r *LaborOperationRepositoryPostgres) Update(operation *domain.LaborOperation) error {
+	result := r.db.Model(operation).Select(laborOperationColumns).Updates(operation)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("labor operation not found")
+	}
+	return nil
+}
+
+// Import creates and updates labor operations in a single transaction
+func (r *LaborOperationRepositoryPostgres) Import(created, updated []*domain.LaborOperation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(created) > 0 {
+			if err := tx.CreateInBatches(created, 500).Error; err != nil {
+				return err
+			}
+		}
+		for _, operation := range updated {
+			if err := tx.Model(operation).Select(laborOperationColumns).Updates(operation).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListStandardComparison sums the labor recorded per operation task on the work orders completed in
+// the range. Tasks without labor have nothing to compare and are left out.
+func (r *LaborOperationRepositoryPostgres) ListStandardComparison(filter interfaces.LaborStandardFilter) ([]*interfaces.LaborStandardRow, error) {
+	query := r.db.Table("work_order_tasks t").
+		Select(`o.id AS operation_id, o.code, o.description, t.id AS task_id, wo.id AS work_order_id, wo.wo_number,
+			wo.completion_date, COALESCE(t.standard_hours, o.standard_hours) AS standard_hours,
+			SUM(l.hours_worked) AS actual_hours, SUM(l.total_cost) AS labor_cost`).
+		Joins("JOIN labor_operations o ON o.id = t.operation_id").
+		Joins("JOIN work_orders wo ON wo.id = t.work_order_id").
+		Joins("JOIN work_order_labor l ON l.task_id = t.id").
+		Where("wo.status = ? AND wo.completion_date >= ? AND wo.completion_date < ?", domain.StatusCompleted, filter.From, filter.To)
+	if filter.OperationID != 0 {
+		query = query.Where("t.operation_id = ?", filter.OperationID)
+	}
+	if filter.BranchID != 0 {
+		query = query.Where("wo.branch_id = ?", filter.BranchID)
+	}
+
+	rows := []*interfaces.LaborStandardRow{}
+	if err := query.Group("o.id, t.id, wo.id").
+		Order("o.code, wo.completion_date, t.id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// laborClockLockNamespace scopes the advisory locks that serialize clocking in per mechanic
+const laborClockLockNamespace = 40001
+
+// LaborRepositoryPostgres implements LaborRepository interface using PostgreSQL
+type LaborRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewLaborRepositoryPostgres creates a new PostgreSQL labor repository
+func NewLaborRepositoryPostgres(db *gorm.DB) interfaces.LaborRepository {
+	return &LaborRepositoryPostgres{db: db}
+}
+
+// CreateTask creates a work order task
+func (r *LaborRepositoryPostgres) CreateTask(task *domain.WorkOrderTask) error {
+	return r.db.Create(task).Error
+}
+
+// GetTask retrieves a work order task by ID
+func (r *LaborRepositoryPostgres) GetTask(id uint) (*domain.WorkOrderTask, error) {
+	var task domain.WorkOrderTask
+	if err := r.db.First(&task, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("work order task not found")
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListTasks retrieves the tasks of a work order in creation order
+func (r *LaborRepositoryPostgres) ListTasks(workOrderID uint) ([]*domain.WorkOrderTask, error) {
+	tasks := []*domain.WorkOrderTask{}
+	if err := r.db.Where("work_order_id = ?", workOrderID).Order("id").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// StartEntry stores a running time entry once check accepts the mechanic's current running entry
+// and the work committed to the work order
+func (r *LaborRepositoryPostgres) StartEntry(entry *domain.LaborTimeEntry, check func(active *domain.LaborTimeEntry, committed *interfaces.WorkOrderCommitment) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", laborClockLockNamespace, entry.MechanicID).Error; err != nil {
+			return err
+		}
+		var active *domain.LaborTimeEntry
+		var running domain.LaborTimeEntry
+		err := tx.Where("mechanic_id = ? AND ended_at IS NULL", entry.MechanicID).First(&running).Error
+		switch {
+		case err == nil:
+			active = &running
+		case err != gorm.ErrRecordNotFound:
+			return err
+		}
+		committed, err := lockWorkOrderCommitment(tx, entry.WorkOrderID)
+		if err != nil {
+			return err
+		}
+		if err := check(active, committed); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(entry).Error
+	})
+}
+
+// GetActiveEntry retrieves the running time entry of a mechanic
+func (r *LaborRepositoryPostgres) GetActiveEntry(mechanicID uint) (*domain.LaborTimeEntry, error) {
+	var entry domain.LaborTimeEntry
+	if err := r.db.Where("mechanic_id = ? AND ended_at IS NULL", mechanicID).First(&entry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("active time entry not found")
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListActiveEntries retrieves the running time entries on a work order
+func (r *LaborRepositoryPostgres) ListActiveEntries(workOrderID uint) ([]*domain.LaborTimeEntry, error) {
+	entries := []*domain.LaborTimeEntry{}
+	if err := r.db.Where("work_order_id = ? AND ended_at IS NULL", workOrderID).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// StopEntry ends a running time entry and rolls its hours up into work order labor
+func (r *LaborRepositoryPostgres) StopEntry(entry *domain.LaborTimeEntry, hourlyRate float64, completeTask bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockWorkOrder(tx, entry.WorkOrderID); err != nil {
+			return err
+		}
+		result := tx.Model(&domain.LaborTimeEntry{}).Where("id = ? AND ended_at IS NULL", entry.ID).Updates(map[string]interface{}{
+			"ended_at":   entry.EndedAt,
+			"end_reason": entry.EndReason,
+			"note":       entry.Note,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("active time entry not found")
+		}
+
+		var task domain.WorkOrderTask
+		if err := tx.First(&task, entry.TaskID).Error; err != nil {
+			return err
+		}
+		if completeTask && task.Status != domain.TaskStatusCompleted {
+			if err := tx.Model(&task).Updates(map[string]interface{}{
+				"status":       domain.TaskStatusCompleted,
+				"completed_at": entry.EndedAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		// The labor line of the task, mechanic and day holds all their finished entries that
+		// started that day
+		workDate := entry.StartedAt.UTC().Truncate(24 * time.Hour)
+		var hours float64
+		if err := tx.Raw(`
+			SELECT COALESCE(ROUND((SUM(EXTRACT(EPOCH FROM (ended_at - started_at))) / 3600)::numeric, 2), 0)
+			FROM labor_time_entries
+			WHERE task_id = ? AND mechanic_id = ? AND ended_at IS NOT NULL AND started_at >= ? AND started_at < ?`,
+			entry.TaskID, entry.MechanicID, workDate, workDate.AddDate(0, 0, 1)).Scan(&hours).Error; err != nil {
+			return err
+		}
+		labor := &domain.WorkOrderLabor{
+			WorkOrderID: entry.WorkOrderID,
+			TaskID:      &entry.TaskID,
+			MechanicID:  entry.MechanicID,
+			Description: task.Description,
+			HoursWorked: hours,
+			HourlyRate:  hourlyRate,
+			WorkDate:    workDate,
+		}
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "work_order_id"}, {Name: "task_id"}, {Name: "mechanic_id"}, {Name: "work_date"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "task_id IS NOT NULL"}}},
+			DoUpdates:   clause.AssignmentColumns([]string{"hours_worked", "updated_at"}),
+		}).Create(labor).Error; err != nil {
+			return err
+		}
+
+		return refreshWorkOrderActuals(tx, entry.WorkOrderID)
+	})
+}
+
+// ListEntries retrieves the time entries of a work order, earliest first
+func (r *LaborRepositoryPostgres) ListEntries(workOrderID uint) ([]*domain.LaborTimeEntry, error) {
+	entries := []*domain.LaborTimeEntry{}
+	if err := r.db.Preload("Mechanic").Where("work_order_id = ?", workOrderID).
+		Order("started_at").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListLabor retrieves the labor lines of a work order by work date
+func (r *LaborRepositoryPostgres) ListLabor(workOrderID uint) ([]*domain.WorkOrderLabor, error) {
+	labor := []*domain.WorkOrderLabor{}
+	if err := r.db.Preload("Mechanic").Where("work_order_id = ?", workOrderID).
+		Order("work_date, id").Find(&labor).Error; err != nil {
+		return nil, err
+	}
+	return labor, nil
+}
+
+// ListLaborEfficiency sums the labor of each mechanic on the work orders completed in the range
+func (r *LaborRepositoryPostgres) ListLaborEfficiency(filter interfaces.LaborEfficiencyFilter) ([]*interfaces.LaborEfficiencyRow, error) {
+	query := r.db.Table("work_order_labor l").
+		Select(`wo.id AS work_order_id, wo.wo_number, wo.service_type, COALESCE(wo.estimated_hours, 0) AS estimated_hours,
+			wo.completion_date, l.mechanic_id, u.first_name, u.last_name,
+			SUM(l.hours_worked) AS hours, SUM(l.total_cost) AS cost`).
+		Joins("JOIN work_orders wo ON wo.id = l.work_order_id").
+		Joins("JOIN users u ON u.id = l.mechanic_id").
+		Where("wo.status = ? AND wo.completion_date >= ? AND wo.completion_date < ?", domain.StatusCompleted, filter.From, filter.To)
+	if filter.MechanicID != 0 {
+		query = query.Where("wo.id IN (SELECT work_order_id FROM work_order_labor WHERE mechanic_id = ?)", filter.MechanicID)
+	}
+	if filter.BranchID != 0 {
+		query = query.Where("wo.branch_id = ?", filter.BranchID)
+	}
+
+	rows := []*interfaces.LaborEfficiencyRow{}
+	if err := query.Group("wo.id, l.mechanic_id, u.first_name, u.last_name").
+		Order("wo.completion_date, wo.id, l.mechanic_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
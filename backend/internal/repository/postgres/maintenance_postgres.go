@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// maintenancePlanColumns are the plan columns written when a plan is updated
+var maintenancePlanColumns = []string{
+	"name", "description", "vehicle_type", "make", "model", "interval_km", "interval_days", "interval_engine_hours",
+	"lead_km", "lead_days", "lead_engine_hours", "action", "operation_id", "priority", "recipient_user_ids", "is_active",
+}
+
+// maintenanceScheduleColumns are the schedule columns written when a schedule is evaluated or done
+var maintenanceScheduleColumns = []string{
+	"last_done_at", "last_done_odometer", "last_done_engine_hours", "next_due_at", "next_due_odometer",
+	"next_due_engine_hours", "current_odometer", "current_engine_hours", "status", "evaluated_at",
+	"work_order_id", "notified_status", "is_active",
+}
+
+// MaintenanceRepositoryPostgres implements MaintenanceRepository interface using PostgreSQL
+type MaintenanceRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewMaintenanceRepositoryPostgres creates a new PostgreSQL maintenance repository
+func NewMaintenanceRepositoryPostgres(db *gorm.DB) interfaces.MaintenanceRepository {
+	return &MaintenanceRepositoryPostgres{db: db}
+}
+
+// CreatePlan creates a maintenance plan
+func (r *MaintenanceRepositoryPostgres) CreatePlan(plan *domain.MaintenancePlan) error {
+	return r.db.Create(plan).Error
+}
+
+// GetPlan retrieves a maintenance plan by ID
+func (r *MaintenanceRepositoryPostgres) GetPlan(id uint) (*domain.MaintenancePlan, error) {
+	var plan domain.MaintenancePlan
+	if err := r.db.First(&plan, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("maintenance plan not found")
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// ListPlans retrieves the maintenance plans ordered by name
+func (r *MaintenanceRepositoryPostgres) ListPlans(activeOnly bool) ([]*domain.MaintenancePlan, error) {
+	query := r.db.Model(&domain.MaintenancePlan{})
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	plans := []*domain.MaintenancePlan{}
+	if err := query.Order("name, id").Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// UpdatePlan saves the fields of a maintenance plan
+func (r *MaintenanceRepositoryPostgres) UpdatePlan(plan *domain.MaintenancePlan) error {
+	result := r.db.Model(plan).Select(maintenancePlanColumns).Updates(plan)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("maintenance plan not found")
+	}
+	return nil
+}
+
+// ListUnscheduledVehicles retrieves the vehicles matching a plan without an active schedule for it
+func (r *MaintenanceRepositoryPostgres) ListUnscheduledVehicles(plan *domain.MaintenancePlan) ([]*domain.Vehicle, error) {
+	query := r.db.Model(&domain.Vehicle{}).
+		Where(`NOT EXISTS (SELECT 1 FROM vehicle_maintenance_schedules s
+			WHERE s.vehicle_id = vehicles.id AND s.plan_id = ? AND s.is_active)`, plan.ID)
+	if plan.VehicleType != nil {
+		query = query.Where("type = ?", *plan.VehicleType)
+	}
+	if plan.Make != nil {
+		query = query.Where("LOWER(make) = LOWER(?)", *plan.Make)
+	}
+	if plan.Model != nil {
+		query = query.Where("LOWER(model) = LOWER(?)", *plan.Model)
+	}
+
+	vehicles := []*domain.Vehicle{}
+	if err := query.Order("id").Find(&vehicles).Error; err != nil {
+		return nil, err
+	}
+	return vehicles, nil
+}
+
+// CreateSchedules creates vehicle maintenance schedules. A retired schedule for the same plan and
+// vehicle is reactivated instead, keeping when the plan was last done.
+func (r *MaintenanceRepositoryPostgres) CreateSchedules(schedules []*domain.VehicleMaintenanceSchedule) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "plan_id"}, {Name: "vehicle_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_active", "updated_at"}),
+	}).CreateInBatches(schedules, 500).Error
+}
+
+// GetSchedule retrieves a vehicle maintenance schedule with its plan and vehicle
+func (r *MaintenanceRepositoryPostgres) GetSchedule(id uint) (*domain.VehicleMaintenanceSchedule, error) {
+	var schedule domain.VehicleMaintenanceSchedule
+	if err := r.db.Preload("Plan").Preload("Vehicle").First(&schedule, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("maintenance schedule not found")
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ListSchedules retrieves a page of active schedules, the most urgent and soonest due first
+func (r *MaintenanceRepositoryPostgres) ListSchedules(filter interfaces.MaintenanceScheduleFilter, offset, limit int) ([]*domain.VehicleMaintenanceSchedule, int64, error) {
+	query := r.db.Model(&domain.VehicleMaintenanceSchedule{}).
+		Joins("JOIN maintenance_plans p ON p.id = vehicle_maintenance_schedules.plan_id").
+		Where("vehicle_maintenance_schedules.is_active = ? AND p.is_active = ?", true, true)
+	if filter.PlanID != 0 {
+		query = query.Where("vehicle_maintenance_schedules.plan_id = ?", filter.PlanID)
+	}
+	if filter.VehicleID != 0 {
+		query = query.Where("vehicle_maintenance_schedules.vehicle_id = ?", filter.VehicleID)
+	}
+	if filter.AreaID != 0 {
+		query = query.Where("vehicle_maintenance_schedules.vehicle_id IN (SELECT id FROM vehicles WHERE area_id = ?)", filter.AreaID)
+	}
+	if filter.Status != "" {
+		query = query.Where("vehicle_maintenance_schedules.status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	schedules := []*domain.VehicleMaintenanceSchedule{}
+	err := query.Preload("Plan").Preload("Vehicle").
+		Order(`CASE vehicle_maintenance_schedules.status WHEN 'overdue' THEN 0 WHEN 'due_soon' THEN 1 ELSE 2 END,
+			vehicle_maintenance_schedules.next_due_at NULLS LAST, vehicle_maintenance_schedules.id`).
+		Offset(offset).Limit(limit).Find(&schedules).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return schedules, total, nil
+}
+
+// ListSchedulesByWorkOrder retrieves the schedules linked to a work order with their plan
+func (r *MaintenanceRepositoryPostgres) ListSchedulesByWorkOrder(workOrderID uint) ([]*domain.VehicleMaintenanceSchedule, error) {
+	schedules := []*domain.VehicleMaintenanceSchedule{}
+	if err := r.db.Preload("Plan").Where("work_order_id = ?", workOrderID).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// FindActiveSchedulesInBatches walks the active schedules of active plans in ID order
+func (r *MaintenanceRepositoryPostgres) FindActiveSchedulesInBatches(batchSize int, fn func(schedules []*domain.VehicleMaintenanceSchedule) error) error {
+	query := r.db.Model(&domain.VehicleMaintenanceSchedule{}).
+		Preload("Plan").Preload("Vehicle").
+		Where("is_active = ? AND plan_id IN (SELECT id FROM maintenance_plans WHERE is_active = ?)", true, true)
+
+	var batch []*domain.VehicleMaintenanceSchedule
+	return query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// UpdateSchedule saves the tracked fields of a vehicle maintenance schedule
+func (r *MaintenanceRepositoryPostgres) UpdateSchedule(schedule *domain.VehicleMaintenanceSchedule) error {
+	result := r.db.Model(schedule).Select(maintenanceScheduleColumns).Updates(schedule)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("maintenance schedule not found")
+	}
+	return nil
+}
+
+// ReadMeters reads the odometer from the vehicle record and the latest telematics state, and
+// sums the duration of the vehicle's trips as its engine hours
+func (r *MaintenanceRepositoryPostgres) ReadMeters(vehicleIDs []uint) (map[uint]*interfaces.MeterReading, error) {
+	readings := make(map[uint]*interfaces.MeterReading, len(vehicleIDs))
+	if len(vehicleIDs) == 0 {
+		return readings, nil
+	}
+
+	var rows []*interfaces.MeterReading
+	err := r.db.Table("vehicles v").
+		Select(`v.id AS vehicle_id,
+			GREATEST(COALESCE(v.odometer, 0), FLOOR(COALESCE(ls.total_distance, 0)))::int AS odometer,
+			COALESCE((SELECT SUM(t.duration_seconds) FROM trips t WHERE t.vehicle_id = v.id), 0) / 3600.0 AS engine_hours`).
+		Joins("LEFT JOIN vehicle_latest_states ls ON ls.vehicle_id = v.id").
+		Where("v.id IN ?", vehicleIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		readings[row.VehicleID] = row
+	}
+	return readings, nil
+}
+
+// SyncVehicleServiceDates sets the next service date of the vehicles to the earliest due date of their schedules
+func (r *MaintenanceRepositoryPostgres) SyncVehicleServiceDates(vehicleIDs []uint) error {
+	if len(vehicleIDs) == 0 {
+		return nil
+	}
+	return r.db.Exec(`UPDATE vehicles v SET next_service_date = d.next_due_at, updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT s.vehicle_id, MIN(s.next_due_at) AS next_due_at
+			FROM vehicle_maintenance_schedules s
+			JOIN maintenance_plans p ON p.id = s.plan_id
+			WHERE s.is_active AND p.is_active AND s.next_due_at IS NOT NULL AND s.vehicle_id IN ?
+			GROUP BY s.vehicle_id
+		) d
+		WHERE v.id = d.vehicle_id AND v.next_service_date IS DISTINCT FROM d.next_due_at`, vehicleIDs).Error
+}
+
+// RecordVehicleService moves the last service date of a vehicle up to when maintenance was done
+func (r *MaintenanceRepositoryPostgres) RecordVehicleService(vehicleID uint, doneAt time.Time) error {
+	return r.db.Exec(`UPDATE vehicles SET last_service_date = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND (last_service_date IS NULL OR last_service_date < ?)`, doneAt, vehicleID, doneAt).Error
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// MechanicRepositoryPostgres implements MechanicRepository interface using PostgreSQL
+type MechanicRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewMechanicRepositoryPostgres creates a new PostgreSQL mechanic repository
+func NewMechanicRepositoryPostgres(db *gorm.DB) interfaces.MechanicRepository {
+	return &MechanicRepositoryPostgres{db: db}
+}
+
+// GetProfile retrieves a mechanic profile with its user, branch and skills
+func (r *MechanicRepositoryPostgres) GetProfile(userID uint) (*domain.MechanicProfile, error) {
+	var profile domain.MechanicProfile
+	if err := r.db.Preload("User").Preload("Branch").Preload("Skills", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&profile, "user_id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("mechanic profile not found")
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ListProfiles retrieves the profiles of the given mechanics with their skills
+func (r *MechanicRepositoryPostgres) ListProfiles(userIDs []uint) ([]*domain.MechanicProfile, error) {
+	profiles := []*domain.MechanicProfile{}
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+	if err := r.db.Preload("Skills", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("user_id IN ?", userIDs).Order("user_id").Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// SaveProfile creates or updates a profile and replaces its skills
+func (r *MechanicRepositoryPostgres) SaveProfile(profile *domain.MechanicProfile) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"branch_id", "warehouse_id", "daily_capacity_hours", "hourly_rate", "is_available", "notes", "updated_at"}),
+		}).Create(profile).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", profile.UserID).Delete(&domain.MechanicSkill{}).Error; err != nil {
+			return err
+		}
+		for i := range profile.Skills {
+			profile.Skills[i].ID = 0
+			profile.Skills[i].UserID = profile.UserID
+		}
+		if len(profile.Skills) > 0 {
+			if err := tx.Create(&profile.Skills).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateShift creates a shift
+func (r *MechanicRepositoryPostgres) CreateShift(shift *domain.MechanicShift) error {
+	return r.db.Create(shift).Error
+}
+
+// GetShift retrieves a shift by ID
+func (r *MechanicRepositoryPostgres) GetShift(id uint) (*domain.MechanicShift, error) {
+	var shift domain.MechanicShift
+	if err := r.db.First(&shift, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("mechanic shift not found")
+		}
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// DeleteShift deletes a shift
+func (r *MechanicRepositoryPostgres) DeleteShift(id uint) error {
+	result := r.db.Delete(&domain.MechanicShift{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("mechanic shift not found")
+	}
+	return nil
+}
+
+// ListShifts retrieves the shifts of the mechanics overlapping [from, to), earliest first
+func (r *MechanicRepositoryPostgres) ListShifts(userIDs []uint, from, to time.Time) ([]*domain.MechanicShift, error) {
+	shifts := []*domain.MechanicShift{}
+	if len(userIDs) == 0 {
+		return shifts, nil
+	}
+	if err := r.db.Where("user_id IN ? AND starts_at < ? AND ends_at > ?", userIDs, to, from).
+		Order("starts_at").Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
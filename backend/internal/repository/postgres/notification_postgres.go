@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// NotificationRepositoryPostgres implements NotificationRepository interface using PostgreSQL
+type NotificationRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepositoryPostgres creates a new PostgreSQL notification repository
+func NewNotificationRepositoryPostgres(db *gorm.DB) interfaces.NotificationRepository {
+	return &NotificationRepositoryPostgres{db: db}
+}
+
+// CreateBatch stores notifications
+func (r *NotificationRepositoryPostgres) CreateBatch(notifications []*domain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Create(&notifications).Error
+}
+
+// ListByUser retrieves a page of a user's notifications, newest first
+func (r *NotificationRepositoryPostgres) ListByUser(userID uint, unreadOnly bool, offset, limit int) ([]*domain.Notification, int64, error) {
+	query := r.db.Model(&domain.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []*domain.Notification
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// CountUnread counts a user's unread notifications
+func (r *NotificationRepositoryPostgres) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one notification of a user as read
+func (r *NotificationRepositoryPostgres) MarkRead(userID, id uint) error {
+	result := r.db.Model(&domain.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now().UTC()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read
+func (r *NotificationRepositoryPostgres) MarkAllRead(userID uint) (int64, error) {
+	result := r.db.Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now().UTC())
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// ServiceRequestRepositoryPostgres implements ServiceRequestRepository interface using PostgreSQL
+type ServiceRequestRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewServiceRequestRepositoryPostgres creates a new PostgreSQL service request repository
+func NewServiceRequestRepositoryPostgres(db *gorm.DB) interfaces.ServiceRequestRepository {
+	return &ServiceRequestRepositoryPostgres{db: db}
+}
+
+// Create creates a new service request
+func (r *ServiceRequestRepositoryPostgres) Create(request *domain.ServiceRequest) error {
+	return r.db.Omit(clause.Associations).Create(request).Error
+}
+
+// GetByID retrieves a service request with its vehicle, area, branch and work order
+func (r *ServiceRequestRepositoryPostgres) GetByID(id uint) (*domain.ServiceRequest, error) {
+	var request domain.ServiceRequest
+	if err := r.db.Preload("Vehicle").Preload("Area").Preload("Requester").Preload("Branch").Preload("WorkOrder").
+		First(&request, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("service request not found")
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// List retrieves a page of service requests, most urgent first, then oldest first
+func (r *ServiceRequestRepositoryPostgres) List(filter interfaces.ServiceRequestFilter, offset, limit int) ([]*domain.ServiceRequest, int64, error) {
+	query := r.db.Model(&domain.ServiceRequest{})
+	if filter.VehicleID != 0 {
+		query = query.Where("vehicle_id = ?", filter.VehicleID)
+	}
+	if filter.RequestedBy != 0 {
+		query = query.Where("requested_by = ?", filter.RequestedBy)
+	}
+	if filter.AreaIDs != nil {
+		switch {
+		case len(filter.AreaIDs) > 0 && filter.WithoutArea:
+			query = query.Where("(area_id IN ? OR area_id IS NULL)", filter.AreaIDs)
+		case len(filter.AreaIDs) > 0:
+			query = query.Where("area_id IN ?", filter.AreaIDs)
+		case filter.WithoutArea:
+			query = query.Where("area_id IS NULL")
+		default:
+			return []*domain.ServiceRequest{}, 0, nil
+		}
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Urgency != "" {
+		query = query.Where("urgency = ?", filter.Urgency)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var requests []*domain.ServiceRequest
+	if err := query.Preload("Vehicle").Preload("Area").
+		Order("CASE urgency WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'normal' THEN 2 ELSE 3 END").
+		Order("created_at, id").
+		Offset(offset).Limit(limit).Find(&requests).Error; err != nil {
+		return nil, 0, err
+	}
+	return requests, total, nil
+}
+
+// Transition applies a change to a locked service request, creating the work order it is converted into
+func (r *ServiceRequestRepositoryPostgres) Transition(id uint, apply func(request *domain.ServiceRequest) (*domain.WorkOrder, error)) (*domain.ServiceRequest, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var request domain.ServiceRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&request, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("service request not found")
+			}
+			return err
+		}
+
+		workOrder, err := apply(&request)
+		if err != nil {
+			return err
+		}
+		if workOrder != nil {
+			workOrder.ServiceRequestID = &request.ID
+			if err := createWorkOrder(tx, workOrder); err != nil {
+				return err
+			}
+			request.WorkOrderID = &workOrder.ID
+		}
+
+		return tx.Omit(clause.Associations).Save(&request).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// CreateArea creates a service area
+func (r *ServiceRequestRepositoryPostgres) CreateArea(area *domain.ServiceArea) error {
+	return r.db.Omit(clause.Associations).Create(area).Error
+}
+
+// GetArea retrieves a service area with its manager
+func (r *ServiceRequestRepositoryPostgres) GetArea(id uint) (*domain.ServiceArea, error) {
+	var area domain.ServiceArea
+	if err := r.db.Preload("Manager").First(&area, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("service area not found")
+		}
+		return nil, err
+	}
+	return &area, nil
+}
+
+// UpdateArea saves a service area
+func (r *ServiceRequestRepositoryPostgres) UpdateArea(area *domain.ServiceArea) error {
+	return r.db.Omit(clause.Associations).Save(area).Error
+}
+
+// ListAreas retrieves every service area ordered by name
+func (r *ServiceRequestRepositoryPostgres) ListAreas() ([]*domain.ServiceArea, error) {
+	var areas []*domain.ServiceArea
+	if err := r.db.Preload("Manager").Order("name").Find(&areas).Error; err != nil {
+		return nil, err
+	}
+	return areas, nil
+}
+
+// GetAreaIDsByManager retrieves the active areas managed by a user
+func (r *ServiceRequestRepositoryPostgres) GetAreaIDsByManager(managerID uint) ([]uint, error) {
+	ids := []uint{}
+	if err := r.db.Model(&domain.ServiceArea{}).Where("manager_id = ? AND is_active", managerID).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// AssignToArea moves vehicles and branches into an area
+func (r *ServiceRequestRepositoryPostgres) AssignToArea(areaID uint, vehicleIDs, branchIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(vehicleIDs) > 0 {
+			if err := tx.Model(&domain.Vehicle{}).Where("id IN ?", vehicleIDs).
+				Update("area_id", areaID).Error; err != nil {
+				return err
+			}
+		}
+		if len(branchIDs) > 0 {
+			if err := tx.Model(&domain.Warehouse{}).Where("id IN ?", branchIDs).
+				Update("area_id", areaID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetBranch retrieves a warehouse that work can be allocated to
+func (r *ServiceRequestRepositoryPostgres) GetBranch(id uint) (*domain.Warehouse, error) {
+	var branch domain.Warehouse
+	if err := r.db.First(&branch, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("branch not found")
+		}
+		return nil, err
+	}
+	return &branch, nil
+}
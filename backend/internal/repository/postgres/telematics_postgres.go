@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// telematicsColumns lists the columns written by bulk ingestion, in COPY order
+var telematicsColumns = []string{
+	"vehicle_id", "device_id", "latitude", "longitude", "speed", "heading", "altitude",
+	"engine_status", "fuel_level", "engine_temperature", "oil_pressure", "battery_voltage",
+	"engine_rpm", "total_distance", "fuel_consumption", "timestamp", "created_at",
+}
+
+const telematicsColumnList = `vehicle_id, device_id, latitude, longitude, speed, heading, altitude,
+	engine_status, fuel_level, engine_temperature, oil_pressure, battery_voltage,
+	engine_rpm, total_distance, fuel_consumption, "timestamp", created_at`
+
+// TelematicsRepositoryPostgres implements TelematicsRepository interface using PostgreSQL
+type TelematicsRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewTelematicsRepositoryPostgres creates a new PostgreSQL telematics repository
+func NewTelematicsRepositoryPostgres(db *gorm.DB) interfaces.TelematicsRepository {
+	return &TelematicsRepositoryPostgres{db: db}
+}
+
+// telematicsKey identifies a sample for deduplication (device and timestamp at microsecond precision)
+type telematicsKey struct {
+	deviceID  string
+	timestamp int64
+}
+
+// InsertBatch copies the samples into a transaction-scoped staging table and moves them into
+// telematics_data with ON CONFLICT DO NOTHING, so duplicates are skipped without failing the batch
+func (r *TelematicsRepositoryPostgres) InsertBatch(samples []*domain.TelematicsData) ([]bool, error) {
+	stored := make([]bool, len(samples))
+	if len(samples) == 0 {
+		return stored, nil
+	}
+
+	ctx := context.Background()
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	inserted := make(map[telematicsKey]bool, len(samples))
+	err = conn.Raw(func(driverConn interface{}) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("bulk insert requires the pgx driver")
+		}
+
+		tx, err := stdConn.Conn().Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		if _, err := tx.Exec(ctx, `CREATE TEMP TABLE telematics_staging ON COMMIT DROP AS
+			SELECT `+telematicsColumnList+` FROM telematics_data WITH NO DATA`); err != nil {
+			return fmt.Errorf("failed to create staging table: %w", err)
+		}
+
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"telematics_staging"}, telematicsColumns,
+			pgx.CopyFromSlice(len(samples), func(i int) ([]interface{}, error) {
+				s := samples[i]
+				return []interface{}{
+					s.VehicleID, s.DeviceID, s.Latitude, s.Longitude, s.Speed, s.Heading, s.Altitude,
+					s.EngineStatus, s.FuelLevel, s.EngineTemp, s.OilPressure, s.BatteryLevel,
+					s.EngineRPM, s.TotalDistance, s.FuelConsumption, s.Timestamp, s.CreatedAt,
+				}, nil
+			})); err != nil {
+			return fmt.Errorf("failed to copy telematics samples: %w", err)
+		}
+
+		rows, err := tx.Query(ctx, `INSERT INTO telematics_data (`+telematicsColumnList+`)
+			SELECT `+telematicsColumnList+` FROM telematics_staging
+			ON CONFLICT (device_id, "timestamp") DO NOTHING
+			RETURNING device_id, "timestamp"`)
+		if err != nil {
+			return fmt.Errorf("failed to insert telematics samples: %w", err)
+		}
+		for rows.Next() {
+			var deviceID string
+			var timestamp time.Time
+			if err := rows.Scan(&deviceID, &timestamp); err != nil {
+				rows.Close()
+				return err
+			}
+			inserted[telematicsKey{deviceID: deviceID, timestamp: timestamp.UnixMicro()}] = true
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		return tx.Commit(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the first occurrence of a key within the batch counts as stored
+	for i, s := range samples {
+		key := telematicsKey{deviceID: s.DeviceID, timestamp: s.Timestamp.UnixMicro()}
+		if inserted[key] {
+			stored[i] = true
+			delete(inserted, key)
+		}
+	}
+	return stored, nil
+}
+
+// GetByVehicle returns the samples of a vehicle in [from, to] ordered by timestamp
+func (r *TelematicsRepositoryPostgres) GetByVehicle(vehicleID uint, from, to time.Time) ([]*domain.TelematicsData, error) {
+	var samples []*domain.TelematicsData
+	if err := r.db.Where(`vehicle_id = ? AND "timestamp" BETWEEN ? AND ?`, vehicleID, from, to).
+		Order(`"timestamp"`).Find(&samples).Error; err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// GetLastBefore returns the newest sample of a vehicle before t
+func (r *TelematicsRepositoryPostgres) GetLastBefore(vehicleID uint, t time.Time) (*domain.TelematicsData, error) {
+	var sample domain.TelematicsData
+	if err := r.db.Where(`vehicle_id = ? AND "timestamp" < ?`, vehicleID, t).
+		Order(`"timestamp" DESC`).First(&sample).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("telematics sample not found")
+		}
+		return nil, err
+	}
+	return &sample, nil
+}
+
+// GetVehicleIDsInRange returns the vehicles with samples in [from, to]
+func (r *TelematicsRepositoryPostgres) GetVehicleIDsInRange(from, to time.Time) ([]uint, error) {
+	var vehicleIDs []uint
+	if err := r.db.Model(&domain.TelematicsData{}).
+		Where(`"timestamp" BETWEEN ? AND ?`, from, to).
+		Distinct("vehicle_id").Order("vehicle_id").
+		Pluck("vehicle_id", &vehicleIDs).Error; err != nil {
+		return nil, err
+	}
+	return vehicleIDs, nil
+}
+
+// QueueReprocess queues vehicles for a consumer, keeping the earliest time of those already queued
+func (r *TelematicsRepositoryPostgres) QueueReprocess(consumer string, earliest map[uint]time.Time) error {
+	if len(earliest) == 0 {
+		return nil
+	}
+	marks := make([]*domain.TelematicsReprocessMark, 0, len(earliest))
+	for vehicleID, t := range earliest {
+		marks = append(marks, &domain.TelematicsReprocessMark{Consumer: consumer, VehicleID: vehicleID, Earliest: t})
+	}
+	// A consistent order keeps concurrent upserts from deadlocking
+	sort.Slice(marks, func(i, j int) bool { return marks[i].VehicleID < marks[j].VehicleID })
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "consumer"}, {Name: "vehicle_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"earliest": gorm.Expr("LEAST(telematics_reprocess_queue.earliest, EXCLUDED.earliest)"),
+		}),
+	}).Create(&marks).Error
+}
+
+// TakeReprocess dequeues and returns every vehicle queued for a consumer
+func (r *TelematicsRepositoryPostgres) TakeReprocess(consumer string) (map[uint]time.Time, error) {
+	var marks []*domain.TelematicsReprocessMark
+	if err := r.db.Raw("DELETE FROM telematics_reprocess_queue WHERE consumer = ? RETURNING consumer, vehicle_id, earliest", consumer).
+		Scan(&marks).Error; err != nil {
+		return nil, err
+	}
+	earliest := make(map[uint]time.Time, len(marks))
+	for _, mark := range marks {
+		earliest[mark.VehicleID] = mark.Earliest
+	}
+	return earliest, nil
+}
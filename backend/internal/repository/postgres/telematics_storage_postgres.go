@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+const (
+	// telematicsPartitionLockNamespace scopes the advisory lock that serializes partition changes
+	telematicsPartitionLockNamespace = 39001
+	// telematicsPartitionPrefix names daily partitions, e.g. telematics_data_p20240131
+	telematicsPartitionPrefix = "telematics_data_p"
+	telematicsPartitionLayout = "20060102"
+)
+
+// telematicsAggregateTables maps resolutions to their aggregate table and bucket width
+var telematicsAggregateTables = map[string]struct {
+	table string
+	unit  string
+}{
+	domain.TelematicsResolutionMinute: {table: "telematics_aggregates_1m", unit: "minute"},
+	domain.TelematicsResolutionHour:   {table: "telematics_aggregates_1h", unit: "hour"},
+}
+
+// telematicsAggregateColumns lists the aggregate columns in the order of telematicsAggregateSelect
+var telematicsAggregateColumns = []string{
+	"sample_count", "latitude", "longitude", "avg_speed", "max_speed", "engine_on_samples",
+	"fuel_level", "engine_temperature", "max_engine_temperature", "oil_pressure",
+	"battery_voltage", "min_battery_voltage", "engine_rpm", "max_engine_rpm", "total_distance", "updated_at",
+}
+
+// telematicsAggregateSelect aggregates raw samples; positions ignore samples without a GPS fix
+const telematicsAggregateSelect = `COUNT(*),
+	COALESCE((array_agg(latitude ORDER BY "timestamp" DESC) FILTER (WHERE latitude <> 0 OR longitude <> 0))[1], 0),
+	COALESCE((array_agg(longitude ORDER BY "timestamp" DESC) FILTER (WHERE latitude <> 0 OR longitude <> 0))[1], 0),
+	COALESCE(ROUND(AVG(speed), 2), 0), COALESCE(MAX(speed), 0),
+	COUNT(*) FILTER (WHERE COALESCE(engine_status, '') NOT IN ('', 'off')),
+	ROUND(AVG(fuel_level) FILTER (WHERE fuel_level > 0), 2),
+	ROUND(AVG(engine_temperature), 2), MAX(engine_temperature), ROUND(AVG(oil_pressure), 2),
+	ROUND(AVG(battery_voltage), 2), MIN(battery_voltage),
+	ROUND(AVG(engine_rpm))::int, MAX(engine_rpm), MAX(total_distance), NOW()`
+
+// TelematicsStorageRepositoryPostgres implements TelematicsStorageRepository interface using PostgreSQL
+type TelematicsStorageRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewTelematicsStorageRepositoryPostgres creates a new PostgreSQL telematics storage repository
+func NewTelematicsStorageRepositoryPostgres(db *gorm.DB) interfaces.TelematicsStorageRepository {
+	return &TelematicsStorageRepositoryPostgres{db: db}
+}
+
+// ListPartitionDays returns the days that have a raw sample partition, oldest first
+func (r *TelematicsStorageRepositoryPostgres) ListPartitionDays() ([]time.Time, error) {
+	var names []string
+	if err := r.db.Raw(`SELECT c.relname FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'telematics_data'::regclass AND c.relname LIKE ?
+		ORDER BY c.relname`, telematicsPartitionPrefix+"%").Scan(&names).Error; err != nil {
+		return nil, err
+	}
+
+	days := make([]time.Time, 0, len(names))
+	for _, name := range names {
+		day, err := time.Parse(telematicsPartitionLayout, strings.TrimPrefix(name, telematicsPartitionPrefix))
+		if err != nil {
+			// Not a partition managed here
+			continue
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// CreatePartition creates the partition of a day. Samples of that day in the default partition
+// would block attaching it, so they are moved into the new partition first.
+func (r *TelematicsStorageRepositoryPostgres) CreatePartition(day time.Time) error {
+	name := telematicsPartitionName(day)
+	from, to := day.Format("2006-01-02"), day.AddDate(0, 0, 1).Format("2006-01-02")
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, 0)", telematicsPartitionLockNamespace).Error; err != nil {
+			return err
+		}
+		var exists bool
+		if err := tx.Raw("SELECT to_regclass(?) IS NOT NULL", name).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+
+		if err := tx.Exec(fmt.Sprintf(`CREATE TABLE %s (LIKE telematics_data INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`, name)).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(fmt.Sprintf(`WITH moved AS (
+				DELETE FROM telematics_data_default WHERE "timestamp" >= ? AND "timestamp" < ? RETURNING *
+			) INSERT INTO %s SELECT * FROM moved`, name), from, to).Error; err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf(`ALTER TABLE telematics_data ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`,
+			name, from, to)).Error
+	})
+}
+
+// DropPartition drops the partition of a day with its samples
+func (r *TelematicsStorageRepositoryPostgres) DropPartition(day time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, 0)", telematicsPartitionLockNamespace).Error; err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", telematicsPartitionName(day))).Error
+	})
+}
+
+// PurgeDefaultPartition deletes samples older than t from the default partition
+func (r *TelematicsStorageRepositoryPostgres) PurgeDefaultPartition(before time.Time) (int64, error) {
+	result := r.db.Exec(`DELETE FROM telematics_data_default WHERE "timestamp" < ?`, before)
+	return result.RowsAffected, result.Error
+}
+
+// EnqueueCompaction queues vehicle hours for aggregation
+func (r *TelematicsStorageRepositoryPostgres) EnqueueCompaction(tasks []*domain.TelematicsCompactionTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "vehicle_id"}, {Name: "hour"}},
+		DoUpdates: clause.AssignmentColumns([]string{"queued_at"}),
+	}).Create(&tasks).Error
+}
+
+// GetCompactionTasks returns up to limit queued vehicle hours, oldest hour first
+func (r *TelematicsStorageRepositoryPostgres) GetCompactionTasks(limit int) ([]*domain.TelematicsCompactionTask, error) {
+	var tasks []*domain.TelematicsCompactionTask
+	if err := r.db.Order("hour, vehicle_id").Limit(limit).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Compact recomputes the aggregates of a vehicle hour and dequeues it
+func (r *TelematicsStorageRepositoryPostgres) Compact(task *domain.TelematicsCompactionTask) error {
+	updates := make([]string, len(telematicsAggregateColumns))
+	for i, column := range telematicsAggregateColumns {
+		updates[i] = fmt.Sprintf("%s = EXCLUDED.%s", column, column)
+	}
+	end := task.Hour.Add(time.Hour)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, resolution := range []string{domain.TelematicsResolutionMinute, domain.TelematicsResolutionHour} {
+			target := telematicsAggregateTables[resolution]
+			if err := tx.Exec(fmt.Sprintf(`INSERT INTO %s (vehicle_id, bucket, %s)
+				SELECT vehicle_id, date_trunc('%s', "timestamp"), %s
+				FROM telematics_data
+				WHERE vehicle_id = ? AND "timestamp" >= ? AND "timestamp" < ?
+				GROUP BY vehicle_id, 2
+				ON CONFLICT (vehicle_id, bucket) DO UPDATE SET %s`,
+				target.table, strings.Join(telematicsAggregateColumns, ", "), target.unit,
+				telematicsAggregateSelect, strings.Join(updates, ", ")),
+				task.VehicleID, task.Hour, end).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("vehicle_id = ? AND hour = ? AND queued_at <= ?", task.VehicleID, task.Hour, task.QueuedAt).
+			Delete(&domain.TelematicsCompactionTask{}).Error
+	})
+}
+
+// OldestQueuedHour returns the oldest queued hour, nil when the queue is empty
+func (r *TelematicsStorageRepositoryPostgres) OldestQueuedHour() (*time.Time, error) {
+	var oldest *time.Time
+	if err := r.db.Model(&domain.TelematicsCompactionTask{}).Select("MIN(hour)").Row().Scan(&oldest); err != nil {
+		return nil, err
+	}
+	return oldest, nil
+}
+
+// GetAggregates returns the buckets of a vehicle at a resolution in [from, to], oldest first
+func (r *TelematicsStorageRepositoryPostgres) GetAggregates(resolution string, vehicleID uint, from, to time.Time) ([]*domain.TelematicsAggregate, error) {
+	target, ok := telematicsAggregateTables[resolution]
+	if !ok {
+		return nil, fmt.Errorf("unknown telematics resolution %q", resolution)
+	}
+
+	var aggregates []*domain.TelematicsAggregate
+	if err := r.db.Table(target.table).
+		Where("vehicle_id = ? AND bucket BETWEEN ? AND ?", vehicleID, from, to).
+		Order("bucket").Find(&aggregates).Error; err != nil {
+		return nil, err
+	}
+	return aggregates, nil
+}
+
+// DeleteAggregatesBefore deletes buckets at a resolution starting before t
+func (r *TelematicsStorageRepositoryPostgres) DeleteAggregatesBefore(resolution string, before time.Time) (int64, error) {
+	target, ok := telematicsAggregateTables[resolution]
+	if !ok {
+		return 0, fmt.Errorf("unknown telematics resolution %q", resolution)
+	}
+	result := r.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE bucket < ?", target.table), before)
+	return result.RowsAffected, result.Error
+}
+
+// telematicsPartitionName returns the partition table name of a day
+func telematicsPartitionName(day time.Time) string {
+	return telematicsPartitionPrefix + day.Format(telematicsPartitionLayout)
+}
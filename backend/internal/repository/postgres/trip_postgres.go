@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// tripLockNamespace scopes the advisory locks that serialize trip replacement per vehicle
+const tripLockNamespace = 34001
+
+// TripRepositoryPostgres implements TripRepository interface using PostgreSQL
+type TripRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewTripRepositoryPostgres creates a new PostgreSQL trip repository
+func NewTripRepositoryPostgres(db *gorm.DB) interfaces.TripRepository {
+	return &TripRepositoryPostgres{db: db}
+}
+
+// GetByID retrieves a trip by ID
+func (r *TripRepositoryPostgres) GetByID(id uint) (*domain.Trip, error) {
+	var trip domain.Trip
+	if err := r.db.First(&trip, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("trip not found")
+		}
+		return nil, err
+	}
+	return &trip, nil
+}
+
+// ListByVehicle lists the trips of a vehicle that start in [from, to], newest first
+func (r *TripRepositoryPostgres) ListByVehicle(vehicleID uint, from, to time.Time, offset, limit int) ([]*domain.Trip, int64, error) {
+	query := r.db.Model(&domain.Trip{}).
+		Where("vehicle_id = ? AND start_time BETWEEN ? AND ?", vehicleID, from, to)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var trips []*domain.Trip
+	if err := query.Order("start_time DESC").Offset(offset).Limit(limit).Find(&trips).Error; err != nil {
+		return nil, 0, err
+	}
+	return trips, total, nil
+}
+
+// Resegment reads, re-segments and replaces the trips of a vehicle in one transaction under the vehicle's trip lock
+func (r *TripRepositoryPostgres) Resegment(vehicleID uint, from, to time.Time, segment func(existing []*domain.Trip) (*interfaces.TripSegment, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", tripLockNamespace, vehicleID).Error; err != nil {
+			return err
+		}
+		var existing []*domain.Trip
+		if err := tx.Where("vehicle_id = ? AND start_time <= ? AND end_time >= ?", vehicleID, to, from).
+			Order("start_time").Find(&existing).Error; err != nil {
+			return err
+		}
+		result, err := segment(existing)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("vehicle_id = ? AND start_time <= ? AND end_time >= ?", vehicleID, result.To, result.From).
+			Delete(&domain.Trip{}).Error; err != nil {
+			return err
+		}
+		if len(result.Trips) == 0 {
+			return nil
+		}
+		return tx.Create(&result.Trips).Error
+	})
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// VehicleRepositoryPostgres implements VehicleRepository interface using PostgreSQL
+type VehicleRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewVehicleRepositoryPostgres creates a new PostgreSQL vehicle repository
+func NewVehicleRepositoryPostgres(db *gorm.DB) interfaces.VehicleRepository {
+	return &VehicleRepositoryPostgres{db: db}
+}
+
+// Create creates a new vehicle
+func (r *VehicleRepositoryPostgres) Create(vehicle *domain.Vehicle) error {
+	return r.db.Create(vehicle).Error
+}
+
+// GetByID retrieves a vehicle by ID
+func (r *VehicleRepositoryPostgres) GetByID(id uint) (*domain.Vehicle, error) {
+	var vehicle domain.Vehicle
+	if err := r.db.First(&vehicle, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("vehicle not found")
+		}
+		return nil, err
+	}
+	return &vehicle, nil
+}
+
+// GetByPlateNumber retrieves a vehicle by plate number
+func (r *VehicleRepositoryPostgres) GetByPlateNumber(plateNumber string) (*domain.Vehicle, error) {
+	var vehicle domain.Vehicle
+	if err := r.db.Where("plate_number = ?", plateNumber).First(&vehicle).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("vehicle not found")
+		}
+		return nil, err
+	}
+	return &vehicle, nil
+}
+
+// GetByIDs retrieves the vehicles with the given IDs; missing IDs are skipped
+func (r *VehicleRepositoryPostgres) GetByIDs(ids []uint) ([]*domain.Vehicle, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var vehicles []*domain.Vehicle
+	if err := r.db.Where("id IN ?", ids).Order("id").Find(&vehicles).Error; err != nil {
+		return nil, err
+	}
+	return vehicles, nil
+}
+
+// Update updates a vehicle
+func (r *VehicleRepositoryPostgres) Update(vehicle *domain.Vehicle) error {
+	return r.db.Save(vehicle).Error
+}
+
+// Delete deletes a vehicle
+func (r *VehicleRepositoryPostgres) Delete(id uint) error {
+	return r.db.Delete(&domain.Vehicle{}, id).Error
+}
+
+// CreateBatch inserts vehicles in a single transaction so that either all or none are stored
+func (r *VehicleRepositoryPostgres) CreateBatch(vehicles []*domain.Vehicle) error {
+	if len(vehicles) == 0 {
+		return nil
+	}
+	var withVIN, withoutVIN []*domain.Vehicle
+	for _, vehicle := range vehicles {
+		if vehicle.VIN == "" {
+			withoutVIN = append(withoutVIN, vehicle)
+		} else {
+			withVIN = append(withVIN, vehicle)
+		}
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Imported vehicles carry no service history; leave the dates NULL.
+		// A missing VIN must be stored as NULL, not '', to satisfy the unique constraint.
+		if len(withVIN) > 0 {
+			if err := tx.Omit("LastService", "NextService").CreateInBatches(withVIN, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(withoutVIN) > 0 {
+			if err := tx.Omit("LastService", "NextService", "VIN").CreateInBatches(withoutVIN, 500).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetExistingPlateNumbers returns the given plate numbers that are already registered (case-insensitive)
+func (r *VehicleRepositoryPostgres) GetExistingPlateNumbers(plateNumbers []string) ([]string, error) {
+	var existing []string
+	if len(plateNumbers) == 0 {
+		return existing, nil
+	}
+	if err := r.db.Model(&domain.Vehicle{}).
+		Where("UPPER(plate_number) IN ?", plateNumbers).
+		Pluck("UPPER(plate_number)", &existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// GetExistingVINs returns the given VINs that are already registered (case-insensitive)
+func (r *VehicleRepositoryPostgres) GetExistingVINs(vins []string) ([]string, error) {
+	var existing []string
+	if len(vins) == 0 {
+		return existing, nil
+	}
+	if err := r.db.Model(&domain.Vehicle{}).
+		Where("UPPER(vin) IN ?", vins).
+		Pluck("UPPER(vin)", &existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// FindInBatches walks all vehicles matching the filter ordered by ID, passing each batch to fn
+func (r *VehicleRepositoryPostgres) FindInBatches(filter interfaces.VehicleFilter, batchSize int, fn func(vehicles []*domain.Vehicle) error) error {
+	query := r.db.Model(&domain.Vehicle{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Make != "" {
+		query = query.Where("make ILIKE ?", filter.Make)
+	}
+	if filter.Search != "" {
+		searchQuery := fmt.Sprintf("%%%s%%", filter.Search)
+		query = query.Where("plate_number ILIKE ? OR vin ILIKE ? OR make ILIKE ? OR model ILIKE ?",
+			searchQuery, searchQuery, searchQuery, searchQuery)
+	}
+
+	var batch []*domain.Vehicle
+	return query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// GetByAssignee retrieves vehicles whose assigned_to matches any of the given values, ignoring case
+func (r *VehicleRepositoryPostgres) GetByAssignee(assignees []string) ([]*domain.Vehicle, error) {
+	lowered := make([]string, 0, len(assignees))
+	for _, assignee := range assignees {
+		if assignee = strings.ToLower(strings.TrimSpace(assignee)); assignee != "" {
+			lowered = append(lowered, assignee)
+		}
+	}
+	if len(lowered) == 0 {
+		return nil, nil
+	}
+
+	var vehicles []*domain.Vehicle
+	if err := r.db.Where("LOWER(TRIM(assigned_to)) IN ?", lowered).Order("id").Find(&vehicles).Error; err != nil {
+		return nil, err
+	}
+	return vehicles, nil
+}
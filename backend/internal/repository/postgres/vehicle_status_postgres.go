@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// VehicleStatusRepositoryPostgres implements VehicleStatusRepository interface using PostgreSQL
+type VehicleStatusRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewVehicleStatusRepositoryPostgres creates a new PostgreSQL vehicle status repository
+func NewVehicleStatusRepositoryPostgres(db *gorm.DB) interfaces.VehicleStatusRepository {
+	return &VehicleStatusRepositoryPostgres{db: db}
+}
+
+// UpsertLatestStates inserts or replaces states; out-of-order samples never overwrite newer state
+func (r *VehicleStatusRepositoryPostgres) UpsertLatestStates(states []*domain.VehicleLatestState) error {
+	if len(states) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "vehicle_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"device_id", "latitude", "longitude", "speed", "heading", "engine_status",
+			"fuel_level", "battery_voltage", "engine_rpm", "total_distance", "sample_at", "updated_at",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "vehicle_latest_states.sample_at < excluded.sample_at"},
+		}},
+	}).Create(&states).Error
+}
+
+// GetLatestState retrieves the latest state of a vehicle
+func (r *VehicleStatusRepositoryPostgres) GetLatestState(vehicleID uint) (*domain.VehicleLatestState, error) {
+	var state domain.VehicleLatestState
+	if err := r.db.Where("vehicle_id = ?", vehicleID).First(&state).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("vehicle state not found")
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+// ListLatestStates retrieves the latest states of the given vehicles, or of all vehicles when vehicleIDs is nil
+func (r *VehicleStatusRepositoryPostgres) ListLatestStates(vehicleIDs []uint) ([]*domain.VehicleLatestState, error) {
+	query := r.db.Order("vehicle_id")
+	if vehicleIDs != nil {
+		if len(vehicleIDs) == 0 {
+			return nil, nil
+		}
+		query = query.Where("vehicle_id IN ?", vehicleIDs)
+	}
+
+	var states []*domain.VehicleLatestState
+	if err := query.Find(&states).Error; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// WorkOrderRepositoryPostgres implements WorkOrderRepository interface using PostgreSQL
+type WorkOrderRepositoryPostgres struct {
+	db *gorm.DB
+}
+
+// NewWorkOrderRepositoryPostgres creates a new PostgreSQL work order repository
+func NewWorkOrderRepositoryPostgres(db *gorm.DB) interfaces.WorkOrderRepository {
+	return &WorkOrderRepositoryPostgres{db: db}
+}
+
+// Create creates a new work order
+// The work order number is generated by a database trigger and loaded back after insert.
+// The initial status history entry is attributed to the service advisor.
+func (r *WorkOrderRepositoryPostgres) Create(workOrder *domain.WorkOrder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createWorkOrder(tx, workOrder)
+	})
+}
+
+// createWorkOrder inserts a work order and its initial status history entry within a transaction
+func createWorkOrder(tx *gorm.DB, workOrder *domain.WorkOrder) error {
+	if err := tx.Omit(clause.Associations).Create(workOrder).Error; err != nil {
+		return err
+	}
+	entry := &domain.WorkOrderStatusHistory{
+		WorkOrderID: workOrder.ID,
+		NewStatus:   workOrder.Status,
+		ChangedBy:   workOrder.ServiceAdvisorID,
+		Notes:       "Work order created",
+		ChangedAt:   time.Now().UTC(),
+	}
+	if err := tx.Omit(clause.Associations).Create(entry).Error; err != nil {
+		return err
+	}
+	return tx.Raw("SELECT wo_number FROM work_orders WHERE id = ?", workOrder.ID).
+		Scan(&workOrder.WONumber).Error
+}
+
+// GetByID retrieves a work order by ID
+func (r *WorkOrderRepositoryPostgres) GetByID(id uint) (*domain.WorkOrder, error) {
+	var workOrder domain.WorkOrder
+	if err := r.db.Preload("Vehicle").Preload("AssignedMechanic").Preload("ServiceAdvisor").
+		First(&workOrder, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("work order not found")
+		}
+		return nil, err
+	}
+	return &workOrder, nil
+}
+
+// Update saves the descriptive fields of a work order. Status, assignment and the
+// start and completion dates only change through Transition.
+func (r *WorkOrderRepositoryPostgres) Update(workOrder *domain.WorkOrder) error {
+	result := r.db.Model(&domain.WorkOrder{}).Where("id = ?", workOrder.ID).Updates(map[string]interface{}{
+		"customer_name":   workOrder.CustomerName,
+		"customer_phone":  workOrder.CustomerPhone,
+		"customer_email":  workOrder.CustomerEmail,
+		"service_type":    workOrder.ServiceType,
+		"priority":        workOrder.Priority,
+		"description":     workOrder.Description,
+		"symptoms":        workOrder.Symptoms,
+		"diagnosis":       workOrder.Diagnosis,
+		"estimated_cost":  workOrder.EstimatedCost,
+		"estimated_hours": workOrder.EstimatedHours,
+		"notes":           workOrder.Notes,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("work order not found")
+	}
+	return nil
+}
+
+// Delete deletes a work order; its parts, labor, photos and history are removed with it
+func (r *WorkOrderRepositoryPostgres) Delete(id uint) error {
+	result := r.db.Delete(&domain.WorkOrder{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("work order not found")
+	}
+	return nil
+}
+
+// List retrieves a page of work orders, most urgent first, then newest first
+func (r *WorkOrderRepositoryPostgres) List(filter interfaces.WorkOrderFilter, offset, limit int) ([]*domain.WorkOrder, int64, error) {
+	query := r.db.Model(&domain.WorkOrder{})
+	if filter.VehicleID != 0 {
+		query = query.Where("vehicle_id = ?", filter.VehicleID)
+	}
+	if filter.VehicleIDs != nil {
+		if len(filter.VehicleIDs) == 0 {
+			return []*domain.WorkOrder{}, 0, nil
+		}
+		query = query.Where("vehicle_id IN ?", filter.VehicleIDs)
+	}
+	if filter.MechanicID != 0 {
+		query = query.Where("assigned_mechanic_id = ?", filter.MechanicID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Priority != "" {
+		query = query.Where("priority = ?", filter.Priority)
+	}
+	if filter.ServiceType != "" {
+		query = query.Where("service_type = ?", filter.ServiceType)
+	}
+	if filter.Search != "" {
+		pattern := "%" + filter.Search + "%"
+		query = query.Where("wo_number ILIKE ? OR customer_name ILIKE ?", pattern, pattern)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var workOrders []*domain.WorkOrder
+	if err := query.Preload("Vehicle").Preload("AssignedMechanic").
+		Order(`CASE priority WHEN 'emergency' THEN 0 WHEN 'critical' THEN 1 WHEN 'high' THEN 2
+			WHEN 'normal' THEN 3 ELSE 4 END`).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).Find(&workOrders).Error; err != nil {
+		return nil, 0, err
+	}
+	return workOrders, total, nil
+}
+
+// Transition applies a status change to a locked work order and records it in the history
+func (r *WorkOrderRepositoryPostgres) Transition(id uint, apply func(workOrder *domain.WorkOrder) (*domain.WorkOrderStatusHistory, error)) (*domain.WorkOrder, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var workOrder domain.WorkOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&workOrder, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("work order not found")
+			}
+			return err
+		}
+
+		entry, err := apply(&workOrder)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&workOrder).Updates(map[string]interface{}{
+			"status":               workOrder.Status,
+			"assigned_mechanic_id": workOrder.AssignedMechanicID,
+			"start_date":           workOrder.StartDate,
+			"completion_date":      workOrder.CompletionDate,
+			"actual_cost":          workOrder.ActualCost,
+			"actual_hours":         workOrder.ActualHours,
+			"diagnosis":            workOrder.Diagnosis,
+		}).Error; err != nil {
+			return err
+		}
+
+		entry.WorkOrderID = workOrder.ID
+		return tx.Omit(clause.Associations).Create(entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// GetStatusHistory retrieves the status changes of a work order, oldest first
+func (r *WorkOrderRepositoryPostgres) GetStatusHistory(workOrderID uint) ([]*domain.WorkOrderStatusHistory, error) {
+	var history []*domain.WorkOrderStatusHistory
+	if err := r.db.Preload("ChangedByUser").Where("work_order_id = ?", workOrderID).
+		Order("changed_at, id").Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// ListOpenByMechanics retrieves the assigned, in progress and paused work orders of the mechanics
+func (r *WorkOrderRepositoryPostgres) ListOpenByMechanics(mechanicIDs []uint) ([]*domain.WorkOrder, error) {
+	workOrders := []*domain.WorkOrder{}
+	if len(mechanicIDs) == 0 {
+		return workOrders, nil
+	}
+	if err := r.db.Where("assigned_mechanic_id IN ? AND status IN ?", mechanicIDs, []string{
+		domain.StatusAssigned, domain.StatusInProgress, domain.StatusOnHold, domain.StatusWaitingForParts,
+	}).Order("created_at").Find(&workOrders).Error; err != nil {
+		return nil, err
+	}
+	return workOrders, nil
+}
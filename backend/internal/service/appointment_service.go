@@ -0,0 +1,992 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Workshop appointment errors
+var (
+	ErrWorkshopBayNotFound = errors.New("workshop bay not found")
+	ErrWorkshopBayExists   = errors.New("a bay with this name already exists at the branch")
+	ErrAppointmentNotFound = errors.New("appointment not found")
+	ErrAppointmentExists   = errors.New("work order already has a scheduled appointment")
+	ErrAppointmentClosed   = errors.New("appointment is no longer scheduled")
+	ErrAppointmentConflict = errors.New("appointment conflicts with the workshop calendar")
+)
+
+const (
+	// maxAppointmentHours bounds how long a work order can hold a bay
+	maxAppointmentHours = 14 * 24
+	// maxCalendarRange bounds the appointments listed per calendar query
+	maxCalendarRange = 31 * 24 * time.Hour
+	// appointmentHorizon is how far ahead scheduled appointments keep a bay in use
+	appointmentHorizon = 5 * 366 * 24 * time.Hour
+)
+
+// Appointment conflict type constants
+const (
+	ConflictBayBooked           = "bay_booked"
+	ConflictMechanicBooked      = "mechanic_booked"
+	ConflictMechanicOffShift    = "mechanic_off_shift"
+	ConflictOutsideOpeningHours = "outside_opening_hours"
+)
+
+// AppointmentConfig sets the hours of the day, in UTC, bays can be booked
+type AppointmentConfig struct {
+	OpeningHour int
+	ClosingHour int
+}
+
+// WorkshopBayRequest represents a bay or lift; updates replace the whole bay
+type WorkshopBayRequest struct {
+	BranchID     uint     `json:"branch_id" validate:"required"`
+	Name         string   `json:"name" validate:"required,max=50"`
+	BayType      string   `json:"bay_type" validate:"omitempty,oneof=bay lift"` // defaults to bay
+	VehicleTypes []string `json:"vehicle_types" validate:"max=20,dive,max=50"`  // empty accepts every vehicle type
+	Notes        string   `json:"notes" validate:"max=1000"`
+	IsActive     *bool    `json:"is_active"`
+}
+
+// AppointmentRequest books a work order on a bay. The duration defaults to the remaining
+// estimated hours of the work order.
+type AppointmentRequest struct {
+	BayID         uint      `json:"bay_id" validate:"required"`
+	StartsAt      time.Time `json:"starts_at" validate:"required"`
+	DurationHours float64   `json:"duration_hours" validate:"min=0,max=336"`
+	Notes         string    `json:"notes" validate:"max=1000"`
+}
+
+// MoveAppointmentRequest moves an appointment; the bay and duration default to the current ones
+type MoveAppointmentRequest struct {
+	BayID         uint      `json:"bay_id"`
+	StartsAt      time.Time `json:"starts_at" validate:"required"`
+	DurationHours float64   `json:"duration_hours" validate:"min=0,max=336"`
+	Reason        string    `json:"reason" validate:"max=500"`
+}
+
+// CancelAppointmentRequest cancels an appointment
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// AppointmentConflict is a reason a period cannot be booked
+type AppointmentConflict struct {
+	Type          string     `json:"type"` // bay_booked, mechanic_booked, mechanic_off_shift, outside_opening_hours
+	Message       string     `json:"message"`
+	AppointmentID *uint      `json:"appointment_id,omitempty"`
+	WorkOrderID   *uint      `json:"work_order_id,omitempty"`
+	From          *time.Time `json:"from,omitempty"`
+	To            *time.Time `json:"to,omitempty"`
+}
+
+// AppointmentCheck reports whether a work order can be booked for a period
+type AppointmentCheck struct {
+	BayID      uint                   `json:"bay_id"`
+	MechanicID *uint                  `json:"mechanic_id"`
+	StartsAt   time.Time              `json:"starts_at"`
+	EndsAt     time.Time              `json:"ends_at"`
+	Available  bool                   `json:"available"`
+	Conflicts  []*AppointmentConflict `json:"conflicts"`
+}
+
+// CapacityDay summarises the booked and available hours of a branch on one day
+type CapacityDay struct {
+	Date                string  `json:"date"`
+	Bays                int     `json:"bays"`
+	BayHours            float64 `json:"bay_hours"` // opening hours times active bays
+	BookedBayHours      float64 `json:"booked_bay_hours"`
+	BayUtilisation      float64 `json:"bay_utilisation"`
+	MechanicHours       float64 `json:"mechanic_hours"` // shift hours within opening hours, capped at the daily capacity
+	BookedMechanicHours float64 `json:"booked_mechanic_hours"`
+	MechanicUtilisation float64 `json:"mechanic_utilisation"`
+	Appointments        int     `json:"appointments"`
+}
+
+// BayCalendar lists the appointments of a bay in the capacity range
+type BayCalendar struct {
+	Bay          *domain.WorkshopBay            `json:"bay"`
+	BookedHours  float64                        `json:"booked_hours"`
+	Appointments []*domain.WorkOrderAppointment `json:"appointments"`
+}
+
+// MechanicCalendar lists the shifts and appointments of a mechanic in the capacity range
+type MechanicCalendar struct {
+	MechanicID   uint                           `json:"mechanic_id"`
+	Name         string                         `json:"name"`
+	ShiftHours   float64                        `json:"shift_hours"`
+	BookedHours  float64                        `json:"booked_hours"`
+	Shifts       []*domain.MechanicShift        `json:"shifts"`
+	Appointments []*domain.WorkOrderAppointment `json:"appointments"`
+}
+
+// WorkshopCapacity is the day or week view of a branch's bays and mechanics
+type WorkshopCapacity struct {
+	BranchID    uint                `json:"branch_id"`
+	From        time.Time           `json:"from"`
+	To          time.Time           `json:"to"`
+	OpeningHour int                 `json:"opening_hour"`
+	ClosingHour int                 `json:"closing_hour"`
+	Days        []*CapacityDay      `json:"days"`
+	Bays        []*BayCalendar      `json:"bays"`
+	Mechanics   []*MechanicCalendar `json:"mechanics"`
+}
+
+// AppointmentService books work orders on workshop bays. An appointment holds a bay and the
+// mechanic assigned to the work order for a period within opening hours; bookings that
+// double book a bay or mechanic, or fall outside the mechanic's shifts, are rejected.
+// Moving an appointment, or reassigning its work order, moves it on the mechanic's calendar.
+type AppointmentService struct {
+	appointmentRepo     interfaces.AppointmentRepository
+	mechanicRepo        interfaces.MechanicRepository
+	serviceRequestRepo  interfaces.ServiceRequestRepository
+	workOrderService    *WorkOrderService
+	mechanicService     *MechanicService
+	notificationService *NotificationService
+	config              AppointmentConfig
+	validator           *validator.Validate
+	logger              *logrus.Logger
+}
+
+// NewAppointmentService creates a new appointment service
+func NewAppointmentService(
+	appointmentRepo interfaces.AppointmentRepository,
+	mechanicRepo interfaces.MechanicRepository,
+	serviceRequestRepo interfaces.ServiceRequestRepository,
+	workOrderService *WorkOrderService,
+	mechanicService *MechanicService,
+	notificationService *NotificationService,
+	config AppointmentConfig,
+	logger *logrus.Logger,
+) *AppointmentService {
+	if config.OpeningHour < 0 || config.ClosingHour > 24 || config.OpeningHour >= config.ClosingHour {
+		config.OpeningHour, config.ClosingHour = 8, 18
+	}
+	return &AppointmentService{
+		appointmentRepo:     appointmentRepo,
+		mechanicRepo:        mechanicRepo,
+		serviceRequestRepo:  serviceRequestRepo,
+		workOrderService:    workOrderService,
+		mechanicService:     mechanicService,
+		notificationService: notificationService,
+		config:              config,
+		validator:           validator.New(),
+		logger:              logger,
+	}
+}
+
+// CreateBay adds a bay or lift to a branch
+func (s *AppointmentService) CreateBay(req *WorkshopBayRequest) (*domain.WorkshopBay, error) {
+	bay := &domain.WorkshopBay{IsActive: true}
+	if err := s.applyBayRequest(bay, req); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepo.CreateBay(bay); err != nil {
+		return nil, fmt.Errorf("failed to create workshop bay: %w", err)
+	}
+	return bay, nil
+}
+
+// GetBay retrieves a workshop bay
+func (s *AppointmentService) GetBay(id uint) (*domain.WorkshopBay, error) {
+	bay, err := s.appointmentRepo.GetBay(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrWorkshopBayNotFound
+		}
+		return nil, fmt.Errorf("failed to get workshop bay: %w", err)
+	}
+	return bay, nil
+}
+
+// ListBays retrieves the workshop bays, optionally of one branch
+func (s *AppointmentService) ListBays(branchID uint, activeOnly bool) ([]*domain.WorkshopBay, error) {
+	bays, err := s.appointmentRepo.ListBays(branchID, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workshop bays: %w", err)
+	}
+	return bays, nil
+}
+
+// UpdateBay replaces a workshop bay. A bay with scheduled appointments ahead cannot move to
+// another branch or be deactivated until they are moved.
+func (s *AppointmentService) UpdateBay(id uint, req *WorkshopBayRequest) (*domain.WorkshopBay, error) {
+	bay, err := s.GetBay(id)
+	if err != nil {
+		return nil, err
+	}
+	previous := *bay
+	if err := s.applyBayRequest(bay, req); err != nil {
+		return nil, err
+	}
+
+	if bay.BranchID != previous.BranchID || (previous.IsActive && !bay.IsActive) {
+		now := time.Now().UTC()
+		ahead, err := s.appointmentRepo.List(interfaces.AppointmentFilter{
+			From:   now,
+			To:     now.Add(appointmentHorizon),
+			BayID:  bay.ID,
+			Status: domain.AppointmentStatusScheduled,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get appointments: %w", err)
+		}
+		if len(ahead) > 0 {
+			return nil, fmt.Errorf("validation failed: %s has %d scheduled appointments, move them first", previous.Name, len(ahead))
+		}
+	}
+
+	if err := s.appointmentRepo.UpdateBay(bay); err != nil {
+		if isNotFound(err) {
+			return nil, ErrWorkshopBayNotFound
+		}
+		return nil, fmt.Errorf("failed to update workshop bay: %w", err)
+	}
+	return bay, nil
+}
+
+// Get retrieves an appointment of a work order the viewer can access
+func (s *AppointmentService) Get(viewer Viewer, id uint) (*domain.WorkOrderAppointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get appointment: %w", err)
+	}
+	if _, err := s.workOrderService.Get(viewer, appointment.WorkOrderID); err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
+
+// ListByWorkOrder retrieves the appointments of a work order, latest first
+func (s *AppointmentService) ListByWorkOrder(viewer Viewer, workOrderID uint) ([]*domain.WorkOrderAppointment, error) {
+	if _, err := s.workOrderService.Get(viewer, workOrderID); err != nil {
+		return nil, err
+	}
+	appointments, err := s.appointmentRepo.ListByWorkOrder(workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+	return appointments, nil
+}
+
+// List retrieves the appointments overlapping a range. Mechanics only see their own.
+func (s *AppointmentService) List(viewer Viewer, filter interfaces.AppointmentFilter) ([]*domain.WorkOrderAppointment, error) {
+	if !filter.To.After(filter.From) {
+		return nil, ErrInvalidTimeRange
+	}
+	if filter.To.Sub(filter.From) > maxCalendarRange {
+		return nil, fmt.Errorf("validation failed: time range cannot exceed %d days", int(maxCalendarRange.Hours()/24))
+	}
+	if viewer.Role == domain.RoleMechanic {
+		filter.MechanicID = viewer.UserID
+	}
+	appointments, err := s.appointmentRepo.List(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+	return appointments, nil
+}
+
+// Check reports whether a work order can be booked as requested, without booking it. The work
+// order's own scheduled appointment does not conflict, so moves can be checked too.
+func (s *AppointmentService) Check(viewer Viewer, workOrderID uint, req *AppointmentRequest) (*AppointmentCheck, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	bay, err := s.bookableBay(req.BayID, workOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment := &domain.WorkOrderAppointment{
+		WorkOrderID: workOrder.ID,
+		BayID:       bay.ID,
+		MechanicID:  workOrder.AssignedMechanicID,
+	}
+	if existing, err := s.appointmentRepo.GetScheduled(workOrder.ID); err == nil {
+		appointment.ID = existing.ID
+	} else if !isNotFound(err) {
+		return nil, fmt.Errorf("failed to get appointment: %w", err)
+	}
+	if err := s.setPeriod(appointment, req.StartsAt, appointmentDuration(req.DurationHours, workOrder)); err != nil {
+		return nil, err
+	}
+
+	conflicts, err := s.conflicts(appointment)
+	if err != nil {
+		return nil, err
+	}
+	return &AppointmentCheck{
+		BayID:      appointment.BayID,
+		MechanicID: appointment.MechanicID,
+		StartsAt:   appointment.StartsAt,
+		EndsAt:     appointment.EndsAt,
+		Available:  len(conflicts) == 0,
+		Conflicts:  conflicts,
+	}, nil
+}
+
+// Book books an open work order on a bay with its assigned mechanic
+func (s *AppointmentService) Book(viewer Viewer, workOrderID uint, req *AppointmentRequest) (*domain.WorkOrderAppointment, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+	bay, err := s.bookableBay(req.BayID, workOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment := &domain.WorkOrderAppointment{
+		WorkOrderID: workOrder.ID,
+		BranchID:    bay.BranchID,
+		BayID:       bay.ID,
+		MechanicID:  workOrder.AssignedMechanicID,
+		Status:      domain.AppointmentStatusScheduled,
+		Notes:       req.Notes,
+		CreatedBy:   viewer.UserID,
+	}
+	if err := s.setPeriod(appointment, req.StartsAt, appointmentDuration(req.DurationHours, workOrder)); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.appointmentRepo.GetScheduled(workOrder.ID); err == nil {
+		return nil, ErrAppointmentExists
+	} else if !isNotFound(err) {
+		return nil, fmt.Errorf("failed to get appointment: %w", err)
+	}
+	if err := s.checkConflicts(appointment); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepo.Create(appointment); err != nil {
+		return nil, bookingError(err, "failed to create appointment")
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"appointment_id": appointment.ID,
+		"work_order_id":  workOrder.ID,
+		"bay_id":         bay.ID,
+		"starts_at":      appointment.StartsAt,
+	}).Info("Work order appointment booked")
+	if appointment.MechanicID != nil {
+		s.notifyMechanic(*appointment.MechanicID, workOrder, "appointment_scheduled", "Work booked",
+			fmt.Sprintf("Work order %s is booked on %s %s.", workOrder.WONumber, bay.Name, describePeriod(appointment.StartsAt, appointment.EndsAt)))
+	}
+	return s.appointmentRepo.GetByID(appointment.ID)
+}
+
+// Move moves a scheduled appointment to another period or bay, and with it the mechanic's booking
+func (s *AppointmentService) Move(viewer Viewer, id uint, req *MoveAppointmentRequest) (*domain.WorkOrderAppointment, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	appointment, err := s.Get(viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != domain.AppointmentStatusScheduled {
+		return nil, ErrAppointmentClosed
+	}
+	workOrder, err := s.workOrderService.Get(viewer, appointment.WorkOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	bayID := req.BayID
+	if bayID == 0 {
+		bayID = appointment.BayID
+	}
+	bay, err := s.bookableBay(bayID, workOrder)
+	if err != nil {
+		return nil, err
+	}
+	duration := appointment.EndsAt.Sub(appointment.StartsAt)
+	if req.DurationHours > 0 {
+		duration = hoursDuration(req.DurationHours)
+	}
+
+	previous := *appointment
+	appointment.BranchID = bay.BranchID
+	appointment.BayID = bay.ID
+	appointment.Bay = bay
+	appointment.MechanicID = workOrder.AssignedMechanicID
+	if err := s.setPeriod(appointment, req.StartsAt, duration); err != nil {
+		return nil, err
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		appointment.Notes = strings.TrimSpace(fmt.Sprintf("%s\nMoved from %s: %s", appointment.Notes,
+			describePeriod(previous.StartsAt, previous.EndsAt), reason))
+	}
+
+	if err := s.checkConflicts(appointment); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepo.Update(appointment); err != nil {
+		if isNotFound(err) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, bookingError(err, "failed to update appointment")
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"appointment_id": appointment.ID,
+		"work_order_id":  workOrder.ID,
+		"bay_id":         bay.ID,
+		"starts_at":      appointment.StartsAt,
+	}).Info("Work order appointment moved")
+	if appointment.MechanicID != nil {
+		s.notifyMechanic(*appointment.MechanicID, workOrder, "appointment_moved", "Work rescheduled",
+			fmt.Sprintf("Work order %s moved from %s to %s on %s.", workOrder.WONumber,
+				describePeriod(previous.StartsAt, previous.EndsAt), describePeriod(appointment.StartsAt, appointment.EndsAt), bay.Name))
+	}
+	return s.appointmentRepo.GetByID(appointment.ID)
+}
+
+// Cancel cancels a scheduled appointment, freeing the bay and the mechanic
+func (s *AppointmentService) Cancel(viewer Viewer, id uint, req *CancelAppointmentRequest) (*domain.WorkOrderAppointment, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	appointment, err := s.Get(viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != domain.AppointmentStatusScheduled {
+		return nil, ErrAppointmentClosed
+	}
+	workOrder, err := s.workOrderService.Get(viewer, appointment.WorkOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment.Status = domain.AppointmentStatusCancelled
+	appointment.CancelReason = req.Reason
+	if err := s.appointmentRepo.Update(appointment); err != nil {
+		if isNotFound(err) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, fmt.Errorf("failed to update appointment: %w", err)
+	}
+	if appointment.MechanicID != nil {
+		s.notifyMechanic(*appointment.MechanicID, workOrder, "appointment_cancelled", "Booking cancelled",
+			fmt.Sprintf("Work order %s is no longer booked %s: %s", workOrder.WONumber,
+				describePeriod(appointment.StartsAt, appointment.EndsAt), req.Reason))
+	}
+	return appointment, nil
+}
+
+// Capacity returns the day or week view of a branch: the booked and available bay and mechanic
+// hours per day, and the calendar of each bay and mechanic
+func (s *AppointmentService) Capacity(branchID uint, date time.Time, days int) (*WorkshopCapacity, error) {
+	if days != 1 && days != 7 {
+		return nil, fmt.Errorf("validation failed: days must be 1 or 7")
+	}
+	if _, err := workshopBranch(s.serviceRequestRepo, branchID); err != nil {
+		return nil, err
+	}
+
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, days)
+	capacity := &WorkshopCapacity{
+		BranchID:    branchID,
+		From:        from,
+		To:          to,
+		OpeningHour: s.config.OpeningHour,
+		ClosingHour: s.config.ClosingHour,
+		Days:        make([]*CapacityDay, days),
+		Bays:        []*BayCalendar{},
+		Mechanics:   []*MechanicCalendar{},
+	}
+
+	bays, err := s.appointmentRepo.ListBays(branchID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workshop bays: %w", err)
+	}
+	appointments, err := s.appointmentRepo.List(interfaces.AppointmentFilter{From: from, To: to, BranchID: branchID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+	booked := make([]*domain.WorkOrderAppointment, 0, len(appointments))
+	for _, appointment := range appointments {
+		if appointment.Status != domain.AppointmentStatusCancelled {
+			booked = append(booked, appointment)
+		}
+	}
+	profiles, err := s.mechanicService.List(branchID)
+	if err != nil {
+		return nil, err
+	}
+	mechanicIDs := make([]uint, len(profiles))
+	for i, profile := range profiles {
+		mechanicIDs[i] = profile.UserID
+	}
+	shifts, err := s.mechanicRepo.ListShifts(mechanicIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shifts: %w", err)
+	}
+
+	byBay := make(map[uint]*BayCalendar, len(bays))
+	for _, bay := range bays {
+		calendar := &BayCalendar{Bay: bay, Appointments: []*domain.WorkOrderAppointment{}}
+		byBay[bay.ID] = calendar
+		capacity.Bays = append(capacity.Bays, calendar)
+	}
+	byMechanic := make(map[uint]*MechanicCalendar, len(profiles))
+	dailyCapacity := make(map[uint]float64, len(profiles))
+	for _, profile := range profiles {
+		calendar := &MechanicCalendar{
+			MechanicID:   profile.UserID,
+			Shifts:       []*domain.MechanicShift{},
+			Appointments: []*domain.WorkOrderAppointment{},
+		}
+		if profile.User != nil {
+			calendar.Name = fmt.Sprintf("%s %s", profile.User.FirstName, profile.User.LastName)
+		}
+		byMechanic[profile.UserID] = calendar
+		dailyCapacity[profile.UserID] = profile.DailyCapacityHours
+		capacity.Mechanics = append(capacity.Mechanics, calendar)
+	}
+	for _, appointment := range booked {
+		if calendar := byBay[appointment.BayID]; calendar != nil {
+			calendar.Appointments = append(calendar.Appointments, appointment)
+		}
+		if appointment.MechanicID != nil {
+			if calendar := byMechanic[*appointment.MechanicID]; calendar != nil {
+				calendar.Appointments = append(calendar.Appointments, appointment)
+			}
+		}
+	}
+	for _, shift := range shifts {
+		if calendar := byMechanic[shift.UserID]; calendar != nil {
+			calendar.Shifts = append(calendar.Shifts, shift)
+		}
+	}
+
+	openHours := float64(s.config.ClosingHour - s.config.OpeningHour)
+	for i := range capacity.Days {
+		day := from.AddDate(0, 0, i)
+		opens, closes := s.openingHours(day)
+		summary := &CapacityDay{
+			Date:     day.Format("2006-01-02"),
+			Bays:     len(bays),
+			BayHours: openHours * float64(len(bays)),
+		}
+		for _, appointment := range booked {
+			hours := appointment.Hours(opens, closes)
+			if hours == 0 {
+				continue
+			}
+			summary.Appointments++
+			summary.BookedBayHours += hours
+			if calendar := byBay[appointment.BayID]; calendar != nil {
+				calendar.BookedHours += hours
+			}
+			if appointment.MechanicID != nil {
+				if calendar := byMechanic[*appointment.MechanicID]; calendar != nil {
+					summary.BookedMechanicHours += hours
+					calendar.BookedHours += hours
+				}
+			}
+		}
+		shiftHours := make(map[uint]float64)
+		for _, shift := range shifts {
+			shiftHours[shift.UserID] += shift.Hours(opens, closes)
+		}
+		for mechanicID, hours := range shiftHours {
+			if calendar := byMechanic[mechanicID]; calendar != nil {
+				hours = math.Min(hours, dailyCapacity[mechanicID])
+				summary.MechanicHours += hours
+				calendar.ShiftHours += hours
+			}
+		}
+
+		summary.BookedBayHours = roundTo(summary.BookedBayHours, 2)
+		summary.BookedMechanicHours = roundTo(summary.BookedMechanicHours, 2)
+		summary.MechanicHours = roundTo(summary.MechanicHours, 2)
+		if summary.BayHours > 0 {
+			summary.BayUtilisation = roundTo(summary.BookedBayHours/summary.BayHours, 2)
+		}
+		if summary.MechanicHours > 0 {
+			summary.MechanicUtilisation = roundTo(summary.BookedMechanicHours/summary.MechanicHours, 2)
+		}
+		capacity.Days[i] = summary
+	}
+	for _, calendar := range capacity.Bays {
+		calendar.BookedHours = roundTo(calendar.BookedHours, 2)
+	}
+	for _, calendar := range capacity.Mechanics {
+		calendar.ShiftHours = roundTo(calendar.ShiftHours, 2)
+		calendar.BookedHours = roundTo(calendar.BookedHours, 2)
+	}
+	return capacity, nil
+}
+
+// HandleWorkOrderStatus keeps the scheduled appointment in step with its work order: it follows
+// the work order to a newly assigned mechanic, ends when the work order is completed and is
+// cancelled with the work order
+func (s *AppointmentService) HandleWorkOrderStatus(workOrder *domain.WorkOrder, entry *domain.WorkOrderStatusHistory) {
+	if entry.NewStatus != domain.StatusAssigned && !isWorkOrderClosed(entry.NewStatus) {
+		return
+	}
+	appointment, err := s.appointmentRepo.GetScheduled(workOrder.ID)
+	if err != nil {
+		if !isNotFound(err) {
+			s.logger.WithError(err).WithField("work_order_id", workOrder.ID).Error("Failed to get appointment of work order")
+		}
+		return
+	}
+
+	now := time.Now().UTC()
+	previousMechanicID := appointment.MechanicID
+	switch {
+	case entry.NewStatus == domain.StatusCompleted && now.After(appointment.StartsAt):
+		appointment.Status = domain.AppointmentStatusCompleted
+		if now.Before(appointment.EndsAt) {
+			appointment.EndsAt = now // the rest of the booking is free again
+		}
+	case isWorkOrderClosed(entry.NewStatus):
+		appointment.Status = domain.AppointmentStatusCancelled
+		appointment.CancelReason = fmt.Sprintf("Work order %s", entry.NewStatus)
+	case sameMechanic(appointment.MechanicID, workOrder.AssignedMechanicID):
+		return
+	default:
+		appointment.MechanicID = workOrder.AssignedMechanicID
+	}
+
+	log := s.logger.WithFields(logrus.Fields{
+		"work_order_id":  workOrder.ID,
+		"appointment_id": appointment.ID,
+	})
+	err = s.appointmentRepo.Update(appointment)
+	if errors.Is(err, interfaces.ErrAppointmentOverlap) && appointment.Status == domain.AppointmentStatusScheduled {
+		// The new mechanic is booked elsewhere meanwhile: keep the bay without a mechanic
+		appointment.MechanicID = nil
+		err = s.appointmentRepo.Update(appointment)
+		if err == nil {
+			s.notifyMechanic(workOrder.ServiceAdvisorID, workOrder, "appointment_conflict", "Appointment conflict",
+				fmt.Sprintf("Work order %s was reassigned but the new mechanic is already booked %s. Move the appointment.",
+					workOrder.WONumber, describePeriod(appointment.StartsAt, appointment.EndsAt)))
+		}
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to update appointment of work order")
+		return
+	}
+	if appointment.Status != domain.AppointmentStatusScheduled {
+		if appointment.Status == domain.AppointmentStatusCancelled && previousMechanicID != nil {
+			s.notifyMechanic(*previousMechanicID, workOrder, "appointment_cancelled", "Booking cancelled",
+				fmt.Sprintf("Work order %s is no longer booked %s: %s.", workOrder.WONumber,
+					describePeriod(appointment.StartsAt, appointment.EndsAt), appointment.CancelReason))
+		}
+		return
+	}
+
+	// Reassigned: the booking moves from the previous mechanic's calendar to the new one's
+	period := describePeriod(appointment.StartsAt, appointment.EndsAt)
+	if previousMechanicID != nil {
+		s.notifyMechanic(*previousMechanicID, workOrder, "appointment_cancelled", "Booking reassigned",
+			fmt.Sprintf("Work order %s %s was reassigned to another mechanic.", workOrder.WONumber, period))
+	}
+	if appointment.MechanicID == nil {
+		return
+	}
+	s.notifyMechanic(*appointment.MechanicID, workOrder, "appointment_scheduled", "Work booked",
+		fmt.Sprintf("Work order %s is booked %s.", workOrder.WONumber, period))
+
+	conflicts, err := s.conflicts(appointment)
+	if err != nil {
+		log.WithError(err).Error("Failed to check appointment of reassigned work order")
+		return
+	}
+	if len(conflicts) > 0 {
+		s.notifyMechanic(workOrder.ServiceAdvisorID, workOrder, "appointment_conflict", "Appointment conflict",
+			fmt.Sprintf("Work order %s was reassigned but its appointment %s conflicts: %s. Move the appointment.",
+				workOrder.WONumber, period, describeConflicts(conflicts)))
+	}
+}
+
+// bookableBay retrieves an active bay that the work order's vehicle fits and that belongs to the
+// work order's branch, if it has one
+func (s *AppointmentService) bookableBay(bayID uint, workOrder *domain.WorkOrder) (*domain.WorkshopBay, error) {
+	bay, err := s.appointmentRepo.GetBay(bayID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("validation failed: workshop bay %d does not exist", bayID)
+		}
+		return nil, fmt.Errorf("failed to get workshop bay: %w", err)
+	}
+	if !bay.IsActive {
+		return nil, fmt.Errorf("validation failed: %s is not active", bay.Name)
+	}
+	if workOrder.BranchID != nil && *workOrder.BranchID != bay.BranchID {
+		return nil, fmt.Errorf("validation failed: %s is not at the work order's branch", bay.Name)
+	}
+	if !bay.Accepts(workOrder.Vehicle.Type) {
+		return nil, fmt.Errorf("validation failed: %s does not take %s vehicles", bay.Name, workOrder.Vehicle.Type)
+	}
+	return bay, nil
+}
+
+// setPeriod sets when an appointment starts and ends, to the minute
+func (s *AppointmentService) setPeriod(appointment *domain.WorkOrderAppointment, startsAt time.Time, duration time.Duration) error {
+	startsAt = startsAt.UTC().Truncate(time.Minute)
+	if startsAt.Before(time.Now().UTC().Truncate(time.Minute)) {
+		return fmt.Errorf("validation failed: starts_at must not be in the past")
+	}
+	if duration <= 0 || duration > maxAppointmentHours*time.Hour {
+		return fmt.Errorf("validation failed: duration must be between 1 minute and %d hours", maxAppointmentHours)
+	}
+	appointment.StartsAt = startsAt
+	appointment.EndsAt = startsAt.Add(duration)
+	return nil
+}
+
+// bookingError maps a booking rejected by the database constraints to the service errors
+func bookingError(err error, message string) error {
+	switch {
+	case errors.Is(err, interfaces.ErrAppointmentOverlap):
+		return fmt.Errorf("%w: the bay or mechanic was booked for the period meanwhile", ErrAppointmentConflict)
+	case errors.Is(err, interfaces.ErrAppointmentScheduled):
+		return ErrAppointmentExists
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// checkConflicts rejects an appointment that conflicts with the workshop calendar
+func (s *AppointmentService) checkConflicts(appointment *domain.WorkOrderAppointment) error {
+	conflicts, err := s.conflicts(appointment)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: %s", ErrAppointmentConflict, describeConflicts(conflicts))
+	}
+	return nil
+}
+
+// conflicts lists why an appointment cannot be booked: it must start and end within opening
+// hours, its bay and mechanic must be free, and the mechanic must be on shift for the opening
+// hours it covers. Bays stay held overnight for appointments spanning several days.
+func (s *AppointmentService) conflicts(appointment *domain.WorkOrderAppointment) ([]*AppointmentConflict, error) {
+	conflicts := []*AppointmentConflict{}
+	startOpens, startCloses := s.openingHours(appointment.StartsAt)
+	endOpens, endCloses := s.openingHours(appointment.EndsAt.Add(-time.Nanosecond))
+	if appointment.StartsAt.Before(startOpens) || !appointment.StartsAt.Before(startCloses) ||
+		!appointment.EndsAt.After(endOpens) || appointment.EndsAt.After(endCloses) {
+		conflicts = append(conflicts, &AppointmentConflict{
+			Type:    ConflictOutsideOpeningHours,
+			Message: fmt.Sprintf("bookings must be within %02d:00-%02d:00 UTC", s.config.OpeningHour, s.config.ClosingHour),
+		})
+	}
+
+	others, err := s.appointmentRepo.ListConflicting(appointment.BayID, appointment.MechanicID,
+		appointment.StartsAt, appointment.EndsAt, appointment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointments: %w", err)
+	}
+	for _, other := range others {
+		workOrder := fmt.Sprintf("work order %d", other.WorkOrderID)
+		if other.WorkOrder != nil {
+			workOrder = fmt.Sprintf("work order %s", other.WorkOrder.WONumber)
+		}
+		from, to, otherID, workOrderID := other.StartsAt, other.EndsAt, other.ID, other.WorkOrderID
+		if other.BayID == appointment.BayID {
+			conflicts = append(conflicts, &AppointmentConflict{
+				Type:          ConflictBayBooked,
+				Message:       fmt.Sprintf("the bay is booked for %s %s", workOrder, describePeriod(from, to)),
+				AppointmentID: &otherID,
+				WorkOrderID:   &workOrderID,
+				From:          &from,
+				To:            &to,
+			})
+		}
+		if sameMechanic(other.MechanicID, appointment.MechanicID) {
+			conflicts = append(conflicts, &AppointmentConflict{
+				Type:          ConflictMechanicBooked,
+				Message:       fmt.Sprintf("the mechanic is booked for %s %s", workOrder, describePeriod(from, to)),
+				AppointmentID: &otherID,
+				WorkOrderID:   &workOrderID,
+				From:          &from,
+				To:            &to,
+			})
+		}
+	}
+
+	if appointment.MechanicID != nil {
+		gap, err := s.offShift(*appointment.MechanicID, appointment.StartsAt, appointment.EndsAt)
+		if err != nil {
+			return nil, err
+		}
+		if gap != nil {
+			from, to := gap[0], gap[1]
+			conflicts = append(conflicts, &AppointmentConflict{
+				Type:    ConflictMechanicOffShift,
+				Message: fmt.Sprintf("the mechanic has no shift %s", describePeriod(from, to)),
+				From:    &from,
+				To:      &to,
+			})
+		}
+	}
+	return conflicts, nil
+}
+
+// offShift returns the first period within opening hours of [from, to) that the mechanic's
+// shifts do not cover, or nil when they cover all of it
+func (s *AppointmentService) offShift(mechanicID uint, from, to time.Time) ([]time.Time, error) {
+	shifts, err := s.mechanicRepo.ListShifts([]uint{mechanicID}, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shifts: %w", err)
+	}
+	for day := from.Truncate(24 * time.Hour); day.Before(to); day = day.AddDate(0, 0, 1) {
+		opens, closes := s.openingHours(day)
+		if opens.Before(from) {
+			opens = from
+		}
+		if closes.After(to) {
+			closes = to
+		}
+		covered := opens
+		for _, shift := range shifts { // earliest first
+			if !covered.Before(closes) {
+				break
+			}
+			if shift.StartsAt.After(covered) {
+				return []time.Time{covered, minTime(shift.StartsAt, closes)}, nil
+			}
+			if shift.EndsAt.After(covered) {
+				covered = shift.EndsAt
+			}
+		}
+		if covered.Before(closes) {
+			return []time.Time{covered, closes}, nil
+		}
+	}
+	return nil, nil
+}
+
+// openingHours returns when the workshop opens and closes on the day of t
+func (s *AppointmentService) openingHours(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.Add(time.Duration(s.config.OpeningHour) * time.Hour), day.Add(time.Duration(s.config.ClosingHour) * time.Hour)
+}
+
+// applyBayRequest validates a workshop bay request and copies it onto the bay
+func (s *AppointmentService) applyBayRequest(bay *domain.WorkshopBay, req *WorkshopBayRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := workshopBranch(s.serviceRequestRepo, req.BranchID); err != nil {
+		return err
+	}
+	name := strings.TrimSpace(req.Name)
+	existing, err := s.appointmentRepo.ListBays(req.BranchID, false)
+	if err != nil {
+		return fmt.Errorf("failed to list workshop bays: %w", err)
+	}
+	for _, other := range existing {
+		if other.ID != bay.ID && strings.EqualFold(other.Name, name) {
+			return ErrWorkshopBayExists
+		}
+	}
+
+	vehicleTypes := domain.StringList{}
+	for _, vehicleType := range req.VehicleTypes {
+		vehicleType = strings.ToLower(strings.TrimSpace(vehicleType))
+		if vehicleType != "" && !vehicleTypes.Contains(vehicleType) {
+			vehicleTypes = append(vehicleTypes, vehicleType)
+		}
+	}
+
+	bay.BranchID = req.BranchID
+	bay.Name = name
+	bay.BayType = req.BayType
+	if bay.BayType == "" {
+		bay.BayType = domain.BayTypeBay
+	}
+	bay.VehicleTypes = vehicleTypes
+	bay.Notes = req.Notes
+	if req.IsActive != nil {
+		bay.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// notifyMechanic tells a user about a change to their calendar
+func (s *AppointmentService) notifyMechanic(userID uint, workOrder *domain.WorkOrder, notificationType, title, message string) {
+	err := s.notificationService.Notify([]uint{userID}, NotificationMessage{
+		Type:          notificationType,
+		Title:         fmt.Sprintf("%s: %s", title, workOrder.WONumber),
+		Message:       message,
+		ReferenceType: "work_order",
+		ReferenceID:   workOrder.ID,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("work_order_id", workOrder.ID).Error("Failed to send appointment notification")
+	}
+}
+
+// appointmentDuration returns the requested duration, or the remaining estimated hours of the work order
+func appointmentDuration(hours float64, workOrder *domain.WorkOrder) time.Duration {
+	if hours <= 0 {
+		hours = remainingHours(workOrder)
+	}
+	return hoursDuration(hours)
+}
+
+// hoursDuration converts hours to a duration rounded to the minute
+func hoursDuration(hours float64) time.Duration {
+	return time.Duration(math.Round(hours*60)) * time.Minute
+}
+
+func sameMechanic(a, b *uint) bool {
+	return a != nil && b != nil && *a == *b || a == nil && b == nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// describePeriod formats a period, e.g. "on 2024-06-01 08:00-10:30 UTC"
+func describePeriod(from, to time.Time) string {
+	from, to = from.UTC(), to.UTC()
+	if from.Format("2006-01-02") == to.Format("2006-01-02") {
+		return fmt.Sprintf("on %s-%s UTC", from.Format("2006-01-02 15:04"), to.Format("15:04"))
+	}
+	return fmt.Sprintf("from %s to %s UTC", from.Format("2006-01-02 15:04"), to.Format("2006-01-02 15:04"))
+}
+
+func describeConflicts(conflicts []*AppointmentConflict) string {
+	sort.SliceStable(conflicts, func(i, j int) bool { return conflicts[i].Type < conflicts[j].Type })
+	messages := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		messages[i] = conflict.Message
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/pkg/imaging"
+	"ton-platform/pkg/storage"
+)
+
+// Attachment errors
+var (
+	ErrAttachmentNotFound     = errors.New("attachment not found")
+	ErrAttachmentAccessDenied = errors.New("attachment was uploaded by another user")
+	ErrAttachmentTooLarge     = errors.New("file is too large")
+	ErrAttachmentType         = errors.New("unsupported file type, expected a JPEG, PNG, GIF or WebP image or a PDF")
+)
+
+// attachmentTypes maps the accepted content types, sniffed from the file itself, to the
+// extension the file is stored with
+var attachmentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// AttachmentConfig bounds uploads and the signed download links
+type AttachmentConfig struct {
+	MaxSize       int64         // bytes
+	URLExpiry     time.Duration // validity of signed download links
+	ThumbnailSize int           // longest side of image thumbnails in pixels
+}
+
+// AttachmentUpload is a file uploaded to a work order, vehicle or inspection
+type AttachmentUpload struct {
+	FileName    string `validate:"required,max=255"`
+	Category    string `validate:"omitempty,oneof=before after damage progress document other"` // defaults to other
+	Description string `validate:"max=500"`
+	Size        int64
+	Body        io.Reader
+}
+
+// AttachmentService stores files uploaded to work orders, vehicles and inspections in object
+// storage. Files are typed by their content rather than their name, images get a JPEG
+// thumbnail, and downloads go through signed links that expire. Image attachments of work
+// orders are listed among the work order's photos.
+type AttachmentService struct {
+	attachmentRepo   interfaces.AttachmentRepository
+	vehicleRepo      interfaces.VehicleRepository
+	inspectionRepo   interfaces.InspectionRepository
+	workOrderService *WorkOrderService
+	storage          storage.Storage
+	config           AttachmentConfig
+	validator        *validator.Validate
+	logger           *logrus.Logger
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(
+	attachmentRepo interfaces.AttachmentRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	inspectionRepo interfaces.InspectionRepository,
+	workOrderService *WorkOrderService,
+	store storage.Storage,
+	config AttachmentConfig,
+	logger *logrus.Logger,
+) *AttachmentService {
+	if config.MaxSize <= 0 {
+		config.MaxSize = 10 << 20
+	}
+	if config.URLExpiry <= 0 || config.URLExpiry > storage.MaxURLExpiry {
+		config.URLExpiry = 15 * time.Minute
+	}
+	if config.ThumbnailSize <= 0 {
+		config.ThumbnailSize = 320
+	}
+	return &AttachmentService{
+		attachmentRepo:   attachmentRepo,
+		vehicleRepo:      vehicleRepo,
+		inspectionRepo:   inspectionRepo,
+		workOrderService: workOrderService,
+		storage:          store,
+		config:           config,
+		validator:        validator.New(),
+		logger:           logger,
+	}
+}
+
+// MaxSize returns the largest file accepted in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.config.MaxSize
+}
+
+// Upload stores a file and attaches it to a work order, vehicle or inspection the viewer can access
+func (s *AttachmentService) Upload(viewer Viewer, ownerType string, ownerID uint, upload *AttachmentUpload) (*domain.Attachment, error) {
+	if err := s.validator.Struct(upload); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.checkOwnerAccess(viewer, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+	if upload.Size > s.config.MaxSize {
+		return nil, fmt.Errorf("%w, the maximum is %d MB", ErrAttachmentTooLarge, s.config.MaxSize>>20)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(upload.Body, s.config.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(data)) > s.config.MaxSize {
+		return nil, fmt.Errorf("%w, the maximum is %d MB", ErrAttachmentTooLarge, s.config.MaxSize>>20)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("validation failed: file is empty")
+	}
+	contentType := http.DetectContentType(data)
+	extension, ok := attachmentTypes[contentType]
+	if !ok {
+		return nil, ErrAttachmentType
+	}
+
+	name, err := randomObjectName()
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(data)
+	attachment := &domain.Attachment{
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		Category:    upload.Category,
+		FileName:    filepath.Base(strings.TrimSpace(upload.FileName)),
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+		Checksum:    hex.EncodeToString(checksum[:]),
+		StorageKey:  fmt.Sprintf("%s/%d/%s%s", ownerType, ownerID, name, extension),
+		Description: upload.Description,
+		UploadedBy:  viewer.UserID,
+	}
+	if attachment.Category == "" {
+		attachment.Category = domain.AttachmentCategoryOther
+	}
+
+	var thumbnail []byte
+	if attachment.IsImage() && contentType != "image/webp" { // the standard library has no WebP decoder
+		_, width, height, err := imaging.DecodeConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: file is not a valid image")
+		}
+		attachment.Width, attachment.Height = &width, &height
+		if thumbnail, err = imaging.Thumbnail(data, s.config.ThumbnailSize); err != nil {
+			s.logger.WithError(err).WithField("file_name", attachment.FileName).Warn("Failed to create thumbnail")
+			thumbnail = nil
+		}
+	}
+
+	if err := s.storage.Put(attachment.StorageKey, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+	if thumbnail != nil {
+		thumbnailKey := fmt.Sprintf("%s/%d/thumbnails/%s.jpg", ownerType, ownerID, name)
+		if err := s.storage.Put(thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+			s.removeObjects(attachment)
+			return nil, fmt.Errorf("failed to store thumbnail: %w", err)
+		}
+		attachment.ThumbnailKey = &thumbnailKey
+	}
+
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		s.removeObjects(attachment)
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	if ownerType == domain.AttachmentOwnerWorkOrder && attachment.IsImage() {
+		photo := &domain.WorkOrderPhoto{
+			WorkOrderID:  ownerID,
+			AttachmentID: &attachment.ID,
+			PhotoURL:     fmt.Sprintf("/api/v1/attachments/%d/content", attachment.ID),
+			Description:  attachment.Description,
+			PhotoType:    attachment.Category,
+			UploadedBy:   viewer.UserID,
+		}
+		if err := s.attachmentRepo.CreateWorkOrderPhoto(photo); err != nil {
+			s.logger.WithError(err).WithField("attachment_id", attachment.ID).Error("Failed to add work order photo")
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"attachment_id": attachment.ID,
+		"owner_type":    ownerType,
+		"owner_id":      ownerID,
+		"content_type":  contentType,
+		"size_bytes":    attachment.SizeBytes,
+	}).Info("Attachment uploaded")
+	if err := s.sign(attachment); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// List retrieves the attachments of a work order, vehicle or inspection with signed download links
+func (s *AttachmentService) List(viewer Viewer, ownerType string, ownerID uint) ([]*domain.Attachment, error) {
+	if err := s.checkOwnerAccess(viewer, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.ListByOwner(ownerType, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	for _, attachment := range attachments {
+		if err := s.sign(attachment); err != nil {
+			return nil, err
+		}
+	}
+	return attachments, nil
+}
+
+// Get retrieves an attachment with signed download links
+func (s *AttachmentService) Get(viewer Viewer, id uint) (*domain.Attachment, error) {
+	attachment, err := s.get(viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sign(attachment); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// ContentURL returns a fresh signed link to the file, or to its thumbnail when there is one
+func (s *AttachmentService) ContentURL(viewer Viewer, id uint, thumbnail bool) (string, error) {
+	attachment, err := s.get(viewer, id)
+	if err != nil {
+		return "", err
+	}
+	key := attachment.StorageKey
+	if thumbnail && attachment.ThumbnailKey != nil {
+		key = *attachment.ThumbnailKey
+	}
+	url, err := s.storage.SignedURL(key, s.config.URLExpiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign download link: %w", err)
+	}
+	return url, nil
+}
+
+// Delete deletes an attachment and its files. Drivers and mechanics can only delete their own uploads.
+func (s *AttachmentService) Delete(viewer Viewer, id uint) error {
+	attachment, err := s.get(viewer, id)
+	if err != nil {
+		return err
+	}
+	if (viewer.IsDriver() || viewer.Role == domain.RoleMechanic) && attachment.UploadedBy != viewer.UserID {
+		return ErrAttachmentAccessDenied
+	}
+	if err := s.attachmentRepo.Delete(id); err != nil {
+		if isNotFound(err) {
+			return ErrAttachmentNotFound
+		}
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	s.removeObjects(attachment)
+	return nil
+}
+
+// OpenSigned opens a file for a signed link the application serves itself, after checking
+// the link's signature and expiry
+func (s *AttachmentService) OpenSigned(key, expires, signature string) (io.ReadCloser, *storage.Object, error) {
+	verifier, ok := s.storage.(storage.Verifier)
+	if !ok {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err := verifier.Verify(key, expires, signature); err != nil {
+		return nil, nil, err
+	}
+	body, object, err := s.storage.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return body, object, nil
+}
+
+// get retrieves an attachment whose owner the viewer can access
+func (s *AttachmentService) get(viewer Viewer, id uint) (*domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	if err := s.checkOwnerAccess(viewer, attachment.OwnerType, attachment.OwnerID); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// checkOwnerAccess verifies the work order, vehicle or inspection exists and the viewer can
+// access it; drivers only reach their assigned vehicles and their inspections
+func (s *AttachmentService) checkOwnerAccess(viewer Viewer, ownerType string, ownerID uint) error {
+	switch ownerType {
+	case domain.AttachmentOwnerWorkOrder:
+		_, err := s.workOrderService.Get(viewer, ownerID)
+		return err
+	case domain.AttachmentOwnerVehicle:
+		return checkVehicleAccess(s.vehicleRepo, viewer, ownerID)
+	case domain.AttachmentOwnerInspection:
+		inspection, err := s.inspectionRepo.GetByID(ownerID)
+		if err != nil {
+			if isNotFound(err) {
+				return ErrInspectionNotFound
+			}
+			return fmt.Errorf("failed to get inspection: %w", err)
+		}
+		return checkVehicleAccess(s.vehicleRepo, viewer, inspection.VehicleID)
+	default:
+		return fmt.Errorf("validation failed: unknown attachment owner %q", ownerType)
+	}
+}
+
+// sign fills in the signed download links of an attachment
+func (s *AttachmentService) sign(attachment *domain.Attachment) error {
+	expiresAt := time.Now().Add(s.config.URLExpiry).UTC()
+	url, err := s.storage.SignedURL(attachment.StorageKey, s.config.URLExpiry)
+	if err != nil {
+		return fmt.Errorf("failed to sign download link: %w", err)
+	}
+	attachment.URL = url
+	attachment.URLExpiresAt = &expiresAt
+	if attachment.ThumbnailKey != nil {
+		if attachment.ThumbnailURL, err = s.storage.SignedURL(*attachment.ThumbnailKey, s.config.URLExpiry); err != nil {
+			return fmt.Errorf("failed to sign download link: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeObjects deletes the files of an attachment, logging failures; a leftover object is
+// unreachable without its attachment
+func (s *AttachmentService) removeObjects(attachment *domain.Attachment) {
+	keys := []string{attachment.StorageKey}
+	if attachment.ThumbnailKey != nil {
+		keys = append(keys, *attachment.ThumbnailKey)
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(key); err != nil {
+			s.logger.WithError(err).WithField("key", key).Error("Failed to delete attachment file")
+		}
+	}
+}
+
+// randomObjectName returns a random name for a stored file, so keys cannot be guessed
+func randomObjectName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate file name: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Errors shared by business services. Handlers map them to HTTP status codes.
+var (
+	ErrVehicleNotFound     = errors.New("vehicle not found")
+	ErrWorkOrderNotFound   = errors.New("work order not found")
+	ErrVehicleAccessDenied = errors.New("vehicle is not assigned to the current user")
+)
+
+// isNotFound reports whether a repository error signals a missing record
+func isNotFound(err error) bool {
+	return err != nil && strings.HasSuffix(err.Error(), "not found")
+}
+
+// generateNumber builds a human readable document number such as INS-20240131-1A2B3C
+func generateNumber(prefix string) string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Sprintf("%s-%s", prefix, time.Now().UTC().Format("20060102-150405.000"))
+	}
+	return fmt.Sprintf("%s-%s-%s", prefix, time.Now().UTC().Format("20060102"), strings.ToUpper(hex.EncodeToString(suffix)))
+}
+
+// internalCustomerName is used as the customer of work orders raised by the
+// platform itself for fleet vehicles
+func internalCustomerName(vehicle *domain.Vehicle) string {
+	return fmt.Sprintf("TON Fleet - %s", vehicle.PlateNumber)
+}
+
+// workOrderAdvisor picks the user automatic work orders are raised on behalf of:
+// the first active service advisor, or the first active administrator when there is none
+func workOrderAdvisor(roleRepo interfaces.RoleRepository, userRepo interfaces.UserRepository) (uint, error) {
+	for _, roleName := range []string{domain.RoleServiceAdvisor, domain.RoleAdministrator} {
+		role, err := roleRepo.GetByName(roleName)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return 0, fmt.Errorf("failed to get role: %w", err)
+		}
+		users, err := userRepo.GetByRoleID(role.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get users: %w", err)
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+		for _, user := range users {
+			if user.IsActive {
+				return user.ID, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no active service advisor or administrator to raise the work order")
+}
+
+// workshopBranch retrieves an active branch or workshop that work can be allocated to
+func workshopBranch(serviceRequestRepo interfaces.ServiceRequestRepository, id uint) (*domain.Warehouse, error) {
+	branch, err := serviceRequestRepo.GetBranch(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("validation failed: branch %d does not exist", id)
+		}
+		return nil, fmt.Errorf("failed to get branch: %w", err)
+	}
+	if !branch.IsActive || (branch.Type != domain.WarehouseTypeBranch && branch.Type != domain.WarehouseTypeMechanic) {
+		return nil, fmt.Errorf("validation failed: %s is not an active branch or workshop", branch.Name)
+	}
+	return branch, nil
+}
+
+// Viewer identifies the user a request is served for
+type Viewer struct {
+	UserID   uint
+	Username string
+	Role     string
+}
+
+// IsDriver reports whether the viewer only has access to assigned vehicles
+func (v Viewer) IsDriver() bool {
+	return v.Role == domain.RoleDriver
+}
+
+// assignedVehicleIDs returns the vehicles assigned to a user. Vehicle.AssignedTo holds
+// either the username or the numeric user ID.
+func assignedVehicleIDs(vehicleRepo interfaces.VehicleRepository, viewer Viewer) (map[uint]bool, error) {
+	vehicles, err := vehicleRepo.GetByAssignee([]string{viewer.Username, fmt.Sprint(viewer.UserID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned vehicles: %w", err)
+	}
+	ids := make(map[uint]bool, len(vehicles))
+	for _, vehicle := range vehicles {
+		ids[vehicle.ID] = true
+	}
+	return ids, nil
+}
+
+// checkVehicleAccess verifies the vehicle exists and, for drivers, is assigned to them
+func checkVehicleAccess(vehicleRepo interfaces.VehicleRepository, viewer Viewer, vehicleID uint) error {
+	if _, err := vehicleRepo.GetByID(vehicleID); err != nil {
+		if isNotFound(err) {
+			return ErrVehicleNotFound
+		}
+		return fmt.Errorf("failed to get vehicle: %w", err)
+	}
+	if viewer.IsDriver() {
+		assigned, err := assignedVehicleIDs(vehicleRepo, viewer)
+		if err != nil {
+			return err
+		}
+		if !assigned[vehicleID] {
+			return ErrVehicleAccessDenied
+		}
+	}
+	return nil
+}
@@ -0,0 +1,690 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Condition monitoring errors
+var (
+	ErrConditionRuleNotFound      = errors.New("condition rule not found")
+	ErrConditionAlertNotFound     = errors.New("condition alert not found")
+	ErrConditionAlertAcknowledged = errors.New("condition alert is already acknowledged")
+)
+
+const (
+	// conditionRuleCacheTTL bounds how long another instance's rule changes take to apply here
+	conditionRuleCacheTTL = time.Minute
+	// conditionVehicleCacheTTL bounds how long vehicle type changes take to apply
+	conditionVehicleCacheTTL = 10 * time.Minute
+	// conditionMaxSampleGap restarts a pending breach when readings stop for longer, so a
+	// duration is only met by readings that cover it
+	conditionMaxSampleGap = 5 * time.Minute
+)
+
+// conditionMetricUnits are appended to readings in alert messages
+var conditionMetricUnits = map[string]string{
+	domain.ConditionMetricEngineTemp:   " °C",
+	domain.ConditionMetricOilPressure:  "",
+	domain.ConditionMetricBatteryLevel: " V",
+	domain.ConditionMetricEngineRPM:    " rpm",
+}
+
+// ConditionRuleRequest represents a condition rule; updates replace the whole rule
+type ConditionRuleRequest struct {
+	Name        string   `json:"name" validate:"required,max=100"`
+	Description string   `json:"description"`
+	VehicleType *string  `json:"vehicle_type" validate:"omitempty,max=50"`
+	Metric      string   `json:"metric" validate:"required,oneof=engine_temperature oil_pressure battery_voltage engine_rpm"`
+	Operator    string   `json:"operator" validate:"required,oneof=above below"`
+	Threshold   *float64 `json:"threshold" validate:"required"`
+	// ClearThreshold defaults to the threshold, which disables hysteresis
+	ClearThreshold   *float64 `json:"clear_threshold"`
+	DurationSeconds  int      `json:"duration_seconds" validate:"min=0,max=86400"`
+	EngineState      string   `json:"engine_state" validate:"omitempty,oneof=any running off"`
+	Severity         string   `json:"severity" validate:"required,oneof=warning critical"`
+	CreateWorkOrder  bool     `json:"create_work_order"`
+	RecipientUserIDs []uint   `json:"recipient_user_ids" validate:"max=50"`
+	IsActive         *bool    `json:"is_active"`
+}
+
+// ConditionAlertAcknowledgeRequest records that a user has seen an alert
+type ConditionAlertAcknowledgeRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// ConditionAlertList represents a page of condition alerts
+type ConditionAlertList struct {
+	Alerts []*domain.ConditionAlert `json:"alerts"`
+	Total  int64                    `json:"total"`
+	Page   int                      `json:"page"`
+	Limit  int                      `json:"limit"`
+}
+
+type conditionStateKey struct {
+	ruleID    uint
+	vehicleID uint
+}
+
+// conditionRuleScope groups rules that a vehicle type specific rule overrides
+type conditionRuleScope struct {
+	metric      string
+	operator    string
+	engineState string
+}
+
+type conditionVehicleInfo struct {
+	plateNumber string
+	vehicleType string
+	loadedAt    time.Time
+}
+
+// ConditionService watches sensor readings during ingestion. Each active rule is evaluated on
+// every stored sample; a reading beyond the threshold for the rule's duration raises an alert,
+// which stays active until a reading is back past the clear threshold. Raised alerts notify
+// the rule's recipients and may open an inspection work order.
+type ConditionService struct {
+	conditionRepo       interfaces.ConditionRepository
+	vehicleRepo         interfaces.VehicleRepository
+	workOrderRepo       interfaces.WorkOrderRepository
+	userRepo            interfaces.UserRepository
+	roleRepo            interfaces.RoleRepository
+	notificationService *NotificationService
+	validator           *validator.Validate
+	logger              *logrus.Logger
+
+	mu       sync.Mutex
+	rules    []*domain.ConditionRule
+	loadedAt time.Time
+	vehicles map[uint]*conditionVehicleInfo
+}
+
+// NewConditionService creates a new condition service
+func NewConditionService(
+	conditionRepo interfaces.ConditionRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	workOrderRepo interfaces.WorkOrderRepository,
+	userRepo interfaces.UserRepository,
+	roleRepo interfaces.RoleRepository,
+	notificationService *NotificationService,
+	logger *logrus.Logger,
+) *ConditionService {
+	return &ConditionService{
+		conditionRepo:       conditionRepo,
+		vehicleRepo:         vehicleRepo,
+		workOrderRepo:       workOrderRepo,
+		userRepo:            userRepo,
+		roleRepo:            roleRepo,
+		notificationService: notificationService,
+		validator:           validator.New(),
+		logger:              logger,
+		vehicles:            make(map[uint]*conditionVehicleInfo),
+	}
+}
+
+// CreateRule creates a condition rule
+func (s *ConditionService) CreateRule(req *ConditionRuleRequest, createdBy uint) (*domain.ConditionRule, error) {
+	rule := &domain.ConditionRule{IsActive: true, CreatedBy: &createdBy}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.conditionRepo.CreateRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to create condition rule: %w", err)
+	}
+	s.invalidateRules()
+
+	s.logger.WithFields(logrus.Fields{
+		"rule_id":    rule.ID,
+		"name":       rule.Name,
+		"created_by": createdBy,
+	}).Info("Condition rule created")
+	return rule, nil
+}
+
+// GetRule retrieves a condition rule
+func (s *ConditionService) GetRule(id uint) (*domain.ConditionRule, error) {
+	rule, err := s.conditionRepo.GetRule(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrConditionRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get condition rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListRules retrieves every condition rule
+func (s *ConditionService) ListRules() ([]*domain.ConditionRule, error) {
+	rules, err := s.conditionRepo.ListRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list condition rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateRule replaces a condition rule. Deactivating a rule, or changing what it measures,
+// clears its active alerts and pending breaches; other changes apply to the next reading.
+func (s *ConditionService) UpdateRule(id uint, req *ConditionRuleRequest) (*domain.ConditionRule, error) {
+	rule, err := s.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+	previous := *rule
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.conditionRepo.UpdateRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to update condition rule: %w", err)
+	}
+	s.invalidateRules()
+
+	if !rule.IsActive || rule.Metric != previous.Metric || rule.Operator != previous.Operator {
+		if err := s.conditionRepo.ResetRule(rule.ID, time.Now().UTC()); err != nil {
+			return nil, fmt.Errorf("failed to reset condition rule: %w", err)
+		}
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes a condition rule. Its active alerts are cleared; past alerts are kept.
+func (s *ConditionService) DeleteRule(id uint) error {
+	if err := s.conditionRepo.ResetRule(id, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to reset condition rule: %w", err)
+	}
+	if err := s.conditionRepo.DeleteRule(id); err != nil {
+		if isNotFound(err) {
+			return ErrConditionRuleNotFound
+		}
+		return fmt.Errorf("failed to delete condition rule: %w", err)
+	}
+	s.invalidateRules()
+	return nil
+}
+
+// ListAlerts retrieves a page of condition alerts across the fleet
+func (s *ConditionService) ListAlerts(filter interfaces.ConditionAlertFilter, page, limit, offset int) (*ConditionAlertList, error) {
+	if filter.Status != "" && filter.Status != domain.ConditionAlertActive && filter.Status != domain.ConditionAlertCleared {
+		return nil, fmt.Errorf("validation failed: status must be active or cleared")
+	}
+	if filter.Severity != "" && filter.Severity != domain.ConditionSeverityWarning && filter.Severity != domain.ConditionSeverityCritical {
+		return nil, fmt.Errorf("validation failed: severity must be warning or critical")
+	}
+	alerts, total, err := s.conditionRepo.ListAlerts(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list condition alerts: %w", err)
+	}
+	return &ConditionAlertList{Alerts: alerts, Total: total, Page: page, Limit: limit}, nil
+}
+
+// ListVehicleAlerts retrieves a page of the condition alerts of a vehicle
+func (s *ConditionService) ListVehicleAlerts(viewer Viewer, vehicleID uint, filter interfaces.ConditionAlertFilter, page, limit, offset int) (*ConditionAlertList, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+	filter.VehicleID = vehicleID
+	return s.ListAlerts(filter, page, limit, offset)
+}
+
+// GetAlert retrieves a condition alert with its rule
+func (s *ConditionService) GetAlert(viewer Viewer, id uint) (*domain.ConditionAlert, error) {
+	alert, err := s.conditionRepo.GetAlert(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrConditionAlertNotFound
+		}
+		return nil, fmt.Errorf("failed to get condition alert: %w", err)
+	}
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, alert.VehicleID); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// AcknowledgeAlert records that a user has seen an alert. Acknowledging does not clear an active alert.
+func (s *ConditionService) AcknowledgeAlert(viewer Viewer, id uint, req *ConditionAlertAcknowledgeRequest) (*domain.ConditionAlert, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	alert, err := s.GetAlert(viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.AcknowledgedAt != nil {
+		return nil, ErrConditionAlertAcknowledged
+	}
+
+	now := time.Now().UTC()
+	alert.AcknowledgedBy = &viewer.UserID
+	alert.AcknowledgedAt = &now
+	alert.Note = req.Note
+	if err := s.conditionRepo.UpdateAlert(alert); err != nil {
+		return nil, fmt.Errorf("failed to update condition alert: %w", err)
+	}
+	return alert, nil
+}
+
+// HandleTelematics evaluates the active rules on newly stored samples; it is registered as an ingestion listener
+func (s *ConditionService) HandleTelematics(samples []*domain.TelematicsData) {
+	rules, err := s.activeRules()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to load condition rules")
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	vehicleIDs := make([]uint, 0)
+	seen := make(map[uint]bool)
+	for _, sample := range samples {
+		if !seen[sample.VehicleID] {
+			seen[sample.VehicleID] = true
+			vehicleIDs = append(vehicleIDs, sample.VehicleID)
+		}
+	}
+	vehicles := s.vehicleInfo(vehicleIDs)
+
+	stored, err := s.conditionRepo.GetVehicleStates(vehicleIDs)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to load condition states")
+		return
+	}
+	states := make(map[conditionStateKey]*domain.ConditionVehicleState, len(stored))
+	for _, state := range stored {
+		states[conditionStateKey{state.RuleID, state.VehicleID}] = state
+	}
+	activeAlerts, err := s.conditionRepo.GetActiveAlerts(vehicleIDs)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to load active condition alerts")
+		return
+	}
+	active := make(map[conditionStateKey]*domain.ConditionAlert, len(activeAlerts))
+	for _, alert := range activeAlerts {
+		if alert.RuleID != nil {
+			active[conditionStateKey{*alert.RuleID, alert.VehicleID}] = alert
+		}
+	}
+
+	vehicleRules := make(map[uint][]*domain.ConditionRule, len(vehicleIDs))
+	for _, vehicleID := range vehicleIDs {
+		vehicleType := ""
+		if info := vehicles[vehicleID]; info != nil {
+			vehicleType = info.vehicleType
+		}
+		vehicleRules[vehicleID] = applicableConditionRules(rules, vehicleType)
+	}
+
+	changedStates := make(map[conditionStateKey]*domain.ConditionVehicleState)
+	changedAlerts := make(map[*domain.ConditionAlert]bool)
+	var touched []*domain.ConditionAlert
+	var raised []*domain.ConditionAlert
+	for _, sample := range samples {
+		for _, rule := range vehicleRules[sample.VehicleID] {
+			key := conditionStateKey{rule.ID, sample.VehicleID}
+			state := states[key]
+			if state == nil {
+				state = &domain.ConditionVehicleState{RuleID: rule.ID, VehicleID: sample.VehicleID}
+				states[key] = state
+			} else if !sample.Timestamp.After(state.SampleAt) {
+				// Already evaluated or arrived out of order
+				continue
+			}
+			changedStates[key] = state
+
+			alert, isNew := evaluateCondition(rule, state, active[key], sample)
+			if alert == nil {
+				continue
+			}
+			if isNew {
+				raised = append(raised, alert)
+			}
+			if alert.Status == domain.ConditionAlertActive {
+				active[key] = alert
+			} else {
+				delete(active, key)
+			}
+			if !changedAlerts[alert] {
+				changedAlerts[alert] = true
+				touched = append(touched, alert)
+			}
+		}
+	}
+
+	if err := s.conditionRepo.SaveAlerts(touched); err != nil {
+		s.logger.WithError(err).Error("Failed to store condition alerts")
+		return
+	}
+	toSave := make([]*domain.ConditionVehicleState, 0, len(changedStates))
+	for _, state := range changedStates {
+		toSave = append(toSave, state)
+	}
+	if err := s.conditionRepo.SaveVehicleStates(toSave); err != nil {
+		s.logger.WithError(err).Error("Failed to store condition states")
+	}
+
+	for _, alert := range raised {
+		s.raise(alert, conditionRuleByID(rules, *alert.RuleID), vehicles[alert.VehicleID])
+	}
+}
+
+// evaluateCondition advances the state of a rule for a vehicle by one sample. It returns the
+// alert the sample raised, cleared or raised the peak of, and whether the alert is new.
+// Readings the rule does not apply to interrupt a pending breach but leave an active alert
+// as it is.
+func evaluateCondition(rule *domain.ConditionRule, state *domain.ConditionVehicleState, active *domain.ConditionAlert, sample *domain.TelematicsData) (*domain.ConditionAlert, bool) {
+	at := sample.Timestamp
+	if !state.SampleAt.IsZero() && at.Sub(state.SampleAt) > conditionMaxSampleGap {
+		state.BreachStartedAt = nil
+	}
+	state.SampleAt = at
+
+	value, ok := rule.Reading(sample)
+	if !ok || !rule.MatchesEngine(sample.EngineStatus) {
+		state.BreachStartedAt = nil
+		return nil, false
+	}
+
+	if active != nil {
+		if rule.Cleared(value) {
+			active.Status = domain.ConditionAlertCleared
+			active.ClearedAt = &at
+			active.ClearValue = &value
+			return active, false
+		}
+		if peak := rule.Worse(active.PeakValue, value); peak != active.PeakValue {
+			active.PeakValue = peak
+			return active, false
+		}
+		return nil, false
+	}
+
+	if !rule.Breached(value) {
+		state.BreachStartedAt = nil
+		return nil, false
+	}
+	if state.BreachStartedAt == nil {
+		started := at
+		state.BreachStartedAt = &started
+		state.PeakValue = value
+	} else {
+		state.PeakValue = rule.Worse(state.PeakValue, value)
+	}
+	if at.Sub(*state.BreachStartedAt) < time.Duration(rule.DurationSeconds)*time.Second {
+		return nil, false
+	}
+
+	ruleID := rule.ID
+	alert := &domain.ConditionAlert{
+		RuleID:       &ruleID,
+		VehicleID:    state.VehicleID,
+		Name:         rule.Name,
+		Metric:       rule.Metric,
+		Operator:     rule.Operator,
+		Threshold:    rule.Threshold,
+		Severity:     rule.Severity,
+		Status:       domain.ConditionAlertActive,
+		StartedAt:    *state.BreachStartedAt,
+		TriggeredAt:  at,
+		TriggerValue: value,
+		PeakValue:    state.PeakValue,
+	}
+	state.BreachStartedAt = nil
+	return alert, true
+}
+
+// applicableConditionRules returns the rules evaluated for a vehicle type. A rule for the
+// vehicle's type replaces the generic rules on the same metric, operator and engine state.
+func applicableConditionRules(rules []*domain.ConditionRule, vehicleType string) []*domain.ConditionRule {
+	overridden := make(map[conditionRuleScope]bool)
+	for _, rule := range rules {
+		if rule.VehicleType != nil && strings.EqualFold(*rule.VehicleType, vehicleType) {
+			overridden[conditionRuleScope{rule.Metric, rule.Operator, rule.EngineState}] = true
+		}
+	}
+
+	var applicable []*domain.ConditionRule
+	for _, rule := range rules {
+		if rule.VehicleType != nil {
+			if strings.EqualFold(*rule.VehicleType, vehicleType) {
+				applicable = append(applicable, rule)
+			}
+			continue
+		}
+		if !overridden[conditionRuleScope{rule.Metric, rule.Operator, rule.EngineState}] {
+			applicable = append(applicable, rule)
+		}
+	}
+	return applicable
+}
+
+// raise opens the work order of a new alert when the rule asks for one and notifies the recipients
+func (s *ConditionService) raise(alert *domain.ConditionAlert, rule *domain.ConditionRule, vehicle *conditionVehicleInfo) {
+	plate := fmt.Sprintf("Vehicle %d", alert.VehicleID)
+	if vehicle != nil && vehicle.plateNumber != "" {
+		plate = vehicle.plateNumber
+	}
+	s.logger.WithFields(logrus.Fields{
+		"vehicle_id": alert.VehicleID,
+		"rule_id":    *alert.RuleID,
+		"alert_id":   alert.ID,
+		"value":      alert.TriggerValue,
+	}).Info("Condition alert raised")
+	if rule == nil {
+		return
+	}
+
+	recipients := append([]uint{}, rule.RecipientUserIDs...)
+	workOrderNote := ""
+	if rule.CreateWorkOrder {
+		workOrder, created, err := s.openWorkOrder(alert, plate)
+		if err != nil {
+			s.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to open work order for condition alert")
+		} else if created {
+			recipients = append(recipients, workOrder.ServiceAdvisorID)
+			workOrderNote = fmt.Sprintf(" Work order %s was opened.", workOrder.WONumber)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	err := s.notificationService.Notify(uniqueUints(recipients), NotificationMessage{
+		Type:          "condition_" + alert.Severity,
+		Title:         fmt.Sprintf("%s on %s", alert.Name, plate),
+		Message:       fmt.Sprintf("%s: %s.%s", plate, describeConditionAlert(alert), workOrderNote),
+		ReferenceType: "condition_alert",
+		ReferenceID:   alert.ID,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to send condition alert notification")
+	}
+}
+
+// openWorkOrder links an alert to the vehicle's open inspection work order for the rule,
+// opening one when there is none. It reports whether the work order is new.
+func (s *ConditionService) openWorkOrder(alert *domain.ConditionAlert, plate string) (*domain.WorkOrder, bool, error) {
+	workOrderID, err := s.conditionRepo.FindOpenWorkOrderID(alert.VehicleID, *alert.RuleID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find open work order: %w", err)
+	}
+	if workOrderID != 0 {
+		if err := s.conditionRepo.SetAlertWorkOrder(alert.ID, workOrderID); err != nil {
+			return nil, false, fmt.Errorf("failed to link work order: %w", err)
+		}
+		alert.WorkOrderID = &workOrderID
+		return &domain.WorkOrder{ID: workOrderID}, false, nil
+	}
+
+	vehicle, err := s.vehicleRepo.GetByID(alert.VehicleID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+	advisorID, err := workOrderAdvisor(s.roleRepo, s.userRepo)
+	if err != nil {
+		return nil, false, err
+	}
+
+	priority := domain.PriorityNormal
+	if alert.Severity == domain.ConditionSeverityCritical {
+		priority = domain.PriorityCritical
+	}
+	workOrder := &domain.WorkOrder{
+		CustomerName:     internalCustomerName(vehicle),
+		VehicleID:        vehicle.ID,
+		ServiceType:      domain.ServiceTypeInspection,
+		Priority:         priority,
+		Status:           domain.StatusPending,
+		Description:      fmt.Sprintf("Inspection after condition alert %s: %s", alert.Name, describeConditionAlert(alert)),
+		ServiceAdvisorID: advisorID,
+		Notes:            fmt.Sprintf("Opened automatically, triggered at %s.", alert.TriggeredAt.UTC().Format(time.RFC3339)),
+	}
+	if err := s.workOrderRepo.Create(workOrder); err != nil {
+		return nil, false, fmt.Errorf("failed to create work order: %w", err)
+	}
+	if err := s.conditionRepo.SetAlertWorkOrder(alert.ID, workOrder.ID); err != nil {
+		return nil, false, fmt.Errorf("failed to link work order: %w", err)
+	}
+	alert.WorkOrderID = &workOrder.ID
+
+	s.logger.WithFields(logrus.Fields{
+		"vehicle_id":    vehicle.ID,
+		"plate_number":  plate,
+		"alert_id":      alert.ID,
+		"work_order_id": workOrder.ID,
+	}).Info("Work order opened for condition alert")
+	return workOrder, true, nil
+}
+
+// activeRules returns the cached active condition rules, reloading them when stale
+func (s *ConditionService) activeRules() ([]*domain.ConditionRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) < conditionRuleCacheTTL {
+		return s.rules, nil
+	}
+	rules, err := s.conditionRepo.ListActiveRules()
+	if err != nil {
+		return nil, err
+	}
+	s.rules, s.loadedAt = rules, time.Now()
+	return rules, nil
+}
+
+func (s *ConditionService) invalidateRules() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// vehicleInfo returns plate numbers and types, loading vehicles missing from the cache
+func (s *ConditionService) vehicleInfo(vehicleIDs []uint) map[uint]*conditionVehicleInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var missing []uint
+	for _, id := range vehicleIDs {
+		if info, ok := s.vehicles[id]; !ok || time.Since(info.loadedAt) > conditionVehicleCacheTTL {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		loaded, err := s.vehicleRepo.GetByIDs(missing)
+		if err != nil {
+			// Vehicle type rules are skipped until the vehicles can be loaded
+			s.logger.WithError(err).Warn("Failed to load vehicles for condition evaluation")
+		}
+		now := time.Now()
+		for _, vehicle := range loaded {
+			s.vehicles[vehicle.ID] = &conditionVehicleInfo{plateNumber: vehicle.PlateNumber, vehicleType: vehicle.Type, loadedAt: now}
+		}
+	}
+
+	result := make(map[uint]*conditionVehicleInfo, len(vehicleIDs))
+	for _, id := range vehicleIDs {
+		result[id] = s.vehicles[id]
+	}
+	return result
+}
+
+// applyRuleRequest validates a condition rule request and copies it onto the rule
+func (s *ConditionService) applyRuleRequest(rule *domain.ConditionRule, req *ConditionRuleRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	clearThreshold := *req.Threshold
+	if req.ClearThreshold != nil {
+		clearThreshold = *req.ClearThreshold
+	}
+	if req.Operator == domain.ConditionOperatorAbove && clearThreshold > *req.Threshold {
+		return fmt.Errorf("validation failed: clear_threshold must not be above the threshold")
+	}
+	if req.Operator == domain.ConditionOperatorBelow && clearThreshold < *req.Threshold {
+		return fmt.Errorf("validation failed: clear_threshold must not be below the threshold")
+	}
+
+	recipients := uniqueUints(req.RecipientUserIDs)
+	for _, userID := range recipients {
+		if _, err := s.userRepo.GetByID(userID); err != nil {
+			if isNotFound(err) {
+				return fmt.Errorf("validation failed: user %d not found", userID)
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+	}
+
+	rule.Name = req.Name
+	rule.Description = req.Description
+	rule.VehicleType = nil
+	if req.VehicleType != nil {
+		if vehicleType := strings.ToLower(strings.TrimSpace(*req.VehicleType)); vehicleType != "" {
+			rule.VehicleType = &vehicleType
+		}
+	}
+	rule.Metric = req.Metric
+	rule.Operator = req.Operator
+	rule.Threshold = *req.Threshold
+	rule.ClearThreshold = clearThreshold
+	rule.DurationSeconds = req.DurationSeconds
+	rule.EngineState = req.EngineState
+	if rule.EngineState == "" {
+		rule.EngineState = domain.ConditionEngineAny
+	}
+	rule.Severity = req.Severity
+	rule.CreateWorkOrder = req.CreateWorkOrder
+	rule.RecipientUserIDs = domain.UintList(recipients)
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// describeConditionAlert summarizes an alert, e.g. "engine temperature above 110 °C since 10:02 UTC, peak 114.5 °C"
+func describeConditionAlert(alert *domain.ConditionAlert) string {
+	unit := conditionMetricUnits[alert.Metric]
+	return fmt.Sprintf("%s %s %s%s since %s, peak %s%s",
+		strings.ReplaceAll(alert.Metric, "_", " "), alert.Operator,
+		strconv.FormatFloat(alert.Threshold, 'f', -1, 64), unit,
+		alert.StartedAt.UTC().Format("2006-01-02 15:04 MST"),
+		strconv.FormatFloat(alert.PeakValue, 'f', -1, 64), unit)
+}
+
+func conditionRuleByID(rules []*domain.ConditionRule, id uint) *domain.ConditionRule {
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Damage service errors
+var (
+	ErrDamageReportNotFound   = errors.New("damage report not found")
+	ErrInvoiceNotFound        = errors.New("invoice not found")
+	ErrInvalidDamageOperation = errors.New("invalid damage report operation")
+)
+
+// DamageService handles vehicle damage reporting
+type DamageService struct {
+	damageRepo     interfaces.DamageReportRepository
+	vehicleRepo    interfaces.VehicleRepository
+	inspectionRepo interfaces.InspectionRepository
+	invoiceRepo    interfaces.InvoiceRepository
+	validator      *validator.Validate
+	logger         *logrus.Logger
+}
+
+// CreateDamageReportRequest represents a new damage record on a vehicle
+type CreateDamageReportRequest struct {
+	InspectionID  *uint    `json:"inspection_id"`
+	ZoneCode      string   `json:"zone_code" validate:"required"`
+	PositionX     *float64 `json:"position_x" validate:"omitempty,min=0,max=1"`
+	PositionY     *float64 `json:"position_y" validate:"omitempty,min=0,max=1"`
+	DamageType    string   `json:"damage_type" validate:"required,oneof=scratch dent crack chip broken missing stain other"`
+	Severity      string   `json:"severity" validate:"required,oneof=minor moderate severe"`
+	Description   string   `json:"description"`
+	PhotoURLs     []string `json:"photo_urls" validate:"dive,url"`
+	EstimatedCost float64  `json:"estimated_cost" validate:"min=0"`
+}
+
+// UpdateDamageStatusRequest represents a damage status change
+type UpdateDamageStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=open repaired"`
+}
+
+// ChargeDamageRequest represents charging a damage to the customer.
+// Either an existing draft invoice or the customer for a new invoice must be given.
+type ChargeDamageRequest struct {
+	InvoiceID     *uint   `json:"invoice_id"`
+	CustomerName  string  `json:"customer_name" validate:"required_without=InvoiceID,max=100"`
+	CustomerEmail string  `json:"customer_email" validate:"omitempty,email"`
+	CustomerPhone string  `json:"customer_phone" validate:"max=20"`
+	Amount        float64 `json:"amount" validate:"required,gt=0"`
+	TaxRate       float64 `json:"tax_rate" validate:"min=0,max=100"`
+	DueInDays     int     `json:"due_in_days" validate:"min=0,max=365"`
+}
+
+// RepairDamageRequest represents converting damage into a repair work order
+type RepairDamageRequest struct {
+	Priority string `json:"priority" validate:"omitempty,oneof=low normal high critical emergency"`
+	Notes    string `json:"notes"`
+}
+
+// DamageComparison describes the damage differences between two inspections of a vehicle
+type DamageComparison struct {
+	FromInspection *domain.Inspection     `json:"from_inspection"`
+	ToInspection   *domain.Inspection     `json:"to_inspection"`
+	NewDamage      []*domain.DamageReport `json:"new_damage"`
+	ExistingDamage []*domain.DamageReport `json:"existing_damage"`
+	ResolvedDamage []*domain.DamageReport `json:"resolved_damage"`
+}
+
+// NewDamageService creates a new damage service
+func NewDamageService(
+	damageRepo interfaces.DamageReportRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	inspectionRepo interfaces.InspectionRepository,
+	invoiceRepo interfaces.InvoiceRepository,
+	logger *logrus.Logger,
+) *DamageService {
+	return &DamageService{
+		damageRepo:     damageRepo,
+		vehicleRepo:    vehicleRepo,
+		inspectionRepo: inspectionRepo,
+		invoiceRepo:    invoiceRepo,
+		validator:      validator.New(),
+		logger:         logger,
+	}
+}
+
+// Create records a new damage on a vehicle
+func (s *DamageService) Create(vehicleID uint, req *CreateDamageReportRequest, reportedBy uint) (*domain.DamageReport, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, ok := domain.VehicleDiagramZones[req.ZoneCode]; !ok {
+		return nil, fmt.Errorf("validation failed: unknown zone code %s", req.ZoneCode)
+	}
+
+	if _, err := s.getVehicle(vehicleID); err != nil {
+		return nil, err
+	}
+
+	reportedAt := time.Now().UTC()
+	if req.InspectionID != nil {
+		inspection, err := s.getVehicleInspection(vehicleID, *req.InspectionID)
+		if err != nil {
+			return nil, err
+		}
+		reportedAt = inspection.SubmittedAt
+	}
+
+	report := &domain.DamageReport{
+		VehicleID:     vehicleID,
+		InspectionID:  req.InspectionID,
+		ZoneCode:      req.ZoneCode,
+		PositionX:     req.PositionX,
+		PositionY:     req.PositionY,
+		DamageType:    req.DamageType,
+		Severity:      req.Severity,
+		Description:   req.Description,
+		PhotoURLs:     domain.StringList(req.PhotoURLs),
+		Status:        domain.DamageStatusOpen,
+		EstimatedCost: req.EstimatedCost,
+		ReportedBy:    reportedBy,
+		ReportedAt:    reportedAt,
+	}
+
+	if err := s.damageRepo.Create(report); err != nil {
+		s.logger.WithError(err).Error("Damage report creation failed")
+		return nil, fmt.Errorf("failed to create damage report: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"damage_report_id": report.ID,
+		"vehicle_id":       vehicleID,
+		"zone_code":        report.ZoneCode,
+		"severity":         report.Severity,
+	}).Info("Damage report created")
+
+	return report, nil
+}
+
+// GetByID retrieves a damage report
+func (s *DamageService) GetByID(id uint) (*domain.DamageReport, error) {
+	report, err := s.damageRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrDamageReportNotFound
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListByVehicle lists the damage reports of a vehicle, optionally filtered by status
+func (s *DamageService) ListByVehicle(vehicleID uint, status string) ([]*domain.DamageReport, error) {
+	if _, err := s.getVehicle(vehicleID); err != nil {
+		return nil, err
+	}
+	return s.damageRepo.GetByVehicle(vehicleID, status)
+}
+
+// UpdateStatus marks damage as repaired or re-opens it
+func (s *DamageService) UpdateStatus(id uint, req *UpdateDamageStatusRequest) (*domain.DamageReport, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	report, err := s.damageRepo.UpdateStatus(id, func(locked *domain.DamageReport) error {
+		switch req.Status {
+		case domain.DamageStatusRepaired:
+			if locked.Status == domain.DamageStatusCharged {
+				return fmt.Errorf("%w: damage has already been charged", ErrInvalidDamageOperation)
+			}
+			now := time.Now().UTC()
+			locked.Status = domain.DamageStatusRepaired
+			locked.ResolvedAt = &now
+		case domain.DamageStatusOpen:
+			if locked.InvoiceItemID != nil {
+				return fmt.Errorf("%w: damage has already been charged", ErrInvalidDamageOperation)
+			}
+			locked.Status = domain.DamageStatusOpen
+			locked.ResolvedAt = nil
+		}
+		return nil
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrDamageReportNotFound
+		}
+		if errors.Is(err, ErrInvalidDamageOperation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update damage report: %w", err)
+	}
+	return report, nil
+}
+
+// Compare detects damage present at the later inspection that was not present at the earlier one.
+// Damage is matched on zone and damage type, since annotated positions vary between inspectors.
+func (s *DamageService) Compare(vehicleID, fromInspectionID, toInspectionID uint) (*DamageComparison, error) {
+	from, err := s.getVehicleInspection(vehicleID, fromInspectionID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getVehicleInspection(vehicleID, toInspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if to.SubmittedAt.Before(from.SubmittedAt) {
+		from, to = to, from
+	}
+
+	reports, err := s.damageRepo.GetByVehicle(vehicleID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &DamageComparison{
+		FromInspection: from,
+		ToInspection:   to,
+		NewDamage:      []*domain.DamageReport{},
+		ExistingDamage: []*domain.DamageReport{},
+		ResolvedDamage: []*domain.DamageReport{},
+	}
+
+	baseline := make(map[string]bool)
+	var later []*domain.DamageReport
+	for _, report := range reports {
+		switch {
+		case recordedAt(report, from):
+			if report.ResolvedAt != nil && !report.ResolvedAt.After(from.SubmittedAt) {
+				continue // already repaired before the first inspection
+			}
+			if report.ResolvedAt != nil && !report.ResolvedAt.After(to.SubmittedAt) {
+				comparison.ResolvedDamage = append(comparison.ResolvedDamage, report)
+				continue
+			}
+			baseline[damageKey(report)] = true
+		case recordedAt(report, to):
+			later = append(later, report)
+		}
+	}
+
+	for _, report := range later {
+		if baseline[damageKey(report)] {
+			comparison.ExistingDamage = append(comparison.ExistingDamage, report)
+		} else {
+			comparison.NewDamage = append(comparison.NewDamage, report)
+		}
+	}
+
+	return comparison, nil
+}
+
+// ChargeToCustomer converts damage into an invoice line charged to the customer
+func (s *DamageService) ChargeToCustomer(id uint, req *ChargeDamageRequest, chargedBy uint) (*domain.DamageReport, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	report, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &domain.Invoice{}
+	if req.InvoiceID != nil {
+		if _, err := s.invoiceRepo.GetByID(*req.InvoiceID); err != nil {
+			if isNotFound(err) {
+				return nil, ErrInvoiceNotFound
+			}
+			return nil, err
+		}
+		invoice.ID = *req.InvoiceID
+	} else {
+		now := time.Now().UTC()
+		dueInDays := req.DueInDays
+		if dueInDays == 0 {
+			dueInDays = 14
+		}
+		invoice = &domain.Invoice{
+			CustomerName:  req.CustomerName,
+			CustomerEmail: req.CustomerEmail,
+			CustomerPhone: req.CustomerPhone,
+			Type:          domain.InvoiceTypeFees,
+			Status:        domain.InvoiceStatusDraft,
+			IssueDate:     now,
+			DueDate:       now.AddDate(0, 0, dueInDays),
+			Notes:         fmt.Sprintf("Vehicle damage charges for %s", report.Vehicle.PlateNumber),
+			CreatedBy:     chargedBy,
+		}
+	}
+
+	reportID := report.ID
+	item := &domain.InvoiceItem{
+		Description:   damageDescription(report),
+		Quantity:      1,
+		UnitPrice:     req.Amount,
+		TaxRate:       req.TaxRate,
+		ItemType:      domain.InvoiceTypeFees,
+		ReferenceType: "damage_report",
+		ReferenceID:   &reportID,
+	}
+	report, err = s.damageRepo.Charge(id, invoice, item, func(locked *domain.DamageReport, invoice *domain.Invoice) error {
+		if locked.Status != domain.DamageStatusOpen {
+			return fmt.Errorf("%w: only open damage can be charged", ErrInvalidDamageOperation)
+		}
+		if invoice.Status != domain.InvoiceStatusDraft {
+			return fmt.Errorf("%w: invoice %s is no longer a draft", ErrInvalidDamageOperation, invoice.InvoiceNumber)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidDamageOperation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to charge damage: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"damage_report_id": report.ID,
+		"invoice_id":       invoice.ID,
+		"invoice_item_id":  item.ID,
+		"amount":           req.Amount,
+	}).Info("Damage charged to customer")
+
+	return report, nil
+}
+
+// CreateRepairWorkOrder converts damage into a repair work order for the vehicle
+func (s *DamageService) CreateRepairWorkOrder(id uint, req *RepairDamageRequest, requestedBy uint) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	report, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = domain.PriorityNormal
+		if report.Severity == domain.DamageSeveritySevere {
+			priority = domain.PriorityHigh
+		}
+	}
+
+	workOrder := &domain.WorkOrder{
+		CustomerName:     internalCustomerName(report.Vehicle),
+		VehicleID:        report.VehicleID,
+		ServiceType:      domain.ServiceTypeRepair,
+		Priority:         priority,
+		Status:           domain.StatusPending,
+		Description:      damageDescription(report),
+		ServiceAdvisorID: requestedBy,
+		EstimatedCost:    report.EstimatedCost,
+		Notes:            req.Notes,
+	}
+	err = s.damageRepo.CreateRepairWorkOrder(id, workOrder, func(locked *domain.DamageReport) error {
+		if locked.RepairWorkOrderID != nil {
+			return fmt.Errorf("%w: a repair work order already exists", ErrInvalidDamageOperation)
+		}
+		if locked.Status == domain.DamageStatusRepaired {
+			return fmt.Errorf("%w: damage is already repaired", ErrInvalidDamageOperation)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidDamageOperation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create work order: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"damage_report_id": report.ID,
+		"work_order_id":    workOrder.ID,
+	}).Info("Repair work order created from damage report")
+
+	return workOrder, nil
+}
+
+func (s *DamageService) getVehicle(vehicleID uint) (*domain.Vehicle, error) {
+	vehicle, err := s.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, err
+	}
+	return vehicle, nil
+}
+
+func (s *DamageService) getVehicleInspection(vehicleID, inspectionID uint) (*domain.Inspection, error) {
+	inspection, err := s.inspectionRepo.GetByID(inspectionID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrInspectionNotFound
+		}
+		return nil, err
+	}
+	if inspection.VehicleID != vehicleID {
+		return nil, fmt.Errorf("%w: inspection %d belongs to another vehicle", ErrInvalidDamageOperation, inspectionID)
+	}
+	return inspection, nil
+}
+
+// recordedAt reports whether damage was known at the time of the given inspection
+func recordedAt(report *domain.DamageReport, inspection *domain.Inspection) bool {
+	if report.InspectionID != nil && *report.InspectionID == inspection.ID {
+		return true
+	}
+	return !report.ReportedAt.After(inspection.SubmittedAt)
+}
+
+func damageKey(report *domain.DamageReport) string {
+	return report.ZoneCode + "/" + report.DamageType
+}
+
+func damageDescription(report *domain.DamageReport) string {
+	description := fmt.Sprintf("Damage: %s - %s (%s)", domain.VehicleDiagramZones[report.ZoneCode], report.DamageType, report.Severity)
+	if report.Description != "" {
+		description += ": " + report.Description
+	}
+	return description
+}
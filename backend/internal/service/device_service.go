@@ -0,0 +1,411 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/pkg/security"
+)
+
+// Device service errors
+var (
+	ErrDeviceNotFound        = errors.New("device not found")
+	ErrDeviceExists          = errors.New("device with this identifier already exists")
+	ErrDeviceBindingNotFound = errors.New("device is not bound to a vehicle")
+	ErrInvalidDeviceBinding  = errors.New("invalid device binding")
+)
+
+// DeviceService handles the telematics device registry and device-to-vehicle bindings
+type DeviceService struct {
+	deviceRepo  interfaces.DeviceRepository
+	vehicleRepo interfaces.VehicleRepository
+	validator   *validator.Validate
+	logger      *logrus.Logger
+}
+
+// CreateDeviceRequest represents a device registration
+type CreateDeviceRequest struct {
+	Identifier      string `json:"identifier" validate:"required,max=50"`
+	IMEI            string `json:"imei" validate:"omitempty,numeric,min=14,max=16"`
+	SerialNumber    string `json:"serial_number" validate:"max=50"`
+	Manufacturer    string `json:"manufacturer" validate:"max=50"`
+	Model           string `json:"model" validate:"max=50"`
+	FirmwareVersion string `json:"firmware_version" validate:"max=50"`
+	Protocol        string `json:"protocol" validate:"omitempty,oneof=http teltonika_codec8 gt06 mqtt"`
+	SIMICCID        string `json:"sim_iccid" validate:"max=22"`
+	SIMPhoneNumber  string `json:"sim_phone_number" validate:"max=20"`
+	SIMCarrier      string `json:"sim_carrier" validate:"max=50"`
+	AuthType        string `json:"auth_type" validate:"omitempty,oneof=token hmac"`
+	Notes           string `json:"notes"`
+}
+
+// UpdateDeviceRequest represents a device update; empty fields are left unchanged
+type UpdateDeviceRequest struct {
+	IMEI            string `json:"imei" validate:"omitempty,numeric,min=14,max=16"`
+	SerialNumber    string `json:"serial_number" validate:"max=50"`
+	Manufacturer    string `json:"manufacturer" validate:"max=50"`
+	Model           string `json:"model" validate:"max=50"`
+	FirmwareVersion string `json:"firmware_version" validate:"max=50"`
+	Protocol        string `json:"protocol" validate:"omitempty,oneof=http teltonika_codec8 gt06 mqtt"`
+	SIMICCID        string `json:"sim_iccid" validate:"max=22"`
+	SIMPhoneNumber  string `json:"sim_phone_number" validate:"max=20"`
+	SIMCarrier      string `json:"sim_carrier" validate:"max=50"`
+	Status          string `json:"status" validate:"omitempty,oneof=active inactive maintenance retired"`
+	Notes           string `json:"notes"`
+}
+
+// RotateCredentialsRequest selects the credential type to issue
+type RotateCredentialsRequest struct {
+	AuthType string `json:"auth_type" validate:"omitempty,oneof=token hmac"`
+}
+
+// BindDeviceRequest represents installing a device in a vehicle
+type BindDeviceRequest struct {
+	VehicleID uint       `json:"vehicle_id" validate:"required"`
+	BoundFrom *time.Time `json:"bound_from"` // defaults to now; may be backdated to route late data
+	Note      string     `json:"note"`
+}
+
+// UnbindDeviceRequest represents removing a device from its vehicle
+type UnbindDeviceRequest struct {
+	At   *time.Time `json:"at"` // defaults to now
+	Note string     `json:"note"`
+}
+
+// DeviceCredentials is returned once when credentials are issued; the secret is not stored in clear
+// for token devices and is never returned again
+type DeviceCredentials struct {
+	Device   *domain.TelematicsDevice `json:"device"`
+	AuthType string                   `json:"auth_type"`
+	Secret   string                   `json:"secret"`
+}
+
+// DeviceList represents a page of devices
+type DeviceList struct {
+	Devices []*domain.TelematicsDevice `json:"devices"`
+	Total   int64                      `json:"total"`
+	Page    int                        `json:"page"`
+	Limit   int                        `json:"limit"`
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService(deviceRepo interfaces.DeviceRepository, vehicleRepo interfaces.VehicleRepository, logger *logrus.Logger) *DeviceService {
+	return &DeviceService{
+		deviceRepo:  deviceRepo,
+		vehicleRepo: vehicleRepo,
+		validator:   validator.New(),
+		logger:      logger,
+	}
+}
+
+// Register adds a device to the registry and issues its first credentials
+func (s *DeviceService) Register(req *CreateDeviceRequest, createdBy uint) (*DeviceCredentials, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := s.deviceRepo.GetByIdentifier(req.Identifier); err == nil {
+		return nil, ErrDeviceExists
+	} else if !isNotFound(err) {
+		return nil, fmt.Errorf("failed to check device: %w", err)
+	}
+
+	device := &domain.TelematicsDevice{
+		Identifier:      req.Identifier,
+		IMEI:            req.IMEI,
+		SerialNumber:    req.SerialNumber,
+		Manufacturer:    req.Manufacturer,
+		Model:           req.Model,
+		FirmwareVersion: req.FirmwareVersion,
+		Protocol:        req.Protocol,
+		SIMICCID:        req.SIMICCID,
+		SIMPhoneNumber:  req.SIMPhoneNumber,
+		SIMCarrier:      req.SIMCarrier,
+		Status:          domain.DeviceStatusActive,
+		Notes:           req.Notes,
+		CreatedBy:       createdBy,
+	}
+	if device.Protocol == "" {
+		device.Protocol = domain.DeviceProtocolHTTP
+	}
+
+	secret, err := issueCredentials(device, req.AuthType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deviceRepo.Create(device); err != nil {
+		s.logger.WithError(err).Error("Device registration failed")
+		return nil, fmt.Errorf("failed to register device: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"device_id":  device.ID,
+		"identifier": device.Identifier,
+		"created_by": createdBy,
+	}).Info("Telematics device registered")
+
+	return &DeviceCredentials{Device: device, AuthType: device.AuthType, Secret: secret}, nil
+}
+
+// GetByID retrieves a device with its current binding
+func (s *DeviceService) GetByID(id uint) (*domain.TelematicsDevice, error) {
+	device, err := s.getDevice(id)
+	if err != nil {
+		return nil, err
+	}
+
+	binding, err := s.deviceRepo.GetCurrentBinding(id)
+	if err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("failed to get device binding: %w", err)
+	}
+	device.CurrentBinding = binding
+	return device, nil
+}
+
+// List retrieves a page of devices
+func (s *DeviceService) List(status, search string, page, limit, offset int) (*DeviceList, error) {
+	devices, total, err := s.deviceRepo.List(status, search, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	return &DeviceList{Devices: devices, Total: total, Page: page, Limit: limit}, nil
+}
+
+// Update changes device details
+func (s *DeviceService) Update(id uint, req *UpdateDeviceRequest) (*domain.TelematicsDevice, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	device, err := s.getDevice(id)
+	if err != nil {
+		return nil, err
+	}
+
+	setIfNotEmpty(&device.IMEI, req.IMEI)
+	setIfNotEmpty(&device.SerialNumber, req.SerialNumber)
+	setIfNotEmpty(&device.Manufacturer, req.Manufacturer)
+	setIfNotEmpty(&device.Model, req.Model)
+	setIfNotEmpty(&device.FirmwareVersion, req.FirmwareVersion)
+	setIfNotEmpty(&device.Protocol, req.Protocol)
+	setIfNotEmpty(&device.SIMICCID, req.SIMICCID)
+	setIfNotEmpty(&device.SIMPhoneNumber, req.SIMPhoneNumber)
+	setIfNotEmpty(&device.SIMCarrier, req.SIMCarrier)
+	setIfNotEmpty(&device.Status, req.Status)
+	setIfNotEmpty(&device.Notes, req.Notes)
+
+	if err := s.deviceRepo.Update(device); err != nil {
+		return nil, fmt.Errorf("failed to update device: %w", err)
+	}
+	return device, nil
+}
+
+// RotateCredentials replaces the device credentials; the previous credentials stop working immediately
+func (s *DeviceService) RotateCredentials(id uint, req *RotateCredentialsRequest) (*DeviceCredentials, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	device, err := s.getDevice(id)
+	if err != nil {
+		return nil, err
+	}
+
+	authType := req.AuthType
+	if authType == "" {
+		authType = device.AuthType
+	}
+	secret, err := issueCredentials(device, authType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deviceRepo.Update(device); err != nil {
+		return nil, fmt.Errorf("failed to rotate device credentials: %w", err)
+	}
+
+	s.logger.WithField("device_id", device.ID).Info("Device credentials rotated")
+	return &DeviceCredentials{Device: device, AuthType: device.AuthType, Secret: secret}, nil
+}
+
+// Bind installs a device in a vehicle, closing its previous binding at the same instant
+func (s *DeviceService) Bind(id uint, req *BindDeviceRequest, userID uint) (*domain.DeviceBinding, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := s.getDevice(id); err != nil {
+		return nil, err
+	}
+	vehicle, err := s.vehicleRepo.GetByID(req.VehicleID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+
+	now := time.Now().UTC()
+	boundFrom := now
+	if req.BoundFrom != nil {
+		boundFrom = req.BoundFrom.UTC()
+		if boundFrom.After(now) {
+			return nil, fmt.Errorf("%w: bound_from cannot be in the future", ErrInvalidDeviceBinding)
+		}
+	}
+
+	// History must stay non-overlapping: a new binding may only start after the current one
+	current, err := s.deviceRepo.GetCurrentBinding(id)
+	if err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("failed to get device binding: %w", err)
+	}
+	if current != nil {
+		if current.VehicleID == req.VehicleID {
+			return nil, fmt.Errorf("%w: device is already bound to this vehicle", ErrInvalidDeviceBinding)
+		}
+		if !boundFrom.After(current.BoundFrom) {
+			return nil, fmt.Errorf("%w: bound_from must be after the start of the current binding", ErrInvalidDeviceBinding)
+		}
+	} else {
+		history, err := s.deviceRepo.GetBindingsInRange(id, boundFrom, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get device bindings: %w", err)
+		}
+		if len(history) > 0 {
+			return nil, fmt.Errorf("%w: bound_from overlaps an earlier binding", ErrInvalidDeviceBinding)
+		}
+	}
+
+	binding := &domain.DeviceBinding{
+		DeviceID:  id,
+		VehicleID: req.VehicleID,
+		BoundFrom: boundFrom,
+		BoundBy:   userID,
+		Note:      req.Note,
+	}
+	if err := s.deviceRepo.Bind(binding); err != nil {
+		return nil, fmt.Errorf("failed to bind device: %w", err)
+	}
+	binding.Vehicle = vehicle
+
+	s.logger.WithFields(logrus.Fields{
+		"device_id":  id,
+		"vehicle_id": req.VehicleID,
+		"bound_from": boundFrom,
+		"bound_by":   userID,
+	}).Info("Device bound to vehicle")
+
+	return binding, nil
+}
+
+// Unbind removes a device from its current vehicle
+func (s *DeviceService) Unbind(id uint, req *UnbindDeviceRequest, userID uint) error {
+	if _, err := s.getDevice(id); err != nil {
+		return err
+	}
+
+	current, err := s.deviceRepo.GetCurrentBinding(id)
+	if err != nil {
+		if isNotFound(err) {
+			return ErrDeviceBindingNotFound
+		}
+		return fmt.Errorf("failed to get device binding: %w", err)
+	}
+
+	at := time.Now().UTC()
+	if req.At != nil {
+		at = req.At.UTC()
+		if at.After(time.Now().UTC()) || !at.After(current.BoundFrom) {
+			return fmt.Errorf("%w: unbind time must be between the binding start and now", ErrInvalidDeviceBinding)
+		}
+	}
+
+	if err := s.deviceRepo.Unbind(id, at, userID); err != nil {
+		if isNotFound(err) {
+			return ErrDeviceBindingNotFound
+		}
+		return fmt.Errorf("failed to unbind device: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"device_id":  id,
+		"vehicle_id": current.VehicleID,
+		"unbound_by": userID,
+	}).Info("Device unbound from vehicle")
+	return nil
+}
+
+// GetBindingHistory retrieves the vehicles a device has been installed in
+func (s *DeviceService) GetBindingHistory(id uint) ([]*domain.DeviceBinding, error) {
+	if _, err := s.getDevice(id); err != nil {
+		return nil, err
+	}
+	bindings, err := s.deviceRepo.GetBindings(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device bindings: %w", err)
+	}
+	return bindings, nil
+}
+
+// GetVehicleDevices retrieves the devices installed in a vehicle over time
+func (s *DeviceService) GetVehicleDevices(vehicleID uint) ([]*domain.DeviceBinding, error) {
+	if _, err := s.vehicleRepo.GetByID(vehicleID); err != nil {
+		if isNotFound(err) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+	bindings, err := s.deviceRepo.GetBindingsByVehicle(vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vehicle devices: %w", err)
+	}
+	return bindings, nil
+}
+
+func (s *DeviceService) getDevice(id uint) (*domain.TelematicsDevice, error) {
+	device, err := s.deviceRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+	return device, nil
+}
+
+// issueCredentials generates a new secret for the device and returns it in clear text.
+// Token devices keep only a hash; HMAC devices keep the shared key for signature verification.
+func issueCredentials(device *domain.TelematicsDevice, authType string) (string, error) {
+	secret, err := security.GenerateDeviceSecret()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	device.CredentialRotatedAt = &now
+	switch authType {
+	case domain.DeviceAuthHMAC:
+		device.AuthType = domain.DeviceAuthHMAC
+		device.HMACSecret = secret
+		device.CredentialHash = ""
+	default:
+		device.AuthType = domain.DeviceAuthToken
+		device.CredentialHash = security.HashToken(secret)
+		device.HMACSecret = ""
+	}
+	return secret, nil
+}
+
+// setIfNotEmpty assigns value to field unless value is empty
+func setIfNotEmpty(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
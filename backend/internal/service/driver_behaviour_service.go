@@ -0,0 +1,550 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Driver behaviour errors
+var (
+	ErrDriverNotFound     = errors.New("driver not found")
+	ErrDriverAccessDenied = errors.New("drivers may only view their own behaviour")
+)
+
+const (
+	// driverBehaviourSettingsTTL bounds how long another instance's settings changes take to apply here
+	driverBehaviourSettingsTTL = time.Minute
+	// defaultBehaviourWeeks is the history returned when no range is given
+	defaultBehaviourWeeks = 12
+	// maxBehaviourRange bounds history requests
+	maxBehaviourRange = 366 * 24 * time.Hour
+)
+
+// DriverBehaviourSettingsRequest represents the scoring settings; updates replace all of them
+type DriverBehaviourSettingsRequest struct {
+	DefaultSpeedLimit        float64            `json:"default_speed_limit" validate:"required,gt=0,lte=300"`
+	SpeedLimits              map[string]float64 `json:"speed_limits" validate:"max=50,dive,keys,required,max=30,endkeys,gt=0,lte=300"`
+	SpeedingTolerance        float64            `json:"speeding_tolerance" validate:"gte=0,lte=50"`
+	HarshAccelThreshold      float64            `json:"harsh_accel_threshold" validate:"required,gt=0,lte=50"`
+	HarshBrakeThreshold      float64            `json:"harsh_brake_threshold" validate:"required,gt=0,lte=50"`
+	HarshMaxIntervalSeconds  int                `json:"harsh_max_interval_seconds" validate:"required,min=1,max=60"`
+	IdleThresholdSeconds     int                `json:"idle_threshold_seconds" validate:"min=0,max=86400"`
+	NightStartHour           int                `json:"night_start_hour" validate:"min=0,max=23"`
+	NightEndHour             int                `json:"night_end_hour" validate:"min=0,max=23"`
+	Timezone                 string             `json:"timezone" validate:"required"`
+	HarshAccelWeight         float64            `json:"harsh_accel_weight" validate:"gte=0,lte=100"`
+	HarshBrakeWeight         float64            `json:"harsh_brake_weight" validate:"gte=0,lte=100"`
+	SpeedingWeight           float64            `json:"speeding_weight" validate:"gte=0,lte=100"`
+	IdleWeight               float64            `json:"idle_weight" validate:"gte=0,lte=100"`
+	NightWeight              float64            `json:"night_weight" validate:"gte=0,lte=100"`
+	LeaderboardMinDistanceKm float64            `json:"leaderboard_min_distance_km" validate:"gte=0"`
+}
+
+// DriverBehaviourWeek is the behaviour of a driver in one week
+type DriverBehaviourWeek struct {
+	WeekStart time.Time `json:"week_start"`
+	Trips     int       `json:"trips"`
+	domain.DriverBehaviourMetrics
+	Score float64 `json:"score"`
+}
+
+// DriverBehaviourHistory is the weekly behaviour of a driver over a range of weeks
+type DriverBehaviourHistory struct {
+	DriverID uint                   `json:"driver_id"`
+	Username string                 `json:"username"`
+	Name     string                 `json:"name"`
+	From     time.Time              `json:"from"` // first week start
+	To       time.Time              `json:"to"`   // last week start
+	Weeks    []*DriverBehaviourWeek `json:"weeks"`
+	Total    *DriverBehaviourWeek   `json:"total"` // whole range; WeekStart is the first week
+}
+
+// DriverTripBehaviourList represents a page of a driver's trip behaviour
+type DriverTripBehaviourList struct {
+	Trips []*domain.DriverTripBehaviour `json:"trips"`
+	Total int64                         `json:"total"`
+	Page  int                           `json:"page"`
+	Limit int                           `json:"limit"`
+}
+
+// DriverLeaderboardEntry is the rank of one driver in a week
+type DriverLeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	DriverID uint   `json:"driver_id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	DriverBehaviourWeek
+}
+
+// DriverLeaderboard ranks drivers by their weekly score
+type DriverLeaderboard struct {
+	WeekStart      time.Time                 `json:"week_start"`
+	MinDistanceKm  float64                   `json:"min_distance_km"`
+	Entries        []*DriverLeaderboardEntry `json:"entries"`
+	BelowThreshold int                       `json:"below_threshold"` // drivers with too little distance to rank
+}
+
+// DriverBehaviourService scores driver behaviour from telematics. It is registered as a trip
+// listener: each completed trip is scored from its samples, which are attributed to the driver
+// recorded on the vehicle when they were taken. Weekly figures are sums of trip figures.
+type DriverBehaviourService struct {
+	behaviourRepo interfaces.DriverBehaviourRepository
+	vehicleRepo   interfaces.VehicleRepository
+	userRepo      interfaces.UserRepository
+	movingSpeed   float64
+	validator     *validator.Validate
+	logger        *logrus.Logger
+
+	mu       sync.Mutex
+	settings *domain.DriverBehaviourSettings
+	loadedAt time.Time
+}
+
+// NewDriverBehaviourService creates a new driver behaviour service
+func NewDriverBehaviourService(
+	behaviourRepo interfaces.DriverBehaviourRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	userRepo interfaces.UserRepository,
+	logger *logrus.Logger,
+) *DriverBehaviourService {
+	return &DriverBehaviourService{
+		behaviourRepo: behaviourRepo,
+		vehicleRepo:   vehicleRepo,
+		userRepo:      userRepo,
+		movingSpeed:   DefaultTripDetectionConfig().MovingSpeed,
+		validator:     validator.New(),
+		logger:        logger,
+	}
+}
+
+// HandleTrips scores the completed trips of a vehicle; trips in progress are scored once they complete
+func (s *DriverBehaviourService) HandleTrips(vehicleID uint, trips []*domain.Trip, samples []*domain.TelematicsData) {
+	if err := s.scoreTrips(vehicleID, trips, samples); err != nil {
+		s.logger.WithError(err).WithField("vehicle_id", vehicleID).Error("Failed to score driver behaviour")
+	}
+}
+
+func (s *DriverBehaviourService) scoreTrips(vehicleID uint, trips []*domain.Trip, samples []*domain.TelematicsData) error {
+	completed := make([]*domain.Trip, 0, len(trips))
+	for _, trip := range trips {
+		if trip.Status == domain.TripStatusCompleted {
+			completed = append(completed, trip)
+		}
+	}
+	if len(completed) == 0 {
+		return nil
+	}
+
+	settings, err := s.currentSettings()
+	if err != nil {
+		return fmt.Errorf("failed to load driver behaviour settings: %w", err)
+	}
+	vehicle, err := s.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		return fmt.Errorf("failed to get vehicle: %w", err)
+	}
+	assignments, err := s.behaviourRepo.GetAssignments(vehicleID, completed[0].StartTime, completed[len(completed)-1].EndTime)
+	if err != nil {
+		return fmt.Errorf("failed to get driver assignments: %w", err)
+	}
+
+	scorer := behaviourScorer{
+		settings:    settings,
+		location:    settings.Location(),
+		speedLimit:  settings.SpeedLimitFor(strings.ToLower(vehicle.Type)),
+		movingSpeed: s.movingSpeed,
+		assignments: assignments,
+	}
+	tripIDs := make([]uint, 0, len(completed))
+	var behaviours []*domain.DriverTripBehaviour
+	for _, trip := range completed {
+		tripIDs = append(tripIDs, trip.ID)
+		start := sort.Search(len(samples), func(i int) bool { return !samples[i].Timestamp.Before(trip.StartTime) })
+		end := sort.Search(len(samples), func(i int) bool { return samples[i].Timestamp.After(trip.EndTime) })
+		behaviours = append(behaviours, scorer.score(trip, samples[start:end])...)
+	}
+
+	if err := s.behaviourRepo.ReplaceTripBehaviours(vehicleID, tripIDs, behaviours); err != nil {
+		return fmt.Errorf("failed to store trip behaviour: %w", err)
+	}
+	return nil
+}
+
+// GetHistory returns the weekly behaviour of a driver for the weeks touching [from, to]
+func (s *DriverBehaviourService) GetHistory(viewer Viewer, driverID uint, from, to *time.Time) (*DriverBehaviourHistory, error) {
+	driver, err := s.checkDriverAccess(viewer, driverID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.currentSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load driver behaviour settings: %w", err)
+	}
+
+	location := settings.Location()
+	last := domain.WeekStart(time.Now(), location)
+	if to != nil {
+		last = domain.WeekStart(*to, location)
+	}
+	first := last.AddDate(0, 0, -7*(defaultBehaviourWeeks-1))
+	if from != nil {
+		first = domain.WeekStart(*from, location)
+	}
+	if last.Before(first) || last.Sub(first) > maxBehaviourRange {
+		return nil, fmt.Errorf("%w: to must not be before from and the range at most %d days",
+			ErrInvalidTimeRange, int(maxBehaviourRange.Hours()/24))
+	}
+
+	totals, err := s.behaviourRepo.WeeklyTotals(driverID, first, last)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly behaviour: %w", err)
+	}
+
+	history := &DriverBehaviourHistory{
+		DriverID: driver.ID,
+		Username: driver.Username,
+		Name:     driverName(driver),
+		From:     first,
+		To:       last,
+		Weeks:    make([]*DriverBehaviourWeek, 0, len(totals)),
+		Total:    &DriverBehaviourWeek{WeekStart: first},
+	}
+	for _, week := range totals {
+		history.Weeks = append(history.Weeks, newBehaviourWeek(week, settings))
+		history.Total.Trips += week.Trips
+		history.Total.Add(week.DriverBehaviourMetrics)
+	}
+	history.Total.Score = history.Total.DriverBehaviourMetrics.Score(settings)
+	return history, nil
+}
+
+// ListTrips lists the scored trips of a driver starting in [from, to], newest first
+func (s *DriverBehaviourService) ListTrips(viewer Viewer, driverID uint, from, to time.Time, page, limit, offset int) (*DriverTripBehaviourList, error) {
+	if _, err := s.checkDriverAccess(viewer, driverID); err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", ErrInvalidTimeRange)
+	}
+
+	trips, total, err := s.behaviourRepo.ListTripBehaviours(driverID, from, to, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trip behaviour: %w", err)
+	}
+	return &DriverTripBehaviourList{Trips: trips, Total: total, Page: page, Limit: limit}, nil
+}
+
+// GetLeaderboard ranks the drivers of the week containing day, best score first. Drivers who
+// drove less than the minimum distance are left out, as a few kilometres say little.
+func (s *DriverBehaviourService) GetLeaderboard(day *time.Time) (*DriverLeaderboard, error) {
+	settings, err := s.currentSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load driver behaviour settings: %w", err)
+	}
+	weekStart := domain.WeekStart(time.Now(), settings.Location())
+	if day != nil {
+		// A date names a calendar day, not an instant in the settings timezone
+		weekStart = domain.WeekStart(*day, time.UTC)
+	}
+
+	totals, err := s.behaviourRepo.WeeklyTotals(0, weekStart, weekStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly behaviour: %w", err)
+	}
+
+	leaderboard := &DriverLeaderboard{
+		WeekStart:     weekStart,
+		MinDistanceKm: settings.LeaderboardMinDistanceKm,
+		Entries:       []*DriverLeaderboardEntry{},
+	}
+	for _, week := range totals {
+		if week.DistanceKm < settings.LeaderboardMinDistanceKm {
+			leaderboard.BelowThreshold++
+			continue
+		}
+		entry := &DriverLeaderboardEntry{DriverID: week.DriverID, DriverBehaviourWeek: *newBehaviourWeek(week, settings)}
+		if driver, err := s.userRepo.GetByID(week.DriverID); err == nil {
+			entry.Username = driver.Username
+			entry.Name = driverName(driver)
+		} else if !isNotFound(err) {
+			return nil, fmt.Errorf("failed to get driver: %w", err)
+		}
+		leaderboard.Entries = append(leaderboard.Entries, entry)
+	}
+	sort.SliceStable(leaderboard.Entries, func(i, j int) bool {
+		a, b := leaderboard.Entries[i], leaderboard.Entries[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.DistanceKm > b.DistanceKm
+	})
+	for i, entry := range leaderboard.Entries {
+		entry.Rank = i + 1
+		if i > 0 && entry.Score == leaderboard.Entries[i-1].Score {
+			entry.Rank = leaderboard.Entries[i-1].Rank
+		}
+	}
+	return leaderboard, nil
+}
+
+// GetSettings returns the scoring settings
+func (s *DriverBehaviourService) GetSettings() (*domain.DriverBehaviourSettings, error) {
+	settings, err := s.behaviourRepo.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get driver behaviour settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings replaces the scoring settings. Trips are scored with the settings current at
+// the time; rebuild trips to rescore history. Weekly scores always use the current weights.
+func (s *DriverBehaviourService) UpdateSettings(req *DriverBehaviourSettingsRequest, updatedBy uint) (*domain.DriverBehaviourSettings, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, fmt.Errorf("validation failed: unknown timezone %q", req.Timezone)
+	}
+
+	settings, err := s.behaviourRepo.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get driver behaviour settings: %w", err)
+	}
+	speedLimits := make(domain.SpeedLimitMap, len(req.SpeedLimits))
+	for vehicleType, limit := range req.SpeedLimits {
+		speedLimits[strings.ToLower(strings.TrimSpace(vehicleType))] = limit
+	}
+	settings.DefaultSpeedLimit = req.DefaultSpeedLimit
+	settings.SpeedLimits = speedLimits
+	settings.SpeedingTolerance = req.SpeedingTolerance
+	settings.HarshAccelThreshold = req.HarshAccelThreshold
+	settings.HarshBrakeThreshold = req.HarshBrakeThreshold
+	settings.HarshMaxIntervalSeconds = req.HarshMaxIntervalSeconds
+	settings.IdleThresholdSeconds = req.IdleThresholdSeconds
+	settings.NightStartHour = req.NightStartHour
+	settings.NightEndHour = req.NightEndHour
+	settings.Timezone = req.Timezone
+	settings.HarshAccelWeight = req.HarshAccelWeight
+	settings.HarshBrakeWeight = req.HarshBrakeWeight
+	settings.SpeedingWeight = req.SpeedingWeight
+	settings.IdleWeight = req.IdleWeight
+	settings.NightWeight = req.NightWeight
+	settings.LeaderboardMinDistanceKm = req.LeaderboardMinDistanceKm
+	settings.UpdatedBy = &updatedBy
+
+	if err := s.behaviourRepo.UpdateSettings(settings); err != nil {
+		return nil, fmt.Errorf("failed to update driver behaviour settings: %w", err)
+	}
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+	return settings, nil
+}
+
+// currentSettings returns the cached settings, reloading them when stale
+func (s *DriverBehaviourService) currentSettings() (*domain.DriverBehaviourSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) < driverBehaviourSettingsTTL {
+		return s.settings, nil
+	}
+	settings, err := s.behaviourRepo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	s.settings, s.loadedAt = settings, time.Now()
+	return settings, nil
+}
+
+// checkDriverAccess verifies the driver exists and that drivers only look at themselves
+func (s *DriverBehaviourService) checkDriverAccess(viewer Viewer, driverID uint) (*domain.User, error) {
+	if viewer.IsDriver() && viewer.UserID != driverID {
+		return nil, ErrDriverAccessDenied
+	}
+	driver, err := s.userRepo.GetByID(driverID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrDriverNotFound
+		}
+		return nil, fmt.Errorf("failed to get driver: %w", err)
+	}
+	return driver, nil
+}
+
+func newBehaviourWeek(totals *interfaces.DriverBehaviourTotals, settings *domain.DriverBehaviourSettings) *DriverBehaviourWeek {
+	return &DriverBehaviourWeek{
+		WeekStart:              totals.WeekStart,
+		Trips:                  totals.Trips,
+		DriverBehaviourMetrics: totals.DriverBehaviourMetrics,
+		Score:                  totals.DriverBehaviourMetrics.Score(settings),
+	}
+}
+
+func driverName(user *domain.User) string {
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
+
+// behaviourScorer computes the behaviour metrics of trips of one vehicle
+type behaviourScorer struct {
+	settings    *domain.DriverBehaviourSettings
+	location    *time.Location
+	speedLimit  float64
+	movingSpeed float64
+	assignments []*domain.VehicleDriverAssignment
+}
+
+// behaviourSegment accumulates the part of a trip driven under one assignment
+type behaviourSegment struct {
+	assignment *domain.VehicleDriverAssignment
+	start, end time.Time
+	weight     float64 // share of the trip distance, from speed over time
+	metrics    domain.DriverBehaviourMetrics
+	// Durations are summed unrounded
+	driving, night, speeding float64
+}
+
+// score computes the behaviour of each driver of a trip. Each interval between consecutive
+// samples is attributed to the driver assigned at its first sample, as in trip summaries.
+func (b *behaviourScorer) score(trip *domain.Trip, samples []*domain.TelematicsData) []*domain.DriverTripBehaviour {
+	if len(samples) < 2 {
+		return nil
+	}
+
+	var segments []*behaviourSegment
+	segmentFor := func(t time.Time) *behaviourSegment {
+		assignment := b.assignmentAt(t)
+		for _, segment := range segments {
+			if segment.assignment == assignment {
+				return segment
+			}
+		}
+		segment := &behaviourSegment{assignment: assignment, start: t, end: t}
+		segments = append(segments, segment)
+		return segment
+	}
+
+	speedingThreshold := b.speedLimit + b.settings.SpeedingTolerance
+	maxHarshInterval := float64(b.settings.HarshMaxIntervalSeconds)
+	var speeding, accelerating, braking bool
+	var idleSeconds float64
+	var idleSegment *behaviourSegment
+	flushIdle := func() {
+		if excess := idleSeconds - float64(b.settings.IdleThresholdSeconds); idleSegment != nil && excess > 0 {
+			idleSegment.metrics.ExcessiveIdleSeconds += int(excess)
+		}
+		idleSeconds, idleSegment = 0, nil
+	}
+
+	for i := 1; i < len(samples); i++ {
+		prev, sample := samples[i-1], samples[i]
+		dt := sample.Timestamp.Sub(prev.Timestamp).Seconds()
+		if dt <= 0 {
+			continue
+		}
+		segment := segmentFor(prev.Timestamp)
+		segment.end = sample.Timestamp
+		segment.weight += (prev.Speed + sample.Speed) / 2 * dt
+
+		moving := prev.Speed >= b.movingSpeed
+		if moving {
+			segment.driving += dt
+			if b.settings.IsNight(prev.Timestamp.In(b.location)) {
+				segment.night += dt
+			}
+		}
+
+		if prev.Speed > speedingThreshold {
+			segment.speeding += dt
+			if !speeding {
+				segment.metrics.SpeedingEvents++
+			}
+			speeding = true
+		} else {
+			speeding = false
+		}
+
+		// Consecutive intervals over a threshold are one event
+		acceleration := (sample.Speed - prev.Speed) / dt
+		judged := dt <= maxHarshInterval
+		if judged && acceleration >= b.settings.HarshAccelThreshold {
+			if !accelerating {
+				segment.metrics.HarshAccelerations++
+			}
+			accelerating = true
+		} else {
+			accelerating = false
+		}
+		if judged && -acceleration >= b.settings.HarshBrakeThreshold {
+			if !braking {
+				segment.metrics.HarshBrakings++
+			}
+			braking = true
+		} else {
+			braking = false
+		}
+
+		if !moving && prev.EngineStatus != domain.EngineStatusOff {
+			if idleSegment == nil {
+				idleSegment = segment
+			}
+			idleSeconds += dt
+		} else {
+			flushIdle()
+		}
+	}
+	flushIdle()
+
+	var totalWeight float64
+	for _, segment := range segments {
+		totalWeight += segment.weight
+	}
+	behaviours := make([]*domain.DriverTripBehaviour, 0, len(segments))
+	for i, segment := range segments {
+		segment.metrics.DrivingSeconds = int(segment.driving)
+		segment.metrics.NightSeconds = int(segment.night)
+		segment.metrics.SpeedingSeconds = int(segment.speeding)
+		switch {
+		case totalWeight > 0:
+			segment.metrics.DistanceKm = trip.DistanceKm * segment.weight / totalWeight
+		case i == 0:
+			segment.metrics.DistanceKm = trip.DistanceKm
+		}
+
+		behaviour := &domain.DriverTripBehaviour{
+			TripID:                 trip.ID,
+			VehicleID:              trip.VehicleID,
+			StartTime:              segment.start,
+			EndTime:                segment.end,
+			WeekStart:              domain.WeekStart(segment.start, b.location),
+			DriverBehaviourMetrics: segment.metrics,
+			Score:                  segment.metrics.Score(b.settings),
+		}
+		if segment.assignment != nil {
+			behaviour.DriverID = segment.assignment.DriverID
+			behaviour.AssignedTo = segment.assignment.AssignedTo
+		}
+		behaviours = append(behaviours, behaviour)
+	}
+	return behaviours
+}
+
+// assignmentAt returns the assignment current at t, or nil when the vehicle had no recorded assignee
+func (b *behaviourScorer) assignmentAt(t time.Time) *domain.VehicleDriverAssignment {
+	for _, assignment := range b.assignments {
+		if assignment.Covers(t) {
+			return assignment
+		}
+	}
+	return nil
+}
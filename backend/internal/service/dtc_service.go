@@ -0,0 +1,458 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/pkg/dtc"
+)
+
+// DTC errors
+var (
+	ErrDTCRuleNotFound = errors.New("dtc severity rule not found")
+	ErrDTCRuleExists   = errors.New("a dtc severity rule with this pattern already exists")
+)
+
+const (
+	// dtcRuleCacheTTL bounds how long another instance's rule changes take to apply here
+	dtcRuleCacheTTL = time.Minute
+	// dtcDefaultSeverity classifies codes no rule matches
+	dtcDefaultSeverity = domain.DTCSeverityWarning
+)
+
+// DTCReportRequest is the full set of codes a vehicle reports as active at one moment.
+// Codes missing from a report are resolved, so an empty list clears all codes.
+type DTCReportRequest struct {
+	ReportedAt time.Time `json:"reported_at" validate:"required"`
+	Codes      []string  `json:"codes" validate:"max=100"`
+}
+
+// DTCReportResult summarizes how a report changed a vehicle's codes
+type DTCReportResult struct {
+	VehicleID    uint              `json:"vehicle_id"`
+	Opened       []*domain.DTCCode `json:"opened"`
+	Resolved     []*domain.DTCCode `json:"resolved"`
+	Active       int               `json:"active"`
+	WorkOrderIDs []uint            `json:"work_order_ids"`
+}
+
+// DTCSeverityRuleRequest represents a severity rule; updates replace the whole rule
+type DTCSeverityRuleRequest struct {
+	Pattern          string `json:"pattern" validate:"required,max=10"`
+	Severity         string `json:"severity" validate:"required,oneof=info warning error critical"`
+	WorkOrderType    string `json:"work_order_type" validate:"omitempty,oneof=emergency repair"`
+	RecipientUserIDs []uint `json:"recipient_user_ids" validate:"max=50"`
+	Description      string `json:"description"`
+	IsActive         *bool  `json:"is_active"`
+}
+
+// DTCCodeList represents a page of a vehicle's diagnostic trouble codes
+type DTCCodeList struct {
+	Codes []*domain.DTCCode `json:"codes"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+}
+
+// DTCDefinition describes a code from the embedded dictionary and the rule that classifies it
+type DTCDefinition struct {
+	Code        string                  `json:"code"`
+	System      string                  `json:"system"`
+	Generic     bool                    `json:"generic"`
+	Known       bool                    `json:"known"` // whether the dictionary lists the code
+	Description string                  `json:"description"`
+	Severity    string                  `json:"severity"`
+	Rule        *domain.DTCSeverityRule `json:"rule"`
+}
+
+// DTCService processes diagnostic trouble code reports. Reported codes are enriched from the
+// SAE J2012 dictionary and classified by severity rules; critical codes open one work order
+// per vehicle and code until that work order is completed or cancelled.
+type DTCService struct {
+	dtcRepo             interfaces.DTCRepository
+	deviceRepo          interfaces.DeviceRepository
+	vehicleRepo         interfaces.VehicleRepository
+	userRepo            interfaces.UserRepository
+	roleRepo            interfaces.RoleRepository
+	notificationService *NotificationService
+	validator           *validator.Validate
+	logger              *logrus.Logger
+
+	mu       sync.Mutex
+	rules    []*domain.DTCSeverityRule
+	loadedAt time.Time
+}
+
+// NewDTCService creates a new DTC service
+func NewDTCService(
+	dtcRepo interfaces.DTCRepository,
+	deviceRepo interfaces.DeviceRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	userRepo interfaces.UserRepository,
+	roleRepo interfaces.RoleRepository,
+	notificationService *NotificationService,
+	logger *logrus.Logger,
+) *DTCService {
+	return &DTCService{
+		dtcRepo:             dtcRepo,
+		deviceRepo:          deviceRepo,
+		vehicleRepo:         vehicleRepo,
+		userRepo:            userRepo,
+		roleRepo:            roleRepo,
+		notificationService: notificationService,
+		validator:           validator.New(),
+		logger:              logger,
+	}
+}
+
+// Report processes a report from an authenticated device. The codes are attributed to the
+// vehicle the device was bound to when the report was taken.
+func (s *DTCService) Report(device *domain.TelematicsDevice, req *DTCReportRequest) (*DTCReportResult, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	reportedAt := req.ReportedAt.UTC()
+	now := time.Now().UTC()
+	if reportedAt.After(now.Add(maxSampleFutureSkew)) {
+		return nil, fmt.Errorf("validation failed: reported_at is in the future")
+	}
+	if reportedAt.Before(now.Add(-maxSampleAge)) {
+		return nil, fmt.Errorf("validation failed: reported_at is too old")
+	}
+	bindings, err := s.deviceRepo.GetBindingsInRange(device.ID, reportedAt, reportedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device bindings: %w", err)
+	}
+	var vehicleID uint
+	for _, binding := range bindings {
+		if binding.Covers(reportedAt) {
+			vehicleID = binding.VehicleID
+			break
+		}
+	}
+	if vehicleID == 0 {
+		return nil, ErrDeviceBindingNotFound
+	}
+
+	return s.ProcessReport(vehicleID, reportedAt, req.Codes)
+}
+
+// ProcessReport reconciles a vehicle's active codes with the codes it reported at reportedAt
+func (s *DTCService) ProcessReport(vehicleID uint, reportedAt time.Time, rawCodes []string) (*DTCReportResult, error) {
+	rules, err := s.activeRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dtc severity rules: %w", err)
+	}
+
+	seen := make(map[string]bool, len(rawCodes))
+	codes := make([]*domain.DTCCode, 0, len(rawCodes))
+	for _, raw := range rawCodes {
+		code, err := dtc.Normalize(raw)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		severity, _ := classifyDTC(rules, code)
+		codes = append(codes, &domain.DTCCode{
+			Code:        code,
+			Severity:    severity,
+			Description: dtc.Describe(code),
+		})
+	}
+
+	changes, err := s.dtcRepo.ApplyReport(vehicleID, reportedAt, codes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply dtc report: %w", err)
+	}
+
+	result := &DTCReportResult{
+		VehicleID:    vehicleID,
+		Opened:       changes.Opened,
+		Resolved:     changes.Resolved,
+		Active:       len(changes.Opened) + len(changes.Updated),
+		WorkOrderIDs: []uint{},
+	}
+	if result.Opened == nil {
+		result.Opened = []*domain.DTCCode{}
+	}
+	if result.Resolved == nil {
+		result.Resolved = []*domain.DTCCode{}
+	}
+
+	// Codes already active are included so work orders that failed to open earlier are retried
+	candidates := make([]*domain.DTCCode, 0, len(changes.Opened)+len(changes.Updated))
+	candidates = append(append(candidates, changes.Opened...), changes.Updated...)
+	for _, code := range candidates {
+		if code.Severity != domain.DTCSeverityCritical || code.WorkOrderID != nil {
+			continue
+		}
+		_, rule := classifyDTC(rules, code.Code)
+		workOrderID, err := s.openWorkOrder(code, rule)
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"vehicle_id": vehicleID,
+				"code":       code.Code,
+			}).Error("Failed to open work order for critical DTC")
+			continue
+		}
+		result.WorkOrderIDs = append(result.WorkOrderIDs, workOrderID)
+	}
+
+	if len(changes.Opened) > 0 || len(changes.Resolved) > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"vehicle_id": vehicleID,
+			"opened":     len(changes.Opened),
+			"resolved":   len(changes.Resolved),
+		}).Info("DTC report processed")
+	}
+	return result, nil
+}
+
+// ListByVehicle retrieves a page of a vehicle's codes; active filters by status when set
+func (s *DTCService) ListByVehicle(viewer Viewer, vehicleID uint, active *bool, page, limit, offset int) (*DTCCodeList, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+	codes, total, err := s.dtcRepo.ListByVehicle(vehicleID, active, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dtc codes: %w", err)
+	}
+	return &DTCCodeList{Codes: codes, Total: total, Page: page, Limit: limit}, nil
+}
+
+// Describe returns the dictionary entry and severity classification of a code
+func (s *DTCService) Describe(rawCode string) (*DTCDefinition, error) {
+	code, err := dtc.Normalize(rawCode)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	rules, err := s.activeRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dtc severity rules: %w", err)
+	}
+	_, known := dtc.Lookup(code)
+	severity, rule := classifyDTC(rules, code)
+	return &DTCDefinition{
+		Code:        code,
+		System:      dtc.System(code),
+		Generic:     dtc.IsGeneric(code),
+		Known:       known,
+		Description: dtc.Describe(code),
+		Severity:    severity,
+		Rule:        rule,
+	}, nil
+}
+
+// CreateRule creates a severity rule
+func (s *DTCService) CreateRule(req *DTCSeverityRuleRequest, createdBy uint) (*domain.DTCSeverityRule, error) {
+	rule := &domain.DTCSeverityRule{IsActive: true, CreatedBy: &createdBy}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.dtcRepo.CreateRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to create dtc severity rule: %w", err)
+	}
+	s.invalidateRules()
+	return rule, nil
+}
+
+// ListRules retrieves every severity rule
+func (s *DTCService) ListRules() ([]*domain.DTCSeverityRule, error) {
+	rules, err := s.dtcRepo.ListRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dtc severity rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateRule replaces a severity rule. Severities of active codes are refreshed on their next report.
+func (s *DTCService) UpdateRule(id uint, req *DTCSeverityRuleRequest) (*domain.DTCSeverityRule, error) {
+	rule, err := s.dtcRepo.GetRule(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrDTCRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get dtc severity rule: %w", err)
+	}
+	if err := s.applyRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.dtcRepo.UpdateRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to update dtc severity rule: %w", err)
+	}
+	s.invalidateRules()
+	return rule, nil
+}
+
+// DeleteRule deletes a severity rule
+func (s *DTCService) DeleteRule(id uint) error {
+	if err := s.dtcRepo.DeleteRule(id); err != nil {
+		if isNotFound(err) {
+			return ErrDTCRuleNotFound
+		}
+		return fmt.Errorf("failed to delete dtc severity rule: %w", err)
+	}
+	s.invalidateRules()
+	return nil
+}
+
+// openWorkOrder links a critical code to the vehicle's open work order for that code,
+// opening one when there is none, and notifies the responsible users about new work orders
+func (s *DTCService) openWorkOrder(code *domain.DTCCode, rule *domain.DTCSeverityRule) (uint, error) {
+	vehicle, err := s.vehicleRepo.GetByID(code.VehicleID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+	advisorID, err := workOrderAdvisor(s.roleRepo, s.userRepo)
+	if err != nil {
+		return 0, err
+	}
+
+	serviceType, priority := domain.ServiceTypeRepair, domain.PriorityCritical
+	if rule != nil && rule.WorkOrderType == domain.ServiceTypeEmergency {
+		serviceType, priority = domain.ServiceTypeEmergency, domain.PriorityEmergency
+	}
+	workOrder := &domain.WorkOrder{
+		CustomerName:     internalCustomerName(vehicle),
+		VehicleID:        vehicle.ID,
+		ServiceType:      serviceType,
+		Priority:         priority,
+		Status:           domain.StatusPending,
+		Description:      fmt.Sprintf("Critical diagnostic trouble code %s: %s", code.Code, code.Description),
+		ServiceAdvisorID: advisorID,
+		Notes:            fmt.Sprintf("Opened automatically, first reported at %s.", code.FirstSeen.UTC().Format(time.RFC3339)),
+	}
+	workOrderID, err := s.dtcRepo.LinkWorkOrder(code, workOrder)
+	if err != nil {
+		return 0, fmt.Errorf("failed to link work order: %w", err)
+	}
+	code.WorkOrderID = &workOrderID
+	if workOrder.ID == 0 {
+		return workOrderID, nil // the code already has an open work order
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"vehicle_id":    vehicle.ID,
+		"code":          code.Code,
+		"work_order_id": workOrder.ID,
+	}).Info("Work order opened for critical DTC")
+
+	recipients := []uint{advisorID}
+	if rule != nil {
+		recipients = append(recipients, rule.RecipientUserIDs...)
+	}
+	err = s.notificationService.Notify(recipients, NotificationMessage{
+		Type:          "dtc_critical",
+		Title:         fmt.Sprintf("Critical DTC %s on %s", code.Code, vehicle.PlateNumber),
+		Message:       fmt.Sprintf("%s reported %s (%s). Work order %s was opened.", vehicle.PlateNumber, code.Code, code.Description, workOrder.WONumber),
+		ReferenceType: "work_order",
+		ReferenceID:   workOrder.ID,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("work_order_id", workOrder.ID).Error("Failed to send critical DTC notification")
+	}
+	return workOrder.ID, nil
+}
+
+// activeRules returns the cached active severity rules, reloading them when stale
+func (s *DTCService) activeRules() ([]*domain.DTCSeverityRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) < dtcRuleCacheTTL {
+		return s.rules, nil
+	}
+	rules, err := s.dtcRepo.ListActiveRules()
+	if err != nil {
+		return nil, err
+	}
+	s.rules, s.loadedAt = rules, time.Now()
+	return rules, nil
+}
+
+func (s *DTCService) invalidateRules() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// applyRuleRequest validates a severity rule request and copies it onto the rule
+func (s *DTCService) applyRuleRequest(rule *domain.DTCSeverityRule, req *DTCSeverityRuleRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	pattern := strings.ToUpper(strings.TrimSpace(req.Pattern))
+	prefix, wildcard := strings.CutSuffix(pattern, "*")
+	if wildcard {
+		if prefix == "" || strings.Contains(prefix, "*") || len(prefix) > 4 {
+			return fmt.Errorf("validation failed: pattern %q must be a code or a 1-4 character prefix followed by *", req.Pattern)
+		}
+		if dtc.System(prefix) == "" {
+			return fmt.Errorf("validation failed: pattern %q must start with P, C, B or U", req.Pattern)
+		}
+	} else if _, err := dtc.Normalize(pattern); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	existing, err := s.dtcRepo.ListRules()
+	if err != nil {
+		return fmt.Errorf("failed to list dtc severity rules: %w", err)
+	}
+	for _, other := range existing {
+		if other.Pattern == pattern && other.ID != rule.ID {
+			return ErrDTCRuleExists
+		}
+	}
+
+	recipients := uniqueUints(req.RecipientUserIDs)
+	for _, userID := range recipients {
+		if _, err := s.userRepo.GetByID(userID); err != nil {
+			if isNotFound(err) {
+				return fmt.Errorf("validation failed: user %d not found", userID)
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+	}
+
+	rule.Pattern = pattern
+	rule.Severity = req.Severity
+	rule.WorkOrderType = req.WorkOrderType
+	if rule.WorkOrderType == "" {
+		rule.WorkOrderType = domain.ServiceTypeRepair
+	}
+	rule.RecipientUserIDs = domain.UintList(recipients)
+	rule.Description = req.Description
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// classifyDTC returns the severity of a code and the most specific active rule that matched it
+func classifyDTC(rules []*domain.DTCSeverityRule, code string) (string, *domain.DTCSeverityRule) {
+	var best *domain.DTCSeverityRule
+	for _, rule := range rules {
+		if !rule.Matches(code) {
+			continue
+		}
+		if best == nil || rule.Specificity() > best.Specificity() {
+			best = rule
+		}
+	}
+	if best == nil {
+		return dtcDefaultSeverity, nil
+	}
+	return best.Severity, best
+}
@@ -0,0 +1,652 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/pkg/security"
+)
+
+// maxSignatureBytes bounds the decoded size of a captured signature image
+const maxSignatureBytes = 256 * 1024
+
+// signatureImageTypes maps the data URL prefixes accepted for captured signatures to the content
+// type their data must have. Only raster images are accepted: an SVG could carry script.
+var signatureImageTypes = map[string]string{
+	"data:image/png;base64,":  "image/png",
+	"data:image/jpeg;base64,": "image/jpeg",
+}
+
+// Estimate service errors
+var (
+	ErrEstimateNotFound    = errors.New("estimate not found")
+	ErrEstimateNotDraft    = errors.New("only draft estimates can be changed")
+	ErrEstimateNotSendable = errors.New("only draft or sent estimates can be sent")
+	ErrEstimateAnswered    = errors.New("estimate is no longer waiting for the customer")
+	ErrEstimateLinkExpired = errors.New("estimate approval link has expired")
+	ErrEstimateExceeded    = errors.New("work exceeds the approved estimate, a revised estimate must be approved first")
+)
+
+// EstimateConfig prices estimates and controls their approval links
+type EstimateConfig struct {
+	DefaultLaborRate float64       // hourly rate of labor lines without a price when the mechanic has none
+	TaxRate          float64       // percent, unless the estimate sets its own
+	LinkValidity     time.Duration // how long approval links can be used
+	ApprovalURL      string        // base URL the approval token is appended to
+}
+
+// EstimateLineRequest represents a labor or part line of an estimate. Part lines are priced
+// from the inventory item; labor lines from their catalogued operation or the mechanic's hourly rate.
+type EstimateLineRequest struct {
+	LineType        string   `json:"line_type" validate:"required,oneof=labor part"`
+	Description     string   `json:"description" validate:"max=200"`        // defaults to the item or operation name
+	InventoryItemID *uint    `json:"inventory_item_id"`                     // required on part lines
+	OperationID     *uint    `json:"operation_id"`                          // catalogued labor operation of labor lines
+	Quantity        float64  `json:"quantity" validate:"min=0,max=10000"`   // defaults to the operation's standard hours
+	UnitPrice       *float64 `json:"unit_price" validate:"omitempty,min=0"` // hourly rate of labor lines
+	DiscountPercent float64  `json:"discount_percent" validate:"min=0,max=100"`
+}
+
+// EstimateRequest represents the lines and pricing of an estimate version
+type EstimateRequest struct {
+	Lines          []EstimateLineRequest `json:"lines" validate:"required,min=1,max=200,dive"`
+	DiscountAmount float64               `json:"discount_amount" validate:"min=0"`
+	TaxRate        *float64              `json:"tax_rate" validate:"omitempty,min=0,max=100"` // defaults to the workshop tax rate
+	Notes          string                `json:"notes" validate:"max=2000"`
+}
+
+// EstimateLineDecision is the customer's decision on one estimate line
+type EstimateLineDecision struct {
+	LineID   uint `json:"line_id" validate:"required"`
+	Approved bool `json:"approved"`
+}
+
+// EstimateResponseRequest is the customer's signed answer to an estimate
+type EstimateResponseRequest struct {
+	Decisions  []EstimateLineDecision `json:"decisions" validate:"required,min=1,dive"`
+	SignerName string                 `json:"signer_name" validate:"required,max=200"`
+	Signature  string                 `json:"signature" validate:"required"` // PNG or JPEG image data URL
+	Comment    string                 `json:"comment" validate:"max=2000"`
+}
+
+// EstimateLink is a sent estimate with the link the customer answers it through
+type EstimateLink struct {
+	Estimate    *domain.WorkOrderEstimate `json:"estimate"`
+	ApprovalURL string                    `json:"approval_url"`
+	ExpiresAt   time.Time                 `json:"expires_at"`
+}
+
+// CustomerEstimate is what the customer sees through the approval link
+type CustomerEstimate struct {
+	WONumber     string                    `json:"wo_number"`
+	CustomerName string                    `json:"customer_name"`
+	Description  string                    `json:"description"`
+	Estimate     *domain.WorkOrderEstimate `json:"estimate"`
+}
+
+// EstimateService builds versioned cost estimates for work orders and collects the customer's
+// signed approval. Once an estimate has been sent, labor and parts on the work order are held to
+// the approved amount.
+type EstimateService struct {
+	estimateRepo     interfaces.EstimateRepository
+	workOrderRepo    interfaces.WorkOrderRepository
+	inventoryRepo    interfaces.InventoryRepository
+	mechanicRepo     interfaces.MechanicRepository
+	workOrderService *WorkOrderService
+	operationService *LaborOperationService
+	config           EstimateConfig
+	validator        *validator.Validate
+	logger           *logrus.Logger
+}
+
+// NewEstimateService creates a new estimate service
+func NewEstimateService(
+	estimateRepo interfaces.EstimateRepository,
+	workOrderRepo interfaces.WorkOrderRepository,
+	inventoryRepo interfaces.InventoryRepository,
+	mechanicRepo interfaces.MechanicRepository,
+	workOrderService *WorkOrderService,
+	operationService *LaborOperationService,
+	config EstimateConfig,
+	logger *logrus.Logger,
+) *EstimateService {
+	if config.LinkValidity <= 0 {
+		config.LinkValidity = 14 * 24 * time.Hour
+	}
+	return &EstimateService{
+		estimateRepo:     estimateRepo,
+		workOrderRepo:    workOrderRepo,
+		inventoryRepo:    inventoryRepo,
+		mechanicRepo:     mechanicRepo,
+		workOrderService: workOrderService,
+		operationService: operationService,
+		config:           config,
+		validator:        validator.New(),
+		logger:           logger,
+	}
+}
+
+// List returns the estimates of a work order, newest version first
+func (s *EstimateService) List(viewer Viewer, workOrderID uint) ([]*domain.WorkOrderEstimate, error) {
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	estimates, err := s.estimateRepo.ListByWorkOrder(workOrder.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list estimates: %w", err)
+	}
+	return estimates, nil
+}
+
+// Get returns an estimate of a work order
+func (s *EstimateService) Get(viewer Viewer, workOrderID, id uint) (*domain.WorkOrderEstimate, error) {
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.getEstimate(workOrder.ID, id)
+}
+
+// Create drafts the next estimate version of a work order. Versions still in draft or waiting
+// for the customer are superseded.
+func (s *EstimateService) Create(viewer Viewer, workOrderID uint, req *EstimateRequest) (*domain.WorkOrderEstimate, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+
+	estimate := &domain.WorkOrderEstimate{
+		WorkOrderID: workOrder.ID,
+		Status:      domain.EstimateStatusDraft,
+		CreatedBy:   viewer.UserID,
+	}
+	if err := s.price(estimate, workOrder, req); err != nil {
+		return nil, err
+	}
+	if err := s.estimateRepo.Create(estimate); err != nil {
+		if isNotFound(err) {
+			return nil, ErrWorkOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to create estimate: %w", err)
+	}
+	return s.getEstimate(workOrder.ID, estimate.ID)
+}
+
+// Update replaces the lines and pricing of a draft estimate
+func (s *EstimateService) Update(viewer Viewer, workOrderID, id uint, req *EstimateRequest) (*domain.WorkOrderEstimate, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Price outside the lock; the lines replace the current ones
+	priced := &domain.WorkOrderEstimate{}
+	if err := s.price(priced, workOrder, req); err != nil {
+		return nil, err
+	}
+	return s.save(workOrder.ID, id, func(estimate *domain.WorkOrderEstimate) error {
+		if estimate.Status != domain.EstimateStatusDraft {
+			return ErrEstimateNotDraft
+		}
+		estimate.Lines = priced.Lines
+		estimate.DiscountAmount = priced.DiscountAmount
+		estimate.TaxRate = priced.TaxRate
+		estimate.Notes = priced.Notes
+		estimate.Recalculate()
+		return nil
+	})
+}
+
+// Send issues a new approval link for a draft or sent estimate. Sending again replaces the
+// previous link.
+func (s *EstimateService) Send(viewer Viewer, workOrderID, id uint) (*EstimateLink, error) {
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+	token, err := approvalToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate approval token: %w", err)
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.config.LinkValidity)
+	estimate, err := s.save(workOrder.ID, id, func(estimate *domain.WorkOrderEstimate) error {
+		if estimate.Status != domain.EstimateStatusDraft && estimate.Status != domain.EstimateStatusSent {
+			return ErrEstimateNotSendable
+		}
+		estimate.Status = domain.EstimateStatusSent
+		tokenHash := security.HashToken(token)
+		estimate.ApprovalTokenHash = &tokenHash
+		estimate.TokenExpiresAt = &expiresAt
+		estimate.SentAt = &now
+		estimate.SentBy = &viewer.UserID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EstimateLink{
+		Estimate:    estimate,
+		ApprovalURL: strings.TrimRight(s.config.ApprovalURL, "/") + "/" + token,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// GetForCustomer returns the estimate behind an approval link
+func (s *EstimateService) GetForCustomer(token string) (*CustomerEstimate, error) {
+	estimate, err := s.estimateRepo.GetByTokenHash(security.HashToken(token))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrEstimateNotFound
+		}
+		return nil, fmt.Errorf("failed to get estimate: %w", err)
+	}
+	if estimate.Status == domain.EstimateStatusSent && linkExpired(estimate, time.Now().UTC()) {
+		return nil, ErrEstimateLinkExpired
+	}
+	workOrder, err := s.workOrderRepo.GetByID(estimate.WorkOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work order: %w", err)
+	}
+
+	estimate.Signature, estimate.SignerIP, estimate.SignerUserAgent = "", "", ""
+	return &CustomerEstimate{
+		WONumber:     workOrder.WONumber,
+		CustomerName: workOrder.CustomerName,
+		Description:  workOrder.Description,
+		Estimate:     estimate,
+	}, nil
+}
+
+// Respond records the customer's signed decision on every line of an estimate. The estimate is
+// approved, partially approved or declined, and the approved total becomes the work order's
+// estimated cost.
+func (s *EstimateService) Respond(token string, req *EstimateResponseRequest, ip, userAgent string) (*CustomerEstimate, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validateSignature(req.Signature); err != nil {
+		return nil, err
+	}
+	estimate, err := s.estimateRepo.GetByTokenHash(security.HashToken(token))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrEstimateNotFound
+		}
+		return nil, fmt.Errorf("failed to get estimate: %w", err)
+	}
+
+	now := time.Now().UTC()
+	_, err = s.estimateRepo.Save(estimate.ID, func(estimate *domain.WorkOrderEstimate) error {
+		if estimate.ApprovalTokenHash == nil || !security.VerifyToken(*estimate.ApprovalTokenHash, token) {
+			return ErrEstimateNotFound
+		}
+		if estimate.Status != domain.EstimateStatusSent {
+			return ErrEstimateAnswered
+		}
+		if linkExpired(estimate, now) {
+			return ErrEstimateLinkExpired
+		}
+		if err := applyDecisions(estimate, req.Decisions); err != nil {
+			return err
+		}
+
+		estimate.Recalculate()
+		switch approvedLines(estimate) {
+		case 0:
+			estimate.Status = domain.EstimateStatusDeclined
+		case len(estimate.Lines):
+			estimate.Status = domain.EstimateStatusApproved
+		default:
+			estimate.Status = domain.EstimateStatusPartiallyApproved
+		}
+		estimate.RespondedAt = &now
+		estimate.SignerName = req.SignerName
+		estimate.Signature = req.Signature
+		estimate.SignerIP = ip
+		estimate.SignerUserAgent = truncate(userAgent, 500)
+		estimate.CustomerComment = req.Comment
+		return nil
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrEstimateNotFound
+		}
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"work_order_id": estimate.WorkOrderID,
+		"estimate_id":   estimate.ID,
+		"version":       estimate.Version,
+	}).Info("Customer answered estimate")
+	return s.GetForCustomer(token)
+}
+
+// ApprovalLimit limits the work committed to a work order to its latest approved estimate. The
+// work is priced at the estimate's terms so that it compares like for like with what the customer
+// approved: hours at the approved price of an hour of labor, parts at their approved price, both
+// less the estimate discount. Work the estimate does not price is taken at its own rate.
+type ApprovalLimit struct {
+	approved   float64          // approved subtotal, zero until an estimate is approved
+	discount   float64          // share of the estimate discount taken off every price
+	laborPrice float64          // approved price of an hour of labor, zero without approved labor
+	partPrices map[uint]float64 // approved unit price by inventory item
+}
+
+// ApprovalLimit retrieves the limit on the work of a work order. Work orders that never had an
+// estimate sent are not limited and get nil; once one was sent, nothing can be added until an
+// estimate is approved.
+func (s *EstimateService) ApprovalLimit(workOrderID uint) (*ApprovalLimit, error) {
+	estimates, err := s.estimateRepo.ListByWorkOrder(workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list estimates: %w", err)
+	}
+	var limit *ApprovalLimit
+	for _, estimate := range estimates {
+		if estimate.SentAt != nil && limit == nil {
+			limit = &ApprovalLimit{}
+		}
+		if estimate.IsApproved() {
+			limit = newApprovalLimit(estimate)
+			break
+		}
+	}
+	return limit, nil
+}
+
+// newApprovalLimit derives the approved prices from the approved lines of an estimate
+func newApprovalLimit(estimate *domain.WorkOrderEstimate) *ApprovalLimit {
+	limit := &ApprovalLimit{approved: estimate.ApprovedSubtotal, partPrices: map[uint]float64{}}
+	if subtotal := estimate.LaborSubtotal + estimate.PartsSubtotal; subtotal > 0 {
+		limit.discount = estimate.DiscountAmount / subtotal
+	}
+
+	laborAmount, laborHours := 0.0, 0.0
+	partAmounts, partQuantities := map[uint]float64{}, map[uint]float64{}
+	for _, line := range estimate.Lines {
+		if line.Approved == nil || !*line.Approved {
+			continue
+		}
+		switch {
+		case line.LineType == domain.EstimateLineLabor:
+			laborAmount += line.Amount
+			laborHours += line.Quantity
+		case line.InventoryItemID != nil:
+			partAmounts[*line.InventoryItemID] += line.Amount
+			partQuantities[*line.InventoryItemID] += line.Quantity
+		}
+	}
+	if laborHours > 0 {
+		limit.laborPrice = laborAmount / laborHours * (1 - limit.discount)
+	}
+	for itemID, amount := range partAmounts {
+		limit.partPrices[itemID] = amount / partQuantities[itemID] * (1 - limit.discount)
+	}
+	return limit
+}
+
+// Check reports ErrEstimateExceeded when the work committed to the work order, with the part
+// being added if any, goes beyond the approved estimate. A nil limit accepts everything.
+func (l *ApprovalLimit) Check(committed *interfaces.WorkOrderCommitment, adding *domain.WorkOrderPart) error {
+	if l == nil {
+		return nil
+	}
+	total := 0.0
+	for _, line := range committed.Labor {
+		total += line.HoursWorked * l.hourPrice(line.HourlyRate)
+	}
+	for _, part := range committed.Parts {
+		total += float64(part.Quantity) * l.partPrice(part)
+	}
+	total = roundTo(total, 2)
+
+	additional := 0.0
+	if adding != nil {
+		additional = roundTo(float64(adding.Quantity)*l.partPrice(adding), 2)
+	}
+	remaining := l.approved - total
+	if remaining <= 0 || additional > remaining+0.005 {
+		return fmt.Errorf("%w (approved %.2f, committed %.2f, adding %.2f)", ErrEstimateExceeded, l.approved, total, additional)
+	}
+	return nil
+}
+
+// hourPrice is the approved price of an hour of labor recorded at the hourly rate
+func (l *ApprovalLimit) hourPrice(rate float64) float64 {
+	if l.laborPrice > 0 {
+		return l.laborPrice
+	}
+	return rate * (1 - l.discount)
+}
+
+// partPrice is the approved unit price of a part
+func (l *ApprovalLimit) partPrice(part *domain.WorkOrderPart) float64 {
+	if price, ok := l.partPrices[part.InventoryItemID]; ok {
+		return price
+	}
+	return part.UnitPrice * (1 - l.discount)
+}
+
+// price builds the lines of an estimate from a request and calculates its totals
+func (s *EstimateService) price(estimate *domain.WorkOrderEstimate, workOrder *domain.WorkOrder, req *EstimateRequest) error {
+	laborRate, err := s.laborRate(workOrder)
+	if err != nil {
+		return err
+	}
+
+	estimate.Lines = make([]domain.WorkOrderEstimateLine, 0, len(req.Lines))
+	for i, lineReq := range req.Lines {
+		line := domain.WorkOrderEstimateLine{
+			LineType:        lineReq.LineType,
+			Description:     strings.TrimSpace(lineReq.Description),
+			Quantity:        lineReq.Quantity,
+			DiscountPercent: lineReq.DiscountPercent,
+		}
+		switch lineReq.LineType {
+		case domain.EstimateLineLabor:
+			line.UnitPrice = laborRate
+			if lineReq.OperationID != nil {
+				operation, err := s.operationService.Applicable(*lineReq.OperationID, &workOrder.Vehicle)
+				if err != nil {
+					return err
+				}
+				line.OperationID = &operation.ID
+				line.UnitPrice = operation.HourlyPrice(laborRate)
+				if line.Description == "" {
+					line.Description = operation.Description
+				}
+				if line.Quantity == 0 {
+					line.Quantity = operation.StandardHours
+				}
+			}
+			if line.Description == "" {
+				return fmt.Errorf("validation failed: line %d needs a description", i+1)
+			}
+			if lineReq.UnitPrice != nil {
+				line.UnitPrice = *lineReq.UnitPrice
+			}
+		case domain.EstimateLinePart:
+			if lineReq.InventoryItemID == nil {
+				return fmt.Errorf("validation failed: line %d needs an inventory_item_id", i+1)
+			}
+			if lineReq.Quantity != float64(int(lineReq.Quantity)) {
+				return fmt.Errorf("validation failed: line %d needs a whole part quantity", i+1)
+			}
+			item, err := s.inventoryRepo.GetItem(*lineReq.InventoryItemID)
+			if err != nil {
+				if isNotFound(err) {
+					return fmt.Errorf("validation failed: inventory item %d does not exist", *lineReq.InventoryItemID)
+				}
+				return fmt.Errorf("failed to get inventory item: %w", err)
+			}
+			line.InventoryItemID = &item.ID
+			line.UnitPrice = item.UnitPrice
+			if line.Description == "" {
+				line.Description = item.Name
+			}
+		}
+		if line.Quantity <= 0 {
+			return fmt.Errorf("validation failed: line %d needs a quantity", i+1)
+		}
+		estimate.Lines = append(estimate.Lines, line)
+	}
+
+	estimate.DiscountAmount = req.DiscountAmount
+	estimate.TaxRate = s.config.TaxRate
+	if req.TaxRate != nil {
+		estimate.TaxRate = *req.TaxRate
+	}
+	estimate.Notes = req.Notes
+	estimate.Recalculate()
+	return nil
+}
+
+// laborRate is the hourly rate of the work order's mechanic, or the workshop default
+func (s *EstimateService) laborRate(workOrder *domain.WorkOrder) (float64, error) {
+	if workOrder.AssignedMechanicID != nil {
+		profile, err := s.mechanicRepo.GetProfile(*workOrder.AssignedMechanicID)
+		if err != nil && !isNotFound(err) {
+			return 0, fmt.Errorf("failed to get mechanic profile: %w", err)
+		}
+		if profile != nil && profile.HourlyRate != nil {
+			return *profile.HourlyRate, nil
+		}
+	}
+	return s.config.DefaultLaborRate, nil
+}
+
+// getEstimate retrieves an estimate that belongs to a work order
+func (s *EstimateService) getEstimate(workOrderID, id uint) (*domain.WorkOrderEstimate, error) {
+	estimate, err := s.estimateRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrEstimateNotFound
+		}
+		return nil, fmt.Errorf("failed to get estimate: %w", err)
+	}
+	if estimate.WorkOrderID != workOrderID {
+		return nil, ErrEstimateNotFound
+	}
+	return estimate, nil
+}
+
+// save applies a change to an estimate of a work order
+func (s *EstimateService) save(workOrderID, id uint, apply func(estimate *domain.WorkOrderEstimate) error) (*domain.WorkOrderEstimate, error) {
+	estimate, err := s.estimateRepo.Save(id, func(estimate *domain.WorkOrderEstimate) error {
+		if estimate.WorkOrderID != workOrderID {
+			return ErrEstimateNotFound
+		}
+		return apply(estimate)
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrEstimateNotFound
+		}
+		return nil, err
+	}
+	return estimate, nil
+}
+
+// applyDecisions sets the customer's decision on the lines; every line must be decided once
+func applyDecisions(estimate *domain.WorkOrderEstimate, decisions []EstimateLineDecision) error {
+	byLine := make(map[uint]bool, len(decisions))
+	for _, decision := range decisions {
+		if _, seen := byLine[decision.LineID]; seen {
+			return fmt.Errorf("validation failed: line %d is decided twice", decision.LineID)
+		}
+		byLine[decision.LineID] = decision.Approved
+	}
+	if len(byLine) != len(estimate.Lines) {
+		return fmt.Errorf("validation failed: every line of the estimate must be approved or declined")
+	}
+	for i := range estimate.Lines {
+		approved, ok := byLine[estimate.Lines[i].ID]
+		if !ok {
+			return fmt.Errorf("validation failed: line %d is not decided", estimate.Lines[i].ID)
+		}
+		estimate.Lines[i].Approved = &approved
+	}
+	return nil
+}
+
+// approvedLines counts the lines the customer approved
+func approvedLines(estimate *domain.WorkOrderEstimate) int {
+	count := 0
+	for _, line := range estimate.Lines {
+		if line.Approved != nil && *line.Approved {
+			count++
+		}
+	}
+	return count
+}
+
+// linkExpired reports whether the approval link of an estimate can no longer be used
+func linkExpired(estimate *domain.WorkOrderEstimate, now time.Time) bool {
+	return estimate.TokenExpiresAt != nil && now.After(*estimate.TokenExpiresAt)
+}
+
+// validateSignature checks that a signature is a base64 PNG or JPEG data URL of reasonable size
+// whose data is the image type it claims
+func validateSignature(signature string) error {
+	for prefix, contentType := range signatureImageTypes {
+		if !strings.HasPrefix(signature, prefix) {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(signature, prefix))
+		if err != nil || len(data) == 0 {
+			return fmt.Errorf("validation failed: signature is not valid base64 image data")
+		}
+		if len(data) > maxSignatureBytes {
+			return fmt.Errorf("validation failed: signature image exceeds %d KB", maxSignatureBytes/1024)
+		}
+		if http.DetectContentType(data) != contentType {
+			return fmt.Errorf("validation failed: signature data is not a %s image", contentType)
+		}
+		return nil
+	}
+	return fmt.Errorf("validation failed: signature must be a PNG or JPEG image data URL")
+}
+
+// approvalToken generates the unguessable token of an approval link
+func approvalToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// truncate cuts a string to at most n bytes
+func truncate(value string, n int) string {
+	if len(value) <= n {
+		return value
+	}
+	return value[:n]
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// FuelDetectionConfig holds the thresholds of the fuel event detector
+type FuelDetectionConfig struct {
+	// FilterWindow is the number of samples of the median filter that removes sloshing noise
+	FilterWindow int
+	// NoiseStep is the largest filtered change (percent) between two samples treated as flat
+	NoiseStep float64
+	// MaxFlatSamples lets a rise or fall pause for this many samples and still count as one change
+	MaxFlatSamples int
+	// RefuelMinPercent and DropMinPercent are the smallest changes reported
+	RefuelMinPercent float64
+	DropMinPercent   float64
+	// DropMaxDuration bounds drops with the engine running; slower falls are consumption
+	DropMaxDuration time.Duration
+	// MovingSpeed (km/h): falls while moving are consumption, not theft
+	MovingSpeed float64
+	// SettleDuration defers a change still under way at the newest sample
+	SettleDuration time.Duration
+	// Lookback widens reprocessing windows so changes in progress at their start are seen whole
+	Lookback time.Duration
+	// ConsumptionMinDistanceKm is the shortest trip given a L/100km figure
+	ConsumptionMinDistanceKm float64
+	// ReceiptMatchWindow is how far a detected refuel may be from a receipt's fill time
+	ReceiptMatchWindow time.Duration
+	// Receipt and detected volumes match within the larger of these tolerances
+	ReceiptToleranceLiters  float64
+	ReceiptTolerancePercent float64
+}
+
+// DefaultFuelDetectionConfig returns thresholds suited to capacitive and float level sensors
+func DefaultFuelDetectionConfig() FuelDetectionConfig {
+	return FuelDetectionConfig{
+		FilterWindow:             5,
+		NoiseStep:                0.3,
+		MaxFlatSamples:           2,
+		RefuelMinPercent:         5,
+		DropMinPercent:           4,
+		DropMaxDuration:          20 * time.Minute,
+		MovingSpeed:              DefaultTripDetectionConfig().MovingSpeed,
+		SettleDuration:           10 * time.Minute,
+		Lookback:                 time.Hour,
+		ConsumptionMinDistanceKm: 5,
+		ReceiptMatchWindow:       2 * time.Hour,
+		ReceiptToleranceLiters:   5,
+		ReceiptTolerancePercent:  10,
+	}
+}
+
+// fuelSeries is the filtered fuel level series of a vehicle
+type fuelSeries struct {
+	samples []*domain.TelematicsData // samples reporting a fuel level, ordered by timestamp
+	levels  []float64                // median filtered levels of samples
+}
+
+// newFuelSeries keeps the samples with a plausible fuel level and filters their levels.
+// A level of 0 is how most devices report a missing sensor, so it is dropped as well.
+func newFuelSeries(samples []*domain.TelematicsData, window int) *fuelSeries {
+	series := &fuelSeries{}
+	raw := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		if sample.FuelLevel > 0 && sample.FuelLevel <= 100 {
+			series.samples = append(series.samples, sample)
+			raw = append(raw, sample.FuelLevel)
+		}
+	}
+	series.levels = medianFilter(raw, window)
+	return series
+}
+
+// indexRange returns the indices of the samples in [from, to]
+func (s *fuelSeries) indexRange(from, to time.Time) (int, int) {
+	start := sort.Search(len(s.samples), func(i int) bool { return !s.samples[i].Timestamp.Before(from) })
+	end := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].Timestamp.After(to) })
+	return start, end
+}
+
+// detectFuelEvents finds refuels and sudden drops in samples ordered by timestamp. A change
+// still under way at the newest sample is left for a later run unless that sample is settled;
+// deferred is then the start of that change, zero otherwise.
+func detectFuelEvents(samples []*domain.TelematicsData, config FuelDetectionConfig, now time.Time) (events []*domain.FuelEvent, deferred time.Time) {
+	series := newFuelSeries(samples, config.FilterWindow)
+	n := len(series.samples)
+	if n < 2 {
+		return nil, time.Time{}
+	}
+
+	emit := func(start, end, direction int) {
+		if event := series.event(start, end, direction, config); event != nil {
+			events = append(events, event)
+		}
+	}
+
+	start, end, direction, flat := 0, 0, 0, 0
+	for i := 1; i < n; i++ {
+		step := 0
+		switch delta := series.levels[i] - series.levels[i-1]; {
+		case delta > config.NoiseStep:
+			step = 1
+		case delta < -config.NoiseStep:
+			step = -1
+		}
+
+		switch {
+		case step == 0:
+			if direction != 0 {
+				flat++
+				if flat > config.MaxFlatSamples {
+					emit(start, end, direction)
+					direction = 0
+				}
+			}
+		case step == direction:
+			end, flat = i, 0
+		default:
+			if direction != 0 {
+				emit(start, end, direction)
+			}
+			start, end, direction, flat = i-1, i, step, 0
+		}
+	}
+	if direction != 0 {
+		if now.Sub(series.samples[n-1].Timestamp) < config.SettleDuration {
+			return events, series.samples[start].Timestamp
+		}
+		emit(start, end, direction)
+	}
+	return events, time.Time{}
+}
+
+// event turns a rise or fall of the filtered levels into an event when it is large enough
+func (s *fuelSeries) event(start, end, direction int, config FuelDetectionConfig) *domain.FuelEvent {
+	change := math.Abs(s.levels[end] - s.levels[start])
+	first, last := s.samples[start], s.samples[end]
+
+	eventType := domain.FuelEventRefuel
+	if direction > 0 {
+		if change < config.RefuelMinPercent {
+			return nil
+		}
+	} else {
+		if change < config.DropMinPercent {
+			return nil
+		}
+		engineRunning := false
+		for _, sample := range s.samples[start : end+1] {
+			if sample.Speed >= config.MovingSpeed {
+				return nil
+			}
+			if sample.EngineStatus != domain.EngineStatusOff {
+				engineRunning = true
+			}
+		}
+		// Idling burns fuel slowly; with the engine off any loss is suspicious
+		if engineRunning && last.Timestamp.Sub(first.Timestamp) > config.DropMaxDuration {
+			return nil
+		}
+		eventType = domain.FuelEventDrop
+	}
+
+	return &domain.FuelEvent{
+		VehicleID:     last.VehicleID,
+		Type:          eventType,
+		StartedAt:     first.Timestamp,
+		EndedAt:       last.Timestamp,
+		LevelBefore:   roundTo(s.levels[start], 2),
+		LevelAfter:    roundTo(s.levels[end], 2),
+		ChangePercent: roundTo(change, 2),
+		Latitude:      first.Latitude,
+		Longitude:     first.Longitude,
+	}
+}
+
+// medianFilter replaces each value with the median of the window centred on it
+func medianFilter(values []float64, window int) []float64 {
+	filtered := make([]float64, len(values))
+	half := window / 2
+	buffer := make([]float64, 0, window)
+	for i := range values {
+		from, to := i-half, i+half+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(values) {
+			to = len(values)
+		}
+		buffer = append(buffer[:0], values[from:to]...)
+		sort.Float64s(buffer)
+		if len(buffer)%2 == 1 {
+			filtered[i] = buffer[len(buffer)/2]
+		} else {
+			filtered[i] = (buffer[len(buffer)/2-1] + buffer[len(buffer)/2]) / 2
+		}
+	}
+	return filtered
+}
+
+func roundTo(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,716 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Fuel errors
+var (
+	ErrFuelProfileNotFound = errors.New("fuel profile not found")
+	ErrFuelReceiptNotFound = errors.New("fuel receipt not found")
+	ErrFuelAnomalyNotFound = errors.New("fuel anomaly not found")
+)
+
+const (
+	// fuelProcessInterval is how often vehicles with new fuel levels are analysed
+	fuelProcessInterval = time.Minute
+	// fuelPendingBatch bounds the overdue receipts reconciled per run
+	fuelPendingBatch = 100
+	// fuelReconcileRange is how far back receipts are re-reconciled when a fuel profile changes
+	fuelReconcileRange = 92 * 24 * time.Hour
+)
+
+// FuelProfileRequest represents the fuel profile of a vehicle
+type FuelProfileRequest struct {
+	TankCapacityLiters float64 `json:"tank_capacity_liters" validate:"required,gt=0,lte=5000"`
+}
+
+// FuelReceiptRequest represents a fuel receipt; updates replace the whole receipt
+type FuelReceiptRequest struct {
+	VehicleID     uint      `json:"vehicle_id" validate:"required"`
+	FilledAt      time.Time `json:"filled_at" validate:"required"`
+	Liters        float64   `json:"liters" validate:"required,gt=0,lte=5000"`
+	TotalCost     float64   `json:"total_cost" validate:"gte=0"`
+	Odometer      *int      `json:"odometer" validate:"omitempty,gte=0"`
+	Station       string    `json:"station" validate:"max=100"`
+	ReceiptNumber string    `json:"receipt_number" validate:"max=50"`
+	Notes         string    `json:"notes" validate:"max=1000"`
+}
+
+// FuelAnomalyReviewRequest records the outcome of reviewing an anomaly
+type FuelAnomalyReviewRequest struct {
+	Status string `json:"status" validate:"required,oneof=confirmed dismissed"`
+	Note   string `json:"note" validate:"max=1000"`
+}
+
+// FuelEventList represents a page of fuel events
+type FuelEventList struct {
+	Events []*domain.FuelEvent `json:"events"`
+	Total  int64               `json:"total"`
+	Page   int                 `json:"page"`
+	Limit  int                 `json:"limit"`
+}
+
+// FuelConsumptionReport is the per-trip fuel consumption of a vehicle with totals for the range
+type FuelConsumptionReport struct {
+	Trips  []*domain.TripFuelConsumption     `json:"trips"`
+	Total  int64                             `json:"total"`
+	Page   int                               `json:"page"`
+	Limit  int                               `json:"limit"`
+	Totals *interfaces.FuelConsumptionTotals `json:"totals"`
+	// LitersPer100Km is the average over the trips with a litre figure
+	LitersPer100Km *float64 `json:"liters_per_100km"`
+}
+
+// FuelReceiptList represents a page of fuel receipts
+type FuelReceiptList struct {
+	Receipts []*domain.FuelReceipt `json:"receipts"`
+	Total    int64                 `json:"total"`
+	Page     int                   `json:"page"`
+	Limit    int                   `json:"limit"`
+}
+
+// FuelAnomalyList represents a page of fuel anomalies
+type FuelAnomalyList struct {
+	Anomalies []*domain.FuelAnomaly `json:"anomalies"`
+	Total     int64                 `json:"total"`
+	Page      int                   `json:"page"`
+	Limit     int                   `json:"limit"`
+}
+
+// FuelService analyses fuel levels. Ingested samples with a fuel level queue their vehicle in
+// whichever process stores them; a background worker in the API server re-detects refuels and
+// sudden drops in the affected window, raises anomalies for drops and reconciles fuel receipts
+// with the refuels. Consumption is computed per trip as trips are stored.
+type FuelService struct {
+	fuelRepo       interfaces.FuelRepository
+	telematicsRepo interfaces.TelematicsRepository
+	vehicleRepo    interfaces.VehicleRepository
+	config         FuelDetectionConfig
+	validator      *validator.Validate
+	logger         *logrus.Logger
+
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+// NewFuelService creates a new fuel service
+func NewFuelService(
+	fuelRepo interfaces.FuelRepository,
+	telematicsRepo interfaces.TelematicsRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	logger *logrus.Logger,
+) *FuelService {
+	return &FuelService{
+		fuelRepo:       fuelRepo,
+		telematicsRepo: telematicsRepo,
+		vehicleRepo:    vehicleRepo,
+		config:         DefaultFuelDetectionConfig(),
+		validator:      validator.New(),
+		logger:         logger,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start runs the fuel analysis worker
+func (s *FuelService) Start() {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		ticker := time.NewTicker(fuelProcessInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.processDirty()
+				s.reconcilePending()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the worker
+func (s *FuelService) Close() {
+	close(s.stop)
+	s.workers.Wait()
+}
+
+// HandleTelematics queues vehicles with new fuel levels for analysis; it is registered as an ingestion listener
+func (s *FuelService) HandleTelematics(samples []*domain.TelematicsData) {
+	earliest := make(map[uint]time.Time)
+	for _, sample := range samples {
+		if sample.FuelLevel <= 0 {
+			continue
+		}
+		if t, ok := earliest[sample.VehicleID]; !ok || sample.Timestamp.Before(t) {
+			earliest[sample.VehicleID] = sample.Timestamp
+		}
+	}
+	s.queue(earliest)
+}
+
+// queue stores vehicles for the analysis worker
+func (s *FuelService) queue(earliest map[uint]time.Time) {
+	if err := s.telematicsRepo.QueueReprocess(domain.ReprocessConsumerFuel, earliest); err != nil {
+		s.logger.WithError(err).WithField("vehicles", len(earliest)).Error("Failed to queue fuel analysis")
+	}
+}
+
+// processDirty analyses every vehicle queued since the last run
+func (s *FuelService) processDirty() {
+	dirty, err := s.telematicsRepo.TakeReprocess(domain.ReprocessConsumerFuel)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to take queued fuel analysis")
+		return
+	}
+
+	now := time.Now().UTC()
+	for vehicleID, earliest := range dirty {
+		deferred, err := s.Reprocess(vehicleID, earliest, now)
+		if err != nil {
+			s.logger.WithError(err).WithField("vehicle_id", vehicleID).Error("Failed to analyse fuel levels")
+			// Retry on the next run
+			deferred = earliest
+		}
+		// A change still under way is completed once it settles, even if the vehicle goes quiet
+		if !deferred.IsZero() {
+			s.queue(map[uint]time.Time{vehicleID: deferred})
+		}
+	}
+}
+
+// Reprocess re-detects the fuel events of a vehicle in [from, to] and reconciles the receipts
+// around them. The window grows to cover events it cuts through. It returns the start of a
+// change still under way at the newest sample, or the zero time.
+func (s *FuelService) Reprocess(vehicleID uint, from, to time.Time) (time.Time, error) {
+	from = from.Add(-s.config.Lookback)
+	existing, err := s.fuelRepo.GetEventsInRange(vehicleID, "", from, to)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get fuel events: %w", err)
+	}
+	for _, event := range existing {
+		if event.StartedAt.Before(from) {
+			from = event.StartedAt
+		}
+		if event.EndedAt.After(to) {
+			to = event.EndedAt
+		}
+	}
+
+	samples, err := s.telematicsRepo.GetByVehicle(vehicleID, from, to)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get telematics samples: %w", err)
+	}
+	profile, err := s.profile(vehicleID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now().UTC()
+	events, deferred := detectFuelEvents(samples, s.config, now)
+	for _, event := range events {
+		event.Liters = roundLiters(profile.Liters(event.ChangePercent))
+	}
+	events, err = s.fuelRepo.SyncEvents(vehicleID, from, to, events)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to store fuel events: %w", err)
+	}
+
+	for _, event := range events {
+		if event.Type != domain.FuelEventDrop {
+			continue
+		}
+		eventID := event.ID
+		anomaly := &domain.FuelAnomaly{
+			VehicleID:   vehicleID,
+			Type:        domain.FuelAnomalySuddenDrop,
+			FuelEventID: &eventID,
+			OccurredAt:  event.StartedAt,
+			Liters:      event.Liters,
+			Description: fmt.Sprintf("Fuel level fell from %.1f%% to %.1f%% in %s while stationary",
+				event.LevelBefore, event.LevelAfter, event.EndedAt.Sub(event.StartedAt).Round(time.Minute)),
+			Status: domain.FuelAnomalyOpen,
+		}
+		if err := s.fuelRepo.SaveAnomaly(anomaly); err != nil {
+			return time.Time{}, fmt.Errorf("failed to store fuel anomaly: %w", err)
+		}
+	}
+
+	window := s.config.ReceiptMatchWindow
+	receipts, err := s.fuelRepo.GetReceiptsInRange(vehicleID, from.Add(-window), to.Add(window))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get fuel receipts: %w", err)
+	}
+	for _, receipt := range receipts {
+		if err := s.reconcile(receipt, now); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return deferred, nil
+}
+
+// reconcilePending settles receipts whose match window has passed without being reconciled
+func (s *FuelService) reconcilePending() {
+	now := time.Now().UTC()
+	receipts, err := s.fuelRepo.GetPendingReceipts(now.Add(-s.config.ReceiptMatchWindow-s.config.SettleDuration), fuelPendingBatch)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get pending fuel receipts")
+		return
+	}
+	for _, receipt := range receipts {
+		if err := s.reconcile(receipt, now); err != nil {
+			s.logger.WithError(err).WithField("receipt_id", receipt.ID).Error("Failed to reconcile fuel receipt")
+		}
+	}
+}
+
+// reconcile matches a receipt with the closest refuel detected within the match window.
+// Without a fuel profile refuels are matched by time only. A receipt without a refuel stays
+// pending until the window has passed and the vehicle's samples have had time to arrive.
+func (s *FuelService) reconcile(receipt *domain.FuelReceipt, now time.Time) error {
+	window := s.config.ReceiptMatchWindow
+	from, to := receipt.FilledAt.Add(-window), receipt.FilledAt.Add(window)
+	refuels, err := s.fuelRepo.GetEventsInRange(receipt.VehicleID, domain.FuelEventRefuel, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get refuels: %w", err)
+	}
+	var match *domain.FuelEvent
+	var best time.Duration
+	for _, event := range refuels {
+		if gap := fillGap(receipt.FilledAt, event); match == nil || gap < best {
+			match, best = event, gap
+		}
+	}
+
+	receipt.FuelEventID, receipt.DetectedLiters, receipt.ReconciledAt = nil, nil, nil
+	var anomaly *domain.FuelAnomaly
+	switch {
+	case match != nil:
+		eventID := match.ID
+		receipt.FuelEventID = &eventID
+		receipt.DetectedLiters = match.Liters
+		receipt.ReconciliationStatus = domain.FuelReconciliationMatched
+		if match.Liters == nil {
+			break
+		}
+		difference := roundTo(receipt.Liters-*match.Liters, 2)
+		tolerance := math.Max(s.config.ReceiptToleranceLiters, receipt.Liters*s.config.ReceiptTolerancePercent/100)
+		if math.Abs(difference) > tolerance {
+			receipt.ReconciliationStatus = domain.FuelReconciliationMismatch
+			anomaly = &domain.FuelAnomaly{
+				Type:   domain.FuelAnomalyReceiptMismatch,
+				Liters: &difference,
+				Description: fmt.Sprintf("Receipt of %.1f L but a refuel of %.1f L was detected",
+					receipt.Liters, *match.Liters),
+			}
+		}
+	case now.Before(to.Add(s.config.SettleDuration)):
+		receipt.ReconciliationStatus = domain.FuelReconciliationPending
+	default:
+		samples, err := s.telematicsRepo.GetByVehicle(receipt.VehicleID, from, to)
+		if err != nil {
+			return fmt.Errorf("failed to get telematics samples: %w", err)
+		}
+		if len(newFuelSeries(samples, 1).samples) < 2 {
+			receipt.ReconciliationStatus = domain.FuelReconciliationNoData
+			break
+		}
+		receipt.ReconciliationStatus = domain.FuelReconciliationUnmatched
+		liters := receipt.Liters
+		anomaly = &domain.FuelAnomaly{
+			Type:   domain.FuelAnomalyUnmatchedReceipt,
+			Liters: &liters,
+			Description: fmt.Sprintf("No refuel was detected within %s of a receipt of %.1f L",
+				window, receipt.Liters),
+		}
+	}
+	if receipt.ReconciliationStatus != domain.FuelReconciliationPending {
+		receipt.ReconciledAt = &now
+	}
+
+	keepType := ""
+	if anomaly != nil {
+		keepType = anomaly.Type
+	}
+	if err := s.fuelRepo.DeleteOpenReceiptAnomalies(receipt.ID, keepType); err != nil {
+		return fmt.Errorf("failed to clear fuel anomalies: %w", err)
+	}
+	if anomaly != nil {
+		receiptID := receipt.ID
+		anomaly.VehicleID = receipt.VehicleID
+		anomaly.FuelReceiptID = &receiptID
+		anomaly.OccurredAt = receipt.FilledAt
+		anomaly.Status = domain.FuelAnomalyOpen
+		if err := s.fuelRepo.SaveAnomaly(anomaly); err != nil {
+			return fmt.Errorf("failed to store fuel anomaly: %w", err)
+		}
+	}
+	if err := s.fuelRepo.UpdateReconciliation(receipt); err != nil {
+		return fmt.Errorf("failed to store receipt reconciliation: %w", err)
+	}
+	return nil
+}
+
+// HandleTrips stores the fuel used during completed trips. It is the drop of the filtered
+// level from the first to the last sample of the trip plus any refuel in between.
+func (s *FuelService) HandleTrips(vehicleID uint, trips []*domain.Trip, samples []*domain.TelematicsData) {
+	if err := s.computeConsumption(vehicleID, trips, samples); err != nil {
+		s.logger.WithError(err).WithField("vehicle_id", vehicleID).Error("Failed to compute trip fuel consumption")
+	}
+}
+
+func (s *FuelService) computeConsumption(vehicleID uint, trips []*domain.Trip, samples []*domain.TelematicsData) error {
+	tripIDs := make([]uint, 0, len(trips))
+	for _, trip := range trips {
+		if trip.Status == domain.TripStatusCompleted {
+			tripIDs = append(tripIDs, trip.ID)
+		}
+	}
+	if len(tripIDs) == 0 {
+		return nil
+	}
+
+	profile, err := s.profile(vehicleID)
+	if err != nil {
+		return err
+	}
+	series := newFuelSeries(samples, s.config.FilterWindow)
+	events, _ := detectFuelEvents(samples, s.config, time.Now().UTC())
+
+	var consumptions []*domain.TripFuelConsumption
+	for _, trip := range trips {
+		if trip.Status != domain.TripStatusCompleted {
+			continue
+		}
+		start, end := series.indexRange(trip.StartTime, trip.EndTime)
+		if end-start < 2 {
+			continue
+		}
+		used := series.levels[start] - series.levels[end-1]
+		for _, event := range events {
+			if event.Type == domain.FuelEventRefuel && !event.StartedAt.Before(trip.StartTime) && !event.EndedAt.After(trip.EndTime) {
+				used += event.ChangePercent
+			}
+		}
+		// Sensor noise on short trips can exceed the fuel burnt; no figure beats a wrong one
+		if used < 0 {
+			continue
+		}
+
+		consumption := &domain.TripFuelConsumption{
+			TripID:          trip.ID,
+			VehicleID:       vehicleID,
+			StartTime:       trip.StartTime,
+			EndTime:         trip.EndTime,
+			DistanceKm:      trip.DistanceKm,
+			FuelUsedPercent: roundTo(used, 2),
+			FuelUsedLiters:  roundLiters(profile.Liters(used)),
+		}
+		if consumption.FuelUsedLiters != nil && trip.DistanceKm >= s.config.ConsumptionMinDistanceKm {
+			perHundred := roundTo(*consumption.FuelUsedLiters/trip.DistanceKm*100, 2)
+			consumption.LitersPer100Km = &perHundred
+		}
+		consumptions = append(consumptions, consumption)
+	}
+
+	if err := s.fuelRepo.ReplaceTripConsumptions(vehicleID, tripIDs, consumptions); err != nil {
+		return fmt.Errorf("failed to store trip fuel consumption: %w", err)
+	}
+	return nil
+}
+
+// GetProfile returns the fuel profile of a vehicle
+func (s *FuelService) GetProfile(viewer Viewer, vehicleID uint) (*domain.VehicleFuelProfile, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+	profile, err := s.profile(vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrFuelProfileNotFound
+	}
+	return profile, nil
+}
+
+// SaveProfile sets the tank capacity of a vehicle. Litre figures of past events and trips are
+// recalculated and recent receipts reconciled again.
+func (s *FuelService) SaveProfile(vehicleID uint, req *FuelProfileRequest, updatedBy uint) (*domain.VehicleFuelProfile, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := s.vehicleRepo.GetByID(vehicleID); err != nil {
+		if isNotFound(err) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+
+	profile := &domain.VehicleFuelProfile{
+		VehicleID:          vehicleID,
+		TankCapacityLiters: req.TankCapacityLiters,
+		UpdatedBy:          &updatedBy,
+	}
+	if err := s.fuelRepo.SaveProfile(profile); err != nil {
+		return nil, fmt.Errorf("failed to save fuel profile: %w", err)
+	}
+	if err := s.fuelRepo.RecalculateLiters(vehicleID, profile.TankCapacityLiters, s.config.ConsumptionMinDistanceKm); err != nil {
+		return nil, fmt.Errorf("failed to recalculate litres: %w", err)
+	}
+
+	now := time.Now().UTC()
+	receipts, err := s.fuelRepo.GetReceiptsInRange(vehicleID, now.Add(-fuelReconcileRange), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fuel receipts: %w", err)
+	}
+	for _, receipt := range receipts {
+		if err := s.reconcile(receipt, now); err != nil {
+			return nil, err
+		}
+	}
+	return s.fuelRepo.GetProfile(vehicleID)
+}
+
+// ListEvents lists the refuels and drops of a vehicle starting in [from, to], newest first
+func (s *FuelService) ListEvents(viewer Viewer, vehicleID uint, eventType string, from, to time.Time, page, limit, offset int) (*FuelEventList, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", ErrInvalidTimeRange)
+	}
+
+	events, total, err := s.fuelRepo.ListEvents(vehicleID, eventType, from, to, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel events: %w", err)
+	}
+	return &FuelEventList{Events: events, Total: total, Page: page, Limit: limit}, nil
+}
+
+// GetConsumption returns the per-trip fuel consumption of a vehicle for trips starting in [from, to]
+func (s *FuelService) GetConsumption(viewer Viewer, vehicleID uint, from, to time.Time, page, limit, offset int) (*FuelConsumptionReport, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", ErrInvalidTimeRange)
+	}
+
+	trips, total, err := s.fuelRepo.ListTripConsumptions(vehicleID, from, to, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trip fuel consumption: %w", err)
+	}
+	totals, err := s.fuelRepo.SumTripConsumptions(vehicleID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum trip fuel consumption: %w", err)
+	}
+
+	report := &FuelConsumptionReport{Trips: trips, Total: total, Page: page, Limit: limit, Totals: totals}
+	if totals.MeasuredDistanceKm >= s.config.ConsumptionMinDistanceKm {
+		perHundred := roundTo(totals.FuelUsedLiters/totals.MeasuredDistanceKm*100, 2)
+		report.LitersPer100Km = &perHundred
+	}
+	return report, nil
+}
+
+// CreateReceipt records a fuel purchase and reconciles it right away. Drivers may only enter
+// receipts for their assigned vehicles.
+func (s *FuelService) CreateReceipt(viewer Viewer, req *FuelReceiptRequest) (*domain.FuelReceipt, error) {
+	if err := s.validateReceipt(req); err != nil {
+		return nil, err
+	}
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, req.VehicleID); err != nil {
+		return nil, err
+	}
+
+	receipt := &domain.FuelReceipt{EnteredBy: viewer.UserID}
+	applyReceiptRequest(receipt, req)
+	if err := s.fuelRepo.CreateReceipt(receipt); err != nil {
+		return nil, fmt.Errorf("failed to create fuel receipt: %w", err)
+	}
+	if err := s.reconcile(receipt, time.Now().UTC()); err != nil {
+		// The worker picks the receipt up once its window has passed
+		s.logger.WithError(err).WithField("receipt_id", receipt.ID).Warn("Failed to reconcile fuel receipt")
+	}
+	return s.fuelRepo.GetReceipt(receipt.ID)
+}
+
+// GetReceipt returns a fuel receipt. Drivers only see the receipts they entered.
+func (s *FuelService) GetReceipt(viewer Viewer, id uint) (*domain.FuelReceipt, error) {
+	receipt, err := s.fuelRepo.GetReceipt(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrFuelReceiptNotFound
+		}
+		return nil, fmt.Errorf("failed to get fuel receipt: %w", err)
+	}
+	if viewer.IsDriver() && receipt.EnteredBy != viewer.UserID {
+		return nil, ErrFuelReceiptNotFound
+	}
+	return receipt, nil
+}
+
+// UpdateReceipt replaces a fuel receipt and reconciles it again
+func (s *FuelService) UpdateReceipt(viewer Viewer, id uint, req *FuelReceiptRequest) (*domain.FuelReceipt, error) {
+	if err := s.validateReceipt(req); err != nil {
+		return nil, err
+	}
+	receipt, err := s.GetReceipt(viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, req.VehicleID); err != nil {
+		return nil, err
+	}
+
+	applyReceiptRequest(receipt, req)
+	receipt.Vehicle = nil
+	if err := s.fuelRepo.UpdateReceipt(receipt); err != nil {
+		return nil, fmt.Errorf("failed to update fuel receipt: %w", err)
+	}
+	if err := s.reconcile(receipt, time.Now().UTC()); err != nil {
+		s.logger.WithError(err).WithField("receipt_id", receipt.ID).Warn("Failed to reconcile fuel receipt")
+	}
+	return s.fuelRepo.GetReceipt(receipt.ID)
+}
+
+// DeleteReceipt deletes a fuel receipt together with its anomalies
+func (s *FuelService) DeleteReceipt(viewer Viewer, id uint) error {
+	if _, err := s.GetReceipt(viewer, id); err != nil {
+		return err
+	}
+	if err := s.fuelRepo.DeleteReceipt(id); err != nil {
+		if isNotFound(err) {
+			return ErrFuelReceiptNotFound
+		}
+		return fmt.Errorf("failed to delete fuel receipt: %w", err)
+	}
+	return nil
+}
+
+// ListReceipts lists fuel receipts, newest fill first. Drivers only see the receipts they entered.
+func (s *FuelService) ListReceipts(viewer Viewer, filter interfaces.FuelReceiptFilter, page, limit, offset int) (*FuelReceiptList, error) {
+	if viewer.IsDriver() {
+		filter.EnteredBy = viewer.UserID
+	}
+	receipts, total, err := s.fuelRepo.ListReceipts(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel receipts: %w", err)
+	}
+	return &FuelReceiptList{Receipts: receipts, Total: total, Page: page, Limit: limit}, nil
+}
+
+// ListAnomalies lists fuel anomalies, newest first
+func (s *FuelService) ListAnomalies(filter interfaces.FuelAnomalyFilter, page, limit, offset int) (*FuelAnomalyList, error) {
+	anomalies, total, err := s.fuelRepo.ListAnomalies(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel anomalies: %w", err)
+	}
+	return &FuelAnomalyList{Anomalies: anomalies, Total: total, Page: page, Limit: limit}, nil
+}
+
+// GetAnomaly returns a fuel anomaly with its event
+func (s *FuelService) GetAnomaly(id uint) (*domain.FuelAnomaly, error) {
+	anomaly, err := s.fuelRepo.GetAnomaly(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrFuelAnomalyNotFound
+		}
+		return nil, fmt.Errorf("failed to get fuel anomaly: %w", err)
+	}
+	return anomaly, nil
+}
+
+// ReviewAnomaly confirms or dismisses an anomaly. Reviewed anomalies are no longer refreshed
+// by the analysis and keep their event.
+func (s *FuelService) ReviewAnomaly(id uint, req *FuelAnomalyReviewRequest, reviewerID uint) (*domain.FuelAnomaly, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	anomaly, err := s.GetAnomaly(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	anomaly.Status = req.Status
+	anomaly.ReviewNote = req.Note
+	anomaly.ReviewedBy = &reviewerID
+	anomaly.ReviewedAt = &now
+	if err := s.fuelRepo.UpdateAnomaly(anomaly); err != nil {
+		return nil, fmt.Errorf("failed to update fuel anomaly: %w", err)
+	}
+	return anomaly, nil
+}
+
+// profile returns the fuel profile of a vehicle, nil when it has none
+func (s *FuelService) profile(vehicleID uint) (*domain.VehicleFuelProfile, error) {
+	profile, err := s.fuelRepo.GetProfile(vehicleID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get fuel profile: %w", err)
+	}
+	return profile, nil
+}
+
+func (s *FuelService) validateReceipt(req *FuelReceiptRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if req.FilledAt.After(time.Now().UTC().Add(maxSampleFutureSkew)) {
+		return fmt.Errorf("validation failed: filled_at is in the future")
+	}
+	return nil
+}
+
+// applyReceiptRequest copies a request onto a receipt and resets its reconciliation
+func applyReceiptRequest(receipt *domain.FuelReceipt, req *FuelReceiptRequest) {
+	receipt.VehicleID = req.VehicleID
+	receipt.FilledAt = req.FilledAt.UTC()
+	receipt.Liters = req.Liters
+	receipt.TotalCost = req.TotalCost
+	receipt.Odometer = req.Odometer
+	receipt.Station = req.Station
+	receipt.ReceiptNumber = req.ReceiptNumber
+	receipt.Notes = req.Notes
+	receipt.FuelEventID = nil
+	receipt.DetectedLiters = nil
+	receipt.ReconciliationStatus = domain.FuelReconciliationPending
+	receipt.ReconciledAt = nil
+}
+
+// fillGap is the time between a fill and a refuel, zero when the fill falls inside it
+func fillGap(filledAt time.Time, event *domain.FuelEvent) time.Duration {
+	switch {
+	case filledAt.Before(event.StartedAt):
+		return event.StartedAt.Sub(filledAt)
+	case filledAt.After(event.EndedAt):
+		return filledAt.Sub(event.EndedAt)
+	default:
+		return 0
+	}
+}
+
+// roundLiters rounds a litre figure to centilitres, keeping it empty
+func roundLiters(liters *float64) *float64 {
+	if liters == nil {
+		return nil
+	}
+	rounded := roundTo(*liters, 2)
+	return &rounded
+}
@@ -0,0 +1,657 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	// Embedded zone database so geofence schedules work on hosts without tzdata
+	_ "time/tzdata"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/pkg/geo"
+)
+
+// Geofence errors
+var (
+	ErrGeofenceNotFound          = errors.New("geofence not found")
+	ErrGeofenceAlertRuleNotFound = errors.New("geofence alert rule not found")
+)
+
+const (
+	// geofenceCacheTTL bounds how long another instance's geofence changes take to apply here
+	geofenceCacheTTL = time.Minute
+	// geofenceVehicleCacheTTL bounds how long vehicle category changes take to apply
+	geofenceVehicleCacheTTL  = 10 * time.Minute
+	maxGeofencePolygonPoints = 500
+	maxGeofenceRadiusMeters  = 100000
+)
+
+// GeofenceRequest represents a geofence definition; updates replace the whole definition
+type GeofenceRequest struct {
+	Name                  string                          `json:"name" validate:"required,max=100"`
+	Description           string                          `json:"description"`
+	Shape                 string                          `json:"shape" validate:"required,oneof=circle polygon"`
+	CenterLatitude        *float64                        `json:"center_latitude" validate:"omitempty,min=-90,max=90"`
+	CenterLongitude       *float64                        `json:"center_longitude" validate:"omitempty,min=-180,max=180"`
+	RadiusMeters          *float64                        `json:"radius_meters" validate:"omitempty,gt=0"`
+	Polygon               []geo.Point                     `json:"polygon"`
+	DwellThresholdMinutes *int                            `json:"dwell_threshold_minutes" validate:"omitempty,min=1,max=10080"`
+	Schedule              []domain.GeofenceScheduleWindow `json:"schedule"`
+	Timezone              string                          `json:"timezone" validate:"max=50"`
+	IsActive              *bool                           `json:"is_active"`
+	VehicleIDs            []uint                          `json:"vehicle_ids"`
+	VehicleCategories     []string                        `json:"vehicle_categories" validate:"dive,oneof=rental workshop customer company"`
+}
+
+// GeofenceAlertRuleRequest represents an alert rule
+type GeofenceAlertRuleRequest struct {
+	EventType        string `json:"event_type" validate:"required,oneof=enter exit dwell"`
+	RecipientUserIDs []uint `json:"recipient_user_ids" validate:"required,min=1,max=50"`
+	IsActive         *bool  `json:"is_active"`
+}
+
+// GeofenceList represents a page of geofences
+type GeofenceList struct {
+	Geofences []*domain.Geofence `json:"geofences"`
+	Total     int64              `json:"total"`
+	Page      int                `json:"page"`
+	Limit     int                `json:"limit"`
+}
+
+// GeofenceEventList represents a page of geofence events
+type GeofenceEventList struct {
+	Events []*domain.GeofenceEvent `json:"events"`
+	Total  int64                   `json:"total"`
+	Page   int                     `json:"page"`
+	Limit  int                     `json:"limit"`
+}
+
+type geofenceStateKey struct {
+	geofenceID uint
+	vehicleID  uint
+}
+
+type geofenceVehicleInfo struct {
+	plateNumber string
+	category    string
+	loadedAt    time.Time
+}
+
+// GeofenceService manages geofences and evaluates ingested positions against them.
+// Positions are evaluated on every stored sample; transitions raise enter and exit events,
+// and stays longer than a geofence's dwell threshold raise one dwell event per stay.
+// Outside a geofence's schedule the vehicle state is still tracked but no events are raised.
+type GeofenceService struct {
+	geofenceRepo        interfaces.GeofenceRepository
+	vehicleRepo         interfaces.VehicleRepository
+	userRepo            interfaces.UserRepository
+	notificationService *NotificationService
+	validator           *validator.Validate
+	logger              *logrus.Logger
+
+	mu        sync.Mutex
+	geofences []*domain.Geofence
+	rules     map[uint][]*domain.GeofenceAlertRule // by geofence ID
+	loadedAt  time.Time
+	vehicles  map[uint]*geofenceVehicleInfo
+}
+
+// NewGeofenceService creates a new geofence service
+func NewGeofenceService(
+	geofenceRepo interfaces.GeofenceRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	userRepo interfaces.UserRepository,
+	notificationService *NotificationService,
+	logger *logrus.Logger,
+) *GeofenceService {
+	return &GeofenceService{
+		geofenceRepo:        geofenceRepo,
+		vehicleRepo:         vehicleRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		validator:           validator.New(),
+		logger:              logger,
+		vehicles:            make(map[uint]*geofenceVehicleInfo),
+	}
+}
+
+// Create creates a geofence
+func (s *GeofenceService) Create(req *GeofenceRequest, createdBy uint) (*domain.Geofence, error) {
+	geofence := &domain.Geofence{IsActive: true, CreatedBy: createdBy}
+	if err := s.applyRequest(geofence, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.geofenceRepo.Create(geofence); err != nil {
+		return nil, fmt.Errorf("failed to create geofence: %w", err)
+	}
+	s.invalidateCache()
+
+	s.logger.WithFields(logrus.Fields{
+		"geofence_id": geofence.ID,
+		"name":        geofence.Name,
+		"created_by":  createdBy,
+	}).Info("Geofence created")
+	return geofence, nil
+}
+
+// GetByID retrieves a geofence
+func (s *GeofenceService) GetByID(id uint) (*domain.Geofence, error) {
+	geofence, err := s.geofenceRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrGeofenceNotFound
+		}
+		return nil, fmt.Errorf("failed to get geofence: %w", err)
+	}
+	return geofence, nil
+}
+
+// List retrieves a page of geofences
+func (s *GeofenceService) List(search string, active *bool, page, limit, offset int) (*GeofenceList, error) {
+	geofences, total, err := s.geofenceRepo.List(search, active, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list geofences: %w", err)
+	}
+	return &GeofenceList{Geofences: geofences, Total: total, Page: page, Limit: limit}, nil
+}
+
+// Update replaces a geofence definition. Changing the geometry resets the tracked vehicle
+// states, so the next position of each vehicle is taken as its starting point.
+func (s *GeofenceService) Update(id uint, req *GeofenceRequest) (*domain.Geofence, error) {
+	geofence, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	previousGeometry := geofenceGeometry(geofence)
+
+	if err := s.applyRequest(geofence, req); err != nil {
+		return nil, err
+	}
+	if err := s.geofenceRepo.Update(geofence); err != nil {
+		return nil, fmt.Errorf("failed to update geofence: %w", err)
+	}
+	if geofenceGeometry(geofence) != previousGeometry {
+		if err := s.geofenceRepo.DeleteVehicleStates(id); err != nil {
+			return nil, fmt.Errorf("failed to reset geofence states: %w", err)
+		}
+	}
+	s.invalidateCache()
+	return geofence, nil
+}
+
+// Delete deletes a geofence with its events and alert rules
+func (s *GeofenceService) Delete(id uint) error {
+	if err := s.geofenceRepo.Delete(id); err != nil {
+		if isNotFound(err) {
+			return ErrGeofenceNotFound
+		}
+		return fmt.Errorf("failed to delete geofence: %w", err)
+	}
+	s.invalidateCache()
+	return nil
+}
+
+// ListEvents retrieves a page of geofence events
+func (s *GeofenceService) ListEvents(filter interfaces.GeofenceEventFilter, page, limit, offset int) (*GeofenceEventList, error) {
+	events, total, err := s.geofenceRepo.ListEvents(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list geofence events: %w", err)
+	}
+	return &GeofenceEventList{Events: events, Total: total, Page: page, Limit: limit}, nil
+}
+
+// CreateAlertRule adds an alert rule to a geofence
+func (s *GeofenceService) CreateAlertRule(geofenceID uint, req *GeofenceAlertRuleRequest, createdBy uint) (*domain.GeofenceAlertRule, error) {
+	if _, err := s.GetByID(geofenceID); err != nil {
+		return nil, err
+	}
+
+	rule := &domain.GeofenceAlertRule{GeofenceID: geofenceID, IsActive: true, CreatedBy: createdBy}
+	if err := s.applyAlertRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.geofenceRepo.CreateAlertRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to create geofence alert rule: %w", err)
+	}
+	s.invalidateCache()
+	return rule, nil
+}
+
+// ListAlertRules retrieves the alert rules of a geofence
+func (s *GeofenceService) ListAlertRules(geofenceID uint) ([]*domain.GeofenceAlertRule, error) {
+	if _, err := s.GetByID(geofenceID); err != nil {
+		return nil, err
+	}
+	rules, err := s.geofenceRepo.ListAlertRules(geofenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list geofence alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateAlertRule replaces an alert rule
+func (s *GeofenceService) UpdateAlertRule(id uint, req *GeofenceAlertRuleRequest) (*domain.GeofenceAlertRule, error) {
+	rule, err := s.geofenceRepo.GetAlertRule(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrGeofenceAlertRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get geofence alert rule: %w", err)
+	}
+
+	if err := s.applyAlertRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.geofenceRepo.UpdateAlertRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to update geofence alert rule: %w", err)
+	}
+	s.invalidateCache()
+	return rule, nil
+}
+
+// DeleteAlertRule deletes an alert rule
+func (s *GeofenceService) DeleteAlertRule(id uint) error {
+	if err := s.geofenceRepo.DeleteAlertRule(id); err != nil {
+		if isNotFound(err) {
+			return ErrGeofenceAlertRuleNotFound
+		}
+		return fmt.Errorf("failed to delete geofence alert rule: %w", err)
+	}
+	s.invalidateCache()
+	return nil
+}
+
+// HandleTelematics evaluates stored samples against the active geofences; it is registered as an ingestion listener
+func (s *GeofenceService) HandleTelematics(samples []*domain.TelematicsData) {
+	geofences, rules, err := s.activeGeofences()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to load geofences")
+		return
+	}
+	if len(geofences) == 0 {
+		return
+	}
+
+	vehicleIDs := make([]uint, 0)
+	seen := make(map[uint]bool)
+	for _, sample := range samples {
+		if !seen[sample.VehicleID] {
+			seen[sample.VehicleID] = true
+			vehicleIDs = append(vehicleIDs, sample.VehicleID)
+		}
+	}
+	vehicles := s.vehicleInfo(vehicleIDs)
+
+	stored, err := s.geofenceRepo.GetVehicleStates(vehicleIDs)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to load geofence states")
+		return
+	}
+	states := make(map[geofenceStateKey]*domain.GeofenceVehicleState, len(stored))
+	for _, state := range stored {
+		states[geofenceStateKey{state.GeofenceID, state.VehicleID}] = state
+	}
+
+	changed := make(map[geofenceStateKey]*domain.GeofenceVehicleState)
+	var events []*domain.GeofenceEvent
+	for _, sample := range samples {
+		if sample.Latitude == 0 && sample.Longitude == 0 {
+			continue
+		}
+		category := ""
+		if info := vehicles[sample.VehicleID]; info != nil {
+			category = info.category
+		}
+
+		for _, geofence := range geofences {
+			if !geofence.AppliesTo(sample.VehicleID, category) {
+				continue
+			}
+			key := geofenceStateKey{geofence.ID, sample.VehicleID}
+			event := evaluateGeofence(geofence, states, key, sample)
+			changed[key] = states[key]
+			if event != nil {
+				events = append(events, event)
+			}
+		}
+	}
+
+	if err := s.geofenceRepo.CreateEvents(events); err != nil {
+		s.logger.WithError(err).Error("Failed to store geofence events")
+		return
+	}
+	toSave := make([]*domain.GeofenceVehicleState, 0, len(changed))
+	for _, state := range changed {
+		toSave = append(toSave, state)
+	}
+	if err := s.geofenceRepo.SaveVehicleStates(toSave); err != nil {
+		s.logger.WithError(err).Error("Failed to store geofence states")
+	}
+
+	for _, event := range events {
+		s.alert(event, geofenceByID(geofences, event.GeofenceID), vehicles[event.VehicleID], rules[event.GeofenceID])
+	}
+}
+
+// evaluateGeofence advances the state of a vehicle for one sample and returns the event it raises, if any.
+// The first position seen for a vehicle only establishes its state.
+func evaluateGeofence(geofence *domain.Geofence, states map[geofenceStateKey]*domain.GeofenceVehicleState, key geofenceStateKey, sample *domain.TelematicsData) *domain.GeofenceEvent {
+	at := sample.Timestamp
+	inside := geofence.Contains(sample.Latitude, sample.Longitude)
+
+	state := states[key]
+	if state == nil {
+		state = &domain.GeofenceVehicleState{GeofenceID: key.geofenceID, VehicleID: key.vehicleID, Inside: inside, SampleAt: at}
+		if inside {
+			state.EnteredAt = &at
+		}
+		states[key] = state
+		return nil
+	}
+	// Samples older than the state were already evaluated or arrived out of order
+	if !at.After(state.SampleAt) {
+		return nil
+	}
+	state.SampleAt = at
+
+	var eventType string
+	var dwellSeconds *int
+	switch {
+	case inside && !state.Inside:
+		state.Inside, state.EnteredAt, state.DwellReported = true, &at, false
+		eventType = domain.GeofenceEventEnter
+	case !inside && state.Inside:
+		if state.EnteredAt != nil {
+			seconds := int(at.Sub(*state.EnteredAt).Seconds())
+			dwellSeconds = &seconds
+		}
+		state.Inside, state.EnteredAt, state.DwellReported = false, nil, false
+		eventType = domain.GeofenceEventExit
+	case inside && geofence.DwellThresholdMinutes != nil && !state.DwellReported && state.EnteredAt != nil:
+		stay := at.Sub(*state.EnteredAt)
+		if stay < time.Duration(*geofence.DwellThresholdMinutes)*time.Minute {
+			return nil
+		}
+		state.DwellReported = true
+		seconds := int(stay.Seconds())
+		dwellSeconds = &seconds
+		eventType = domain.GeofenceEventDwell
+	default:
+		return nil
+	}
+
+	if !geofence.IsActiveAt(at) {
+		return nil
+	}
+	return &domain.GeofenceEvent{
+		GeofenceID:   key.geofenceID,
+		VehicleID:    key.vehicleID,
+		EventType:    eventType,
+		OccurredAt:   at,
+		Latitude:     sample.Latitude,
+		Longitude:    sample.Longitude,
+		DwellSeconds: dwellSeconds,
+	}
+}
+
+// alert notifies the recipients of the rules matching an event
+func (s *GeofenceService) alert(event *domain.GeofenceEvent, geofence *domain.Geofence, vehicle *geofenceVehicleInfo, rules []*domain.GeofenceAlertRule) {
+	var recipients []uint
+	for _, rule := range rules {
+		if rule.EventType == event.EventType {
+			recipients = append(recipients, rule.RecipientUserIDs...)
+		}
+	}
+	if len(recipients) == 0 || geofence == nil {
+		return
+	}
+
+	plate := fmt.Sprintf("Vehicle %d", event.VehicleID)
+	if vehicle != nil && vehicle.plateNumber != "" {
+		plate = vehicle.plateNumber
+	}
+	location, err := time.LoadLocation(geofence.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	at := event.OccurredAt.In(location).Format("2006-01-02 15:04 MST")
+
+	var title, message string
+	switch event.EventType {
+	case domain.GeofenceEventEnter:
+		title = fmt.Sprintf("%s entered %s", plate, geofence.Name)
+		message = fmt.Sprintf("%s entered geofence %s at %s.", plate, geofence.Name, at)
+	case domain.GeofenceEventExit:
+		title = fmt.Sprintf("%s left %s", plate, geofence.Name)
+		message = fmt.Sprintf("%s left geofence %s at %s.", plate, geofence.Name, at)
+	case domain.GeofenceEventDwell:
+		title = fmt.Sprintf("%s is dwelling in %s", plate, geofence.Name)
+		message = fmt.Sprintf("%s has been inside geofence %s for %d minutes as of %s.",
+			plate, geofence.Name, *event.DwellSeconds/60, at)
+	}
+
+	err = s.notificationService.Notify(recipients, NotificationMessage{
+		Type:          "geofence_" + event.EventType,
+		Title:         title,
+		Message:       message,
+		ReferenceType: "geofence_event",
+		ReferenceID:   event.ID,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("geofence_event_id", event.ID).Error("Failed to send geofence alert")
+	}
+}
+
+// activeGeofences returns the cached active geofences and alert rules, reloading them when stale
+func (s *GeofenceService) activeGeofences() ([]*domain.Geofence, map[uint][]*domain.GeofenceAlertRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) < geofenceCacheTTL {
+		return s.geofences, s.rules, nil
+	}
+
+	geofences, err := s.geofenceRepo.ListActive()
+	if err != nil {
+		return nil, nil, err
+	}
+	activeRules, err := s.geofenceRepo.ListActiveAlertRules()
+	if err != nil {
+		return nil, nil, err
+	}
+	rules := make(map[uint][]*domain.GeofenceAlertRule)
+	for _, rule := range activeRules {
+		rules[rule.GeofenceID] = append(rules[rule.GeofenceID], rule)
+	}
+
+	s.geofences, s.rules, s.loadedAt = geofences, rules, time.Now()
+	return geofences, rules, nil
+}
+
+// vehicleInfo returns plate numbers and categories, loading vehicles missing from the cache
+func (s *GeofenceService) vehicleInfo(vehicleIDs []uint) map[uint]*geofenceVehicleInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var missing []uint
+	for _, id := range vehicleIDs {
+		if info, ok := s.vehicles[id]; !ok || time.Since(info.loadedAt) > geofenceVehicleCacheTTL {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		loaded, err := s.vehicleRepo.GetByIDs(missing)
+		if err != nil {
+			// Category assignments are skipped until the vehicles can be loaded
+			s.logger.WithError(err).Warn("Failed to load vehicles for geofence evaluation")
+		}
+		now := time.Now()
+		for _, vehicle := range loaded {
+			s.vehicles[vehicle.ID] = &geofenceVehicleInfo{plateNumber: vehicle.PlateNumber, category: vehicle.Category, loadedAt: now}
+		}
+	}
+
+	result := make(map[uint]*geofenceVehicleInfo, len(vehicleIDs))
+	for _, id := range vehicleIDs {
+		result[id] = s.vehicles[id]
+	}
+	return result
+}
+
+func (s *GeofenceService) invalidateCache() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// applyRequest validates a geofence request and copies it onto the geofence
+func (s *GeofenceService) applyRequest(geofence *domain.Geofence, req *GeofenceRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	geofence.Name = req.Name
+	geofence.Description = req.Description
+	geofence.Shape = req.Shape
+	geofence.CenterLatitude, geofence.CenterLongitude, geofence.RadiusMeters, geofence.Polygon = nil, nil, nil, nil
+
+	switch req.Shape {
+	case domain.GeofenceShapeCircle:
+		if req.CenterLatitude == nil || req.CenterLongitude == nil || req.RadiusMeters == nil {
+			return fmt.Errorf("validation failed: circle geofences require center_latitude, center_longitude and radius_meters")
+		}
+		if *req.RadiusMeters > maxGeofenceRadiusMeters {
+			return fmt.Errorf("validation failed: radius_meters must be at most %d", maxGeofenceRadiusMeters)
+		}
+		geofence.CenterLatitude, geofence.CenterLongitude, geofence.RadiusMeters = req.CenterLatitude, req.CenterLongitude, req.RadiusMeters
+	case domain.GeofenceShapePolygon:
+		if len(req.Polygon) < 3 || len(req.Polygon) > maxGeofencePolygonPoints {
+			return fmt.Errorf("validation failed: polygons need between 3 and %d points", maxGeofencePolygonPoints)
+		}
+		for _, point := range req.Polygon {
+			if point.Latitude < -90 || point.Latitude > 90 || point.Longitude < -180 || point.Longitude > 180 {
+				return fmt.Errorf("validation failed: polygon point %.6f,%.6f is out of range", point.Latitude, point.Longitude)
+			}
+		}
+		geofence.Polygon = domain.GeofencePolygon(req.Polygon)
+	}
+	geofence.UpdateBounds()
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("validation failed: unknown timezone %s", timezone)
+	}
+	geofence.Timezone = timezone
+
+	for _, window := range req.Schedule {
+		if len(window.Days) == 0 {
+			return fmt.Errorf("validation failed: schedule windows need at least one day")
+		}
+		for _, day := range window.Days {
+			if day < 0 || day > 6 {
+				return fmt.Errorf("validation failed: schedule days must be 0 (Sunday) to 6 (Saturday)")
+			}
+		}
+		if _, err := domain.ParseClock(window.Start); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		if _, err := domain.ParseClock(window.End); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+	geofence.Schedule = domain.GeofenceSchedule(req.Schedule)
+	geofence.DwellThresholdMinutes = req.DwellThresholdMinutes
+	if req.IsActive != nil {
+		geofence.IsActive = *req.IsActive
+	}
+
+	assignments := make([]*domain.GeofenceAssignment, 0, len(req.VehicleIDs)+len(req.VehicleCategories))
+	if len(req.VehicleIDs) > 0 {
+		ids := uniqueUints(req.VehicleIDs)
+		vehicles, err := s.vehicleRepo.GetByIDs(ids)
+		if err != nil {
+			return fmt.Errorf("failed to get vehicles: %w", err)
+		}
+		if len(vehicles) != len(ids) {
+			return ErrVehicleNotFound
+		}
+		for _, id := range ids {
+			vehicleID := id
+			assignments = append(assignments, &domain.GeofenceAssignment{GeofenceID: geofence.ID, VehicleID: &vehicleID})
+		}
+	}
+	categories := make(map[string]bool)
+	for _, category := range req.VehicleCategories {
+		if categories[category] {
+			continue
+		}
+		categories[category] = true
+		vehicleCategory := category
+		assignments = append(assignments, &domain.GeofenceAssignment{GeofenceID: geofence.ID, VehicleCategory: &vehicleCategory})
+	}
+	geofence.Assignments = assignments
+	return nil
+}
+
+// applyAlertRuleRequest validates an alert rule request and copies it onto the rule
+func (s *GeofenceService) applyAlertRuleRequest(rule *domain.GeofenceAlertRule, req *GeofenceAlertRuleRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	recipients := uniqueUints(req.RecipientUserIDs)
+	for _, userID := range recipients {
+		if _, err := s.userRepo.GetByID(userID); err != nil {
+			if isNotFound(err) {
+				return fmt.Errorf("validation failed: user %d not found", userID)
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+	}
+
+	rule.EventType = req.EventType
+	rule.RecipientUserIDs = domain.UintList(recipients)
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// geofenceGeometry serializes the fields that decide containment
+func geofenceGeometry(geofence *domain.Geofence) string {
+	data, _ := json.Marshal([]interface{}{
+		geofence.Shape, geofence.CenterLatitude, geofence.CenterLongitude, geofence.RadiusMeters, geofence.Polygon,
+	})
+	return string(data)
+}
+
+func geofenceByID(geofences []*domain.Geofence, id uint) *domain.Geofence {
+	for _, geofence := range geofences {
+		if geofence.ID == id {
+			return geofence
+		}
+	}
+	return nil
+}
+
+func uniqueUints(values []uint) []uint {
+	seen := make(map[uint]bool, len(values))
+	unique := make([]uint, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,500 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Inspection service errors
+var (
+	ErrInspectionTemplateNotFound = errors.New("inspection template not found")
+	ErrInspectionTemplateExists   = errors.New("inspection template code already exists")
+	ErrInspectionNotFound         = errors.New("inspection not found")
+	ErrInvalidInspection          = errors.New("invalid inspection submission")
+)
+
+// InspectionService handles inspection templates and submissions
+type InspectionService struct {
+	inspectionRepo interfaces.InspectionRepository
+	vehicleRepo    interfaces.VehicleRepository
+	workOrderRepo  interfaces.WorkOrderRepository
+	validator      *validator.Validate
+	logger         *logrus.Logger
+}
+
+// TemplateItemRequest represents a checklist item in a template request
+type TemplateItemRequest struct {
+	Code           string   `json:"code" validate:"required,max=50"`
+	Label          string   `json:"label" validate:"required,max=200"`
+	ItemType       string   `json:"item_type" validate:"required,oneof=pass_fail value text"`
+	Unit           string   `json:"unit" validate:"max=20"`
+	MinValue       *float64 `json:"min_value"`
+	MaxValue       *float64 `json:"max_value"`
+	IsRequired     *bool    `json:"is_required"`
+	IsCritical     bool     `json:"is_critical"`
+	PhotosRequired int      `json:"photos_required" validate:"min=0,max=10"`
+}
+
+// TemplateSectionRequest represents a checklist section in a template request
+type TemplateSectionRequest struct {
+	Title string                `json:"title" validate:"required,max=100"`
+	Items []TemplateItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// InspectionTemplateRequest represents template creation and new version requests
+type InspectionTemplateRequest struct {
+	Code        string                   `json:"code" validate:"required,max=50"`
+	Name        string                   `json:"name" validate:"required,max=100"`
+	Description string                   `json:"description"`
+	VehicleType string                   `json:"vehicle_type" validate:"omitempty,oneof=sedan suv truck van motorcycle bus"`
+	Sections    []TemplateSectionRequest `json:"sections" validate:"required,min=1,dive"`
+}
+
+// InspectionResultRequest represents the answer for one checklist item
+type InspectionResultRequest struct {
+	ItemID    uint     `json:"item_id" validate:"required"`
+	Result    string   `json:"result" validate:"omitempty,oneof=pass fail na"`
+	Value     *float64 `json:"value"`
+	Text      string   `json:"text"`
+	PhotoURLs []string `json:"photo_urls" validate:"dive,url"`
+	Notes     string   `json:"notes"`
+}
+
+// SubmitInspectionRequest represents an inspection submission
+type SubmitInspectionRequest struct {
+	VehicleID   uint                      `json:"vehicle_id" validate:"required"`
+	WorkOrderID *uint                     `json:"work_order_id"`
+	TemplateID  uint                      `json:"template_id" validate:"required"`
+	Odometer    int                       `json:"odometer" validate:"min=0"`
+	Notes       string                    `json:"notes"`
+	Results     []InspectionResultRequest `json:"results" validate:"required,min=1,dive"`
+}
+
+// NewInspectionService creates a new inspection service
+func NewInspectionService(
+	inspectionRepo interfaces.InspectionRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	workOrderRepo interfaces.WorkOrderRepository,
+	logger *logrus.Logger,
+) *InspectionService {
+	return &InspectionService{
+		inspectionRepo: inspectionRepo,
+		vehicleRepo:    vehicleRepo,
+		workOrderRepo:  workOrderRepo,
+		validator:      validator.New(),
+		logger:         logger,
+	}
+}
+
+// CreateTemplate creates the first version of a checklist template
+func (s *InspectionService) CreateTemplate(req *InspectionTemplateRequest, createdBy uint) (*domain.InspectionTemplate, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	versions, err := s.inspectionRepo.GetTemplateVersions(req.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check template code: %w", err)
+	}
+	if len(versions) > 0 {
+		return nil, ErrInspectionTemplateExists
+	}
+
+	template, err := buildTemplate(req, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.inspectionRepo.PublishTemplateVersion(template); err != nil {
+		s.logger.WithError(err).Error("Inspection template creation failed")
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"template_id": template.ID,
+		"code":        template.Code,
+		"version":     template.Version,
+	}).Info("Inspection template created")
+
+	return s.inspectionRepo.GetTemplateByID(template.ID)
+}
+
+// PublishNewVersion replaces the checklist of an existing template with a new version.
+// Earlier versions are kept so that past inspections remain readable.
+func (s *InspectionService) PublishNewVersion(templateID uint, req *InspectionTemplateRequest, createdBy uint) (*domain.InspectionTemplate, error) {
+	current, err := s.inspectionRepo.GetTemplateByID(templateID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrInspectionTemplateNotFound
+		}
+		return nil, err
+	}
+
+	// The code identifies the template family and cannot be changed
+	req.Code = current.Code
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	template, err := buildTemplate(req, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.inspectionRepo.PublishTemplateVersion(template); err != nil {
+		s.logger.WithError(err).Error("Inspection template versioning failed")
+		return nil, fmt.Errorf("failed to publish template version: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"template_id": template.ID,
+		"code":        template.Code,
+		"version":     template.Version,
+	}).Info("Inspection template version published")
+
+	return s.inspectionRepo.GetTemplateByID(template.ID)
+}
+
+// GetTemplate retrieves a template version with its checklist
+func (s *InspectionService) GetTemplate(id uint) (*domain.InspectionTemplate, error) {
+	template, err := s.inspectionRepo.GetTemplateByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrInspectionTemplateNotFound
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+// ListTemplates lists templates, by default only the active versions
+func (s *InspectionService) ListTemplates(activeOnly bool) ([]*domain.InspectionTemplate, error) {
+	return s.inspectionRepo.GetTemplates(activeOnly)
+}
+
+// GetTemplateVersions lists all versions of the template family the given template belongs to
+func (s *InspectionService) GetTemplateVersions(id uint) ([]*domain.InspectionTemplate, error) {
+	template, err := s.GetTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.inspectionRepo.GetTemplateVersions(template.Code)
+}
+
+// DeactivateTemplate retires a template so it can no longer be used for new inspections
+func (s *InspectionService) DeactivateTemplate(id uint) error {
+	if _, err := s.GetTemplate(id); err != nil {
+		return err
+	}
+	return s.inspectionRepo.DeactivateTemplate(id)
+}
+
+// Submit validates and stores an inspection against its template. Failed
+// critical items automatically raise a repair work order for the vehicle.
+func (s *InspectionService) Submit(req *SubmitInspectionRequest, inspectorID uint) (*domain.Inspection, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	vehicle, err := s.vehicleRepo.GetByID(req.VehicleID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, err
+	}
+
+	if req.WorkOrderID != nil {
+		workOrder, err := s.workOrderRepo.GetByID(*req.WorkOrderID)
+		if err != nil {
+			if isNotFound(err) {
+				return nil, ErrWorkOrderNotFound
+			}
+			return nil, err
+		}
+		if workOrder.VehicleID != vehicle.ID {
+			return nil, fmt.Errorf("%w: work order belongs to another vehicle", ErrInvalidInspection)
+		}
+	}
+
+	template, err := s.GetTemplate(req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if !template.IsActive {
+		return nil, fmt.Errorf("%w: template version %d is no longer active", ErrInvalidInspection, template.Version)
+	}
+	if template.VehicleType != "" && template.VehicleType != vehicle.Type {
+		return nil, fmt.Errorf("%w: template applies to %s vehicles", ErrInvalidInspection, template.VehicleType)
+	}
+
+	results, err := evaluateResults(template, req.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	inspection := &domain.Inspection{
+		InspectionNumber: generateNumber("INS"),
+		VehicleID:        vehicle.ID,
+		WorkOrderID:      req.WorkOrderID,
+		TemplateID:       template.ID,
+		InspectorID:      inspectorID,
+		Odometer:         req.Odometer,
+		Outcome:          inspectionOutcome(results),
+		Notes:            req.Notes,
+		Results:          results,
+		SubmittedAt:      time.Now().UTC(),
+	}
+
+	if err := s.inspectionRepo.Create(inspection); err != nil {
+		s.logger.WithError(err).Error("Inspection submission failed")
+		return nil, fmt.Errorf("failed to store inspection: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"inspection_id": inspection.ID,
+		"vehicle_id":    vehicle.ID,
+		"template_id":   template.ID,
+		"outcome":       inspection.Outcome,
+	}).Info("Inspection submitted")
+
+	if inspection.Outcome == domain.InspectionOutcomeFailed {
+		// The inspection is already recorded; a failure here must not lose it
+		if err := s.raiseDefectWorkOrder(inspection, vehicle, inspectorID); err != nil {
+			s.logger.WithError(err).WithField("inspection_id", inspection.ID).
+				Error("Failed to create defect work order")
+		}
+	}
+
+	return s.inspectionRepo.GetByID(inspection.ID)
+}
+
+// GetInspection retrieves a submitted inspection
+func (s *InspectionService) GetInspection(id uint) (*domain.Inspection, error) {
+	inspection, err := s.inspectionRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrInspectionNotFound
+		}
+		return nil, err
+	}
+	return inspection, nil
+}
+
+// GetVehicleHistory retrieves the inspection history of a vehicle
+func (s *InspectionService) GetVehicleHistory(vehicleID uint, offset, limit int) ([]*domain.Inspection, int64, error) {
+	if _, err := s.vehicleRepo.GetByID(vehicleID); err != nil {
+		if isNotFound(err) {
+			return nil, 0, ErrVehicleNotFound
+		}
+		return nil, 0, err
+	}
+
+	inspections, err := s.inspectionRepo.GetByVehicle(vehicleID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.inspectionRepo.CountByVehicle(vehicleID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return inspections, total, nil
+}
+
+// raiseDefectWorkOrder opens one repair work order covering all failed critical items
+func (s *InspectionService) raiseDefectWorkOrder(inspection *domain.Inspection, vehicle *domain.Vehicle, requestedBy uint) error {
+	var defects []string
+	var resultIDs []uint
+	for _, result := range inspection.Results {
+		if result.Result == domain.InspectionResultFail && result.Item != nil && result.Item.IsCritical {
+			line := result.Item.Label
+			if result.Notes != "" {
+				line += ": " + result.Notes
+			}
+			defects = append(defects, "- "+line)
+			resultIDs = append(resultIDs, result.ID)
+		}
+	}
+	if len(resultIDs) == 0 {
+		return nil
+	}
+
+	workOrder := &domain.WorkOrder{
+		CustomerName:     internalCustomerName(vehicle),
+		VehicleID:        vehicle.ID,
+		ServiceType:      domain.ServiceTypeRepair,
+		Priority:         domain.PriorityHigh,
+		Status:           domain.StatusPending,
+		Description:      fmt.Sprintf("Critical defects found in inspection %s:\n%s", inspection.InspectionNumber, strings.Join(defects, "\n")),
+		ServiceAdvisorID: requestedBy,
+	}
+	if err := s.inspectionRepo.CreateDefectWorkOrder(workOrder, resultIDs); err != nil {
+		return err
+	}
+	if workOrder.ID == 0 {
+		return nil // the defects already have a work order
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"inspection_id": inspection.ID,
+		"work_order_id": workOrder.ID,
+		"defects":       len(resultIDs),
+	}).Info("Defect work order created from inspection")
+
+	return nil
+}
+
+// buildTemplate converts a template request into a domain template
+func buildTemplate(req *InspectionTemplateRequest, createdBy uint) (*domain.InspectionTemplate, error) {
+	template := &domain.InspectionTemplate{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		VehicleType: req.VehicleType,
+		CreatedBy:   createdBy,
+	}
+
+	codes := make(map[string]bool)
+	for i, sectionReq := range req.Sections {
+		section := domain.InspectionSection{
+			Title:     sectionReq.Title,
+			SortOrder: i,
+		}
+		for j, itemReq := range sectionReq.Items {
+			if codes[itemReq.Code] {
+				return nil, fmt.Errorf("validation failed: duplicate item code %s", itemReq.Code)
+			}
+			codes[itemReq.Code] = true
+
+			if itemReq.MinValue != nil && itemReq.MaxValue != nil && *itemReq.MinValue > *itemReq.MaxValue {
+				return nil, fmt.Errorf("validation failed: item %s has min_value greater than max_value", itemReq.Code)
+			}
+
+			isRequired := true
+			if itemReq.IsRequired != nil {
+				isRequired = *itemReq.IsRequired
+			}
+
+			section.Items = append(section.Items, domain.InspectionItem{
+				Code:           itemReq.Code,
+				Label:          itemReq.Label,
+				ItemType:       itemReq.ItemType,
+				Unit:           itemReq.Unit,
+				MinValue:       itemReq.MinValue,
+				MaxValue:       itemReq.MaxValue,
+				IsRequired:     isRequired,
+				IsCritical:     itemReq.IsCritical,
+				PhotosRequired: itemReq.PhotosRequired,
+				SortOrder:      j,
+			})
+		}
+		template.Sections = append(template.Sections, section)
+	}
+
+	return template, nil
+}
+
+// evaluateResults checks submitted answers against the template and derives pass/fail
+func evaluateResults(template *domain.InspectionTemplate, answers []InspectionResultRequest) ([]domain.InspectionResult, error) {
+	items := make(map[uint]*domain.InspectionItem)
+	for i := range template.Sections {
+		for j := range template.Sections[i].Items {
+			item := &template.Sections[i].Items[j]
+			items[item.ID] = item
+		}
+	}
+
+	answered := make(map[uint]bool)
+	results := make([]domain.InspectionResult, 0, len(answers))
+	for _, answer := range answers {
+		item, ok := items[answer.ItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: item %d is not part of template", ErrInvalidInspection, answer.ItemID)
+		}
+		if answered[item.ID] {
+			return nil, fmt.Errorf("%w: item %s answered more than once", ErrInvalidInspection, item.Code)
+		}
+		answered[item.ID] = true
+
+		result := domain.InspectionResult{
+			ItemID:    item.ID,
+			Item:      item,
+			Result:    answer.Result,
+			Value:     answer.Value,
+			Text:      answer.Text,
+			PhotoURLs: domain.StringList(answer.PhotoURLs),
+			Notes:     answer.Notes,
+		}
+
+		if result.Result == domain.InspectionResultNA {
+			if item.IsRequired {
+				return nil, fmt.Errorf("%w: item %s is required", ErrInvalidInspection, item.Code)
+			}
+			results = append(results, result)
+			continue
+		}
+
+		switch item.ItemType {
+		case domain.InspectionItemTypePassFail:
+			if result.Result == "" {
+				return nil, fmt.Errorf("%w: item %s requires pass or fail", ErrInvalidInspection, item.Code)
+			}
+		case domain.InspectionItemTypeValue:
+			if answer.Value == nil {
+				return nil, fmt.Errorf("%w: item %s requires a value", ErrInvalidInspection, item.Code)
+			}
+			// Out-of-range readings always fail; in-range readings may still be failed manually
+			outOfRange := (item.MinValue != nil && *answer.Value < *item.MinValue) ||
+				(item.MaxValue != nil && *answer.Value > *item.MaxValue)
+			if outOfRange {
+				result.Result = domain.InspectionResultFail
+			} else if result.Result == "" {
+				result.Result = domain.InspectionResultPass
+			}
+		case domain.InspectionItemTypeText:
+			if item.IsRequired && strings.TrimSpace(answer.Text) == "" {
+				return nil, fmt.Errorf("%w: item %s requires a text answer", ErrInvalidInspection, item.Code)
+			}
+			if result.Result == "" {
+				result.Result = domain.InspectionResultPass
+			}
+		}
+
+		if len(answer.PhotoURLs) < item.PhotosRequired {
+			return nil, fmt.Errorf("%w: item %s requires %d photo(s)", ErrInvalidInspection, item.Code, item.PhotosRequired)
+		}
+
+		results = append(results, result)
+	}
+
+	for _, item := range items {
+		if item.IsRequired && !answered[item.ID] {
+			return nil, fmt.Errorf("%w: required item %s was not answered", ErrInvalidInspection, item.Code)
+		}
+	}
+
+	return results, nil
+}
+
+// inspectionOutcome derives the overall outcome from item results
+func inspectionOutcome(results []domain.InspectionResult) string {
+	outcome := domain.InspectionOutcomePassed
+	for _, result := range results {
+		if result.Result != domain.InspectionResultFail {
+			continue
+		}
+		if result.Item != nil && result.Item.IsCritical {
+			return domain.InspectionOutcomeFailed
+		}
+		outcome = domain.InspectionOutcomeAdvisory
+	}
+	return outcome
+}
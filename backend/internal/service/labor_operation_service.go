@@ -0,0 +1,505 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Labor operation service errors
+var (
+	ErrLaborOperationNotFound = errors.New("labor operation not found")
+	ErrLaborOperationExists   = errors.New("labor operation code already exists")
+)
+
+// laborOperationImportFields lists the columns of a labor operation import file with their aliases
+var laborOperationImportFields = []vehicleImportField{
+	{Name: "code", Required: true, MaxLength: 30, Aliases: []string{"operation_code", "op_code"}},
+	{Name: "description", Required: true, MaxLength: 200, Aliases: []string{"name", "operation", "operation_name"}},
+	{Name: "vehicle_types", Aliases: []string{"vehicle_type", "types", "type"}},
+	{Name: "makes", Aliases: []string{"make", "brands", "brand"}},
+	{Name: "standard_hours", Required: true, Aliases: []string{"hours", "flat_rate_hours", "standard_time"}},
+	{Name: "default_price", Aliases: []string{"price", "flat_rate_price"}},
+	{Name: "is_active", Aliases: []string{"active", "enabled"}},
+}
+
+// LaborOperationRequest represents a catalogued labor operation. Empty vehicle type and make
+// lists apply the operation to every vehicle.
+type LaborOperationRequest struct {
+	Code          string   `json:"code" validate:"required,max=30"`
+	Description   string   `json:"description" validate:"required,max=200"`
+	VehicleTypes  []string `json:"vehicle_types" validate:"max=20,dive,oneof=sedan suv truck van motorcycle bus"`
+	Makes         []string `json:"makes" validate:"max=50,dive,required,max=50"`
+	StandardHours float64  `json:"standard_hours" validate:"gt=0,max=999"`
+	DefaultPrice  *float64 `json:"default_price" validate:"omitempty,min=0"` // flat-rate price of the whole operation
+	IsActive      *bool    `json:"is_active"`                                // defaults to true
+}
+
+// LaborOperationList is a page of catalogued labor operations
+type LaborOperationList struct {
+	Operations []*domain.LaborOperation `json:"operations"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+}
+
+// LaborOperationImportReport summarizes a dry run or an applied catalogue import
+type LaborOperationImportReport struct {
+	DryRun      bool                    `json:"dry_run"`
+	Applied     bool                    `json:"applied"`
+	TotalRows   int                     `json:"total_rows"`
+	ValidRows   int                     `json:"valid_rows"`
+	InvalidRows int                     `json:"invalid_rows"`
+	Created     int                     `json:"created"`
+	Updated     int                     `json:"updated"`
+	Errors      []VehicleImportRowError `json:"errors"`
+}
+
+// TaskStandardComparison compares the standard and recorded hours of one task
+type TaskStandardComparison struct {
+	TaskID         uint      `json:"task_id"`
+	WorkOrderID    uint      `json:"work_order_id"`
+	WONumber       string    `json:"wo_number"`
+	CompletionDate time.Time `json:"completion_date"`
+	StandardHours  float64   `json:"standard_hours"`
+	ActualHours    float64   `json:"actual_hours"`
+	Variance       float64   `json:"variance"` // actual minus standard hours
+	Efficiency     *float64  `json:"efficiency"`
+}
+
+// OperationStandardComparison compares the standard and recorded hours of an operation across tasks
+type OperationStandardComparison struct {
+	OperationID        uint                      `json:"operation_id"`
+	Code               string                    `json:"code"`
+	Description        string                    `json:"description"`
+	TaskCount          int                       `json:"task_count"`
+	StandardHours      float64                   `json:"standard_hours"`
+	ActualHours        float64                   `json:"actual_hours"`
+	AverageActualHours float64                   `json:"average_actual_hours"` // per task, a hint for revising the standard
+	Variance           float64                   `json:"variance"`
+	LaborCost          float64                   `json:"labor_cost"`
+	Efficiency         *float64                  `json:"efficiency"` // standard per actual hour; above 1 beats the standard
+	Tasks              []*TaskStandardComparison `json:"tasks"`
+}
+
+// LaborStandardReport compares standard and recorded hours of catalogued operations on the work
+// orders completed in a period
+type LaborStandardReport struct {
+	From          time.Time                      `json:"from"`
+	To            time.Time                      `json:"to"`
+	StandardHours float64                        `json:"standard_hours"`
+	ActualHours   float64                        `json:"actual_hours"`
+	Efficiency    *float64                       `json:"efficiency"`
+	Operations    []*OperationStandardComparison `json:"operations"`
+}
+
+// LaborOperationService manages the catalogue of standard labor operations that work order tasks
+// and estimate labor lines reference, and compares their standard hours with the labor recorded
+type LaborOperationService struct {
+	operationRepo interfaces.LaborOperationRepository
+	validator     *validator.Validate
+	logger        *logrus.Logger
+}
+
+// NewLaborOperationService creates a new labor operation service
+func NewLaborOperationService(operationRepo interfaces.LaborOperationRepository, logger *logrus.Logger) *LaborOperationService {
+	return &LaborOperationService{
+		operationRepo: operationRepo,
+		validator:     validator.New(),
+		logger:        logger,
+	}
+}
+
+// Create adds an operation to the catalogue
+func (s *LaborOperationService) Create(req *LaborOperationRequest) (*domain.LaborOperation, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	operation := buildLaborOperation(req)
+	if err := s.checkCode(operation.Code, 0); err != nil {
+		return nil, err
+	}
+
+	if err := s.operationRepo.Create(operation); err != nil {
+		s.logger.WithError(err).Error("Labor operation creation failed")
+		return nil, fmt.Errorf("failed to create labor operation: %w", err)
+	}
+	return operation, nil
+}
+
+// Get retrieves a catalogued operation
+func (s *LaborOperationService) Get(id uint) (*domain.LaborOperation, error) {
+	operation, err := s.operationRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrLaborOperationNotFound
+		}
+		return nil, fmt.Errorf("failed to get labor operation: %w", err)
+	}
+	return operation, nil
+}
+
+// List retrieves a page of catalogued operations
+func (s *LaborOperationService) List(filter interfaces.LaborOperationFilter, page, limit, offset int) (*LaborOperationList, error) {
+	operations, total, err := s.operationRepo.List(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labor operations: %w", err)
+	}
+	return &LaborOperationList{Operations: operations, Total: total, Page: page, Limit: limit}, nil
+}
+
+// Update replaces a catalogued operation. Tasks already created keep the standard hours they were
+// created with.
+func (s *LaborOperationService) Update(id uint, req *LaborOperationRequest) (*domain.LaborOperation, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	existing, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	operation := buildLaborOperation(req)
+	if err := s.checkCode(operation.Code, existing.ID); err != nil {
+		return nil, err
+	}
+	operation.ID = existing.ID
+	operation.CreatedAt = existing.CreatedAt
+
+	if err := s.operationRepo.Update(operation); err != nil {
+		if isNotFound(err) {
+			return nil, ErrLaborOperationNotFound
+		}
+		return nil, fmt.Errorf("failed to update labor operation: %w", err)
+	}
+	return s.Get(id)
+}
+
+// Import validates a catalogue file and, unless dryRun is set, creates new codes and updates
+// existing ones in a single transaction. Nothing is stored when any row is invalid.
+func (s *LaborOperationService) Import(format string, file io.ReaderAt, size int64, dryRun bool) (*LaborOperationImportReport, error) {
+	headers, rows, err := readTable(format, file, size)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := laborOperationImportColumns(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, rowErrors := s.parseRows(rows, columns)
+	invalid := make(map[int]bool)
+	for _, rowErr := range rowErrors {
+		invalid[rowErr.Row] = true
+	}
+
+	codes := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		codes = append(codes, operation.Code)
+	}
+	existing, err := s.operationRepo.GetByCodes(codes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing labor operations: %w", err)
+	}
+	existingByCode := make(map[string]*domain.LaborOperation, len(existing))
+	for _, operation := range existing {
+		existingByCode[operation.Code] = operation
+	}
+
+	var created, updated []*domain.LaborOperation
+	for _, operation := range operations {
+		if current, ok := existingByCode[operation.Code]; ok {
+			operation.ID = current.ID
+			updated = append(updated, operation)
+		} else {
+			created = append(created, operation)
+		}
+	}
+
+	report := &LaborOperationImportReport{
+		DryRun:      dryRun,
+		TotalRows:   len(rows),
+		ValidRows:   len(operations),
+		InvalidRows: len(invalid),
+		Created:     len(created),
+		Updated:     len(updated),
+		Errors:      rowErrors,
+	}
+	if dryRun {
+		return report, nil
+	}
+	if len(rowErrors) > 0 {
+		return report, ErrImportValidation
+	}
+
+	if err := s.operationRepo.Import(created, updated); err != nil {
+		return nil, fmt.Errorf("failed to import labor operations: %w", err)
+	}
+	report.Applied = true
+
+	s.logger.WithFields(logrus.Fields{
+		"format":  format,
+		"created": report.Created,
+		"updated": report.Updated,
+	}).Info("Labor operations imported")
+	return report, nil
+}
+
+// Applicable retrieves an active operation that applies to the vehicle, for tasks and estimate
+// lines that reference it
+func (s *LaborOperationService) Applicable(id uint, vehicle *domain.Vehicle) (*domain.LaborOperation, error) {
+	operation, err := s.operationRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("validation failed: labor operation %d does not exist", id)
+		}
+		return nil, fmt.Errorf("failed to get labor operation: %w", err)
+	}
+	if !operation.IsActive {
+		return nil, fmt.Errorf("validation failed: labor operation %s is no longer active", operation.Code)
+	}
+	if vehicle != nil && !operation.AppliesTo(vehicle.Type, vehicle.Make) {
+		return nil, fmt.Errorf("validation failed: labor operation %s does not apply to a %s %s", operation.Code, vehicle.Make, vehicle.Type)
+	}
+	return operation, nil
+}
+
+// StandardReport compares the standard hours of operation tasks with the labor recorded on them
+// for the work orders completed in [from, to)
+func (s *LaborOperationService) StandardReport(filter interfaces.LaborStandardFilter) (*LaborStandardReport, error) {
+	if !filter.To.After(filter.From) {
+		return nil, ErrInvalidTimeRange
+	}
+	rows, err := s.operationRepo.ListStandardComparison(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labor: %w", err)
+	}
+
+	report := &LaborStandardReport{
+		From:       filter.From,
+		To:         filter.To,
+		Operations: []*OperationStandardComparison{},
+	}
+	operations := make(map[uint]*OperationStandardComparison)
+	for _, row := range rows {
+		operation, ok := operations[row.OperationID]
+		if !ok {
+			operation = &OperationStandardComparison{
+				OperationID: row.OperationID,
+				Code:        row.Code,
+				Description: row.Description,
+				Tasks:       []*TaskStandardComparison{},
+			}
+			operations[row.OperationID] = operation
+			report.Operations = append(report.Operations, operation)
+		}
+		operation.Tasks = append(operation.Tasks, &TaskStandardComparison{
+			TaskID:         row.TaskID,
+			WorkOrderID:    row.WorkOrderID,
+			WONumber:       row.WONumber,
+			CompletionDate: row.CompletionDate,
+			StandardHours:  row.StandardHours,
+			ActualHours:    roundTo(row.ActualHours, 2),
+			Variance:       roundTo(row.ActualHours-row.StandardHours, 2),
+			Efficiency:     efficiency(row.StandardHours, row.ActualHours),
+		})
+		operation.TaskCount++
+		operation.StandardHours += row.StandardHours
+		operation.ActualHours += row.ActualHours
+		operation.LaborCost += row.LaborCost
+	}
+
+	for _, operation := range report.Operations {
+		operation.StandardHours = roundTo(operation.StandardHours, 2)
+		operation.ActualHours = roundTo(operation.ActualHours, 2)
+		operation.AverageActualHours = roundTo(operation.ActualHours/float64(operation.TaskCount), 2)
+		operation.Variance = roundTo(operation.ActualHours-operation.StandardHours, 2)
+		operation.LaborCost = roundTo(operation.LaborCost, 2)
+		operation.Efficiency = efficiency(operation.StandardHours, operation.ActualHours)
+		report.StandardHours += operation.StandardHours
+		report.ActualHours += operation.ActualHours
+	}
+	report.StandardHours = roundTo(report.StandardHours, 2)
+	report.ActualHours = roundTo(report.ActualHours, 2)
+	report.Efficiency = efficiency(report.StandardHours, report.ActualHours)
+	return report, nil
+}
+
+// checkCode rejects a code that another operation already uses
+func (s *LaborOperationService) checkCode(code string, id uint) error {
+	existing, err := s.operationRepo.GetByCodes([]string{code})
+	if err != nil {
+		return fmt.Errorf("failed to check labor operation code: %w", err)
+	}
+	for _, operation := range existing {
+		if operation.ID != id {
+			return ErrLaborOperationExists
+		}
+	}
+	return nil
+}
+
+// parseRows converts catalogue rows into operations, collecting per-row validation errors and
+// codes repeated within the file
+func (s *LaborOperationService) parseRows(rows [][]string, columns map[string]int) ([]*domain.LaborOperation, []VehicleImportRowError) {
+	var operations []*domain.LaborOperation
+	var rowErrors []VehicleImportRowError
+	seenCodes := make(map[string]int)
+
+	for i, row := range rows {
+		rowNumber := i + 2 // header is row 1
+		values := make(map[string]string, len(laborOperationImportFields))
+		for _, field := range laborOperationImportFields {
+			if col, ok := columns[field.Name]; ok && col < len(row) {
+				values[field.Name] = strings.TrimSpace(row[col])
+			}
+		}
+		if isBlankRow(values) {
+			continue
+		}
+
+		addError := func(field, message string) {
+			rowErrors = append(rowErrors, VehicleImportRowError{
+				Row: rowNumber, Field: field, Value: values[field], Message: message,
+			})
+		}
+
+		req := &LaborOperationRequest{
+			Code:         values["code"],
+			Description:  values["description"],
+			VehicleTypes: splitListValue(values["vehicle_types"], normalizeEnumValue),
+			Makes:        splitListValue(values["makes"], strings.TrimSpace),
+		}
+
+		valid := true
+		for _, field := range laborOperationImportFields {
+			value := values[field.Name]
+			if field.Required && value == "" {
+				addError(field.Name, "value is required")
+				valid = false
+			}
+			if field.MaxLength > 0 && len(value) > field.MaxLength {
+				addError(field.Name, fmt.Sprintf("must be at most %d characters", field.MaxLength))
+				valid = false
+			}
+		}
+
+		if values["standard_hours"] != "" {
+			hours, err := strconv.ParseFloat(values["standard_hours"], 64)
+			if err != nil || hours <= 0 || hours > 999 {
+				addError("standard_hours", "must be a number of hours above 0 and at most 999")
+				valid = false
+			}
+			req.StandardHours = hours
+		}
+		if values["default_price"] != "" {
+			price, err := strconv.ParseFloat(values["default_price"], 64)
+			if err != nil || price < 0 {
+				addError("default_price", "must be a non-negative amount")
+				valid = false
+			}
+			req.DefaultPrice = &price
+		}
+		if values["is_active"] != "" {
+			active, err := strconv.ParseBool(strings.ToLower(values["is_active"]))
+			if err != nil {
+				addError("is_active", "must be true or false")
+				valid = false
+			}
+			req.IsActive = &active
+		}
+		for _, vehicleType := range req.VehicleTypes {
+			if !containsString(validVehicleTypes, vehicleType) {
+				addError("vehicle_types", "must be a list of: "+strings.Join(validVehicleTypes, ", "))
+				valid = false
+				break
+			}
+		}
+		for _, vehicleMake := range req.Makes {
+			if len(vehicleMake) > 50 {
+				addError("makes", "each make must be at most 50 characters")
+				valid = false
+				break
+			}
+		}
+
+		code := strings.ToUpper(req.Code)
+		if code != "" {
+			if first, ok := seenCodes[code]; ok {
+				addError("code", fmt.Sprintf("duplicate code, first seen in row %d", first))
+				valid = false
+			} else {
+				seenCodes[code] = rowNumber
+			}
+		}
+
+		if valid {
+			operations = append(operations, buildLaborOperation(req))
+		}
+	}
+	return operations, rowErrors
+}
+
+// buildLaborOperation converts a request into a catalogue operation with a normalized code
+func buildLaborOperation(req *LaborOperationRequest) *domain.LaborOperation {
+	operation := &domain.LaborOperation{
+		Code:          strings.ToUpper(strings.TrimSpace(req.Code)),
+		Description:   strings.TrimSpace(req.Description),
+		VehicleTypes:  domain.StringList{},
+		Makes:         domain.StringList{},
+		StandardHours: roundTo(req.StandardHours, 2),
+		DefaultPrice:  req.DefaultPrice,
+		IsActive:      req.IsActive == nil || *req.IsActive,
+	}
+	for _, vehicleType := range req.VehicleTypes {
+		if !operation.VehicleTypes.Contains(vehicleType) {
+			operation.VehicleTypes = append(operation.VehicleTypes, vehicleType)
+		}
+	}
+	for _, vehicleMake := range req.Makes {
+		if vehicleMake = strings.TrimSpace(vehicleMake); vehicleMake != "" && !operation.Makes.Contains(vehicleMake) {
+			operation.Makes = append(operation.Makes, vehicleMake)
+		}
+	}
+	return operation
+}
+
+// laborOperationImportColumns maps the catalogue fields to the file columns by name or known alias
+func laborOperationImportColumns(headers []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for _, field := range laborOperationImportFields {
+		candidates := append([]string{field.Name}, field.Aliases...)
+		for i, header := range headers {
+			if containsString(candidates, normalizeHeader(header)) {
+				columns[field.Name] = i
+				break
+			}
+		}
+		if _, ok := columns[field.Name]; field.Required && !ok {
+			return nil, fmt.Errorf("%w: required column %q is missing", ErrInvalidColumnMapping, field.Name)
+		}
+	}
+	return columns, nil
+}
+
+// splitListValue splits a list cell separated by semicolons, pipes or commas
+func splitListValue(value string, normalize func(string) string) []string {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == '|' || r == ','
+	})
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = normalize(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
@@ -0,0 +1,506 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Labor service errors
+var (
+	ErrWorkOrderTaskNotFound  = errors.New("work order task not found")
+	ErrWorkOrderTaskCompleted = errors.New("work order task is completed")
+	ErrWorkOrderNotInProgress = errors.New("time can only be clocked on work orders in progress")
+	ErrClockActive            = errors.New("mechanic is already clocked in")
+	ErrNoActiveClock          = errors.New("mechanic is not clocked in on this task")
+	ErrClockNotMechanic       = errors.New("only mechanics can clock time")
+)
+
+// WorkOrderTaskRequest represents a task added to a work order
+type WorkOrderTaskRequest struct {
+	OperationID    *uint   `json:"operation_id"` // catalogued operation the task performs
+	Description    string  `json:"description" validate:"required_without=OperationID,max=200"`
+	EstimatedHours float64 `json:"estimated_hours" validate:"min=0,max=999"` // defaults to the operation's standard hours
+}
+
+// ClockRequest carries the note recorded when clocking in or pausing
+type ClockRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// ClockOutRequest ends the mechanic's clock on a task
+type ClockOutRequest struct {
+	Note         string `json:"note" validate:"max=1000"`
+	CompleteTask bool   `json:"complete_task"`
+}
+
+// WorkOrderLaborSummary is the time clocked on a work order and the labor it rolled up into
+type WorkOrderLaborSummary struct {
+	Tasks          []*domain.WorkOrderTask  `json:"tasks"`
+	Entries        []*domain.LaborTimeEntry `json:"entries"`
+	Labor          []*domain.WorkOrderLabor `json:"labor"`
+	EstimatedHours float64                  `json:"estimated_hours"`
+	StandardHours  float64                  `json:"standard_hours"` // of the tasks that perform a catalogued operation
+	LaborHours     float64                  `json:"labor_hours"`
+	LaborCost      float64                  `json:"labor_cost"`
+	Efficiency     *float64                 `json:"efficiency"` // estimated per labor hour; nil until time is recorded
+}
+
+// WorkOrderEfficiency compares the estimated and recorded hours of a completed work order
+type WorkOrderEfficiency struct {
+	WorkOrderID    uint      `json:"work_order_id"`
+	WONumber       string    `json:"wo_number"`
+	ServiceType    string    `json:"service_type"`
+	CompletionDate time.Time `json:"completion_date"`
+	EstimatedHours float64   `json:"estimated_hours"`
+	ActualHours    float64   `json:"actual_hours"`
+	Variance       float64   `json:"variance"` // actual minus estimated hours
+	LaborCost      float64   `json:"labor_cost"`
+	Efficiency     *float64  `json:"efficiency"` // nil without an estimate
+}
+
+// MechanicEfficiency compares the hours a mechanic clocked on completed work orders with their
+// share of the estimates, split by the hours each mechanic recorded on a work order
+type MechanicEfficiency struct {
+	MechanicID   uint     `json:"mechanic_id"`
+	Name         string   `json:"name"`
+	WorkOrders   int      `json:"work_orders"`
+	ClockedHours float64  `json:"clocked_hours"`
+	EarnedHours  float64  `json:"earned_hours"`
+	LaborCost    float64  `json:"labor_cost"`
+	Efficiency   *float64 `json:"efficiency"` // earned per clocked hour; above 1 beats the estimates
+}
+
+// LaborEfficiencyReport compares estimated and actual hours of the work orders completed in a period
+type LaborEfficiencyReport struct {
+	From           time.Time              `json:"from"`
+	To             time.Time              `json:"to"`
+	EstimatedHours float64                `json:"estimated_hours"`
+	ActualHours    float64                `json:"actual_hours"`
+	Efficiency     *float64               `json:"efficiency"`
+	Mechanics      []*MechanicEfficiency  `json:"mechanics"`
+	WorkOrders     []*WorkOrderEfficiency `json:"work_orders"`
+}
+
+// LaborService runs the mechanics' time clock on work order tasks. Finished clock entries are
+// rolled up into work order labor at the mechanic's hourly rate, which sets the work order's
+// actual hours and cost.
+type LaborService struct {
+	laborRepo        interfaces.LaborRepository
+	mechanicRepo     interfaces.MechanicRepository
+	workOrderService *WorkOrderService
+	estimateService  *EstimateService
+	operationService *LaborOperationService
+	defaultRate      float64
+	validator        *validator.Validate
+	logger           *logrus.Logger
+}
+
+// NewLaborService creates a new labor service. defaultRate is the hourly rate of mechanics
+// without their own rate.
+func NewLaborService(
+	laborRepo interfaces.LaborRepository,
+	mechanicRepo interfaces.MechanicRepository,
+	workOrderService *WorkOrderService,
+	estimateService *EstimateService,
+	operationService *LaborOperationService,
+	defaultRate float64,
+	logger *logrus.Logger,
+) *LaborService {
+	return &LaborService{
+		laborRepo:        laborRepo,
+		mechanicRepo:     mechanicRepo,
+		workOrderService: workOrderService,
+		estimateService:  estimateService,
+		operationService: operationService,
+		defaultRate:      defaultRate,
+		validator:        validator.New(),
+		logger:           logger,
+	}
+}
+
+// CreateTask adds a task to an open work order. A task that performs a catalogued operation takes
+// its description and estimated hours from the operation unless given, and keeps its standard hours.
+func (s *LaborService) CreateTask(viewer Viewer, workOrderID uint, req *WorkOrderTaskRequest) (*domain.WorkOrderTask, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+
+	task := &domain.WorkOrderTask{
+		WorkOrderID:    workOrder.ID,
+		Description:    req.Description,
+		EstimatedHours: req.EstimatedHours,
+		Status:         domain.TaskStatusOpen,
+		CreatedBy:      viewer.UserID,
+	}
+	if req.OperationID != nil {
+		operation, err := s.operationService.Applicable(*req.OperationID, &workOrder.Vehicle)
+		if err != nil {
+			return nil, err
+		}
+		task.OperationID = &operation.ID
+		task.StandardHours = &operation.StandardHours
+		if task.Description == "" {
+			task.Description = operation.Description
+		}
+		if task.EstimatedHours == 0 {
+			task.EstimatedHours = operation.StandardHours
+		}
+	}
+	if err := s.laborRepo.CreateTask(task); err != nil {
+		s.logger.WithError(err).Error("Work order task creation failed")
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+	return task, nil
+}
+
+// ListTasks retrieves the tasks of a work order
+func (s *LaborService) ListTasks(viewer Viewer, workOrderID uint) ([]*domain.WorkOrderTask, error) {
+	if _, err := s.workOrderService.Get(viewer, workOrderID); err != nil {
+		return nil, err
+	}
+	return s.laborRepo.ListTasks(workOrderID)
+}
+
+// ClockIn starts the current mechanic's clock on a task of a work order in progress, or resumes
+// a paused one. A mechanic can only have one clock running.
+func (s *LaborService) ClockIn(viewer Viewer, workOrderID, taskID uint, req *ClockRequest) (*domain.LaborTimeEntry, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if viewer.Role != domain.RoleMechanic {
+		return nil, ErrClockNotMechanic
+	}
+	workOrder, task, err := s.getTask(viewer, workOrderID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if workOrder.Status != domain.StatusInProgress {
+		return nil, ErrWorkOrderNotInProgress
+	}
+	if task.Status == domain.TaskStatusCompleted {
+		return nil, ErrWorkOrderTaskCompleted
+	}
+	limit, err := s.estimateService.ApprovalLimit(workOrder.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &domain.LaborTimeEntry{
+		WorkOrderID: workOrder.ID,
+		TaskID:      task.ID,
+		MechanicID:  viewer.UserID,
+		StartedAt:   time.Now().UTC(),
+		Note:        req.Note,
+	}
+	if err := s.laborRepo.StartEntry(entry, func(active *domain.LaborTimeEntry, committed *interfaces.WorkOrderCommitment) error {
+		if active != nil {
+			return fmt.Errorf("%w on task %d of work order %d", ErrClockActive, active.TaskID, active.WorkOrderID)
+		}
+		return limit.Check(committed, nil)
+	}); err != nil {
+		if errors.Is(err, ErrClockActive) || errors.Is(err, ErrEstimateExceeded) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to clock in: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"work_order_id": workOrder.ID,
+		"task_id":       task.ID,
+		"mechanic_id":   viewer.UserID,
+	}).Info("Mechanic clocked in")
+	return entry, nil
+}
+
+// Pause stops the current mechanic's clock on a task for a break; clocking in resumes it
+func (s *LaborService) Pause(viewer Viewer, workOrderID, taskID uint, req *ClockRequest) (*domain.LaborTimeEntry, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return s.clockOut(viewer, workOrderID, taskID, domain.ClockEndPaused, req.Note, false)
+}
+
+// ClockOut stops the current mechanic's clock on a task, optionally completing the task
+func (s *LaborService) ClockOut(viewer Viewer, workOrderID, taskID uint, req *ClockOutRequest) (*domain.LaborTimeEntry, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return s.clockOut(viewer, workOrderID, taskID, domain.ClockEndClockedOut, req.Note, req.CompleteTask)
+}
+
+// ActiveClock retrieves the running clock entry of a mechanic, nil when not clocked in.
+// Mechanics can only view their own clock.
+func (s *LaborService) ActiveClock(viewer Viewer, mechanicID uint) (*domain.LaborTimeEntry, error) {
+	if err := checkMechanicAccess(viewer, mechanicID); err != nil {
+		return nil, err
+	}
+	entry, err := s.laborRepo.GetActiveEntry(mechanicID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active clock: %w", err)
+	}
+	return entry, nil
+}
+
+// GetLabor retrieves the tasks, clock entries and labor of a work order
+func (s *LaborService) GetLabor(viewer Viewer, workOrderID uint) (*WorkOrderLaborSummary, error) {
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.laborRepo.ListTasks(workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	entries, err := s.laborRepo.ListEntries(workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+	labor, err := s.laborRepo.ListLabor(workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labor: %w", err)
+	}
+
+	summary := &WorkOrderLaborSummary{
+		Tasks:          tasks,
+		Entries:        entries,
+		Labor:          labor,
+		EstimatedHours: workOrder.EstimatedHours,
+	}
+	for _, task := range tasks {
+		if task.StandardHours != nil {
+			summary.StandardHours += *task.StandardHours
+		}
+	}
+	summary.StandardHours = roundTo(summary.StandardHours, 2)
+	for _, line := range labor {
+		summary.LaborHours += line.HoursWorked
+		summary.LaborCost += line.TotalCost
+	}
+	summary.LaborHours = roundTo(summary.LaborHours, 2)
+	summary.LaborCost = roundTo(summary.LaborCost, 2)
+	summary.Efficiency = efficiency(summary.EstimatedHours, summary.LaborHours)
+	return summary, nil
+}
+
+// EfficiencyReport compares estimated and recorded hours of the work orders completed in [from, to)
+func (s *LaborService) EfficiencyReport(filter interfaces.LaborEfficiencyFilter) (*LaborEfficiencyReport, error) {
+	if !filter.To.After(filter.From) {
+		return nil, ErrInvalidTimeRange
+	}
+	rows, err := s.laborRepo.ListLaborEfficiency(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labor: %w", err)
+	}
+
+	report := &LaborEfficiencyReport{
+		From:       filter.From,
+		To:         filter.To,
+		Mechanics:  []*MechanicEfficiency{},
+		WorkOrders: []*WorkOrderEfficiency{},
+	}
+	workOrders := make(map[uint]*WorkOrderEfficiency)
+	for _, row := range rows {
+		workOrder, ok := workOrders[row.WorkOrderID]
+		if !ok {
+			workOrder = &WorkOrderEfficiency{
+				WorkOrderID:    row.WorkOrderID,
+				WONumber:       row.WONumber,
+				ServiceType:    row.ServiceType,
+				CompletionDate: row.CompletionDate,
+				EstimatedHours: row.EstimatedHours,
+			}
+			workOrders[row.WorkOrderID] = workOrder
+			report.WorkOrders = append(report.WorkOrders, workOrder)
+		}
+		workOrder.ActualHours += row.Hours
+		workOrder.LaborCost += row.Cost
+	}
+
+	mechanics := make(map[uint]*MechanicEfficiency)
+	for _, row := range rows {
+		if filter.MechanicID != 0 && row.MechanicID != filter.MechanicID {
+			continue
+		}
+		mechanic, ok := mechanics[row.MechanicID]
+		if !ok {
+			mechanic = &MechanicEfficiency{
+				MechanicID: row.MechanicID,
+				Name:       fmt.Sprintf("%s %s", row.FirstName, row.LastName),
+			}
+			mechanics[row.MechanicID] = mechanic
+			report.Mechanics = append(report.Mechanics, mechanic)
+		}
+		workOrder := workOrders[row.WorkOrderID]
+		mechanic.WorkOrders++
+		mechanic.ClockedHours += row.Hours
+		mechanic.LaborCost += row.Cost
+		if workOrder.ActualHours > 0 {
+			mechanic.EarnedHours += workOrder.EstimatedHours * row.Hours / workOrder.ActualHours
+		}
+	}
+
+	for _, workOrder := range report.WorkOrders {
+		workOrder.ActualHours = roundTo(workOrder.ActualHours, 2)
+		workOrder.LaborCost = roundTo(workOrder.LaborCost, 2)
+		workOrder.Variance = roundTo(workOrder.ActualHours-workOrder.EstimatedHours, 2)
+		workOrder.Efficiency = efficiency(workOrder.EstimatedHours, workOrder.ActualHours)
+		report.EstimatedHours += workOrder.EstimatedHours
+		report.ActualHours += workOrder.ActualHours
+	}
+	report.EstimatedHours = roundTo(report.EstimatedHours, 2)
+	report.ActualHours = roundTo(report.ActualHours, 2)
+	report.Efficiency = efficiency(report.EstimatedHours, report.ActualHours)
+
+	for _, mechanic := range report.Mechanics {
+		mechanic.ClockedHours = roundTo(mechanic.ClockedHours, 2)
+		mechanic.EarnedHours = roundTo(mechanic.EarnedHours, 2)
+		mechanic.LaborCost = roundTo(mechanic.LaborCost, 2)
+		mechanic.Efficiency = efficiency(mechanic.EarnedHours, mechanic.ClockedHours)
+	}
+	sort.SliceStable(report.Mechanics, func(i, j int) bool {
+		return report.Mechanics[i].ClockedHours > report.Mechanics[j].ClockedHours
+	})
+	return report, nil
+}
+
+// HandleWorkOrderStatus stops the running clocks of a work order that is no longer in progress
+func (s *LaborService) HandleWorkOrderStatus(workOrder *domain.WorkOrder, entry *domain.WorkOrderStatusHistory) {
+	if entry.OldStatus == nil || *entry.OldStatus != domain.StatusInProgress || workOrder.Status == domain.StatusInProgress {
+		return
+	}
+	active, err := s.laborRepo.ListActiveEntries(workOrder.ID)
+	if err != nil {
+		s.logger.WithError(err).WithField("work_order_id", workOrder.ID).Error("Failed to get running clocks")
+		return
+	}
+	for _, running := range active {
+		note := fmt.Sprintf("Stopped when the work order moved to %s", workOrder.Status)
+		if err := s.stop(running, domain.ClockEndStopped, note, false); err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"work_order_id": workOrder.ID,
+				"entry_id":      running.ID,
+			}).Error("Failed to stop clock")
+		}
+	}
+}
+
+// clockOut stops the current mechanic's running clock on a task
+func (s *LaborService) clockOut(viewer Viewer, workOrderID, taskID uint, reason, note string, completeTask bool) (*domain.LaborTimeEntry, error) {
+	if viewer.Role != domain.RoleMechanic {
+		return nil, ErrClockNotMechanic
+	}
+	if _, _, err := s.getTask(viewer, workOrderID, taskID); err != nil {
+		return nil, err
+	}
+	running, err := s.laborRepo.GetActiveEntry(viewer.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrNoActiveClock
+		}
+		return nil, fmt.Errorf("failed to get active clock: %w", err)
+	}
+	if running.WorkOrderID != workOrderID || running.TaskID != taskID {
+		return nil, ErrNoActiveClock
+	}
+
+	if err := s.stop(running, reason, note, completeTask); err != nil {
+		if isNotFound(err) {
+			return nil, ErrNoActiveClock
+		}
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"work_order_id": workOrderID,
+		"task_id":       taskID,
+		"mechanic_id":   viewer.UserID,
+		"reason":        reason,
+		"hours":         roundTo(running.Hours(*running.EndedAt), 2),
+	}).Info("Mechanic clocked out")
+	return running, nil
+}
+
+// stop ends a running entry at the mechanic's hourly rate
+func (s *LaborService) stop(running *domain.LaborTimeEntry, reason, note string, completeTask bool) error {
+	rate, err := s.hourlyRate(running.MechanicID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	running.EndedAt = &now
+	running.EndReason = reason
+	if note != "" {
+		if running.Note != "" {
+			running.Note += "\n"
+		}
+		running.Note += note
+	}
+	if err := s.laborRepo.StopEntry(running, rate, completeTask); err != nil {
+		if isNotFound(err) {
+			return err
+		}
+		return fmt.Errorf("failed to clock out: %w", err)
+	}
+	return nil
+}
+
+// getTask retrieves a task of a work order the viewer has access to
+func (s *LaborService) getTask(viewer Viewer, workOrderID, taskID uint) (*domain.WorkOrder, *domain.WorkOrderTask, error) {
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	task, err := s.laborRepo.GetTask(taskID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil, ErrWorkOrderTaskNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if task.WorkOrderID != workOrder.ID {
+		return nil, nil, ErrWorkOrderTaskNotFound
+	}
+	return workOrder, task, nil
+}
+
+// hourlyRate returns the labor rate of a mechanic, or the workshop default
+func (s *LaborService) hourlyRate(mechanicID uint) (float64, error) {
+	profile, err := s.mechanicRepo.GetProfile(mechanicID)
+	if err != nil {
+		if isNotFound(err) {
+			return s.defaultRate, nil
+		}
+		return 0, fmt.Errorf("failed to get mechanic profile: %w", err)
+	}
+	if profile.HourlyRate == nil {
+		return s.defaultRate, nil
+	}
+	return *profile.HourlyRate, nil
+}
+
+// efficiency divides estimated by actual hours, nil when either is missing
+func efficiency(estimated, actual float64) *float64 {
+	if estimated <= 0 || actual <= 0 {
+		return nil
+	}
+	value := roundTo(estimated/actual, 2)
+	return &value
+}
@@ -0,0 +1,850 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Preventive maintenance errors
+var (
+	ErrMaintenancePlanNotFound     = errors.New("maintenance plan not found")
+	ErrMaintenanceScheduleNotFound = errors.New("maintenance schedule not found")
+)
+
+const (
+	// maintenanceBatchSize bounds the schedules evaluated per query
+	maintenanceBatchSize = 200
+	// maintenanceReportPage bounds the overdue schedules loaded per query for the report
+	maintenanceReportPage = 500
+)
+
+// MaintenancePlanRequest represents a maintenance plan; updates replace the whole plan. At least
+// one interval is required and the plan is due at whichever comes first.
+type MaintenancePlanRequest struct {
+	Name                string   `json:"name" validate:"required,max=100"`
+	Description         string   `json:"description"`
+	VehicleType         *string  `json:"vehicle_type" validate:"omitempty,max=50"`
+	Make                *string  `json:"make" validate:"omitempty,max=50"`
+	Model               *string  `json:"model" validate:"omitempty,max=50"`
+	IntervalKm          *int     `json:"interval_km" validate:"omitempty,min=1"`
+	IntervalDays        *int     `json:"interval_days" validate:"omitempty,min=1,max=3650"`
+	IntervalEngineHours *float64 `json:"interval_engine_hours" validate:"omitempty,gt=0"`
+	LeadKm              int      `json:"lead_km" validate:"min=0"`
+	LeadDays            int      `json:"lead_days" validate:"min=0"`
+	LeadEngineHours     float64  `json:"lead_engine_hours" validate:"min=0"`
+	Action              string   `json:"action" validate:"omitempty,oneof=work_order reminder"` // defaults to work_order
+	OperationID         *uint    `json:"operation_id"`
+	Priority            string   `json:"priority" validate:"omitempty,oneof=low normal high critical emergency"` // defaults to normal
+	RecipientUserIDs    []uint   `json:"recipient_user_ids" validate:"max=50"`
+	IsActive            *bool    `json:"is_active"`
+}
+
+// MaintenanceDoneRequest records that the maintenance of a schedule was carried out outside a
+// work order. Readings default to the vehicle's current readings.
+type MaintenanceDoneRequest struct {
+	DoneAt      *time.Time `json:"done_at"` // defaults to now
+	Odometer    *int       `json:"odometer" validate:"omitempty,min=0"`
+	EngineHours *float64   `json:"engine_hours" validate:"omitempty,min=0"`
+}
+
+// MaintenanceScheduleList represents a page of vehicle maintenance schedules
+type MaintenanceScheduleList struct {
+	Schedules []*domain.VehicleMaintenanceSchedule `json:"schedules"`
+	Total     int64                                `json:"total"`
+	Page      int                                  `json:"page"`
+	Limit     int                                  `json:"limit"`
+}
+
+// MaintenanceRunResult summarizes a run of the preventive maintenance scheduler
+type MaintenanceRunResult struct {
+	SchedulesCreated int `json:"schedules_created"`
+	SchedulesRetired int `json:"schedules_retired"`
+	Evaluated        int `json:"evaluated"`
+	DueSoon          int `json:"due_soon"`
+	Overdue          int `json:"overdue"`
+	WorkOrdersOpened int `json:"work_orders_opened"`
+	Notified         int `json:"notified"`
+}
+
+// OverdueMaintenance is an overdue schedule with how far past due it is on each interval
+type OverdueMaintenance struct {
+	ScheduleID         uint       `json:"schedule_id"`
+	PlanID             uint       `json:"plan_id"`
+	PlanName           string     `json:"plan_name"`
+	VehicleID          uint       `json:"vehicle_id"`
+	PlateNumber        string     `json:"plate_number"`
+	AreaID             *uint      `json:"area_id"`
+	LastDoneAt         time.Time  `json:"last_done_at"`
+	NextDueAt          *time.Time `json:"next_due_at"`
+	NextDueOdometer    *int       `json:"next_due_odometer"`
+	NextDueEngineHours *float64   `json:"next_due_engine_hours"`
+	DaysOverdue        *int       `json:"days_overdue"`
+	KmOverdue          *int       `json:"km_overdue"`
+	EngineHoursOverdue *float64   `json:"engine_hours_overdue"`
+	WorkOrderID        *uint      `json:"work_order_id"`
+}
+
+// OverdueMaintenancePlan counts the overdue vehicles of a plan
+type OverdueMaintenancePlan struct {
+	PlanID   uint   `json:"plan_id"`
+	PlanName string `json:"plan_name"`
+	Vehicles int    `json:"vehicles"`
+}
+
+// OverdueMaintenanceReport lists the overdue maintenance as of the last scheduler run
+type OverdueMaintenanceReport struct {
+	GeneratedAt time.Time                 `json:"generated_at"`
+	Total       int                       `json:"total"`
+	Plans       []*OverdueMaintenancePlan `json:"plans"`
+	Schedules   []*OverdueMaintenance     `json:"schedules"`
+}
+
+// MaintenanceService manages preventive maintenance plans. A scheduler gives every vehicle that
+// matches an active plan a schedule, evaluates it against the vehicle's odometer, engine hours and
+// the calendar, and once it comes within the plan's lead opens a routine maintenance work order or
+// reminds the plan's recipients. Completing the work order restarts the schedule.
+type MaintenanceService struct {
+	maintenanceRepo     interfaces.MaintenanceRepository
+	vehicleRepo         interfaces.VehicleRepository
+	workOrderRepo       interfaces.WorkOrderRepository
+	laborRepo           interfaces.LaborRepository
+	userRepo            interfaces.UserRepository
+	roleRepo            interfaces.RoleRepository
+	operationService    *LaborOperationService
+	notificationService *NotificationService
+	interval            time.Duration
+	validator           *validator.Validate
+	logger              *logrus.Logger
+
+	running sync.Mutex // one scheduler run at a time
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(
+	maintenanceRepo interfaces.MaintenanceRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	workOrderRepo interfaces.WorkOrderRepository,
+	laborRepo interfaces.LaborRepository,
+	userRepo interfaces.UserRepository,
+	roleRepo interfaces.RoleRepository,
+	operationService *LaborOperationService,
+	notificationService *NotificationService,
+	interval time.Duration,
+	logger *logrus.Logger,
+) *MaintenanceService {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &MaintenanceService{
+		maintenanceRepo:     maintenanceRepo,
+		vehicleRepo:         vehicleRepo,
+		workOrderRepo:       workOrderRepo,
+		laborRepo:           laborRepo,
+		userRepo:            userRepo,
+		roleRepo:            roleRepo,
+		operationService:    operationService,
+		notificationService: notificationService,
+		interval:            interval,
+		validator:           validator.New(),
+		logger:              logger,
+		stop:                make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler right away and then periodically. Only one process needs to run it.
+func (s *MaintenanceService) Start() {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		s.runScheduler()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.runScheduler()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the scheduler
+func (s *MaintenanceService) Close() {
+	close(s.stop)
+	s.workers.Wait()
+}
+
+func (s *MaintenanceService) runScheduler() {
+	started := time.Now()
+	result, err := s.RunScheduler()
+	if err != nil {
+		s.logger.WithError(err).Error("Maintenance scheduler failed")
+		return
+	}
+	s.logger.WithFields(logrus.Fields{
+		"duration":           time.Since(started).String(),
+		"evaluated":          result.Evaluated,
+		"overdue":            result.Overdue,
+		"work_orders_opened": result.WorkOrdersOpened,
+	}).Debug("Maintenance scheduler completed")
+}
+
+// CreatePlan creates a maintenance plan; its vehicles are scheduled on the next scheduler run
+func (s *MaintenanceService) CreatePlan(req *MaintenancePlanRequest, createdBy uint) (*domain.MaintenancePlan, error) {
+	plan := &domain.MaintenancePlan{IsActive: true, CreatedBy: createdBy}
+	if err := s.applyPlanRequest(plan, req); err != nil {
+		return nil, err
+	}
+	if err := s.maintenanceRepo.CreatePlan(plan); err != nil {
+		return nil, fmt.Errorf("failed to create maintenance plan: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"plan_id":    plan.ID,
+		"name":       plan.Name,
+		"created_by": createdBy,
+	}).Info("Maintenance plan created")
+	return plan, nil
+}
+
+// GetPlan retrieves a maintenance plan
+func (s *MaintenanceService) GetPlan(id uint) (*domain.MaintenancePlan, error) {
+	plan, err := s.maintenanceRepo.GetPlan(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrMaintenancePlanNotFound
+		}
+		return nil, fmt.Errorf("failed to get maintenance plan: %w", err)
+	}
+	return plan, nil
+}
+
+// ListPlans retrieves the maintenance plans
+func (s *MaintenanceService) ListPlans(activeOnly bool) ([]*domain.MaintenancePlan, error) {
+	plans, err := s.maintenanceRepo.ListPlans(activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance plans: %w", err)
+	}
+	return plans, nil
+}
+
+// UpdatePlan replaces a maintenance plan. Schedules keep when the plan was last done; new
+// intervals and vehicle filters apply from the next scheduler run.
+func (s *MaintenanceService) UpdatePlan(id uint, req *MaintenancePlanRequest) (*domain.MaintenancePlan, error) {
+	plan, err := s.GetPlan(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyPlanRequest(plan, req); err != nil {
+		return nil, err
+	}
+	if err := s.maintenanceRepo.UpdatePlan(plan); err != nil {
+		if isNotFound(err) {
+			return nil, ErrMaintenancePlanNotFound
+		}
+		return nil, fmt.Errorf("failed to update maintenance plan: %w", err)
+	}
+	return plan, nil
+}
+
+// ListSchedules retrieves a page of active vehicle maintenance schedules, the most urgent first
+func (s *MaintenanceService) ListSchedules(filter interfaces.MaintenanceScheduleFilter, page, limit, offset int) (*MaintenanceScheduleList, error) {
+	if filter.VehicleID != 0 {
+		if _, err := s.vehicleRepo.GetByID(filter.VehicleID); err != nil {
+			if isNotFound(err) {
+				return nil, ErrVehicleNotFound
+			}
+			return nil, fmt.Errorf("failed to get vehicle: %w", err)
+		}
+	}
+	schedules, total, err := s.maintenanceRepo.ListSchedules(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance schedules: %w", err)
+	}
+	return &MaintenanceScheduleList{Schedules: schedules, Total: total, Page: page, Limit: limit}, nil
+}
+
+// RecordDone restarts a schedule from maintenance carried out outside a work order. A linked
+// work order is left as it is but no longer restarts the schedule.
+func (s *MaintenanceService) RecordDone(id uint, req *MaintenanceDoneRequest) (*domain.VehicleMaintenanceSchedule, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	schedule, err := s.maintenanceRepo.GetSchedule(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrMaintenanceScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to get maintenance schedule: %w", err)
+	}
+
+	now := time.Now().UTC()
+	doneAt := now
+	if req.DoneAt != nil {
+		doneAt = req.DoneAt.UTC()
+	}
+	if doneAt.After(now) {
+		return nil, fmt.Errorf("validation failed: done_at must not be in the future")
+	}
+	if doneAt.Before(schedule.LastDoneAt) {
+		return nil, fmt.Errorf("validation failed: done_at must not be before the last time the plan was done")
+	}
+
+	readings, err := s.maintenanceRepo.ReadMeters([]uint{schedule.VehicleID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vehicle meters: %w", err)
+	}
+	reading := meterReading(readings, schedule.VehicleID)
+	odometer, engineHours := reading.Odometer, reading.EngineHours
+	if req.Odometer != nil {
+		odometer = *req.Odometer
+	}
+	if req.EngineHours != nil {
+		engineHours = *req.EngineHours
+	}
+	if odometer > reading.Odometer || engineHours > reading.EngineHours {
+		return nil, fmt.Errorf("validation failed: readings must not be above the vehicle's current readings")
+	}
+
+	if err := s.restart(schedule, doneAt, odometer, engineHours, reading, now); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// RunScheduler schedules the vehicles of the active plans, evaluates every schedule and raises
+// the work orders and reminders that are due
+func (s *MaintenanceService) RunScheduler() (*MaintenanceRunResult, error) {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	now := time.Now().UTC()
+	result := &MaintenanceRunResult{}
+	if err := s.scheduleVehicles(now, result); err != nil {
+		return nil, err
+	}
+
+	var vehicleIDs []uint
+	err := s.maintenanceRepo.FindActiveSchedulesInBatches(maintenanceBatchSize, func(schedules []*domain.VehicleMaintenanceSchedule) error {
+		ids := make([]uint, 0, len(schedules))
+		for _, schedule := range schedules {
+			ids = append(ids, schedule.VehicleID)
+		}
+		readings, err := s.maintenanceRepo.ReadMeters(uniqueUints(ids))
+		if err != nil {
+			return fmt.Errorf("failed to read vehicle meters: %w", err)
+		}
+		for _, schedule := range schedules {
+			s.evaluate(schedule, meterReading(readings, schedule.VehicleID), now, result)
+		}
+		vehicleIDs = append(vehicleIDs, ids...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate maintenance schedules: %w", err)
+	}
+
+	if err := s.maintenanceRepo.SyncVehicleServiceDates(uniqueUints(vehicleIDs)); err != nil {
+		return nil, fmt.Errorf("failed to update vehicle service dates: %w", err)
+	}
+	return result, nil
+}
+
+// OverdueReport lists the overdue schedules as of the last scheduler run, the longest overdue first
+func (s *MaintenanceService) OverdueReport(filter interfaces.MaintenanceScheduleFilter) (*OverdueMaintenanceReport, error) {
+	filter.Status = domain.MaintenanceStatusOverdue
+	report := &OverdueMaintenanceReport{
+		GeneratedAt: time.Now().UTC(),
+		Plans:       []*OverdueMaintenancePlan{},
+		Schedules:   []*OverdueMaintenance{},
+	}
+
+	plans := make(map[uint]*OverdueMaintenancePlan)
+	for offset := 0; ; offset += maintenanceReportPage {
+		schedules, total, err := s.maintenanceRepo.ListSchedules(filter, offset, maintenanceReportPage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list overdue maintenance: %w", err)
+		}
+		for _, schedule := range schedules {
+			row := overdueMaintenance(schedule, report.GeneratedAt)
+			report.Schedules = append(report.Schedules, row)
+
+			plan, ok := plans[row.PlanID]
+			if !ok {
+				plan = &OverdueMaintenancePlan{PlanID: row.PlanID, PlanName: row.PlanName}
+				plans[row.PlanID] = plan
+				report.Plans = append(report.Plans, plan)
+			}
+			plan.Vehicles++
+		}
+		if len(schedules) == 0 || int64(offset+len(schedules)) >= total {
+			break
+		}
+	}
+	report.Total = len(report.Schedules)
+
+	sort.SliceStable(report.Schedules, func(i, j int) bool {
+		return overdueDays(report.Schedules[i]) > overdueDays(report.Schedules[j])
+	})
+	sort.SliceStable(report.Plans, func(i, j int) bool {
+		return report.Plans[i].Vehicles > report.Plans[j].Vehicles
+	})
+	return report, nil
+}
+
+// HandleWorkOrderStatus restarts the schedules of a completed maintenance work order and
+// unlinks a cancelled one, so the next scheduler run opens a new work order if still due
+func (s *MaintenanceService) HandleWorkOrderStatus(workOrder *domain.WorkOrder, entry *domain.WorkOrderStatusHistory) {
+	if !isWorkOrderClosed(entry.NewStatus) {
+		return
+	}
+	schedules, err := s.maintenanceRepo.ListSchedulesByWorkOrder(workOrder.ID)
+	if err != nil {
+		s.logger.WithError(err).WithField("work_order_id", workOrder.ID).Error("Failed to get maintenance schedules of work order")
+		return
+	}
+	for _, schedule := range schedules {
+		if err := s.closeWorkOrder(schedule, workOrder, entry.ChangedAt); err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"work_order_id": workOrder.ID,
+				"schedule_id":   schedule.ID,
+			}).Error("Failed to update maintenance schedule of work order")
+		}
+	}
+}
+
+// scheduleVehicles gives the vehicles matching each active plan a schedule for it
+func (s *MaintenanceService) scheduleVehicles(now time.Time, result *MaintenanceRunResult) error {
+	plans, err := s.maintenanceRepo.ListPlans(true)
+	if err != nil {
+		return fmt.Errorf("failed to list maintenance plans: %w", err)
+	}
+	for _, plan := range plans {
+		vehicles, err := s.maintenanceRepo.ListUnscheduledVehicles(plan)
+		if err != nil {
+			return fmt.Errorf("failed to list vehicles of maintenance plan %d: %w", plan.ID, err)
+		}
+		if len(vehicles) == 0 {
+			continue
+		}
+		ids := make([]uint, 0, len(vehicles))
+		for _, vehicle := range vehicles {
+			ids = append(ids, vehicle.ID)
+		}
+		readings, err := s.maintenanceRepo.ReadMeters(ids)
+		if err != nil {
+			return fmt.Errorf("failed to read vehicle meters: %w", err)
+		}
+
+		schedules := make([]*domain.VehicleMaintenanceSchedule, 0, len(vehicles))
+		for _, vehicle := range vehicles {
+			reading := meterReading(readings, vehicle.ID)
+			schedule := &domain.VehicleMaintenanceSchedule{
+				PlanID:    plan.ID,
+				VehicleID: vehicle.ID,
+				Status:    domain.MaintenanceStatusOK,
+				IsActive:  true,
+			}
+			schedule.Baseline(plan, vehicle.LastService, reading.Odometer, reading.EngineHours, now)
+			schedules = append(schedules, schedule)
+		}
+		if err := s.maintenanceRepo.CreateSchedules(schedules); err != nil {
+			return fmt.Errorf("failed to create maintenance schedules: %w", err)
+		}
+		result.SchedulesCreated += len(schedules)
+	}
+	return nil
+}
+
+// evaluate updates the due status of a schedule and raises its work order or reminder. Failures
+// are logged so one vehicle does not hold up the others.
+func (s *MaintenanceService) evaluate(schedule *domain.VehicleMaintenanceSchedule, reading *interfaces.MeterReading, now time.Time, result *MaintenanceRunResult) {
+	log := s.logger.WithFields(logrus.Fields{
+		"schedule_id": schedule.ID,
+		"plan_id":     schedule.PlanID,
+		"vehicle_id":  schedule.VehicleID,
+	})
+	if schedule.Plan == nil || schedule.Vehicle == nil {
+		return
+	}
+	if !schedule.Plan.AppliesTo(schedule.Vehicle) {
+		schedule.IsActive = false
+		if err := s.maintenanceRepo.UpdateSchedule(schedule); err != nil {
+			log.WithError(err).Error("Failed to retire maintenance schedule")
+			return
+		}
+		result.SchedulesRetired++
+		return
+	}
+
+	if schedule.WorkOrderID != nil {
+		workOrder, err := s.workOrderRepo.GetByID(*schedule.WorkOrderID)
+		switch {
+		case err != nil && !isNotFound(err):
+			log.WithError(err).Error("Failed to get maintenance work order")
+			return
+		case err != nil:
+			schedule.WorkOrderID = nil
+		case isWorkOrderClosed(workOrder.Status):
+			// The status change was missed, e.g. while the scheduler was not running
+			if err := s.closeWorkOrder(schedule, workOrder, workOrder.UpdatedAt); err != nil {
+				log.WithError(err).Error("Failed to update maintenance schedule of closed work order")
+			}
+			return
+		}
+	}
+
+	schedule.Evaluate(schedule.Plan, reading.Odometer, reading.EngineHours, now)
+	result.Evaluated++
+	switch schedule.Status {
+	case domain.MaintenanceStatusOverdue:
+		result.Overdue++
+	case domain.MaintenanceStatusDueSoon:
+		result.DueSoon++
+	}
+
+	if schedule.Status != domain.MaintenanceStatusOK {
+		var workOrder *domain.WorkOrder
+		if schedule.Plan.Action == domain.MaintenanceActionWorkOrder && schedule.WorkOrderID == nil {
+			opened, created, err := s.openWorkOrder(schedule)
+			if err != nil {
+				log.WithError(err).Error("Failed to open maintenance work order")
+			} else {
+				workOrder = opened
+				schedule.WorkOrderID = &opened.ID
+				if created {
+					result.WorkOrdersOpened++
+				}
+			}
+		}
+		if domain.MaintenanceStatusAbove(schedule.Status, schedule.NotifiedStatus) {
+			if s.notify(schedule, workOrder) {
+				schedule.NotifiedStatus = schedule.Status
+				result.Notified++
+			}
+		}
+	}
+
+	if err := s.maintenanceRepo.UpdateSchedule(schedule); err != nil {
+		log.WithError(err).Error("Failed to update maintenance schedule")
+	}
+}
+
+// openWorkOrder links a due schedule to the open maintenance work order of another plan on the
+// vehicle, opening a routine maintenance work order when there is none. The plan's labor
+// operation is added as a task. It reports whether the work order is new.
+func (s *MaintenanceService) openWorkOrder(schedule *domain.VehicleMaintenanceSchedule) (*domain.WorkOrder, bool, error) {
+	plan, vehicle := schedule.Plan, schedule.Vehicle
+	workOrder, err := s.openMaintenanceWorkOrder(vehicle.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	created := workOrder == nil
+	if created {
+		advisorID, err := workOrderAdvisor(s.roleRepo, s.userRepo)
+		if err != nil {
+			return nil, false, err
+		}
+		workOrder = &domain.WorkOrder{
+			CustomerName:     internalCustomerName(vehicle),
+			VehicleID:        vehicle.ID,
+			ServiceType:      domain.ServiceTypeRoutine,
+			Priority:         plan.Priority,
+			Status:           domain.StatusPending,
+			Description:      fmt.Sprintf("Preventive maintenance: %s", plan.Name),
+			ServiceAdvisorID: advisorID,
+			Notes:            fmt.Sprintf("Opened automatically, %s.", describeMaintenanceDue(schedule)),
+		}
+		if err := s.workOrderRepo.Create(workOrder); err != nil {
+			return nil, false, fmt.Errorf("failed to create work order: %w", err)
+		}
+		s.logger.WithFields(logrus.Fields{
+			"vehicle_id":    vehicle.ID,
+			"plate_number":  vehicle.PlateNumber,
+			"plan_id":       plan.ID,
+			"work_order_id": workOrder.ID,
+		}).Info("Work order opened for preventive maintenance")
+	}
+
+	if plan.OperationID != nil {
+		operation, err := s.operationService.Applicable(*plan.OperationID, vehicle)
+		if err != nil {
+			s.logger.WithError(err).WithField("plan_id", plan.ID).Warn("Maintenance plan operation not added to work order")
+		} else {
+			task := &domain.WorkOrderTask{
+				WorkOrderID:    workOrder.ID,
+				OperationID:    &operation.ID,
+				Description:    operation.Description,
+				EstimatedHours: operation.StandardHours,
+				StandardHours:  &operation.StandardHours,
+				Status:         domain.TaskStatusOpen,
+				CreatedBy:      workOrder.ServiceAdvisorID,
+			}
+			if err := s.laborRepo.CreateTask(task); err != nil {
+				return nil, false, fmt.Errorf("failed to create task: %w", err)
+			}
+		}
+	}
+	return workOrder, created, nil
+}
+
+// openMaintenanceWorkOrder returns an open work order another schedule of the vehicle is linked to, if any
+func (s *MaintenanceService) openMaintenanceWorkOrder(vehicleID uint) (*domain.WorkOrder, error) {
+	schedules, _, err := s.maintenanceRepo.ListSchedules(interfaces.MaintenanceScheduleFilter{VehicleID: vehicleID}, 0, 100)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance schedules: %w", err)
+	}
+	for _, other := range schedules {
+		if other.WorkOrderID == nil {
+			continue
+		}
+		workOrder, err := s.workOrderRepo.GetByID(*other.WorkOrderID)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get work order: %w", err)
+		}
+		if !isWorkOrderClosed(workOrder.Status) {
+			return workOrder, nil
+		}
+	}
+	return nil, nil
+}
+
+// notify tells the plan's recipients, and the advisor of the work order, that a schedule is due.
+// Reminders without recipients go to a service advisor. It reports whether anyone was notified.
+func (s *MaintenanceService) notify(schedule *domain.VehicleMaintenanceSchedule, workOrder *domain.WorkOrder) bool {
+	plan, vehicle := schedule.Plan, schedule.Vehicle
+	recipients := append([]uint{}, plan.RecipientUserIDs...)
+	workOrderNote := ""
+	if workOrder != nil {
+		recipients = append(recipients, workOrder.ServiceAdvisorID)
+		workOrderNote = fmt.Sprintf(" Work order %s is open for it.", workOrder.WONumber)
+	}
+	if len(recipients) == 0 {
+		advisorID, err := workOrderAdvisor(s.roleRepo, s.userRepo)
+		if err != nil {
+			s.logger.WithError(err).WithField("schedule_id", schedule.ID).Error("No recipient for maintenance reminder")
+			return false
+		}
+		recipients = append(recipients, advisorID)
+	}
+
+	status := strings.ReplaceAll(schedule.Status, "_", " ")
+	err := s.notificationService.Notify(uniqueUints(recipients), NotificationMessage{
+		Type:          "maintenance_" + schedule.Status,
+		Title:         fmt.Sprintf("%s %s on %s", plan.Name, status, vehicle.PlateNumber),
+		Message:       fmt.Sprintf("%s: %s is %s, %s.%s", vehicle.PlateNumber, plan.Name, status, describeMaintenanceDue(schedule), workOrderNote),
+		ReferenceType: "maintenance_schedule",
+		ReferenceID:   schedule.ID,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("schedule_id", schedule.ID).Error("Failed to send maintenance notification")
+		return false
+	}
+	return true
+}
+
+// closeWorkOrder restarts a schedule from its completed work order, or unlinks a cancelled one
+func (s *MaintenanceService) closeWorkOrder(schedule *domain.VehicleMaintenanceSchedule, workOrder *domain.WorkOrder, changedAt time.Time) error {
+	if workOrder.Status != domain.StatusCompleted {
+		schedule.WorkOrderID = nil
+		return s.maintenanceRepo.UpdateSchedule(schedule)
+	}
+
+	doneAt := changedAt.UTC()
+	if workOrder.CompletionDate != nil {
+		doneAt = workOrder.CompletionDate.UTC()
+	}
+	readings, err := s.maintenanceRepo.ReadMeters([]uint{schedule.VehicleID})
+	if err != nil {
+		return fmt.Errorf("failed to read vehicle meters: %w", err)
+	}
+	reading := meterReading(readings, schedule.VehicleID)
+	return s.restart(schedule, doneAt, reading.Odometer, reading.EngineHours, reading, time.Now().UTC())
+}
+
+// restart records that a schedule's maintenance was done and evaluates it from there
+func (s *MaintenanceService) restart(schedule *domain.VehicleMaintenanceSchedule, doneAt time.Time, odometer int, engineHours float64, reading *interfaces.MeterReading, now time.Time) error {
+	schedule.LastDoneAt = doneAt
+	schedule.LastDoneOdometer = odometer
+	schedule.LastDoneEngineHours = engineHours
+	schedule.WorkOrderID = nil
+	schedule.NotifiedStatus = ""
+	if schedule.Plan != nil {
+		schedule.Evaluate(schedule.Plan, reading.Odometer, reading.EngineHours, now)
+	}
+	if err := s.maintenanceRepo.UpdateSchedule(schedule); err != nil {
+		return fmt.Errorf("failed to update maintenance schedule: %w", err)
+	}
+	if err := s.maintenanceRepo.RecordVehicleService(schedule.VehicleID, doneAt); err != nil {
+		return fmt.Errorf("failed to update vehicle service dates: %w", err)
+	}
+	if err := s.maintenanceRepo.SyncVehicleServiceDates([]uint{schedule.VehicleID}); err != nil {
+		return fmt.Errorf("failed to update vehicle service dates: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"schedule_id": schedule.ID,
+		"vehicle_id":  schedule.VehicleID,
+		"done_at":     doneAt,
+	}).Info("Preventive maintenance recorded")
+	return nil
+}
+
+// applyPlanRequest validates a maintenance plan request and copies it onto the plan
+func (s *MaintenanceService) applyPlanRequest(plan *domain.MaintenancePlan, req *MaintenancePlanRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if req.IntervalKm == nil && req.IntervalDays == nil && req.IntervalEngineHours == nil {
+		return fmt.Errorf("validation failed: at least one of interval_km, interval_days and interval_engine_hours is required")
+	}
+	if req.IntervalKm != nil && req.LeadKm >= *req.IntervalKm {
+		return fmt.Errorf("validation failed: lead_km must be below interval_km")
+	}
+	if req.IntervalDays != nil && req.LeadDays >= *req.IntervalDays {
+		return fmt.Errorf("validation failed: lead_days must be below interval_days")
+	}
+	if req.IntervalEngineHours != nil && req.LeadEngineHours >= *req.IntervalEngineHours {
+		return fmt.Errorf("validation failed: lead_engine_hours must be below interval_engine_hours")
+	}
+	if req.OperationID != nil {
+		if _, err := s.operationService.Applicable(*req.OperationID, nil); err != nil {
+			return err
+		}
+	}
+
+	recipients := uniqueUints(req.RecipientUserIDs)
+	for _, userID := range recipients {
+		if _, err := s.userRepo.GetByID(userID); err != nil {
+			if isNotFound(err) {
+				return fmt.Errorf("validation failed: user %d not found", userID)
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+	}
+
+	plan.Name = req.Name
+	plan.Description = req.Description
+	plan.VehicleType = nil
+	if req.VehicleType != nil {
+		if vehicleType := strings.ToLower(strings.TrimSpace(*req.VehicleType)); vehicleType != "" {
+			plan.VehicleType = &vehicleType
+		}
+	}
+	plan.Make = optionalTrimmed(req.Make)
+	plan.Model = optionalTrimmed(req.Model)
+	plan.IntervalKm = req.IntervalKm
+	plan.IntervalDays = req.IntervalDays
+	plan.IntervalEngineHours = req.IntervalEngineHours
+	plan.LeadKm = req.LeadKm
+	plan.LeadDays = req.LeadDays
+	plan.LeadEngineHours = req.LeadEngineHours
+	plan.Action = req.Action
+	if plan.Action == "" {
+		plan.Action = domain.MaintenanceActionWorkOrder
+	}
+	plan.OperationID = req.OperationID
+	plan.Priority = req.Priority
+	if plan.Priority == "" {
+		plan.Priority = domain.PriorityNormal
+	}
+	plan.RecipientUserIDs = domain.UintList(recipients)
+	if req.IsActive != nil {
+		plan.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// meterReading returns the reading of a vehicle, or zero readings when there are none
+func meterReading(readings map[uint]*interfaces.MeterReading, vehicleID uint) *interfaces.MeterReading {
+	if reading, ok := readings[vehicleID]; ok {
+		return reading
+	}
+	return &interfaces.MeterReading{VehicleID: vehicleID}
+}
+
+// optionalTrimmed returns nil for an empty or blank value
+func optionalTrimmed(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// overdueMaintenance reports how far past due an overdue schedule is on each of its intervals
+func overdueMaintenance(schedule *domain.VehicleMaintenanceSchedule, now time.Time) *OverdueMaintenance {
+	row := &OverdueMaintenance{
+		ScheduleID:         schedule.ID,
+		PlanID:             schedule.PlanID,
+		VehicleID:          schedule.VehicleID,
+		LastDoneAt:         schedule.LastDoneAt,
+		NextDueAt:          schedule.NextDueAt,
+		NextDueOdometer:    schedule.NextDueOdometer,
+		NextDueEngineHours: schedule.NextDueEngineHours,
+		WorkOrderID:        schedule.WorkOrderID,
+	}
+	if schedule.Plan != nil {
+		row.PlanName = schedule.Plan.Name
+	}
+	if schedule.Vehicle != nil {
+		row.PlateNumber = schedule.Vehicle.PlateNumber
+		row.AreaID = schedule.Vehicle.AreaID
+	}
+	if schedule.NextDueAt != nil && !now.Before(*schedule.NextDueAt) {
+		days := int(now.Sub(*schedule.NextDueAt).Hours() / 24)
+		row.DaysOverdue = &days
+	}
+	if schedule.NextDueOdometer != nil && schedule.CurrentOdometer >= *schedule.NextDueOdometer {
+		km := schedule.CurrentOdometer - *schedule.NextDueOdometer
+		row.KmOverdue = &km
+	}
+	if schedule.NextDueEngineHours != nil && schedule.CurrentEngineHours >= *schedule.NextDueEngineHours {
+		hours := roundTo(schedule.CurrentEngineHours-*schedule.NextDueEngineHours, 1)
+		row.EngineHoursOverdue = &hours
+	}
+	return row
+}
+
+func overdueDays(row *OverdueMaintenance) int {
+	if row.DaysOverdue == nil {
+		return -1
+	}
+	return *row.DaysOverdue
+}
+
+// describeMaintenanceDue summarizes when a schedule is due, e.g. "due at 60000 km or on 2024-06-01"
+func describeMaintenanceDue(schedule *domain.VehicleMaintenanceSchedule) string {
+	var parts []string
+	if schedule.NextDueOdometer != nil {
+		parts = append(parts, fmt.Sprintf("at %d km (now %d km)", *schedule.NextDueOdometer, schedule.CurrentOdometer))
+	}
+	if schedule.NextDueEngineHours != nil {
+		parts = append(parts, fmt.Sprintf("at %.1f engine hours (now %.1f)", *schedule.NextDueEngineHours, schedule.CurrentEngineHours))
+	}
+	if schedule.NextDueAt != nil {
+		parts = append(parts, "on "+schedule.NextDueAt.Format("2006-01-02"))
+	}
+	return "due " + strings.Join(parts, " or ")
+}
@@ -0,0 +1,591 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Mechanic service errors
+var (
+	ErrMechanicNotFound      = errors.New("mechanic not found")
+	ErrMechanicShiftNotFound = errors.New("mechanic shift not found")
+	ErrMechanicShiftOverlap  = errors.New("shift overlaps another shift of the mechanic")
+	ErrMechanicAccessDenied  = errors.New("mechanics can only access their own profile and shifts")
+)
+
+const (
+	defaultDailyCapacityHours = 8.0
+	// defaultJobHours is counted for open work orders without an estimate
+	defaultJobHours  = 2.0
+	maxShiftDuration = 16 * time.Hour
+	maxShiftRange    = 92 * 24 * time.Hour
+	// defaultWorkloadDays is how far ahead shifts count towards a mechanic's capacity
+	defaultWorkloadDays = 7
+	maxWorkloadDays     = 31
+)
+
+// Weights of the assignment score components; they add up to 1
+const (
+	skillWeight        = 0.5
+	workloadWeight     = 0.3
+	availabilityWeight = 0.2
+)
+
+// MechanicSkillRequest represents a skill or certification of a mechanic
+type MechanicSkillRequest struct {
+	ServiceType    string     `json:"service_type" validate:"omitempty,oneof=routine_maintenance repair inspection emergency customization"` // empty covers every service type
+	VehicleType    string     `json:"vehicle_type" validate:"max=50"`                                                                        // empty covers every vehicle type
+	Level          int        `json:"level" validate:"omitempty,min=1,max=3"`                                                                // defaults to basic
+	Certification  string     `json:"certification" validate:"max=100"`
+	CertifiedUntil *time.Time `json:"certified_until"`
+}
+
+// MechanicProfileRequest represents a mechanic profile update. The skills replace the current ones.
+type MechanicProfileRequest struct {
+	BranchID           *uint                  `json:"branch_id"`
+	WarehouseID        *uint                  `json:"warehouse_id"` // small warehouse the mechanic draws parts from
+	DailyCapacityHours float64                `json:"daily_capacity_hours" validate:"omitempty,gt=0,max=24"` // defaults to 8
+	HourlyRate         *float64               `json:"hourly_rate" validate:"omitempty,min=0"`                // defaults to the workshop labor rate
+	IsAvailable        *bool                  `json:"is_available"`                                          // defaults to true
+	Notes              string                 `json:"notes" validate:"max=1000"`
+	Skills             []MechanicSkillRequest `json:"skills" validate:"max=50,dive"`
+}
+
+// MechanicShiftRequest represents a scheduled working period
+type MechanicShiftRequest struct {
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+	Note     string    `json:"note" validate:"max=500"`
+}
+
+// MechanicWorkload summarises the open work and schedule of a mechanic
+type MechanicWorkload struct {
+	MechanicID     uint       `json:"mechanic_id"`
+	Name           string     `json:"name"`
+	BranchID       *uint      `json:"branch_id"`
+	IsAvailable    bool       `json:"is_available"`
+	OpenWorkOrders int        `json:"open_work_orders"`
+	InProgress     int        `json:"in_progress"`
+	OpenHours      float64    `json:"open_hours"`     // remaining estimated hours of the open work orders
+	CapacityHours  float64    `json:"capacity_hours"` // scheduled hours ahead, each shift capped at the daily capacity
+	Utilisation    float64    `json:"utilisation"`    // open hours per capacity hour; above 1 is overbooked
+	OnShift        bool       `json:"on_shift"`
+	NextShiftAt    *time.Time `json:"next_shift_at"`
+}
+
+// AssignmentCandidate is a mechanic ranked for a work order. Scores range from 0 to 1.
+type AssignmentCandidate struct {
+	MechanicWorkload
+	Qualified         bool                  `json:"qualified"` // has a current skill covering the service and vehicle type
+	MatchedSkill      *domain.MechanicSkill `json:"matched_skill,omitempty"`
+	SkillScore        float64               `json:"skill_score"`
+	WorkloadScore     float64               `json:"workload_score"`
+	AvailabilityScore float64               `json:"availability_score"`
+	Score             float64               `json:"score"`
+	Recommended       bool                  `json:"recommended"`
+}
+
+// eligible reports whether the candidate can be recommended: qualified, available and scheduled
+func (c *AssignmentCandidate) eligible() bool {
+	return c.Qualified && c.IsAvailable && c.CapacityHours > 0
+}
+
+// MechanicService manages mechanic profiles and shifts, and ranks mechanics for work orders
+type MechanicService struct {
+	mechanicRepo       interfaces.MechanicRepository
+	workOrderRepo      interfaces.WorkOrderRepository
+	userRepo           interfaces.UserRepository
+	roleRepo           interfaces.RoleRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	validator          *validator.Validate
+	logger             *logrus.Logger
+}
+
+// NewMechanicService creates a new mechanic service
+func NewMechanicService(
+	mechanicRepo interfaces.MechanicRepository,
+	workOrderRepo interfaces.WorkOrderRepository,
+	userRepo interfaces.UserRepository,
+	roleRepo interfaces.RoleRepository,
+	serviceRequestRepo interfaces.ServiceRequestRepository,
+	logger *logrus.Logger,
+) *MechanicService {
+	return &MechanicService{
+		mechanicRepo:       mechanicRepo,
+		workOrderRepo:      workOrderRepo,
+		userRepo:           userRepo,
+		roleRepo:           roleRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		validator:          validator.New(),
+		logger:             logger,
+	}
+}
+
+// List retrieves the profiles of the active mechanics, optionally of one branch. Mechanics
+// without a stored profile are listed with the defaults.
+func (s *MechanicService) List(branchID uint) ([]*domain.MechanicProfile, error) {
+	profiles, err := s.profiles()
+	if err != nil {
+		return nil, err
+	}
+	if branchID == 0 {
+		return profiles, nil
+	}
+	inBranch := []*domain.MechanicProfile{}
+	for _, profile := range profiles {
+		if profile.BranchID != nil && *profile.BranchID == branchID {
+			inBranch = append(inBranch, profile)
+		}
+	}
+	return inBranch, nil
+}
+
+// Get retrieves the profile of a mechanic. Mechanics can only view their own profile.
+func (s *MechanicService) Get(viewer Viewer, userID uint) (*domain.MechanicProfile, error) {
+	if err := checkMechanicAccess(viewer, userID); err != nil {
+		return nil, err
+	}
+	mechanic, err := s.getMechanic(userID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.mechanicRepo.GetProfile(userID)
+	if err != nil {
+		if isNotFound(err) {
+			return defaultMechanicProfile(mechanic), nil
+		}
+		return nil, fmt.Errorf("failed to get mechanic profile: %w", err)
+	}
+	return profile, nil
+}
+
+// SaveProfile creates or replaces the profile of a mechanic
+func (s *MechanicService) SaveProfile(userID uint, req *MechanicProfileRequest) (*domain.MechanicProfile, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := s.getMechanic(userID); err != nil {
+		return nil, err
+	}
+	if req.BranchID != nil {
+		if _, err := workshopBranch(s.serviceRequestRepo, *req.BranchID); err != nil {
+			return nil, err
+		}
+	}
+	if req.WarehouseID != nil {
+		warehouse, err := s.serviceRequestRepo.GetBranch(*req.WarehouseID)
+		if err != nil {
+			if isNotFound(err) {
+				return nil, fmt.Errorf("validation failed: warehouse %d does not exist", *req.WarehouseID)
+			}
+			return nil, fmt.Errorf("failed to get warehouse: %w", err)
+		}
+		if !warehouse.IsActive || (warehouse.Type != domain.WarehouseTypeSmall && warehouse.Type != domain.WarehouseTypeMechanic) {
+			return nil, fmt.Errorf("validation failed: %s is not an active small or mechanic warehouse", warehouse.Name)
+		}
+	}
+
+	profile := &domain.MechanicProfile{
+		UserID:             userID,
+		BranchID:           req.BranchID,
+		WarehouseID:        req.WarehouseID,
+		DailyCapacityHours: req.DailyCapacityHours,
+		HourlyRate:         req.HourlyRate,
+		IsAvailable:        req.IsAvailable == nil || *req.IsAvailable,
+		Notes:              req.Notes,
+		Skills:             make([]domain.MechanicSkill, 0, len(req.Skills)),
+	}
+	if profile.DailyCapacityHours == 0 {
+		profile.DailyCapacityHours = defaultDailyCapacityHours
+	}
+	for _, skill := range req.Skills {
+		level := skill.Level
+		if level == 0 {
+			level = domain.SkillLevelBasic
+		}
+		profile.Skills = append(profile.Skills, domain.MechanicSkill{
+			ServiceType:    skill.ServiceType,
+			VehicleType:    skill.VehicleType,
+			Level:          level,
+			Certification:  skill.Certification,
+			CertifiedUntil: skill.CertifiedUntil,
+		})
+	}
+
+	if err := s.mechanicRepo.SaveProfile(profile); err != nil {
+		s.logger.WithError(err).Error("Mechanic profile update failed")
+		return nil, fmt.Errorf("failed to save mechanic profile: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"mechanic_id": userID,
+		"skills":      len(profile.Skills),
+	}).Info("Mechanic profile saved")
+
+	return s.mechanicRepo.GetProfile(userID)
+}
+
+// ListShifts retrieves the shifts of a mechanic overlapping a time range
+func (s *MechanicService) ListShifts(viewer Viewer, userID uint, from, to time.Time) ([]*domain.MechanicShift, error) {
+	if err := checkMechanicAccess(viewer, userID); err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, ErrInvalidTimeRange
+	}
+	if to.Sub(from) > maxShiftRange {
+		return nil, fmt.Errorf("validation failed: time range cannot exceed %d days", int(maxShiftRange.Hours()/24))
+	}
+	if _, err := s.getMechanic(userID); err != nil {
+		return nil, err
+	}
+	return s.mechanicRepo.ListShifts([]uint{userID}, from, to)
+}
+
+// CreateShift schedules a working period for a mechanic. Shifts of a mechanic cannot overlap.
+func (s *MechanicService) CreateShift(userID uint, req *MechanicShiftRequest, createdBy uint) (*domain.MechanicShift, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if req.EndsAt.Sub(req.StartsAt) > maxShiftDuration {
+		return nil, fmt.Errorf("validation failed: a shift cannot be longer than %d hours", int(maxShiftDuration.Hours()))
+	}
+	if _, err := s.getMechanic(userID); err != nil {
+		return nil, err
+	}
+
+	overlapping, err := s.mechanicRepo.ListShifts([]uint{userID}, req.StartsAt, req.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check shifts: %w", err)
+	}
+	if len(overlapping) > 0 {
+		return nil, ErrMechanicShiftOverlap
+	}
+
+	shift := &domain.MechanicShift{
+		UserID:    userID,
+		StartsAt:  req.StartsAt.UTC(),
+		EndsAt:    req.EndsAt.UTC(),
+		Note:      req.Note,
+		CreatedBy: createdBy,
+	}
+	if err := s.mechanicRepo.CreateShift(shift); err != nil {
+		s.logger.WithError(err).Error("Mechanic shift creation failed")
+		return nil, fmt.Errorf("failed to create shift: %w", err)
+	}
+	return shift, nil
+}
+
+// DeleteShift removes a shift of a mechanic
+func (s *MechanicService) DeleteShift(userID, shiftID uint) error {
+	shift, err := s.mechanicRepo.GetShift(shiftID)
+	if err != nil {
+		if isNotFound(err) {
+			return ErrMechanicShiftNotFound
+		}
+		return fmt.Errorf("failed to get shift: %w", err)
+	}
+	if shift.UserID != userID {
+		return ErrMechanicShiftNotFound
+	}
+	if err := s.mechanicRepo.DeleteShift(shiftID); err != nil {
+		if isNotFound(err) {
+			return ErrMechanicShiftNotFound
+		}
+		return fmt.Errorf("failed to delete shift: %w", err)
+	}
+	return nil
+}
+
+// Workload summarises the open work and the schedule of the next days for the active
+// mechanics, optionally of one branch, most utilised first
+func (s *MechanicService) Workload(branchID uint, days int) ([]*MechanicWorkload, error) {
+	if days == 0 {
+		days = defaultWorkloadDays
+	}
+	if days < 1 || days > maxWorkloadDays {
+		return nil, fmt.Errorf("validation failed: days must be between 1 and %d", maxWorkloadDays)
+	}
+	profiles, err := s.List(branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	loads, err := s.workloads(profiles, now, now.AddDate(0, 0, days), 0)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(loads, func(i, j int) bool {
+		if loads[i].Utilisation != loads[j].Utilisation {
+			return loads[i].Utilisation > loads[j].Utilisation
+		}
+		return loads[i].OpenHours > loads[j].OpenHours
+	})
+	return loads, nil
+}
+
+// RankCandidates ranks the active mechanics of the work order's branch, and those without a
+// branch, for a work order. The score weighs the best matching skill, the free capacity over
+// the coming week and how soon the mechanic is on shift. The first candidate is recommended
+// when it is qualified, available and scheduled. The mechanic currently assigned is left out.
+func (s *MechanicService) RankCandidates(workOrder *domain.WorkOrder) ([]*AssignmentCandidate, error) {
+	all, err := s.profiles()
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]*domain.MechanicProfile, 0, len(all))
+	for _, profile := range all {
+		if workOrder.AssignedMechanicID != nil && *workOrder.AssignedMechanicID == profile.UserID {
+			continue
+		}
+		if workOrder.BranchID != nil && profile.BranchID != nil && *profile.BranchID != *workOrder.BranchID {
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+
+	now := time.Now().UTC()
+	loads, err := s.workloads(profiles, now, now.AddDate(0, 0, defaultWorkloadDays), workOrder.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*AssignmentCandidate, len(profiles))
+	for i, profile := range profiles {
+		candidate := &AssignmentCandidate{MechanicWorkload: *loads[i]}
+		candidate.MatchedSkill, candidate.SkillScore = matchSkill(profile.Skills, workOrder.ServiceType, workOrder.Vehicle.Type, now)
+		candidate.Qualified = candidate.MatchedSkill != nil
+		if candidate.CapacityHours > 0 {
+			candidate.WorkloadScore = roundTo(1-math.Min(candidate.OpenHours/candidate.CapacityHours, 1), 3)
+		}
+		candidate.AvailabilityScore = availabilityScore(&candidate.MechanicWorkload, now)
+		candidate.Score = roundTo(skillWeight*candidate.SkillScore+
+			workloadWeight*candidate.WorkloadScore+
+			availabilityWeight*candidate.AvailabilityScore, 3)
+		candidates[i] = candidate
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.eligible() != b.eligible() {
+			return a.eligible()
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.OpenHours != b.OpenHours {
+			return a.OpenHours < b.OpenHours
+		}
+		return a.MechanicID < b.MechanicID
+	})
+	if len(candidates) > 0 && candidates[0].eligible() {
+		candidates[0].Recommended = true
+	}
+	return candidates, nil
+}
+
+// workloads summarises the open work orders and the shifts within [from, to) of each profile,
+// in profile order. excludeWorkOrderID leaves one work order out of the open work.
+func (s *MechanicService) workloads(profiles []*domain.MechanicProfile, from, to time.Time, excludeWorkOrderID uint) ([]*MechanicWorkload, error) {
+	loads := make([]*MechanicWorkload, len(profiles))
+	byMechanic := make(map[uint]*MechanicWorkload, len(profiles))
+	capacity := make(map[uint]float64, len(profiles))
+	ids := make([]uint, len(profiles))
+	for i, profile := range profiles {
+		loads[i] = &MechanicWorkload{
+			MechanicID:  profile.UserID,
+			BranchID:    profile.BranchID,
+			IsAvailable: profile.IsAvailable,
+		}
+		if profile.User != nil {
+			loads[i].Name = fmt.Sprintf("%s %s", profile.User.FirstName, profile.User.LastName)
+		}
+		byMechanic[profile.UserID] = loads[i]
+		capacity[profile.UserID] = profile.DailyCapacityHours
+		ids[i] = profile.UserID
+	}
+
+	workOrders, err := s.workOrderRepo.ListOpenByMechanics(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open work orders: %w", err)
+	}
+	for _, workOrder := range workOrders {
+		load := byMechanic[*workOrder.AssignedMechanicID]
+		if load == nil || workOrder.ID == excludeWorkOrderID {
+			continue
+		}
+		load.OpenWorkOrders++
+		if workOrder.Status == domain.StatusInProgress {
+			load.InProgress++
+		}
+		load.OpenHours += remainingHours(workOrder)
+	}
+
+	shifts, err := s.mechanicRepo.ListShifts(ids, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shifts: %w", err)
+	}
+	for _, shift := range shifts {
+		load := byMechanic[shift.UserID]
+		if load == nil {
+			continue
+		}
+		load.CapacityHours += math.Min(shift.Hours(from, to), capacity[shift.UserID])
+		switch {
+		case !shift.StartsAt.After(from):
+			load.OnShift = true
+		case load.NextShiftAt == nil:
+			startsAt := shift.StartsAt
+			load.NextShiftAt = &startsAt
+		}
+	}
+
+	for _, load := range loads {
+		load.OpenHours = roundTo(load.OpenHours, 2)
+		load.CapacityHours = roundTo(load.CapacityHours, 2)
+		if load.CapacityHours > 0 {
+			load.Utilisation = roundTo(load.OpenHours/load.CapacityHours, 2)
+		}
+	}
+	return loads, nil
+}
+
+// profiles retrieves the profiles of the active mechanics, filling in defaults for
+// mechanics without a stored profile
+func (s *MechanicService) profiles() ([]*domain.MechanicProfile, error) {
+	role, err := s.roleRepo.GetByName(domain.RoleMechanic)
+	if err != nil {
+		if isNotFound(err) {
+			return []*domain.MechanicProfile{}, nil
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	users, err := s.userRepo.GetByRoleID(role.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mechanics: %w", err)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	ids := make([]uint, 0, len(users))
+	for _, user := range users {
+		if user.IsActive {
+			ids = append(ids, user.ID)
+		}
+	}
+	stored, err := s.mechanicRepo.ListProfiles(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mechanic profiles: %w", err)
+	}
+	byUser := make(map[uint]*domain.MechanicProfile, len(stored))
+	for _, profile := range stored {
+		byUser[profile.UserID] = profile
+	}
+
+	profiles := make([]*domain.MechanicProfile, 0, len(ids))
+	for _, user := range users {
+		if !user.IsActive {
+			continue
+		}
+		profile, ok := byUser[user.ID]
+		if !ok {
+			profile = defaultMechanicProfile(user)
+		}
+		profile.User = user
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// getMechanic retrieves an active user with the Mechanic role
+func (s *MechanicService) getMechanic(userID uint) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrMechanicNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsActive || user.Role.Name != domain.RoleMechanic {
+		return nil, ErrMechanicNotFound
+	}
+	return user, nil
+}
+
+// checkMechanicAccess limits mechanics to their own profile and shifts
+func checkMechanicAccess(viewer Viewer, userID uint) error {
+	if viewer.Role == domain.RoleMechanic && viewer.UserID != userID {
+		return ErrMechanicAccessDenied
+	}
+	return nil
+}
+
+// defaultMechanicProfile is the profile of a mechanic that has not been set up yet
+func defaultMechanicProfile(user *domain.User) *domain.MechanicProfile {
+	return &domain.MechanicProfile{
+		UserID:             user.ID,
+		User:               user,
+		DailyCapacityHours: defaultDailyCapacityHours,
+		IsAvailable:        true,
+		Skills:             []domain.MechanicSkill{},
+	}
+}
+
+// matchSkill returns the best current skill covering a service type on a vehicle type and its
+// score. Specific skills score higher than generic ones and experts higher than beginners.
+func matchSkill(skills []domain.MechanicSkill, serviceType, vehicleType string, at time.Time) (*domain.MechanicSkill, float64) {
+	var best *domain.MechanicSkill
+	bestScore := 0.0
+	for i := range skills {
+		skill := &skills[i]
+		if !skill.Covers(serviceType, vehicleType, at) {
+			continue
+		}
+		specificity := 0.6
+		if skill.ServiceType != "" {
+			specificity += 0.2
+		}
+		if skill.VehicleType != "" {
+			specificity += 0.2
+		}
+		score := specificity * float64(skill.Level) / domain.SkillLevelExpert
+		if score > bestScore {
+			best, bestScore = skill, score
+		}
+	}
+	return best, roundTo(bestScore, 3)
+}
+
+// availabilityScore rates how soon a mechanic can pick up work: on shift now scores 1,
+// a shift within a day 0.5 and a later shift 0.25
+func availabilityScore(load *MechanicWorkload, now time.Time) float64 {
+	switch {
+	case !load.IsAvailable:
+		return 0
+	case load.OnShift:
+		return 1
+	case load.NextShiftAt != nil && load.NextShiftAt.Sub(now) <= 24*time.Hour:
+		return 0.5
+	case load.NextShiftAt != nil:
+		return 0.25
+	default:
+		return 0
+	}
+}
+
+// remainingHours estimates the hours left on an open work order
+func remainingHours(workOrder *domain.WorkOrder) float64 {
+	if workOrder.EstimatedHours <= 0 {
+		return defaultJobHours
+	}
+	return math.Max(workOrder.EstimatedHours-workOrder.ActualHours, 0)
+}
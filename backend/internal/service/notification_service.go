@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Notification errors
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationMessage is a notification to deliver to one or more users
+type NotificationMessage struct {
+	Type          string
+	Title         string
+	Message       string
+	ReferenceType string
+	ReferenceID   uint
+}
+
+// NotificationList represents a page of notifications
+type NotificationList struct {
+	Notifications []*domain.Notification `json:"notifications"`
+	Total         int64                  `json:"total"`
+	Unread        int64                  `json:"unread"`
+	Page          int                    `json:"page"`
+	Limit         int                    `json:"limit"`
+}
+
+// NotificationService delivers in-app notifications to users
+type NotificationService struct {
+	notificationRepo interfaces.NotificationRepository
+	logger           *logrus.Logger
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo interfaces.NotificationRepository, logger *logrus.Logger) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		logger:           logger,
+	}
+}
+
+// Notify stores a notification for each user; duplicate user IDs are notified once
+func (s *NotificationService) Notify(userIDs []uint, msg NotificationMessage) error {
+	seen := make(map[uint]bool, len(userIDs))
+	notifications := make([]*domain.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == 0 || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		notification := &domain.Notification{
+			UserID:        userID,
+			Type:          msg.Type,
+			Title:         msg.Title,
+			Message:       msg.Message,
+			ReferenceType: msg.ReferenceType,
+		}
+		if msg.ReferenceID != 0 {
+			referenceID := msg.ReferenceID
+			notification.ReferenceID = &referenceID
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := s.notificationRepo.CreateBatch(notifications); err != nil {
+		return fmt.Errorf("failed to store notifications: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"type":       msg.Type,
+		"recipients": len(notifications),
+	}).Debug("Notifications delivered")
+	return nil
+}
+
+// List retrieves a page of a user's notifications
+func (s *NotificationService) List(userID uint, unreadOnly bool, page, limit, offset int) (*NotificationList, error) {
+	notifications, total, err := s.notificationRepo.ListByUser(userID, unreadOnly, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return &NotificationList{Notifications: notifications, Total: total, Unread: unread, Page: page, Limit: limit}, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationService) MarkRead(userID, id uint) error {
+	if err := s.notificationRepo.MarkRead(userID, id); err != nil {
+		if isNotFound(err) {
+			return ErrNotificationNotFound
+		}
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks all of the user's notifications as read and returns how many changed
+func (s *NotificationService) MarkAllRead(userID uint) (int64, error) {
+	count, err := s.notificationRepo.MarkAllRead(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return count, nil
+}
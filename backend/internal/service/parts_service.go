@@ -0,0 +1,467 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Parts service errors
+var (
+	ErrInventoryItemNotFound = errors.New("inventory item not found")
+	ErrWorkOrderPartNotFound = errors.New("work order part not found")
+	ErrStockTransferNotFound = errors.New("stock transfer request not found")
+	ErrPartShort             = errors.New("work order part is waiting for a stock transfer")
+	ErrPartIssued            = errors.New("work order part is already issued")
+	ErrPartRemoved           = errors.New("work order part was removed from the work order")
+	ErrStockTransferClosed   = errors.New("stock transfer request is already fulfilled or cancelled")
+	ErrInsufficientStock     = errors.New("source warehouse does not have enough available stock")
+)
+
+// WorkOrderPartRequest represents a part added to a work order
+type WorkOrderPartRequest struct {
+	InventoryItemID uint     `json:"inventory_item_id" validate:"required"`
+	Quantity        int      `json:"quantity" validate:"required,min=1,max=10000"`
+	WarehouseID     *uint    `json:"warehouse_id"`                          // defaults to the assigned mechanic's warehouse
+	UnitPrice       *float64 `json:"unit_price" validate:"omitempty,min=0"` // defaults to the item's unit price
+	Notes           string   `json:"notes" validate:"max=1000"`
+}
+
+// PartMovementRequest carries the note recorded when a part is issued or removed
+type PartMovementRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// FulfilTransferRequest represents shipping a stock transfer request
+type FulfilTransferRequest struct {
+	FromWarehouseID *uint  `json:"from_warehouse_id"` // defaults to the suggested source
+	Note            string `json:"note" validate:"max=1000"`
+}
+
+// CancelTransferRequest represents cancelling a stock transfer request
+type CancelTransferRequest struct {
+	Note string `json:"note" validate:"required,max=1000"`
+}
+
+// AddPartResult is a part added to a work order with the transfer request raised for a shortage
+type AddPartResult struct {
+	Part      *domain.WorkOrderPart        `json:"part"`
+	Transfer  *domain.StockTransferRequest `json:"transfer,omitempty"`
+	WorkOrder *domain.WorkOrder            `json:"work_order"`
+}
+
+// WorkOrderParts is the parts of a work order with their transfer requests
+type WorkOrderParts struct {
+	Parts     []*domain.WorkOrderPart        `json:"parts"`
+	Transfers []*domain.StockTransferRequest `json:"transfers"`
+	PartsCost float64                        `json:"parts_cost"` // issued parts
+	Short     int                            `json:"short"`      // parts waiting for a transfer
+}
+
+// StockTransferList represents a paginated list of stock transfer requests
+type StockTransferList struct {
+	Transfers []*domain.StockTransferRequest `json:"transfers"`
+	Total     int64                          `json:"total"`
+	Page      int                            `json:"page"`
+	Limit     int                            `json:"limit"`
+}
+
+// PartsService moves stock for the parts of work orders. Parts are reserved at the mechanic's
+// small warehouse when added, taken out of stock when issued and put back when removed. A
+// shortage raises a stock transfer request and puts a work order in progress to waiting for parts.
+type PartsService struct {
+	inventoryRepo    interfaces.InventoryRepository
+	mechanicRepo     interfaces.MechanicRepository
+	workOrderService *WorkOrderService
+	estimateService  *EstimateService
+	validator        *validator.Validate
+	logger           *logrus.Logger
+}
+
+// NewPartsService creates a new parts service
+func NewPartsService(
+	inventoryRepo interfaces.InventoryRepository,
+	mechanicRepo interfaces.MechanicRepository,
+	workOrderService *WorkOrderService,
+	estimateService *EstimateService,
+	logger *logrus.Logger,
+) *PartsService {
+	return &PartsService{
+		inventoryRepo:    inventoryRepo,
+		mechanicRepo:     mechanicRepo,
+		workOrderService: workOrderService,
+		estimateService:  estimateService,
+		validator:        validator.New(),
+		logger:           logger,
+	}
+}
+
+// List returns the parts of a work order with their transfer requests
+func (s *PartsService) List(viewer Viewer, workOrderID uint) (*WorkOrderParts, error) {
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := s.inventoryRepo.ListParts(workOrder.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work order parts: %w", err)
+	}
+	transfers, _, err := s.inventoryRepo.ListTransfers(interfaces.StockTransferFilter{WorkOrderID: workOrder.ID}, 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock transfer requests: %w", err)
+	}
+
+	result := &WorkOrderParts{Parts: parts, Transfers: transfers}
+	for _, part := range parts {
+		switch part.Status {
+		case domain.PartStatusIssued:
+			result.PartsCost += part.TotalPrice
+		case domain.PartStatusShort:
+			result.Short++
+		}
+	}
+	result.PartsCost = roundTo(result.PartsCost, 2)
+	return result, nil
+}
+
+// AddPart reserves a part for a work order. What the warehouse cannot cover is requested by
+// transfer, and a work order in progress then waits for parts.
+func (s *PartsService) AddPart(viewer Viewer, workOrderID uint, req *WorkOrderPartRequest) (*AddPartResult, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+
+	item, err := s.inventoryRepo.GetItem(req.InventoryItemID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrInventoryItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get inventory item: %w", err)
+	}
+	if !item.IsActive {
+		return nil, fmt.Errorf("validation failed: %s is no longer stocked", item.Name)
+	}
+	warehouse, err := s.partsWarehouse(workOrder, req.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	part := &domain.WorkOrderPart{
+		WorkOrderID:     workOrder.ID,
+		InventoryItemID: item.ID,
+		WarehouseID:     warehouse.ID,
+		Quantity:        req.Quantity,
+		UnitPrice:       item.UnitPrice,
+		UsedBy:          viewer.UserID,
+		Notes:           req.Notes,
+	}
+	if req.UnitPrice != nil {
+		part.UnitPrice = *req.UnitPrice
+	}
+	limit, err := s.estimateService.ApprovalLimit(workOrder.ID)
+	if err != nil {
+		return nil, err
+	}
+	transfer := &domain.StockTransferRequest{
+		RequestNumber: generateNumber("STR"),
+		RequestedBy:   viewer.UserID,
+		Note:          fmt.Sprintf("%s short at %s for work order %s", item.Name, warehouse.Name, workOrder.WONumber),
+	}
+	stocks, err := s.inventoryRepo.ListStock(item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock: %w", err)
+	}
+	transfer.FromWarehouseID = suggestTransferSource(stocks, warehouse.ID, req.Quantity)
+
+	if err := s.inventoryRepo.ReservePart(part, transfer, func(committed *interfaces.WorkOrderCommitment) error {
+		return limit.Check(committed, part)
+	}); err != nil {
+		if errors.Is(err, ErrEstimateExceeded) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reserve work order part: %w", err)
+	}
+	result := &AddPartResult{WorkOrder: workOrder}
+	if result.Part, err = s.inventoryRepo.GetPart(part.ID); err != nil {
+		return nil, fmt.Errorf("failed to get work order part: %w", err)
+	}
+	if part.Status != domain.PartStatusShort {
+		return result, nil
+	}
+
+	result.Transfer = transfer
+	s.logger.WithFields(logrus.Fields{
+		"work_order_id": workOrder.ID,
+		"item_id":       item.ID,
+		"warehouse_id":  warehouse.ID,
+		"short":         transfer.Quantity,
+		"transfer":      transfer.RequestNumber,
+	}).Info("Work order part short, stock transfer requested")
+
+	if workOrder.Status == domain.StatusInProgress {
+		held, err := s.workOrderService.Hold(viewer, workOrder.ID, &HoldWorkOrderRequest{
+			Status: domain.StatusWaitingForParts,
+			Note:   fmt.Sprintf("Waiting for %d x %s (transfer %s)", transfer.Quantity, item.Name, transfer.RequestNumber),
+		})
+		if err != nil {
+			// The reservation and transfer stand; the work order can still be put on hold by hand
+			s.logger.WithError(err).WithField("work_order_id", workOrder.ID).Warn("Failed to put work order waiting for parts")
+		} else {
+			result.WorkOrder = held
+		}
+	}
+	return result, nil
+}
+
+// IssuePart takes a reserved part out of stock as used on the work order
+func (s *PartsService) IssuePart(viewer Viewer, workOrderID, partID uint, req *PartMovementRequest) (*domain.WorkOrderPart, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+
+	movement := &domain.InventoryTransaction{
+		TransactionNumber: generateNumber("ITX"),
+		PerformedBy:       viewer.UserID,
+		Reason:            fmt.Sprintf("Used on work order %s", workOrder.WONumber),
+		Notes:             req.Note,
+	}
+	part, err := s.inventoryRepo.IssuePart(partID, movement, func(part *domain.WorkOrderPart) error {
+		if part.WorkOrderID != workOrder.ID {
+			return ErrWorkOrderPartNotFound
+		}
+		switch part.Status {
+		case domain.PartStatusShort:
+			return ErrPartShort
+		case domain.PartStatusIssued:
+			return ErrPartIssued
+		case domain.PartStatusReleased, domain.PartStatusReturned:
+			return ErrPartRemoved
+		}
+		return nil
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrWorkOrderPartNotFound
+		}
+		return nil, err
+	}
+	return part, nil
+}
+
+// RemovePart takes a part off a work order. A reserved part is released and an issued part is
+// returned to stock.
+func (s *PartsService) RemovePart(viewer Viewer, workOrderID, partID uint, req *PartMovementRequest) (*domain.WorkOrderPart, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	workOrder, err := s.workOrderService.Get(viewer, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+
+	movement := &domain.InventoryTransaction{
+		TransactionNumber: generateNumber("ITX"),
+		PerformedBy:       viewer.UserID,
+		Reason:            fmt.Sprintf("Returned from work order %s", workOrder.WONumber),
+		Notes:             req.Note,
+	}
+	part, err := s.inventoryRepo.RemovePart(partID, movement, func(part *domain.WorkOrderPart) error {
+		if part.WorkOrderID != workOrder.ID {
+			return ErrWorkOrderPartNotFound
+		}
+		if part.Status == domain.PartStatusReleased || part.Status == domain.PartStatusReturned {
+			return ErrPartRemoved
+		}
+		return nil
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrWorkOrderPartNotFound
+		}
+		return nil, err
+	}
+	return part, nil
+}
+
+// ListTransfers returns stock transfer requests, oldest first
+func (s *PartsService) ListTransfers(filter interfaces.StockTransferFilter, page, limit, offset int) (*StockTransferList, error) {
+	switch filter.Status {
+	case "", domain.TransferStatusRequested, domain.TransferStatusFulfilled, domain.TransferStatusCancelled:
+	default:
+		return nil, fmt.Errorf("validation failed: unknown status %s", filter.Status)
+	}
+	transfers, total, err := s.inventoryRepo.ListTransfers(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock transfer requests: %w", err)
+	}
+	return &StockTransferList{Transfers: transfers, Total: total, Page: page, Limit: limit}, nil
+}
+
+// GetTransfer returns a stock transfer request
+func (s *PartsService) GetTransfer(id uint) (*domain.StockTransferRequest, error) {
+	transfer, err := s.inventoryRepo.GetTransfer(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrStockTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to get stock transfer request: %w", err)
+	}
+	return transfer, nil
+}
+
+// FulfilTransfer moves the requested stock to the warehouse that is short. The moved stock is
+// reserved for the work order part the transfer was raised for.
+func (s *PartsService) FulfilTransfer(viewer Viewer, id uint, req *FulfilTransferRequest) (*domain.StockTransferRequest, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	transfer, err := s.GetTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+	fromID := transfer.FromWarehouseID
+	if req.FromWarehouseID != nil {
+		fromID = req.FromWarehouseID
+	}
+	if fromID == nil {
+		return nil, fmt.Errorf("validation failed: from_warehouse_id is required, no source was suggested")
+	}
+	if *fromID == transfer.ToWarehouseID {
+		return nil, fmt.Errorf("validation failed: source and destination warehouse are the same")
+	}
+	source, err := s.inventoryRepo.GetWarehouse(*fromID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("validation failed: warehouse %d does not exist", *fromID)
+		}
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+	if !source.IsActive {
+		return nil, fmt.Errorf("validation failed: %s is not active", source.Name)
+	}
+
+	movement := &domain.InventoryTransaction{
+		TransactionNumber: generateNumber("ITX"),
+		PerformedBy:       viewer.UserID,
+		Reason:            fmt.Sprintf("Stock transfer request %s", transfer.RequestNumber),
+		Notes:             req.Note,
+	}
+	fulfilled, err := s.inventoryRepo.FulfilTransfer(id, source.ID, viewer.UserID, movement,
+		func(transfer *domain.StockTransferRequest, stock *domain.InventoryStock) error {
+			if transfer.Status != domain.TransferStatusRequested {
+				return ErrStockTransferClosed
+			}
+			if stock.AvailableQuantity < transfer.Quantity {
+				return fmt.Errorf("%w: %s has %d of %d available", ErrInsufficientStock, source.Name, stock.AvailableQuantity, transfer.Quantity)
+			}
+			return nil
+		})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrStockTransferNotFound
+		}
+		return nil, err
+	}
+	return fulfilled, nil
+}
+
+// CancelTransfer cancels an open stock transfer request. The work order part stays short until
+// it is removed or covered by another transfer.
+func (s *PartsService) CancelTransfer(id uint, req *CancelTransferRequest) (*domain.StockTransferRequest, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	transfer, err := s.inventoryRepo.CancelTransfer(id, req.Note, func(transfer *domain.StockTransferRequest) error {
+		if transfer.Status != domain.TransferStatusRequested {
+			return ErrStockTransferClosed
+		}
+		return nil
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrStockTransferNotFound
+		}
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// partsWarehouse resolves the warehouse parts are drawn from: the requested one, or else the
+// small warehouse of the work order's mechanic
+func (s *PartsService) partsWarehouse(workOrder *domain.WorkOrder, warehouseID *uint) (*domain.Warehouse, error) {
+	if warehouseID == nil {
+		if workOrder.AssignedMechanicID == nil {
+			return nil, fmt.Errorf("validation failed: warehouse_id is required until a mechanic is assigned")
+		}
+		profile, err := s.mechanicRepo.GetProfile(*workOrder.AssignedMechanicID)
+		if err != nil && !isNotFound(err) {
+			return nil, fmt.Errorf("failed to get mechanic profile: %w", err)
+		}
+		if profile == nil || profile.WarehouseID == nil {
+			return nil, fmt.Errorf("validation failed: warehouse_id is required, the mechanic has no warehouse")
+		}
+		warehouseID = profile.WarehouseID
+	}
+
+	warehouse, err := s.inventoryRepo.GetWarehouse(*warehouseID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("validation failed: warehouse %d does not exist", *warehouseID)
+		}
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+	if !warehouse.IsActive {
+		return nil, fmt.Errorf("validation failed: %s is not active", warehouse.Name)
+	}
+	return warehouse, nil
+}
+
+// suggestTransferSource picks the warehouse a shortage is best requested from: a central
+// warehouse that can cover the quantity, else any warehouse that can, else the one with the
+// most available. stocks are ordered most available first.
+func suggestTransferSource(stocks []*domain.InventoryStock, toWarehouseID uint, quantity int) *uint {
+	var covering, most *uint
+	for _, stock := range stocks {
+		if stock.WarehouseID == toWarehouseID || stock.AvailableQuantity <= 0 {
+			continue
+		}
+		id := stock.WarehouseID
+		if stock.AvailableQuantity >= quantity {
+			if stock.Warehouse.Type == domain.WarehouseTypeCentral {
+				return &id
+			}
+			if covering == nil {
+				covering = &id
+			}
+		}
+		if most == nil {
+			most = &id
+		}
+	}
+	if covering != nil {
+		return covering
+	}
+	return most
+}
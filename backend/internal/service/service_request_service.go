@@ -0,0 +1,718 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Service request errors
+var (
+	ErrServiceRequestNotFound          = errors.New("service request not found")
+	ErrServiceAreaNotFound             = errors.New("service area not found")
+	ErrServiceAreaExists               = errors.New("service area name already exists")
+	ErrInvalidServiceRequestTransition = errors.New("invalid service request status transition")
+	ErrServiceRequestAccessDenied      = errors.New("service request is outside the current user's scope")
+)
+
+// ServiceRequestRequest represents a service request raised by a driver or coordinator
+type ServiceRequestRequest struct {
+	VehicleID           uint     `json:"vehicle_id" validate:"required"`
+	Symptoms            string   `json:"symptoms" validate:"required,max=2000"`
+	PhotoURLs           []string `json:"photo_urls" validate:"max=10,dive,url"`
+	Latitude            *float64 `json:"latitude" validate:"omitempty,min=-90,max=90"`
+	Longitude           *float64 `json:"longitude" validate:"omitempty,min=-180,max=180"`
+	LocationDescription string   `json:"location_description" validate:"max=500"`
+	Odometer            int      `json:"odometer" validate:"min=0"`
+	Urgency             string   `json:"urgency" validate:"omitempty,oneof=low normal high critical"` // defaults to normal
+}
+
+// ServiceRequestReviewRequest approves or rejects a submitted service request
+type ServiceRequestReviewRequest struct {
+	Note     string `json:"note" validate:"max=1000"`
+	BranchID *uint  `json:"branch_id"` // allocates the request right away when approving
+}
+
+// ServiceRequestAllocateRequest allocates an approved service request to a workshop branch
+type ServiceRequestAllocateRequest struct {
+	BranchID uint   `json:"branch_id" validate:"required"`
+	Note     string `json:"note" validate:"max=1000"`
+}
+
+// ServiceRequestConvertRequest converts an allocated service request into a work order
+type ServiceRequestConvertRequest struct {
+	ServiceType string `json:"service_type" validate:"omitempty,oneof=routine_maintenance repair inspection emergency customization"` // defaults to repair
+	Priority    string `json:"priority" validate:"omitempty,oneof=low normal high critical emergency"`                                // defaults to the urgency
+	Description string `json:"description"`                                                                                           // defaults to the symptoms
+}
+
+// ServiceRequestCancelRequest cancels a service request that has not been converted
+type ServiceRequestCancelRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// ServiceAreaRequest represents service area creation and update requests
+type ServiceAreaRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description"`
+	ManagerID   *uint  `json:"manager_id"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// ServiceAreaMembersRequest moves vehicles and workshop branches into an area
+type ServiceAreaMembersRequest struct {
+	VehicleIDs []uint `json:"vehicle_ids"`
+	BranchIDs  []uint `json:"branch_ids"`
+}
+
+// ServiceRequestList represents a page of service requests
+type ServiceRequestList struct {
+	Requests []*domain.ServiceRequest `json:"requests"`
+	Total    int64                    `json:"total"`
+	Page     int                      `json:"page"`
+	Limit    int                      `json:"limit"`
+}
+
+// ServiceRequestService handles service requests (PS) from drivers and coordinators. Area Managers
+// review the requests of their areas; requests for vehicles without an area can be reviewed by any
+// Area Manager. Requesters are notified as their request and the resulting work order progress.
+type ServiceRequestService struct {
+	serviceRequestRepo  interfaces.ServiceRequestRepository
+	vehicleRepo         interfaces.VehicleRepository
+	userRepo            interfaces.UserRepository
+	roleRepo            interfaces.RoleRepository
+	notificationService *NotificationService
+	validator           *validator.Validate
+	logger              *logrus.Logger
+}
+
+// NewServiceRequestService creates a new service request service
+func NewServiceRequestService(
+	serviceRequestRepo interfaces.ServiceRequestRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	userRepo interfaces.UserRepository,
+	roleRepo interfaces.RoleRepository,
+	notificationService *NotificationService,
+	logger *logrus.Logger,
+) *ServiceRequestService {
+	return &ServiceRequestService{
+		serviceRequestRepo:  serviceRequestRepo,
+		vehicleRepo:         vehicleRepo,
+		userRepo:            userRepo,
+		roleRepo:            roleRepo,
+		notificationService: notificationService,
+		validator:           validator.New(),
+		logger:              logger,
+	}
+}
+
+// Create submits a service request. Drivers can only raise requests for their assigned vehicles.
+func (s *ServiceRequestService) Create(viewer Viewer, req *ServiceRequestRequest) (*domain.ServiceRequest, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, fmt.Errorf("validation failed: latitude and longitude must be given together")
+	}
+
+	vehicle, err := s.vehicleRepo.GetByID(req.VehicleID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+	if viewer.IsDriver() {
+		assigned, err := assignedVehicleIDs(s.vehicleRepo, viewer)
+		if err != nil {
+			return nil, err
+		}
+		if !assigned[vehicle.ID] {
+			return nil, ErrVehicleAccessDenied
+		}
+	}
+
+	urgency := req.Urgency
+	if urgency == "" {
+		urgency = domain.UrgencyNormal
+	}
+	request := &domain.ServiceRequest{
+		RequestNumber:       generateNumber("PS"),
+		VehicleID:           vehicle.ID,
+		AreaID:              vehicle.AreaID,
+		RequestedBy:         viewer.UserID,
+		Symptoms:            req.Symptoms,
+		PhotoURLs:           domain.StringList(req.PhotoURLs),
+		Latitude:            req.Latitude,
+		Longitude:           req.Longitude,
+		LocationDescription: req.LocationDescription,
+		Odometer:            req.Odometer,
+		Urgency:             urgency,
+		Status:              domain.ServiceRequestSubmitted,
+	}
+	if request.PhotoURLs == nil {
+		request.PhotoURLs = domain.StringList{}
+	}
+
+	if err := s.serviceRequestRepo.Create(request); err != nil {
+		s.logger.WithError(err).Error("Service request creation failed")
+		return nil, fmt.Errorf("failed to create service request: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"service_request_id": request.ID,
+		"request_number":     request.RequestNumber,
+		"vehicle_id":         vehicle.ID,
+		"urgency":            urgency,
+	}).Info("Service request submitted")
+
+	s.notifyReviewers(request, vehicle)
+	return s.serviceRequestRepo.GetByID(request.ID)
+}
+
+// Get retrieves a service request the viewer has access to
+func (s *ServiceRequestService) Get(viewer Viewer, id uint) (*domain.ServiceRequest, error) {
+	request, err := s.serviceRequestRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrServiceRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get service request: %w", err)
+	}
+	if err := s.checkAccess(viewer, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// List retrieves a page of service requests. Drivers only see their own requests and Area
+// Managers those of their areas and of vehicles without an area.
+func (s *ServiceRequestService) List(viewer Viewer, filter interfaces.ServiceRequestFilter, page, limit, offset int) (*ServiceRequestList, error) {
+	if filter.Status != "" && !isServiceRequestStatus(filter.Status) {
+		return nil, fmt.Errorf("validation failed: unknown status %s", filter.Status)
+	}
+	if filter.Urgency != "" && !isServiceRequestUrgency(filter.Urgency) {
+		return nil, fmt.Errorf("validation failed: urgency must be low, normal, high or critical")
+	}
+
+	switch {
+	case viewer.IsDriver():
+		filter.RequestedBy = viewer.UserID
+	case viewer.Role == domain.RoleAreaManager:
+		areaIDs, err := s.serviceRequestRepo.GetAreaIDsByManager(viewer.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get managed areas: %w", err)
+		}
+		filter.AreaIDs = areaIDs
+		filter.WithoutArea = true
+	}
+
+	requests, total, err := s.serviceRequestRepo.List(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service requests: %w", err)
+	}
+	return &ServiceRequestList{Requests: requests, Total: total, Page: page, Limit: limit}, nil
+}
+
+// Approve approves a submitted request, allocating it right away when a branch is given
+func (s *ServiceRequestService) Approve(viewer Viewer, id uint, req *ServiceRequestReviewRequest) (*domain.ServiceRequest, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	var branch *domain.Warehouse
+	if req.BranchID != nil {
+		var err error
+		if branch, err = workshopBranch(s.serviceRequestRepo, *req.BranchID); err != nil {
+			return nil, err
+		}
+	}
+
+	request, err := s.transition(viewer, id, true, func(request *domain.ServiceRequest) (*domain.WorkOrder, error) {
+		if request.Status != domain.ServiceRequestSubmitted {
+			return nil, fmt.Errorf("%w: cannot approve a %s request", ErrInvalidServiceRequestTransition, request.Status)
+		}
+		now := time.Now().UTC()
+		request.Status = domain.ServiceRequestApproved
+		request.ReviewedBy = &viewer.UserID
+		request.ReviewedAt = &now
+		request.ReviewNote = req.Note
+		if branch != nil {
+			return nil, allocateServiceRequest(request, branch, viewer.UserID, now)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Service request %s was approved.", request.RequestNumber)
+	if request.Branch != nil {
+		message = fmt.Sprintf("Service request %s was approved and allocated to %s.", request.RequestNumber, request.Branch.Name)
+	}
+	s.notifyRequester(request, "service_request_approved", "Service request approved", withNote(message, req.Note))
+	return request, nil
+}
+
+// Reject rejects a request that has not been allocated yet; a note is required
+func (s *ServiceRequestService) Reject(viewer Viewer, id uint, req *ServiceRequestReviewRequest) (*domain.ServiceRequest, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if strings.TrimSpace(req.Note) == "" {
+		return nil, fmt.Errorf("validation failed: a note is required to reject a service request")
+	}
+
+	request, err := s.transition(viewer, id, true, func(request *domain.ServiceRequest) (*domain.WorkOrder, error) {
+		if request.Status != domain.ServiceRequestSubmitted && request.Status != domain.ServiceRequestApproved {
+			return nil, fmt.Errorf("%w: cannot reject a %s request", ErrInvalidServiceRequestTransition, request.Status)
+		}
+		now := time.Now().UTC()
+		request.Status = domain.ServiceRequestRejected
+		request.ReviewedBy = &viewer.UserID
+		request.ReviewedAt = &now
+		request.ReviewNote = req.Note
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyRequester(request, "service_request_rejected", "Service request rejected",
+		withNote(fmt.Sprintf("Service request %s was rejected.", request.RequestNumber), req.Note))
+	return request, nil
+}
+
+// Allocate allocates an approved request to a workshop branch, or moves it to another branch
+func (s *ServiceRequestService) Allocate(viewer Viewer, id uint, req *ServiceRequestAllocateRequest) (*domain.ServiceRequest, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	branch, err := workshopBranch(s.serviceRequestRepo, req.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := s.transition(viewer, id, true, func(request *domain.ServiceRequest) (*domain.WorkOrder, error) {
+		if request.Status != domain.ServiceRequestApproved && request.Status != domain.ServiceRequestAllocated {
+			return nil, fmt.Errorf("%w: cannot allocate a %s request", ErrInvalidServiceRequestTransition, request.Status)
+		}
+		return nil, allocateServiceRequest(request, branch, viewer.UserID, time.Now().UTC())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyRequester(request, "service_request_allocated", "Service request allocated",
+		withNote(fmt.Sprintf("Service request %s was allocated to %s.", request.RequestNumber, branch.Name), req.Note))
+	return request, nil
+}
+
+// Convert turns an allocated request into a pending work order with the current user as
+// service advisor. The work order keeps a reference to the request.
+func (s *ServiceRequestService) Convert(viewer Viewer, id uint, req *ServiceRequestConvertRequest) (*domain.ServiceRequest, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	request, err := s.transition(viewer, id, true, func(request *domain.ServiceRequest) (*domain.WorkOrder, error) {
+		if request.Status != domain.ServiceRequestAllocated {
+			return nil, fmt.Errorf("%w: only allocated requests can be converted, request is %s", ErrInvalidServiceRequestTransition, request.Status)
+		}
+		vehicle, err := s.vehicleRepo.GetByID(request.VehicleID)
+		if err != nil {
+			if isNotFound(err) {
+				return nil, ErrVehicleNotFound
+			}
+			return nil, fmt.Errorf("failed to get vehicle: %w", err)
+		}
+
+		workOrder := &domain.WorkOrder{
+			CustomerName:     internalCustomerName(vehicle),
+			VehicleID:        vehicle.ID,
+			ServiceType:      req.ServiceType,
+			Priority:         req.Priority,
+			Status:           domain.StatusPending,
+			Description:      req.Description,
+			Symptoms:         request.Symptoms,
+			ServiceAdvisorID: viewer.UserID,
+			BranchID:         request.BranchID,
+			Notes:            fmt.Sprintf("Converted from service request %s", request.RequestNumber),
+		}
+		if workOrder.ServiceType == "" {
+			workOrder.ServiceType = domain.ServiceTypeRepair
+		}
+		if workOrder.Priority == "" {
+			workOrder.Priority = request.Urgency
+		}
+		if strings.TrimSpace(workOrder.Description) == "" {
+			workOrder.Description = request.Symptoms
+		}
+		request.Status = domain.ServiceRequestConverted
+		return workOrder, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	woNumber := ""
+	if request.WorkOrder != nil {
+		woNumber = " " + request.WorkOrder.WONumber
+	}
+	s.logger.WithFields(logrus.Fields{
+		"service_request_id": request.ID,
+		"work_order_id":      request.WorkOrderID,
+	}).Info("Service request converted into work order")
+
+	s.notifyRequester(request, "service_request_converted", "Work order opened",
+		fmt.Sprintf("Service request %s was converted into work order%s.", request.RequestNumber, woNumber))
+	return request, nil
+}
+
+// Cancel withdraws a request that has not been converted. Requesters can cancel their own requests.
+func (s *ServiceRequestService) Cancel(viewer Viewer, id uint, req *ServiceRequestCancelRequest) (*domain.ServiceRequest, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	request, err := s.transition(viewer, id, false, func(request *domain.ServiceRequest) (*domain.WorkOrder, error) {
+		switch request.Status {
+		case domain.ServiceRequestSubmitted, domain.ServiceRequestApproved, domain.ServiceRequestAllocated:
+		default:
+			return nil, fmt.Errorf("%w: cannot cancel a %s request", ErrInvalidServiceRequestTransition, request.Status)
+		}
+		if request.RequestedBy != viewer.UserID {
+			if err := s.checkReview(viewer, request); err != nil {
+				return nil, err
+			}
+		}
+		request.Status = domain.ServiceRequestCancelled
+		if req.Note != "" {
+			request.ReviewNote = req.Note
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if request.RequestedBy != viewer.UserID {
+		s.notifyRequester(request, "service_request_cancelled", "Service request cancelled",
+			withNote(fmt.Sprintf("Service request %s was cancelled.", request.RequestNumber), req.Note))
+	}
+	return request, nil
+}
+
+// HandleWorkOrderStatus tells the requester how the work order converted from their request progresses
+func (s *ServiceRequestService) HandleWorkOrderStatus(workOrder *domain.WorkOrder, entry *domain.WorkOrderStatusHistory) {
+	if workOrder.ServiceRequestID == nil {
+		return
+	}
+	request, err := s.serviceRequestRepo.GetByID(*workOrder.ServiceRequestID)
+	if err != nil {
+		s.logger.WithError(err).WithField("work_order_id", workOrder.ID).Error("Failed to get service request of work order")
+		return
+	}
+
+	status := strings.ReplaceAll(entry.NewStatus, "_", " ")
+	s.notifyRequester(request, "service_request_progress", fmt.Sprintf("Work order %s %s", workOrder.WONumber, status),
+		withNote(fmt.Sprintf("Work order %s for service request %s is now %s.", workOrder.WONumber, request.RequestNumber, status), entry.Notes))
+}
+
+// CreateArea creates a service area
+func (s *ServiceRequestService) CreateArea(req *ServiceAreaRequest) (*domain.ServiceArea, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.checkAreaManager(req.ManagerID); err != nil {
+		return nil, err
+	}
+	if err := s.checkAreaName(req.Name, 0); err != nil {
+		return nil, err
+	}
+
+	area := &domain.ServiceArea{
+		Name:        req.Name,
+		Description: req.Description,
+		ManagerID:   req.ManagerID,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+	if err := s.serviceRequestRepo.CreateArea(area); err != nil {
+		return nil, fmt.Errorf("failed to create service area: %w", err)
+	}
+	return s.serviceRequestRepo.GetArea(area.ID)
+}
+
+// UpdateArea replaces the details of a service area
+func (s *ServiceRequestService) UpdateArea(id uint, req *ServiceAreaRequest) (*domain.ServiceArea, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	area, err := s.getArea(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAreaManager(req.ManagerID); err != nil {
+		return nil, err
+	}
+	if err := s.checkAreaName(req.Name, id); err != nil {
+		return nil, err
+	}
+
+	area.Name = req.Name
+	area.Description = req.Description
+	area.ManagerID = req.ManagerID
+	if req.IsActive != nil {
+		area.IsActive = *req.IsActive
+	}
+	if err := s.serviceRequestRepo.UpdateArea(area); err != nil {
+		return nil, fmt.Errorf("failed to update service area: %w", err)
+	}
+	return s.serviceRequestRepo.GetArea(id)
+}
+
+// ListAreas lists the service areas
+func (s *ServiceRequestService) ListAreas() ([]*domain.ServiceArea, error) {
+	return s.serviceRequestRepo.ListAreas()
+}
+
+// AssignToArea moves vehicles and workshop branches into an area. Requests raised earlier keep
+// the area they were raised in.
+func (s *ServiceRequestService) AssignToArea(id uint, req *ServiceAreaMembersRequest) (*domain.ServiceArea, error) {
+	if len(req.VehicleIDs) == 0 && len(req.BranchIDs) == 0 {
+		return nil, fmt.Errorf("validation failed: vehicle_ids or branch_ids is required")
+	}
+	area, err := s.getArea(id)
+	if err != nil {
+		return nil, err
+	}
+
+	vehicles, err := s.vehicleRepo.GetByIDs(req.VehicleIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vehicles: %w", err)
+	}
+	if len(vehicles) != len(uniqueUints(req.VehicleIDs)) {
+		return nil, fmt.Errorf("validation failed: unknown vehicle in vehicle_ids")
+	}
+	for _, branchID := range req.BranchIDs {
+		if _, err := workshopBranch(s.serviceRequestRepo, branchID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.serviceRequestRepo.AssignToArea(area.ID, req.VehicleIDs, req.BranchIDs); err != nil {
+		return nil, fmt.Errorf("failed to assign service area: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"area_id":  area.ID,
+		"vehicles": len(req.VehicleIDs),
+		"branches": len(req.BranchIDs),
+	}).Info("Service area members assigned")
+	return area, nil
+}
+
+// transition changes a request under a row lock. Reviews (approve, reject, allocate, convert)
+// are limited to the request's reviewers; other changes to users with access to the request.
+func (s *ServiceRequestService) transition(viewer Viewer, id uint, review bool, apply func(request *domain.ServiceRequest) (*domain.WorkOrder, error)) (*domain.ServiceRequest, error) {
+	request, err := s.serviceRequestRepo.Transition(id, func(request *domain.ServiceRequest) (*domain.WorkOrder, error) {
+		if review {
+			if err := s.checkReview(viewer, request); err != nil {
+				return nil, err
+			}
+		} else if err := s.checkAccess(viewer, request); err != nil {
+			return nil, err
+		}
+		return apply(request)
+	})
+	if err != nil {
+		if isNotFound(err) && !errors.Is(err, ErrVehicleNotFound) {
+			return nil, ErrServiceRequestNotFound
+		}
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"service_request_id": request.ID,
+		"status":             request.Status,
+		"changed_by":         viewer.UserID,
+	}).Info("Service request status changed")
+	return request, nil
+}
+
+// checkAccess limits drivers to their own requests and Area Managers to the requests they review
+func (s *ServiceRequestService) checkAccess(viewer Viewer, request *domain.ServiceRequest) error {
+	if viewer.IsDriver() {
+		if request.RequestedBy != viewer.UserID {
+			return ErrServiceRequestAccessDenied
+		}
+		return nil
+	}
+	return s.checkReview(viewer, request)
+}
+
+// checkReview limits Area Managers to the requests of their areas and of vehicles without an area
+func (s *ServiceRequestService) checkReview(viewer Viewer, request *domain.ServiceRequest) error {
+	if viewer.IsDriver() {
+		return ErrServiceRequestAccessDenied
+	}
+	if viewer.Role != domain.RoleAreaManager || request.AreaID == nil {
+		return nil
+	}
+	areaIDs, err := s.serviceRequestRepo.GetAreaIDsByManager(viewer.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get managed areas: %w", err)
+	}
+	for _, areaID := range areaIDs {
+		if areaID == *request.AreaID {
+			return nil
+		}
+	}
+	return ErrServiceRequestAccessDenied
+}
+
+// allocateServiceRequest allocates a request to a branch of its area
+func allocateServiceRequest(request *domain.ServiceRequest, branch *domain.Warehouse, allocatedBy uint, at time.Time) error {
+	if request.AreaID != nil && branch.AreaID != nil && *branch.AreaID != *request.AreaID {
+		return fmt.Errorf("validation failed: branch %s belongs to another area", branch.Name)
+	}
+	request.Status = domain.ServiceRequestAllocated
+	request.BranchID = &branch.ID
+	request.Branch = branch
+	request.AllocatedBy = &allocatedBy
+	request.AllocatedAt = &at
+	return nil
+}
+
+// getArea retrieves a service area
+func (s *ServiceRequestService) getArea(id uint) (*domain.ServiceArea, error) {
+	area, err := s.serviceRequestRepo.GetArea(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrServiceAreaNotFound
+		}
+		return nil, fmt.Errorf("failed to get service area: %w", err)
+	}
+	return area, nil
+}
+
+// checkAreaManager ensures an area is managed by an active Area Manager
+func (s *ServiceRequestService) checkAreaManager(managerID *uint) error {
+	if managerID == nil {
+		return nil
+	}
+	manager, err := s.userRepo.GetByID(*managerID)
+	if err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("validation failed: user %d does not exist", *managerID)
+		}
+		return fmt.Errorf("failed to get manager: %w", err)
+	}
+	if !manager.IsActive || manager.Role.Name != domain.RoleAreaManager {
+		return fmt.Errorf("validation failed: user %d is not an active area manager", *managerID)
+	}
+	return nil
+}
+
+// checkAreaName ensures area names are unique
+func (s *ServiceRequestService) checkAreaName(name string, id uint) error {
+	areas, err := s.serviceRequestRepo.ListAreas()
+	if err != nil {
+		return fmt.Errorf("failed to list service areas: %w", err)
+	}
+	for _, area := range areas {
+		if area.ID != id && strings.EqualFold(area.Name, name) {
+			return ErrServiceAreaExists
+		}
+	}
+	return nil
+}
+
+// notifyReviewers tells the area's manager about a new request, or every Area Manager when
+// the vehicle has no area or the area has no manager
+func (s *ServiceRequestService) notifyReviewers(request *domain.ServiceRequest, vehicle *domain.Vehicle) {
+	var recipients []uint
+	if request.AreaID != nil {
+		area, err := s.serviceRequestRepo.GetArea(*request.AreaID)
+		if err != nil {
+			s.logger.WithError(err).WithField("area_id", *request.AreaID).Error("Failed to get service area")
+		} else if area.ManagerID != nil {
+			recipients = append(recipients, *area.ManagerID)
+		}
+	}
+	if len(recipients) == 0 {
+		role, err := s.roleRepo.GetByName(domain.RoleAreaManager)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to get area manager role")
+			return
+		}
+		managers, err := s.userRepo.GetByRoleID(role.ID)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to get area managers")
+			return
+		}
+		for _, manager := range managers {
+			if manager.IsActive {
+				recipients = append(recipients, manager.ID)
+			}
+		}
+	}
+
+	err := s.notificationService.Notify(recipients, NotificationMessage{
+		Type:          "service_request_submitted",
+		Title:         fmt.Sprintf("Service request for %s", vehicle.PlateNumber),
+		Message:       fmt.Sprintf("%s (%s urgency): %s", request.RequestNumber, request.Urgency, request.Symptoms),
+		ReferenceType: "service_request",
+		ReferenceID:   request.ID,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("service_request_id", request.ID).Error("Failed to notify service request reviewers")
+	}
+}
+
+// notifyRequester tells the requester about progress on their request
+func (s *ServiceRequestService) notifyRequester(request *domain.ServiceRequest, notificationType, title, message string) {
+	err := s.notificationService.Notify([]uint{request.RequestedBy}, NotificationMessage{
+		Type:          notificationType,
+		Title:         title,
+		Message:       message,
+		ReferenceType: "service_request",
+		ReferenceID:   request.ID,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("service_request_id", request.ID).Error("Failed to notify service requester")
+	}
+}
+
+// withNote appends a reviewer note to a notification message
+func withNote(message, note string) string {
+	if strings.TrimSpace(note) == "" {
+		return message
+	}
+	return fmt.Sprintf("%s Note: %s", message, note)
+}
+
+// isServiceRequestStatus reports whether a status is one of the service request statuses
+func isServiceRequestStatus(status string) bool {
+	switch status {
+	case domain.ServiceRequestSubmitted, domain.ServiceRequestApproved, domain.ServiceRequestRejected,
+		domain.ServiceRequestAllocated, domain.ServiceRequestConverted, domain.ServiceRequestCancelled:
+		return true
+	}
+	return false
+}
+
+// isServiceRequestUrgency reports whether an urgency is known
+func isServiceRequestUrgency(urgency string) bool {
+	switch urgency {
+	case domain.UrgencyLow, domain.UrgencyNormal, domain.UrgencyHigh, domain.UrgencyCritical:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,571 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Telematics ingestion errors
+var (
+	ErrIngestionStopped = errors.New("telematics ingestion is shutting down")
+)
+
+// Ingest item statuses
+const (
+	IngestStatusAccepted  = "accepted"
+	IngestStatusDuplicate = "duplicate"
+	IngestStatusRejected  = "rejected"
+)
+
+const (
+	// ingestFlushSize and ingestFlushInterval control how concurrent requests are coalesced into one bulk insert
+	ingestFlushSize     = 5000
+	ingestFlushInterval = 100 * time.Millisecond
+	// maxSampleFutureSkew tolerates device clocks running slightly ahead
+	maxSampleFutureSkew = 5 * time.Minute
+	// maxSampleAge bounds how late buffered samples may arrive
+	maxSampleAge = 30 * 24 * time.Hour
+	// lastSeenResolution limits how often device activity is written back
+	lastSeenResolution = time.Minute
+	// listenerQueueSize is the number of stored batches each listener may fall behind by
+	listenerQueueSize = 256
+	// listenerDropWarnInterval limits how often dropped batches are logged per listener
+	listenerDropWarnInterval = time.Minute
+)
+
+// TelematicsIngestService validates telematics samples and writes them in coalesced bulk batches.
+// It is the single ingestion pipeline shared by all transports.
+type TelematicsIngestService struct {
+	telematicsRepo interfaces.TelematicsRepository
+	deviceRepo     interfaces.DeviceRepository
+	validator      *validator.Validate
+	logger         *logrus.Logger
+
+	jobs    chan *ingestJob
+	mu      sync.RWMutex
+	closed  bool
+	stop    chan struct{}
+	stopped chan struct{}
+
+	listeners        []*listenerQueue
+	listenersStopped sync.WaitGroup
+}
+
+// TelematicsListener is notified of newly stored samples, ordered by timestamp.
+// Each listener runs on its own goroutine after the samples are persisted; duplicates are
+// never delivered. A listener that falls more than listenerQueueSize batches behind misses
+// batches rather than slowing ingestion.
+type TelematicsListener interface {
+	HandleTelematics(samples []*domain.TelematicsData)
+}
+
+// TelematicsListenerStats reports the delivery backlog of one listener
+type TelematicsListenerStats struct {
+	Listener string `json:"listener"`
+	Backlog  int    `json:"backlog"`
+	Dropped  int64  `json:"dropped"`
+}
+
+// listenerQueue holds the stored batches waiting for one listener
+type listenerQueue struct {
+	listener   TelematicsListener
+	events     chan []*domain.TelematicsData
+	dropped    atomic.Int64
+	lastWarned time.Time // only touched by the writer
+}
+
+// TelematicsIngestRequest represents a batch of samples uploaded by one device
+type TelematicsIngestRequest struct {
+	Samples []TelematicsSampleRequest `json:"samples" validate:"required,min=1,max=1000"`
+}
+
+// TelematicsSampleRequest represents a single telematics reading
+type TelematicsSampleRequest struct {
+	Timestamp       time.Time `json:"timestamp" validate:"required"`
+	Latitude        float64   `json:"latitude" validate:"min=-90,max=90"`
+	Longitude       float64   `json:"longitude" validate:"min=-180,max=180"`
+	Speed           float64   `json:"speed" validate:"min=0,max=400"`
+	Heading         float64   `json:"heading" validate:"min=0,max=360"`
+	Altitude        float64   `json:"altitude" validate:"min=-1000,max=10000"`
+	EngineStatus    string    `json:"engine_status" validate:"omitempty,oneof=on off idle"`
+	FuelLevel       float64   `json:"fuel_level" validate:"min=0,max=100"`
+	EngineTemp      float64   `json:"engine_temp" validate:"min=-60,max=300"`
+	OilPressure     float64   `json:"oil_pressure" validate:"min=0,max=2000"`
+	BatteryLevel    float64   `json:"battery_level" validate:"min=0,max=100"`
+	EngineRPM       int       `json:"engine_rpm" validate:"min=0,max=20000"`
+	TotalDistance   float64   `json:"total_distance" validate:"min=0,max=99999999"`
+	FuelConsumption float64   `json:"fuel_consumption" validate:"min=0,max=1000"`
+}
+
+// IngestItemResult reports the outcome for one sample of a batch
+type IngestItemResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"` // accepted, duplicate, rejected
+	Error  string `json:"error,omitempty"`
+}
+
+// IngestResult summarizes an ingested batch
+type IngestResult struct {
+	Accepted   int                `json:"accepted"`
+	Duplicates int                `json:"duplicates"`
+	Rejected   int                `json:"rejected"`
+	Items      []IngestItemResult `json:"items"`
+}
+
+// ingestJob is a group of samples waiting for the writer
+type ingestJob struct {
+	samples []*domain.TelematicsData
+	done    chan ingestJobResult
+}
+
+type ingestJobResult struct {
+	stored []bool
+	err    error
+}
+
+// NewTelematicsIngestService creates a new telematics ingestion service and starts its writer
+func NewTelematicsIngestService(
+	telematicsRepo interfaces.TelematicsRepository,
+	deviceRepo interfaces.DeviceRepository,
+	logger *logrus.Logger,
+) *TelematicsIngestService {
+	s := &TelematicsIngestService{
+		telematicsRepo: telematicsRepo,
+		deviceRepo:     deviceRepo,
+		validator:      validator.New(),
+		logger:         logger,
+		jobs:           make(chan *ingestJob, 1024),
+		stop:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// AddListener registers a listener for stored samples and starts its delivery goroutine.
+// It must be called before ingestion starts.
+func (s *TelematicsIngestService) AddListener(listener TelematicsListener) {
+	queue := &listenerQueue{listener: listener, events: make(chan []*domain.TelematicsData, listenerQueueSize)}
+	s.listeners = append(s.listeners, queue)
+	s.listenersStopped.Add(1)
+	go s.dispatch(queue)
+}
+
+// ListenerStats reports the backlog and dropped batches of every listener
+func (s *TelematicsIngestService) ListenerStats() []TelematicsListenerStats {
+	stats := make([]TelematicsListenerStats, len(s.listeners))
+	for i, queue := range s.listeners {
+		stats[i] = TelematicsListenerStats{
+			Listener: fmt.Sprintf("%T", queue.listener),
+			Backlog:  len(queue.events),
+			Dropped:  queue.dropped.Load(),
+		}
+	}
+	return stats
+}
+
+// Close flushes pending samples and stops the writer
+func (s *TelematicsIngestService) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.stopped
+
+	for _, queue := range s.listeners {
+		close(queue.events)
+	}
+	s.listenersStopped.Wait()
+}
+
+// Ingest validates a batch uploaded by an authenticated device and stores the valid samples
+func (s *TelematicsIngestService) Ingest(device *domain.TelematicsDevice, req *TelematicsIngestRequest) (*IngestResult, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now().UTC()
+	samples := make([]*domain.TelematicsData, len(req.Samples))
+	rejections := make(map[int]string)
+	for i := range req.Samples {
+		sample := &req.Samples[i]
+		if err := s.validator.Struct(sample); err != nil {
+			rejections[i] = err.Error()
+			continue
+		}
+		samples[i] = &domain.TelematicsData{
+			DeviceID:        device.Identifier,
+			Latitude:        sample.Latitude,
+			Longitude:       sample.Longitude,
+			Speed:           sample.Speed,
+			Heading:         sample.Heading,
+			Altitude:        sample.Altitude,
+			EngineStatus:    sample.EngineStatus,
+			FuelLevel:       sample.FuelLevel,
+			EngineTemp:      sample.EngineTemp,
+			OilPressure:     sample.OilPressure,
+			BatteryLevel:    sample.BatteryLevel,
+			EngineRPM:       sample.EngineRPM,
+			TotalDistance:   sample.TotalDistance,
+			FuelConsumption: sample.FuelConsumption,
+			Timestamp:       sample.Timestamp,
+			CreatedAt:       now,
+		}
+	}
+
+	return s.ingest(samples, rejections, map[string]*domain.TelematicsDevice{device.Identifier: device})
+}
+
+// IngestSamples stores already normalized samples, e.g. decoded by a protocol gateway.
+// Each sample must carry the identifier of its device; the vehicle is resolved from the device bindings.
+func (s *TelematicsIngestService) IngestSamples(samples []*domain.TelematicsData) (*IngestResult, error) {
+	now := time.Now().UTC()
+	rejections := make(map[int]string)
+	for i, sample := range samples {
+		if sample == nil {
+			continue
+		}
+		if sample.DeviceID == "" {
+			rejections[i] = "device_id is required"
+			continue
+		}
+		// Gateway input has not passed request binding, apply the same ranges as the HTTP API
+		if err := s.validator.Struct(sampleRequestFrom(sample)); err != nil {
+			rejections[i] = err.Error()
+			continue
+		}
+		if sample.CreatedAt.IsZero() {
+			sample.CreatedAt = now
+		}
+	}
+	return s.ingest(samples, rejections, make(map[string]*domain.TelematicsDevice))
+}
+
+// sampleRequestFrom converts a normalized sample back into its request form for validation
+func sampleRequestFrom(sample *domain.TelematicsData) *TelematicsSampleRequest {
+	return &TelematicsSampleRequest{
+		Timestamp:       sample.Timestamp,
+		Latitude:        sample.Latitude,
+		Longitude:       sample.Longitude,
+		Speed:           sample.Speed,
+		Heading:         sample.Heading,
+		Altitude:        sample.Altitude,
+		EngineStatus:    sample.EngineStatus,
+		FuelLevel:       sample.FuelLevel,
+		EngineTemp:      sample.EngineTemp,
+		OilPressure:     sample.OilPressure,
+		BatteryLevel:    sample.BatteryLevel,
+		EngineRPM:       sample.EngineRPM,
+		TotalDistance:   sample.TotalDistance,
+		FuelConsumption: sample.FuelConsumption,
+	}
+}
+
+// ingest checks sample timestamps, routes samples to vehicles, hands them to the writer and
+// builds per-item results. Samples at indexes present in rejections, or nil samples, are rejected.
+func (s *TelematicsIngestService) ingest(samples []*domain.TelematicsData, rejections map[int]string, devices map[string]*domain.TelematicsDevice) (*IngestResult, error) {
+	now := time.Now().UTC()
+
+	for i, sample := range samples {
+		if _, rejected := rejections[i]; rejected {
+			continue
+		}
+		if sample == nil {
+			rejections[i] = "invalid sample"
+			continue
+		}
+		// Timestamps are stored without time zone at microsecond precision
+		sample.Timestamp = sample.Timestamp.UTC().Truncate(time.Microsecond)
+		switch {
+		case sample.Timestamp.After(now.Add(maxSampleFutureSkew)):
+			rejections[i] = "timestamp is in the future"
+		case sample.Timestamp.Before(now.Add(-maxSampleAge)):
+			rejections[i] = "timestamp is older than the accepted ingestion window"
+		}
+	}
+
+	if err := s.routeSamples(samples, rejections, devices); err != nil {
+		return nil, err
+	}
+
+	var valid []*domain.TelematicsData
+	var validIndexes []int
+	for i, sample := range samples {
+		if _, rejected := rejections[i]; !rejected {
+			valid = append(valid, sample)
+			validIndexes = append(validIndexes, i)
+		}
+	}
+
+	stored := make([]bool, len(valid))
+	if len(valid) > 0 {
+		var err error
+		if stored, err = s.submit(valid); err != nil {
+			return nil, err
+		}
+		s.touchDevices(devices, now)
+	}
+
+	result := &IngestResult{Items: make([]IngestItemResult, len(samples))}
+	for i := range samples {
+		result.Items[i] = IngestItemResult{Index: i}
+		if msg, rejected := rejections[i]; rejected {
+			result.Items[i].Status = IngestStatusRejected
+			result.Items[i].Error = msg
+			result.Rejected++
+		}
+	}
+	for n, i := range validIndexes {
+		if stored[n] {
+			result.Items[i].Status = IngestStatusAccepted
+			result.Accepted++
+		} else {
+			result.Items[i].Status = IngestStatusDuplicate
+			result.Duplicates++
+		}
+	}
+
+	return result, nil
+}
+
+// routeSamples assigns each sample to the vehicle its device was bound to at the sample timestamp.
+// Devices already known (e.g. authenticated by the transport) are taken from devices; others are looked up.
+func (s *TelematicsIngestService) routeSamples(samples []*domain.TelematicsData, rejections map[int]string, devices map[string]*domain.TelematicsDevice) error {
+	type period struct{ from, to time.Time }
+	periods := make(map[string]*period)
+	for i, sample := range samples {
+		if _, rejected := rejections[i]; rejected {
+			continue
+		}
+		p, ok := periods[sample.DeviceID]
+		if !ok {
+			periods[sample.DeviceID] = &period{from: sample.Timestamp, to: sample.Timestamp}
+			continue
+		}
+		if sample.Timestamp.Before(p.from) {
+			p.from = sample.Timestamp
+		}
+		if sample.Timestamp.After(p.to) {
+			p.to = sample.Timestamp
+		}
+	}
+
+	bindings := make(map[string][]*domain.DeviceBinding, len(periods))
+	deviceErrors := make(map[string]string)
+	for identifier, p := range periods {
+		device, ok := devices[identifier]
+		if !ok {
+			var err error
+			device, err = s.deviceRepo.GetByIdentifier(identifier)
+			if err != nil {
+				if isNotFound(err) {
+					deviceErrors[identifier] = "unknown device"
+					continue
+				}
+				return fmt.Errorf("failed to get device: %w", err)
+			}
+			devices[identifier] = device
+		}
+		if device.Status != domain.DeviceStatusActive {
+			deviceErrors[identifier] = "device is not active"
+			continue
+		}
+
+		deviceBindings, err := s.deviceRepo.GetBindingsInRange(device.ID, p.from, p.to)
+		if err != nil {
+			return fmt.Errorf("failed to get device bindings: %w", err)
+		}
+		bindings[identifier] = deviceBindings
+	}
+
+	for i, sample := range samples {
+		if _, rejected := rejections[i]; rejected {
+			continue
+		}
+		if msg, failed := deviceErrors[sample.DeviceID]; failed {
+			rejections[i] = msg
+			continue
+		}
+		sample.VehicleID = 0
+		for _, binding := range bindings[sample.DeviceID] {
+			if binding.Covers(sample.Timestamp) {
+				sample.VehicleID = binding.VehicleID
+				break
+			}
+		}
+		if sample.VehicleID == 0 {
+			rejections[i] = "device was not bound to a vehicle at the sample timestamp"
+		}
+	}
+	return nil
+}
+
+// touchDevices records device activity, at most once per lastSeenResolution to spare the database
+func (s *TelematicsIngestService) touchDevices(devices map[string]*domain.TelematicsDevice, now time.Time) {
+	for _, device := range devices {
+		if device.LastSeenAt != nil && now.Sub(*device.LastSeenAt) < lastSeenResolution {
+			continue
+		}
+		if err := s.deviceRepo.UpdateLastSeen(device.ID, now); err != nil {
+			s.logger.WithError(err).WithField("device_id", device.ID).Warn("Failed to update device last seen")
+			continue
+		}
+		device.LastSeenAt = &now
+	}
+}
+
+// submit queues samples for the writer and waits until they are persisted
+func (s *TelematicsIngestService) submit(samples []*domain.TelematicsData) ([]bool, error) {
+	job := &ingestJob{samples: samples, done: make(chan ingestJobResult, 1)}
+
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, ErrIngestionStopped
+	}
+	s.jobs <- job
+	s.mu.RUnlock()
+
+	result := <-job.done
+	return result.stored, result.err
+}
+
+// run coalesces queued jobs and flushes them when the batch is full or the interval elapses
+func (s *TelematicsIngestService) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(ingestFlushInterval)
+	defer ticker.Stop()
+
+	var pending []*ingestJob
+	size := 0
+	flush := func() {
+		if len(pending) > 0 {
+			s.flush(pending, size)
+			pending, size = nil, 0
+		}
+	}
+
+	for {
+		select {
+		case job := <-s.jobs:
+			pending = append(pending, job)
+			size += len(job.samples)
+			if size >= ingestFlushSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.stop:
+			// No new jobs can be queued once stop is closed; drain what is left
+			for {
+				select {
+				case job := <-s.jobs:
+					pending = append(pending, job)
+					size += len(job.samples)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes the samples of all pending jobs in one bulk insert
+func (s *TelematicsIngestService) flush(jobs []*ingestJob, size int) {
+	batch := make([]*domain.TelematicsData, 0, size)
+	for _, job := range jobs {
+		batch = append(batch, job.samples...)
+	}
+
+	start := time.Now()
+	stored, err := s.telematicsRepo.InsertBatch(batch)
+	if err != nil {
+		s.logger.WithError(err).WithField("samples", len(batch)).Error("Failed to store telematics batch")
+		for _, job := range jobs {
+			job.done <- ingestJobResult{err: fmt.Errorf("failed to store telematics samples: %w", err)}
+		}
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"samples":     len(batch),
+		"duration_ms": time.Since(start).Milliseconds(),
+	}).Debug("Telematics batch stored")
+
+	offset := 0
+	for _, job := range jobs {
+		job.done <- ingestJobResult{stored: stored[offset : offset+len(job.samples)]}
+		offset += len(job.samples)
+	}
+
+	if len(s.listeners) > 0 {
+		var fresh []*domain.TelematicsData
+		for i, sample := range batch {
+			if stored[i] {
+				fresh = append(fresh, sample)
+			}
+		}
+		if len(fresh) > 0 {
+			sort.SliceStable(fresh, func(i, j int) bool {
+				return fresh[i].Timestamp.Before(fresh[j].Timestamp)
+			})
+			for _, queue := range s.listeners {
+				s.enqueue(queue, fresh)
+			}
+		}
+	}
+}
+
+// enqueue hands stored samples to a listener without waiting for it. When the listener is
+// too far behind, the batch is dropped for that listener alone.
+func (s *TelematicsIngestService) enqueue(queue *listenerQueue, samples []*domain.TelematicsData) {
+	select {
+	case queue.events <- samples:
+		return
+	default:
+	}
+	dropped := queue.dropped.Add(1)
+	if now := time.Now(); now.Sub(queue.lastWarned) >= listenerDropWarnInterval {
+		queue.lastWarned = now
+		s.logger.WithFields(logrus.Fields{
+			"listener": fmt.Sprintf("%T", queue.listener),
+			"dropped":  dropped,
+		}).Warn("Telematics listener is falling behind, dropping batches")
+	}
+}
+
+// dispatch delivers stored samples to one listener
+func (s *TelematicsIngestService) dispatch(queue *listenerQueue) {
+	defer s.listenersStopped.Done()
+
+	for samples := range queue.events {
+		s.notify(queue.listener, samples)
+	}
+}
+
+// notify calls one listener, isolating the pipeline from listener panics
+func (s *TelematicsIngestService) notify(listener TelematicsListener, samples []*domain.TelematicsData) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.WithField("panic", r).Errorf("Telematics listener %T panicked", listener)
+		}
+	}()
+	listener.HandleTelematics(samples)
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+const (
+	// Ranges up to these spans are served raw and at 1-minute resolution when none is requested
+	autoRawSeriesRange    = 6 * time.Hour
+	autoMinuteSeriesRange = 7 * 24 * time.Hour
+	// Ranges served at each resolution are bounded to keep responses small
+	maxRawSeriesRange    = 24 * time.Hour
+	maxMinuteSeriesRange = 31 * 24 * time.Hour
+	maxHourSeriesRange   = 3 * 366 * 24 * time.Hour
+	// compactionBatch and maxCompactionTasks bound the vehicle hours aggregated per query and per run
+	compactionBatch    = 500
+	maxCompactionTasks = 20000
+)
+
+// TelematicsRetentionConfig controls how long telematics data is kept at each resolution.
+// A zero retention keeps that resolution forever.
+type TelematicsRetentionConfig struct {
+	RawRetention        time.Duration // whole days; raw samples are dropped a daily partition at a time
+	MinuteRetention     time.Duration
+	HourRetention       time.Duration
+	PartitionPremake    int // days of partitions created ahead
+	MaintenanceInterval time.Duration
+}
+
+// TelematicsSeries is the telematics history of a vehicle at one resolution
+type TelematicsSeries struct {
+	VehicleID  uint                          `json:"vehicle_id"`
+	From       time.Time                     `json:"from"`
+	To         time.Time                     `json:"to"`
+	Resolution string                        `json:"resolution"`
+	Points     []*domain.TelematicsAggregate `json:"points"`
+}
+
+// TelematicsStorageService manages the lifecycle of telematics data. Raw samples live in daily
+// partitions that are created ahead and dropped after the raw retention; ingested samples queue
+// their vehicle hour for aggregation into 1-minute and 1-hour buckets, which are kept longer.
+// History queries pick the resolution from the requested range.
+type TelematicsStorageService struct {
+	storageRepo    interfaces.TelematicsStorageRepository
+	telematicsRepo interfaces.TelematicsRepository
+	vehicleRepo    interfaces.VehicleRepository
+	config         TelematicsRetentionConfig
+	logger         *logrus.Logger
+
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+// NewTelematicsStorageService creates a new telematics storage service
+func NewTelematicsStorageService(
+	storageRepo interfaces.TelematicsStorageRepository,
+	telematicsRepo interfaces.TelematicsRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	config TelematicsRetentionConfig,
+	logger *logrus.Logger,
+) *TelematicsStorageService {
+	if config.MaintenanceInterval <= 0 {
+		config.MaintenanceInterval = 5 * time.Minute
+	}
+	return &TelematicsStorageService{
+		storageRepo:    storageRepo,
+		telematicsRepo: telematicsRepo,
+		vehicleRepo:    vehicleRepo,
+		config:         config,
+		logger:         logger,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start runs the maintenance job right away and then periodically. Only one process needs to
+// run it; every process that ingests samples must register the service as a listener.
+func (s *TelematicsStorageService) Start() {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		s.runMaintenance()
+		ticker := time.NewTicker(s.config.MaintenanceInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.runMaintenance()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the maintenance job
+func (s *TelematicsStorageService) Close() {
+	close(s.stop)
+	s.workers.Wait()
+}
+
+// HandleTelematics queues the vehicle hours of new samples for aggregation; it is registered as an ingestion listener
+func (s *TelematicsStorageService) HandleTelematics(samples []*domain.TelematicsData) {
+	type vehicleHour struct {
+		vehicleID uint
+		hour      time.Time
+	}
+	queuedAt := time.Now().UTC()
+	seen := make(map[vehicleHour]bool)
+	var tasks []*domain.TelematicsCompactionTask
+	for _, sample := range samples {
+		key := vehicleHour{vehicleID: sample.VehicleID, hour: sample.Timestamp.UTC().Truncate(time.Hour)}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		tasks = append(tasks, &domain.TelematicsCompactionTask{VehicleID: key.vehicleID, Hour: key.hour, QueuedAt: queuedAt})
+	}
+	if err := s.storageRepo.EnqueueCompaction(tasks); err != nil {
+		s.logger.WithError(err).WithField("hours", len(tasks)).Error("Failed to queue telematics compaction")
+	}
+}
+
+func (s *TelematicsStorageService) runMaintenance() {
+	started := time.Now()
+	if err := s.RunMaintenance(); err != nil {
+		s.logger.WithError(err).Error("Telematics maintenance failed")
+		return
+	}
+	s.logger.WithField("duration", time.Since(started).String()).Debug("Telematics maintenance completed")
+}
+
+// RunMaintenance creates upcoming partitions, aggregates queued vehicle hours and applies retention
+func (s *TelematicsStorageService) RunMaintenance() error {
+	now := time.Now().UTC()
+	if err := s.ensurePartitions(now); err != nil {
+		return err
+	}
+	if err := s.compact(); err != nil {
+		return err
+	}
+	return s.applyRetention(now)
+}
+
+// ensurePartitions creates the partitions from yesterday to the premake horizon
+func (s *TelematicsStorageService) ensurePartitions(now time.Time) error {
+	days, err := s.storageRepo.ListPartitionDays()
+	if err != nil {
+		return fmt.Errorf("failed to list telematics partitions: %w", err)
+	}
+	existing := make(map[time.Time]bool, len(days))
+	for _, day := range days {
+		existing[day] = true
+	}
+
+	today := now.Truncate(24 * time.Hour)
+	for offset := -1; offset <= s.config.PartitionPremake; offset++ {
+		day := today.AddDate(0, 0, offset)
+		if existing[day] {
+			continue
+		}
+		if err := s.storageRepo.CreatePartition(day); err != nil {
+			return fmt.Errorf("failed to create telematics partition for %s: %w", day.Format("2006-01-02"), err)
+		}
+		s.logger.WithField("day", day.Format("2006-01-02")).Info("Created telematics partition")
+	}
+	return nil
+}
+
+// compact aggregates queued vehicle hours, oldest first
+func (s *TelematicsStorageService) compact() error {
+	processed := 0
+	for processed < maxCompactionTasks {
+		tasks, err := s.storageRepo.GetCompactionTasks(compactionBatch)
+		if err != nil {
+			return fmt.Errorf("failed to get compaction tasks: %w", err)
+		}
+		for _, task := range tasks {
+			select {
+			case <-s.stop:
+				return nil
+			default:
+			}
+			if err := s.storageRepo.Compact(task); err != nil {
+				return fmt.Errorf("failed to aggregate vehicle %d at %s: %w", task.VehicleID, task.Hour.Format(time.RFC3339), err)
+			}
+		}
+		processed += len(tasks)
+		// Hours queued again meanwhile come back; they are picked up on the next run
+		if len(tasks) < compactionBatch {
+			break
+		}
+	}
+	return nil
+}
+
+// applyRetention drops raw partitions and aggregates past their retention. Raw days with
+// hours not yet aggregated are kept until they are.
+func (s *TelematicsStorageService) applyRetention(now time.Time) error {
+	if s.config.RawRetention > 0 {
+		cutoff := now.Truncate(24 * time.Hour).Add(-s.config.RawRetention)
+		oldest, err := s.storageRepo.OldestQueuedHour()
+		if err != nil {
+			return fmt.Errorf("failed to get compaction queue: %w", err)
+		}
+		if oldest != nil && oldest.Before(cutoff) {
+			cutoff = oldest.UTC().Truncate(24 * time.Hour)
+		}
+
+		days, err := s.storageRepo.ListPartitionDays()
+		if err != nil {
+			return fmt.Errorf("failed to list telematics partitions: %w", err)
+		}
+		for _, day := range days {
+			if day.AddDate(0, 0, 1).After(cutoff) {
+				break
+			}
+			if err := s.storageRepo.DropPartition(day); err != nil {
+				return fmt.Errorf("failed to drop telematics partition for %s: %w", day.Format("2006-01-02"), err)
+			}
+			s.logger.WithField("day", day.Format("2006-01-02")).Info("Dropped telematics partition")
+		}
+		if _, err := s.storageRepo.PurgeDefaultPartition(cutoff); err != nil {
+			return fmt.Errorf("failed to purge default telematics partition: %w", err)
+		}
+	}
+
+	for resolution, retention := range map[string]time.Duration{
+		domain.TelematicsResolutionMinute: s.config.MinuteRetention,
+		domain.TelematicsResolutionHour:   s.config.HourRetention,
+	} {
+		if retention <= 0 {
+			continue
+		}
+		deleted, err := s.storageRepo.DeleteAggregatesBefore(resolution, now.Add(-retention))
+		if err != nil {
+			return fmt.Errorf("failed to delete %s telematics aggregates: %w", resolution, err)
+		}
+		if deleted > 0 {
+			s.logger.WithFields(logrus.Fields{"resolution": resolution, "buckets": deleted}).Info("Deleted expired telematics aggregates")
+		}
+	}
+	return nil
+}
+
+// GetSeries returns the telematics history of a vehicle in [from, to]. Without a resolution,
+// short ranges are served raw and longer ones from aggregates; a range reaching past the
+// retention of a resolution is served from the next coarser one. Aggregates trail ingestion by
+// up to the maintenance interval.
+func (s *TelematicsStorageService) GetSeries(viewer Viewer, vehicleID uint, from, to time.Time, resolution string) (*TelematicsSeries, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalidTimeRange)
+	}
+	resolution, err := s.resolveResolution(resolution, from, to, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	series := &TelematicsSeries{VehicleID: vehicleID, From: from, To: to, Resolution: resolution}
+	if resolution == domain.TelematicsResolutionRaw {
+		samples, err := s.telematicsRepo.GetByVehicle(vehicleID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get telematics samples: %w", err)
+		}
+		series.Points = make([]*domain.TelematicsAggregate, len(samples))
+		for i, sample := range samples {
+			series.Points[i] = domain.NewRawTelematicsAggregate(sample)
+		}
+		return series, nil
+	}
+
+	series.Points, err = s.storageRepo.GetAggregates(resolution, vehicleID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get telematics aggregates: %w", err)
+	}
+	return series, nil
+}
+
+// resolveResolution picks the resolution of a range and checks the range fits it
+func (s *TelematicsStorageService) resolveResolution(resolution string, from, to, now time.Time) (string, error) {
+	span := to.Sub(from)
+	if resolution == "" || resolution == "auto" {
+		switch {
+		case span <= autoRawSeriesRange:
+			resolution = domain.TelematicsResolutionRaw
+		case span <= autoMinuteSeriesRange:
+			resolution = domain.TelematicsResolutionMinute
+		default:
+			resolution = domain.TelematicsResolutionHour
+		}
+		if resolution == domain.TelematicsResolutionRaw && s.expired(s.config.RawRetention, from, now) {
+			resolution = domain.TelematicsResolutionMinute
+		}
+		if resolution == domain.TelematicsResolutionMinute && s.expired(s.config.MinuteRetention, from, now) {
+			resolution = domain.TelematicsResolutionHour
+		}
+	}
+
+	var limit time.Duration
+	switch resolution {
+	case domain.TelematicsResolutionRaw:
+		limit = maxRawSeriesRange
+	case domain.TelematicsResolutionMinute:
+		limit = maxMinuteSeriesRange
+	case domain.TelematicsResolutionHour:
+		limit = maxHourSeriesRange
+	default:
+		return "", fmt.Errorf("validation failed: resolution must be one of auto, raw, 1m, 1h")
+	}
+	if span > limit {
+		return "", fmt.Errorf("%w: the range at %s resolution is at most %s", ErrInvalidTimeRange, resolution, limit)
+	}
+	return resolution, nil
+}
+
+// expired reports whether data from t on is past a retention
+func (s *TelematicsStorageService) expired(retention time.Duration, t, now time.Time) bool {
+	return retention > 0 && t.Before(now.Add(-retention))
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Telematics streaming errors
+var (
+	ErrNoStreamableVehicles = errors.New("no vehicles are available for streaming")
+	ErrInvalidBoundingBox   = errors.New("invalid bounding box")
+)
+
+const (
+	// telematicsUpdatesChannel is the Redis pub/sub channel shared by all API and gateway instances
+	telematicsUpdatesChannel = "telematics:updates"
+	// streamBufferSize is the number of updates queued per subscriber
+	streamBufferSize = 256
+	// slowConsumerTimeout disconnects subscribers that have not drained their queue for this long
+	slowConsumerTimeout = 30 * time.Second
+)
+
+// TelematicsUpdate is a live position and engine update pushed to stream subscribers
+type TelematicsUpdate struct {
+	VehicleID    uint      `json:"vehicle_id"`
+	DeviceID     string    `json:"device_id"`
+	Timestamp    time.Time `json:"timestamp"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	Speed        float64   `json:"speed"`
+	Heading      float64   `json:"heading"`
+	EngineStatus string    `json:"engine_status,omitempty"`
+	FuelLevel    float64   `json:"fuel_level"`
+	BatteryLevel float64   `json:"battery_level"`
+	EngineRPM    int       `json:"engine_rpm"`
+}
+
+// BoundingBox is a geographic rectangle in degrees
+type BoundingBox struct {
+	MinLongitude float64 `json:"min_longitude"`
+	MinLatitude  float64 `json:"min_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+}
+
+// Validate checks the box is inside coordinate ranges and not inverted
+func (b *BoundingBox) Validate() error {
+	if b.MinLatitude < -90 || b.MaxLatitude > 90 || b.MinLongitude < -180 || b.MaxLongitude > 180 ||
+		b.MinLatitude > b.MaxLatitude || b.MinLongitude > b.MaxLongitude {
+		return ErrInvalidBoundingBox
+	}
+	return nil
+}
+
+// Contains reports whether a point lies inside the box
+func (b *BoundingBox) Contains(latitude, longitude float64) bool {
+	return latitude >= b.MinLatitude && latitude <= b.MaxLatitude &&
+		longitude >= b.MinLongitude && longitude <= b.MaxLongitude
+}
+
+// StreamSubscriptionRequest selects the vehicles a client wants to follow.
+// Empty VehicleIDs and a nil BBox follow every vehicle the user may see.
+type StreamSubscriptionRequest struct {
+	VehicleIDs []uint       `json:"vehicle_ids"`
+	BBox       *BoundingBox `json:"bbox"`
+}
+
+// streamFilter is the effective, permission-checked selection of a subscription
+type streamFilter struct {
+	vehicleIDs map[uint]bool // nil matches all vehicles
+	bbox       *BoundingBox
+}
+
+func (f *streamFilter) matches(update *TelematicsUpdate) bool {
+	if f.vehicleIDs != nil && !f.vehicleIDs[update.VehicleID] {
+		return false
+	}
+	if f.bbox != nil && !f.bbox.Contains(update.Latitude, update.Longitude) {
+		return false
+	}
+	return true
+}
+
+// StreamSubscription receives the updates matching its filter.
+// Updates are dropped, not queued without bound, when the client reads too slowly.
+type StreamSubscription struct {
+	updates chan *TelematicsUpdate
+	done    chan struct{}
+
+	mu            sync.Mutex
+	viewer        Viewer
+	filter        *streamFilter
+	dropped       int
+	lastDelivered time.Time
+	closed        bool
+	closeReason   string
+}
+
+// Updates returns the channel of matching updates
+func (s *StreamSubscription) Updates() <-chan *TelematicsUpdate {
+	return s.updates
+}
+
+// Done is closed when the subscription ends, e.g. because the client is too slow
+func (s *StreamSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// CloseReason explains why the service ended the subscription
+func (s *StreamSubscription) CloseReason() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeReason
+}
+
+// TakeDropped returns and resets the number of updates dropped since the last call
+func (s *StreamSubscription) TakeDropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dropped := s.dropped
+	s.dropped = 0
+	return dropped
+}
+
+// offer queues an update without blocking the fan-out
+func (s *StreamSubscription) offer(update *TelematicsUpdate, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || !s.filter.matches(update) {
+		return true
+	}
+	select {
+	case s.updates <- update:
+		s.lastDelivered = now
+		return true
+	default:
+		s.dropped++
+		return now.Sub(s.lastDelivered) < slowConsumerTimeout
+	}
+}
+
+func (s *StreamSubscription) close(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		s.closeReason = reason
+		close(s.done)
+	}
+}
+
+// TelematicsStreamService fans out stored telematics samples to live subscribers.
+// With Redis, samples are published to a shared channel so subscribers on every
+// instance receive samples ingested anywhere; without it, fan-out is process local.
+type TelematicsStreamService struct {
+	redis       *redis.Client
+	vehicleRepo interfaces.VehicleRepository
+	logger      *logrus.Logger
+
+	mu            sync.RWMutex
+	subscriptions map[*StreamSubscription]struct{}
+
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// NewTelematicsStreamService creates a new telematics stream service. redisClient may be nil.
+func NewTelematicsStreamService(redisClient *redis.Client, vehicleRepo interfaces.VehicleRepository, logger *logrus.Logger) *TelematicsStreamService {
+	return &TelematicsStreamService{
+		redis:         redisClient,
+		vehicleRepo:   vehicleRepo,
+		logger:        logger,
+		subscriptions: make(map[*StreamSubscription]struct{}),
+	}
+}
+
+// Start listens for updates published by all instances. It is a no-op without Redis.
+func (s *TelematicsStreamService) Start() {
+	if s.redis == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.stopped = make(chan struct{})
+
+	pubsub := s.redis.Subscribe(ctx, telematicsUpdatesChannel)
+	go func() {
+		defer close(s.stopped)
+		defer pubsub.Close()
+
+		for msg := range pubsub.Channel() {
+			var updates []*TelematicsUpdate
+			if err := json.Unmarshal([]byte(msg.Payload), &updates); err != nil {
+				s.logger.WithError(err).Warn("Invalid telematics update message")
+				continue
+			}
+			s.broadcast(updates)
+		}
+	}()
+}
+
+// Close stops listening and ends all subscriptions
+func (s *TelematicsStreamService) Close() {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.stopped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscriptions {
+		sub.close("server shutting down")
+		delete(s.subscriptions, sub)
+	}
+}
+
+// HandleTelematics publishes stored samples; it is registered as an ingestion listener
+func (s *TelematicsStreamService) HandleTelematics(samples []*domain.TelematicsData) {
+	updates := make([]*TelematicsUpdate, len(samples))
+	for i, sample := range samples {
+		updates[i] = &TelematicsUpdate{
+			VehicleID:    sample.VehicleID,
+			DeviceID:     sample.DeviceID,
+			Timestamp:    sample.Timestamp,
+			Latitude:     sample.Latitude,
+			Longitude:    sample.Longitude,
+			Speed:        sample.Speed,
+			Heading:      sample.Heading,
+			EngineStatus: sample.EngineStatus,
+			FuelLevel:    sample.FuelLevel,
+			BatteryLevel: sample.BatteryLevel,
+			EngineRPM:    sample.EngineRPM,
+		}
+	}
+
+	if s.redis == nil {
+		s.broadcast(updates)
+		return
+	}
+
+	payload, err := json.Marshal(updates)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to encode telematics updates")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.redis.Publish(ctx, telematicsUpdatesChannel, payload).Err(); err != nil {
+		s.logger.WithError(err).Warn("Failed to publish telematics updates")
+	}
+}
+
+// Subscribe starts a subscription for a user. Drivers are limited to their assigned vehicles.
+func (s *TelematicsStreamService) Subscribe(viewer Viewer, req *StreamSubscriptionRequest) (*StreamSubscription, error) {
+	filter, err := s.buildFilter(viewer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &StreamSubscription{
+		updates:       make(chan *TelematicsUpdate, streamBufferSize),
+		done:          make(chan struct{}),
+		viewer:        viewer,
+		filter:        filter,
+		lastDelivered: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.subscriptions[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub, nil
+}
+
+// Resubscribe replaces the selection of an existing subscription
+func (s *TelematicsStreamService) Resubscribe(sub *StreamSubscription, req *StreamSubscriptionRequest) error {
+	filter, err := s.buildFilter(sub.viewer, req)
+	if err != nil {
+		return err
+	}
+	sub.mu.Lock()
+	sub.filter = filter
+	sub.mu.Unlock()
+	return nil
+}
+
+// Unsubscribe ends a subscription
+func (s *TelematicsStreamService) Unsubscribe(sub *StreamSubscription) {
+	s.mu.Lock()
+	delete(s.subscriptions, sub)
+	s.mu.Unlock()
+	sub.close("unsubscribed")
+}
+
+// buildFilter intersects the requested selection with what the viewer may see
+func (s *TelematicsStreamService) buildFilter(viewer Viewer, req *StreamSubscriptionRequest) (*streamFilter, error) {
+	filter := &streamFilter{}
+	if req.BBox != nil {
+		if err := req.BBox.Validate(); err != nil {
+			return nil, err
+		}
+		filter.bbox = req.BBox
+	}
+	if len(req.VehicleIDs) > 0 {
+		filter.vehicleIDs = make(map[uint]bool, len(req.VehicleIDs))
+		for _, id := range req.VehicleIDs {
+			filter.vehicleIDs[id] = true
+		}
+	}
+
+	if viewer.IsDriver() {
+		assigned, err := assignedVehicleIDs(s.vehicleRepo, viewer)
+		if err != nil {
+			return nil, err
+		}
+		if filter.vehicleIDs == nil {
+			filter.vehicleIDs = assigned
+		} else {
+			for id := range filter.vehicleIDs {
+				if !assigned[id] {
+					delete(filter.vehicleIDs, id)
+				}
+			}
+		}
+		if len(filter.vehicleIDs) == 0 {
+			return nil, ErrNoStreamableVehicles
+		}
+	}
+	return filter, nil
+}
+
+// broadcast offers updates to every local subscriber, closing those that stopped reading
+func (s *TelematicsStreamService) broadcast(updates []*TelematicsUpdate) {
+	now := time.Now()
+	var slow []*StreamSubscription
+
+	s.mu.RLock()
+	for sub := range s.subscriptions {
+		for _, update := range updates {
+			if !sub.offer(update, now) {
+				slow = append(slow, sub)
+				break
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(slow) > 0 {
+		s.mu.Lock()
+		for _, sub := range slow {
+			delete(s.subscriptions, sub)
+			sub.close("client is not reading updates fast enough")
+		}
+		s.mu.Unlock()
+		s.logger.WithField("subscriptions", len(slow)).Warn("Closed slow telematics stream subscriptions")
+	}
+}
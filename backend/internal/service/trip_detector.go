@@ -0,0 +1,192 @@
+package service
+
+import (
+	"time"
+
+	"ton-platform/internal/domain"
+	"ton-platform/pkg/geo"
+)
+
+// TripDetectionConfig holds the thresholds of the trip segmentation engine
+type TripDetectionConfig struct {
+	// MovingSpeed is the speed (km/h) from which a vehicle counts as moving
+	MovingSpeed float64
+	// StopDuration ends a trip when the vehicle has not moved for this long with the ignition on
+	StopDuration time.Duration
+	// MaxSampleGap ends a trip when no sample arrived for this long
+	MaxSampleGap time.Duration
+	// MinDistanceKm and MinDuration discard GPS drift and manoeuvring
+	MinDistanceKm float64
+	MinDuration   time.Duration
+	// MaxPlausibleSpeed drops position jumps faster than this (km/h) from the distance
+	MaxPlausibleSpeed float64
+}
+
+// DefaultTripDetectionConfig returns thresholds suited to road vehicles
+func DefaultTripDetectionConfig() TripDetectionConfig {
+	return TripDetectionConfig{
+		MovingSpeed:       5,
+		StopDuration:      5 * time.Minute,
+		MaxSampleGap:      10 * time.Minute,
+		MinDistanceKm:     0.2,
+		MinDuration:       time.Minute,
+		MaxPlausibleSpeed: 250,
+	}
+}
+
+// detectTrips segments samples ordered by timestamp into trips. lead is the sample preceding
+// samples, if any; it may be where the first trip started. A trip still open at the
+// last sample is reported in progress when that sample is recent relative to now.
+func detectTrips(samples []*domain.TelematicsData, lead *domain.TelematicsData, config TripDetectionConfig, now time.Time) []*domain.Trip {
+	var trips []*domain.Trip
+	var current []*domain.TelematicsData
+	lastMoving := -1 // index into current
+	prev := lead
+
+	finish := func() {
+		if lastMoving >= 0 {
+			if trip := buildTrip(current[:lastMoving+1], config); trip != nil {
+				trips = append(trips, trip)
+			}
+		}
+		current, lastMoving = nil, -1
+	}
+
+	for _, sample := range samples {
+		moving := sample.Speed >= config.MovingSpeed
+		ignitionOff := sample.EngineStatus == domain.EngineStatusOff
+
+		if current != nil {
+			last := current[len(current)-1]
+			if sample.Timestamp.Sub(last.Timestamp) > config.MaxSampleGap {
+				finish()
+			}
+		}
+
+		if current == nil {
+			if moving && !ignitionOff {
+				// A recent sample with the ignition on is where the vehicle set off from
+				if prev != nil && prev.EngineStatus != domain.EngineStatusOff && !isZeroPosition(prev) &&
+					sample.Timestamp.Sub(prev.Timestamp) <= config.StopDuration {
+					current = append(current, prev)
+				}
+				current = append(current, sample)
+				lastMoving = len(current) - 1
+			}
+			prev = sample
+			continue
+		}
+
+		current = append(current, sample)
+		switch {
+		case ignitionOff:
+			// The ignition-off sample is where the vehicle parked
+			lastMoving = len(current) - 1
+			finish()
+		case moving:
+			lastMoving = len(current) - 1
+		case sample.Timestamp.Sub(current[lastMoving].Timestamp) > config.StopDuration:
+			finish()
+		}
+		prev = sample
+	}
+
+	if current != nil {
+		last := current[len(current)-1]
+		if now.Sub(last.Timestamp) <= config.StopDuration {
+			if trip := buildTrip(current, config); trip != nil {
+				trip.Status = domain.TripStatusInProgress
+				trips = append(trips, trip)
+			} else if lastMoving >= 0 {
+				// Too short so far, but the trip may still grow
+				trips = append(trips, summarizeTrip(current, config, domain.TripStatusInProgress))
+			}
+		} else {
+			finish()
+		}
+	}
+	return trips
+}
+
+// buildTrip summarizes a completed trip, discarding it when it is below the minimum size
+func buildTrip(samples []*domain.TelematicsData, config TripDetectionConfig) *domain.Trip {
+	if len(samples) < 2 {
+		return nil
+	}
+	trip := summarizeTrip(samples, config, domain.TripStatusCompleted)
+	if trip.DistanceKm < config.MinDistanceKm || time.Duration(trip.DurationSeconds)*time.Second < config.MinDuration {
+		return nil
+	}
+	return trip
+}
+
+// summarizeTrip computes the trip metrics; each interval is attributed to the state of its first sample
+func summarizeTrip(samples []*domain.TelematicsData, config TripDetectionConfig, status string) *domain.Trip {
+	first, last := samples[0], samples[len(samples)-1]
+	trip := &domain.Trip{
+		VehicleID:       last.VehicleID,
+		Status:          status,
+		StartTime:       first.Timestamp,
+		EndTime:         last.Timestamp,
+		StartLatitude:   first.Latitude,
+		StartLongitude:  first.Longitude,
+		EndLatitude:     last.Latitude,
+		EndLongitude:    last.Longitude,
+		DurationSeconds: int(last.Timestamp.Sub(first.Timestamp) / time.Second),
+		SampleCount:     len(samples),
+	}
+
+	var gpsDistance, movingSeconds, idleSeconds float64
+	var fixed *domain.TelematicsData // last sample with a GPS fix
+	for i, sample := range samples {
+		if sample.Speed > trip.MaxSpeed {
+			trip.MaxSpeed = sample.Speed
+		}
+		if i > 0 {
+			prev := samples[i-1]
+			dt := sample.Timestamp.Sub(prev.Timestamp).Seconds()
+			if prev.Speed >= config.MovingSpeed {
+				movingSeconds += dt
+			} else if prev.EngineStatus != domain.EngineStatusOff {
+				idleSeconds += dt
+			}
+		}
+		if isZeroPosition(sample) {
+			continue
+		}
+		if fixed != nil {
+			km := geo.DistanceKm(
+				geo.Point{Latitude: fixed.Latitude, Longitude: fixed.Longitude},
+				geo.Point{Latitude: sample.Latitude, Longitude: sample.Longitude},
+			)
+			hours := sample.Timestamp.Sub(fixed.Timestamp).Hours()
+			if hours > 0 && km/hours <= config.MaxPlausibleSpeed {
+				gpsDistance += km
+			}
+		}
+		fixed = sample
+	}
+
+	trip.DistanceKm = gpsDistance
+	// The odometer is more accurate than summed GPS positions when the device reports it
+	if first.TotalDistance > 0 && last.TotalDistance >= first.TotalDistance {
+		trip.DistanceKm = last.TotalDistance - first.TotalDistance
+	}
+	trip.MovingSeconds = int(movingSeconds)
+	trip.IdleSeconds = int(idleSeconds)
+	if movingSeconds > 0 {
+		trip.AvgSpeed = trip.DistanceKm / (movingSeconds / 3600)
+		if trip.AvgSpeed > trip.MaxSpeed && trip.MaxSpeed > 0 {
+			trip.AvgSpeed = trip.MaxSpeed
+		}
+	}
+	if first.FuelLevel > 0 && last.FuelLevel > 0 && first.FuelLevel >= last.FuelLevel {
+		used := first.FuelLevel - last.FuelLevel
+		trip.FuelUsedPercent = &used
+	}
+	return trip
+}
+
+func isZeroPosition(sample *domain.TelematicsData) bool {
+	return sample.Latitude == 0 && sample.Longitude == 0
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"ton-platform/internal/domain"
+)
+
+// detectStart is the time all detector test samples are offset from
+var detectStart = time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+
+// lonStep is how far east a vehicle at 60 km/h gets in 30 s, about 0.5 km
+const lonStep = 0.0045
+
+func at(minutes float64) time.Time {
+	return detectStart.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+func sampleAt(minutes, lon, speed float64, engine string) *domain.TelematicsData {
+	return &domain.TelematicsData{
+		VehicleID:    1,
+		Latitude:     1,
+		Longitude:    lon,
+		Speed:        speed,
+		EngineStatus: engine,
+		Timestamp:    at(minutes),
+	}
+}
+
+// drive returns count samples 30 s apart of a vehicle heading east at 60 km/h
+func drive(fromMinute, lon float64, count int) []*domain.TelematicsData {
+	samples := make([]*domain.TelematicsData, count)
+	for i := range samples {
+		samples[i] = sampleAt(fromMinute+float64(i)/2, lon+float64(i)*lonStep, 60, domain.EngineStatusOn)
+	}
+	return samples
+}
+
+// idle returns count samples 30 s apart of a vehicle standing with the engine running
+func idle(fromMinute, lon float64, count int) []*domain.TelematicsData {
+	samples := make([]*domain.TelematicsData, count)
+	for i := range samples {
+		samples[i] = sampleAt(fromMinute+float64(i)/2, lon, 0, domain.EngineStatusOn)
+	}
+	return samples
+}
+
+func park(minute, lon float64) *domain.TelematicsData {
+	return sampleAt(minute, lon, 0, domain.EngineStatusOff)
+}
+
+func concat(parts ...[]*domain.TelematicsData) []*domain.TelematicsData {
+	var samples []*domain.TelematicsData
+	for _, part := range parts {
+		samples = append(samples, part...)
+	}
+	return samples
+}
+
+func TestDetectTrips(t *testing.T) {
+	type wantTrip struct {
+		start, end  float64 // minutes
+		status      string
+		idleSeconds int
+	}
+	const (
+		completed  = domain.TripStatusCompleted
+		inProgress = domain.TripStatusInProgress
+	)
+
+	tests := []struct {
+		name    string
+		samples []*domain.TelematicsData
+		lead    *domain.TelematicsData
+		now     time.Time
+		want    []wantTrip
+	}{
+		{
+			name:    "no samples",
+			samples: nil,
+			now:     at(60),
+			want:    nil,
+		},
+		{
+			name: "ignition off ends the trip",
+			samples: concat(
+				drive(0, 0, 11), []*domain.TelematicsData{park(5.5, 10*lonStep)},
+				drive(30, 10*lonStep, 11), []*domain.TelematicsData{park(35.5, 20*lonStep)},
+			),
+			now: at(120),
+			want: []wantTrip{
+				{start: 0, end: 5.5, status: completed},
+				{start: 30, end: 35.5, status: completed},
+			},
+		},
+		{
+			name: "short idle stays in the trip",
+			samples: concat(
+				drive(0, 0, 11), idle(5.5, 10*lonStep, 5),
+				drive(8, 10*lonStep, 11), []*domain.TelematicsData{park(13.5, 20*lonStep)},
+			),
+			now:  at(120),
+			want: []wantTrip{{start: 0, end: 13.5, status: completed, idleSeconds: 150}},
+		},
+		{
+			// The trip ends at the last moving sample; the next one sets off from the last idle sample
+			name: "idle beyond the stop duration ends the trip",
+			samples: concat(
+				drive(0, 0, 11), idle(5.5, 10*lonStep, 14),
+				drive(12.5, 10*lonStep, 11), []*domain.TelematicsData{park(18, 20*lonStep)},
+			),
+			now: at(120),
+			want: []wantTrip{
+				{start: 0, end: 5, status: completed},
+				{start: 12, end: 18, status: completed, idleSeconds: 30},
+			},
+		},
+		{
+			name: "sample gap ends the trip",
+			samples: concat(
+				drive(0, 0, 11),
+				drive(20, 40*lonStep, 11), []*domain.TelematicsData{park(25.5, 50*lonStep)},
+			),
+			now: at(120),
+			want: []wantTrip{
+				{start: 0, end: 5, status: completed},
+				{start: 20, end: 25.5, status: completed},
+			},
+		},
+		{
+			// The gap case again once the samples that were missing have arrived
+			name: "late samples join the trips across the gap",
+			samples: concat(
+				drive(0, 0, 11), drive(5.5, 11*lonStep, 29),
+				drive(20, 40*lonStep, 11), []*domain.TelematicsData{park(25.5, 50*lonStep)},
+			),
+			now:  at(120),
+			want: []wantTrip{{start: 0, end: 25.5, status: completed}},
+		},
+		{
+			name:    "below the minimum duration",
+			samples: concat(drive(0, 0, 2), []*domain.TelematicsData{park(0.75, lonStep)}),
+			now:     at(120),
+			want:    nil,
+		},
+		{
+			// GPS drift while manoeuvring
+			name: "below the minimum distance",
+			samples: []*domain.TelematicsData{
+				sampleAt(0, 0, 10, domain.EngineStatusOn),
+				sampleAt(0.5, 0.0005, 10, domain.EngineStatusOn),
+				sampleAt(1, 0.001, 10, domain.EngineStatusOn),
+				park(1.5, 0.001),
+			},
+			now:  at(120),
+			want: nil,
+		},
+		{
+			name:    "stationary with the engine running",
+			samples: idle(0, 0, 20),
+			now:     at(120),
+			want:    nil,
+		},
+		{
+			name:    "recent samples leave the trip in progress",
+			samples: drive(0, 0, 11),
+			now:     at(6),
+			want:    []wantTrip{{start: 0, end: 5, status: inProgress}},
+		},
+		{
+			name:    "stale samples complete the trip",
+			samples: drive(0, 0, 11),
+			now:     at(120),
+			want:    []wantTrip{{start: 0, end: 5, status: completed}},
+		},
+		{
+			name:    "recent start below the minimum is reported in progress",
+			samples: drive(0, 0, 2),
+			now:     at(1),
+			want:    []wantTrip{{start: 0, end: 0.5, status: inProgress}},
+		},
+		{
+			name:    "lead sample with the engine running starts the trip",
+			lead:    sampleAt(-1, 0, 0, domain.EngineStatusOn),
+			samples: concat(drive(0, 0, 11), []*domain.TelematicsData{park(5.5, 10*lonStep)}),
+			now:     at(120),
+			want:    []wantTrip{{start: -1, end: 5.5, status: completed, idleSeconds: 60}},
+		},
+		{
+			name:    "lead sample with the ignition off does not",
+			lead:    park(-1, 0),
+			samples: concat(drive(0, 0, 11), []*domain.TelematicsData{park(5.5, 10*lonStep)}),
+			now:     at(120),
+			want:    []wantTrip{{start: 0, end: 5.5, status: completed}},
+		},
+		{
+			name:    "lead sample before the stop duration does not",
+			lead:    sampleAt(-10, 0, 0, domain.EngineStatusOn),
+			samples: concat(drive(0, 0, 11), []*domain.TelematicsData{park(5.5, 10*lonStep)}),
+			now:     at(120),
+			want:    []wantTrip{{start: 0, end: 5.5, status: completed}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trips := detectTrips(tt.samples, tt.lead, DefaultTripDetectionConfig(), tt.now)
+			if len(trips) != len(tt.want) {
+				t.Fatalf("detectTrips() returned %d trips, want %d", len(trips), len(tt.want))
+			}
+			for i, want := range tt.want {
+				trip := trips[i]
+				if !trip.StartTime.Equal(at(want.start)) || !trip.EndTime.Equal(at(want.end)) {
+					t.Errorf("trip %d runs %v to %v, want %v to %v", i, trip.StartTime, trip.EndTime, at(want.start), at(want.end))
+				}
+				if trip.Status != want.status {
+					t.Errorf("trip %d status = %q, want %q", i, trip.Status, want.status)
+				}
+				if trip.IdleSeconds != want.idleSeconds {
+					t.Errorf("trip %d IdleSeconds = %d, want %d", i, trip.IdleSeconds, want.idleSeconds)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeTrip(t *testing.T) {
+	samples := concat(drive(0, 0, 11), []*domain.TelematicsData{park(5.5, 10*lonStep)})
+
+	t.Run("GPS distance", func(t *testing.T) {
+		trip := summarizeTrip(samples, DefaultTripDetectionConfig(), domain.TripStatusCompleted)
+		if trip.DistanceKm < 4.9 || trip.DistanceKm > 5.1 {
+			t.Errorf("DistanceKm = %v, want about 5", trip.DistanceKm)
+		}
+		if trip.MovingSeconds != 330 || trip.IdleSeconds != 0 || trip.MaxSpeed != 60 {
+			t.Errorf("moving %ds idle %ds max %v, want 330s, 0s and 60", trip.MovingSeconds, trip.IdleSeconds, trip.MaxSpeed)
+		}
+	})
+
+	t.Run("implausible jump dropped", func(t *testing.T) {
+		jumped := concat(samples[:5], []*domain.TelematicsData{sampleAt(2.5, 1, 60, domain.EngineStatusOn)}, samples[6:])
+		trip := summarizeTrip(jumped, DefaultTripDetectionConfig(), domain.TripStatusCompleted)
+		// The legs to and from the jumped sample are both dropped
+		if trip.DistanceKm < 3.9 || trip.DistanceKm > 4.1 {
+			t.Errorf("DistanceKm = %v, want about 4", trip.DistanceKm)
+		}
+	})
+
+	t.Run("odometer preferred", func(t *testing.T) {
+		withOdometer := make([]*domain.TelematicsData, len(samples))
+		for i, sample := range samples {
+			copied := *sample
+			copied.TotalDistance = 1000 + float64(i)*0.6
+			withOdometer[i] = &copied
+		}
+		trip := summarizeTrip(withOdometer, DefaultTripDetectionConfig(), domain.TripStatusCompleted)
+		if want := 11 * 0.6; trip.DistanceKm < want-1e-9 || trip.DistanceKm > want+1e-9 {
+			t.Errorf("DistanceKm = %v, want %v from the odometer", trip.DistanceKm, want)
+		}
+	})
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/pkg/geo"
+)
+
+// Trip errors
+var (
+	ErrTripNotFound          = errors.New("trip not found")
+	ErrInvalidTimeRange      = errors.New("invalid time range")
+	ErrTripRebuildInProgress = errors.New("a trip rebuild is already running")
+)
+
+const (
+	// tripProcessInterval is how often vehicles with new samples are re-segmented
+	tripProcessInterval = 30 * time.Second
+	// tripRebuildChunk bounds the samples loaded at once during a rebuild
+	tripRebuildChunk = 24 * time.Hour
+	// maxTripRebuildRange and maxRouteRange bound single requests
+	maxTripRebuildRange = 92 * 24 * time.Hour
+	maxRouteRange       = 7 * 24 * time.Hour
+	// defaultRouteTolerance is the route simplification tolerance in meters
+	defaultRouteTolerance = 10.0
+)
+
+// TripList represents a page of trips
+type TripList struct {
+	Trips []*domain.Trip `json:"trips"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+// Route is a simplified route of a vehicle
+type Route struct {
+	VehicleID     uint                 `json:"vehicle_id"`
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	Tolerance     float64              `json:"tolerance_meters"`
+	OriginalCount int                  `json:"original_count"`
+	Points        []*domain.RoutePoint `json:"points"`
+}
+
+// RebuildTripsRequest selects the history to re-segment. Without a vehicle ID all vehicles with samples are rebuilt.
+type RebuildTripsRequest struct {
+	VehicleID *uint     `json:"vehicle_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+}
+
+// RebuildStarted describes an accepted rebuild
+type RebuildStarted struct {
+	Vehicles int       `json:"vehicles"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+}
+
+// TripListener is notified whenever the trips of a vehicle are replaced. samples are the samples the
+// trips were segmented from, ordered by timestamp; they cover every trip. Listeners run on the
+// segmentation worker or rebuild goroutine, after the trips are stored.
+type TripListener interface {
+	HandleTrips(vehicleID uint, trips []*domain.Trip, samples []*domain.TelematicsData)
+}
+
+// TripService segments telematics samples into trips and serves trip and route history.
+// Ingested samples queue their vehicle for reprocessing; a background worker re-segments the
+// affected window, so late and out-of-order samples are folded into the right trip. The queue
+// is stored, so the worker only runs in the API server while any process may ingest.
+type TripService struct {
+	tripRepo       interfaces.TripRepository
+	telematicsRepo interfaces.TelematicsRepository
+	vehicleRepo    interfaces.VehicleRepository
+	config         TripDetectionConfig
+	logger         *logrus.Logger
+	listeners      []TripListener
+
+	mu         sync.Mutex
+	rebuilding bool
+
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+// NewTripService creates a new trip service
+func NewTripService(
+	tripRepo interfaces.TripRepository,
+	telematicsRepo interfaces.TelematicsRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	logger *logrus.Logger,
+) *TripService {
+	return &TripService{
+		tripRepo:       tripRepo,
+		telematicsRepo: telematicsRepo,
+		vehicleRepo:    vehicleRepo,
+		config:         DefaultTripDetectionConfig(),
+		logger:         logger,
+		stop:           make(chan struct{}),
+	}
+}
+
+// AddListener registers a listener for stored trips. It must be called before Start.
+func (s *TripService) AddListener(listener TripListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// Start runs the incremental segmentation worker
+func (s *TripService) Start() {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		ticker := time.NewTicker(tripProcessInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.processDirty()
+			case <-s.stop:
+				// Queued vehicles stay stored for the next start
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the worker and waits for a running rebuild
+func (s *TripService) Close() {
+	close(s.stop)
+	s.workers.Wait()
+}
+
+// HandleTelematics queues vehicles with new samples for segmentation; it is registered as an ingestion listener
+func (s *TripService) HandleTelematics(samples []*domain.TelematicsData) {
+	earliest := make(map[uint]time.Time)
+	for _, sample := range samples {
+		if t, ok := earliest[sample.VehicleID]; !ok || sample.Timestamp.Before(t) {
+			earliest[sample.VehicleID] = sample.Timestamp
+		}
+	}
+	s.queue(earliest)
+}
+
+// queue stores vehicles for the segmentation worker
+func (s *TripService) queue(earliest map[uint]time.Time) {
+	if err := s.telematicsRepo.QueueReprocess(domain.ReprocessConsumerTrips, earliest); err != nil {
+		s.logger.WithError(err).WithField("vehicles", len(earliest)).Error("Failed to queue trip segmentation")
+	}
+}
+
+// processDirty re-segments every vehicle queued since the last run
+func (s *TripService) processDirty() {
+	dirty, err := s.telematicsRepo.TakeReprocess(domain.ReprocessConsumerTrips)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to take queued trip segmentation")
+		return
+	}
+
+	now := time.Now().UTC()
+	for vehicleID, earliest := range dirty {
+		if _, err := s.Reprocess(vehicleID, earliest, now); err != nil {
+			s.logger.WithError(err).WithField("vehicle_id", vehicleID).Error("Failed to segment trips")
+			// Retry on the next run
+			s.queue(map[uint]time.Time{vehicleID: earliest})
+		}
+	}
+}
+
+// Reprocess re-segments the samples of a vehicle in [from, to] and replaces the stored trips there.
+// The window grows to cover trips it cuts through, so they are rebuilt whole. The vehicle's trips stay
+// locked from reading them to storing the new ones, so the worker and a rebuild never replace trips
+// from a stale view. It returns the number of trips stored.
+func (s *TripService) Reprocess(vehicleID uint, from, to time.Time) (int, error) {
+	var trips []*domain.Trip
+	var samples []*domain.TelematicsData
+	var lead *domain.TelematicsData
+	err := s.tripRepo.Resegment(vehicleID, from.Add(-s.config.MaxSampleGap), to.Add(s.config.MaxSampleGap),
+		func(existing []*domain.Trip) (*interfaces.TripSegment, error) {
+			for _, trip := range existing {
+				if trip.StartTime.Before(from) {
+					from = trip.StartTime
+				}
+				if trip.EndTime.After(to) {
+					to = trip.EndTime
+				}
+			}
+
+			var err error
+			samples, err = s.telematicsRepo.GetByVehicle(vehicleID, from, to)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get telematics samples: %w", err)
+			}
+			lead, err = s.telematicsRepo.GetLastBefore(vehicleID, from)
+			if err != nil {
+				if !isNotFound(err) {
+					return nil, fmt.Errorf("failed to get preceding sample: %w", err)
+				}
+				lead = nil
+			}
+
+			trips = detectTrips(samples, lead, s.config, time.Now().UTC())
+			// A trip may start at the preceding sample
+			if len(trips) > 0 && trips[0].StartTime.Before(from) {
+				from = trips[0].StartTime
+			}
+			return &interfaces.TripSegment{From: from, To: to, Trips: trips}, nil
+		})
+	if err != nil {
+		return 0, fmt.Errorf("failed to segment trips: %w", err)
+	}
+
+	// Listeners take the trip lock themselves, so they run once the trips are stored
+	if len(s.listeners) > 0 && len(trips) > 0 {
+		if lead != nil {
+			samples = append([]*domain.TelematicsData{lead}, samples...)
+		}
+		for _, listener := range s.listeners {
+			s.notify(listener, vehicleID, trips, samples)
+		}
+	}
+	return len(trips), nil
+}
+
+// notify calls one listener, isolating segmentation from listener panics
+func (s *TripService) notify(listener TripListener, vehicleID uint, trips []*domain.Trip, samples []*domain.TelematicsData) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.WithField("panic", r).Errorf("Trip listener %T panicked", listener)
+		}
+	}()
+	listener.HandleTrips(vehicleID, trips, samples)
+}
+
+// Rebuild re-segments historical samples in the background, one day at a time
+func (s *TripService) Rebuild(req *RebuildTripsRequest) (*RebuildStarted, error) {
+	if !req.To.After(req.From) || req.To.Sub(req.From) > maxTripRebuildRange {
+		return nil, fmt.Errorf("%w: to must be after from and the range at most %d days",
+			ErrInvalidTimeRange, int(maxTripRebuildRange.Hours()/24))
+	}
+
+	var vehicleIDs []uint
+	if req.VehicleID != nil {
+		if _, err := s.vehicleRepo.GetByID(*req.VehicleID); err != nil {
+			if isNotFound(err) {
+				return nil, ErrVehicleNotFound
+			}
+			return nil, fmt.Errorf("failed to get vehicle: %w", err)
+		}
+		vehicleIDs = []uint{*req.VehicleID}
+	} else {
+		ids, err := s.telematicsRepo.GetVehicleIDsInRange(req.From, req.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get vehicles with telematics: %w", err)
+		}
+		vehicleIDs = ids
+	}
+
+	s.mu.Lock()
+	if s.rebuilding {
+		s.mu.Unlock()
+		return nil, ErrTripRebuildInProgress
+	}
+	s.rebuilding = true
+	s.mu.Unlock()
+
+	from, to := req.From.UTC(), req.To.UTC()
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		defer func() {
+			s.mu.Lock()
+			s.rebuilding = false
+			s.mu.Unlock()
+		}()
+		s.rebuild(vehicleIDs, from, to)
+	}()
+
+	return &RebuildStarted{Vehicles: len(vehicleIDs), From: from, To: to}, nil
+}
+
+func (s *TripService) rebuild(vehicleIDs []uint, from, to time.Time) {
+	started := time.Now()
+	total := 0
+	for _, vehicleID := range vehicleIDs {
+		for chunkStart := from; chunkStart.Before(to); chunkStart = chunkStart.Add(tripRebuildChunk) {
+			select {
+			case <-s.stop:
+				s.logger.Warn("Trip rebuild interrupted by shutdown")
+				return
+			default:
+			}
+
+			chunkEnd := chunkStart.Add(tripRebuildChunk)
+			if chunkEnd.After(to) {
+				chunkEnd = to
+			}
+			// A trip cut at the chunk end is extended by the next chunk, which reprocesses it whole
+			count, err := s.Reprocess(vehicleID, chunkStart, chunkEnd)
+			if err != nil {
+				s.logger.WithError(err).WithFields(logrus.Fields{
+					"vehicle_id": vehicleID,
+					"from":       chunkStart,
+				}).Error("Failed to rebuild trips")
+				continue
+			}
+			total += count
+		}
+	}
+	s.logger.WithFields(logrus.Fields{
+		"vehicles": len(vehicleIDs),
+		"trips":    total,
+		"from":     from,
+		"to":       to,
+		"duration": time.Since(started).String(),
+	}).Info("Trip rebuild completed")
+}
+
+// ListTrips lists the trips of a vehicle starting in [from, to], newest first
+func (s *TripService) ListTrips(viewer Viewer, vehicleID uint, from, to time.Time, page, limit, offset int) (*TripList, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", ErrInvalidTimeRange)
+	}
+
+	trips, total, err := s.tripRepo.ListByVehicle(vehicleID, from, to, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trips: %w", err)
+	}
+	return &TripList{Trips: trips, Total: total, Page: page, Limit: limit}, nil
+}
+
+// GetTrip returns one trip of a vehicle
+func (s *TripService) GetTrip(viewer Viewer, vehicleID, tripID uint) (*domain.Trip, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+
+	trip, err := s.tripRepo.GetByID(tripID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrTripNotFound
+		}
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+	if trip.VehicleID != vehicleID {
+		return nil, ErrTripNotFound
+	}
+	return trip, nil
+}
+
+// GetTripRoute returns the simplified route driven during a trip
+func (s *TripService) GetTripRoute(viewer Viewer, vehicleID, tripID uint, tolerance float64) (*Route, error) {
+	trip, err := s.GetTrip(viewer, vehicleID, tripID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildRoute(vehicleID, trip.StartTime, trip.EndTime, tolerance)
+}
+
+// GetRoute returns the simplified route of a vehicle in [from, to]
+func (s *TripService) GetRoute(viewer Viewer, vehicleID uint, from, to time.Time, tolerance float64) (*Route, error) {
+	if err := checkVehicleAccess(s.vehicleRepo, viewer, vehicleID); err != nil {
+		return nil, err
+	}
+	if !to.After(from) || to.Sub(from) > maxRouteRange {
+		return nil, fmt.Errorf("%w: to must be after from and the range at most %d days",
+			ErrInvalidTimeRange, int(maxRouteRange.Hours()/24))
+	}
+	return s.buildRoute(vehicleID, from, to, tolerance)
+}
+
+func (s *TripService) buildRoute(vehicleID uint, from, to time.Time, tolerance float64) (*Route, error) {
+	if tolerance < 0 {
+		tolerance = defaultRouteTolerance
+	}
+
+	samples, err := s.telematicsRepo.GetByVehicle(vehicleID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get telematics samples: %w", err)
+	}
+
+	// Samples without a GPS fix would draw lines to 0,0
+	fixed := make([]*domain.TelematicsData, 0, len(samples))
+	points := make([]geo.Point, 0, len(samples))
+	for _, sample := range samples {
+		point := geo.Point{Latitude: sample.Latitude, Longitude: sample.Longitude}
+		if point.IsZero() {
+			continue
+		}
+		fixed = append(fixed, sample)
+		points = append(points, point)
+	}
+
+	indices := geo.SimplifyIndices(points, tolerance)
+	route := &Route{
+		VehicleID:     vehicleID,
+		From:          from,
+		To:            to,
+		Tolerance:     tolerance,
+		OriginalCount: len(points),
+		Points:        make([]*domain.RoutePoint, len(indices)),
+	}
+	for i, index := range indices {
+		sample := fixed[index]
+		route.Points[i] = &domain.RoutePoint{
+			Latitude:  sample.Latitude,
+			Longitude: sample.Longitude,
+			Timestamp: sample.Timestamp,
+			Speed:     sample.Speed,
+		}
+	}
+	return route, nil
+}
@@ -0,0 +1,598 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+	"ton-platform/pkg/xlsx"
+)
+
+// Vehicle import errors
+var (
+	ErrUnsupportedFileFormat = errors.New("unsupported file format, expected csv or xlsx")
+	ErrEmptyImportFile       = errors.New("import file has no data rows")
+	ErrInvalidColumnMapping  = errors.New("invalid column mapping")
+	ErrImportValidation      = errors.New("import file contains invalid rows")
+)
+
+// Supported tabular file formats
+const (
+	FileFormatCSV  = "csv"
+	FileFormatXLSX = "xlsx"
+)
+
+const (
+	maxImportRows    = 10000
+	exportBatchSize  = 500
+	importSampleRows = 5
+	minVehicleYear   = 1900
+)
+
+// vehicleImportField describes a vehicle attribute that can be mapped from an import column
+type vehicleImportField struct {
+	Name      string
+	Required  bool
+	MaxLength int
+	Aliases   []string
+}
+
+// vehicleImportFields lists importable fields in export column order
+var vehicleImportFields = []vehicleImportField{
+	{Name: "plate_number", Required: true, MaxLength: 20, Aliases: []string{"plate", "plate_no", "license_plate", "registration", "registration_number", "nopol"}},
+	{Name: "vin", MaxLength: 17, Aliases: []string{"vin_number", "chassis", "chassis_number", "no_rangka"}},
+	{Name: "make", Required: true, MaxLength: 50, Aliases: []string{"brand", "manufacturer", "merk"}},
+	{Name: "model", Required: true, MaxLength: 50, Aliases: []string{"model_name", "tipe"}},
+	{Name: "year", Required: true, Aliases: []string{"model_year", "production_year", "tahun"}},
+	{Name: "color", MaxLength: 30, Aliases: []string{"colour", "warna"}},
+	{Name: "type", Required: true, Aliases: []string{"vehicle_type", "body_type"}},
+	{Name: "category", Required: true, Aliases: []string{"vehicle_category", "usage"}},
+	{Name: "status", Aliases: []string{"vehicle_status", "state"}},
+	{Name: "odometer", Aliases: []string{"mileage", "km", "odometer_km"}},
+	{Name: "location", MaxLength: 100, Aliases: []string{"branch", "site", "lokasi"}},
+	{Name: "assigned_to", MaxLength: 100, Aliases: []string{"driver", "assignee"}},
+	{Name: "notes", Aliases: []string{"note", "remarks", "comments"}},
+}
+
+var (
+	validVehicleTypes      = []string{domain.TypeSedan, domain.TypeSUV, domain.TypeTruck, domain.TypeVan, domain.TypeMotorcycle, domain.TypeBus}
+	validVehicleCategories = []string{domain.CategoryRental, domain.CategoryWorkshop, domain.CategoryCustomer, domain.CategoryCompany}
+	validVehicleStatuses   = []string{domain.StatusAvailable, domain.StatusRented, domain.StatusInMaintenance, domain.StatusOutOfService, domain.StatusReserved}
+)
+
+// VehicleImportService handles bulk vehicle import and export
+type VehicleImportService struct {
+	vehicleRepo interfaces.VehicleRepository
+	logger      *logrus.Logger
+}
+
+// VehicleImportField describes a target field for the column mapping step
+type VehicleImportField struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+}
+
+// VehicleImportPreview is the result of the column mapping step
+type VehicleImportPreview struct {
+	Format           string               `json:"format"`
+	Headers          []string             `json:"headers"`
+	Fields           []VehicleImportField `json:"fields"`
+	SuggestedMapping map[string]string    `json:"suggested_mapping"` // field -> column header
+	SampleRows       [][]string           `json:"sample_rows"`
+	TotalRows        int                  `json:"total_rows"`
+}
+
+// VehicleImportRowError describes one validation problem in the import file
+type VehicleImportRowError struct {
+	Row     int    `json:"row"` // 1-based row number in the file, header included
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+// VehicleImportReport summarizes a dry run or an applied import
+type VehicleImportReport struct {
+	DryRun      bool                    `json:"dry_run"`
+	Applied     bool                    `json:"applied"`
+	TotalRows   int                     `json:"total_rows"`
+	ValidRows   int                     `json:"valid_rows"`
+	InvalidRows int                     `json:"invalid_rows"`
+	Imported    int                     `json:"imported"`
+	Mapping     map[string]string       `json:"mapping"`
+	Errors      []VehicleImportRowError `json:"errors"`
+}
+
+// NewVehicleImportService creates a new vehicle import service
+func NewVehicleImportService(vehicleRepo interfaces.VehicleRepository, logger *logrus.Logger) *VehicleImportService {
+	return &VehicleImportService{
+		vehicleRepo: vehicleRepo,
+		logger:      logger,
+	}
+}
+
+// DetectFileFormat derives the file format from an explicit format value or the file name
+func DetectFileFormat(format, filename string) (string, error) {
+	if format == "" {
+		if idx := strings.LastIndex(filename, "."); idx >= 0 {
+			format = filename[idx+1:]
+		}
+	}
+	switch strings.ToLower(format) {
+	case FileFormatCSV:
+		return FileFormatCSV, nil
+	case FileFormatXLSX:
+		return FileFormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFileFormat
+	}
+}
+
+// Preview reads the file headers and proposes a column mapping
+func (s *VehicleImportService) Preview(format string, file io.ReaderAt, size int64) (*VehicleImportPreview, error) {
+	headers, rows, err := readTable(format, file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]VehicleImportField, len(vehicleImportFields))
+	for i, field := range vehicleImportFields {
+		fields[i] = VehicleImportField{Name: field.Name, Required: field.Required}
+	}
+
+	samples := rows
+	if len(samples) > importSampleRows {
+		samples = samples[:importSampleRows]
+	}
+
+	return &VehicleImportPreview{
+		Format:           format,
+		Headers:          headers,
+		Fields:           fields,
+		SuggestedMapping: suggestColumnMapping(headers),
+		SampleRows:       samples,
+		TotalRows:        len(rows),
+	}, nil
+}
+
+// Import validates the file using the column mapping and, unless dryRun is set, stores all
+// vehicles in a single transaction. Nothing is stored when any row is invalid.
+func (s *VehicleImportService) Import(format string, file io.ReaderAt, size int64, mapping map[string]string, dryRun bool) (*VehicleImportReport, error) {
+	headers, rows, err := readTable(format, file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mapping) == 0 {
+		mapping = suggestColumnMapping(headers)
+	}
+	columns, err := resolveColumnMapping(headers, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	vehicles, rowErrors := s.parseRows(rows, columns)
+	if err := s.checkExistingVehicles(vehicles, &rowErrors); err != nil {
+		return nil, fmt.Errorf("failed to check existing vehicles: %w", err)
+	}
+
+	invalid := make(map[int]bool)
+	for _, rowErr := range rowErrors {
+		invalid[rowErr.Row] = true
+	}
+	validRows := 0
+	for _, row := range vehicles {
+		if !invalid[row.row] {
+			validRows++
+		}
+	}
+
+	report := &VehicleImportReport{
+		DryRun:      dryRun,
+		TotalRows:   len(rows),
+		InvalidRows: len(invalid),
+		ValidRows:   validRows,
+		Mapping:     mapping,
+		Errors:      rowErrors,
+	}
+
+	if dryRun {
+		return report, nil
+	}
+	if len(rowErrors) > 0 {
+		return report, ErrImportValidation
+	}
+
+	batch := make([]*domain.Vehicle, 0, len(vehicles))
+	for _, row := range vehicles {
+		batch = append(batch, row.vehicle)
+	}
+	if err := s.vehicleRepo.CreateBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to import vehicles: %w", err)
+	}
+
+	report.Applied = true
+	report.Imported = len(batch)
+
+	s.logger.WithFields(logrus.Fields{
+		"format":   format,
+		"imported": report.Imported,
+	}).Info("Vehicles imported")
+
+	return report, nil
+}
+
+// Export streams vehicles matching the filter to w in the given format
+func (s *VehicleImportService) Export(w io.Writer, format string, filter interfaces.VehicleFilter) error {
+	header := make([]string, len(vehicleImportFields))
+	for i, field := range vehicleImportFields {
+		header[i] = field.Name
+	}
+	header = append(header, "last_service_date", "next_service_date", "created_at")
+
+	var writeRow func([]string) error
+	var flush func() error
+	var finish func() error
+
+	switch format {
+	case FileFormatCSV:
+		cw := csv.NewWriter(w)
+		writeRow = cw.Write
+		flush = func() error { cw.Flush(); return cw.Error() }
+		finish = flush
+	case FileFormatXLSX:
+		xw, err := xlsx.NewWriter(w, "Vehicles")
+		if err != nil {
+			return err
+		}
+		writeRow = xw.WriteRow
+		flush = xw.Flush
+		finish = xw.Close
+	default:
+		return ErrUnsupportedFileFormat
+	}
+
+	if err := writeRow(header); err != nil {
+		return err
+	}
+
+	err := s.vehicleRepo.FindInBatches(filter, exportBatchSize, func(vehicles []*domain.Vehicle) error {
+		for _, vehicle := range vehicles {
+			if err := writeRow(vehicleExportRow(vehicle)); err != nil {
+				return err
+			}
+		}
+		return flush()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export vehicles: %w", err)
+	}
+
+	return finish()
+}
+
+// parsedVehicleRow keeps a parsed vehicle together with its position in the file
+type parsedVehicleRow struct {
+	row     int
+	vehicle *domain.Vehicle
+}
+
+// parseRows converts data rows into vehicles, collecting per-row validation errors and
+// duplicates within the file itself
+func (s *VehicleImportService) parseRows(rows [][]string, columns map[string]int) ([]parsedVehicleRow, []VehicleImportRowError) {
+	var parsed []parsedVehicleRow
+	var rowErrors []VehicleImportRowError
+
+	seenPlates := make(map[string]int)
+	seenVINs := make(map[string]int)
+
+	for i, row := range rows {
+		rowNumber := i + 2 // header is row 1
+		values := make(map[string]string, len(vehicleImportFields))
+		for _, field := range vehicleImportFields {
+			if col, ok := columns[field.Name]; ok && col < len(row) {
+				values[field.Name] = strings.TrimSpace(row[col])
+			}
+		}
+
+		if isBlankRow(values) {
+			continue
+		}
+
+		addError := func(field, message string) {
+			rowErrors = append(rowErrors, VehicleImportRowError{
+				Row: rowNumber, Field: field, Value: values[field], Message: message,
+			})
+		}
+
+		valid := true
+		for _, field := range vehicleImportFields {
+			value := values[field.Name]
+			if field.Required && value == "" {
+				addError(field.Name, "value is required")
+				valid = false
+			}
+			if field.MaxLength > 0 && len(value) > field.MaxLength {
+				addError(field.Name, fmt.Sprintf("must be at most %d characters", field.MaxLength))
+				valid = false
+			}
+		}
+
+		vehicle := &domain.Vehicle{
+			PlateNumber: strings.ToUpper(values["plate_number"]),
+			VIN:         strings.ToUpper(values["vin"]),
+			Make:        values["make"],
+			Model:       values["model"],
+			Color:       values["color"],
+			Type:        normalizeEnumValue(values["type"]),
+			Category:    normalizeEnumValue(values["category"]),
+			Status:      normalizeEnumValue(values["status"]),
+			Location:    values["location"],
+			AssignedTo:  values["assigned_to"],
+			Notes:       values["notes"],
+		}
+		if vehicle.Status == "" {
+			vehicle.Status = domain.StatusAvailable
+		}
+
+		if values["year"] != "" {
+			year, err := parseWholeNumber(values["year"])
+			if err != nil || year < minVehicleYear || year > time.Now().Year()+1 {
+				addError("year", fmt.Sprintf("must be a year between %d and %d", minVehicleYear, time.Now().Year()+1))
+				valid = false
+			}
+			vehicle.Year = year
+		}
+		if values["odometer"] != "" {
+			odometer, err := parseWholeNumber(values["odometer"])
+			if err != nil || odometer < 0 {
+				addError("odometer", "must be a non-negative whole number")
+				valid = false
+			}
+			vehicle.Odometer = odometer
+		}
+
+		if vehicle.Type != "" && !containsString(validVehicleTypes, vehicle.Type) {
+			addError("type", "must be one of: "+strings.Join(validVehicleTypes, ", "))
+			valid = false
+		}
+		if vehicle.Category != "" && !containsString(validVehicleCategories, vehicle.Category) {
+			addError("category", "must be one of: "+strings.Join(validVehicleCategories, ", "))
+			valid = false
+		}
+		if !containsString(validVehicleStatuses, vehicle.Status) {
+			addError("status", "must be one of: "+strings.Join(validVehicleStatuses, ", "))
+			valid = false
+		}
+
+		if vehicle.PlateNumber != "" {
+			if first, ok := seenPlates[vehicle.PlateNumber]; ok {
+				addError("plate_number", fmt.Sprintf("duplicate plate number, first seen in row %d", first))
+				valid = false
+			} else {
+				seenPlates[vehicle.PlateNumber] = rowNumber
+			}
+		}
+		if vehicle.VIN != "" {
+			if first, ok := seenVINs[vehicle.VIN]; ok {
+				addError("vin", fmt.Sprintf("duplicate VIN, first seen in row %d", first))
+				valid = false
+			} else {
+				seenVINs[vehicle.VIN] = rowNumber
+			}
+		}
+
+		if valid {
+			parsed = append(parsed, parsedVehicleRow{row: rowNumber, vehicle: vehicle})
+		}
+	}
+
+	return parsed, rowErrors
+}
+
+// checkExistingVehicles reports rows whose plate number or VIN is already registered
+func (s *VehicleImportService) checkExistingVehicles(rows []parsedVehicleRow, rowErrors *[]VehicleImportRowError) error {
+	var plates, vins []string
+	for _, row := range rows {
+		plates = append(plates, row.vehicle.PlateNumber)
+		if row.vehicle.VIN != "" {
+			vins = append(vins, row.vehicle.VIN)
+		}
+	}
+
+	existingPlates, err := s.vehicleRepo.GetExistingPlateNumbers(plates)
+	if err != nil {
+		return err
+	}
+	existingVINs, err := s.vehicleRepo.GetExistingVINs(vins)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if containsString(existingPlates, row.vehicle.PlateNumber) {
+			*rowErrors = append(*rowErrors, VehicleImportRowError{
+				Row: row.row, Field: "plate_number", Value: row.vehicle.PlateNumber,
+				Message: "plate number is already registered",
+			})
+		}
+		if row.vehicle.VIN != "" && containsString(existingVINs, row.vehicle.VIN) {
+			*rowErrors = append(*rowErrors, VehicleImportRowError{
+				Row: row.row, Field: "vin", Value: row.vehicle.VIN,
+				Message: "VIN is already registered",
+			})
+		}
+	}
+	return nil
+}
+
+// readTable reads a CSV or XLSX file into its header row and data rows
+func readTable(format string, file io.ReaderAt, size int64) ([]string, [][]string, error) {
+	var records [][]string
+	var err error
+
+	switch format {
+	case FileFormatCSV:
+		reader := csv.NewReader(io.NewSectionReader(file, 0, size))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err = reader.ReadAll()
+		if err != nil {
+			return nil, nil, fmt.Errorf("validation failed: invalid CSV file: %w", err)
+		}
+	case FileFormatXLSX:
+		records, err = xlsx.ReadFirstSheet(file, size, maxImportRows+1)
+		if errors.Is(err, xlsx.ErrTooManyRows) {
+			return nil, nil, fmt.Errorf("validation failed: import is limited to %d rows", maxImportRows)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("validation failed: %w", err)
+		}
+	default:
+		return nil, nil, ErrUnsupportedFileFormat
+	}
+
+	if len(records) < 2 {
+		return nil, nil, ErrEmptyImportFile
+	}
+	if len(records)-1 > maxImportRows {
+		return nil, nil, fmt.Errorf("validation failed: import is limited to %d rows", maxImportRows)
+	}
+
+	headers := make([]string, len(records[0]))
+	for i, header := range records[0] {
+		// Spreadsheet tools often prepend a byte order mark to CSV exports
+		headers[i] = strings.TrimSpace(strings.TrimPrefix(header, "\ufeff"))
+	}
+	return headers, records[1:], nil
+}
+
+// suggestColumnMapping matches file headers to vehicle fields by name or known alias
+func suggestColumnMapping(headers []string) map[string]string {
+	mapping := make(map[string]string)
+	for _, field := range vehicleImportFields {
+		candidates := append([]string{field.Name}, field.Aliases...)
+		for _, header := range headers {
+			if containsString(candidates, normalizeHeader(header)) {
+				mapping[field.Name] = header
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+// resolveColumnMapping converts a field -> header mapping into field -> column index
+func resolveColumnMapping(headers []string, mapping map[string]string) (map[string]int, error) {
+	columns := make(map[string]int, len(mapping))
+	for fieldName, header := range mapping {
+		if header == "" {
+			continue
+		}
+		known := false
+		for _, field := range vehicleImportFields {
+			if field.Name == fieldName {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidColumnMapping, fieldName)
+		}
+
+		col := -1
+		for i, h := range headers {
+			if strings.EqualFold(h, header) {
+				col = i
+				break
+			}
+		}
+		if col < 0 {
+			return nil, fmt.Errorf("%w: column %q not found in file", ErrInvalidColumnMapping, header)
+		}
+		columns[fieldName] = col
+	}
+
+	for _, field := range vehicleImportFields {
+		if _, ok := columns[field.Name]; field.Required && !ok {
+			return nil, fmt.Errorf("%w: required field %q is not mapped", ErrInvalidColumnMapping, field.Name)
+		}
+	}
+	return columns, nil
+}
+
+// vehicleExportRow renders a vehicle in export column order
+func vehicleExportRow(vehicle *domain.Vehicle) []string {
+	return []string{
+		vehicle.PlateNumber,
+		vehicle.VIN,
+		vehicle.Make,
+		vehicle.Model,
+		strconv.Itoa(vehicle.Year),
+		vehicle.Color,
+		vehicle.Type,
+		vehicle.Category,
+		vehicle.Status,
+		strconv.Itoa(vehicle.Odometer),
+		vehicle.Location,
+		vehicle.AssignedTo,
+		vehicle.Notes,
+		formatDate(vehicle.LastService),
+		formatDate(vehicle.NextService),
+		vehicle.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func normalizeHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	return strings.NewReplacer(" ", "_", "-", "_", ".", "").Replace(header)
+}
+
+func normalizeEnumValue(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(value)
+}
+
+// parseWholeNumber accepts integers and whole-number decimals such as "2021.0" produced by spreadsheets
+func parseWholeNumber(value string) (int, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f != float64(int(f)) {
+		return 0, fmt.Errorf("not a whole number: %s", value)
+	}
+	return int(f), nil
+}
+
+func isBlankRow(values map[string]string) bool {
+	for _, value := range values {
+		if value != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+const (
+	// vehicleStatesKey is the Redis hash of latest states, keyed by vehicle ID
+	vehicleStatesKey = "vehicle:latest_states"
+	// vehicleStatesWarmKey marks that the hash holds every vehicle, not only those ingested since Redis started
+	vehicleStatesWarmKey = "vehicle:latest_states:warm"
+	// vehicleOnlineThreshold is how recent the last sample must be for a vehicle to count as online
+	vehicleOnlineThreshold = 5 * time.Minute
+	redisOperationTimeout  = 2 * time.Second
+)
+
+// upsertVehicleStatesScript writes states unless the cached state comes from a newer sample.
+// ARGV holds vehicle ID / state JSON pairs.
+var upsertVehicleStatesScript = redis.NewScript(`
+for i = 1, #ARGV, 2 do
+	local current = redis.call('HGET', KEYS[1], ARGV[i])
+	if not current or cjson.decode(current).sample_at_ms < cjson.decode(ARGV[i + 1]).sample_at_ms then
+		redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 1])
+	end
+end
+return 1
+`)
+
+// cachedVehicleState is the Redis representation of a latest state
+type cachedVehicleState struct {
+	*domain.VehicleLatestState
+	SampleAtMs int64 `json:"sample_at_ms"`
+}
+
+// FleetStatusFilter narrows the fleet-wide status listing
+type FleetStatusFilter struct {
+	Online *bool
+}
+
+// FleetStatus is the realtime status of every vehicle a user can see
+type FleetStatus struct {
+	Vehicles    []*domain.VehicleRealtimeStatus `json:"vehicles"`
+	Total       int                             `json:"total"`
+	OnlineCount int                             `json:"online_count"`
+	GeneratedAt time.Time                       `json:"generated_at"`
+}
+
+// VehicleStatusService maintains the latest-state projection on ingest and serves realtime status.
+// Redis holds the hot projection; Postgres is written alongside and used when Redis is unavailable.
+type VehicleStatusService struct {
+	statusRepo  interfaces.VehicleStatusRepository
+	dtcRepo     interfaces.DTCRepository
+	vehicleRepo interfaces.VehicleRepository
+	redis       *redis.Client
+	logger      *logrus.Logger
+}
+
+// NewVehicleStatusService creates a new vehicle status service. redisClient may be nil.
+func NewVehicleStatusService(
+	statusRepo interfaces.VehicleStatusRepository,
+	dtcRepo interfaces.DTCRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	redisClient *redis.Client,
+	logger *logrus.Logger,
+) *VehicleStatusService {
+	return &VehicleStatusService{
+		statusRepo:  statusRepo,
+		dtcRepo:     dtcRepo,
+		vehicleRepo: vehicleRepo,
+		redis:       redisClient,
+		logger:      logger,
+	}
+}
+
+// HandleTelematics projects the newest sample of each vehicle; it is registered as an ingestion listener
+func (s *VehicleStatusService) HandleTelematics(samples []*domain.TelematicsData) {
+	latest := make(map[uint]*domain.TelematicsData)
+	for _, sample := range samples {
+		if current, ok := latest[sample.VehicleID]; !ok || sample.Timestamp.After(current.Timestamp) {
+			latest[sample.VehicleID] = sample
+		}
+	}
+
+	states := make([]*domain.VehicleLatestState, 0, len(latest))
+	for _, sample := range latest {
+		states = append(states, domain.NewVehicleLatestState(sample))
+	}
+
+	if err := s.statusRepo.UpsertLatestStates(states); err != nil {
+		s.logger.WithError(err).Error("Failed to store vehicle latest states")
+	}
+	if err := s.cacheStates(states); err != nil {
+		s.logger.WithError(err).Warn("Failed to cache vehicle latest states")
+	}
+}
+
+// GetStatus returns the realtime status of one vehicle
+func (s *VehicleStatusService) GetStatus(viewer Viewer, vehicleID uint) (*domain.VehicleRealtimeStatus, error) {
+	vehicle, err := s.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+	if viewer.IsDriver() {
+		assigned, err := assignedVehicleIDs(s.vehicleRepo, viewer)
+		if err != nil {
+			return nil, err
+		}
+		if !assigned[vehicleID] {
+			return nil, ErrVehicleAccessDenied
+		}
+	}
+
+	state, err := s.getState(vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	dtcCounts, err := s.dtcRepo.CountActiveByVehicles([]uint{vehicleID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active DTCs: %w", err)
+	}
+
+	return buildRealtimeStatus(vehicle, state, dtcCounts[vehicleID], time.Now().UTC()), nil
+}
+
+// ListStatuses returns the realtime status of every vehicle with telematics the viewer can see
+func (s *VehicleStatusService) ListStatuses(viewer Viewer, filter FleetStatusFilter) (*FleetStatus, error) {
+	var vehicleIDs []uint
+	if viewer.IsDriver() {
+		assigned, err := assignedVehicleIDs(s.vehicleRepo, viewer)
+		if err != nil {
+			return nil, err
+		}
+		vehicleIDs = make([]uint, 0, len(assigned))
+		for id := range assigned {
+			vehicleIDs = append(vehicleIDs, id)
+		}
+	}
+
+	states, err := s.listStates(vehicleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(states))
+	for i, state := range states {
+		ids[i] = state.VehicleID
+	}
+	vehicles, err := s.vehicleRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vehicles: %w", err)
+	}
+	vehiclesByID := make(map[uint]*domain.Vehicle, len(vehicles))
+	for _, vehicle := range vehicles {
+		vehiclesByID[vehicle.ID] = vehicle
+	}
+	dtcCounts, err := s.dtcRepo.CountActiveByVehicles(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active DTCs: %w", err)
+	}
+
+	now := time.Now().UTC()
+	fleet := &FleetStatus{Vehicles: []*domain.VehicleRealtimeStatus{}, GeneratedAt: now}
+	for _, state := range states {
+		vehicle, ok := vehiclesByID[state.VehicleID]
+		if !ok {
+			// Deleted vehicle with a stale cache entry
+			continue
+		}
+		status := buildRealtimeStatus(vehicle, state, dtcCounts[state.VehicleID], now)
+		if filter.Online != nil && status.Online != *filter.Online {
+			continue
+		}
+		fleet.Vehicles = append(fleet.Vehicles, status)
+		if status.Online {
+			fleet.OnlineCount++
+		}
+	}
+	fleet.Total = len(fleet.Vehicles)
+	return fleet, nil
+}
+
+// getState reads a state from Redis, falling back to Postgres. A missing state returns nil.
+func (s *VehicleStatusService) getState(vehicleID uint) (*domain.VehicleLatestState, error) {
+	if s.redis != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+		defer cancel()
+
+		value, err := s.redis.HGet(ctx, vehicleStatesKey, strconv.FormatUint(uint64(vehicleID), 10)).Result()
+		switch {
+		case err == nil:
+			if state, err := decodeCachedState(value); err == nil {
+				return state, nil
+			}
+		case err != redis.Nil:
+			s.logger.WithError(err).Warn("Failed to read vehicle state cache")
+		}
+	}
+
+	state, err := s.statusRepo.GetLatestState(vehicleID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get vehicle state: %w", err)
+	}
+	if err := s.cacheStates([]*domain.VehicleLatestState{state}); err != nil {
+		s.logger.WithError(err).Warn("Failed to cache vehicle latest state")
+	}
+	return state, nil
+}
+
+// listStates reads states from Redis once the cache has been fully loaded, otherwise from Postgres.
+// vehicleIDs nil lists all vehicles.
+func (s *VehicleStatusService) listStates(vehicleIDs []uint) ([]*domain.VehicleLatestState, error) {
+	if vehicleIDs != nil && len(vehicleIDs) == 0 {
+		return nil, nil
+	}
+
+	if s.redis != nil {
+		states, err := s.listCachedStates(vehicleIDs)
+		if err == nil && states != nil {
+			return states, nil
+		}
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to read vehicle state cache")
+		}
+	}
+
+	states, err := s.statusRepo.ListLatestStates(vehicleIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vehicle states: %w", err)
+	}
+	if vehicleIDs == nil && s.redis != nil {
+		s.warmCache(states)
+	}
+	return states, nil
+}
+
+// listCachedStates returns nil without error when the cache is not warm
+func (s *VehicleStatusService) listCachedStates(vehicleIDs []uint) ([]*domain.VehicleLatestState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+
+	warm, err := s.redis.Exists(ctx, vehicleStatesWarmKey).Result()
+	if err != nil || warm == 0 {
+		return nil, err
+	}
+
+	var values []string
+	if vehicleIDs == nil {
+		all, err := s.redis.HGetAll(ctx, vehicleStatesKey).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range all {
+			values = append(values, value)
+		}
+	} else {
+		fields := make([]string, len(vehicleIDs))
+		for i, id := range vehicleIDs {
+			fields[i] = strconv.FormatUint(uint64(id), 10)
+		}
+		results, err := s.redis.HMGet(ctx, vehicleStatesKey, fields...).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			if value, ok := result.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	states := make([]*domain.VehicleLatestState, 0, len(values))
+	for _, value := range values {
+		state, err := decodeCachedState(value)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// warmCache loads the full projection into Redis and marks it complete
+func (s *VehicleStatusService) warmCache(states []*domain.VehicleLatestState) {
+	if err := s.cacheStates(states); err != nil {
+		s.logger.WithError(err).Warn("Failed to warm vehicle state cache")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+	if err := s.redis.Set(ctx, vehicleStatesWarmKey, time.Now().UTC().Format(time.RFC3339), 0).Err(); err != nil {
+		s.logger.WithError(err).Warn("Failed to mark vehicle state cache as warm")
+	}
+}
+
+// cacheStates writes states to Redis without replacing newer ones
+func (s *VehicleStatusService) cacheStates(states []*domain.VehicleLatestState) error {
+	if s.redis == nil || len(states) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(states)*2)
+	for _, state := range states {
+		payload, err := json.Marshal(&cachedVehicleState{
+			VehicleLatestState: state,
+			SampleAtMs:         state.SampleAt.UnixMilli(),
+		})
+		if err != nil {
+			return err
+		}
+		args = append(args, strconv.FormatUint(uint64(state.VehicleID), 10), string(payload))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisOperationTimeout)
+	defer cancel()
+	return upsertVehicleStatesScript.Run(ctx, s.redis, []string{vehicleStatesKey}, args...).Err()
+}
+
+func decodeCachedState(value string) (*domain.VehicleLatestState, error) {
+	cached := cachedVehicleState{VehicleLatestState: &domain.VehicleLatestState{}}
+	if err := json.Unmarshal([]byte(value), &cached); err != nil {
+		return nil, fmt.Errorf("invalid cached vehicle state: %w", err)
+	}
+	return cached.VehicleLatestState, nil
+}
+
+// buildRealtimeStatus derives the client view, including staleness, from a state
+func buildRealtimeStatus(vehicle *domain.Vehicle, state *domain.VehicleLatestState, activeDTCs int, now time.Time) *domain.VehicleRealtimeStatus {
+	status := &domain.VehicleRealtimeStatus{
+		VehicleID:      vehicle.ID,
+		PlateNumber:    vehicle.PlateNumber,
+		ActiveDTCCount: activeDTCs,
+	}
+	if state == nil {
+		return status
+	}
+
+	sampleAt := state.SampleAt
+	staleness := now.Sub(sampleAt)
+	if staleness < 0 {
+		staleness = 0
+	}
+
+	status.HasTelematics = true
+	status.Latitude = state.Latitude
+	status.Longitude = state.Longitude
+	status.Speed = state.Speed
+	status.Heading = state.Heading
+	status.EngineStatus = state.EngineStatus
+	status.Ignition = state.EngineStatus == domain.EngineStatusOn || state.EngineStatus == domain.EngineStatusIdle
+	status.FuelLevel = state.FuelLevel
+	status.BatteryLevel = state.BatteryLevel
+	status.LastSeenAt = &sampleAt
+	status.StalenessSeconds = int64(staleness / time.Second)
+	status.Online = staleness <= vehicleOnlineThreshold
+	return status
+}
@@ -0,0 +1,538 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"ton-platform/internal/domain"
+	"ton-platform/internal/repository/interfaces"
+)
+
+// Work order service errors
+var (
+	ErrInvalidWorkOrderTransition = errors.New("invalid work order status transition")
+	ErrWorkOrderClosed            = errors.New("work order is completed or cancelled")
+	ErrWorkOrderNotDeletable      = errors.New("only pending or cancelled work orders can be deleted")
+	ErrWorkOrderAccessDenied      = errors.New("work order is not assigned to the current user")
+)
+
+// workOrderTransitions lists the statuses each status may move to. Completed and
+// cancelled work orders are final.
+var workOrderTransitions = map[string][]string{
+	domain.StatusPending:         {domain.StatusAssigned, domain.StatusCancelled},
+	domain.StatusAssigned:        {domain.StatusAssigned, domain.StatusInProgress, domain.StatusCancelled},
+	domain.StatusInProgress:      {domain.StatusOnHold, domain.StatusWaitingForParts, domain.StatusCompleted, domain.StatusCancelled},
+	domain.StatusOnHold:          {domain.StatusInProgress, domain.StatusCancelled},
+	domain.StatusWaitingForParts: {domain.StatusInProgress, domain.StatusCancelled},
+}
+
+// canTransitionWorkOrder reports whether a work order may move from one status to another
+func canTransitionWorkOrder(from, to string) bool {
+	for _, status := range workOrderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isWorkOrderClosed reports whether a status is final
+func isWorkOrderClosed(status string) bool {
+	return status == domain.StatusCompleted || status == domain.StatusCancelled
+}
+
+// WorkOrderRequest represents the descriptive fields of a work order
+type WorkOrderRequest struct {
+	CustomerName   string  `json:"customer_name" validate:"max=100"` // defaults to the fleet customer of the vehicle
+	CustomerPhone  string  `json:"customer_phone" validate:"max=20"`
+	CustomerEmail  string  `json:"customer_email" validate:"omitempty,email,max=100"`
+	ServiceType    string  `json:"service_type" validate:"required,oneof=routine_maintenance repair inspection emergency customization"`
+	Priority       string  `json:"priority" validate:"omitempty,oneof=low normal high critical emergency"`
+	Description    string  `json:"description" validate:"required"`
+	Symptoms       string  `json:"symptoms"`
+	Diagnosis      string  `json:"diagnosis"`
+	EstimatedCost  float64 `json:"estimated_cost" validate:"min=0"`
+	EstimatedHours float64 `json:"estimated_hours" validate:"min=0,max=999"`
+	Notes          string  `json:"notes"`
+}
+
+// CreateWorkOrderRequest represents a work order creation request
+type CreateWorkOrderRequest struct {
+	VehicleID uint `json:"vehicle_id" validate:"required"`
+	WorkOrderRequest
+}
+
+// AssignWorkOrderRequest assigns or reassigns a work order to a mechanic. Without a mechanic the
+// recommended candidate is assigned; any other mechanic needs an override reason.
+type AssignWorkOrderRequest struct {
+	MechanicID     uint   `json:"mechanic_id"`
+	OverrideReason string `json:"override_reason" validate:"max=1000"`
+	Note           string `json:"note" validate:"max=1000"`
+}
+
+// WorkOrderTransitionRequest carries the note recorded with a status change
+type WorkOrderTransitionRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// HoldWorkOrderRequest pauses a work order in progress
+type HoldWorkOrderRequest struct {
+	Status string `json:"status" validate:"omitempty,oneof=on_hold waiting_for_parts"` // defaults to on_hold
+	Note   string `json:"note" validate:"required,max=1000"`
+}
+
+// CompleteWorkOrderRequest completes a work order in progress
+type CompleteWorkOrderRequest struct {
+	Note        string   `json:"note" validate:"max=1000"`
+	Diagnosis   string   `json:"diagnosis"`
+	ActualHours *float64 `json:"actual_hours" validate:"omitempty,min=0,max=999"`
+	ActualCost  *float64 `json:"actual_cost" validate:"omitempty,min=0"`
+}
+
+// CancelWorkOrderRequest cancels an open work order
+type CancelWorkOrderRequest struct {
+	Note string `json:"note" validate:"required,max=1000"`
+}
+
+// WorkOrderList represents a page of work orders
+type WorkOrderList struct {
+	WorkOrders []*domain.WorkOrder `json:"work_orders"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+}
+
+// WorkOrderListener is notified after a work order changed status. Listeners run on the request
+// goroutine once the change is stored.
+type WorkOrderListener interface {
+	HandleWorkOrderStatus(workOrder *domain.WorkOrder, entry *domain.WorkOrderStatusHistory)
+}
+
+// WorkOrderService manages work orders and enforces their status lifecycle. Every status
+// change is recorded in the status history with the acting user.
+type WorkOrderService struct {
+	workOrderRepo   interfaces.WorkOrderRepository
+	vehicleRepo     interfaces.VehicleRepository
+	userRepo        interfaces.UserRepository
+	mechanicService *MechanicService
+	listeners       []WorkOrderListener
+	validator       *validator.Validate
+	logger          *logrus.Logger
+}
+
+// NewWorkOrderService creates a new work order service
+func NewWorkOrderService(
+	workOrderRepo interfaces.WorkOrderRepository,
+	vehicleRepo interfaces.VehicleRepository,
+	userRepo interfaces.UserRepository,
+	mechanicService *MechanicService,
+	logger *logrus.Logger,
+) *WorkOrderService {
+	return &WorkOrderService{
+		workOrderRepo:   workOrderRepo,
+		vehicleRepo:     vehicleRepo,
+		userRepo:        userRepo,
+		mechanicService: mechanicService,
+		validator:       validator.New(),
+		logger:          logger,
+	}
+}
+
+// AddListener registers a listener for work order status changes
+func (s *WorkOrderService) AddListener(listener WorkOrderListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// Create opens a pending work order with the current user as service advisor
+func (s *WorkOrderService) Create(viewer Viewer, req *CreateWorkOrderRequest) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	vehicle, err := s.vehicleRepo.GetByID(req.VehicleID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+
+	workOrder := &domain.WorkOrder{
+		VehicleID:        vehicle.ID,
+		Status:           domain.StatusPending,
+		ServiceAdvisorID: viewer.UserID,
+	}
+	applyWorkOrderRequest(workOrder, &req.WorkOrderRequest, vehicle)
+
+	if err := s.workOrderRepo.Create(workOrder); err != nil {
+		s.logger.WithError(err).Error("Work order creation failed")
+		return nil, fmt.Errorf("failed to create work order: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"work_order_id": workOrder.ID,
+		"wo_number":     workOrder.WONumber,
+		"vehicle_id":    vehicle.ID,
+	}).Info("Work order created")
+
+	return s.workOrderRepo.GetByID(workOrder.ID)
+}
+
+// Get retrieves a work order the viewer has access to
+func (s *WorkOrderService) Get(viewer Viewer, id uint) (*domain.WorkOrder, error) {
+	workOrder, err := s.workOrderRepo.GetByID(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrWorkOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get work order: %w", err)
+	}
+	if err := s.checkAccess(viewer, workOrder); err != nil {
+		return nil, err
+	}
+	return workOrder, nil
+}
+
+// List retrieves a page of work orders. Mechanics only see the work orders assigned to
+// them and drivers those of their vehicles.
+func (s *WorkOrderService) List(viewer Viewer, filter interfaces.WorkOrderFilter, page, limit, offset int) (*WorkOrderList, error) {
+	if filter.Status != "" && !isWorkOrderStatus(filter.Status) {
+		return nil, fmt.Errorf("validation failed: unknown status %s", filter.Status)
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	switch {
+	case viewer.Role == domain.RoleMechanic:
+		filter.MechanicID = viewer.UserID
+	case viewer.IsDriver():
+		assigned, err := assignedVehicleIDs(s.vehicleRepo, viewer)
+		if err != nil {
+			return nil, err
+		}
+		filter.VehicleIDs = make([]uint, 0, len(assigned))
+		for vehicleID := range assigned {
+			filter.VehicleIDs = append(filter.VehicleIDs, vehicleID)
+		}
+	}
+
+	workOrders, total, err := s.workOrderRepo.List(filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work orders: %w", err)
+	}
+	return &WorkOrderList{WorkOrders: workOrders, Total: total, Page: page, Limit: limit}, nil
+}
+
+// Update changes the descriptive fields of an open work order
+func (s *WorkOrderService) Update(viewer Viewer, id uint, req *WorkOrderRequest) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	workOrder, err := s.Get(viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+
+	applyWorkOrderRequest(workOrder, req, &workOrder.Vehicle)
+	if err := s.workOrderRepo.Update(workOrder); err != nil {
+		if isNotFound(err) {
+			return nil, ErrWorkOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to update work order: %w", err)
+	}
+	return s.workOrderRepo.GetByID(id)
+}
+
+// Delete removes a work order that has not been worked on
+func (s *WorkOrderService) Delete(viewer Viewer, id uint) error {
+	workOrder, err := s.Get(viewer, id)
+	if err != nil {
+		return err
+	}
+	if workOrder.Status != domain.StatusPending && workOrder.Status != domain.StatusCancelled {
+		return ErrWorkOrderNotDeletable
+	}
+	if err := s.workOrderRepo.Delete(id); err != nil {
+		if isNotFound(err) {
+			return ErrWorkOrderNotFound
+		}
+		return fmt.Errorf("failed to delete work order: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"work_order_id": id,
+		"wo_number":     workOrder.WONumber,
+		"deleted_by":    viewer.UserID,
+	}).Info("Work order deleted")
+	return nil
+}
+
+// GetStatusHistory retrieves the status changes of a work order, oldest first
+func (s *WorkOrderService) GetStatusHistory(viewer Viewer, id uint) ([]*domain.WorkOrderStatusHistory, error) {
+	if _, err := s.Get(viewer, id); err != nil {
+		return nil, err
+	}
+	return s.workOrderRepo.GetStatusHistory(id)
+}
+
+// AssignmentCandidates ranks the mechanics who could take over a work order
+func (s *WorkOrderService) AssignmentCandidates(viewer Viewer, id uint) ([]*AssignmentCandidate, error) {
+	workOrder, err := s.Get(viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkOrderClosed(workOrder.Status) {
+		return nil, ErrWorkOrderClosed
+	}
+	return s.mechanicService.RankCandidates(workOrder)
+}
+
+// Assign assigns a pending work order to a mechanic, or reassigns one that has not started.
+// Without a mechanic the recommended candidate is assigned. Choosing anyone else overrides the
+// recommendation and needs a reason, which is recorded in the status history.
+func (s *WorkOrderService) Assign(viewer Viewer, id uint, req *AssignWorkOrderRequest) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	candidates, err := s.AssignmentCandidates(viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	var recommended *AssignmentCandidate
+	if len(candidates) > 0 && candidates[0].Recommended {
+		recommended = candidates[0]
+	}
+
+	mechanicID := req.MechanicID
+	override := strings.TrimSpace(req.OverrideReason)
+	switch {
+	case mechanicID == 0 && recommended == nil:
+		return nil, fmt.Errorf("validation failed: no qualified mechanic is available, choose one with an override reason")
+	case mechanicID == 0:
+		mechanicID = recommended.MechanicID
+	case (recommended == nil || recommended.MechanicID != mechanicID) && override == "":
+		return nil, fmt.Errorf("validation failed: override_reason is required when not assigning the recommended mechanic")
+	}
+
+	mechanic, err := s.userRepo.GetByID(mechanicID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("validation failed: user %d does not exist", mechanicID)
+		}
+		return nil, fmt.Errorf("failed to get mechanic: %w", err)
+	}
+	if !mechanic.IsActive || mechanic.Role.Name != domain.RoleMechanic {
+		return nil, fmt.Errorf("validation failed: user %d is not an active mechanic", mechanicID)
+	}
+
+	note := fmt.Sprintf("Assigned to %s %s", mechanic.FirstName, mechanic.LastName)
+	switch {
+	case recommended != nil && recommended.MechanicID == mechanic.ID:
+		note += fmt.Sprintf(" (recommended, score %.2f)", recommended.Score)
+	case recommended != nil:
+		note += fmt.Sprintf(" instead of recommended %s: %s", recommended.Name, override)
+	default:
+		note += fmt.Sprintf(" without a recommendation: %s", override)
+	}
+	if req.Note != "" {
+		note += ". " + req.Note
+	}
+	return s.transition(viewer, id, domain.StatusAssigned, note, func(workOrder *domain.WorkOrder) error {
+		if workOrder.AssignedMechanicID != nil && *workOrder.AssignedMechanicID == mechanic.ID {
+			return fmt.Errorf("validation failed: work order is already assigned to user %d", mechanic.ID)
+		}
+		workOrder.AssignedMechanicID = &mechanic.ID
+		return nil
+	})
+}
+
+// Start begins work on an assigned work order
+func (s *WorkOrderService) Start(viewer Viewer, id uint, req *WorkOrderTransitionRequest) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return s.transition(viewer, id, domain.StatusInProgress, defaultNote(req.Note, "Work started"), func(workOrder *domain.WorkOrder) error {
+		if workOrder.Status != domain.StatusAssigned {
+			return fmt.Errorf("%w: only assigned work orders can be started, use resume for %s", ErrInvalidWorkOrderTransition, workOrder.Status)
+		}
+		if workOrder.StartDate == nil {
+			now := time.Now().UTC()
+			workOrder.StartDate = &now
+		}
+		return nil
+	})
+}
+
+// Hold pauses a work order in progress, either on hold or waiting for parts
+func (s *WorkOrderService) Hold(viewer Viewer, id uint, req *HoldWorkOrderRequest) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	status := req.Status
+	if status == "" {
+		status = domain.StatusOnHold
+	}
+	return s.transition(viewer, id, status, req.Note, nil)
+}
+
+// Resume continues a work order that was on hold or waiting for parts
+func (s *WorkOrderService) Resume(viewer Viewer, id uint, req *WorkOrderTransitionRequest) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return s.transition(viewer, id, domain.StatusInProgress, defaultNote(req.Note, "Work resumed"), func(workOrder *domain.WorkOrder) error {
+		if workOrder.Status != domain.StatusOnHold && workOrder.Status != domain.StatusWaitingForParts {
+			return fmt.Errorf("%w: cannot resume a work order that is %s", ErrInvalidWorkOrderTransition, workOrder.Status)
+		}
+		return nil
+	})
+}
+
+// Complete finishes a work order in progress
+func (s *WorkOrderService) Complete(viewer Viewer, id uint, req *CompleteWorkOrderRequest) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return s.transition(viewer, id, domain.StatusCompleted, defaultNote(req.Note, "Work completed"), func(workOrder *domain.WorkOrder) error {
+		now := time.Now().UTC()
+		workOrder.CompletionDate = &now
+		if req.ActualHours != nil {
+			workOrder.ActualHours = *req.ActualHours
+		}
+		if req.ActualCost != nil {
+			workOrder.ActualCost = *req.ActualCost
+		}
+		if req.Diagnosis != "" {
+			workOrder.Diagnosis = req.Diagnosis
+		}
+		return nil
+	})
+}
+
+// Cancel cancels a work order that is not completed. Mechanics cannot cancel work orders.
+func (s *WorkOrderService) Cancel(viewer Viewer, id uint, req *CancelWorkOrderRequest) (*domain.WorkOrder, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if viewer.Role == domain.RoleMechanic {
+		return nil, ErrWorkOrderAccessDenied
+	}
+	return s.transition(viewer, id, domain.StatusCancelled, req.Note, nil)
+}
+
+// transition moves a work order to a new status under a row lock. The optional change
+// adjusts the work order once the transition has been validated.
+func (s *WorkOrderService) transition(viewer Viewer, id uint, to, note string, change func(workOrder *domain.WorkOrder) error) (*domain.WorkOrder, error) {
+	var entry *domain.WorkOrderStatusHistory
+	workOrder, err := s.workOrderRepo.Transition(id, func(workOrder *domain.WorkOrder) (*domain.WorkOrderStatusHistory, error) {
+		if err := s.checkAccess(viewer, workOrder); err != nil {
+			return nil, err
+		}
+		if !canTransitionWorkOrder(workOrder.Status, to) {
+			return nil, fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidWorkOrderTransition, workOrder.Status, to)
+		}
+		if change != nil {
+			if err := change(workOrder); err != nil {
+				return nil, err
+			}
+		}
+
+		from := workOrder.Status
+		workOrder.Status = to
+		entry = &domain.WorkOrderStatusHistory{
+			OldStatus: &from,
+			NewStatus: to,
+			ChangedBy: viewer.UserID,
+			Notes:     note,
+			ChangedAt: time.Now().UTC(),
+		}
+		return entry, nil
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrWorkOrderNotFound
+		}
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"work_order_id": id,
+		"from":          *entry.OldStatus,
+		"to":            to,
+		"changed_by":    viewer.UserID,
+	}).Info("Work order status changed")
+
+	for _, listener := range s.listeners {
+		listener.HandleWorkOrderStatus(workOrder, entry)
+	}
+	return workOrder, nil
+}
+
+// checkAccess limits mechanics to the work orders assigned to them and drivers to
+// the work orders of their vehicles
+func (s *WorkOrderService) checkAccess(viewer Viewer, workOrder *domain.WorkOrder) error {
+	switch {
+	case viewer.Role == domain.RoleMechanic:
+		if workOrder.AssignedMechanicID == nil || *workOrder.AssignedMechanicID != viewer.UserID {
+			return ErrWorkOrderAccessDenied
+		}
+	case viewer.IsDriver():
+		assigned, err := assignedVehicleIDs(s.vehicleRepo, viewer)
+		if err != nil {
+			return err
+		}
+		if !assigned[workOrder.VehicleID] {
+			return ErrVehicleAccessDenied
+		}
+	}
+	return nil
+}
+
+// applyWorkOrderRequest copies the descriptive fields of a request onto a work order
+func applyWorkOrderRequest(workOrder *domain.WorkOrder, req *WorkOrderRequest, vehicle *domain.Vehicle) {
+	workOrder.CustomerName = strings.TrimSpace(req.CustomerName)
+	if workOrder.CustomerName == "" {
+		workOrder.CustomerName = internalCustomerName(vehicle)
+	}
+	workOrder.CustomerPhone = req.CustomerPhone
+	workOrder.CustomerEmail = req.CustomerEmail
+	workOrder.ServiceType = req.ServiceType
+	workOrder.Priority = req.Priority
+	if workOrder.Priority == "" {
+		workOrder.Priority = domain.PriorityNormal
+	}
+	workOrder.Description = req.Description
+	workOrder.Symptoms = req.Symptoms
+	workOrder.Diagnosis = req.Diagnosis
+	workOrder.EstimatedCost = req.EstimatedCost
+	workOrder.EstimatedHours = req.EstimatedHours
+	workOrder.Notes = req.Notes
+}
+
+// isWorkOrderStatus reports whether a status is one of the work order statuses
+func isWorkOrderStatus(status string) bool {
+	if isWorkOrderClosed(status) {
+		return true
+	}
+	_, ok := workOrderTransitions[status]
+	return ok
+}
+
+// defaultNote returns the note, or the fallback when it is empty
+func defaultNote(note, fallback string) string {
+	if strings.TrimSpace(note) == "" {
+		return fallback
+	}
+	return note
+}
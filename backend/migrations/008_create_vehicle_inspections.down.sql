@@ -0,0 +1,6 @@
+-- Drop vehicle inspections migration
+DROP TABLE IF EXISTS inspection_results;
+DROP TABLE IF EXISTS inspections;
+DROP TABLE IF EXISTS inspection_items;
+DROP TABLE IF EXISTS inspection_sections;
+DROP TABLE IF EXISTS inspection_templates;
@@ -0,0 +1,99 @@
+-- Create inspection_templates table
+-- This table stores versioned vehicle inspection checklists
+
+CREATE TABLE IF NOT EXISTS inspection_templates (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL, -- shared by all versions of a template
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    vehicle_type VARCHAR(30), -- NULL/empty applies to all vehicle types
+    version INTEGER NOT NULL DEFAULT 1,
+    is_active BOOLEAN DEFAULT true,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (code, version)
+);
+
+-- Create inspection_sections table
+
+CREATE TABLE IF NOT EXISTS inspection_sections (
+    id SERIAL PRIMARY KEY,
+    template_id INTEGER NOT NULL REFERENCES inspection_templates(id) ON DELETE CASCADE,
+    title VARCHAR(100) NOT NULL,
+    sort_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create inspection_items table
+
+CREATE TABLE IF NOT EXISTS inspection_items (
+    id SERIAL PRIMARY KEY,
+    section_id INTEGER NOT NULL REFERENCES inspection_sections(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    label VARCHAR(200) NOT NULL,
+    item_type VARCHAR(20) NOT NULL, -- pass_fail, value, text
+    unit VARCHAR(20),
+    min_value DECIMAL(12, 3),
+    max_value DECIMAL(12, 3),
+    is_required BOOLEAN DEFAULT true,
+    is_critical BOOLEAN DEFAULT false,
+    photos_required INTEGER DEFAULT 0,
+    sort_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create inspections table
+-- This table stores submitted inspections per vehicle
+
+CREATE TABLE IF NOT EXISTS inspections (
+    id SERIAL PRIMARY KEY,
+    inspection_number VARCHAR(30) UNIQUE NOT NULL,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    work_order_id INTEGER REFERENCES work_orders(id) ON DELETE SET NULL,
+    template_id INTEGER NOT NULL REFERENCES inspection_templates(id),
+    inspector_id INTEGER NOT NULL REFERENCES users(id),
+    odometer INTEGER,
+    outcome VARCHAR(20) NOT NULL, -- passed, advisory, failed
+    notes TEXT,
+    submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create inspection_results table
+
+CREATE TABLE IF NOT EXISTS inspection_results (
+    id SERIAL PRIMARY KEY,
+    inspection_id INTEGER NOT NULL REFERENCES inspections(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES inspection_items(id),
+    result VARCHAR(10) NOT NULL, -- pass, fail, na
+    value DECIMAL(12, 3),
+    text TEXT,
+    photo_urls JSONB NOT NULL DEFAULT '[]',
+    notes TEXT,
+    defect_work_order_id INTEGER REFERENCES work_orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_inspection_templates_code ON inspection_templates(code);
+CREATE INDEX IF NOT EXISTS idx_inspection_templates_is_active ON inspection_templates(is_active);
+CREATE INDEX IF NOT EXISTS idx_inspection_sections_template ON inspection_sections(template_id);
+CREATE INDEX IF NOT EXISTS idx_inspection_items_section ON inspection_items(section_id);
+
+CREATE INDEX IF NOT EXISTS idx_inspections_vehicle_id ON inspections(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_inspections_work_order_id ON inspections(work_order_id);
+CREATE INDEX IF NOT EXISTS idx_inspections_submitted_at ON inspections(submitted_at);
+CREATE INDEX IF NOT EXISTS idx_inspection_results_inspection ON inspection_results(inspection_id);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_inspection_templates_updated_at
+    BEFORE UPDATE ON inspection_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_inspections_updated_at
+    BEFORE UPDATE ON inspections
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Drop damage reports migration
+DROP TABLE IF EXISTS damage_reports;
@@ -0,0 +1,37 @@
+-- Create damage_reports table
+-- This table stores vehicle damage annotated on the vehicle diagram
+
+CREATE TABLE IF NOT EXISTS damage_reports (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    inspection_id INTEGER REFERENCES inspections(id) ON DELETE SET NULL,
+    zone_code VARCHAR(30) NOT NULL, -- front_bumper, left_front_door, etc.
+    position_x DECIMAL(5, 4), -- relative position on the zone diagram (0-1)
+    position_y DECIMAL(5, 4),
+    damage_type VARCHAR(20) NOT NULL, -- scratch, dent, crack, chip, broken, missing, stain, other
+    severity VARCHAR(20) NOT NULL, -- minor, moderate, severe
+    description TEXT,
+    photo_urls JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, repaired, charged
+    estimated_cost DECIMAL(12, 2) DEFAULT 0,
+    charged_amount DECIMAL(12, 2) DEFAULT 0,
+    repair_work_order_id INTEGER REFERENCES work_orders(id) ON DELETE SET NULL,
+    invoice_item_id INTEGER REFERENCES invoice_items(id) ON DELETE SET NULL,
+    reported_by INTEGER NOT NULL REFERENCES users(id),
+    reported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_damage_reports_vehicle_id ON damage_reports(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_damage_reports_inspection_id ON damage_reports(inspection_id);
+CREATE INDEX IF NOT EXISTS idx_damage_reports_status ON damage_reports(status);
+CREATE INDEX IF NOT EXISTS idx_damage_reports_reported_at ON damage_reports(reported_at);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_damage_reports_updated_at
+    BEFORE UPDATE ON damage_reports
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
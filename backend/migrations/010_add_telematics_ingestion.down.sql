@@ -0,0 +1,4 @@
+-- Revert telematics ingestion changes
+DROP INDEX IF EXISTS idx_telematics_data_vehicle_timestamp;
+DROP INDEX IF EXISTS idx_telematics_data_device_timestamp;
+ALTER TABLE telematics_data DROP COLUMN IF EXISTS device_id;
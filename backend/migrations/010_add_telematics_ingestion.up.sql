@@ -0,0 +1,16 @@
+-- Prepare telematics_data for batched device ingestion
+-- Samples are identified by the reporting device and the sample timestamp so that
+-- retried or replayed uploads can be deduplicated.
+
+ALTER TABLE telematics_data ADD COLUMN IF NOT EXISTS device_id VARCHAR(50);
+
+-- High-frequency ingestion exhausts a 32-bit key quickly
+ALTER TABLE telematics_data ALTER COLUMN id TYPE BIGINT;
+ALTER SEQUENCE IF EXISTS telematics_data_id_seq AS BIGINT;
+
+-- Existing rows without a device are never considered duplicates (NULLs are distinct)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_telematics_data_device_timestamp ON telematics_data(device_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_telematics_data_vehicle_timestamp ON telematics_data(vehicle_id, timestamp DESC);
+
+-- telematics_data has no updated_at column, so the generic trigger would fail on any update
+DROP TRIGGER IF EXISTS update_telematics_data_updated_at ON telematics_data;
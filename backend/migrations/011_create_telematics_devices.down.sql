@@ -0,0 +1,3 @@
+-- Drop telematics devices migration
+DROP TABLE IF EXISTS device_bindings;
+DROP TABLE IF EXISTS telematics_devices;
@@ -0,0 +1,57 @@
+-- Create telematics_devices table
+-- This table is the registry of physical telematics units and their ingestion credentials
+
+CREATE TABLE IF NOT EXISTS telematics_devices (
+    id SERIAL PRIMARY KEY,
+    identifier VARCHAR(50) UNIQUE NOT NULL, -- IMEI or serial the unit reports itself with
+    imei VARCHAR(20),
+    serial_number VARCHAR(50),
+    manufacturer VARCHAR(50),
+    model VARCHAR(50),
+    firmware_version VARCHAR(50),
+    protocol VARCHAR(30) NOT NULL DEFAULT 'http', -- http, teltonika_codec8, gt06, mqtt
+    sim_iccid VARCHAR(22),
+    sim_phone_number VARCHAR(20),
+    sim_carrier VARCHAR(50),
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, inactive, maintenance, retired
+    auth_type VARCHAR(10) NOT NULL DEFAULT 'token', -- token, hmac
+    credential_hash VARCHAR(64), -- SHA-256 of the device token
+    hmac_secret VARCHAR(64), -- shared key for HMAC request signing
+    credential_rotated_at TIMESTAMP,
+    last_seen_at TIMESTAMP,
+    notes TEXT,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create device_bindings table
+-- This table records which vehicle a device was installed in over time
+
+CREATE TABLE IF NOT EXISTS device_bindings (
+    id SERIAL PRIMARY KEY,
+    device_id INTEGER NOT NULL REFERENCES telematics_devices(id) ON DELETE CASCADE,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    bound_from TIMESTAMP NOT NULL,
+    bound_until TIMESTAMP, -- NULL while the device is still installed
+    bound_by INTEGER REFERENCES users(id),
+    unbound_by INTEGER REFERENCES users(id),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (bound_until IS NULL OR bound_until > bound_from)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_telematics_devices_status ON telematics_devices(status);
+CREATE INDEX IF NOT EXISTS idx_telematics_devices_imei ON telematics_devices(imei);
+
+CREATE INDEX IF NOT EXISTS idx_device_bindings_device_id ON device_bindings(device_id, bound_from);
+CREATE INDEX IF NOT EXISTS idx_device_bindings_vehicle_id ON device_bindings(vehicle_id);
+-- A device can only be installed in one vehicle at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_bindings_open ON device_bindings(device_id) WHERE bound_until IS NULL;
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_telematics_devices_updated_at
+    BEFORE UPDATE ON telematics_devices
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
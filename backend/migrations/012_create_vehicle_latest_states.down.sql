@@ -0,0 +1,3 @@
+-- Drop vehicle latest states migration
+DROP INDEX IF EXISTS idx_dtc_codes_vehicle_active;
+DROP TABLE IF EXISTS vehicle_latest_states;
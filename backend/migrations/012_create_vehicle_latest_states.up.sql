@@ -0,0 +1,33 @@
+-- Create vehicle_latest_states table
+-- This table is the durable latest-state projection maintained on telematics ingest.
+-- Redis holds the hot copy; this table is the fallback and rebuilds the cache.
+
+CREATE TABLE IF NOT EXISTS vehicle_latest_states (
+    vehicle_id INTEGER PRIMARY KEY REFERENCES vehicles(id) ON DELETE CASCADE,
+    device_id VARCHAR(50),
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    speed DECIMAL(5, 2), -- km/h
+    heading DECIMAL(5, 2), -- degrees
+    engine_status VARCHAR(20), -- on, off, idle
+    fuel_level DECIMAL(5, 2), -- percentage
+    battery_voltage DECIMAL(5, 2), -- volts
+    engine_rpm INTEGER,
+    total_distance DECIMAL(10, 2), -- km
+    sample_at TIMESTAMP NOT NULL, -- timestamp of the sample the state was taken from
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_latest_states_sample_at ON vehicle_latest_states(sample_at);
+
+-- Seed the projection from existing telematics data
+INSERT INTO vehicle_latest_states (vehicle_id, device_id, latitude, longitude, speed, heading, engine_status,
+    fuel_level, battery_voltage, engine_rpm, total_distance, sample_at)
+SELECT DISTINCT ON (vehicle_id) vehicle_id, device_id, latitude, longitude, speed, heading, engine_status,
+    fuel_level, battery_voltage, engine_rpm, total_distance, "timestamp"
+FROM telematics_data
+WHERE "timestamp" IS NOT NULL
+ORDER BY vehicle_id, "timestamp" DESC
+ON CONFLICT (vehicle_id) DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_dtc_codes_vehicle_active ON dtc_codes(vehicle_id) WHERE is_active = true;
@@ -0,0 +1,2 @@
+-- Drop trips migration
+DROP TABLE IF EXISTS trips;
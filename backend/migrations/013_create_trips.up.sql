@@ -0,0 +1,38 @@
+-- Create trips table
+-- This table stores trips segmented from telematics_data. Trips are derived data:
+-- the segmentation engine replaces them whenever the underlying samples are reprocessed.
+
+CREATE TABLE IF NOT EXISTS trips (
+    id BIGSERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'completed', -- in_progress, completed
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    start_latitude DECIMAL(10, 8),
+    start_longitude DECIMAL(11, 8),
+    start_place VARCHAR(255),
+    end_latitude DECIMAL(10, 8),
+    end_longitude DECIMAL(11, 8),
+    end_place VARCHAR(255),
+    distance_km DECIMAL(10, 3) NOT NULL DEFAULT 0,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    moving_seconds INTEGER NOT NULL DEFAULT 0,
+    idle_seconds INTEGER NOT NULL DEFAULT 0,
+    max_speed DECIMAL(5, 2) NOT NULL DEFAULT 0, -- km/h
+    avg_speed DECIMAL(5, 2) NOT NULL DEFAULT 0, -- km/h while moving
+    fuel_used_percent DECIMAL(5, 2), -- drop in fuel level, NULL without fuel data
+    sample_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_time >= start_time)
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trips_vehicle_start ON trips(vehicle_id, start_time);
+CREATE INDEX IF NOT EXISTS idx_trips_vehicle_end ON trips(vehicle_id, end_time);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_trips_updated_at
+    BEFORE UPDATE ON trips
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
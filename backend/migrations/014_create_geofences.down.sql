@@ -0,0 +1,7 @@
+-- Drop geofences migration
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS geofence_alert_rules;
+DROP TABLE IF EXISTS geofence_events;
+DROP TABLE IF EXISTS geofence_vehicle_states;
+DROP TABLE IF EXISTS geofence_assignments;
+DROP TABLE IF EXISTS geofences;
@@ -0,0 +1,124 @@
+-- Create geofences table
+-- This table stores circular and polygonal areas that vehicle positions are evaluated against
+
+CREATE TABLE IF NOT EXISTS geofences (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    shape VARCHAR(10) NOT NULL CHECK (shape IN ('circle', 'polygon')),
+    center_latitude DECIMAL(10, 8), -- circle only
+    center_longitude DECIMAL(11, 8),
+    radius_meters DECIMAL(10, 2),
+    polygon JSONB, -- polygon only: [{"latitude": .., "longitude": ..}, ...]
+    min_latitude DECIMAL(10, 8) NOT NULL, -- bounding box for quick rejection
+    min_longitude DECIMAL(11, 8) NOT NULL,
+    max_latitude DECIMAL(10, 8) NOT NULL,
+    max_longitude DECIMAL(11, 8) NOT NULL,
+    dwell_threshold_minutes INTEGER, -- NULL disables dwell events
+    schedule JSONB, -- active windows, NULL means always active
+    timezone VARCHAR(50) NOT NULL DEFAULT 'UTC',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (
+        (shape = 'circle' AND center_latitude IS NOT NULL AND center_longitude IS NOT NULL AND radius_meters > 0)
+        OR (shape = 'polygon' AND polygon IS NOT NULL)
+    )
+);
+
+-- Create geofence_assignments table
+-- A geofence applies to the listed vehicles and vehicle categories, or to every vehicle without assignments
+
+CREATE TABLE IF NOT EXISTS geofence_assignments (
+    id SERIAL PRIMARY KEY,
+    geofence_id INTEGER NOT NULL REFERENCES geofences(id) ON DELETE CASCADE,
+    vehicle_id INTEGER REFERENCES vehicles(id) ON DELETE CASCADE,
+    vehicle_category VARCHAR(30), -- rental, workshop, customer, company
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((vehicle_id IS NULL) <> (vehicle_category IS NULL))
+);
+
+-- Create geofence_vehicle_states table
+-- This table tracks whether each vehicle is inside each geofence so transitions survive restarts
+
+CREATE TABLE IF NOT EXISTS geofence_vehicle_states (
+    geofence_id INTEGER NOT NULL REFERENCES geofences(id) ON DELETE CASCADE,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    inside BOOLEAN NOT NULL,
+    entered_at TIMESTAMP, -- start of the current stay while inside
+    dwell_reported BOOLEAN NOT NULL DEFAULT false,
+    sample_at TIMESTAMP NOT NULL, -- timestamp of the last evaluated sample
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (geofence_id, vehicle_id)
+);
+
+-- Create geofence_events table
+
+CREATE TABLE IF NOT EXISTS geofence_events (
+    id BIGSERIAL PRIMARY KEY,
+    geofence_id INTEGER NOT NULL REFERENCES geofences(id) ON DELETE CASCADE,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    event_type VARCHAR(10) NOT NULL CHECK (event_type IN ('enter', 'exit', 'dwell')),
+    occurred_at TIMESTAMP NOT NULL,
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    dwell_seconds INTEGER, -- time inside, for exit and dwell events
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create geofence_alert_rules table
+
+CREATE TABLE IF NOT EXISTS geofence_alert_rules (
+    id SERIAL PRIMARY KEY,
+    geofence_id INTEGER NOT NULL REFERENCES geofences(id) ON DELETE CASCADE,
+    event_type VARCHAR(10) NOT NULL CHECK (event_type IN ('enter', 'exit', 'dwell')),
+    recipient_user_ids JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create notifications table
+-- This table stores in-app notifications for users
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL, -- geofence_enter, geofence_exit, geofence_dwell, ...
+    title VARCHAR(200) NOT NULL,
+    message TEXT,
+    reference_type VARCHAR(50), -- entity the notification is about
+    reference_id BIGINT,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_geofences_active ON geofences(is_active);
+CREATE INDEX IF NOT EXISTS idx_geofence_assignments_geofence_id ON geofence_assignments(geofence_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_geofence_assignments_vehicle ON geofence_assignments(geofence_id, vehicle_id) WHERE vehicle_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_geofence_assignments_category ON geofence_assignments(geofence_id, vehicle_category) WHERE vehicle_category IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_geofence_vehicle_states_vehicle_id ON geofence_vehicle_states(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_geofence_events_vehicle ON geofence_events(vehicle_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_geofence_events_geofence ON geofence_events(geofence_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_geofence_alert_rules_geofence_id ON geofence_alert_rules(geofence_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_geofences_updated_at
+    BEFORE UPDATE ON geofences
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_geofence_vehicle_states_updated_at
+    BEFORE UPDATE ON geofence_vehicle_states
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_geofence_alert_rules_updated_at
+    BEFORE UPDATE ON geofence_alert_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
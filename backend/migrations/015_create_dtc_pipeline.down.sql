@@ -0,0 +1,21 @@
+-- Drop DTC pipeline migration
+DROP TABLE IF EXISTS dtc_severity_rules;
+
+DROP INDEX IF EXISTS idx_dtc_codes_work_order_id;
+DROP INDEX IF EXISTS idx_dtc_codes_vehicle_code_active;
+ALTER TABLE dtc_codes DROP COLUMN IF EXISTS work_order_id;
+
+CREATE OR REPLACE FUNCTION update_dtc_last_seen()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE dtc_codes
+    SET last_seen = CURRENT_TIMESTAMP
+    WHERE id = NEW.id;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_dtc_last_seen_trigger
+    BEFORE UPDATE ON dtc_codes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_dtc_last_seen();
@@ -0,0 +1,62 @@
+-- DTC processing pipeline
+-- Active codes are upserted from device reports, classified by severity rules and may open work orders
+
+-- The last_seen trigger overwrote reported timestamps (and re-entered itself); last_seen is now
+-- maintained from the report time by the application
+DROP TRIGGER IF EXISTS update_dtc_last_seen_trigger ON dtc_codes;
+DROP FUNCTION IF EXISTS update_dtc_last_seen();
+
+ALTER TABLE dtc_codes ADD COLUMN IF NOT EXISTS work_order_id INTEGER REFERENCES work_orders(id) ON DELETE SET NULL;
+
+-- Keep only the newest active row per vehicle and code before enforcing uniqueness
+UPDATE dtc_codes d
+SET is_active = false, resolved_at = COALESCE(d.resolved_at, d.last_seen), resolution_note = 'Superseded duplicate'
+WHERE d.is_active
+  AND EXISTS (
+      SELECT 1 FROM dtc_codes newer
+      WHERE newer.vehicle_id = d.vehicle_id AND newer.code = d.code AND newer.is_active AND newer.id > d.id
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dtc_codes_vehicle_code_active ON dtc_codes(vehicle_id, code) WHERE is_active;
+CREATE INDEX IF NOT EXISTS idx_dtc_codes_work_order_id ON dtc_codes(work_order_id);
+
+-- Create dtc_severity_rules table
+-- A rule matches a code exactly or, with a trailing '*', by prefix; the longest matching pattern wins
+
+CREATE TABLE IF NOT EXISTS dtc_severity_rules (
+    id SERIAL PRIMARY KEY,
+    pattern VARCHAR(10) NOT NULL UNIQUE, -- e.g. P0217 or P030*
+    severity VARCHAR(20) NOT NULL CHECK (severity IN ('info', 'warning', 'error', 'critical')),
+    work_order_type VARCHAR(30) NOT NULL DEFAULT 'repair' CHECK (work_order_type IN ('emergency', 'repair')), -- used for critical codes
+    recipient_user_ids JSONB NOT NULL DEFAULT '[]', -- notified when a critical code appears
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_dtc_severity_rules_updated_at
+    BEFORE UPDATE ON dtc_severity_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Default rules
+INSERT INTO dtc_severity_rules (pattern, severity, work_order_type, description)
+VALUES
+    ('P0217', 'critical', 'emergency', 'Engine overtemperature'),
+    ('P0218', 'critical', 'emergency', 'Transmission overtemperature'),
+    ('P0219', 'critical', 'emergency', 'Engine overspeed'),
+    ('P0524', 'critical', 'emergency', 'Engine oil pressure too low'),
+    ('P052*', 'error', 'repair', 'Engine oil pressure sensor'),
+    ('P0300', 'critical', 'repair', 'Random/multiple cylinder misfire'),
+    ('P030*', 'critical', 'repair', 'Cylinder misfire'),
+    ('P0093', 'critical', 'emergency', 'Fuel system large leak'),
+    ('P0A80', 'critical', 'repair', 'Replace hybrid battery pack'),
+    ('B00*', 'critical', 'repair', 'Restraint system'),
+    ('C0*', 'error', 'repair', 'Chassis and brake systems'),
+    ('U0*', 'error', 'repair', 'Network communication'),
+    ('P07*', 'error', 'repair', 'Transmission'),
+    ('P04*', 'warning', 'repair', 'Emission controls'),
+    ('P1000', 'info', 'repair', 'OBD readiness test not complete')
+ON CONFLICT (pattern) DO NOTHING;
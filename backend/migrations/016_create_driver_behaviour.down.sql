@@ -0,0 +1,8 @@
+-- Drop driver behaviour migration
+DROP TABLE IF EXISTS driver_behaviour_settings;
+DROP TABLE IF EXISTS driver_trip_behaviours;
+
+DROP TRIGGER IF EXISTS record_vehicle_driver_assignment_trigger ON vehicles;
+DROP FUNCTION IF EXISTS record_vehicle_driver_assignment();
+DROP FUNCTION IF EXISTS resolve_assigned_driver(TEXT);
+DROP TABLE IF EXISTS vehicle_driver_assignments;
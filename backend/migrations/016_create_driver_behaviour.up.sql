@@ -0,0 +1,111 @@
+-- Create vehicle_driver_assignments table
+-- This table records the values vehicles.assigned_to held over time, so telematics samples
+-- can be attributed to the driver of the vehicle when they were taken
+
+CREATE TABLE IF NOT EXISTS vehicle_driver_assignments (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    assigned_to VARCHAR(100), -- vehicles.assigned_to, NULL while unassigned
+    driver_id INTEGER REFERENCES users(id) ON DELETE SET NULL, -- user named by assigned_to
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP -- NULL for the current assignment
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_driver_assignments_vehicle ON vehicle_driver_assignments(vehicle_id, started_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vehicle_driver_assignments_current ON vehicle_driver_assignments(vehicle_id) WHERE ended_at IS NULL;
+
+-- assigned_to holds a username or a numeric user ID
+CREATE OR REPLACE FUNCTION resolve_assigned_driver(assignee TEXT)
+RETURNS INTEGER AS $$
+    SELECT id FROM users WHERE username = assignee OR id::text = assignee ORDER BY id LIMIT 1;
+$$ language 'sql' STABLE;
+
+-- Record every change of vehicles.assigned_to, whichever code path made it
+CREATE OR REPLACE FUNCTION record_vehicle_driver_assignment()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.assigned_to IS NOT DISTINCT FROM OLD.assigned_to THEN
+        RETURN NEW;
+    END IF;
+    UPDATE vehicle_driver_assignments
+    SET ended_at = CURRENT_TIMESTAMP
+    WHERE vehicle_id = NEW.id AND ended_at IS NULL;
+    INSERT INTO vehicle_driver_assignments (vehicle_id, assigned_to, driver_id, started_at)
+    VALUES (NEW.id, NULLIF(NEW.assigned_to, ''), resolve_assigned_driver(NULLIF(NEW.assigned_to, '')), CURRENT_TIMESTAMP);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_vehicle_driver_assignment_trigger
+    AFTER INSERT OR UPDATE OF assigned_to ON vehicles
+    FOR EACH ROW
+    EXECUTE FUNCTION record_vehicle_driver_assignment();
+
+-- Without earlier history the current assignee is taken to have driven since the vehicle was created
+INSERT INTO vehicle_driver_assignments (vehicle_id, assigned_to, driver_id, started_at)
+SELECT v.id, NULLIF(v.assigned_to, ''), resolve_assigned_driver(NULLIF(v.assigned_to, '')), COALESCE(v.created_at, CURRENT_TIMESTAMP)
+FROM vehicles v
+WHERE NOT EXISTS (SELECT 1 FROM vehicle_driver_assignments a WHERE a.vehicle_id = v.id);
+
+-- Create driver_trip_behaviours table
+-- Behaviour metrics per trip and driver. Rows are derived from trips and removed with them;
+-- a trip spanning a change of assignee has one row per driver.
+
+CREATE TABLE IF NOT EXISTS driver_trip_behaviours (
+    id BIGSERIAL PRIMARY KEY,
+    trip_id BIGINT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    driver_id INTEGER REFERENCES users(id) ON DELETE SET NULL, -- NULL when the assignee is not a user
+    assigned_to VARCHAR(100),
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    week_start DATE NOT NULL, -- Monday of the week in the settings timezone
+    distance_km DECIMAL(10, 3) NOT NULL DEFAULT 0,
+    driving_seconds INTEGER NOT NULL DEFAULT 0,
+    harsh_accelerations INTEGER NOT NULL DEFAULT 0,
+    harsh_brakings INTEGER NOT NULL DEFAULT 0,
+    speeding_events INTEGER NOT NULL DEFAULT 0,
+    speeding_seconds INTEGER NOT NULL DEFAULT 0,
+    excessive_idle_seconds INTEGER NOT NULL DEFAULT 0,
+    night_seconds INTEGER NOT NULL DEFAULT 0,
+    score DECIMAL(4, 1) NOT NULL DEFAULT 100,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_driver_trip_behaviours_trip ON driver_trip_behaviours(trip_id);
+CREATE INDEX IF NOT EXISTS idx_driver_trip_behaviours_driver_week ON driver_trip_behaviours(driver_id, week_start);
+CREATE INDEX IF NOT EXISTS idx_driver_trip_behaviours_week ON driver_trip_behaviours(week_start);
+
+-- Create driver_behaviour_settings table
+-- A single row of scoring thresholds and weights
+
+CREATE TABLE IF NOT EXISTS driver_behaviour_settings (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    default_speed_limit DECIMAL(5, 1) NOT NULL DEFAULT 100, -- km/h
+    speed_limits JSONB NOT NULL DEFAULT '{}', -- vehicle type -> km/h
+    speeding_tolerance DECIMAL(5, 1) NOT NULL DEFAULT 5,
+    harsh_accel_threshold DECIMAL(5, 2) NOT NULL DEFAULT 12, -- km/h per second, about 0.34 g
+    harsh_brake_threshold DECIMAL(5, 2) NOT NULL DEFAULT 14, -- km/h per second, about 0.4 g
+    harsh_max_interval_seconds INTEGER NOT NULL DEFAULT 5,
+    idle_threshold_seconds INTEGER NOT NULL DEFAULT 120,
+    night_start_hour INTEGER NOT NULL DEFAULT 22 CHECK (night_start_hour BETWEEN 0 AND 23),
+    night_end_hour INTEGER NOT NULL DEFAULT 5 CHECK (night_end_hour BETWEEN 0 AND 23),
+    timezone VARCHAR(50) NOT NULL DEFAULT 'UTC',
+    harsh_accel_weight DECIMAL(6, 2) NOT NULL DEFAULT 2,
+    harsh_brake_weight DECIMAL(6, 2) NOT NULL DEFAULT 3,
+    speeding_weight DECIMAL(6, 2) NOT NULL DEFAULT 1,
+    idle_weight DECIMAL(6, 2) NOT NULL DEFAULT 0.5,
+    night_weight DECIMAL(6, 2) NOT NULL DEFAULT 0.2,
+    leaderboard_min_distance_km DECIMAL(8, 1) NOT NULL DEFAULT 10,
+    updated_by INTEGER REFERENCES users(id),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_driver_behaviour_settings_updated_at
+    BEFORE UPDATE ON driver_behaviour_settings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO driver_behaviour_settings (id, speed_limits)
+VALUES (1, '{"truck": 80, "bus": 80, "motorcycle": 80}')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,6 @@
+-- Drop fuel analytics migration
+DROP TABLE IF EXISTS trip_fuel_consumptions;
+DROP TABLE IF EXISTS fuel_anomalies;
+DROP TABLE IF EXISTS fuel_receipts;
+DROP TABLE IF EXISTS fuel_events;
+DROP TABLE IF EXISTS vehicle_fuel_profiles;
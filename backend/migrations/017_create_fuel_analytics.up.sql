@@ -0,0 +1,122 @@
+-- Create vehicle_fuel_profiles table
+-- Tank capacities turn fuel level percentages into litres
+
+CREATE TABLE IF NOT EXISTS vehicle_fuel_profiles (
+    vehicle_id INTEGER PRIMARY KEY REFERENCES vehicles(id) ON DELETE CASCADE,
+    tank_capacity_liters DECIMAL(8, 2) NOT NULL CHECK (tank_capacity_liters > 0),
+    updated_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_vehicle_fuel_profiles_updated_at
+    BEFORE UPDATE ON vehicle_fuel_profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create fuel_events table
+-- Refuels and sudden drops detected in the filtered fuel level series. Events are re-detected
+-- when late samples arrive; matching events are updated in place so references stay valid.
+
+CREATE TABLE IF NOT EXISTS fuel_events (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('refuel', 'drop')),
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NOT NULL,
+    level_before DECIMAL(5, 2) NOT NULL,
+    level_after DECIMAL(5, 2) NOT NULL,
+    change_percent DECIMAL(5, 2) NOT NULL,
+    liters DECIMAL(8, 2), -- NULL without a fuel profile
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fuel_events_vehicle_started ON fuel_events(vehicle_id, started_at);
+
+CREATE TRIGGER update_fuel_events_updated_at
+    BEFORE UPDATE ON fuel_events
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create fuel_receipts table
+-- Manually entered fuel purchases, reconciled against detected refuels
+
+CREATE TABLE IF NOT EXISTS fuel_receipts (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    filled_at TIMESTAMP NOT NULL,
+    liters DECIMAL(8, 2) NOT NULL CHECK (liters > 0),
+    total_cost DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    odometer INTEGER,
+    station VARCHAR(100),
+    receipt_number VARCHAR(50),
+    notes TEXT,
+    entered_by INTEGER NOT NULL REFERENCES users(id),
+    fuel_event_id INTEGER REFERENCES fuel_events(id) ON DELETE SET NULL,
+    detected_liters DECIMAL(8, 2),
+    reconciliation_status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (reconciliation_status IN ('pending', 'matched', 'mismatch', 'unmatched', 'no_data')),
+    reconciled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_fuel_receipts_vehicle_filled ON fuel_receipts(vehicle_id, filled_at);
+CREATE INDEX IF NOT EXISTS idx_fuel_receipts_status ON fuel_receipts(reconciliation_status);
+
+CREATE TRIGGER update_fuel_receipts_updated_at
+    BEFORE UPDATE ON fuel_receipts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create fuel_anomalies table
+-- Findings awaiting review; one open finding per event or receipt and type
+
+CREATE TABLE IF NOT EXISTS fuel_anomalies (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('sudden_drop', 'receipt_mismatch', 'unmatched_receipt')),
+    fuel_event_id INTEGER REFERENCES fuel_events(id) ON DELETE CASCADE,
+    fuel_receipt_id INTEGER REFERENCES fuel_receipts(id) ON DELETE CASCADE,
+    occurred_at TIMESTAMP NOT NULL,
+    liters DECIMAL(8, 2),
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'confirmed', 'dismissed')),
+    reviewed_by INTEGER REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    review_note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (fuel_event_id IS NOT NULL OR fuel_receipt_id IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fuel_anomalies_event_type ON fuel_anomalies(fuel_event_id, type) WHERE fuel_event_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fuel_anomalies_receipt_type ON fuel_anomalies(fuel_receipt_id, type) WHERE fuel_receipt_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_fuel_anomalies_status ON fuel_anomalies(status, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_fuel_anomalies_vehicle ON fuel_anomalies(vehicle_id, occurred_at);
+
+CREATE TRIGGER update_fuel_anomalies_updated_at
+    BEFORE UPDATE ON fuel_anomalies
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create trip_fuel_consumptions table
+-- Fuel used per trip; rows are derived from trips and removed with them
+
+CREATE TABLE IF NOT EXISTS trip_fuel_consumptions (
+    id BIGSERIAL PRIMARY KEY,
+    trip_id BIGINT NOT NULL UNIQUE REFERENCES trips(id) ON DELETE CASCADE,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    distance_km DECIMAL(10, 3) NOT NULL DEFAULT 0,
+    fuel_used_percent DECIMAL(6, 2) NOT NULL DEFAULT 0,
+    fuel_used_liters DECIMAL(8, 2), -- NULL without a fuel profile
+    liters_per_100km DECIMAL(6, 2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_fuel_consumptions_vehicle_start ON trip_fuel_consumptions(vehicle_id, start_time);
@@ -0,0 +1,40 @@
+-- Revert telematics partitioning; raw samples already dropped by retention are not restored
+DROP TABLE IF EXISTS telematics_compaction_queue;
+DROP TABLE IF EXISTS telematics_aggregates_1h;
+DROP TABLE IF EXISTS telematics_aggregates_1m;
+
+ALTER TABLE telematics_data RENAME TO telematics_data_partitioned;
+ALTER INDEX IF EXISTS idx_telematics_data_device_timestamp RENAME TO idx_telematics_data_partitioned_device_timestamp;
+ALTER INDEX IF EXISTS idx_telematics_data_vehicle_timestamp RENAME TO idx_telematics_data_partitioned_vehicle_timestamp;
+
+CREATE TABLE telematics_data (
+    id BIGINT PRIMARY KEY DEFAULT nextval('telematics_data_id_seq'),
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    speed DECIMAL(5, 2),
+    heading DECIMAL(5, 2),
+    altitude DECIMAL(8, 2),
+    engine_status VARCHAR(20),
+    fuel_level DECIMAL(5, 2),
+    engine_temperature DECIMAL(5, 2),
+    oil_pressure DECIMAL(6, 2),
+    battery_voltage DECIMAL(5, 2),
+    engine_rpm INTEGER,
+    total_distance DECIMAL(10, 2),
+    fuel_consumption DECIMAL(6, 2),
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    device_id VARCHAR(50)
+);
+
+ALTER SEQUENCE telematics_data_id_seq OWNED BY telematics_data.id;
+
+INSERT INTO telematics_data SELECT * FROM telematics_data_partitioned;
+DROP TABLE telematics_data_partitioned;
+
+CREATE INDEX IF NOT EXISTS idx_telematics_data_vehicle_id ON telematics_data(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_telematics_data_timestamp ON telematics_data(timestamp);
+CREATE INDEX IF NOT EXISTS idx_telematics_data_location ON telematics_data(latitude, longitude);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_telematics_data_device_timestamp ON telematics_data(device_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_telematics_data_vehicle_timestamp ON telematics_data(vehicle_id, timestamp DESC);
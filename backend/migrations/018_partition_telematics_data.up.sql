@@ -0,0 +1,116 @@
+-- Partition telematics_data by day
+-- Daily partitions are created ahead of time and dropped after the raw retention period by the
+-- application's telematics maintenance job. Samples outside the existing partitions land in
+-- the default partition and are moved out when their partition is created.
+
+ALTER TABLE telematics_data RENAME TO telematics_data_legacy;
+ALTER INDEX IF EXISTS idx_telematics_data_device_timestamp RENAME TO idx_telematics_data_legacy_device_timestamp;
+ALTER INDEX IF EXISTS idx_telematics_data_vehicle_timestamp RENAME TO idx_telematics_data_legacy_vehicle_timestamp;
+
+CREATE TABLE telematics_data (
+    id BIGINT NOT NULL DEFAULT nextval('telematics_data_id_seq'),
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    speed DECIMAL(5, 2), -- km/h
+    heading DECIMAL(5, 2), -- degrees
+    altitude DECIMAL(8, 2), -- meters
+    engine_status VARCHAR(20), -- on, off, idle, etc.
+    fuel_level DECIMAL(5, 2), -- percentage
+    engine_temperature DECIMAL(5, 2), -- celsius
+    oil_pressure DECIMAL(6, 2), -- kPa
+    battery_voltage DECIMAL(5, 2), -- volts
+    engine_rpm INTEGER,
+    total_distance DECIMAL(10, 2), -- km
+    fuel_consumption DECIMAL(6, 2), -- L/100km
+    timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    device_id VARCHAR(50),
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+ALTER SEQUENCE telematics_data_id_seq OWNED BY telematics_data.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_telematics_data_device_timestamp ON telematics_data(device_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_telematics_data_vehicle_timestamp ON telematics_data(vehicle_id, timestamp DESC);
+
+CREATE TABLE IF NOT EXISTS telematics_data_default PARTITION OF telematics_data DEFAULT;
+
+-- One partition per day holding data, and for the coming week
+DO $$
+DECLARE
+    first_day DATE;
+    day DATE;
+BEGIN
+    SELECT COALESCE(MIN(timestamp)::date, CURRENT_DATE) INTO first_day FROM telematics_data_legacy;
+    day := LEAST(first_day, CURRENT_DATE);
+    WHILE day <= CURRENT_DATE + 7 LOOP
+        IF day >= CURRENT_DATE OR EXISTS (
+            SELECT 1 FROM telematics_data_legacy WHERE timestamp >= day AND timestamp < day + 1
+        ) THEN
+            EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF telematics_data FOR VALUES FROM (%L) TO (%L)',
+                'telematics_data_p' || to_char(day, 'YYYYMMDD'), day, day + 1);
+        END IF;
+        day := day + 1;
+    END LOOP;
+END $$;
+
+INSERT INTO telematics_data (id, vehicle_id, latitude, longitude, speed, heading, altitude, engine_status,
+    fuel_level, engine_temperature, oil_pressure, battery_voltage, engine_rpm, total_distance,
+    fuel_consumption, timestamp, created_at, device_id)
+SELECT id, vehicle_id, latitude, longitude, speed, heading, altitude, engine_status,
+    fuel_level, engine_temperature, oil_pressure, battery_voltage, engine_rpm, total_distance,
+    fuel_consumption, COALESCE(timestamp, created_at, CURRENT_TIMESTAMP), created_at, device_id
+FROM telematics_data_legacy;
+
+DROP TABLE telematics_data_legacy;
+
+-- Create telematics aggregate tables
+-- Downsampled series kept after raw samples are dropped. Buckets are recomputed from raw
+-- samples whole, so late samples are folded in. Positions are the last fix in the bucket.
+
+CREATE TABLE IF NOT EXISTS telematics_aggregates_1m (
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    bucket TIMESTAMP NOT NULL,
+    sample_count INTEGER NOT NULL,
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    avg_speed DECIMAL(5, 2),
+    max_speed DECIMAL(5, 2),
+    engine_on_samples INTEGER NOT NULL DEFAULT 0,
+    fuel_level DECIMAL(5, 2),
+    engine_temperature DECIMAL(5, 2),
+    max_engine_temperature DECIMAL(5, 2),
+    oil_pressure DECIMAL(6, 2),
+    battery_voltage DECIMAL(5, 2),
+    min_battery_voltage DECIMAL(5, 2),
+    engine_rpm INTEGER,
+    max_engine_rpm INTEGER,
+    total_distance DECIMAL(10, 2),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (vehicle_id, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_telematics_aggregates_1m_bucket ON telematics_aggregates_1m(bucket);
+
+CREATE TABLE IF NOT EXISTS telematics_aggregates_1h (LIKE telematics_aggregates_1m INCLUDING ALL);
+ALTER TABLE telematics_aggregates_1h ADD FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE CASCADE;
+
+-- Create telematics_compaction_queue table
+-- Vehicle hours with new raw samples awaiting aggregation. Ingestion touches queued_at on
+-- every batch, so an hour aggregated while samples arrive is aggregated again.
+
+CREATE TABLE IF NOT EXISTS telematics_compaction_queue (
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    hour TIMESTAMP NOT NULL,
+    queued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (vehicle_id, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_telematics_compaction_queue_hour ON telematics_compaction_queue(hour);
+
+-- Aggregate existing samples on the first maintenance run
+INSERT INTO telematics_compaction_queue (vehicle_id, hour)
+SELECT DISTINCT vehicle_id, date_trunc('hour', timestamp)
+FROM telematics_data
+ON CONFLICT DO NOTHING;
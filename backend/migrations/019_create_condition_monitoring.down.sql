@@ -0,0 +1,4 @@
+-- Drop condition monitoring tables
+DROP TABLE IF EXISTS condition_alerts;
+DROP TABLE IF EXISTS condition_vehicle_states;
+DROP TABLE IF EXISTS condition_rules;
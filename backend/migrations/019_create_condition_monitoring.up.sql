@@ -0,0 +1,90 @@
+-- Create condition_rules table
+-- Sensor thresholds evaluated during ingestion. An alert is raised once a reading stays beyond
+-- the threshold for duration_seconds and cleared when it is back past clear_threshold.
+
+CREATE TABLE IF NOT EXISTS condition_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    vehicle_type VARCHAR(50), -- NULL applies to every vehicle type
+    metric VARCHAR(30) NOT NULL CHECK (metric IN ('engine_temperature', 'oil_pressure', 'battery_voltage', 'engine_rpm')),
+    operator VARCHAR(10) NOT NULL CHECK (operator IN ('above', 'below')),
+    threshold DECIMAL(10, 2) NOT NULL,
+    clear_threshold DECIMAL(10, 2) NOT NULL,
+    duration_seconds INTEGER NOT NULL DEFAULT 0 CHECK (duration_seconds >= 0),
+    engine_state VARCHAR(10) NOT NULL DEFAULT 'any' CHECK (engine_state IN ('any', 'running', 'off')),
+    severity VARCHAR(20) NOT NULL CHECK (severity IN ('warning', 'critical')),
+    create_work_order BOOLEAN NOT NULL DEFAULT false,
+    recipient_user_ids JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((operator = 'above' AND clear_threshold <= threshold) OR (operator = 'below' AND clear_threshold >= threshold))
+);
+
+CREATE TRIGGER update_condition_rules_updated_at
+    BEFORE UPDATE ON condition_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create condition_vehicle_states table
+-- Pending breaches per rule and vehicle
+
+CREATE TABLE IF NOT EXISTS condition_vehicle_states (
+    rule_id INTEGER NOT NULL REFERENCES condition_rules(id) ON DELETE CASCADE,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    breach_started_at TIMESTAMP,
+    peak_value DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    sample_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rule_id, vehicle_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_condition_vehicle_states_vehicle ON condition_vehicle_states(vehicle_id);
+
+-- Create condition_alerts table
+
+CREATE TABLE IF NOT EXISTS condition_alerts (
+    id SERIAL PRIMARY KEY,
+    rule_id INTEGER REFERENCES condition_rules(id) ON DELETE SET NULL,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    metric VARCHAR(30) NOT NULL,
+    operator VARCHAR(10) NOT NULL,
+    threshold DECIMAL(10, 2) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cleared')),
+    started_at TIMESTAMP NOT NULL,
+    triggered_at TIMESTAMP NOT NULL,
+    trigger_value DECIMAL(10, 2) NOT NULL,
+    peak_value DECIMAL(10, 2) NOT NULL,
+    cleared_at TIMESTAMP,
+    clear_value DECIMAL(10, 2),
+    acknowledged_by INTEGER REFERENCES users(id),
+    acknowledged_at TIMESTAMP,
+    note TEXT,
+    work_order_id INTEGER REFERENCES work_orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- At most one active alert per rule and vehicle
+CREATE UNIQUE INDEX IF NOT EXISTS idx_condition_alerts_rule_vehicle_active ON condition_alerts(rule_id, vehicle_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_condition_alerts_vehicle_triggered ON condition_alerts(vehicle_id, triggered_at);
+CREATE INDEX IF NOT EXISTS idx_condition_alerts_status_triggered ON condition_alerts(status, triggered_at);
+CREATE INDEX IF NOT EXISTS idx_condition_alerts_work_order_id ON condition_alerts(work_order_id);
+
+CREATE TRIGGER update_condition_alerts_updated_at
+    BEFORE UPDATE ON condition_alerts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Default rules
+INSERT INTO condition_rules (name, description, metric, operator, threshold, clear_threshold, duration_seconds, engine_state, severity, create_work_order)
+SELECT * FROM (VALUES
+    ('Coolant overheating', 'Engine coolant above 110 °C for 2 minutes', 'engine_temperature', 'above', 110.00, 105.00, 120, 'running', 'critical', true),
+    ('Weak battery at rest', 'Battery below 11.8 V with the ignition off', 'battery_voltage', 'below', 11.80, 12.20, 60, 'off', 'warning', false),
+    ('Engine over-revving', 'Engine speed above 6000 rpm for 30 seconds', 'engine_rpm', 'above', 6000.00, 5500.00, 30, 'running', 'warning', false)
+) AS defaults (name, description, metric, operator, threshold, clear_threshold, duration_seconds, engine_state, severity, create_work_order)
+WHERE NOT EXISTS (SELECT 1 FROM condition_rules);
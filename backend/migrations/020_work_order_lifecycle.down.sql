@@ -0,0 +1,19 @@
+-- Restore trigger based status tracking
+DROP INDEX IF EXISTS idx_work_orders_created_at;
+ALTER TABLE work_orders DROP CONSTRAINT IF EXISTS chk_work_orders_status;
+
+CREATE OR REPLACE FUNCTION track_work_order_status_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.status IS DISTINCT FROM NEW.status THEN
+        INSERT INTO work_order_status_history (work_order_id, old_status, new_status, changed_by, notes)
+        VALUES (NEW.id, OLD.status, NEW.status, NEW.service_advisor_id, 'Status changed');
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER track_work_order_status_change_trigger
+    AFTER UPDATE ON work_orders
+    FOR EACH ROW
+    EXECUTE FUNCTION track_work_order_status_change();
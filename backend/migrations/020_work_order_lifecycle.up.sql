@@ -0,0 +1,20 @@
+-- Work order lifecycle
+-- Status changes are made by the work order service, which validates each transition and
+-- records the acting user and a note. The trigger from 005 attributed every change to the
+-- service advisor and would duplicate those entries, so it is removed.
+
+DROP TRIGGER IF EXISTS track_work_order_status_change_trigger ON work_orders;
+DROP FUNCTION IF EXISTS track_work_order_status_change();
+
+-- Work orders created before the service existed get their initial entry
+INSERT INTO work_order_status_history (work_order_id, old_status, new_status, changed_by, notes, changed_at)
+SELECT wo.id, NULL, 'pending', wo.service_advisor_id, 'Work order created', wo.created_at
+FROM work_orders wo
+WHERE NOT EXISTS (
+    SELECT 1 FROM work_order_status_history h WHERE h.work_order_id = wo.id
+);
+
+ALTER TABLE work_orders ADD CONSTRAINT chk_work_orders_status
+    CHECK (status IN ('pending', 'assigned', 'in_progress', 'on_hold', 'waiting_for_parts', 'completed', 'cancelled'));
+
+CREATE INDEX IF NOT EXISTS idx_work_orders_created_at ON work_orders(created_at);
@@ -0,0 +1,9 @@
+-- Drop service requests and areas
+DROP INDEX IF EXISTS idx_work_orders_service_request_id;
+ALTER TABLE work_orders DROP COLUMN IF EXISTS branch_id;
+ALTER TABLE work_orders DROP COLUMN IF EXISTS service_request_id;
+DROP TABLE IF EXISTS service_requests;
+DROP INDEX IF EXISTS idx_vehicles_area_id;
+ALTER TABLE warehouses DROP COLUMN IF EXISTS area_id;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS area_id;
+DROP TABLE IF EXISTS service_areas;
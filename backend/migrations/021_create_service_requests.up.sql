@@ -0,0 +1,77 @@
+-- Create service_areas table
+-- Vehicles and workshop branches belong to an area run by an Area Manager, who reviews the
+-- service requests raised for the area's vehicles.
+
+CREATE TABLE IF NOT EXISTS service_areas (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    manager_id INTEGER REFERENCES users(id),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS area_id INTEGER REFERENCES service_areas(id) ON DELETE SET NULL;
+ALTER TABLE warehouses ADD COLUMN IF NOT EXISTS area_id INTEGER REFERENCES service_areas(id) ON DELETE SET NULL;
+
+-- One area per branch location
+INSERT INTO service_areas (name, description)
+SELECT DISTINCT location, 'Created from branch warehouse locations'
+FROM warehouses
+WHERE type = 'branch' AND location IS NOT NULL
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE warehouses w SET area_id = a.id
+FROM service_areas a
+WHERE w.type = 'branch' AND w.location = a.name AND w.area_id IS NULL;
+
+-- Create service_requests table
+-- Service requests (PS) raised by drivers and coordinators. Submitted requests are approved or
+-- rejected by the area's manager, allocated to a workshop branch and converted into a work order.
+
+CREATE TABLE IF NOT EXISTS service_requests (
+    id SERIAL PRIMARY KEY,
+    request_number VARCHAR(30) UNIQUE NOT NULL,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id),
+    area_id INTEGER REFERENCES service_areas(id) ON DELETE SET NULL,
+    requested_by INTEGER NOT NULL REFERENCES users(id),
+    symptoms TEXT NOT NULL,
+    photo_urls JSONB NOT NULL DEFAULT '[]',
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    location_description TEXT,
+    odometer INTEGER NOT NULL DEFAULT 0,
+    urgency VARCHAR(20) NOT NULL DEFAULT 'normal' CHECK (urgency IN ('low', 'normal', 'high', 'critical')),
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted'
+        CHECK (status IN ('submitted', 'approved', 'rejected', 'allocated', 'converted', 'cancelled')),
+    reviewed_by INTEGER REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    review_note TEXT,
+    branch_id INTEGER REFERENCES warehouses(id),
+    allocated_by INTEGER REFERENCES users(id),
+    allocated_at TIMESTAMP,
+    work_order_id INTEGER REFERENCES work_orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Work orders keep a reference to the request they were converted from and the branch doing the work
+ALTER TABLE work_orders ADD COLUMN IF NOT EXISTS service_request_id INTEGER REFERENCES service_requests(id) ON DELETE SET NULL;
+ALTER TABLE work_orders ADD COLUMN IF NOT EXISTS branch_id INTEGER REFERENCES warehouses(id);
+
+CREATE INDEX IF NOT EXISTS idx_vehicles_area_id ON vehicles(area_id);
+CREATE INDEX IF NOT EXISTS idx_service_requests_vehicle_id ON service_requests(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_service_requests_area_status ON service_requests(area_id, status);
+CREATE INDEX IF NOT EXISTS idx_service_requests_requested_by ON service_requests(requested_by);
+CREATE INDEX IF NOT EXISTS idx_work_orders_service_request_id ON work_orders(service_request_id);
+
+CREATE TRIGGER update_service_areas_updated_at
+    BEFORE UPDATE ON service_areas
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_service_requests_updated_at
+    BEFORE UPDATE ON service_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
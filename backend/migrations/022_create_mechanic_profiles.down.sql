@@ -0,0 +1,4 @@
+-- Drop mechanic profile tables
+DROP TABLE IF EXISTS mechanic_shifts;
+DROP TABLE IF EXISTS mechanic_skills;
+DROP TABLE IF EXISTS mechanic_profiles;
@@ -0,0 +1,49 @@
+-- Create mechanic_profiles table
+-- Branch, capacity and availability of the users with the Mechanic role
+
+CREATE TABLE IF NOT EXISTS mechanic_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    branch_id INTEGER REFERENCES warehouses(id) ON DELETE SET NULL,
+    daily_capacity_hours DECIMAL(4, 2) NOT NULL DEFAULT 8 CHECK (daily_capacity_hours > 0 AND daily_capacity_hours <= 24),
+    is_available BOOLEAN NOT NULL DEFAULT true,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create mechanic_skills table
+-- Skills and certifications per service type and vehicle type; NULL types cover every type
+
+CREATE TABLE IF NOT EXISTS mechanic_skills (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES mechanic_profiles(user_id) ON DELETE CASCADE,
+    service_type VARCHAR(30),
+    vehicle_type VARCHAR(50),
+    level INTEGER NOT NULL DEFAULT 1 CHECK (level BETWEEN 1 AND 3),
+    certification VARCHAR(100),
+    certified_until TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create mechanic_shifts table
+-- Scheduled working periods; a mechanic's shifts do not overlap
+
+CREATE TABLE IF NOT EXISTS mechanic_shifts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    note TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mechanic_profiles_branch_id ON mechanic_profiles(branch_id);
+CREATE INDEX IF NOT EXISTS idx_mechanic_skills_user_id ON mechanic_skills(user_id);
+CREATE INDEX IF NOT EXISTS idx_mechanic_shifts_user_starts ON mechanic_shifts(user_id, starts_at);
+
+CREATE TRIGGER update_mechanic_profiles_updated_at
+    BEFORE UPDATE ON mechanic_profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
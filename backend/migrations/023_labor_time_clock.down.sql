@@ -0,0 +1,6 @@
+-- Drop the labor time clock
+DROP INDEX IF EXISTS uniq_work_order_labor_task_day;
+ALTER TABLE work_order_labor DROP COLUMN IF EXISTS task_id;
+DROP TABLE IF EXISTS labor_time_entries;
+DROP TABLE IF EXISTS work_order_tasks;
+ALTER TABLE mechanic_profiles DROP COLUMN IF EXISTS hourly_rate;
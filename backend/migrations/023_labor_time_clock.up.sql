@@ -0,0 +1,45 @@
+-- Labor time clock
+-- Mechanics clock in, pause and clock out on work order tasks. Finished clock entries roll up into
+-- work_order_labor per task, mechanic and day, which in turn sets the work order's actual hours.
+
+ALTER TABLE mechanic_profiles ADD COLUMN IF NOT EXISTS hourly_rate DECIMAL(10, 2) CHECK (hourly_rate >= 0); -- NULL uses the workshop default
+
+CREATE TABLE IF NOT EXISTS work_order_tasks (
+    id SERIAL PRIMARY KEY,
+    work_order_id INTEGER NOT NULL REFERENCES work_orders(id) ON DELETE CASCADE,
+    description VARCHAR(200) NOT NULL,
+    estimated_hours DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'completed')),
+    completed_at TIMESTAMP,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS labor_time_entries (
+    id SERIAL PRIMARY KEY,
+    work_order_id INTEGER NOT NULL REFERENCES work_orders(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES work_order_tasks(id) ON DELETE CASCADE,
+    mechanic_id INTEGER NOT NULL REFERENCES users(id),
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    end_reason VARCHAR(20) CHECK (end_reason IN ('paused', 'clocked_out', 'stopped')),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+ALTER TABLE work_order_labor ADD COLUMN IF NOT EXISTS task_id INTEGER REFERENCES work_order_tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_work_order_tasks_work_order ON work_order_tasks(work_order_id);
+CREATE INDEX IF NOT EXISTS idx_labor_time_entries_work_order ON labor_time_entries(work_order_id);
+CREATE INDEX IF NOT EXISTS idx_labor_time_entries_mechanic_started ON labor_time_entries(mechanic_id, started_at);
+-- A mechanic has at most one running clock
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_labor_time_entries_active_mechanic ON labor_time_entries(mechanic_id) WHERE ended_at IS NULL;
+-- One rolled up labor line per task, mechanic and day; lines without a task are entered by hand
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_work_order_labor_task_day ON work_order_labor(work_order_id, task_id, mechanic_id, work_date) WHERE task_id IS NOT NULL;
+
+CREATE TRIGGER update_work_order_tasks_updated_at
+    BEFORE UPDATE ON work_order_tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+-- Drop work order parts reservation
+DROP TABLE IF EXISTS stock_transfer_requests;
+DROP INDEX IF EXISTS idx_work_order_parts_status;
+ALTER TABLE work_order_parts DROP CONSTRAINT IF EXISTS chk_work_order_parts_quantity;
+ALTER TABLE work_order_parts ALTER COLUMN used_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE work_order_parts DROP COLUMN IF EXISTS removed_at;
+ALTER TABLE work_order_parts DROP COLUMN IF EXISTS reserved_quantity;
+ALTER TABLE work_order_parts DROP COLUMN IF EXISTS status;
+ALTER TABLE mechanic_profiles DROP COLUMN IF EXISTS warehouse_id;
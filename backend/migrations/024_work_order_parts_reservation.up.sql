@@ -0,0 +1,45 @@
+-- Work order parts reservation
+-- Parts added to a work order are reserved in inventory_stock at the mechanic's small warehouse,
+-- turned into usage transactions when issued and put back when removed. Shortfalls raise stock
+-- transfer requests to the warehouse.
+
+-- The small warehouse or van stock a mechanic draws parts from
+ALTER TABLE mechanic_profiles ADD COLUMN IF NOT EXISTS warehouse_id INTEGER REFERENCES warehouses(id) ON DELETE SET NULL;
+
+-- Lines from before reservations were tracked are treated as issued
+ALTER TABLE work_order_parts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'issued'
+    CHECK (status IN ('reserved', 'short', 'issued', 'released', 'returned'));
+ALTER TABLE work_order_parts ADD COLUMN IF NOT EXISTS reserved_quantity INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE work_order_parts ADD COLUMN IF NOT EXISTS removed_at TIMESTAMP;
+ALTER TABLE work_order_parts ALTER COLUMN used_at DROP DEFAULT; -- set when the part is issued
+ALTER TABLE work_order_parts ADD CONSTRAINT chk_work_order_parts_quantity
+    CHECK (quantity > 0 AND reserved_quantity >= 0 AND reserved_quantity <= quantity);
+
+CREATE TABLE IF NOT EXISTS stock_transfer_requests (
+    id SERIAL PRIMARY KEY,
+    request_number VARCHAR(50) UNIQUE NOT NULL,
+    inventory_item_id INTEGER NOT NULL REFERENCES inventory_items(id),
+    from_warehouse_id INTEGER REFERENCES warehouses(id), -- suggested source until fulfilled
+    to_warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'requested' CHECK (status IN ('requested', 'fulfilled', 'cancelled')),
+    work_order_id INTEGER REFERENCES work_orders(id) ON DELETE SET NULL,
+    work_order_part_id INTEGER REFERENCES work_order_parts(id) ON DELETE SET NULL,
+    requested_by INTEGER NOT NULL REFERENCES users(id),
+    fulfilled_by INTEGER REFERENCES users(id),
+    fulfilled_at TIMESTAMP,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_warehouse_id IS NULL OR from_warehouse_id <> to_warehouse_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_order_parts_status ON work_order_parts(status);
+CREATE INDEX IF NOT EXISTS idx_stock_transfer_requests_status ON stock_transfer_requests(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_stock_transfer_requests_work_order ON stock_transfer_requests(work_order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_transfer_requests_part ON stock_transfer_requests(work_order_part_id);
+
+CREATE TRIGGER update_stock_transfer_requests_updated_at
+    BEFORE UPDATE ON stock_transfer_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+-- Drop work order estimates
+DROP TABLE IF EXISTS work_order_estimate_lines;
+DROP TABLE IF EXISTS work_order_estimates;
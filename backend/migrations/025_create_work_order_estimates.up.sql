@@ -0,0 +1,58 @@
+-- Work order estimates
+-- Versioned cost estimates of labor and parts lines that the customer approves or declines line by
+-- line through a shareable link, signing the decision. Once an estimate has been sent, work on the
+-- work order is limited to the approved amount until a revised estimate is approved.
+
+CREATE TABLE IF NOT EXISTS work_order_estimates (
+    id SERIAL PRIMARY KEY,
+    work_order_id INTEGER NOT NULL REFERENCES work_orders(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'sent', 'approved', 'partially_approved', 'declined', 'superseded')),
+    labor_subtotal DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    parts_subtotal DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    discount_amount DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (discount_amount >= 0),
+    tax_rate DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (tax_rate >= 0),
+    tax_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    total DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    approved_subtotal DECIMAL(12, 2) NOT NULL DEFAULT 0, -- approved lines after discount, before tax
+    approved_total DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    notes TEXT,
+    approval_token_hash VARCHAR(64) UNIQUE, -- SHA-256 of the approval link token
+    token_expires_at TIMESTAMP,
+    sent_at TIMESTAMP,
+    sent_by INTEGER REFERENCES users(id),
+    responded_at TIMESTAMP,
+    signer_name VARCHAR(200),
+    signature TEXT, -- image data URL captured from the customer
+    signer_ip VARCHAR(45),
+    signer_user_agent VARCHAR(500),
+    customer_comment TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (work_order_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS work_order_estimate_lines (
+    id SERIAL PRIMARY KEY,
+    estimate_id INTEGER NOT NULL REFERENCES work_order_estimates(id) ON DELETE CASCADE,
+    line_type VARCHAR(10) NOT NULL CHECK (line_type IN ('labor', 'part')),
+    description VARCHAR(200) NOT NULL,
+    inventory_item_id INTEGER REFERENCES inventory_items(id),
+    quantity DECIMAL(10, 2) NOT NULL CHECK (quantity > 0), -- hours for labor
+    unit_price DECIMAL(10, 2) NOT NULL CHECK (unit_price >= 0),
+    discount_percent DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (discount_percent BETWEEN 0 AND 100),
+    amount DECIMAL(12, 2) NOT NULL,
+    approved BOOLEAN, -- NULL until the customer decides
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    CHECK (line_type = 'labor' OR inventory_item_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_order_estimates_work_order ON work_order_estimates(work_order_id, version);
+CREATE INDEX IF NOT EXISTS idx_work_order_estimate_lines_estimate ON work_order_estimate_lines(estimate_id, sort_order);
+
+CREATE TRIGGER update_work_order_estimates_updated_at
+    BEFORE UPDATE ON work_order_estimates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- Drop labor operations
+ALTER TABLE work_order_estimate_lines DROP COLUMN IF EXISTS operation_id;
+DROP INDEX IF EXISTS idx_work_order_tasks_operation;
+ALTER TABLE work_order_tasks DROP COLUMN IF EXISTS standard_hours;
+ALTER TABLE work_order_tasks DROP COLUMN IF EXISTS operation_id;
+DROP TABLE IF EXISTS labor_operations;
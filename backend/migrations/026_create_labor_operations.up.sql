@@ -0,0 +1,29 @@
+-- Create labor_operations table
+-- Catalogue of standard labor operations with their flat-rate hours. Work order tasks and estimate
+-- labor lines can reference an operation; tasks keep the standard hours they were created with so
+-- recorded labor can be compared against them.
+
+CREATE TABLE IF NOT EXISTS labor_operations (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(30) NOT NULL UNIQUE,
+    description VARCHAR(200) NOT NULL,
+    vehicle_types JSONB NOT NULL DEFAULT '[]', -- empty applies to every vehicle type
+    makes JSONB NOT NULL DEFAULT '[]', -- empty applies to every make
+    standard_hours DECIMAL(5, 2) NOT NULL CHECK (standard_hours > 0),
+    default_price DECIMAL(10, 2) CHECK (default_price >= 0), -- flat-rate price, NULL charges the standard hours at the labor rate
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE work_order_tasks ADD COLUMN IF NOT EXISTS operation_id INTEGER REFERENCES labor_operations(id) ON DELETE SET NULL;
+ALTER TABLE work_order_tasks ADD COLUMN IF NOT EXISTS standard_hours DECIMAL(5, 2);
+ALTER TABLE work_order_estimate_lines ADD COLUMN IF NOT EXISTS operation_id INTEGER REFERENCES labor_operations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_labor_operations_active ON labor_operations(is_active);
+CREATE INDEX IF NOT EXISTS idx_work_order_tasks_operation ON work_order_tasks(operation_id);
+
+CREATE TRIGGER update_labor_operations_updated_at
+    BEFORE UPDATE ON labor_operations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
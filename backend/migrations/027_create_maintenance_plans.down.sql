@@ -0,0 +1,5 @@
+-- Drop maintenance plans
+DROP TRIGGER IF EXISTS update_vehicle_maintenance_schedules_updated_at ON vehicle_maintenance_schedules;
+DROP TRIGGER IF EXISTS update_maintenance_plans_updated_at ON maintenance_plans;
+DROP TABLE IF EXISTS vehicle_maintenance_schedules;
+DROP TABLE IF EXISTS maintenance_plans;
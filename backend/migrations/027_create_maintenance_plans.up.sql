@@ -0,0 +1,66 @@
+-- Create maintenance plan tables
+-- Preventive maintenance plans are due after a distance, a time or engine hours, whichever comes
+-- first. Every vehicle matching a plan gets a schedule tracking when the plan was last done and
+-- when it is next due; the scheduler opens a routine maintenance work order or sends a reminder
+-- once a schedule comes within the plan's lead.
+
+CREATE TABLE IF NOT EXISTS maintenance_plans (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    vehicle_type VARCHAR(50), -- NULL applies to every vehicle type
+    make VARCHAR(50), -- NULL applies to every make
+    model VARCHAR(50), -- NULL applies to every model
+    interval_km INTEGER CHECK (interval_km > 0),
+    interval_days INTEGER CHECK (interval_days > 0),
+    interval_engine_hours DECIMAL(8, 2) CHECK (interval_engine_hours > 0),
+    lead_km INTEGER NOT NULL DEFAULT 0 CHECK (lead_km >= 0),
+    lead_days INTEGER NOT NULL DEFAULT 0 CHECK (lead_days >= 0),
+    lead_engine_hours DECIMAL(8, 2) NOT NULL DEFAULT 0 CHECK (lead_engine_hours >= 0),
+    action VARCHAR(20) NOT NULL DEFAULT 'work_order' CHECK (action IN ('work_order', 'reminder')),
+    operation_id INTEGER REFERENCES labor_operations(id) ON DELETE SET NULL, -- task added to opened work orders
+    priority VARCHAR(20) NOT NULL DEFAULT 'normal',
+    recipient_user_ids JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (interval_km IS NOT NULL OR interval_days IS NOT NULL OR interval_engine_hours IS NOT NULL)
+);
+
+CREATE TABLE IF NOT EXISTS vehicle_maintenance_schedules (
+    id SERIAL PRIMARY KEY,
+    plan_id INTEGER NOT NULL REFERENCES maintenance_plans(id) ON DELETE CASCADE,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    last_done_at TIMESTAMP NOT NULL,
+    last_done_odometer INTEGER NOT NULL DEFAULT 0,
+    last_done_engine_hours DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    next_due_at TIMESTAMP,
+    next_due_odometer INTEGER,
+    next_due_engine_hours DECIMAL(10, 2),
+    current_odometer INTEGER NOT NULL DEFAULT 0,
+    current_engine_hours DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'ok' CHECK (status IN ('ok', 'due_soon', 'overdue')),
+    evaluated_at TIMESTAMP,
+    work_order_id INTEGER REFERENCES work_orders(id) ON DELETE SET NULL, -- open maintenance work order
+    notified_status VARCHAR(20), -- last status the recipients were told about
+    is_active BOOLEAN NOT NULL DEFAULT true, -- false once the vehicle no longer matches the plan
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (plan_id, vehicle_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_plans_active ON maintenance_plans(is_active);
+CREATE INDEX IF NOT EXISTS idx_vehicle_maintenance_schedules_vehicle ON vehicle_maintenance_schedules(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_vehicle_maintenance_schedules_status ON vehicle_maintenance_schedules(status) WHERE is_active;
+CREATE INDEX IF NOT EXISTS idx_vehicle_maintenance_schedules_work_order ON vehicle_maintenance_schedules(work_order_id);
+
+CREATE TRIGGER update_maintenance_plans_updated_at
+    BEFORE UPDATE ON maintenance_plans
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_vehicle_maintenance_schedules_updated_at
+    BEFORE UPDATE ON vehicle_maintenance_schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
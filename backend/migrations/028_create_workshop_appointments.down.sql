@@ -0,0 +1,5 @@
+-- Drop workshop appointments
+DROP TRIGGER IF EXISTS update_work_order_appointments_updated_at ON work_order_appointments;
+DROP TRIGGER IF EXISTS update_workshop_bays_updated_at ON workshop_bays;
+DROP TABLE IF EXISTS work_order_appointments;
+DROP TABLE IF EXISTS workshop_bays;
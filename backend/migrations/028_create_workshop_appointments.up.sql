@@ -0,0 +1,60 @@
+-- btree_gist lets the exclusion constraints below compare bay and mechanic IDs for equality
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Create workshop_bays table
+-- Bays and lifts of a workshop branch. Empty vehicle types accept every vehicle type.
+
+CREATE TABLE IF NOT EXISTS workshop_bays (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES warehouses(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    bay_type VARCHAR(20) NOT NULL DEFAULT 'bay' CHECK (bay_type IN ('bay', 'lift')),
+    vehicle_types JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (branch_id, name)
+);
+
+-- Create work_order_appointments table
+-- A work order booked on a bay for a period, with the mechanic assigned to the work order.
+-- A work order has at most one scheduled appointment, and the exclusion constraints keep
+-- scheduled appointments of a bay or a mechanic from overlapping.
+
+CREATE TABLE IF NOT EXISTS work_order_appointments (
+    id SERIAL PRIMARY KEY,
+    work_order_id INTEGER NOT NULL REFERENCES work_orders(id) ON DELETE CASCADE,
+    branch_id INTEGER NOT NULL REFERENCES warehouses(id),
+    bay_id INTEGER NOT NULL REFERENCES workshop_bays(id),
+    mechanic_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'completed', 'cancelled')),
+    notes TEXT,
+    cancel_reason TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at),
+    CONSTRAINT work_order_appointments_bay_overlap
+        EXCLUDE USING gist (bay_id WITH =, tsrange(starts_at, ends_at) WITH &&) WHERE (status = 'scheduled'),
+    CONSTRAINT work_order_appointments_mechanic_overlap
+        EXCLUDE USING gist (mechanic_id WITH =, tsrange(starts_at, ends_at) WITH &&) WHERE (status = 'scheduled')
+);
+
+CREATE INDEX IF NOT EXISTS idx_workshop_bays_branch ON workshop_bays(branch_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_work_order_appointments_scheduled ON work_order_appointments(work_order_id) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_work_order_appointments_bay ON work_order_appointments(bay_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_work_order_appointments_mechanic ON work_order_appointments(mechanic_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_work_order_appointments_branch ON work_order_appointments(branch_id, starts_at);
+
+CREATE TRIGGER update_workshop_bays_updated_at
+    BEFORE UPDATE ON workshop_bays
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_work_order_appointments_updated_at
+    BEFORE UPDATE ON work_order_appointments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
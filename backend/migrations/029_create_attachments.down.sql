@@ -0,0 +1,5 @@
+-- Drop attachments
+DROP INDEX IF EXISTS idx_work_order_photos_attachment;
+DELETE FROM work_order_photos WHERE attachment_id IS NOT NULL;
+ALTER TABLE work_order_photos DROP COLUMN IF EXISTS attachment_id;
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,28 @@
+-- Create attachments table
+-- Files uploaded to work orders, vehicles and inspections. The file and its thumbnail live in
+-- object storage under storage_key and thumbnail_key; only images have a thumbnail.
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id SERIAL PRIMARY KEY,
+    owner_type VARCHAR(30) NOT NULL CHECK (owner_type IN ('work_order', 'vehicle', 'inspection')),
+    owner_id INTEGER NOT NULL,
+    category VARCHAR(30) NOT NULL DEFAULT 'other',
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    checksum VARCHAR(64) NOT NULL, -- hex SHA-256 of the file
+    storage_key VARCHAR(500) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(500),
+    width INTEGER,
+    height INTEGER,
+    description VARCHAR(500),
+    uploaded_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_owner ON attachments(owner_type, owner_id);
+
+-- Work order photos uploaded as attachments point at them; deleting the attachment removes the photo
+ALTER TABLE work_order_photos ADD COLUMN IF NOT EXISTS attachment_id INTEGER REFERENCES attachments(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_work_order_photos_attachment ON work_order_photos(attachment_id) WHERE attachment_id IS NOT NULL;
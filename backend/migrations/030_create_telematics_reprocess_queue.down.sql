@@ -0,0 +1,2 @@
+-- Drop telematics_reprocess_queue table
+DROP TABLE IF EXISTS telematics_reprocess_queue;
@@ -0,0 +1,11 @@
+-- Create telematics_reprocess_queue table
+-- Vehicles whose samples changed and must be analysed again by a background worker, per
+-- consumer (trips, fuel). Whichever process stores the samples queues the vehicle; the workers
+-- of the API server take the queue, so the telematics gateway does not run them.
+
+CREATE TABLE IF NOT EXISTS telematics_reprocess_queue (
+    consumer VARCHAR(20) NOT NULL,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    earliest TIMESTAMP NOT NULL,
+    PRIMARY KEY (consumer, vehicle_id)
+);
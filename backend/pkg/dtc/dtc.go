@@ -0,0 +1,94 @@
+// Package dtc provides normalization and descriptions of OBD-II diagnostic trouble codes.
+// Descriptions of generic codes come from an embedded subset of the SAE J2012 dictionary.
+package dtc
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//go:embed j2012_generic.csv
+var genericCSV string
+
+// codePattern matches a five character code such as P0301 or U0100
+var codePattern = regexp.MustCompile(`^[PCBU][0-3][0-9A-F]{3}$`)
+
+// systems maps the first code character to the vehicle system it belongs to
+var systems = map[byte]string{
+	'P': "Powertrain",
+	'C': "Chassis",
+	'B': "Body",
+	'U': "Network",
+}
+
+var (
+	dictionaryOnce sync.Once
+	dictionary     map[string]string
+)
+
+// Normalize upper-cases and trims a code and checks that it is a valid five character DTC
+func Normalize(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if !codePattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid diagnostic trouble code %q", code)
+	}
+	return normalized, nil
+}
+
+// System returns the vehicle system of a normalized code, e.g. Powertrain for P0301
+func System(code string) string {
+	if code == "" {
+		return ""
+	}
+	return systems[code[0]]
+}
+
+// IsGeneric reports whether a normalized code is SAE defined rather than manufacturer specific
+func IsGeneric(code string) bool {
+	if len(code) < 2 {
+		return false
+	}
+	switch code[1] {
+	case '0', '2':
+		return true
+	case '3':
+		// P3400-P3999 are generic, the rest of P3 and all of C3/B3/U3 are reserved for manufacturers
+		return code[0] == 'P' && code[2] >= '4'
+	}
+	return false
+}
+
+// Lookup returns the dictionary description of a normalized code
+func Lookup(code string) (string, bool) {
+	dictionaryOnce.Do(loadDictionary)
+	description, ok := dictionary[code]
+	return description, ok
+}
+
+// Describe returns the dictionary description of a normalized code, or a description of
+// its system when the code is not in the dictionary
+func Describe(code string) string {
+	if description, ok := Lookup(code); ok {
+		return description
+	}
+	if IsGeneric(code) {
+		return fmt.Sprintf("%s fault (generic code)", System(code))
+	}
+	return fmt.Sprintf("%s fault (manufacturer specific code)", System(code))
+}
+
+// loadDictionary parses the embedded dictionary; the file is part of the binary so errors are programming errors
+func loadDictionary() {
+	records, err := csv.NewReader(strings.NewReader(genericCSV)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("dtc: invalid embedded dictionary: %v", err))
+	}
+	dictionary = make(map[string]string, len(records))
+	for _, record := range records[1:] {
+		dictionary[record[0]] = record[1]
+	}
+}
@@ -0,0 +1,133 @@
+// Package geo provides the geographic calculations used for telematics processing.
+package geo
+
+import "math"
+
+// earthRadiusKm is the mean Earth radius
+const earthRadiusKm = 6371.0088
+
+// Point is a WGS84 coordinate in degrees
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// IsZero reports whether the point is the 0,0 coordinate trackers send without a GPS fix
+func (p Point) IsZero() bool {
+	return p.Latitude == 0 && p.Longitude == 0
+}
+
+// DistanceKm returns the great-circle distance between two points using the haversine formula
+func DistanceKm(a, b Point) float64 {
+	lat1 := toRadians(a.Latitude)
+	lat2 := toRadians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := toRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// DistanceMeters returns the great-circle distance between two points in meters
+func DistanceMeters(a, b Point) float64 {
+	return DistanceKm(a, b) * 1000
+}
+
+// PolygonContains reports whether p lies inside the polygon using ray casting. The polygon is
+// given by its vertices in order and is closed implicitly. Points on the boundary follow the
+// half-open rule, so a point on an edge shared by adjoining polygons lies in only one of them.
+// Polygons crossing the antimeridian are not supported.
+func PolygonContains(polygon []Point, p Point) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) {
+			crossing := a.Longitude + (p.Latitude-a.Latitude)*(b.Longitude-a.Longitude)/(b.Latitude-a.Latitude)
+			if p.Longitude < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Simplify reduces a polyline with the Douglas–Peucker algorithm, keeping every point that
+// deviates more than toleranceMeters from the simplified line. The first and last points are kept.
+func Simplify(points []Point, toleranceMeters float64) []Point {
+	indices := SimplifyIndices(points, toleranceMeters)
+	simplified := make([]Point, len(indices))
+	for i, index := range indices {
+		simplified[i] = points[index]
+	}
+	return simplified
+}
+
+// SimplifyIndices is Simplify returning the ascending indices of the kept points, so callers can
+// keep data attached to each point
+func SimplifyIndices(points []Point, toleranceMeters float64) []int {
+	if len(points) < 3 || toleranceMeters <= 0 {
+		indices := make([]int, len(points))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	// Iterative to avoid deep recursion on long routes
+	type span struct{ first, last int }
+	stack := []span{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		maxDistance, index := 0.0, -1
+		for i := s.first + 1; i < s.last; i++ {
+			if d := crossTrackMeters(points[i], points[s.first], points[s.last]); d > maxDistance {
+				maxDistance, index = d, i
+			}
+		}
+		if index >= 0 && maxDistance > toleranceMeters {
+			keep[index] = true
+			stack = append(stack, span{s.first, index}, span{index, s.last})
+		}
+	}
+
+	indices := make([]int, 0, len(points)/4+2)
+	for i, kept := range keep {
+		if kept {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// crossTrackMeters returns the distance from p to the segment a-b. The segment is projected onto a
+// local equirectangular plane, which is accurate for the short segments of a route.
+func crossTrackMeters(p, a, b Point) float64 {
+	scale := math.Cos(toRadians((a.Latitude + b.Latitude) / 2))
+	ax, ay := a.Longitude*scale, a.Latitude
+	bx, by := b.Longitude*scale, b.Latitude
+	px, py := p.Longitude*scale, p.Latitude
+
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if lengthSq := dx*dx + dy*dy; lengthSq > 0 {
+		t = math.Max(0, math.Min(1, ((px-ax)*dx+(py-ay)*dy)/lengthSq))
+	}
+	cx, cy := ax+t*dx, ay+t*dy
+
+	degrees := math.Hypot(px-cx, py-cy)
+	return toRadians(degrees) * earthRadiusKm * 1000
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,125 @@
+package geo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// corner is a route east along the equator for about 1.1 km and then north for as long, with
+// points in the middle of each leg about 1 m off the straight line
+var corner = []Point{
+	{Latitude: 0, Longitude: 0},
+	{Latitude: 0.00001, Longitude: 0.005},
+	{Latitude: 0, Longitude: 0.01},
+	{Latitude: 0.005, Longitude: 0.01001},
+	{Latitude: 0.01, Longitude: 0.01},
+}
+
+func TestSimplifyIndices(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    []Point
+		tolerance float64
+		want      []int
+	}{
+		{name: "no points", points: nil, tolerance: 10, want: []int{}},
+		{name: "too few points", points: corner[:2], tolerance: 10, want: []int{0, 1}},
+		{name: "no tolerance", points: corner, tolerance: 0, want: []int{0, 1, 2, 3, 4}},
+		{name: "corner kept, noise dropped", points: corner, tolerance: 10, want: []int{0, 2, 4}},
+		{name: "noise above tolerance", points: corner, tolerance: 0.5, want: []int{0, 1, 2, 3, 4}},
+		{
+			name: "straight line",
+			points: []Point{
+				{Latitude: 0, Longitude: 0},
+				{Latitude: 0, Longitude: 0.001},
+				{Latitude: 0, Longitude: 0.002},
+				{Latitude: 0, Longitude: 0.003},
+			},
+			tolerance: 1,
+			want:      []int{0, 3},
+		},
+		{
+			name: "loop back to the start",
+			points: []Point{
+				{Latitude: 0, Longitude: 0},
+				{Latitude: 0.001, Longitude: 0},
+				{Latitude: 0, Longitude: 0},
+			},
+			tolerance: 10,
+			want:      []int{0, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SimplifyIndices(tt.points, tt.tolerance); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SimplifyIndices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	want := []Point{corner[0], corner[2], corner[4]}
+	if got := Simplify(corner, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("Simplify() = %v, want %v", got, want)
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	square := []Point{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 1},
+		{Latitude: 1, Longitude: 1},
+		{Latitude: 1, Longitude: 0},
+	}
+	diamond := []Point{
+		{Latitude: 0, Longitude: 0.5},
+		{Latitude: 0.5, Longitude: 1},
+		{Latitude: 1, Longitude: 0.5},
+		{Latitude: 0.5, Longitude: 0},
+	}
+	// notched is a U open to the north with the notch between longitudes 1 and 2 above latitude 1
+	notched := []Point{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 3, Longitude: 0},
+		{Latitude: 3, Longitude: 1},
+		{Latitude: 1, Longitude: 1},
+		{Latitude: 1, Longitude: 2},
+		{Latitude: 3, Longitude: 2},
+		{Latitude: 3, Longitude: 3},
+		{Latitude: 0, Longitude: 3},
+	}
+
+	// On the boundary, the south and west edges of the square belong to it and the north and east
+	// edges do not
+	tests := []struct {
+		name    string
+		polygon []Point
+		point   Point
+		want    bool
+	}{
+		{name: "too few vertices", polygon: square[:2], point: Point{Latitude: 0.5, Longitude: 0.5}, want: false},
+		{name: "inside", polygon: square, point: Point{Latitude: 0.5, Longitude: 0.5}, want: true},
+		{name: "outside", polygon: square, point: Point{Latitude: 0.5, Longitude: 1.5}, want: false},
+		{name: "west edge", polygon: square, point: Point{Latitude: 0.5, Longitude: 0}, want: true},
+		{name: "south edge", polygon: square, point: Point{Latitude: 0, Longitude: 0.5}, want: true},
+		{name: "east edge", polygon: square, point: Point{Latitude: 0.5, Longitude: 1}, want: false},
+		{name: "north edge", polygon: square, point: Point{Latitude: 1, Longitude: 0.5}, want: false},
+		{name: "south-west vertex", polygon: square, point: Point{Latitude: 0, Longitude: 0}, want: true},
+		{name: "south-east vertex", polygon: square, point: Point{Latitude: 0, Longitude: 1}, want: false},
+		{name: "north-east vertex", polygon: square, point: Point{Latitude: 1, Longitude: 1}, want: false},
+		{name: "north-west vertex", polygon: square, point: Point{Latitude: 1, Longitude: 0}, want: false},
+		{name: "ray through two vertices, outside", polygon: diamond, point: Point{Latitude: 0.5, Longitude: -1}, want: false},
+		{name: "ray through a vertex, inside", polygon: diamond, point: Point{Latitude: 0.5, Longitude: 0.5}, want: true},
+		{name: "concave notch", polygon: notched, point: Point{Latitude: 2, Longitude: 1.5}, want: false},
+		{name: "concave arm", polygon: notched, point: Point{Latitude: 2, Longitude: 0.5}, want: true},
+		{name: "ray along the notch floor", polygon: notched, point: Point{Latitude: 1, Longitude: 0.5}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PolygonContains(tt.polygon, tt.point); got != tt.want {
+				t.Errorf("PolygonContains(%v) = %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
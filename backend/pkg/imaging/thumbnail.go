@@ -0,0 +1,126 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register decoders
+	"image/jpeg"
+	_ "image/png"
+)
+
+const (
+	// MaxPixels bounds the images decoded for thumbnails, so a small file that declares huge
+	// dimensions cannot exhaust memory
+	MaxPixels = 16_000_000
+	// maxConcurrent bounds the images decoded at once, which bounds the memory thumbnails use
+	// across concurrent uploads
+	maxConcurrent = 4
+)
+
+// slots holds a token for each thumbnail being generated
+var slots = make(chan struct{}, maxConcurrent)
+
+// ErrTooLarge is returned for images above MaxPixels
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// DecodeConfig returns the format and dimensions of a JPEG, PNG or GIF image without decoding it
+func DecodeConfig(data []byte) (string, int, int, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return format, config.Width, config.Height, nil
+}
+
+// Thumbnail decodes a JPEG, PNG or GIF image and encodes a JPEG scaled down to fit within
+// maxSize×maxSize. Images already within the bounds are re-encoded at their own size.
+func Thumbnail(data []byte, maxSize int) ([]byte, error) {
+	_, width, height, err := DecodeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if width*height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	slots <- struct{}{}
+	defer func() { <-slots }()
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	thumb := scale(src, maxSize)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scale resizes an image to fit within maxSize×maxSize with a box filter, averaging the source
+// pixels each destination pixel covers. Transparent areas are flattened onto white.
+func scale(src image.Image, maxSize int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dstW, dstH := srcW, srcH
+	if srcW > maxSize || srcH > maxSize {
+		if srcW >= srcH {
+			dstW, dstH = maxSize, max(1, srcH*maxSize/srcW)
+		} else {
+			dstW, dstH = max(1, srcW*maxSize/srcH), maxSize
+		}
+	}
+
+	pixel := onWhite(src)
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb := pixel(bounds.Min.X+sx, bounds.Min.Y+sy)
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					n++
+				}
+			}
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = 0xff
+		}
+	}
+	return dst
+}
+
+// onWhite returns a reader of the 8-bit color of a source pixel flattened onto white. The
+// formats the decoders produce for photos are read directly rather than through At.
+func onWhite(src image.Image) func(x, y int) (r, g, b uint32) {
+	switch img := src.(type) {
+	case *image.YCbCr:
+		return func(x, y int) (uint32, uint32, uint32) {
+			c := img.COffset(x, y)
+			r, g, b := color.YCbCrToRGB(img.Y[img.YOffset(x, y)], img.Cb[c], img.Cr[c])
+			return uint32(r), uint32(g), uint32(b)
+		}
+	case *image.NRGBA:
+		return func(x, y int) (uint32, uint32, uint32) {
+			p := img.Pix[img.PixOffset(x, y):]
+			a := uint32(p[3])
+			return uint32(p[0])*a/0xff + 0xff - a, uint32(p[1])*a/0xff + 0xff - a, uint32(p[2])*a/0xff + 0xff - a
+		}
+	default:
+		return func(x, y int) (uint32, uint32, uint32) {
+			r, g, b, a := src.At(x, y).RGBA()
+			return (r + 0xffff - a) >> 8, (g + 0xffff - a) >> 8, (b + 0xffff - a) >> 8
+		}
+	}
+}
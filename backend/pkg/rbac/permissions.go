@@ -27,11 +27,21 @@ const (
 	ResourceVehicle       Resource = "vehicle"
 	ResourceVehicleType   Resource = "vehicle_type"
 	ResourceVehicleStatus Resource = "vehicle_status"
+	ResourceInspection    Resource = "inspection"
+	ResourceDamageReport  Resource = "damage_report"
+	ResourceAttachment    Resource = "attachment"
 
 	// Work order resources
-	ResourceWorkOrder     Resource = "work_order"
-	ResourceWorkOrderItem Resource = "work_order_item"
-	ResourceServiceType   Resource = "service_type"
+	ResourceWorkOrder       Resource = "work_order"
+	ResourceWorkOrderItem   Resource = "work_order_item"
+	ResourceServiceType     Resource = "service_type"
+	ResourceServiceRequest  Resource = "service_request"
+	ResourceMechanic        Resource = "mechanic"
+	ResourceEstimate        Resource = "estimate"
+	ResourceLaborOperation  Resource = "labor_operation"
+	ResourceMaintenancePlan Resource = "maintenance_plan"
+	ResourceWorkshopBay     Resource = "workshop_bay"
+	ResourceAppointment     Resource = "appointment"
 
 	// Inventory resources
 	ResourceInventory     Resource = "inventory"
@@ -53,6 +63,10 @@ const (
 	ResourceTelematics Resource = "telematics"
 	ResourceGPSData   Resource = "gps_data"
 	ResourceDiagnostics Resource = "diagnostics"
+	ResourceTelematicsDevice Resource = "telematics_device"
+	ResourceGeofence         Resource = "geofence"
+	ResourceDriverBehaviour  Resource = "driver_behaviour"
+	ResourceFuel             Resource = "fuel"
 
 	// Reports and analytics
 	ResourceReport   Resource = "report"
@@ -110,12 +124,12 @@ func ParsePermission(permStr string) (PermissionDefinition, error) {
 func GetAllPermissionDefinitions() []PermissionDefinition {
 	resources := []Resource{
 		ResourceUser, ResourceRole, ResourcePermission,
-		ResourceVehicle, ResourceVehicleType, ResourceVehicleStatus,
-		ResourceWorkOrder, ResourceWorkOrderItem, ResourceServiceType,
+		ResourceVehicle, ResourceVehicleType, ResourceVehicleStatus, ResourceInspection, ResourceDamageReport, ResourceAttachment,
+		ResourceWorkOrder, ResourceWorkOrderItem, ResourceServiceType, ResourceServiceRequest, ResourceMechanic, ResourceEstimate, ResourceLaborOperation, ResourceMaintenancePlan, ResourceWorkshopBay, ResourceAppointment,
 		ResourceInventory, ResourceWarehouse, ResourceInventoryItem, ResourceStockMovement,
 		ResourceInvoice, ResourceInvoiceItem, ResourcePayment, ResourcePaymentMethod,
 		ResourceCustomer, ResourceCustomerVehicle,
-		ResourceTelematics, ResourceGPSData, ResourceDiagnostics,
+		ResourceTelematics, ResourceGPSData, ResourceDiagnostics, ResourceTelematicsDevice, ResourceGeofence, ResourceDriverBehaviour, ResourceFuel,
 		ResourceReport, ResourceAnalytics, ResourceDashboard,
 		ResourceSystem, ResourceConfig, ResourceAuditLog,
 	}
@@ -147,7 +161,8 @@ func isPermissionCombinationValid(resource Resource, action Action) bool {
 	switch resource {
 	case ResourceVehicle:
 		return action == ActionCreate || action == ActionRead || action == ActionUpdate ||
-			   action == ActionDelete || action == ActionList || action == ActionExport
+			   action == ActionDelete || action == ActionList || action == ActionExport ||
+			   action == ActionImport
 	case ResourceWorkOrder:
 		return action == ActionCreate || action == ActionRead || action == ActionUpdate ||
 			   action == ActionDelete || action == ActionList || action == ActionAssign ||
@@ -190,6 +205,63 @@ func GetDefaultRolePermissions() RolePermissionMap {
 			{Resource: ResourceVehicle, Action: ActionDelete},
 			{Resource: ResourceVehicle, Action: ActionList},
 			{Resource: ResourceVehicle, Action: ActionExport},
+			{Resource: ResourceVehicle, Action: ActionImport},
+
+			// Inspections
+			{Resource: ResourceInspection, Action: ActionCreate},
+			{Resource: ResourceInspection, Action: ActionRead},
+			{Resource: ResourceInspection, Action: ActionUpdate},
+			{Resource: ResourceInspection, Action: ActionList},
+
+			// Damage reports
+			{Resource: ResourceDamageReport, Action: ActionCreate},
+			{Resource: ResourceDamageReport, Action: ActionRead},
+			{Resource: ResourceDamageReport, Action: ActionUpdate},
+			{Resource: ResourceDamageReport, Action: ActionList},
+
+			// Attachments (photos and documents of work orders, vehicles and inspections)
+			{Resource: ResourceAttachment, Action: ActionCreate},
+			{Resource: ResourceAttachment, Action: ActionRead},
+			{Resource: ResourceAttachment, Action: ActionDelete},
+			{Resource: ResourceAttachment, Action: ActionList},
+
+			// Telematics devices
+			{Resource: ResourceTelematicsDevice, Action: ActionCreate},
+			{Resource: ResourceTelematicsDevice, Action: ActionRead},
+			{Resource: ResourceTelematicsDevice, Action: ActionUpdate},
+			{Resource: ResourceTelematicsDevice, Action: ActionList},
+
+			// Telematics (fleet tracking)
+			{Resource: ResourceTelematics, Action: ActionRead},
+			{Resource: ResourceGPSData, Action: ActionRead},
+
+			// Geofences and their alerts
+			{Resource: ResourceGeofence, Action: ActionCreate},
+			{Resource: ResourceGeofence, Action: ActionRead},
+			{Resource: ResourceGeofence, Action: ActionUpdate},
+			{Resource: ResourceGeofence, Action: ActionDelete},
+			{Resource: ResourceGeofence, Action: ActionList},
+
+			// Diagnostic trouble codes, severity and condition rules, condition alerts
+			{Resource: ResourceDiagnostics, Action: ActionCreate},
+			{Resource: ResourceDiagnostics, Action: ActionRead},
+			{Resource: ResourceDiagnostics, Action: ActionUpdate},
+			{Resource: ResourceDiagnostics, Action: ActionDelete},
+			{Resource: ResourceDiagnostics, Action: ActionList},
+			{Resource: ResourceDiagnostics, Action: ActionApprove},
+
+			// Driver behaviour scoring
+			{Resource: ResourceDriverBehaviour, Action: ActionRead},
+			{Resource: ResourceDriverBehaviour, Action: ActionUpdate},
+			{Resource: ResourceDriverBehaviour, Action: ActionList},
+
+			// Fuel analytics, receipts and anomaly review
+			{Resource: ResourceFuel, Action: ActionCreate},
+			{Resource: ResourceFuel, Action: ActionRead},
+			{Resource: ResourceFuel, Action: ActionUpdate},
+			{Resource: ResourceFuel, Action: ActionDelete},
+			{Resource: ResourceFuel, Action: ActionList},
+			{Resource: ResourceFuel, Action: ActionApprove},
 
 			// Work orders
 			{Resource: ResourceWorkOrder, Action: ActionCreate},
@@ -200,6 +272,49 @@ func GetDefaultRolePermissions() RolePermissionMap {
 			{Resource: ResourceWorkOrder, Action: ActionApprove},
 			{Resource: ResourceWorkOrder, Action: ActionReject},
 
+			// Service requests (their areas)
+			{Resource: ResourceServiceRequest, Action: ActionCreate},
+			{Resource: ResourceServiceRequest, Action: ActionRead},
+			{Resource: ResourceServiceRequest, Action: ActionUpdate},
+			{Resource: ResourceServiceRequest, Action: ActionList},
+			{Resource: ResourceServiceRequest, Action: ActionApprove},
+			{Resource: ResourceServiceRequest, Action: ActionReject},
+			{Resource: ResourceServiceRequest, Action: ActionAssign},
+
+			// Mechanic profiles, shifts and workload
+			{Resource: ResourceMechanic, Action: ActionRead},
+			{Resource: ResourceMechanic, Action: ActionUpdate},
+			{Resource: ResourceMechanic, Action: ActionList},
+
+			// Work order estimates and customer approval links
+			{Resource: ResourceEstimate, Action: ActionCreate},
+			{Resource: ResourceEstimate, Action: ActionRead},
+			{Resource: ResourceEstimate, Action: ActionUpdate},
+			{Resource: ResourceEstimate, Action: ActionList},
+
+			// Labor operation catalogue
+			{Resource: ResourceLaborOperation, Action: ActionCreate},
+			{Resource: ResourceLaborOperation, Action: ActionRead},
+			{Resource: ResourceLaborOperation, Action: ActionUpdate},
+			{Resource: ResourceLaborOperation, Action: ActionList},
+			{Resource: ResourceLaborOperation, Action: ActionImport},
+
+			// Preventive maintenance plans
+			{Resource: ResourceMaintenancePlan, Action: ActionCreate},
+			{Resource: ResourceMaintenancePlan, Action: ActionRead},
+			{Resource: ResourceMaintenancePlan, Action: ActionUpdate},
+			{Resource: ResourceMaintenancePlan, Action: ActionList},
+
+			// Workshop bays and appointments
+			{Resource: ResourceWorkshopBay, Action: ActionCreate},
+			{Resource: ResourceWorkshopBay, Action: ActionRead},
+			{Resource: ResourceWorkshopBay, Action: ActionUpdate},
+			{Resource: ResourceWorkshopBay, Action: ActionList},
+			{Resource: ResourceAppointment, Action: ActionCreate},
+			{Resource: ResourceAppointment, Action: ActionRead},
+			{Resource: ResourceAppointment, Action: ActionUpdate},
+			{Resource: ResourceAppointment, Action: ActionList},
+
 			// Inventory
 			{Resource: ResourceInventory, Action: ActionRead},
 			{Resource: ResourceInventory, Action: ActionUpdate},
@@ -220,16 +335,65 @@ func GetDefaultRolePermissions() RolePermissionMap {
 			{Resource: ResourceVehicle, Action: ActionUpdate},
 			{Resource: ResourceVehicle, Action: ActionList},
 
+			// Inspections
+			{Resource: ResourceInspection, Action: ActionCreate},
+			{Resource: ResourceInspection, Action: ActionRead},
+			{Resource: ResourceInspection, Action: ActionUpdate},
+			{Resource: ResourceInspection, Action: ActionList},
+
+			// Damage reports
+			{Resource: ResourceDamageReport, Action: ActionCreate},
+			{Resource: ResourceDamageReport, Action: ActionRead},
+			{Resource: ResourceDamageReport, Action: ActionUpdate},
+			{Resource: ResourceDamageReport, Action: ActionList},
+
+			// Attachments (photos and documents of work orders, vehicles and inspections)
+			{Resource: ResourceAttachment, Action: ActionCreate},
+			{Resource: ResourceAttachment, Action: ActionRead},
+			{Resource: ResourceAttachment, Action: ActionDelete},
+			{Resource: ResourceAttachment, Action: ActionList},
+
+			// Diagnostic trouble codes and condition alerts
+			{Resource: ResourceDiagnostics, Action: ActionRead},
+			{Resource: ResourceDiagnostics, Action: ActionList},
+			{Resource: ResourceDiagnostics, Action: ActionApprove},
+
 			// Work orders
 			{Resource: ResourceWorkOrder, Action: ActionCreate},
 			{Resource: ResourceWorkOrder, Action: ActionRead},
 			{Resource: ResourceWorkOrder, Action: ActionUpdate},
 			{Resource: ResourceWorkOrder, Action: ActionList},
+			{Resource: ResourceWorkOrder, Action: ActionAssign},
+			{Resource: ResourceServiceRequest, Action: ActionCreate},
+			{Resource: ResourceServiceRequest, Action: ActionRead},
+			{Resource: ResourceServiceRequest, Action: ActionUpdate},
+			{Resource: ResourceServiceRequest, Action: ActionList},
+			{Resource: ResourceMechanic, Action: ActionRead},
+			{Resource: ResourceMechanic, Action: ActionUpdate},
+			{Resource: ResourceMechanic, Action: ActionList},
 			{Resource: ResourceWorkOrderItem, Action: ActionCreate},
 			{Resource: ResourceWorkOrderItem, Action: ActionRead},
 			{Resource: ResourceWorkOrderItem, Action: ActionUpdate},
 			{Resource: ResourceWorkOrderItem, Action: ActionDelete},
 
+			// Work order estimates and customer approval links
+			{Resource: ResourceEstimate, Action: ActionCreate},
+			{Resource: ResourceEstimate, Action: ActionRead},
+			{Resource: ResourceEstimate, Action: ActionUpdate},
+			{Resource: ResourceEstimate, Action: ActionList},
+			{Resource: ResourceLaborOperation, Action: ActionRead},
+			{Resource: ResourceLaborOperation, Action: ActionList},
+			{Resource: ResourceMaintenancePlan, Action: ActionRead},
+			{Resource: ResourceMaintenancePlan, Action: ActionList},
+
+			// Workshop appointments
+			{Resource: ResourceWorkshopBay, Action: ActionRead},
+			{Resource: ResourceWorkshopBay, Action: ActionList},
+			{Resource: ResourceAppointment, Action: ActionCreate},
+			{Resource: ResourceAppointment, Action: ActionRead},
+			{Resource: ResourceAppointment, Action: ActionUpdate},
+			{Resource: ResourceAppointment, Action: ActionList},
+
 			// Customer management
 			{Resource: ResourceCustomer, Action: ActionCreate},
 			{Resource: ResourceCustomer, Action: ActionRead},
@@ -252,12 +416,51 @@ func GetDefaultRolePermissions() RolePermissionMap {
 			{Resource: ResourceVehicle, Action: ActionUpdate},
 			{Resource: ResourceVehicle, Action: ActionList},
 
+			// Inspections
+			{Resource: ResourceInspection, Action: ActionCreate},
+			{Resource: ResourceInspection, Action: ActionRead},
+			{Resource: ResourceInspection, Action: ActionList},
+
+			// Damage reports
+			{Resource: ResourceDamageReport, Action: ActionCreate},
+			{Resource: ResourceDamageReport, Action: ActionRead},
+			{Resource: ResourceDamageReport, Action: ActionUpdate},
+			{Resource: ResourceDamageReport, Action: ActionList},
+
+			// Attachments (photos and documents of work orders, vehicles and inspections)
+			{Resource: ResourceAttachment, Action: ActionCreate},
+			{Resource: ResourceAttachment, Action: ActionRead},
+			{Resource: ResourceAttachment, Action: ActionDelete},
+			{Resource: ResourceAttachment, Action: ActionList},
+
+			// Telematics devices (installation and swaps)
+			{Resource: ResourceTelematicsDevice, Action: ActionRead},
+			{Resource: ResourceTelematicsDevice, Action: ActionUpdate},
+			{Resource: ResourceTelematicsDevice, Action: ActionList},
+
+			// Diagnostic trouble codes
+			{Resource: ResourceDiagnostics, Action: ActionRead},
+
 			// Work orders (assigned to them)
 			{Resource: ResourceWorkOrder, Action: ActionRead},
 			{Resource: ResourceWorkOrder, Action: ActionUpdate},
 			{Resource: ResourceWorkOrder, Action: ActionList},
+			{Resource: ResourceWorkOrderItem, Action: ActionCreate},
 			{Resource: ResourceWorkOrderItem, Action: ActionRead},
 			{Resource: ResourceWorkOrderItem, Action: ActionUpdate},
+			{Resource: ResourceWorkOrderItem, Action: ActionDelete},
+			{Resource: ResourceEstimate, Action: ActionRead},
+			{Resource: ResourceLaborOperation, Action: ActionRead},
+			{Resource: ResourceLaborOperation, Action: ActionList},
+
+			// Workshop calendar (own appointments)
+			{Resource: ResourceWorkshopBay, Action: ActionRead},
+			{Resource: ResourceWorkshopBay, Action: ActionList},
+			{Resource: ResourceAppointment, Action: ActionRead},
+			{Resource: ResourceAppointment, Action: ActionList},
+
+			// Own mechanic profile and shifts
+			{Resource: ResourceMechanic, Action: ActionRead},
 
 			// Inventory (parts usage)
 			{Resource: ResourceInventory, Action: ActionRead},
@@ -279,6 +482,7 @@ func GetDefaultRolePermissions() RolePermissionMap {
 			{Resource: ResourceInventoryItem, Action: ActionList},
 			{Resource: ResourceStockMovement, Action: ActionCreate},
 			{Resource: ResourceStockMovement, Action: ActionRead},
+			{Resource: ResourceStockMovement, Action: ActionUpdate},
 			{Resource: ResourceStockMovement, Action: ActionList},
 			{Resource: ResourceWarehouse, Action: ActionRead},
 			{Resource: ResourceWarehouse, Action: ActionUpdate},
@@ -290,14 +494,39 @@ func GetDefaultRolePermissions() RolePermissionMap {
 			{Resource: ResourceVehicle, Action: ActionRead},
 			{Resource: ResourceVehicle, Action: ActionList},
 
+			// Inspections (hand-back checklists)
+			{Resource: ResourceInspection, Action: ActionCreate},
+			{Resource: ResourceInspection, Action: ActionRead},
+			{Resource: ResourceDamageReport, Action: ActionCreate},
+			{Resource: ResourceDamageReport, Action: ActionRead},
+
+			// Attachments of assigned vehicles, their inspections and work orders
+			{Resource: ResourceAttachment, Action: ActionCreate},
+			{Resource: ResourceAttachment, Action: ActionRead},
+			{Resource: ResourceAttachment, Action: ActionDelete},
+			{Resource: ResourceAttachment, Action: ActionList},
+
 			// Work orders (assigned)
 			{Resource: ResourceWorkOrder, Action: ActionRead},
 			{Resource: ResourceWorkOrder, Action: ActionList},
 
+			// Service requests (their own)
+			{Resource: ResourceServiceRequest, Action: ActionCreate},
+			{Resource: ResourceServiceRequest, Action: ActionRead},
+			{Resource: ResourceServiceRequest, Action: ActionUpdate},
+			{Resource: ResourceServiceRequest, Action: ActionList},
+
 			// Telematics
 			{Resource: ResourceTelematics, Action: ActionRead},
 			{Resource: ResourceGPSData, Action: ActionRead},
 			{Resource: ResourceDiagnostics, Action: ActionRead},
+
+			// Driver behaviour (their own)
+			{Resource: ResourceDriverBehaviour, Action: ActionRead},
+
+			// Fuel receipts (their own) and fuel levels of assigned vehicles
+			{Resource: ResourceFuel, Action: ActionCreate},
+			{Resource: ResourceFuel, Action: ActionRead},
 		},
 
 		"Accountant": {
@@ -322,6 +551,10 @@ func GetDefaultRolePermissions() RolePermissionMap {
 			// Customer information (billing)
 			{Resource: ResourceCustomer, Action: ActionRead},
 			{Resource: ResourceCustomer, Action: ActionList},
+
+			// Fuel costs and receipts
+			{Resource: ResourceFuel, Action: ActionRead},
+			{Resource: ResourceFuel, Action: ActionList},
 		},
 	}
 }
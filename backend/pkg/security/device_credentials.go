@@ -0,0 +1,33 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateDeviceSecret generates a random 256-bit secret for device tokens and HMAC keys
+func GenerateDeviceSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate device secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SignDeviceRequest computes the hex HMAC-SHA256 signature of "<timestamp>.<body>"
+func SignDeviceRequest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDeviceSignature checks a request signature produced by SignDeviceRequest
+func VerifyDeviceSignature(secret, timestamp string, body []byte, signature string) bool {
+	expected := SignDeviceRequest(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
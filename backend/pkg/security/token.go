@@ -0,0 +1,19 @@
+package security
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// HashToken hashes a bearer token for storage, such as a device token or a customer link token.
+// Tokens are random and high-entropy, so a fast hash allows constant-cost lookups on every request.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyToken checks a presented token against its stored hash
+func VerifyToken(hash, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashToken(token))) == 1
+}
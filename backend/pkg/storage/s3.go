@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3RequestTimeout = 5 * time.Minute
+)
+
+// S3Storage stores objects in a bucket of Amazon S3 or an S3 compatible server such as MinIO.
+// Requests and presigned URLs are signed with AWS Signature Version 4.
+type S3Storage struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+}
+
+// NewS3Storage creates an S3 compatible storage
+func NewS3Storage(endpoint, region, bucket, accessKey, secretKey string, pathStyle bool) (*S3Storage, error) {
+	parsed, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	if bucket == "" || accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("S3 bucket, access key and secret key are required")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Storage{
+		endpoint:  parsed,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		pathStyle: pathStyle,
+		client:    &http.Client{Timeout: s3RequestTimeout},
+	}, nil
+}
+
+// Put uploads an object with a single PUT request
+func (s *S3Storage) Put(key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads an object
+func (s *S3Storage) Get(key string) (io.ReadCloser, *Object, error) {
+	req, err := s.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, &Object{Key: key, ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength}, nil
+}
+
+// Delete removes an object; S3 reports success for missing objects
+func (s *S3Storage) Delete(key string) error {
+	req, err := s.newRequest(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if err != nil && err != ErrNotFound {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GET URL of the object
+func (s *S3Storage) SignedURL(key string, expiry time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if err := checkExpiry(expiry); err != nil {
+		return "", err
+	}
+	objectURL := s.objectURL(key)
+	amzDate, scope := s.scope(time.Now())
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     s3Algorithm,
+		"X-Amz-Credential":    s.accessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(expiry.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	canonicalQuery := canonicalQueryString(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		objectURL.EscapedPath(),
+		canonicalQuery,
+		"host:" + objectURL.Host + "\n",
+		"host",
+		s3UnsignedBody,
+	}, "\n")
+
+	signature := s.signature(amzDate, scope, canonicalRequest)
+	return fmt.Sprintf("%s?%s&X-Amz-Signature=%s", objectURL.String(), canonicalQuery, signature), nil
+}
+
+// newRequest creates a request for an object signed with the Authorization header
+func (s *S3Storage) newRequest(method, key string, body io.Reader) (*http.Request, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage request: %w", err)
+	}
+
+	amzDate, scope := s.scope(time.Now())
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedBody)
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		method,
+		req.URL.EscapedPath(),
+		"",
+		"host:" + req.URL.Host + "\nx-amz-content-sha256:" + s3UnsignedBody + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		s3UnsignedBody,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKey, scope, signedHeaders, s.signature(amzDate, scope, canonicalRequest)))
+	return req, nil
+}
+
+// do sends a request, turning error responses into errors
+func (s *S3Storage) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("storage responded %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+}
+
+// objectURL addresses an object in path style (endpoint/bucket/key) or virtual hosted style
+// (bucket.endpoint/key)
+func (s *S3Storage) objectURL(key string) *url.URL {
+	objectURL := *s.endpoint
+	objectPath := strings.TrimRight(s.endpoint.Path, "/")
+	if s.pathStyle {
+		objectPath += "/" + s.bucket
+	} else {
+		objectURL.Host = s.bucket + "." + s.endpoint.Host
+	}
+	objectPath += "/" + key
+	objectURL.Path = objectPath
+	objectURL.RawPath = strings.TrimRight(s.endpoint.EscapedPath(), "/")
+	if s.pathStyle {
+		objectURL.RawPath += "/" + uriEncode(s.bucket, false)
+	}
+	objectURL.RawPath += "/" + uriEncode(key, false)
+	return &objectURL
+}
+
+// scope returns the request timestamp and credential scope
+func (s *S3Storage) scope(now time.Time) (string, string) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	return amzDate, fmt.Sprintf("%s/%s/s3/aws4_request", amzDate[:8], s.region)
+}
+
+// signature signs a canonical request with the key derived for the scope's date and region
+func (s *S3Storage) signature(amzDate, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), amzDate[:8])
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQueryString encodes query parameters sorted by name, as Signature Version 4 requires
+func canonicalQueryString(query map[string]string) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = uriEncode(name, true) + "=" + uriEncode(query[name], true)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters and, in paths, slashes
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
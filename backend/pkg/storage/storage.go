@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Storage errors
+var (
+	ErrNotFound         = errors.New("object not found")
+	ErrInvalidKey       = errors.New("invalid object key")
+	ErrInvalidSignature = errors.New("download link is invalid or has expired")
+)
+
+// MaxURLExpiry is the longest a signed URL can stay valid, the limit S3 presigned URLs allow
+const MaxURLExpiry = 7 * 24 * time.Hour
+
+// Object describes a stored object
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+}
+
+// Storage stores objects under slash separated keys such as "work_order/12/3f9a.jpg"
+type Storage interface {
+	// Put stores size bytes read from body under key, replacing any existing object
+	Put(key string, body io.Reader, size int64, contentType string) error
+	// Get opens an object; the caller closes the reader
+	Get(key string) (io.ReadCloser, *Object, error)
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(key string) error
+	// SignedURL returns a URL that downloads the object without other credentials until it expires
+	SignedURL(key string, expiry time.Duration) (string, error)
+}
+
+// Verifier is implemented by storages whose signed URLs are served by the application
+// rather than by the storage itself
+type Verifier interface {
+	Verify(key, expires, signature string) error
+}
+
+// Config selects and configures a storage driver
+type Config struct {
+	Driver string // local, s3
+
+	// Local filesystem driver
+	LocalPath     string
+	LocalURL      string // base URL the application serves signed files under
+	SigningSecret string
+
+	// S3 compatible driver
+	Endpoint  string // e.g. https://s3.eu-west-1.amazonaws.com or http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool // address the bucket in the path, as MinIO and most S3 compatible servers expect
+}
+
+// New creates the storage selected by the config
+func New(config Config) (Storage, error) {
+	switch config.Driver {
+	case "", "local":
+		return NewLocalStorage(config.LocalPath, config.LocalURL, config.SigningSecret)
+	case "s3":
+		return NewS3Storage(config.Endpoint, config.Region, config.Bucket, config.AccessKey, config.SecretKey, config.PathStyle)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q, expected local or s3", config.Driver)
+	}
+}
+
+// validateKey rejects keys that are empty, absolute or escape the storage root
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// checkExpiry bounds the validity of a signed URL
+func checkExpiry(expiry time.Duration) error {
+	if expiry <= 0 || expiry > MaxURLExpiry {
+		return fmt.Errorf("signed URL expiry must be between 1 second and %s", MaxURLExpiry)
+	}
+	return nil
+}
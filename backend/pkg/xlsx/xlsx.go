@@ -0,0 +1,431 @@
+// Package xlsx provides a minimal reader and streaming writer for single-sheet
+// Office Open XML spreadsheets. It supports the subset needed for tabular data
+// exchange (shared and inline strings, numbers, booleans) without styles.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// MaxColumns is the number of columns of a worksheet (A to XFD)
+const MaxColumns = 16384
+
+// maxPartSize limits the decompressed size of each part read from a workbook
+const maxPartSize = 64 << 20
+
+var (
+	// ErrNoSheet is returned when a workbook does not contain any worksheet
+	ErrNoSheet = errors.New("xlsx: workbook has no worksheets")
+	// ErrTooManyRows is returned when a worksheet has more rows than the reader accepts
+	ErrTooManyRows = errors.New("xlsx: worksheet has too many rows")
+	// ErrPartTooLarge is returned when a part of a workbook decompresses beyond the size limit
+	ErrPartTooLarge = errors.New("xlsx: workbook part is too large")
+)
+
+// ReadFirstSheet reads up to maxRows rows of the first worksheet of a workbook.
+// Missing cells are returned as empty strings so that every row is aligned by column.
+func ReadFirstSheet(r io.ReaderAt, size int64, maxRows int) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: invalid archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrNoSheet
+	}
+	return readSheet(f, shared, maxRows)
+}
+
+type relationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type workbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// openPart opens a workbook part for reading up to maxPartSize decompressed bytes
+func openPart(f *zip.File) (*io.LimitedReader, io.Closer, error) {
+	if f.UncompressedSize64 > maxPartSize {
+		return nil, nil, ErrPartTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &io.LimitedReader{R: rc, N: maxPartSize + 1}, rc, nil
+}
+
+// partError reports a part cut off at maxPartSize as too large instead of as malformed
+func partError(lr *io.LimitedReader, err error) error {
+	if lr.N <= 0 {
+		return ErrPartTooLarge
+	}
+	return err
+}
+
+func decodeFile(f *zip.File, v interface{}) error {
+	lr, rc, err := openPart(f)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return partError(lr, xml.NewDecoder(lr).Decode(v))
+}
+
+// firstSheetPath resolves the archive path of the first worksheet through the workbook relationships
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	wbFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("xlsx: missing workbook")
+	}
+	var wb workbook
+	if err := decodeFile(wbFile, &wb); err != nil {
+		return "", fmt.Errorf("xlsx: invalid workbook: %w", err)
+	}
+	if len(wb.Sheets) == 0 {
+		return "", ErrNoSheet
+	}
+
+	relFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	var rels relationships
+	if err := decodeFile(relFile, &rels); err != nil {
+		return "", fmt.Errorf("xlsx: invalid workbook relationships: %w", err)
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[0].RelID {
+			target := strings.TrimPrefix(rel.Target, "/")
+			if !strings.HasPrefix(target, "xl/") {
+				target = path.Join("xl", target)
+			}
+			return target, nil
+		}
+	}
+	return "", ErrNoSheet
+}
+
+// richText matches both plain <si><t> entries and rich text runs <si><r><t>
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt richText) String() string {
+	if len(rt.Runs) == 0 {
+		return rt.T
+	}
+	var b strings.Builder
+	for _, run := range rt.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	if err := decodeFile(f, &sst); err != nil {
+		return nil, fmt.Errorf("xlsx: invalid shared strings: %w", err)
+	}
+	shared := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		shared[i] = item.String()
+	}
+	return shared, nil
+}
+
+type cell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline richText `xml:"is"`
+}
+
+func readSheet(f *zip.File, shared []string, maxRows int) ([][]string, error) {
+	lr, rc, err := openPart(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var rows [][]string
+	decoder := xml.NewDecoder(lr)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if err := partError(lr, err); err == ErrPartTooLarge {
+				return nil, err
+			}
+			return nil, fmt.Errorf("xlsx: invalid worksheet: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row struct {
+			Index int    `xml:"r,attr"`
+			Cells []cell `xml:"c"`
+		}
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			if err := partError(lr, err); err == ErrPartTooLarge {
+				return nil, err
+			}
+			return nil, fmt.Errorf("xlsx: invalid row: %w", err)
+		}
+		if len(rows) >= maxRows || row.Index > maxRows {
+			return nil, ErrTooManyRows
+		}
+
+		// Keep row positions stable when empty rows are omitted from the sheet
+		for row.Index > 0 && len(rows) < row.Index-1 {
+			rows = append(rows, []string{})
+		}
+
+		var values []string
+		for i, c := range row.Cells {
+			col := i
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			if col >= MaxColumns {
+				return nil, fmt.Errorf("xlsx: row %d has more than %d columns", len(rows)+1, MaxColumns)
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+			value, err := cellValue(c, shared)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		rows = append(rows, values)
+	}
+
+	return rows, nil
+}
+
+func cellValue(c cell, shared []string) (string, error) {
+	switch c.Type {
+	case "s":
+		idx, err := strconv.Atoi(c.Value)
+		if err != nil || idx < 0 || idx >= len(shared) {
+			return "", fmt.Errorf("xlsx: invalid shared string reference in %s", c.Ref)
+		}
+		return shared[idx], nil
+	case "inlineStr":
+		return c.Inline.String(), nil
+	case "b":
+		if c.Value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	default:
+		return c.Value, nil
+	}
+}
+
+// columnIndex converts a cell reference such as "AB12" into a zero based column index
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		if col > MaxColumns {
+			return 0, fmt.Errorf("xlsx: cell reference %q is beyond the last column", ref)
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("xlsx: invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
+
+// columnName converts a zero based column index into its letter form (0 -> A, 27 -> AB)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+const (
+	contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	workbookXMLFormat = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	sheetHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetFooterXML = `</sheetData></worksheet>`
+)
+
+// Writer streams rows into a single-sheet workbook
+type Writer struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+// NewWriter starts a workbook with one worksheet of the given name
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+
+	var nameBuf strings.Builder
+	if err := xml.EscapeText(&nameBuf, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXMLFormat, nameBuf.String())},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+	for _, part := range parts {
+		fw, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(fw, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// The worksheet must be the last entry since it is streamed until Close
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(fw)
+	if _, err := sheet.WriteString(sheetHeaderXML); err != nil {
+		return nil, err
+	}
+
+	return &Writer{zw: zw, sheet: sheet}, nil
+}
+
+// WriteRow appends a row. Values that parse as numbers are stored as numeric cells.
+func (w *Writer) WriteRow(values []string) error {
+	w.rows++
+	if _, err := fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows); err != nil {
+		return err
+	}
+	for i, value := range values {
+		ref := fmt.Sprintf("%s%d", columnName(i), w.rows)
+		if isNumeric(value) {
+			if _, err := fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, value); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(w.sheet, []byte(value)); err != nil {
+			return err
+		}
+		if _, err := w.sheet.WriteString(`</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+// isNumeric reports whether a value is a plain decimal number. Values with
+// leading zeros (postal codes, identifiers) are kept as text.
+func isNumeric(value string) bool {
+	digits := strings.TrimPrefix(value, "-")
+	if digits == "" {
+		return false
+	}
+	intPart, fracPart, hasFrac := strings.Cut(digits, ".")
+	if intPart == "" || (hasFrac && fracPart == "") {
+		return false
+	}
+	if len(intPart) > 1 && intPart[0] == '0' {
+		return false
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Flush flushes buffered rows to the underlying writer
+func (w *Writer) Flush() error {
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Flush()
+}
+
+// Close finishes the worksheet and the archive
+func (w *Writer) Close() error {
+	if _, err := w.sheet.WriteString(sheetFooterXML); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
@@ -0,0 +1,219 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// workbookParts are the parts of a minimal workbook whose first sheet is xl/worksheets/sheet1.xml
+var workbookParts = map[string]string{
+	"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Data" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+	"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId7" Target="/xl/worksheets/sheet1.xml"/></Relationships>`,
+}
+
+// buildWorkbook archives the workbook parts together with extra parts
+func buildWorkbook(t *testing.T, extra map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, parts := range []map[string]string{workbookParts, extra} {
+		for name, content := range parts {
+			fw, err := zw.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.WriteString(fw, content); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sheetXML(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+func readWorkbook(data []byte, maxRows int) ([][]string, error) {
+	return ReadFirstSheet(bytes.NewReader(data), int64(len(data)), maxRows)
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"Plate", "Make", "Year", "Odometer", "Notes"},
+		{"B 1234 XYZ", "Toyota", "2019", "84250.5", "  leading and trailing spaces  "},
+		{"00123", "Škoda", "-1", "0", `<tags> & "quotes"`},
+		{"", "", "", "", ""},
+		{"1.", "1e5", "0.5", "007", "multi\nline"},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Vehicles & <Fleet>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readWorkbook(buf.Bytes(), 100)
+	if err != nil {
+		t.Fatalf("ReadFirstSheet() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("ReadFirstSheet() = %q, want %q", got, rows)
+	}
+}
+
+func TestReadFirstSheetCells(t *testing.T) {
+	data := buildWorkbook(t, map[string]string{
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>Plate</t></si><si><r><t>Rich </t></r><r><t>text</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": sheetXML(
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+				`<row r="3"><c r="A3" t="inlineStr"><is><t>inline</t></is></c><c r="B3"><v>42.5</v></c><c r="C3" t="b"><v>1</v></c><c r="D3" t="b"><v>0</v></c></row>` +
+				`<row><c><v>1</v></c><c><v>2</v></c></row>`,
+		),
+	})
+
+	got, err := readWorkbook(data, 100)
+	if err != nil {
+		t.Fatalf("ReadFirstSheet() error = %v", err)
+	}
+	want := [][]string{
+		{"Plate", "", "Rich text"},
+		{},
+		{"inline", "42.5", "TRUE", "FALSE"},
+		{"1", "2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadFirstSheet() = %q, want %q", got, want)
+	}
+}
+
+func TestReadFirstSheetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sheet   string
+		maxRows int
+		wantErr error // nil only checks that reading fails
+	}{
+		{
+			name:    "rows beyond the limit",
+			sheet:   sheetXML(`<row r="1"><c r="A1"><v>1</v></c></row><row r="2"><c r="A2"><v>2</v></c></row><row r="3"><c r="A3"><v>3</v></c></row>`),
+			maxRows: 2,
+			wantErr: ErrTooManyRows,
+		},
+		{
+			name:    "row index beyond the limit",
+			sheet:   sheetXML(`<row r="1000000"><c r="A1000000"><v>1</v></c></row>`),
+			maxRows: 10,
+			wantErr: ErrTooManyRows,
+		},
+		{
+			name:    "cell reference beyond the last column",
+			sheet:   sheetXML(`<row r="1"><c r="XFE1"><v>1</v></c></row>`),
+			maxRows: 10,
+		},
+		{
+			name:    "cells beyond the last column",
+			sheet:   sheetXML(`<row r="1">` + strings.Repeat(`<c><v>1</v></c>`, MaxColumns+1) + `</row>`),
+			maxRows: 10,
+		},
+		{
+			name:    "shared string out of range",
+			sheet:   sheetXML(`<row r="1"><c r="A1" t="s"><v>3</v></c></row>`),
+			maxRows: 10,
+		},
+		{
+			name:    "invalid cell reference",
+			sheet:   sheetXML(`<row r="1"><c r="11"><v>1</v></c></row>`),
+			maxRows: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readWorkbook(buildWorkbook(t, map[string]string{"xl/worksheets/sheet1.xml": tt.sheet}), tt.maxRows)
+			if err == nil {
+				t.Fatal("ReadFirstSheet() error = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadFirstSheet() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadFirstSheetPartTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range workbookParts {
+		fw, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(fw, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Whitespace compresses to almost nothing, so the archive stays small
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	padding := bytes.Repeat([]byte(" "), 1<<20)
+	for written := 0; written <= maxPartSize; written += len(padding) {
+		if _, err := fw.Write(padding); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readWorkbook(buf.Bytes(), 10); !errors.Is(err, ErrPartTooLarge) {
+		t.Fatalf("ReadFirstSheet() error = %v, want ErrPartTooLarge", err)
+	}
+}
+
+func TestReadFirstSheetNoSheet(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	fw, err := zw.Create("xl/workbook.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(fw, `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheets/></workbook>`); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readWorkbook(buf.Bytes(), 10); !errors.Is(err, ErrNoSheet) {
+		t.Fatalf("ReadFirstSheet() error = %v, want ErrNoSheet", err)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA", MaxColumns - 1: "XFD"} {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", index, got, want)
+		}
+		if got, err := columnIndex(want + "1"); err != nil || got != index {
+			t.Errorf("columnIndex(%q) = %d, %v, want %d", want+"1", got, err, index)
+		}
+	}
+}